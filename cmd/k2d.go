@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -9,18 +10,27 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"strconv"
+	"strings"
 
+	units "github.com/docker/go-units"
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	restful "github.com/emicklei/go-restful/v3"
 	"github.com/portainer/k2d/internal/adapter"
+	volumestore "github.com/portainer/k2d/internal/adapter/store/volume"
+	"github.com/portainer/k2d/internal/admission"
 	"github.com/portainer/k2d/internal/api/apis"
 	"github.com/portainer/k2d/internal/api/core"
 	"github.com/portainer/k2d/internal/api/k2d"
 	"github.com/portainer/k2d/internal/api/root"
+	"github.com/portainer/k2d/internal/audit"
 	"github.com/portainer/k2d/internal/config"
 	"github.com/portainer/k2d/internal/controller"
+	"github.com/portainer/k2d/internal/gitops"
 	"github.com/portainer/k2d/internal/logging"
 	"github.com/portainer/k2d/internal/middleware"
+	"github.com/portainer/k2d/internal/migration"
+	"github.com/portainer/k2d/internal/notification"
 	"github.com/portainer/k2d/internal/openapi"
 	"github.com/portainer/k2d/internal/ssl"
 	"github.com/portainer/k2d/internal/token"
@@ -49,7 +59,19 @@ func main() {
 		log.Fatalf("unable to parse configuration: %s", err)
 	}
 
-	logger, err := logging.NewLogger(cfg.LogLevel, cfg.LogFormat == "json")
+	logFileMaxSize, err := units.FromHumanSize(cfg.LogFileMaxSize)
+	if err != nil {
+		log.Fatalf("invalid log file max size: %s", err)
+	}
+
+	logger, err := logging.NewLogger(cfg.LogLevel, cfg.LogFormat == "json", logging.SinkOptions{
+		FilePath:       cfg.LogFile,
+		FileMaxSize:    logFileMaxSize,
+		FileMaxBackups: cfg.LogFileMaxBackups,
+		SyslogAddress:  cfg.LogSyslogAddress,
+		LokiURL:        cfg.LogLokiURL,
+		LokiLabels:     cfg.LogLokiLabels,
+	})
 	if err != nil {
 		log.Fatalf("unable to initialize logger: %s", err)
 	}
@@ -88,7 +110,7 @@ func main() {
 
 	logger.Infow("starting k2d",
 		"version", types.Version,
-		"config", cfg,
+		"config", cfg.Redacted(),
 	)
 
 	ip, err := getAdvertiseIpAddr(cfg.AdvertiseAddr)
@@ -96,7 +118,19 @@ func main() {
 		logger.Fatalf("unable to get advertise IP address: %s", err)
 	}
 
-	err = ssl.EnsureTLSCertificatesExist(ctx, cfg.DataPath, ip)
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		logger.Fatal("K2D_TLS_CERT_FILE and K2D_TLS_KEY_FILE must be provided together")
+	}
+
+	tlsOptions := ssl.TLSOptions{
+		CertFile:    cfg.TLSCertFile,
+		KeyFile:     cfg.TLSKeyFile,
+		CAFile:      cfg.TLSCAFile,
+		SANs:        cfg.TLSSANs,
+		RenewBefore: cfg.TLSCertRenewBefore,
+	}
+
+	err = ssl.EnsureTLSCertificatesExist(ctx, cfg.DataPath, ip, tlsOptions)
 	if err != nil {
 		logger.Fatalf("unable to setup TLS certificates: %s", err)
 	}
@@ -107,13 +141,41 @@ func main() {
 		logger.Fatalf("unable to retrieve or create encoded secret: %s", err)
 	}
 
+	roles, err := token.LoadTokens(encodedSecret, cfg.Tokens, cfg.TokensFile)
+	if err != nil {
+		logger.Fatalf("unable to load tokens: %s", err)
+	}
+	tokens := token.NewRegistry(roles)
+
+	certificatesRevokedBefore, err := ssl.LoadClientCertificatesRevocation(cfg.DataPath)
+	if err != nil {
+		logger.Fatalf("unable to load client certificates revocation: %s", err)
+	}
+	tokens.RevokeCertificates(certificatesRevokedBefore)
+
+	serverURL := fmt.Sprintf("https://%s:%d", ip.String(), cfg.Port)
+	kubeconfigCAPath := ssl.ServerCAPath(cfg.DataPath, tlsOptions)
+	if cfg.HTTPOnly {
+		serverURL = fmt.Sprintf("http://%s", net.JoinHostPort(cfg.HTTPBindAddress, strconv.Itoa(cfg.Port)))
+		// The certificate of the reverse proxy is verified using the CA certificates of the system of the clients
+		kubeconfigCAPath = cfg.TLSCAFile
+	}
+	if cfg.ExternalURL != "" {
+		serverURL = strings.TrimSuffix(cfg.ExternalURL, "/")
+	}
+
 	serverConfiguration := &types.K2DServerConfiguration{
-		ServerIpAddr: ip.String(),
-		ServerPort:   cfg.Port,
-		CaPath:       ssl.SSLCAPath(cfg.DataPath),
-		TokenPath:    tokenPath,
-		Secret:       encodedSecret,
+		ServerIpAddr:     ip.String(),
+		ServerPort:       cfg.Port,
+		ServerURL:        serverURL,
+		HTTPOnly:         cfg.HTTPOnly,
+		CaPath:           ssl.ServerCAPath(cfg.DataPath, tlsOptions),
+		KubeconfigCAPath: kubeconfigCAPath,
+		DataPath:         cfg.DataPath,
+		TokenPath:        tokenPath,
+		Tokens:           tokens,
 	}
+	serverConfiguration.SetSecret(encodedSecret)
 
 	kubeDockerAdapterOptions := &adapter.KubeDockerAdapterOptions{
 		K2DConfig:           &cfg,
@@ -131,11 +193,35 @@ func main() {
 		logger.Fatalf("unable to connect to local docker server, make sure the docker socket is reachable at /var/run/docker.sock: %s", err)
 	}
 
-	err = kubeDockerAdapter.ProvisionSystemResources(ctx, tokenPath, ssl.SSLCAPath(cfg.DataPath))
+	containerRuntime, err := kubeDockerAdapter.DetectContainerRuntime(ctx)
+	if err != nil {
+		logger.Fatalf("unable to detect container runtime: %s", err)
+	}
+	logger.Infow("container runtime detected", "runtime", containerRuntime)
+
+	err = kubeDockerAdapter.ProvisionSystemResources(ctx, tokenPath, serverConfiguration.CaPath)
 	if err != nil {
 		logger.Fatalf("unable to provision system resources: %s", err)
 	}
 
+	err = migration.NewRunner(logger, cfg.DataPath, kubeDockerAdapter.SchemaMigrations()).Run(ctx)
+	if err != nil {
+		if errors.Is(err, migration.ErrUnsupportedSchemaVersion) {
+			logger.Fatalf("unable to migrate data: %s", err)
+		}
+		logger.Warnf("unable to migrate data, the migration will be retried on the next start: %s", err)
+	}
+
+	err = kubeDockerAdapter.PruneContainerPayloads(ctx)
+	if err != nil {
+		logger.Warnf("unable to prune the configurations of removed containers: %s", err)
+	}
+
+	err = kubeDockerAdapter.RepairContainerNames(ctx)
+	if err != nil {
+		logger.Warnf("unable to repair container names: %s", err)
+	}
+
 	if cfg.PortainerEdgeKey != "" {
 		err = kubeDockerAdapter.DeployPortainerEdgeAgent(ctx, cfg.PortainerEdgeKey, cfg.PortainerEdgeID, cfg.PortainerAgentVersion)
 		if err != nil {
@@ -143,10 +229,105 @@ func main() {
 		}
 	}
 
+	var notifier *notification.Notifier
+	if cfg.NotificationWebhookURL != "" {
+		notifier = notification.NewNotifier(logger, cfg.NotificationWebhookURL, cfg.NotificationWebhookToken)
+	}
+
+	broadcaster := notification.NewBroadcaster()
+
+	var admissionReviewer *admission.Reviewer
+	if len(cfg.AdmissionMutatingWebhookURLs) != 0 || len(cfg.AdmissionValidatingWebhookURLs) != 0 {
+		admissionReviewer, err = admission.NewReviewer(&admission.ReviewerOptions{
+			Logger:                logger,
+			MutatingWebhookURLs:   cfg.AdmissionMutatingWebhookURLs,
+			ValidatingWebhookURLs: cfg.AdmissionValidatingWebhookURLs,
+			Timeout:               cfg.AdmissionWebhookTimeout,
+			FailurePolicy:         cfg.AdmissionWebhookFailurePolicy,
+			CAPath:                cfg.AdmissionWebhookCAPath,
+		})
+		if err != nil {
+			logger.Fatalf("unable to create admission webhook client: %s", err)
+		}
+	}
+
+	// The secrets of the operation queues are encrypted with the key of the volume store
+	queueEncryptionKey, err := volumestore.GenerateOrRetrieveEncryptionKey(logger, cfg.DataPath)
+	if err != nil {
+		logger.Fatalf("unable to retrieve operation queue encryption key: %s", err)
+	}
+
 	operations := make(chan controller.Operation)
-	go controller.NewOperationController(logger, kubeDockerAdapter, cfg.OperationBatchMaxSize).StartControlLoop(operations)
+	operationRegistry := controller.NewOperationStatusRegistry()
+	operationController := controller.NewOperationController(&controller.OperationControllerOptions{
+		Adapter:             kubeDockerAdapter,
+		Admission:           admissionReviewer,
+		Broadcaster:         broadcaster,
+		Logger:              logger,
+		MaxBatchSize:        cfg.OperationBatchMaxSize,
+		Notifier:            notifier,
+		PendingQueuePath:    path.Join(cfg.DataPath, types.PendingQueueFilename),
+		QueueEncryptionKey:  queueEncryptionKey,
+		Registry:            operationRegistry,
+		Synchronous:         cfg.OperationSynchronous,
+		Workers:             cfg.OperationWorkers,
+		RetryMaxAttempts:    cfg.OperationRetryMaxAttempts,
+		RetryInitialBackoff: cfg.OperationRetryInitialBackoff,
+		RetryQueuePath:      path.Join(cfg.DataPath, types.RetryQueueFilename),
+	})
+
+	err = operationController.ResumeRetries()
+	if err != nil {
+		logger.Warnf("unable to resume operation retries: %s", err)
+	}
+
+	err = operationController.ReplayPendingOperations()
+	if err != nil {
+		logger.Warnf("unable to replay queued operations: %s", err)
+	}
+
+	go operationController.StartControlLoop(operations)
+
+	if cfg.ReconcileInterval > 0 {
+		go operationController.StartReconcileLoop(ctx, cfg.ReconcileInterval)
+	}
+
+	if cfg.AutoscalerInterval > 0 {
+		go operationController.StartAutoscalerLoop(ctx, cfg.AutoscalerInterval)
+	}
+
+	if cfg.NodeLeaseInterval > 0 {
+		go operationController.StartNodeLeaseLoop(ctx, cfg.NodeLeaseInterval)
+	}
+
+	if cfg.NetworkPolicyInterval > 0 {
+		go operationController.StartNetworkPolicyLoop(ctx, cfg.NetworkPolicyInterval)
+	}
+
+	go operationController.StartCrashLoopDetectionLoop(ctx)
+
+	go operationController.StartLifecycleNotificationLoop(ctx)
 	defer close(operations)
 
+	if cfg.GitOpsRepositoryURL != "" {
+		gitOpsAgent, err := gitops.NewAgent(&gitops.AgentOptions{
+			Adapter:       kubeDockerAdapter,
+			Logger:        logger,
+			Operations:    operations,
+			RepositoryURL: cfg.GitOpsRepositoryURL,
+			Branch:        cfg.GitOpsBranch,
+			Path:          cfg.GitOpsPath,
+			Interval:      cfg.GitOpsInterval,
+			AuthSecret:    cfg.GitOpsAuthSecret,
+			ImageName:     cfg.GitOpsImageName,
+		})
+		if err != nil {
+			logger.Fatalf("unable to create gitops agent: %s", err)
+		}
+
+		go gitOpsAgent.Start(ctx)
+	}
+
 	container := restful.NewContainer()
 
 	// We add the logger to the context of the request
@@ -158,14 +339,35 @@ func main() {
 
 	container.Filter(middleware.AddTracingHeaders)
 	container.Filter(middleware.LogRequests)
-	container.Filter(middleware.CheckAuthenticationHeader(encodedSecret))
+	container.Filter(middleware.LimitRequests(middleware.RateLimitOptions{
+		MaxReadOnlyInflight: cfg.MaxRequestsInflight,
+		MaxMutatingInflight: cfg.MaxMutatingRequestsInflight,
+		ReadOnlyRate:        cfg.RateLimitQPS,
+		MutatingRate:        cfg.RateLimitMutatingQPS,
+		Burst:               cfg.RateLimitBurst,
+	}))
+
+	if cfg.AuditLogPath != "" {
+		auditSink, err := audit.NewSink(cfg.AuditLogPath)
+		if err != nil {
+			logger.Fatalf("unable to create audit log sink: %s", err)
+		}
+
+		container.Filter(middleware.AuditRequests(audit.NewLogger(logger, auditSink)))
+	}
+
+	container.Filter(middleware.CheckAuthenticationHeader(tokens))
 
 	// We build the API
-	root := root.NewRootAPI()
+	root := root.NewRootAPI(kubeDockerAdapter)
 	// /version
 	container.Add(root.Version())
 	// /healthz
 	container.Add(root.Healthz())
+	// /livez
+	container.Add(root.Livez())
+	// /readyz
+	container.Add(root.Readyz())
 
 	core := core.NewCoreAPI(kubeDockerAdapter, operations)
 	// /api/v1
@@ -174,21 +376,56 @@ func main() {
 	apis := apis.NewApisAPI(kubeDockerAdapter, operations)
 	// /apis
 	container.Add(apis.APIs())
+	// /apis/apiextensions.k8s.io
+	container.Add(apis.ApiExtensions())
 	// /apis/apps
 	container.Add(apis.Apps())
+	// /apis/autoscaling
+	container.Add(apis.Autoscaling())
+	// /apis/coordination.k8s.io
+	container.Add(apis.Coordination())
 	// /apis/events.k8s.io
 	container.Add(apis.Events())
 	// /apis/authorization.k8s.io
 	container.Add(apis.Authorization())
+	// /apis/networking.k8s.io
+	container.Add(apis.Networking())
 	// /apis/storage.k8s.io
 	container.Add(apis.Storages())
+	// /apis/flowcontrol.apiserver.k8s.io
+	container.Add(apis.FlowControl())
 
-	k2d := k2d.NewK2DAPI(serverConfiguration, kubeDockerAdapter)
+	k2d := k2d.NewK2DAPI(serverConfiguration, kubeDockerAdapter, operationRegistry, broadcaster)
 	// /k2d/kubeconfig
 	container.Add(k2d.Kubeconfig())
+	// /k2d/rotate-secret
+	container.Add(k2d.RotateSecret())
+	// /k2d/adopt
+	container.Add(k2d.Adopt())
+	// /k2d/backup
+	container.Add(k2d.Backup())
+	// /k2d/restore
+	container.Add(k2d.Restore())
+	// /k2d/checkpoints
+	container.Add(k2d.Checkpoints())
+	// /k2d/export
+	container.Add(k2d.Export())
+	// /k2d/import-compose
+	container.Add(k2d.ImportCompose())
+	// /k2d/operations
+	container.Add(k2d.Operations())
+	// /k2d/snapshots
+	container.Add(k2d.Snapshots())
+	// /k2d/stream
+	container.Add(k2d.Stream())
 	// /k2d/system
 	container.Add(k2d.System())
 
+	if cfg.UIEnabled {
+		// /k2d/ui
+		container.Add(k2d.UI())
+	}
+
 	// We build and host the OpenAPI specs from the API that we have registered
 	// This is used by kubectl when using the kubectl apply command
 	config := restfulspec.Config{
@@ -203,9 +440,62 @@ func main() {
 		logger.Fatalf("unable to build OpenAPI web service")
 	}
 
+	config.APIPath = "/openapi/v3"
+	openAPIv3, err := openapi.NewOpenAPIV3Service(config)
+	if err != nil {
+		logger.Fatalf("unable to build OpenAPI v3 web service")
+	}
+
 	// /openapi/v2
 	container.Add(openAPIv2)
 
+	// /openapi/v3
+	container.Add(openAPIv3)
+
+	if cfg.ListenSocket != "" {
+		socketListener, err := network.ListenUnixSocket(cfg.ListenSocket)
+		if err != nil {
+			logger.Fatalf("unable to listen on unix socket: %s", err)
+		}
+
+		logger.Infow("serving the k2d API on unix socket",
+			"socket", cfg.ListenSocket,
+		)
+
+		socketServer := &http.Server{
+			Handler: container,
+		}
+
+		go func() {
+			err := socketServer.Serve(socketListener)
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Errorf("unix socket listener stopped: %s", err)
+			}
+		}()
+	}
+
+	if cfg.HTTPOnly {
+		address := net.JoinHostPort(cfg.HTTPBindAddress, strconv.Itoa(cfg.Port))
+
+		logger.Infow("starting k2d server on HTTP port, TLS must be terminated by a reverse proxy",
+			"address", address,
+			"server_url", serverURL,
+			"secret", encodedSecret,
+		)
+
+		logger.Infoln("use the command below to retrieve the kubeconfig file")
+		logger.Infof("curl -H \"Authorization: Bearer %s\" http://%s/k2d/kubeconfig", encodedSecret, address)
+
+		server := &http.Server{
+			Addr:    address,
+			Handler: container,
+		}
+
+		err = server.ListenAndServe()
+
+		logger.Fatal(err)
+	}
+
 	logger.Infow("starting k2d server on HTTPS port",
 		"address", fmt.Sprintf(":%d", cfg.Port),
 		"advertise_address", ip.String(),
@@ -215,12 +505,36 @@ func main() {
 	logger.Infoln("use the command below to retrieve the kubeconfig file")
 	logger.Infof("curl --insecure -H \"Authorization: Bearer %s\" https://%s:%d/k2d/kubeconfig",
 		encodedSecret, serverConfiguration.ServerIpAddr, serverConfiguration.ServerPort)
+	logger.Infoln("append ?auth=certificate to the URL to retrieve a kubeconfig file using client certificate authentication")
+
+	tlsConfig, err := ssl.NewServerTLSConfig(cfg.DataPath)
+	if err != nil {
+		logger.Fatalf("unable to create TLS configuration: %s", err)
+	}
+
+	certificateReloader, err := ssl.NewCertificateReloader(ssl.ServerCertPaths(cfg.DataPath, tlsOptions))
+	if err != nil {
+		logger.Fatalf("unable to load TLS certificate: %s", err)
+	}
+	tlsConfig.GetCertificate = certificateReloader.GetCertificate
+
+	// The generated certificate is renewed before its expiry, the certificate provided by the operator is only reloaded
+	var renewCertificate func(ctx context.Context) error
+	if !tlsOptions.CustomCertificate() {
+		renewCertificate = func(ctx context.Context) error {
+			return ssl.EnsureTLSCertificatesExist(ctx, cfg.DataPath, ip, tlsOptions)
+		}
+	}
+	go certificateReloader.Start(ctx, renewCertificate)
+
+	server := &http.Server{
+		Addr:      fmt.Sprintf(":%d", cfg.Port),
+		Handler:   container,
+		TLSConfig: tlsConfig,
+	}
 
-	err = http.ListenAndServeTLS(
-		fmt.Sprintf(":%d", cfg.Port),
-		ssl.SSLCertPath(cfg.DataPath),
-		ssl.SSLKeyPath(cfg.DataPath),
-		container)
+	// The certificate is served by the reloader (see tls.Config.GetCertificate)
+	err = server.ListenAndServeTLS("", "")
 
 	logger.Fatal(err)
 }
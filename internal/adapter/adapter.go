@@ -3,23 +3,34 @@ package adapter
 import (
 	"context"
 	"fmt"
+	"path"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/client"
 	"github.com/portainer/k2d/internal/adapter/converter"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	"github.com/portainer/k2d/internal/adapter/registry"
 	"github.com/portainer/k2d/internal/adapter/store"
-	"github.com/portainer/k2d/internal/adapter/store/filesystem"
+	filesystemstore "github.com/portainer/k2d/internal/adapter/store/filesystem"
 	"github.com/portainer/k2d/internal/adapter/store/volume"
 	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
 	"github.com/portainer/k2d/internal/config"
 	"github.com/portainer/k2d/internal/types"
+	"github.com/portainer/k2d/pkg/filesystem"
 	"go.uber.org/zap"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/kubernetes/pkg/apis/apps"
 	appsv1 "k8s.io/kubernetes/pkg/apis/apps/v1"
+	"k8s.io/kubernetes/pkg/apis/autoscaling"
+	autoscalingv2 "k8s.io/kubernetes/pkg/apis/autoscaling/v2"
+	"k8s.io/kubernetes/pkg/apis/coordination"
+	coordinationv1 "k8s.io/kubernetes/pkg/apis/coordination/v1"
 	"k8s.io/kubernetes/pkg/apis/core"
 	corev1 "k8s.io/kubernetes/pkg/apis/core/v1"
+	"k8s.io/kubernetes/pkg/apis/networking"
+	networkingv1 "k8s.io/kubernetes/pkg/apis/networking/v1"
 	"k8s.io/kubernetes/pkg/apis/storage"
 )
 
@@ -28,6 +39,7 @@ type (
 	// This struct performs multiple roles:
 	// - Interacts with the Docker API: It uses the Docker client to perform operations like
 	//   pulling images, starting containers, and more.
+	//   A single Docker host is managed, see listNodes for the reasons why several hosts are not supported.
 	//
 	// - Converts Kubernetes Objects: It utilizes a conversion scheme to translate Kubernetes
 	//   objects into their corresponding Docker objects, supporting multiple Kubernetes versions.
@@ -44,20 +56,70 @@ type (
 	// - Server Configuration: Contains configuration related to the k2d server, which is used when
 	//   creating certain resources.
 	//
+	// - Data path: Contains the path where k2d stores its data. It is used to report the disk usage of the node.
+	//
+	// - Ephemeral storage usage: Defines whether the size of the writable layer of the containers is reported in the pods.
+	//
+	// - Image archives: Contains the images available in the archives of the image archive directory, which are loaded
+	//   instead of being pulled.
+	//
 	// - Namespace deletion delay: Contains the delay that k2d waits after a namespace is deleted.
 	//
+	// - Logs path: Contains the path where the logs of previous container instances are retained.
+	//
+	// - Snapshots path: Contains the path where the volume snapshots are stored.
+	//
+	// - Checkpoints path: Contains the path where the container checkpoints are stored.
+	//
+	// - Event store: Contains the events recorded by k2d (e.g. operation failures), kept in memory.
+	//
+	// - Container payload cache: Contains the configurations referenced in the container labels, keyed by container ID.
+	//
+	// - Lease lock: Serializes the updates of the leases, which rely on optimistic concurrency.
+	//
+	// - Service lock: Serializes the application and removal of the services, which re-create the containers they select
+	//   and allocate the NodePorts from the ports published by the other containers.
+	//
+	// - Custom resources path: Contains the path where the custom resource definitions and the custom resources are stored.
+	//
+	// - Restart tracker: Contains the exit and restart history of the containers of the pods, kept in memory.
+	//
+	// - Rollback window: Contains the duration during which a re-created container is watched, it is rolled back
+	//   to its previous configuration if it exits during the window.
+	//
+	// - Termination messages path: Contains the path where the termination message files of the containers are stored.
+	//
 	// This struct is a comprehensive utility for managing the interactions between Docker and Kubernetes.
 	KubeDockerAdapter struct {
-		cli                    *client.Client
-		configMapStore         store.ConfigMapStore
-		converter              *converter.DockerAPIConverter
-		conversionScheme       *runtime.Scheme
-		k2dServerConfiguration *types.K2DServerConfiguration
-		logger                 *zap.SugaredLogger
-		namespaceDeletionDelay time.Duration
-		registrySecretStore    store.SecretStore
-		startTime              time.Time
-		secretStore            store.SecretStore
+		checkpointsPath         string
+		cli                     *client.Client
+		configMapStore          store.ConfigMapStore
+		containerPayloadCache   *containerPayloadCache
+		converter               *converter.DockerAPIConverter
+		conversionScheme        *runtime.Scheme
+		customResourceLock      sync.RWMutex
+		customResourcesPath     string
+		dataPath                string
+		ephemeralStorageUsage   bool
+		eventStore              *eventStore
+		imageArchives           imageArchiveIndex
+		k2dServerConfiguration  *types.K2DServerConfiguration
+		leaseLock               sync.Mutex
+		logger                  *zap.SugaredLogger
+		logsPath                string
+		namespaceDeletionDelay  time.Duration
+		pendingPodStore         *pendingPodStore
+		registryOptions         registry.Options
+		registrySecretStore     store.SecretStore
+		restartTracker          *containerRestartTracker
+		rollbackWindow          time.Duration
+		startTime               time.Time
+		secretStore             store.SecretStore
+		serviceLock             sync.Mutex
+		snapshotsPath           string
+		storeBackend            string
+		terminationMessagesPath string
+		volumeCopyImageName     string
 	}
 
 	// KubeDockerAdapterOptions represents options that can be used to configure a new KubeDockerAdapter
@@ -82,19 +144,83 @@ func NewKubeDockerAdapter(options *KubeDockerAdapterOptions) (*KubeDockerAdapter
 		return nil, fmt.Errorf("unable to create docker client: %w", err)
 	}
 
+	err = naming.ConfigureContainerNaming(naming.ContainerNamingOptions{
+		Prefix:     options.K2DConfig.ContainerNamePrefix,
+		HashSuffix: options.K2DConfig.ContainerNameHashSuffix,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure container naming: %w", err)
+	}
+
+	registryOptions := registry.Options{
+		InsecureRegistries: options.K2DConfig.InsecureRegistries,
+		CABundles:          options.K2DConfig.RegistryCABundles,
+		CertsPath:          options.K2DConfig.DockerCertsPath,
+	}
+
+	// The CA bundles are installed before the store is configured as the volume store pulls the volume copy image
+	err = registry.InstallCABundles(registryOptions)
+	if err != nil {
+		return nil, fmt.Errorf("unable to install registry CA bundles: %w", err)
+	}
+
+	// The image archives are loaded before the store is configured as the volume store uses the volume copy image
+	imageArchives := imageArchiveIndex{}
+	if options.K2DConfig.ImageArchivePath != "" {
+		imageArchives, err = scanImageArchives(options.Logger, options.K2DConfig.ImageArchivePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to scan image archives: %w", err)
+		}
+
+		loadImageArchives(context.TODO(), cli, options.Logger, imageArchives)
+	}
+
 	storeOptions := store.StoreOptions{
 		Backend:         options.K2DConfig.StoreBackend,
 		RegistryBackend: options.K2DConfig.StoreRegistryBackend,
 		Logger:          options.Logger,
-		Filesystem: filesystem.FileSystemStoreOptions{
+		CacheTTL:        options.K2DConfig.StoreCacheTTL,
+		Filesystem: filesystemstore.FileSystemStoreOptions{
 			DataPath: options.K2DConfig.DataPath,
 		},
 		Volume: volume.VolumeStoreOptions{
 			DockerCli:     cli,
 			CopyImageName: options.K2DConfig.StoreVolumeCopyImageName,
+			DirectAccess:  options.K2DConfig.StoreVolumeDirectAccess,
+			Registry:      registryOptions,
 		},
 	}
 
+	logsPath := path.Join(options.K2DConfig.DataPath, LogsFolder)
+	err = filesystem.CreateDir(logsPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create logs directory: %w", err)
+	}
+
+	terminationMessagesPath := path.Join(options.K2DConfig.DataPath, TerminationMessagesFolder)
+	err = filesystem.CreateDir(terminationMessagesPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create termination messages directory: %w", err)
+	}
+
+	snapshotsPath := path.Join(options.K2DConfig.DataPath, SnapshotsFolder)
+	err = filesystem.CreateDir(snapshotsPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create snapshots directory: %w", err)
+	}
+
+	checkpointsPath := path.Join(options.K2DConfig.DataPath, CheckpointsFolder)
+	err = filesystem.CreateDir(checkpointsPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create checkpoints directory: %w", err)
+	}
+
+	customResourcesPath := path.Join(options.K2DConfig.DataPath, CustomResourcesFolder)
+	err = filesystem.CreateDir(customResourcesPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create custom resources directory: %w", err)
+	}
+
 	configMapStore, secretStore, err := store.ConfigureStore(storeOptions)
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize store backends: %w", err)
@@ -105,17 +231,42 @@ func NewKubeDockerAdapter(options *KubeDockerAdapterOptions) (*KubeDockerAdapter
 		return nil, fmt.Errorf("unable to initialize registry secret store: %w", err)
 	}
 
+	dockerAPIConverter := converter.NewDockerAPIConverter(configMapStore, secretStore, options.ServerConfiguration)
+	dockerAPIConverter.SetLogOptions(converter.LogOptions{
+		Driver:   options.K2DConfig.LogDriver,
+		MaxSize:  options.K2DConfig.LogMaxSize,
+		MaxFiles: options.K2DConfig.LogMaxFiles,
+	})
+	dockerAPIConverter.SetRegistryOptions(registryOptions)
+	dockerAPIConverter.SetUsernsMode(options.K2DConfig.UsernsMode)
+	dockerAPIConverter.SetOfflineMode(options.K2DConfig.ImageArchivePath != "", imageArchives.images())
+
 	return &KubeDockerAdapter{
-		cli:                    cli,
-		converter:              converter.NewDockerAPIConverter(configMapStore, secretStore, options.ServerConfiguration),
-		conversionScheme:       initConversionScheme(),
-		configMapStore:         configMapStore,
-		k2dServerConfiguration: options.ServerConfiguration,
-		logger:                 options.Logger,
-		namespaceDeletionDelay: options.K2DConfig.OperationNamespaceDeletionDelay,
-		registrySecretStore:    registrySecretStore,
-		secretStore:            secretStore,
-		startTime:              time.Now(),
+		checkpointsPath:         checkpointsPath,
+		cli:                     cli,
+		converter:               dockerAPIConverter,
+		conversionScheme:        initConversionScheme(),
+		customResourcesPath:     customResourcesPath,
+		dataPath:                options.K2DConfig.DataPath,
+		ephemeralStorageUsage:   options.K2DConfig.EphemeralStorageUsage,
+		configMapStore:          configMapStore,
+		containerPayloadCache:   newContainerPayloadCache(),
+		eventStore:              newEventStore(),
+		imageArchives:           imageArchives,
+		k2dServerConfiguration:  options.ServerConfiguration,
+		logger:                  options.Logger,
+		logsPath:                logsPath,
+		namespaceDeletionDelay:  options.K2DConfig.OperationNamespaceDeletionDelay,
+		pendingPodStore:         newPendingPodStore(),
+		registryOptions:         registryOptions,
+		registrySecretStore:     registrySecretStore,
+		restartTracker:          newContainerRestartTracker(),
+		secretStore:             secretStore,
+		snapshotsPath:           snapshotsPath,
+		terminationMessagesPath: terminationMessagesPath,
+		startTime:               time.Now(),
+		storeBackend:            options.K2DConfig.StoreBackend,
+		volumeCopyImageName:     options.K2DConfig.StoreVolumeCopyImageName,
 	}, nil
 }
 
@@ -135,8 +286,8 @@ func (adapter *KubeDockerAdapter) ConvertK8SResource(src, dest interface{}) erro
 }
 
 // ProvisionSystemResources sets up the essential system resources required for the KubeDockerAdapter to operate.
-// This function takes care of provisioning two namespaces ("default" and a custom k2d namespace),
-// as well as storing service account secrets necessary for client authentication.
+// This function takes care of provisioning three namespaces ("default", a custom k2d namespace and
+// the "kube-node-lease" namespace storing the heartbeat lease of the node), as well as storing service account secrets necessary for client authentication.
 //
 // Parameters:
 // - ctx: Context for managing cancellations and timeouts.
@@ -146,7 +297,8 @@ func (adapter *KubeDockerAdapter) ConvertK8SResource(src, dest interface{}) erro
 // The function performs the following steps in order:
 // 1. Calls provisionNamespace() to create or verify the "default" namespace.
 // 2. Calls provisionNamespace() to create or verify a custom k2d namespace.
-// 3. Calls storeServiceAccountSecret() to store the service account token and SSL CA certificate at the provided paths.
+// 3. Calls provisionNamespace() to create or verify the "kube-node-lease" namespace.
+// 4. Calls storeServiceAccountSecret() to store the service account token and SSL CA certificate at the provided paths.
 //
 // Error Handling:
 // - If provisioning of either namespace fails, an error is returned detailing which namespace failed.
@@ -167,6 +319,11 @@ func (adapter *KubeDockerAdapter) ProvisionSystemResources(ctx context.Context,
 		return fmt.Errorf("unable to provision k2d namespace: %w", err)
 	}
 
+	err = adapter.provisionNamespace(ctx, k2dtypes.NodeLeaseNamespaceName)
+	if err != nil {
+		return fmt.Errorf("unable to provision node lease namespace: %w", err)
+	}
+
 	err = adapter.storeServiceAccountSecret(tokenPath, sslCACertPath)
 	if err != nil {
 		return fmt.Errorf("unable to store service account secret: %w", err)
@@ -188,8 +345,14 @@ func (adapter *KubeDockerAdapter) ProvisionSystemResources(ctx context.Context,
 // The function adds the schemes for the following API groups to the new Scheme object:
 // - 'apps': API group for managing workloads like deployments and stateful sets
 // - 'appsv1': Version 1 of the 'apps' API group
+// - 'autoscaling': API group for autoscaling resources like HorizontalPodAutoscaler
+// - 'autoscalingv2': Version 2 of the 'autoscaling' API group
+// - 'coordination': API group for coordination resources like Lease
+// - 'coordinationv1': Version 1 of the 'coordination' API group
 // - 'core': Core API group for basic Kubernetes resources like Pods and Services
 // - 'corev1': Version 1 of the 'core' API group
+// - 'networking': API group for networking resources like NetworkPolicy
+// - 'networkingv1': Version 1 of the 'networking' API group
 // - 'storage': API group for storage resources like PersistentVolume and PersistentVolumeClaim
 // - 'storagev1': Version 1 of the 'storage' API group
 //
@@ -200,8 +363,14 @@ func initConversionScheme() *runtime.Scheme {
 
 	apps.AddToScheme(scheme)
 	appsv1.AddToScheme(scheme)
+	autoscaling.AddToScheme(scheme)
+	autoscalingv2.AddToScheme(scheme)
+	coordination.AddToScheme(scheme)
+	coordinationv1.AddToScheme(scheme)
 	core.AddToScheme(scheme)
 	corev1.AddToScheme(scheme)
+	networking.AddToScheme(scheme)
+	networkingv1.AddToScheme(scheme)
 	storage.AddToScheme(scheme)
 	storagev1.AddToScheme(scheme)
 
@@ -0,0 +1,200 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/filters"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// admitPodSpec checks that a container created from the specified pod spec can run on the node, as the
+// Kubernetes scheduler does:
+//   - The node name, the node selector and the required node affinity of the pod must match the node. They are evaluated
+//     against the labels of the node (see ConvertInfoToNodeLabels), the preferred node affinity being ignored.
+//   - The node must have enough capacity: the CPU and memory requests of the pod (or its limits when no request is defined)
+//     are compared with the capacity of the Docker host minus the requests of the running containers managed by k2d.
+//     This prevents over-committed hosts from running out of memory. Pods that do not define any request always fit.
+//   - The host ports of the pod must be free (see checkPodHostPorts).
+//
+// A pod that cannot be admitted is kept pending and a FailedScheduling event explaining the reason is recorded.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - podSpec: The internal pod spec of the container to create.
+// - podName: The name of the pod, used to record the event.
+// - namespace: The namespace of the pod.
+// - replacedContainer: The container replaced by the new container, if any. Its requests are not counted.
+//
+// Returns:
+// - An ErrUnschedulable error if the pod does not match the node, if its host ports are used or if the node lacks the capacity.
+// - An error if the capacity of the node or the running containers cannot be retrieved.
+func (adapter *KubeDockerAdapter) admitPodSpec(ctx context.Context, podSpec core.PodSpec, podName, namespace string, replacedContainer *types.ContainerJSON) error {
+	info, err := adapter.cli.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve docker server info: %w", err)
+	}
+
+	if !podSpecMatchesNode(podSpec, info.Name, adapter.converter.ConvertInfoToNodeLabels(info)) {
+		return adapter.rejectPodSpec(podName, namespace, "0/1 nodes are available: 1 node(s) didn't match Pod's node affinity/selector.")
+	}
+
+	err = adapter.checkPodHostPorts(ctx, podSpec, podName, namespace, replacedContainer)
+	if err != nil {
+		return err
+	}
+
+	cpuRequest := getContainerResourceRequest(&podSpec, core.ResourceCPU)
+	memoryRequest := getContainerResourceRequest(&podSpec, core.ResourceMemory)
+
+	if cpuRequest.IsZero() && memoryRequest.IsZero() {
+		return nil
+	}
+
+	containers, err := adapter.cli.ContainerList(ctx, types.ContainerListOptions{Filters: filters.AllNamespaces()})
+	if err != nil {
+		return fmt.Errorf("unable to list containers: %w", err)
+	}
+
+	requestedCPU, requestedMemory := int64(0), int64(0)
+	for _, container := range containers {
+		if replacedContainer != nil && container.ID == replacedContainer.ID {
+			continue
+		}
+
+		containerPodSpec, err := getPodSpecFromLabels(adapter.resolveContainerLabels(container.ID, container.Labels))
+		if err != nil || containerPodSpec == nil {
+			continue
+		}
+
+		containerCPURequest := getContainerResourceRequest(containerPodSpec, core.ResourceCPU)
+		containerMemoryRequest := getContainerResourceRequest(containerPodSpec, core.ResourceMemory)
+
+		requestedCPU += containerCPURequest.MilliValue()
+		requestedMemory += containerMemoryRequest.Value()
+	}
+
+	insufficientResources := []string{}
+	if requestedCPU+cpuRequest.MilliValue() > int64(info.NCPU)*1000 {
+		insufficientResources = append(insufficientResources, "1 Insufficient cpu")
+	}
+
+	if requestedMemory+memoryRequest.Value() > info.MemTotal {
+		insufficientResources = append(insufficientResources, "1 Insufficient memory")
+	}
+
+	if len(insufficientResources) == 0 {
+		return nil
+	}
+
+	return adapter.rejectPodSpec(podName, namespace, fmt.Sprintf("0/1 nodes are available: %s.", strings.Join(insufficientResources, ", ")))
+}
+
+// rejectPodSpec records a FailedScheduling event against a pod that cannot be admitted and returns
+// an ErrUnschedulable error wrapping the specified message.
+func (adapter *KubeDockerAdapter) rejectPodSpec(podName, namespace, message string) error {
+	adapter.RecordEvent(core.ObjectReference{Kind: "Pod", Name: podName, Namespace: namespace},
+		core.EventTypeWarning, "FailedScheduling", message)
+
+	return fmt.Errorf("%w: %s", adaptererr.ErrUnschedulable, message)
+}
+
+// podSpecMatchesNode returns true when the node name, the node selector and the required node affinity
+// of a pod spec match the node. The node selector terms are ORed while the requirements of a term are ANDed.
+func podSpecMatchesNode(podSpec core.PodSpec, nodeName string, nodeLabels map[string]string) bool {
+	if podSpec.NodeName != "" && podSpec.NodeName != nodeName {
+		return false
+	}
+
+	for key, value := range podSpec.NodeSelector {
+		if nodeValue, exists := nodeLabels[key]; !exists || nodeValue != value {
+			return false
+		}
+	}
+
+	if podSpec.Affinity == nil || podSpec.Affinity.NodeAffinity == nil ||
+		podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+
+	for _, term := range podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if nodeSelectorTermMatches(term, nodeName, nodeLabels) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nodeSelectorTermMatches returns true when all the label and field requirements of a node selector term match the node.
+// As in Kubernetes, a term without any requirement does not match any node and metadata.name is the only supported field.
+func nodeSelectorTermMatches(term core.NodeSelectorTerm, nodeName string, nodeLabels map[string]string) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+
+	for _, requirement := range term.MatchExpressions {
+		value, exists := nodeLabels[requirement.Key]
+		if !nodeSelectorRequirementMatches(requirement, value, exists) {
+			return false
+		}
+	}
+
+	for _, requirement := range term.MatchFields {
+		if requirement.Key != "metadata.name" || !nodeSelectorRequirementMatches(requirement, nodeName, true) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nodeSelectorRequirementMatches evaluates a node selector requirement against the value of a node label
+// (or field) and whether that label exists on the node.
+func nodeSelectorRequirementMatches(requirement core.NodeSelectorRequirement, value string, exists bool) bool {
+	switch requirement.Operator {
+	case core.NodeSelectorOpIn:
+		return exists && containsString(requirement.Values, value)
+	case core.NodeSelectorOpNotIn:
+		return !exists || !containsString(requirement.Values, value)
+	case core.NodeSelectorOpExists:
+		return exists
+	case core.NodeSelectorOpDoesNotExist:
+		return !exists
+	case core.NodeSelectorOpGt, core.NodeSelectorOpLt:
+		if !exists || len(requirement.Values) != 1 {
+			return false
+		}
+
+		nodeValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+
+		requirementValue, err := strconv.ParseInt(requirement.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+
+		if requirement.Operator == core.NodeSelectorOpGt {
+			return nodeValue > requirementValue
+		}
+		return nodeValue < requirementValue
+	default:
+		return false
+	}
+}
+
+// containsString returns true when the specified slice contains the specified value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
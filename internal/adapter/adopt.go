@@ -0,0 +1,199 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// AdoptContainerOptions represents the options used to adopt a Docker container created outside of k2d
+type AdoptContainerOptions struct {
+	// Container is the name or the ID of the Docker container to adopt
+	Container string `json:"container"`
+	// Name is the name of the pod managing the container, derived from the name of the container when empty
+	Name string `json:"name"`
+	// Namespace is the namespace of the pod, the default namespace when empty
+	Namespace string `json:"namespace"`
+}
+
+// invalidResourceNameCharacters matches the characters of a Docker name (e.g. a container name) that cannot be used
+// in the name of a Kubernetes resource
+var invalidResourceNameCharacters = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// sanitizeResourceName converts a Docker name into a name that can be used for a Kubernetes resource (DNS-1123 label),
+// e.g. My_App.1 is converted to my-app-1.
+func sanitizeResourceName(name string) string {
+	return strings.Trim(invalidResourceNameCharacters.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// AdoptContainer turns a Docker container created outside of k2d into a pod managed through the Kubernetes API.
+// A pod definition is synthesized from the configuration of the container (see converter.ConvertContainerToPodSpec)
+// and the container is re-created from this definition, in the network of the namespace and with the k2d labels,
+// so that it can be listed, inspected, deleted and re-created like any other pod. The name of the adopted container
+// is recorded in the AdoptedContainerAnnotationKey annotation of the pod.
+//
+// The labels of a container cannot be updated, the original container is therefore stopped and replaced.
+// It is only removed once the pod container has been created, and restarted if the creation fails.
+// The data stored in its volumes and bind mounts is preserved as they are mounted in the pod container.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - opts: The adoption options.
+//
+// Returns:
+// - The pod managing the container.
+// - An ErrResourceNotFound error if the container or the namespace does not exist, an ErrResourceConflict error if the container
+// is already managed by k2d, an ErrResourceAlreadyExists error if the pod already exists or an ErrInvalidResource error
+// if the pod name is invalid.
+func (adapter *KubeDockerAdapter) AdoptContainer(ctx context.Context, opts AdoptContainerOptions) (*corev1.Pod, error) {
+	containerDetails, err := adapter.getContainer(ctx, opts.Container)
+	if err != nil {
+		return nil, err
+	}
+
+	if containerDetails == nil {
+		return nil, fmt.Errorf("%w: container %s", adaptererr.ErrResourceNotFound, opts.Container)
+	}
+
+	containerName := strings.TrimPrefix(containerDetails.Name, "/")
+	if containerDetails.Config.Labels[k2dtypes.NamespaceNameLabelKey] != "" {
+		return nil, fmt.Errorf("%w: container %s is already managed by k2d", adaptererr.ErrResourceConflict, containerName)
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	podName := opts.Name
+	if podName == "" {
+		podName = sanitizeResourceName(containerName)
+	}
+
+	if errs := validation.IsDNS1123Subdomain(podName); len(errs) > 0 {
+		return nil, fmt.Errorf("%w: invalid pod name %s: %s", adaptererr.ErrInvalidResource, podName, strings.Join(errs, ", "))
+	}
+
+	_, err = adapter.getNetwork(ctx, naming.BuildNetworkName(namespace))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find namespace %s: %w", namespace, err)
+	}
+
+	podContainerName := naming.BuildContainerName(podName, namespace)
+	existingContainer, err := adapter.getContainer(ctx, podContainerName)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingContainer != nil && existingContainer.ID != containerDetails.ID {
+		return nil, fmt.Errorf("%w: pod %s/%s", adaptererr.ErrResourceAlreadyExists, namespace, podName)
+	}
+
+	internalPod := core.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podName,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				k2dtypes.AdoptedContainerAnnotationKey: containerName,
+			},
+		},
+		Spec: adapter.converter.ConvertContainerToPodSpec(podName, *containerDetails),
+	}
+
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Pod",
+			APIVersion: "v1",
+		},
+	}
+
+	err = adapter.ConvertK8SResource(&internalPod, pod)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert internal object to versioned object: %w", err)
+	}
+
+	podData, err := json.Marshal(pod)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal pod: %w", err)
+	}
+	pod.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = string(podData)
+
+	err = adapter.replaceAdoptedContainer(ctx, containerDetails, func() error {
+		return adapter.CreateContainerFromPod(ctx, pod)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	adapter.logger.Infow("container adopted",
+		"container", containerName,
+		"pod", podName,
+		"namespace", namespace,
+	)
+
+	return adapter.GetPod(ctx, podName, namespace)
+}
+
+// replaceAdoptedContainer stops the adopted container and creates the container of the workload managing it
+// using the specified function. The adopted container is renamed so that its name can be used by the workload container,
+// its name and state are restored if the workload container cannot be created. It is removed otherwise.
+func (adapter *KubeDockerAdapter) replaceAdoptedContainer(ctx context.Context, containerDetails *types.ContainerJSON, createWorkload func() error) error {
+	containerName := strings.TrimPrefix(containerDetails.Name, "/")
+	wasRunning := containerDetails.State != nil && containerDetails.State.Running
+
+	if wasRunning {
+		err := adapter.cli.ContainerStop(ctx, containerDetails.ID, container.StopOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to stop container %s: %w", containerName, err)
+		}
+	}
+
+	err := adapter.cli.ContainerRename(ctx, containerDetails.ID, containerName+"-adopted")
+	if err != nil {
+		adapter.restoreAdoptedContainer(ctx, containerDetails.ID, "", wasRunning)
+		return fmt.Errorf("unable to rename container %s: %w", containerName, err)
+	}
+
+	err = createWorkload()
+	if err != nil {
+		adapter.restoreAdoptedContainer(ctx, containerDetails.ID, containerName, wasRunning)
+		return fmt.Errorf("unable to create workload container: %w", err)
+	}
+
+	err = adapter.cli.ContainerRemove(ctx, containerDetails.ID, types.ContainerRemoveOptions{})
+	if err != nil {
+		adapter.logger.Warnf("unable to remove adopted container %s: %s", containerName, err)
+	}
+
+	return nil
+}
+
+// restoreAdoptedContainer restores the name and the state of a container whose adoption failed.
+// Failures are only logged as the adoption error is returned to the caller.
+func (adapter *KubeDockerAdapter) restoreAdoptedContainer(ctx context.Context, containerID, containerName string, wasRunning bool) {
+	if containerName != "" {
+		err := adapter.cli.ContainerRename(ctx, containerID, containerName)
+		if err != nil {
+			adapter.logger.Warnf("unable to restore the name of container %s: %s", containerName, err)
+		}
+	}
+
+	if wasRunning {
+		err := adapter.cli.ContainerStart(ctx, containerID, types.ContainerStartOptions{})
+		if err != nil {
+			adapter.logger.Warnf("unable to restart container %s: %s", containerID, err)
+		}
+	}
+}
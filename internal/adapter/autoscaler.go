@@ -0,0 +1,505 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"github.com/portainer/k2d/internal/k8s"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/apis/autoscaling"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// horizontalPodAutoscalerManifestDataKey is the key used to store the definition of a horizontal pod autoscaler in its system configmap
+	horizontalPodAutoscalerManifestDataKey = "manifest"
+	// defaultHorizontalPodAutoscalerCPUUtilization is the target CPU utilization used when a horizontal pod autoscaler
+	// does not define any metric, as in Kubernetes
+	defaultHorizontalPodAutoscalerCPUUtilization = int32(80)
+	// horizontalPodAutoscalerTolerance is the minimum change (from 1.0) in the ratio between the current and the target
+	// metric values for the autoscaler to consider scaling, as in Kubernetes
+	horizontalPodAutoscalerTolerance = 0.1
+)
+
+// CreateHorizontalPodAutoscaler stores a horizontal pod autoscaler inside a system configmap.
+// The autoscaler is evaluated periodically by ReconcileHorizontalPodAutoscalers.
+// Creating an autoscaler with the name of an existing autoscaler replaces it.
+// It returns an ErrInvalidResource error if the autoscaler does not target a deployment or defines invalid replica bounds.
+func (adapter *KubeDockerAdapter) CreateHorizontalPodAutoscaler(ctx context.Context, horizontalPodAutoscaler *autoscalingv2.HorizontalPodAutoscaler) error {
+	if horizontalPodAutoscaler.Spec.ScaleTargetRef.Kind != "Deployment" {
+		return fmt.Errorf("%w: unsupported scale target kind %q, only deployments can be autoscaled",
+			adaptererr.ErrInvalidResource, horizontalPodAutoscaler.Spec.ScaleTargetRef.Kind)
+	}
+
+	minReplicas := getHorizontalPodAutoscalerMinReplicas(horizontalPodAutoscaler)
+	if minReplicas < 1 || horizontalPodAutoscaler.Spec.MaxReplicas < minReplicas {
+		return fmt.Errorf("%w: maxReplicas must be greater than or equal to minReplicas, and minReplicas must be greater than 0",
+			adaptererr.ErrInvalidResource)
+	}
+
+	if horizontalPodAutoscaler.CreationTimestamp.IsZero() {
+		horizontalPodAutoscaler.CreationTimestamp = metav1.NewTime(time.Now())
+	}
+
+	return adapter.storeHorizontalPodAutoscaler(horizontalPodAutoscaler)
+}
+
+// DeleteHorizontalPodAutoscaler removes a horizontal pod autoscaler. The target deployment is left untouched.
+func (adapter *KubeDockerAdapter) DeleteHorizontalPodAutoscaler(ctx context.Context, horizontalPodAutoscalerName, namespace string) error {
+	_, err := adapter.getHorizontalPodAutoscaler(horizontalPodAutoscalerName, namespace)
+	if err != nil {
+		return err
+	}
+
+	err = adapter.DeleteSystemConfigMap(naming.BuildHorizontalPodAutoscalerSystemConfigMapName(horizontalPodAutoscalerName, namespace))
+	if err != nil {
+		return fmt.Errorf("unable to delete horizontal pod autoscaler system configmap: %w", err)
+	}
+
+	return nil
+}
+
+func (adapter *KubeDockerAdapter) GetHorizontalPodAutoscaler(ctx context.Context, horizontalPodAutoscalerName, namespace string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	return adapter.getHorizontalPodAutoscaler(horizontalPodAutoscalerName, namespace)
+}
+
+func (adapter *KubeDockerAdapter) ListHorizontalPodAutoscalers(ctx context.Context, namespace string) (autoscalingv2.HorizontalPodAutoscalerList, error) {
+	horizontalPodAutoscalers, err := adapter.listHorizontalPodAutoscalers(namespace)
+	if err != nil {
+		return autoscalingv2.HorizontalPodAutoscalerList{}, fmt.Errorf("unable to list horizontal pod autoscalers: %w", err)
+	}
+
+	return autoscalingv2.HorizontalPodAutoscalerList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "HorizontalPodAutoscalerList",
+			APIVersion: "autoscaling/v2",
+		},
+		Items: horizontalPodAutoscalers,
+	}, nil
+}
+
+func (adapter *KubeDockerAdapter) GetHorizontalPodAutoscalerTable(ctx context.Context, namespace string) (*metav1.Table, error) {
+	horizontalPodAutoscalerList, err := adapter.ListHorizontalPodAutoscalers(ctx, namespace)
+	if err != nil {
+		return &metav1.Table{}, err
+	}
+
+	internalHorizontalPodAutoscalerList := autoscaling.HorizontalPodAutoscalerList{}
+	err = adapter.ConvertK8SResource(&horizontalPodAutoscalerList, &internalHorizontalPodAutoscalerList)
+	if err != nil {
+		return &metav1.Table{}, fmt.Errorf("unable to convert versioned HorizontalPodAutoscalerList to internal HorizontalPodAutoscalerList: %w", err)
+	}
+
+	return k8s.GenerateTable(&internalHorizontalPodAutoscalerList)
+}
+
+// ReconcileHorizontalPodAutoscalers evaluates each horizontal pod autoscaler against the resource usage of the container
+// of its target deployment, read from the Docker stats API. The utilization of a resource is computed from the requests
+// (or limits when no request is defined) of the container, and the desired replica count follows the Kubernetes algorithm:
+// desiredReplicas = ceil(currentReplicas * currentMetricValue / targetMetricValue), bounded by minReplicas and maxReplicas.
+//
+// k2d runs a single container per deployment, so the current replica count is always 1. When more (or fewer) replicas
+// are desired, the ScalingLimited condition of the autoscaler is set and a warning event is recorded on the deployment.
+//
+// Only the Resource metrics (cpu and memory) are supported, other metric types are ignored.
+// The status of each autoscaler is updated with the current metrics and the desired replica count.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+//
+// Returns:
+// - An error if the horizontal pod autoscalers cannot be listed. Failures to evaluate an autoscaler are logged.
+func (adapter *KubeDockerAdapter) ReconcileHorizontalPodAutoscalers(ctx context.Context) error {
+	horizontalPodAutoscalers, err := adapter.listHorizontalPodAutoscalers("")
+	if err != nil {
+		return fmt.Errorf("unable to list horizontal pod autoscalers: %w", err)
+	}
+
+	for _, horizontalPodAutoscaler := range horizontalPodAutoscalers {
+		status, err := adapter.evaluateHorizontalPodAutoscaler(ctx, &horizontalPodAutoscaler)
+		if err != nil {
+			adapter.logger.Warnf("unable to evaluate horizontal pod autoscaler %s in namespace %s: %s",
+				horizontalPodAutoscaler.Name, horizontalPodAutoscaler.Namespace, err)
+			continue
+		}
+
+		err = adapter.updateHorizontalPodAutoscalerStatus(horizontalPodAutoscaler.Name, horizontalPodAutoscaler.Namespace, status)
+		if err != nil {
+			adapter.logger.Warnf("unable to update the status of horizontal pod autoscaler %s in namespace %s: %s",
+				horizontalPodAutoscaler.Name, horizontalPodAutoscaler.Namespace, err)
+		}
+	}
+
+	return nil
+}
+
+// evaluateHorizontalPodAutoscaler computes the status of a horizontal pod autoscaler from the resource usage
+// of the container of its target deployment.
+func (adapter *KubeDockerAdapter) evaluateHorizontalPodAutoscaler(ctx context.Context, horizontalPodAutoscaler *autoscalingv2.HorizontalPodAutoscaler) (autoscalingv2.HorizontalPodAutoscalerStatus, error) {
+	status := *horizontalPodAutoscaler.Status.DeepCopy()
+	targetName := horizontalPodAutoscaler.Spec.ScaleTargetRef.Name
+
+	container, err := adapter.getContainer(ctx, naming.BuildContainerName(targetName, horizontalPodAutoscaler.Namespace))
+	if err != nil {
+		return status, fmt.Errorf("unable to inspect container: %w", err)
+	}
+
+	if container == nil || container.Config.Labels[k2dtypes.WorkloadTypeLabelKey] != k2dtypes.DeploymentWorkloadType {
+		status.CurrentReplicas = 0
+		status.CurrentMetrics = nil
+		setHorizontalPodAutoscalerCondition(&status, autoscalingv2.AbleToScale, corev1.ConditionFalse, "FailedGetScale",
+			fmt.Sprintf("the HPA controller was unable to get the target's current scale: deployment %s not found", targetName))
+		return status, nil
+	}
+
+	status.CurrentReplicas = 1
+	setHorizontalPodAutoscalerCondition(&status, autoscalingv2.AbleToScale, corev1.ConditionTrue, "SucceededGetScale",
+		"the HPA controller was able to get the target's current scale")
+
+	if container.State == nil || !container.State.Running {
+		status.CurrentMetrics = nil
+		setHorizontalPodAutoscalerCondition(&status, autoscalingv2.ScalingActive, corev1.ConditionFalse, "FailedGetResourceMetric",
+			fmt.Sprintf("the container of deployment %s is not running", targetName))
+		return status, nil
+	}
+
+	podSpec, err := getPodSpecFromLabels(adapter.resolveContainerLabels(container.ID, container.Config.Labels))
+	if err != nil {
+		return status, err
+	}
+
+	cpuUsage, memoryUsage, err := adapter.getContainerResourceUsage(ctx, container.ID)
+	if err != nil {
+		return status, err
+	}
+
+	metrics := horizontalPodAutoscaler.Spec.Metrics
+	if len(metrics) == 0 {
+		targetUtilization := defaultHorizontalPodAutoscalerCPUUtilization
+		metrics = []autoscalingv2.MetricSpec{
+			{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: &targetUtilization,
+					},
+				},
+			},
+		}
+	}
+
+	currentMetrics := []autoscalingv2.MetricStatus{}
+	usageRatio := 0.0
+	metricErrors := []string{}
+
+	for _, metric := range metrics {
+		if metric.Type != autoscalingv2.ResourceMetricSourceType || metric.Resource == nil {
+			metricErrors = append(metricErrors, fmt.Sprintf("unsupported metric type %q", metric.Type))
+			continue
+		}
+
+		var usage *resource.Quantity
+		switch metric.Resource.Name {
+		case corev1.ResourceCPU:
+			usage = resource.NewMilliQuantity(cpuUsage, resource.DecimalSI)
+		case corev1.ResourceMemory:
+			usage = resource.NewQuantity(memoryUsage, resource.BinarySI)
+		default:
+			metricErrors = append(metricErrors, fmt.Sprintf("unsupported resource %q", metric.Resource.Name))
+			continue
+		}
+
+		metricStatus := autoscalingv2.MetricStatus{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricStatus{
+				Name: metric.Resource.Name,
+				Current: autoscalingv2.MetricValueStatus{
+					AverageValue: usage,
+				},
+			},
+		}
+
+		switch metric.Resource.Target.Type {
+		case autoscalingv2.UtilizationMetricType:
+			request := getContainerResourceRequest(podSpec, core.ResourceName(metric.Resource.Name))
+			if request.IsZero() || metric.Resource.Target.AverageUtilization == nil {
+				metricErrors = append(metricErrors, fmt.Sprintf("missing request for %s", metric.Resource.Name))
+				continue
+			}
+
+			utilization := int32(usage.MilliValue() * 100 / request.MilliValue())
+			metricStatus.Resource.Current.AverageUtilization = &utilization
+			usageRatio = math.Max(usageRatio, float64(utilization)/float64(*metric.Resource.Target.AverageUtilization))
+		case autoscalingv2.AverageValueMetricType, autoscalingv2.ValueMetricType:
+			target := metric.Resource.Target.AverageValue
+			if target == nil {
+				target = metric.Resource.Target.Value
+			}
+
+			if target == nil || target.IsZero() {
+				metricErrors = append(metricErrors, fmt.Sprintf("missing target value for %s", metric.Resource.Name))
+				continue
+			}
+
+			usageRatio = math.Max(usageRatio, float64(usage.MilliValue())/float64(target.MilliValue()))
+		default:
+			metricErrors = append(metricErrors, fmt.Sprintf("unsupported target type %q for %s", metric.Resource.Target.Type, metric.Resource.Name))
+			continue
+		}
+
+		currentMetrics = append(currentMetrics, metricStatus)
+	}
+
+	status.CurrentMetrics = currentMetrics
+
+	if len(currentMetrics) == 0 {
+		setHorizontalPodAutoscalerCondition(&status, autoscalingv2.ScalingActive, corev1.ConditionFalse, "FailedGetResourceMetric",
+			fmt.Sprintf("the HPA was unable to compute the replica count: %s", strings.Join(metricErrors, ", ")))
+		return status, nil
+	}
+
+	setHorizontalPodAutoscalerCondition(&status, autoscalingv2.ScalingActive, corev1.ConditionTrue, "ValidMetricFound",
+		"the HPA was able to successfully calculate a replica count from resource metrics")
+
+	desiredReplicas := status.CurrentReplicas
+	if math.Abs(usageRatio-1.0) > horizontalPodAutoscalerTolerance {
+		desiredReplicas = int32(math.Ceil(usageRatio * float64(status.CurrentReplicas)))
+	}
+
+	minReplicas := getHorizontalPodAutoscalerMinReplicas(horizontalPodAutoscaler)
+	if desiredReplicas < minReplicas {
+		desiredReplicas = minReplicas
+	}
+	if desiredReplicas > horizontalPodAutoscaler.Spec.MaxReplicas {
+		desiredReplicas = horizontalPodAutoscaler.Spec.MaxReplicas
+	}
+
+	status.DesiredReplicas = desiredReplicas
+
+	if desiredReplicas != status.CurrentReplicas {
+		message := fmt.Sprintf("the desired replica count is %d but k2d runs a single container per deployment", desiredReplicas)
+		setHorizontalPodAutoscalerCondition(&status, autoscalingv2.ScalingLimited, corev1.ConditionTrue, "ReplicasNotSupported", message)
+
+		adapter.RecordEvent(core.ObjectReference{Kind: "Deployment", Name: targetName, Namespace: horizontalPodAutoscaler.Namespace},
+			core.EventTypeWarning, "FailedRescale", fmt.Sprintf("horizontal pod autoscaler %s: %s", horizontalPodAutoscaler.Name, message))
+	} else {
+		setHorizontalPodAutoscalerCondition(&status, autoscalingv2.ScalingLimited, corev1.ConditionFalse, "DesiredWithinRange",
+			"the desired count is within the acceptable range")
+	}
+
+	return status, nil
+}
+
+// getContainerResourceUsage returns the CPU usage (in millicores) and the memory working set (in bytes) of a container,
+// computed from a single sample of the Docker stats API in the same way as docker stats.
+func (adapter *KubeDockerAdapter) getContainerResourceUsage(ctx context.Context, containerID string) (int64, int64, error) {
+	stats, err := adapter.cli.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to get container stats: %w", err)
+	}
+	defer stats.Body.Close()
+
+	statsJSON := types.StatsJSON{}
+	err = json.NewDecoder(stats.Body).Decode(&statsJSON)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to decode container stats: %w", err)
+	}
+
+	cpuUsage := int64(0)
+	if statsJSON.CPUStats.CPUUsage.TotalUsage > statsJSON.PreCPUStats.CPUUsage.TotalUsage &&
+		statsJSON.CPUStats.SystemUsage > statsJSON.PreCPUStats.SystemUsage {
+		cpuDelta := float64(statsJSON.CPUStats.CPUUsage.TotalUsage - statsJSON.PreCPUStats.CPUUsage.TotalUsage)
+		systemDelta := float64(statsJSON.CPUStats.SystemUsage - statsJSON.PreCPUStats.SystemUsage)
+
+		onlineCPUs := float64(statsJSON.CPUStats.OnlineCPUs)
+		if onlineCPUs == 0 {
+			onlineCPUs = float64(len(statsJSON.CPUStats.CPUUsage.PercpuUsage))
+		}
+
+		cpuUsage = int64(cpuDelta / systemDelta * onlineCPUs * 1000)
+	}
+
+	memoryUsage := statsJSON.MemoryStats.Usage
+	inactiveFile, exists := statsJSON.MemoryStats.Stats["inactive_file"]
+	if !exists {
+		inactiveFile = statsJSON.MemoryStats.Stats["total_inactive_file"]
+	}
+	if inactiveFile < memoryUsage {
+		memoryUsage -= inactiveFile
+	}
+
+	return cpuUsage, int64(memoryUsage), nil
+}
+
+// getContainerResourceRequest returns the request of the container of a pod spec for the specified resource,
+// or its limit when no request is defined.
+func getContainerResourceRequest(podSpec *core.PodSpec, resourceName core.ResourceName) resource.Quantity {
+	if podSpec == nil || len(podSpec.Containers) == 0 {
+		return resource.Quantity{}
+	}
+
+	resources := podSpec.Containers[0].Resources
+	if request, exists := resources.Requests[resourceName]; exists {
+		return request
+	}
+
+	return resources.Limits[resourceName]
+}
+
+// getHorizontalPodAutoscalerMinReplicas returns the minimum replica count of a horizontal pod autoscaler, which defaults to 1.
+func getHorizontalPodAutoscalerMinReplicas(horizontalPodAutoscaler *autoscalingv2.HorizontalPodAutoscaler) int32 {
+	if horizontalPodAutoscaler.Spec.MinReplicas == nil {
+		return 1
+	}
+
+	return *horizontalPodAutoscaler.Spec.MinReplicas
+}
+
+// setHorizontalPodAutoscalerCondition sets a condition in the status of a horizontal pod autoscaler.
+// The last transition time is only updated when the status of the condition changes.
+func setHorizontalPodAutoscalerCondition(status *autoscalingv2.HorizontalPodAutoscalerStatus, conditionType autoscalingv2.HorizontalPodAutoscalerConditionType, conditionStatus corev1.ConditionStatus, reason, message string) {
+	condition := autoscalingv2.HorizontalPodAutoscalerCondition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	for i, existingCondition := range status.Conditions {
+		if existingCondition.Type != conditionType {
+			continue
+		}
+
+		if existingCondition.Status == conditionStatus {
+			condition.LastTransitionTime = existingCondition.LastTransitionTime
+		}
+
+		status.Conditions[i] = condition
+		return
+	}
+
+	status.Conditions = append(status.Conditions, condition)
+}
+
+// updateHorizontalPodAutoscalerStatus updates the status of the latest definition of a horizontal pod autoscaler,
+// so that the changes made to its spec during an evaluation are preserved.
+// It is a no-op if the autoscaler has been removed in the meantime.
+func (adapter *KubeDockerAdapter) updateHorizontalPodAutoscalerStatus(horizontalPodAutoscalerName, namespace string, status autoscalingv2.HorizontalPodAutoscalerStatus) error {
+	horizontalPodAutoscaler, err := adapter.getHorizontalPodAutoscaler(horizontalPodAutoscalerName, namespace)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	horizontalPodAutoscaler.Status = status
+
+	return adapter.storeHorizontalPodAutoscaler(horizontalPodAutoscaler)
+}
+
+func (adapter *KubeDockerAdapter) storeHorizontalPodAutoscaler(horizontalPodAutoscaler *autoscalingv2.HorizontalPodAutoscaler) error {
+	horizontalPodAutoscalerData, err := json.Marshal(horizontalPodAutoscaler)
+	if err != nil {
+		return fmt.Errorf("unable to marshal horizontal pod autoscaler: %w", err)
+	}
+
+	err = adapter.CreateSystemConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: naming.BuildHorizontalPodAutoscalerSystemConfigMapName(horizontalPodAutoscaler.Name, horizontalPodAutoscaler.Namespace),
+			Labels: map[string]string{
+				k2dtypes.NamespaceNameLabelKey: horizontalPodAutoscaler.Namespace,
+			},
+		},
+		Data: map[string]string{
+			horizontalPodAutoscalerManifestDataKey: string(horizontalPodAutoscalerData),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to store horizontal pod autoscaler system configmap: %w", err)
+	}
+
+	return nil
+}
+
+// getHorizontalPodAutoscaler returns a horizontal pod autoscaler stored in a system configmap.
+// It returns an ErrResourceNotFound error if the autoscaler does not exist.
+func (adapter *KubeDockerAdapter) getHorizontalPodAutoscaler(horizontalPodAutoscalerName, namespace string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	configMap, err := adapter.GetSystemConfigMap(naming.BuildHorizontalPodAutoscalerSystemConfigMapName(horizontalPodAutoscalerName, namespace))
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			return nil, adaptererr.ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("unable to get horizontal pod autoscaler system configmap: %w", err)
+	}
+
+	return decodeHorizontalPodAutoscaler(configMap.Data[horizontalPodAutoscalerManifestDataKey])
+}
+
+func decodeHorizontalPodAutoscaler(horizontalPodAutoscalerData string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	horizontalPodAutoscaler := autoscalingv2.HorizontalPodAutoscaler{}
+	err := json.Unmarshal([]byte(horizontalPodAutoscalerData), &horizontalPodAutoscaler)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal horizontal pod autoscaler: %w", err)
+	}
+
+	horizontalPodAutoscaler.TypeMeta = metav1.TypeMeta{
+		Kind:       "HorizontalPodAutoscaler",
+		APIVersion: "autoscaling/v2",
+	}
+
+	return &horizontalPodAutoscaler, nil
+}
+
+// listHorizontalPodAutoscalers returns the horizontal pod autoscalers of a namespace, or of all namespaces
+// when the namespace is empty, sorted by namespace and name.
+func (adapter *KubeDockerAdapter) listHorizontalPodAutoscalers(namespace string) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+	configMaps, err := adapter.ListSystemConfigMaps()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list system configmaps: %w", err)
+	}
+
+	horizontalPodAutoscalers := []autoscalingv2.HorizontalPodAutoscaler{}
+	for _, configMap := range configMaps.Items {
+		if !strings.HasPrefix(configMap.Name, naming.HorizontalPodAutoscalerSystemConfigMapPrefix) {
+			continue
+		}
+
+		if namespace != "" && configMap.Labels[k2dtypes.NamespaceNameLabelKey] != namespace {
+			continue
+		}
+
+		horizontalPodAutoscaler, err := decodeHorizontalPodAutoscaler(configMap.Data[horizontalPodAutoscalerManifestDataKey])
+		if err != nil {
+			adapter.logger.Warnf("unable to decode horizontal pod autoscaler stored in system configmap %s: %s", configMap.Name, err)
+			continue
+		}
+
+		horizontalPodAutoscalers = append(horizontalPodAutoscalers, *horizontalPodAutoscaler)
+	}
+
+	sort.Slice(horizontalPodAutoscalers, func(i, j int) bool {
+		if horizontalPodAutoscalers[i].Namespace != horizontalPodAutoscalers[j].Namespace {
+			return horizontalPodAutoscalers[i].Namespace < horizontalPodAutoscalers[j].Namespace
+		}
+		return horizontalPodAutoscalers[i].Name < horizontalPodAutoscalers[j].Name
+	})
+
+	return horizontalPodAutoscalers, nil
+}
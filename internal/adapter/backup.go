@@ -0,0 +1,527 @@
+package adapter
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/filters"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	filesystemstore "github.com/portainer/k2d/internal/adapter/store/filesystem"
+	volumestore "github.com/portainer/k2d/internal/adapter/store/volume"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"github.com/portainer/k2d/internal/types"
+	"github.com/portainer/k2d/pkg/filesystem"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// The entries of a backup archive. The metadata entry is always the first entry of the archive.
+const (
+	backupMetadataEntry   = "metadata.json"
+	backupNamespacesEntry = "resources/namespaces.json"
+	backupVolumesEntry    = "resources/volumes.json"
+	backupConfigMapsEntry = "resources/configmaps.json"
+	backupSecretsEntry    = "resources/secrets.json"
+	backupServicesEntry   = "resources/services.json"
+	backupDataPathPrefix  = "data/"
+)
+
+// backupExcludedDataPaths are the files and directories of the data path that are not part of a backup:
+//   - The logs of previous container instances, the volume snapshots and the container checkpoints, which can be large.
+//   - The configmaps and secrets of the filesystem store, which are backed up as resources so that a backup
+//     can be restored on a host using another store backend.
+//   - The encryption key of the registry secrets, the registry secrets are re-encrypted with the key of the restored host.
+//   - The operation queues and their temporary files, the operations of the previous host are outdated.
+var backupExcludedDataPaths = []string{
+	LogsFolder,
+	SnapshotsFolder,
+	CheckpointsFolder,
+	filesystemstore.ConfigMapFolder,
+	filesystemstore.SecretFolder,
+	volumestore.EncryptionKeyFileName,
+	types.PendingQueueFilename,
+	types.QueueTemporaryFilename(types.PendingQueueFilename),
+	types.RetryQueueFilename,
+	types.QueueTemporaryFilename(types.RetryQueueFilename),
+}
+
+// backupResources holds the resources read from a backup archive.
+type backupResources struct {
+	namespaces []corev1.Namespace
+	volumes    []volume.CreateOptions
+	configMaps []corev1.ConfigMap
+	secrets    []corev1.Secret
+	services   []corev1.Service
+}
+
+// CreateBackup writes a gzip compressed tar archive containing the state of k2d, which can be used to re-provision
+// a fresh host (see RestoreBackup). The archive contains:
+//   - The namespaces, except the default and k2d namespaces which are provisioned when k2d starts.
+//   - The metadata of the Docker volumes associated with persistent volumes (name, driver, options and labels).
+//     The content of the volumes is not part of the backup, use volume snapshots to back it up.
+//   - The configmaps and secrets of all namespaces, including the system configmaps storing the definition of the
+//     workloads, persistent volume claims and storage classes. The configurations of the containers are excluded as they
+//     are associated with container IDs that do not exist on another host.
+//   - The services, whose definition is stored in the labels of the containers.
+//   - The files of the data path (e.g. the secret, the TLS certificates), see backupExcludedDataPaths for the exclusions.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - w: The writer the archive is written to.
+//
+// Returns:
+// - An error if a resource cannot be listed or if the archive cannot be written.
+func (adapter *KubeDockerAdapter) CreateBackup(ctx context.Context, w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err := writeBackupJSONEntry(tarWriter, backupMetadataEntry, k2dtypes.BackupMetadata{
+		Version:           types.Version,
+		CreationTimestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	namespaceList, err := adapter.ListNamespaces(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list namespaces: %w", err)
+	}
+
+	namespaces := []corev1.Namespace{}
+	for _, namespace := range namespaceList.Items {
+		if namespace.Name == "default" || namespace.Name == k2dtypes.K2DNamespaceName {
+			continue
+		}
+		namespaces = append(namespaces, namespace)
+	}
+
+	err = writeBackupJSONEntry(tarWriter, backupNamespacesEntry, namespaces)
+	if err != nil {
+		return err
+	}
+
+	volumeList, err := adapter.cli.VolumeList(ctx, volume.ListOptions{Filters: filters.AllPersistentVolumes()})
+	if err != nil {
+		return fmt.Errorf("unable to list volumes: %w", err)
+	}
+
+	volumes := []volume.CreateOptions{}
+	for _, vol := range volumeList.Volumes {
+		volumes = append(volumes, volume.CreateOptions{
+			Name:       vol.Name,
+			Driver:     vol.Driver,
+			DriverOpts: vol.Options,
+			Labels:     vol.Labels,
+		})
+	}
+
+	err = writeBackupJSONEntry(tarWriter, backupVolumesEntry, volumes)
+	if err != nil {
+		return err
+	}
+
+	configMapList, err := adapter.ListConfigMaps("")
+	if err != nil {
+		return fmt.Errorf("unable to list configmaps: %w", err)
+	}
+
+	configMaps := []corev1.ConfigMap{}
+	for _, configMap := range configMapList.Items {
+		if configMap.Namespace == k2dtypes.K2DNamespaceName && strings.HasPrefix(configMap.Name, naming.ContainerPayloadSystemConfigMapPrefix) {
+			continue
+		}
+		configMaps = append(configMaps, configMap)
+	}
+
+	err = writeBackupJSONEntry(tarWriter, backupConfigMapsEntry, configMaps)
+	if err != nil {
+		return err
+	}
+
+	secretList, err := adapter.ListSecrets("", labels.Everything())
+	if err != nil {
+		return fmt.Errorf("unable to list secrets: %w", err)
+	}
+
+	err = writeBackupJSONEntry(tarWriter, backupSecretsEntry, secretList.Items)
+	if err != nil {
+		return err
+	}
+
+	serviceList, err := adapter.ListServices(ctx, "")
+	if err != nil {
+		return fmt.Errorf("unable to list services: %w", err)
+	}
+
+	err = writeBackupJSONEntry(tarWriter, backupServicesEntry, serviceList.Items)
+	if err != nil {
+		return err
+	}
+
+	err = adapter.writeBackupDataPath(tarWriter)
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return fmt.Errorf("unable to close backup archive: %w", err)
+	}
+
+	return gzipWriter.Close()
+}
+
+// writeBackupJSONEntry writes a value encoded as JSON as an entry of a backup archive.
+func writeBackupJSONEntry(tarWriter *tar.Writer, name string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("unable to marshal backup entry %s: %w", name, err)
+	}
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to write backup entry header %s: %w", name, err)
+	}
+
+	_, err = tarWriter.Write(data)
+	if err != nil {
+		return fmt.Errorf("unable to write backup entry %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// writeBackupDataPath writes the regular files of the data path as entries of a backup archive.
+func (adapter *KubeDockerAdapter) writeBackupDataPath(tarWriter *tar.Writer) error {
+	return filepath.WalkDir(adapter.dataPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(adapter.dataPath, filePath)
+		if err != nil {
+			return err
+		}
+		relativePath = filepath.ToSlash(relativePath)
+
+		if relativePath == "." {
+			return nil
+		}
+
+		if isBackupExcludedDataPath(relativePath) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("unable to retrieve file information of %s: %w", relativePath, err)
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return fmt.Errorf("unable to build backup entry header of %s: %w", relativePath, err)
+		}
+		header.Name = backupDataPathPrefix + relativePath
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return fmt.Errorf("unable to write backup entry header of %s: %w", relativePath, err)
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return fmt.Errorf("unable to open %s: %w", relativePath, err)
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+		if err != nil {
+			return fmt.Errorf("unable to write backup entry of %s: %w", relativePath, err)
+		}
+
+		return nil
+	})
+}
+
+// isBackupExcludedDataPath returns true if a path relative to the data path is excluded from the backups.
+func isBackupExcludedDataPath(relativePath string) bool {
+	rootName := strings.SplitN(relativePath, "/", 2)[0]
+	for _, excludedPath := range backupExcludedDataPaths {
+		if rootName == excludedPath {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RestoreBackup restores a backup archive created by CreateBackup. It is designed to re-provision a fresh host,
+// e.g. when replacing a device, and never overwrites the resources that already exist on this host:
+//  1. The files of the data path are restored. The files used when k2d starts (e.g. the secret, the TLS certificates)
+//     are only used after k2d is restarted, which is reported through RestartRequired.
+//  2. The namespaces and the Docker volumes are created.
+//  3. The configmaps and secrets are created, including the system configmaps storing the definition of the workloads.
+//  4. The workloads are re-created from their definition by reconciling them (see ReconcileWorkloads). The images are pulled if needed.
+//  5. The services are re-applied to the containers of the workloads.
+//
+// A failure to restore a resource does not stop the restoration, the error is added to the report.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - r: The reader the archive is read from.
+//
+// Returns:
+// - A report summarizing the restoration.
+// - An ErrInvalidResource error if the archive is not a k2d backup archive, or an error if the archive cannot be read.
+func (adapter *KubeDockerAdapter) RestoreBackup(ctx context.Context, r io.Reader) (k2dtypes.RestoreReport, error) {
+	report := k2dtypes.RestoreReport{}
+
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return report, fmt.Errorf("%w: unable to read backup archive: %w", adaptererr.ErrInvalidResource, err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	resources := backupResources{}
+	metadataFound := false
+
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return report, fmt.Errorf("%w: unable to read backup archive: %w", adaptererr.ErrInvalidResource, err)
+		}
+
+		if !metadataFound {
+			if header.Name != backupMetadataEntry {
+				return report, fmt.Errorf("%w: not a k2d backup archive", adaptererr.ErrInvalidResource)
+			}
+
+			err = json.NewDecoder(tarReader).Decode(&report.Backup)
+			if err != nil {
+				return report, fmt.Errorf("%w: unable to decode backup metadata: %w", adaptererr.ErrInvalidResource, err)
+			}
+
+			metadataFound = true
+			continue
+		}
+
+		switch header.Name {
+		case backupNamespacesEntry:
+			err = json.NewDecoder(tarReader).Decode(&resources.namespaces)
+		case backupVolumesEntry:
+			err = json.NewDecoder(tarReader).Decode(&resources.volumes)
+		case backupConfigMapsEntry:
+			err = json.NewDecoder(tarReader).Decode(&resources.configMaps)
+		case backupSecretsEntry:
+			err = json.NewDecoder(tarReader).Decode(&resources.secrets)
+		case backupServicesEntry:
+			err = json.NewDecoder(tarReader).Decode(&resources.services)
+		default:
+			if strings.HasPrefix(header.Name, backupDataPathPrefix) && header.Typeflag == tar.TypeReg {
+				err = adapter.restoreBackupDataPathFile(header, tarReader, &report)
+			}
+		}
+		if err != nil {
+			return report, fmt.Errorf("%w: unable to restore backup entry %s: %w", adaptererr.ErrInvalidResource, header.Name, err)
+		}
+	}
+
+	if !metadataFound {
+		return report, fmt.Errorf("%w: not a k2d backup archive", adaptererr.ErrInvalidResource)
+	}
+
+	adapter.restoreBackupResources(ctx, resources, &report)
+
+	adapter.logger.Infow("backup restored",
+		"backup_version", report.Backup.Version,
+		"backup_creation_timestamp", report.Backup.CreationTimestamp,
+		"files", report.Files,
+		"namespaces", report.Namespaces,
+		"volumes", report.Volumes,
+		"configmaps", report.ConfigMaps,
+		"secrets", report.Secrets,
+		"services", report.Services,
+		"skipped", report.Skipped,
+		"errors", len(report.Errors),
+	)
+
+	return report, nil
+}
+
+// restoreBackupDataPathFile writes a file of a backup archive inside the data path.
+// Entries escaping the data path are rejected and excluded paths are ignored.
+func (adapter *KubeDockerAdapter) restoreBackupDataPathFile(header *tar.Header, r io.Reader, report *k2dtypes.RestoreReport) error {
+	relativePath := path.Clean(strings.TrimPrefix(header.Name, backupDataPathPrefix))
+	if path.IsAbs(relativePath) || relativePath == ".." || strings.HasPrefix(relativePath, "../") {
+		return fmt.Errorf("invalid file path %s", header.Name)
+	}
+
+	if isBackupExcludedDataPath(relativePath) {
+		return nil
+	}
+
+	filePath := filepath.Join(adapter.dataPath, filepath.FromSlash(relativePath))
+
+	err := filesystem.CreateDir(filepath.Dir(filePath))
+	if err != nil {
+		return fmt.Errorf("unable to create directory: %w", err)
+	}
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, header.FileInfo().Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("unable to create file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, r)
+	if err != nil {
+		return fmt.Errorf("unable to write file: %w", err)
+	}
+
+	report.Files++
+	report.RestartRequired = true
+
+	return nil
+}
+
+// restoreBackupResources creates the resources of a backup archive that do not exist on this host, in dependency order.
+func (adapter *KubeDockerAdapter) restoreBackupResources(ctx context.Context, resources backupResources, report *k2dtypes.RestoreReport) {
+	addError := func(kind, name string, err error) {
+		report.Errors = append(report.Errors, fmt.Sprintf("%s %s: %s", kind, name, err))
+	}
+
+	for _, namespace := range resources.namespaces {
+		_, err := adapter.getNetwork(ctx, naming.BuildNetworkName(namespace.Name))
+		if err == nil {
+			report.Skipped++
+			continue
+		} else if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+			addError("namespace", namespace.Name, err)
+			continue
+		}
+
+		if namespace.Annotations == nil {
+			namespace.Annotations = map[string]string{}
+		}
+
+		err = adapter.CreateNetworkFromNamespace(ctx, &namespace)
+		if err != nil {
+			addError("namespace", namespace.Name, err)
+			continue
+		}
+		report.Namespaces++
+	}
+
+	for _, volumeOptions := range resources.volumes {
+		_, err := adapter.cli.VolumeInspect(ctx, volumeOptions.Name)
+		if err == nil {
+			report.Skipped++
+			continue
+		} else if !errdefs.IsNotFound(err) {
+			addError("volume", volumeOptions.Name, err)
+			continue
+		}
+
+		_, err = adapter.cli.VolumeCreate(ctx, volumeOptions)
+		if err != nil {
+			addError("volume", volumeOptions.Name, err)
+			continue
+		}
+		report.Volumes++
+	}
+
+	for _, configMap := range resources.configMaps {
+		_, err := adapter.configMapStore.GetConfigMap(configMap.Name, configMap.Namespace)
+		if err == nil {
+			report.Skipped++
+			continue
+		} else if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+			addError("configmap", configMap.Namespace+"/"+configMap.Name, err)
+			continue
+		}
+
+		err = adapter.configMapStore.StoreConfigMap(&configMap)
+		if err != nil {
+			addError("configmap", configMap.Namespace+"/"+configMap.Name, err)
+			continue
+		}
+		report.ConfigMaps++
+	}
+
+	for _, secret := range resources.secrets {
+		_, err := adapter.getSecret(secret.Name, secret.Namespace)
+		if err == nil {
+			report.Skipped++
+			continue
+		} else if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+			addError("secret", secret.Namespace+"/"+secret.Name, err)
+			continue
+		}
+
+		err = adapter.CreateSecret(&secret)
+		if err != nil {
+			addError("secret", secret.Namespace+"/"+secret.Name, err)
+			continue
+		}
+		report.Secrets++
+	}
+
+	err := adapter.ReconcileWorkloads(ctx)
+	if err != nil {
+		addError("workloads", "", err)
+	}
+
+	for _, service := range resources.services {
+		if service.Annotations == nil {
+			service.Annotations = map[string]string{}
+		}
+
+		// The definition of the service is rebuilt from the last applied configuration stored in the container labels
+		if service.Annotations["kubectl.kubernetes.io/last-applied-configuration"] == "" {
+			serviceData, err := json.Marshal(service)
+			if err != nil {
+				addError("service", service.Namespace+"/"+service.Name, err)
+				continue
+			}
+			service.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = string(serviceData)
+		}
+
+		err := adapter.CreateContainerFromService(ctx, &service)
+		if err != nil {
+			addError("service", service.Namespace+"/"+service.Name, err)
+			continue
+		}
+		report.Services++
+	}
+}
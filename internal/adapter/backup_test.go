@@ -0,0 +1,74 @@
+package adapter
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/portainer/k2d/internal/types"
+)
+
+func TestWriteBackupDataPathExclusions(t *testing.T) {
+	dataPath := t.TempDir()
+	adapter := &KubeDockerAdapter{dataPath: dataPath}
+
+	files := map[string]bool{
+		"secret":                   true,
+		types.PendingQueueFilename: false,
+		types.RetryQueueFilename:   false,
+		types.QueueTemporaryFilename(types.PendingQueueFilename): false,
+		types.QueueTemporaryFilename(types.RetryQueueFilename):   false,
+		path.Join(LogsFolder, "pod.log"):                         false,
+	}
+
+	for name := range files {
+		filePath := path.Join(dataPath, name)
+
+		err := os.MkdirAll(path.Dir(filePath), 0700)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		err = os.WriteFile(filePath, []byte("content"), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	var archive bytes.Buffer
+	tarWriter := tar.NewWriter(&archive)
+
+	err := adapter.writeBackupDataPath(tarWriter)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	entries := map[string]bool{}
+	tarReader := tar.NewReader(&archive)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		entries[header.Name] = true
+	}
+
+	for name, included := range files {
+		if entries[backupDataPathPrefix+name] != included {
+			t.Errorf("expected %s to be included in the archive: %t", name, included)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/docker/docker/api/types"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// getCanaryWeight returns the weight defined in the CanaryWeightAnnotationKey annotation of a deployment
+// and whether the deployment must be rolled out as a canary, that is when the weight is lower than 100.
+// The weight does not set the share of the traffic sent to the canary: Docker DNS round-robins between the containers
+// behind the service aliases, so the traffic is split evenly for any weight between 1 and 99 (see deployCanary).
+// It returns an ErrInvalidResource error if the weight is not an integer between 0 and 100.
+func getCanaryWeight(annotations map[string]string) (int, bool, error) {
+	value, exists := annotations[k2dtypes.CanaryWeightAnnotationKey]
+	if !exists {
+		return 0, false, nil
+	}
+
+	weight, err := strconv.Atoi(value)
+	if err != nil || weight < 0 || weight > 100 {
+		return 0, false, fmt.Errorf("%w: invalid %s annotation %q, the weight must be an integer between 0 and 100",
+			adaptererr.ErrInvalidResource, k2dtypes.CanaryWeightAnnotationKey, value)
+	}
+
+	return weight, weight < 100, nil
+}
+
+// buildCanaryName returns the name of the pod running the canary of a deployment.
+// As a pod created by a user can have the same name, use isCanaryContainer before replacing or removing the pod.
+func buildCanaryName(deploymentName string) string {
+	return deploymentName + "-canary"
+}
+
+// isCanaryContainer returns true if a container runs the canary of the specified deployment.
+func isCanaryContainer(container *types.ContainerJSON, deploymentName string) bool {
+	return container.Config != nil && container.Config.Labels[k2dtypes.CanaryOfLabelKey] == deploymentName
+}
+
+// deployCanary rolls out a new version of a deployment as a canary: the new version runs in a separate container,
+// exposed as the <deployment>-canary pod, while the container of the deployment keeps running the current version.
+// When the weight is greater than 0, the canary container is placed behind the aliases of the service associated
+// with the deployment once it is ready, so that Docker DNS round-robins the traffic of the service between both containers.
+// The traffic is therefore split evenly between the canary and the current version whatever the weight between 1 and 99.
+// A weight of 0 lets operators validate the canary without sending it any traffic of the service.
+//
+// The canary is promoted when the deployment is applied with a weight of 100 or without the CanaryWeightAnnotationKey annotation,
+// and can be aborted by deleting the canary pod.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - deployment: The deployment to roll out.
+// - opts: The options used to create the container of the deployment.
+// - weight: The canary weight.
+//
+// Returns:
+// - false when the deployment does not have a running container yet, in which case it must be rolled out normally.
+// - An ErrResourceConflict error if a pod that is not the canary of the deployment already uses the name of the canary pod.
+// - An error if the canary container cannot be created, does not become ready or cannot be placed behind the service aliases.
+func (adapter *KubeDockerAdapter) deployCanary(ctx context.Context, deployment *appsv1.Deployment, opts ContainerCreationOptions, weight int) (bool, error) {
+	stableContainer, err := adapter.getContainer(ctx, naming.BuildContainerName(deployment.Name, deployment.Namespace))
+	if err != nil {
+		return false, fmt.Errorf("unable to inspect container: %w", err)
+	}
+
+	if stableContainer == nil || stableContainer.State == nil || !stableContainer.State.Running {
+		return false, nil
+	}
+
+	canaryName := buildCanaryName(deployment.Name)
+
+	existingCanary, err := adapter.getContainer(ctx, naming.BuildContainerName(canaryName, deployment.Namespace))
+	if err != nil {
+		return false, fmt.Errorf("unable to inspect canary container: %w", err)
+	}
+
+	if existingCanary != nil && !isCanaryContainer(existingCanary, deployment.Name) {
+		return false, fmt.Errorf("%w: pod %s already exists and is not the canary of deployment %s",
+			adaptererr.ErrResourceConflict, canaryName, deployment.Name)
+	}
+
+	labels := map[string]string{}
+	for key, value := range opts.labels {
+		labels[key] = value
+	}
+	delete(labels, k2dtypes.WorkloadTypeLabelKey)
+	labels[k2dtypes.CanaryOfLabelKey] = deployment.Name
+
+	err = adapter.createContainerFromPodSpec(ctx, ContainerCreationOptions{
+		annotations:              opts.annotations,
+		containerName:            canaryName,
+		labels:                   labels,
+		lastAppliedConfiguration: opts.lastAppliedConfiguration,
+		namespace:                deployment.Namespace,
+		podSpec:                  opts.podSpec,
+	})
+	if err != nil {
+		return false, fmt.Errorf("unable to create canary container: %w", err)
+	}
+
+	serviceName := stableContainer.Config.Labels[k2dtypes.ServiceNameLabelKey]
+	if weight > 0 && serviceName != "" {
+		canaryContainerName := naming.BuildContainerName(canaryName, deployment.Namespace)
+
+		canaryContainer, err := adapter.getContainer(ctx, canaryContainerName)
+		if err != nil {
+			return false, fmt.Errorf("unable to inspect canary container: %w", err)
+		}
+
+		if canaryContainer == nil {
+			return false, fmt.Errorf("%w: canary container %s", adaptererr.ErrResourceNotFound, canaryContainerName)
+		}
+
+		if !hasNetworkAlias(canaryContainer, naming.BuildNetworkName(deployment.Namespace), serviceName) {
+			err = adapter.waitForContainerReady(ctx, canaryContainer.ID)
+			if err != nil {
+				return false, fmt.Errorf("canary container is not ready: %w", err)
+			}
+
+			err = adapter.attachServiceAliases(ctx, canaryContainer.ID, serviceName, deployment.Namespace)
+			if err != nil {
+				return false, fmt.Errorf("unable to attach service aliases to canary container: %w", err)
+			}
+		}
+	}
+
+	adapter.RecordEvent(core.ObjectReference{Kind: "Deployment", Name: deployment.Name, Namespace: deployment.Namespace},
+		core.EventTypeNormal, "CanaryDeployed", fmt.Sprintf("canary %s deployed with a weight of %d", canaryName, weight))
+
+	return true, nil
+}
+
+// DeleteDeploymentCanary removes the canary pod of a deployment, if any.
+// It is used when the canary is promoted or when the deployment is deleted.
+func (adapter *KubeDockerAdapter) DeleteDeploymentCanary(ctx context.Context, deploymentName, namespace string) {
+	canaryName := buildCanaryName(deploymentName)
+
+	canaryContainer, err := adapter.getContainer(ctx, naming.BuildContainerName(canaryName, namespace))
+	if err != nil {
+		adapter.logger.Warnf("unable to inspect canary container of deployment %s: %s", deploymentName, err)
+		return
+	}
+
+	if canaryContainer == nil || !isCanaryContainer(canaryContainer, deploymentName) {
+		return
+	}
+
+	adapter.DeleteContainer(ctx, canaryName, namespace)
+}
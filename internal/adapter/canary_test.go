@@ -0,0 +1,67 @@
+package adapter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+)
+
+func TestGetCanaryWeight(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		weight      int
+		isCanary    bool
+		invalid     bool
+	}{
+		{name: "no annotation", annotations: map[string]string{}},
+		{name: "no traffic", annotations: map[string]string{k2dtypes.CanaryWeightAnnotationKey: "0"}, weight: 0, isCanary: true},
+		{name: "traffic", annotations: map[string]string{k2dtypes.CanaryWeightAnnotationKey: "20"}, weight: 20, isCanary: true},
+		{name: "promotion", annotations: map[string]string{k2dtypes.CanaryWeightAnnotationKey: "100"}, weight: 100},
+		{name: "above 100", annotations: map[string]string{k2dtypes.CanaryWeightAnnotationKey: "101"}, invalid: true},
+		{name: "not an integer", annotations: map[string]string{k2dtypes.CanaryWeightAnnotationKey: "half"}, invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			weight, isCanary, err := getCanaryWeight(test.annotations)
+			if test.invalid {
+				if !errors.Is(err, adaptererr.ErrInvalidResource) {
+					t.Errorf("expected an invalid resource error, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if weight != test.weight || isCanary != test.isCanary {
+				t.Errorf("expected weight %d and canary %t, got %d and %t", test.weight, test.isCanary, weight, isCanary)
+			}
+		})
+	}
+}
+
+func TestIsCanaryContainer(t *testing.T) {
+	newContainer := func(labels map[string]string) *types.ContainerJSON {
+		return &types.ContainerJSON{Config: &container.Config{Labels: labels}}
+	}
+
+	if !isCanaryContainer(newContainer(map[string]string{k2dtypes.CanaryOfLabelKey: "web"}), "web") {
+		t.Error("expected the canary of the deployment to be detected")
+	}
+
+	if isCanaryContainer(newContainer(map[string]string{k2dtypes.CanaryOfLabelKey: "api"}), "web") {
+		t.Error("expected the canary of another deployment not to be detected")
+	}
+
+	// A pod named web-canary created by a user
+	if isCanaryContainer(newContainer(map[string]string{k2dtypes.WorkloadTypeLabelKey: "pod"}), "web") {
+		t.Error("expected a pod created by a user not to be detected as a canary")
+	}
+}
@@ -0,0 +1,472 @@
+package adapter
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"github.com/portainer/k2d/pkg/filesystem"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// CheckpointsFolder is the name of the directory, relative to the k2d data path, where the container checkpoints are stored.
+	// The checkpoints are written and read by the Docker daemon: as for the termination messages, the data path must be
+	// available at the same path on the Docker host.
+	CheckpointsFolder = "checkpoints"
+
+	// checkpointArchiveMetadataEntry is the name of the entry storing the metadata of the checkpoint in a checkpoint archive
+	checkpointArchiveMetadataEntry = "checkpoint.json"
+
+	// checkpointArchiveDataPrefix is the prefix of the entries storing the files of the checkpoint in a checkpoint archive
+	checkpointArchiveDataPrefix = "checkpoint/"
+
+	// checkpointImportPrefix is the prefix of the temporary directories where the checkpoint archives are extracted
+	checkpointImportPrefix = ".import-"
+)
+
+// checkpointDataPath returns the directory where the Docker daemon stores the files of a checkpoint.
+// The daemon stores a checkpoint in a directory named after the checkpoint ID inside the checkpoint directory.
+func (adapter *KubeDockerAdapter) checkpointDataPath(checkpointName string) string {
+	return path.Join(adapter.checkpointsPath, checkpointName)
+}
+
+func (adapter *KubeDockerAdapter) checkpointMetadataPath(checkpointName string) string {
+	return path.Join(adapter.checkpointsPath, checkpointName+".json")
+}
+
+// CreateContainerCheckpoint checkpoints the processes of the running container of a pod through the Docker checkpoint API.
+// This is an experimental feature: the Docker daemon must run with the experimental features enabled and CRIU must be
+// installed on the Docker host. The checkpoint can be restored on this host or, after being exported and imported,
+// on another k2d host, e.g. to migrate a stateful workload before the maintenance of a device.
+// The checkpoint only contains the state of the processes, the volumes of the pod can be migrated through the volume snapshots.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - namespace: The namespace of the pod.
+// - podName: The name of the pod.
+// - checkpointName: The name of the checkpoint, a name based on the pod name and the current time is generated when empty.
+// - exit: Whether the container is stopped after the checkpoint is taken, to prevent its state from diverging from the checkpoint.
+//
+// Returns:
+// - The checkpoint.
+// - An ErrResourceNotFound error if the pod does not exist, an ErrResourceConflict error if its container is not running,
+// an ErrResourceAlreadyExists error if a checkpoint with the same name exists or an ErrInvalidResource error if the checkpoint name is invalid.
+func (adapter *KubeDockerAdapter) CreateContainerCheckpoint(ctx context.Context, namespace, podName, checkpointName string, exit bool) (k2dtypes.ContainerCheckpoint, error) {
+	if checkpointName == "" {
+		checkpointName = fmt.Sprintf("%s-%d", podName, time.Now().Unix())
+	}
+
+	err := validateCheckpointName(checkpointName)
+	if err != nil {
+		return k2dtypes.ContainerCheckpoint{}, err
+	}
+
+	_, err = os.Stat(adapter.checkpointMetadataPath(checkpointName))
+	if err == nil {
+		return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("%w: checkpoint %s", adaptererr.ErrResourceAlreadyExists, checkpointName)
+	}
+
+	cntr, err := adapter.findContainerFromPodAndNamespace(ctx, podName, namespace)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("%w: pod %s/%s", adaptererr.ErrResourceNotFound, namespace, podName)
+		}
+		return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("unable to find container associated to the pod %s/%s: %w", namespace, podName, err)
+	}
+
+	if cntr.State != "running" {
+		return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("%w: the container of pod %s/%s is not running", adaptererr.ErrResourceConflict, namespace, podName)
+	}
+
+	err = adapter.cli.CheckpointCreate(ctx, cntr.ID, types.CheckpointCreateOptions{
+		CheckpointID:  checkpointName,
+		CheckpointDir: adapter.checkpointsPath,
+		Exit:          exit,
+	})
+	if err != nil {
+		os.RemoveAll(adapter.checkpointDataPath(checkpointName))
+		return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("unable to checkpoint the container of pod %s/%s: %w", namespace, podName, err)
+	}
+
+	checkpoint := k2dtypes.ContainerCheckpoint{
+		Name:              checkpointName,
+		Namespace:         namespace,
+		PodName:           podName,
+		Image:             cntr.Image,
+		CreationTimestamp: time.Now().UTC(),
+	}
+
+	err = adapter.storeCheckpointMetadata(checkpoint)
+	if err != nil {
+		os.RemoveAll(adapter.checkpointDataPath(checkpointName))
+		return k2dtypes.ContainerCheckpoint{}, err
+	}
+
+	adapter.logger.Infow("container checkpoint created",
+		"checkpoint", checkpointName,
+		"namespace", namespace,
+		"pod", podName,
+		"exit", exit,
+	)
+
+	return checkpoint, nil
+}
+
+// RestoreContainerCheckpoint restores a checkpoint in the container of a pod. The pod must have been created from the same
+// image as the pod the checkpoint was taken from, e.g. by deploying the same workload on the target host.
+// The container is stopped when it is running, then started from the checkpoint: the state of its running processes is lost.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - checkpointName: The name of the checkpoint to restore.
+// - namespace: The namespace of the pod to restore the checkpoint into, the namespace of the checkpoint when empty.
+// - podName: The name of the pod to restore the checkpoint into, the pod the checkpoint was taken from when empty.
+//
+// Returns:
+// - An ErrResourceNotFound error if the checkpoint or the pod does not exist, or an ErrResourceConflict error if the pod
+// does not use the image of the checkpoint.
+func (adapter *KubeDockerAdapter) RestoreContainerCheckpoint(ctx context.Context, checkpointName, namespace, podName string) error {
+	checkpoint, err := adapter.GetContainerCheckpoint(checkpointName)
+	if err != nil {
+		return err
+	}
+
+	if namespace == "" {
+		namespace = checkpoint.Namespace
+	}
+
+	if podName == "" {
+		podName = checkpoint.PodName
+	}
+
+	cntr, err := adapter.findContainerFromPodAndNamespace(ctx, podName, namespace)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			return fmt.Errorf("%w: pod %s/%s", adaptererr.ErrResourceNotFound, namespace, podName)
+		}
+		return fmt.Errorf("unable to find container associated to the pod %s/%s: %w", namespace, podName, err)
+	}
+
+	if cntr.Image != checkpoint.Image {
+		return fmt.Errorf("%w: the container of pod %s/%s uses image %s, the checkpoint was taken from a container using image %s",
+			adaptererr.ErrResourceConflict, namespace, podName, cntr.Image, checkpoint.Image)
+	}
+
+	if cntr.State == "running" {
+		err = adapter.cli.ContainerStop(ctx, cntr.ID, container.StopOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to stop the container of pod %s/%s: %w", namespace, podName, err)
+		}
+	}
+
+	err = adapter.cli.ContainerStart(ctx, cntr.ID, types.ContainerStartOptions{
+		CheckpointID:  checkpointName,
+		CheckpointDir: adapter.checkpointsPath,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to restore checkpoint %s in the container of pod %s/%s: %w", checkpointName, namespace, podName, err)
+	}
+
+	adapter.logger.Infow("container checkpoint restored",
+		"checkpoint", checkpointName,
+		"namespace", namespace,
+		"pod", podName,
+	)
+
+	return nil
+}
+
+// GetContainerCheckpoint returns a checkpoint. It returns an ErrResourceNotFound error if the checkpoint does not exist.
+func (adapter *KubeDockerAdapter) GetContainerCheckpoint(checkpointName string) (k2dtypes.ContainerCheckpoint, error) {
+	err := validateCheckpointName(checkpointName)
+	if err != nil {
+		return k2dtypes.ContainerCheckpoint{}, err
+	}
+
+	data, err := os.ReadFile(adapter.checkpointMetadataPath(checkpointName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("%w: checkpoint %s", adaptererr.ErrResourceNotFound, checkpointName)
+		}
+		return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("unable to read checkpoint metadata: %w", err)
+	}
+
+	checkpoint := k2dtypes.ContainerCheckpoint{}
+	err = json.Unmarshal(data, &checkpoint)
+	if err != nil {
+		return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("unable to unmarshal checkpoint metadata: %w", err)
+	}
+
+	return checkpoint, nil
+}
+
+// ListContainerCheckpoints returns the checkpoints sorted by creation time, the most recent first.
+func (adapter *KubeDockerAdapter) ListContainerCheckpoints() ([]k2dtypes.ContainerCheckpoint, error) {
+	entries, err := os.ReadDir(adapter.checkpointsPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read checkpoints directory: %w", err)
+	}
+
+	checkpoints := []k2dtypes.ContainerCheckpoint{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		checkpoint, err := adapter.GetContainerCheckpoint(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			adapter.logger.Warnf("unable to read checkpoint %s: %s", entry.Name(), err)
+			continue
+		}
+
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].CreationTimestamp.After(checkpoints[j].CreationTimestamp)
+	})
+
+	return checkpoints, nil
+}
+
+// DeleteContainerCheckpoint removes a checkpoint. It returns an ErrResourceNotFound error if the checkpoint does not exist.
+func (adapter *KubeDockerAdapter) DeleteContainerCheckpoint(checkpointName string) error {
+	_, err := adapter.GetContainerCheckpoint(checkpointName)
+	if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(adapter.checkpointDataPath(checkpointName))
+	if err != nil {
+		return fmt.Errorf("unable to remove checkpoint files: %w", err)
+	}
+
+	err = os.Remove(adapter.checkpointMetadataPath(checkpointName))
+	if err != nil {
+		return fmt.Errorf("unable to remove checkpoint metadata: %w", err)
+	}
+
+	return nil
+}
+
+// ExportContainerCheckpoint writes a checkpoint as a tar archive that can be imported on another k2d host through ImportContainerCheckpoint.
+// The archive contains the metadata of the checkpoint and the files written by the Docker daemon.
+// It returns an ErrResourceNotFound error if the checkpoint does not exist.
+func (adapter *KubeDockerAdapter) ExportContainerCheckpoint(checkpointName string, w io.Writer) error {
+	checkpoint, err := adapter.GetContainerCheckpoint(checkpointName)
+	if err != nil {
+		return err
+	}
+
+	tarWriter := tar.NewWriter(w)
+
+	err = writeBackupJSONEntry(tarWriter, checkpointArchiveMetadataEntry, checkpoint)
+	if err != nil {
+		return err
+	}
+
+	dataPath := adapter.checkpointDataPath(checkpointName)
+	err = filepath.WalkDir(dataPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(dataPath, filePath)
+		if err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("unable to retrieve file information of %s: %w", relativePath, err)
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return fmt.Errorf("unable to build checkpoint entry header of %s: %w", relativePath, err)
+		}
+		header.Name = checkpointArchiveDataPrefix + filepath.ToSlash(relativePath)
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return fmt.Errorf("unable to write checkpoint entry header of %s: %w", relativePath, err)
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return fmt.Errorf("unable to open %s: %w", relativePath, err)
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+		if err != nil {
+			return fmt.Errorf("unable to write checkpoint entry of %s: %w", relativePath, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to archive checkpoint %s: %w", checkpointName, err)
+	}
+
+	return tarWriter.Close()
+}
+
+// ImportContainerCheckpoint imports a checkpoint archive created by ExportContainerCheckpoint, e.g. on the host a workload
+// is migrated to. The archive is extracted in a temporary directory which is renamed once the archive is fully read,
+// an interrupted import does not leave a partial checkpoint.
+//
+// Parameters:
+// - r: The reader the archive is read from.
+//
+// Returns:
+// - The imported checkpoint.
+// - An ErrInvalidResource error if the archive is not a valid checkpoint archive, or an ErrResourceAlreadyExists error
+// if a checkpoint with the same name exists.
+func (adapter *KubeDockerAdapter) ImportContainerCheckpoint(r io.Reader) (k2dtypes.ContainerCheckpoint, error) {
+	importPath, err := os.MkdirTemp(adapter.checkpointsPath, checkpointImportPrefix)
+	if err != nil {
+		return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("unable to create checkpoint import directory: %w", err)
+	}
+	defer os.RemoveAll(importPath)
+
+	var checkpoint *k2dtypes.ContainerCheckpoint
+
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("%w: unable to read checkpoint archive: %s", adaptererr.ErrInvalidResource, err)
+		}
+
+		if header.Name == checkpointArchiveMetadataEntry {
+			checkpoint = &k2dtypes.ContainerCheckpoint{}
+			err = json.NewDecoder(tarReader).Decode(checkpoint)
+			if err != nil {
+				return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("%w: unable to decode checkpoint metadata: %s", adaptererr.ErrInvalidResource, err)
+			}
+			continue
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		err = extractCheckpointArchiveFile(importPath, header, tarReader)
+		if err != nil {
+			return k2dtypes.ContainerCheckpoint{}, err
+		}
+	}
+
+	if checkpoint == nil {
+		return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("%w: the archive does not contain the metadata of a checkpoint", adaptererr.ErrInvalidResource)
+	}
+
+	err = validateCheckpointName(checkpoint.Name)
+	if err != nil {
+		return k2dtypes.ContainerCheckpoint{}, err
+	}
+
+	_, err = os.Stat(adapter.checkpointMetadataPath(checkpoint.Name))
+	if err == nil {
+		return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("%w: checkpoint %s", adaptererr.ErrResourceAlreadyExists, checkpoint.Name)
+	}
+
+	err = os.RemoveAll(adapter.checkpointDataPath(checkpoint.Name))
+	if err != nil {
+		return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("unable to remove the files of a previous checkpoint: %w", err)
+	}
+
+	err = os.Rename(importPath, adapter.checkpointDataPath(checkpoint.Name))
+	if err != nil {
+		return k2dtypes.ContainerCheckpoint{}, fmt.Errorf("unable to store checkpoint files: %w", err)
+	}
+
+	err = adapter.storeCheckpointMetadata(*checkpoint)
+	if err != nil {
+		os.RemoveAll(adapter.checkpointDataPath(checkpoint.Name))
+		return k2dtypes.ContainerCheckpoint{}, err
+	}
+
+	return *checkpoint, nil
+}
+
+// extractCheckpointArchiveFile writes a file of a checkpoint archive inside the import directory.
+// Entries escaping the import directory are rejected.
+func extractCheckpointArchiveFile(importPath string, header *tar.Header, r io.Reader) error {
+	if !strings.HasPrefix(header.Name, checkpointArchiveDataPrefix) {
+		return nil
+	}
+
+	relativePath := path.Clean(strings.TrimPrefix(header.Name, checkpointArchiveDataPrefix))
+	if path.IsAbs(relativePath) || relativePath == "." || relativePath == ".." || strings.HasPrefix(relativePath, "../") {
+		return fmt.Errorf("%w: invalid checkpoint file path %s", adaptererr.ErrInvalidResource, header.Name)
+	}
+
+	filePath := filepath.Join(importPath, filepath.FromSlash(relativePath))
+
+	err := filesystem.CreateDir(filepath.Dir(filePath))
+	if err != nil {
+		return fmt.Errorf("unable to create directory: %w", err)
+	}
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, header.FileInfo().Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("unable to create checkpoint file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, r)
+	if err != nil {
+		return fmt.Errorf("unable to write checkpoint file: %w", err)
+	}
+
+	return nil
+}
+
+func (adapter *KubeDockerAdapter) storeCheckpointMetadata(checkpoint k2dtypes.ContainerCheckpoint) error {
+	metadata, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("unable to marshal checkpoint metadata: %w", err)
+	}
+
+	err = os.WriteFile(adapter.checkpointMetadataPath(checkpoint.Name), metadata, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write checkpoint metadata: %w", err)
+	}
+
+	return nil
+}
+
+// validateCheckpointName ensures that a checkpoint name is a valid DNS subdomain, which also prevents path traversals
+// as the name is used to build the path of the checkpoint files.
+func validateCheckpointName(checkpointName string) error {
+	errs := validation.IsDNS1123Subdomain(checkpointName)
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: invalid checkpoint name %s: %s", adaptererr.ErrInvalidResource, checkpointName, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
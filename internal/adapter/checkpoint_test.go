@@ -0,0 +1,107 @@
+package adapter
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+)
+
+func TestExportImportContainerCheckpoint(t *testing.T) {
+	source := &KubeDockerAdapter{checkpointsPath: t.TempDir()}
+
+	checkpoint := k2dtypes.ContainerCheckpoint{
+		Name:              "web-checkpoint",
+		Namespace:         "default",
+		PodName:           "web",
+		Image:             "nginx:latest",
+		CreationTimestamp: time.Now().UTC().Truncate(time.Second),
+	}
+
+	err := source.storeCheckpointMetadata(checkpoint)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = os.MkdirAll(path.Join(source.checkpointDataPath(checkpoint.Name), "criu.work"), 0700)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = os.WriteFile(path.Join(source.checkpointDataPath(checkpoint.Name), "pages-1.img"), []byte("memory"), 0600)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	archive := &bytes.Buffer{}
+	err = source.ExportContainerCheckpoint(checkpoint.Name, archive)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	target := &KubeDockerAdapter{checkpointsPath: t.TempDir()}
+
+	imported, err := target.ImportContainerCheckpoint(bytes.NewReader(archive.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if imported != checkpoint {
+		t.Errorf("expected the imported checkpoint to be %+v, got %+v", checkpoint, imported)
+	}
+
+	content, err := os.ReadFile(path.Join(target.checkpointDataPath(checkpoint.Name), "pages-1.img"))
+	if err != nil || string(content) != "memory" {
+		t.Errorf("expected the checkpoint files to be imported, got %q: %v", content, err)
+	}
+
+	_, err = target.ImportContainerCheckpoint(bytes.NewReader(archive.Bytes()))
+	if !errors.Is(err, adaptererr.ErrResourceAlreadyExists) {
+		t.Errorf("expected an already exists error, got %v", err)
+	}
+
+	entries, err := os.ReadDir(target.checkpointsPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(entries) != 2 {
+		t.Errorf("expected the import directories to be removed, got %d entries", len(entries))
+	}
+}
+
+func TestImportContainerCheckpointInvalidArchive(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries map[string]string
+	}{
+		{name: "missing metadata", entries: map[string]string{"checkpoint/pages-1.img": "memory"}},
+		{name: "invalid name", entries: map[string]string{"checkpoint.json": `{"name":"../web"}`}},
+		{name: "path traversal", entries: map[string]string{"checkpoint.json": `{"name":"web"}`, "checkpoint/../../web.json": "{}"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			archive := &bytes.Buffer{}
+			tarWriter := tar.NewWriter(archive)
+			for name, content := range test.entries {
+				tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg})
+				tarWriter.Write([]byte(content))
+			}
+			tarWriter.Close()
+
+			adapter := &KubeDockerAdapter{checkpointsPath: t.TempDir()}
+
+			_, err := adapter.ImportContainerCheckpoint(archive)
+			if !errors.Is(err, adaptererr.ErrInvalidResource) {
+				t.Errorf("expected an invalid resource error, got %v", err)
+			}
+		})
+	}
+}
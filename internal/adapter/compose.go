@@ -0,0 +1,267 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	dockerfilters "github.com/docker/docker/api/types/filters"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ImportComposeProject exposes the services of a Docker Compose project running on the host as deployments
+// and services of a namespace named after the project, relying on the labels set by Docker Compose on the containers
+// (see ComposeProjectLabelKey and ComposeServiceLabelKey). The namespace is created if it does not exist.
+//
+// Each Compose service is imported as follows:
+//   - A deployment named after the service is created from the configuration of its container, which is adopted
+//     and replaced by the deployment container (see AdoptContainer).
+//   - When the container exposes ports, a ClusterIP service named after the Compose service is created so that
+//     the other workloads can keep reaching it using the name of the Compose service.
+//
+// The Compose services running more than one container and the containers already managed by k2d are skipped.
+// A failure to import a service does not stop the import, the error is added to the report.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - project: The name of the Docker Compose project.
+//
+// Returns:
+// - A report summarizing the import.
+// - An ErrResourceNotFound error if no container belongs to the project, an ErrInvalidResource error if the project name
+// cannot be used as a namespace name, or an error if the namespace cannot be created.
+func (adapter *KubeDockerAdapter) ImportComposeProject(ctx context.Context, project string) (k2dtypes.ComposeImportReport, error) {
+	report := k2dtypes.ComposeImportReport{
+		Namespace:   sanitizeResourceName(project),
+		Deployments: []string{},
+		Services:    []string{},
+	}
+
+	if errs := validation.IsDNS1123Label(report.Namespace); len(errs) > 0 {
+		return report, fmt.Errorf("%w: invalid namespace name %s: %s", adaptererr.ErrInvalidResource, report.Namespace, strings.Join(errs, ", "))
+	}
+
+	containers, err := adapter.cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: dockerfilters.NewArgs(dockerfilters.Arg("label", fmt.Sprintf("%s=%s", k2dtypes.ComposeProjectLabelKey, project))),
+	})
+	if err != nil {
+		return report, fmt.Errorf("unable to list containers: %w", err)
+	}
+
+	if len(containers) == 0 {
+		return report, fmt.Errorf("%w: no container found for compose project %s", adaptererr.ErrResourceNotFound, project)
+	}
+
+	err = adapter.ensureComposeNamespace(ctx, report.Namespace)
+	if err != nil {
+		return report, err
+	}
+
+	composeServices := map[string][]types.Container{}
+	for _, container := range containers {
+		serviceName := container.Labels[k2dtypes.ComposeServiceLabelKey]
+		composeServices[serviceName] = append(composeServices[serviceName], container)
+	}
+
+	serviceNames := make([]string, 0, len(composeServices))
+	for serviceName := range composeServices {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+
+	services := []*corev1.Service{}
+	for _, serviceName := range serviceNames {
+		serviceContainers := composeServices[serviceName]
+
+		if len(serviceContainers) > 1 {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("%s: scaled services are not supported (%d containers)", serviceName, len(serviceContainers)))
+			continue
+		}
+
+		if serviceContainers[0].Labels[k2dtypes.NamespaceNameLabelKey] != "" {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("%s: the container is already managed by k2d", serviceName))
+			continue
+		}
+
+		service, err := adapter.importComposeService(ctx, project, report.Namespace, serviceName, serviceContainers[0].ID)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %s", serviceName, err))
+			continue
+		}
+
+		report.Deployments = append(report.Deployments, sanitizeResourceName(serviceName))
+		if service != nil {
+			services = append(services, service)
+		}
+	}
+
+	// The services are created once all the deployments exist, as they are applied to the deployment containers
+	for _, service := range services {
+		err := adapter.CreateContainerFromService(ctx, service)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("service %s: %s", service.Name, err))
+			continue
+		}
+
+		report.Services = append(report.Services, service.Name)
+	}
+
+	adapter.logger.Infow("compose project imported",
+		"project", project,
+		"namespace", report.Namespace,
+		"deployments", len(report.Deployments),
+		"services", len(report.Services),
+		"skipped", len(report.Skipped),
+		"errors", len(report.Errors),
+	)
+
+	return report, nil
+}
+
+// ensureComposeNamespace creates the namespace a Compose project is imported into if it does not exist.
+func (adapter *KubeDockerAdapter) ensureComposeNamespace(ctx context.Context, namespaceName string) error {
+	_, err := adapter.getNetwork(ctx, naming.BuildNetworkName(namespaceName))
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		return fmt.Errorf("unable to check for namespace existence: %w", err)
+	}
+
+	err = adapter.CreateNetworkFromNamespace(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        namespaceName,
+			Annotations: map[string]string{},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create namespace %s: %w", namespaceName, err)
+	}
+
+	return nil
+}
+
+// importComposeService replaces the container of a Compose service with a deployment container and returns
+// the service exposing the ports of the container, nil when the container does not expose any port.
+func (adapter *KubeDockerAdapter) importComposeService(ctx context.Context, project, namespace, serviceName, containerID string) (*corev1.Service, error) {
+	containerDetails, err := adapter.getContainer(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if containerDetails == nil {
+		return nil, fmt.Errorf("%w: container %s", adaptererr.ErrResourceNotFound, containerID)
+	}
+
+	name := sanitizeResourceName(serviceName)
+	selector := map[string]string{
+		"app.kubernetes.io/instance": fmt.Sprintf("%s-%s", sanitizeResourceName(project), name),
+	}
+
+	podSpec := corev1.PodSpec{}
+	internalPodSpec := adapter.converter.ConvertContainerToPodSpec(name, *containerDetails)
+	err = adapter.ConvertK8SResource(&internalPodSpec, &podSpec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert internal pod spec to versioned pod spec: %w", err)
+	}
+
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":    name,
+				"app.kubernetes.io/part-of": project,
+			},
+			Annotations: map[string]string{
+				k2dtypes.AdoptedContainerAnnotationKey: strings.TrimPrefix(containerDetails.Name, "/"),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app.kubernetes.io/instance": selector["app.kubernetes.io/instance"],
+						"app.kubernetes.io/name":     name,
+						"app.kubernetes.io/part-of":  project,
+					},
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+
+	err = adapter.replaceAdoptedContainer(ctx, containerDetails, func() error {
+		return adapter.CreateContainerFromDeployment(ctx, deployment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(containerDetails.Config.ExposedPorts) == 0 {
+		return nil, nil
+	}
+
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":    name,
+				"app.kubernetes.io/part-of": project,
+			},
+			Annotations: map[string]string{},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: selector,
+		},
+	}
+
+	for port := range containerDetails.Config.ExposedPorts {
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+			Name:       fmt.Sprintf("%s-%d", port.Proto(), port.Int()),
+			Port:       int32(port.Int()),
+			TargetPort: intstr.FromInt(port.Int()),
+			Protocol:   corev1.Protocol(strings.ToUpper(port.Proto())),
+		})
+	}
+
+	sort.Slice(service.Spec.Ports, func(i, j int) bool {
+		return service.Spec.Ports[i].Name < service.Spec.Ports[j].Name
+	})
+
+	serviceData, err := json.Marshal(service)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal service: %w", err)
+	}
+	service.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = string(serviceData)
+
+	return service, nil
+}
@@ -10,7 +10,16 @@ import (
 	"k8s.io/kubernetes/pkg/apis/core"
 )
 
+// CreateConfigMap stores a configmap. Its owner references are persisted in its labels so that the configmap
+// can be garbage collected when its owner is deleted (see DeleteDependents).
 func (adapter *KubeDockerAdapter) CreateConfigMap(configMap *corev1.ConfigMap) error {
+	configMap = configMap.DeepCopy()
+
+	err := persistOwnerReferences(&configMap.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
 	return adapter.configMapStore.StoreConfigMap(configMap)
 }
 
@@ -34,6 +43,7 @@ func (adapter *KubeDockerAdapter) GetConfigMap(configMapName, namespace string)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get configmap: %w", err)
 	}
+	restoreOwnerReferences(&configMap.ObjectMeta)
 
 	versionedConfigMap := corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
@@ -93,5 +103,14 @@ func (adapter *KubeDockerAdapter) ListSystemConfigMaps() (corev1.ConfigMapList,
 }
 
 func (adapter *KubeDockerAdapter) listConfigMaps(namespace string) (core.ConfigMapList, error) {
-	return adapter.configMapStore.GetConfigMaps(namespace)
+	configMapList, err := adapter.configMapStore.GetConfigMaps(namespace)
+	if err != nil {
+		return core.ConfigMapList{}, err
+	}
+
+	for i := range configMapList.Items {
+		restoreOwnerReferences(&configMapList.Items[i].ObjectMeta)
+	}
+
+	return configMapList, nil
 }
@@ -0,0 +1,90 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+)
+
+const (
+	// ContainerStartedAction is the action of a ContainerLifecycleEvent sent when a container has started
+	ContainerStartedAction = "start"
+	// ContainerDiedAction is the action of a ContainerLifecycleEvent sent when a container has exited
+	ContainerDiedAction = "die"
+	// ContainerOOMAction is the action of a ContainerLifecycleEvent sent when a container ran out of memory
+	ContainerOOMAction = "oom"
+	// ContainerDestroyedAction is the action of a ContainerLifecycleEvent sent when a container was removed
+	ContainerDestroyedAction = "destroy"
+)
+
+// ContainerLifecycleEvent describes a change of state of the container of a pod managed by k2d.
+type ContainerLifecycleEvent struct {
+	// Action is the Docker action associated with the event (start, die, oom or destroy)
+	Action string
+	// PodName is the name of the pod associated with the container
+	PodName string
+	// Namespace is the namespace of the pod associated with the container
+	Namespace string
+	// ContainerID is the ID of the container
+	ContainerID string
+	// ExitCode is the exit code of the container, only set for the die action
+	ExitCode int
+	// Time is the time at which the event occurred
+	Time time.Time
+}
+
+// WatchContainerLifecycleEvents streams the lifecycle events (start, die, oom and destroy) of the containers
+// of the pods managed by k2d and calls the handler for each event. The containers created outside of k2d are ignored.
+//
+// The function blocks until the context is cancelled or the event stream fails (e.g. the Docker daemon restarted),
+// the caller is responsible for watching the events again in the latter case.
+//
+// Parameters:
+// - ctx: The context used to stop watching the events.
+// - handler: The function called for each event.
+//
+// Returns:
+// - An error if the event stream fails, nil if the context is cancelled.
+func (adapter *KubeDockerAdapter) WatchContainerLifecycleEvents(ctx context.Context, handler func(ContainerLifecycleEvent)) error {
+	eventFilters := filters.NewArgs(
+		filters.Arg("type", string(events.ContainerEventType)),
+		filters.Arg("label", k2dtypes.WorkloadNameLabelKey),
+		filters.Arg("event", ContainerStartedAction),
+		filters.Arg("event", ContainerDiedAction),
+		filters.Arg("event", ContainerOOMAction),
+		filters.Arg("event", ContainerDestroyedAction),
+	)
+
+	messages, errs := adapter.cli.Events(ctx, types.EventsOptions{Filters: eventFilters})
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("unable to watch container events: %w", err)
+		case message := <-messages:
+			event := ContainerLifecycleEvent{
+				Action:      message.Action,
+				PodName:     message.Actor.Attributes[k2dtypes.WorkloadNameLabelKey],
+				Namespace:   message.Actor.Attributes[k2dtypes.NamespaceNameLabelKey],
+				ContainerID: message.Actor.ID,
+				Time:        time.Unix(0, message.TimeNano),
+			}
+
+			if message.Action == ContainerDiedAction {
+				event.ExitCode, _ = strconv.Atoi(message.Actor.Attributes["exitCode"])
+			}
+
+			handler(event)
+		}
+	}
+}
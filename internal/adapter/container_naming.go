@@ -0,0 +1,98 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/portainer/k2d/internal/adapter/filters"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+)
+
+// RepairContainerNames ensures that the containers of the workloads managed by k2d follow the container naming scheme
+// (see naming.ConfigureContainerNaming). It is meant to be called on startup, before any operation is processed.
+//   - A temporary container (see naming.TemporaryContainerNameSuffix) left behind by an interrupted replacement is renamed
+//     when the container it was meant to replace is gone, and removed otherwise as the replaced container is kept.
+//   - A container named using another naming scheme (e.g. after a change of the prefix) is renamed.
+//
+// A container that cannot be renamed because its expected name is used by another container is reported and left untouched.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+//
+// Returns:
+// - An error if the containers cannot be listed.
+func (adapter *KubeDockerAdapter) RepairContainerNames(ctx context.Context) error {
+	containers, err := adapter.cli.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filters.AllNamespaces()})
+	if err != nil {
+		return fmt.Errorf("unable to list containers: %w", err)
+	}
+
+	existingNames := map[string]struct{}{}
+	for _, container := range containers {
+		for _, name := range container.Names {
+			existingNames[strings.TrimPrefix(name, "/")] = struct{}{}
+		}
+	}
+
+	for _, container := range containers {
+		workloadName := container.Labels[k2dtypes.WorkloadNameLabelKey]
+		namespace := container.Labels[k2dtypes.NamespaceNameLabelKey]
+		if workloadName == "" || namespace == "" || len(container.Names) == 0 {
+			continue
+		}
+
+		name := strings.TrimPrefix(container.Names[0], "/")
+		expectedName := naming.BuildContainerName(workloadName, namespace)
+		if name == expectedName {
+			continue
+		}
+
+		_, expectedNameExists := existingNames[expectedName]
+		isTemporary := strings.HasSuffix(name, naming.TemporaryContainerNameSuffix)
+
+		switch {
+		case isTemporary && expectedNameExists:
+			adapter.logger.Warnw("removing temporary container left behind by an interrupted container replacement",
+				"container", name,
+				"replaced_container", expectedName,
+			)
+
+			err := adapter.cli.ContainerRemove(ctx, container.ID, types.ContainerRemoveOptions{Force: true})
+			if err != nil {
+				adapter.logger.Errorw("unable to remove temporary container",
+					"container", name,
+					"error", err,
+				)
+				continue
+			}
+			adapter.deleteContainerPayloads(container.ID)
+			delete(existingNames, name)
+		case expectedNameExists:
+			adapter.logger.Warnw("unable to rename container, its expected name is used by another container",
+				"container", name,
+				"expected_name", expectedName,
+			)
+		default:
+			adapter.logger.Infow("renaming container to follow the container naming scheme",
+				"container", name,
+				"expected_name", expectedName,
+			)
+
+			err := adapter.cli.ContainerRename(ctx, container.ID, expectedName)
+			if err != nil {
+				adapter.logger.Errorw("unable to rename container",
+					"container", name,
+					"error", err,
+				)
+				continue
+			}
+			delete(existingNames, name)
+			existingNames[expectedName] = struct{}{}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,388 @@
+package adapter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/portainer/k2d/internal/adapter/converter"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/filters"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// containerPayloadDataKeys associates each container label holding a configuration (payload) with the key
+// used to store the payload in the system configmap of the container.
+var containerPayloadDataKeys = map[string]string{
+	k2dtypes.LastAppliedConfigLabelKey:        "last-applied-configuration",
+	k2dtypes.PodLastAppliedConfigLabelKey:     "pod-last-applied-configuration",
+	k2dtypes.ServiceLastAppliedConfigLabelKey: "service-last-applied-configuration",
+}
+
+// payloadReferenceSuffix is appended to the key of a payload updated in place (see updateContainerPayloads) to store
+// the reference held by the container label, which no longer matches the digest of the payload.
+const payloadReferenceSuffix = "-reference"
+
+// containerPayloadCache is an in-memory cache of the payloads of the containers, keyed by container ID.
+// The payloads of a container only change when its configuration is updated in place (see updateContainerPayloads),
+// which updates the cache, the cache entries are therefore only removed when the container is removed.
+// It avoids reading the store backend each time a container is converted to a resource (e.g. when listing pods),
+// which is expensive with the volume store backend.
+type containerPayloadCache struct {
+	mu       sync.RWMutex
+	payloads map[string]map[string]string
+}
+
+func newContainerPayloadCache() *containerPayloadCache {
+	return &containerPayloadCache{
+		payloads: map[string]map[string]string{},
+	}
+}
+
+func (cache *containerPayloadCache) get(containerID string) (map[string]string, bool) {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	payloads, found := cache.payloads[containerID]
+	return payloads, found
+}
+
+func (cache *containerPayloadCache) set(containerID string, payloads map[string]string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.payloads[containerID] = payloads
+}
+
+func (cache *containerPayloadCache) delete(containerID string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	delete(cache.payloads, containerID)
+}
+
+// buildPayloadReference returns the reference stored in a container label in place of a payload.
+func buildPayloadReference(payload string) string {
+	digest := sha256.Sum256([]byte(payload))
+	return k2dtypes.PayloadReferencePrefix + hex.EncodeToString(digest[:])
+}
+
+// isPayloadReference returns true if the value of a container label is a reference to a payload stored in the store backend.
+func isPayloadReference(value string) bool {
+	return strings.HasPrefix(value, k2dtypes.PayloadReferencePrefix)
+}
+
+// hasLegacyPayloads returns true if the labels of a container contain payloads instead of references to payloads.
+func hasLegacyPayloads(labels map[string]string) bool {
+	for labelKey := range containerPayloadDataKeys {
+		if labels[labelKey] != "" && !isPayloadReference(labels[labelKey]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// createContainer creates a container from the specified configuration. The configurations stored in the labels
+// of the container (last applied configuration, pod spec and service definition) can exceed the size limits of the labels
+// and expose sensitive data through docker inspect. They are stored in a system configmap associated with the ID of the container
+// and the labels only hold a reference to them (see resolveContainerLabels).
+// The labels of the specified configuration are not modified.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - cfg: The configuration of the container.
+// - containerName: The name of the container.
+//
+// Returns:
+// - The response of the Docker API.
+// - An error if the container cannot be created or if its payloads cannot be stored. The container is removed in the latter case.
+func (adapter *KubeDockerAdapter) createContainer(ctx context.Context, cfg converter.ContainerConfiguration, containerName string) (container.CreateResponse, error) {
+	containerConfig := *cfg.ContainerConfig
+	containerConfig.Labels = map[string]string{}
+	payloads := map[string]string{}
+
+	for key, value := range cfg.ContainerConfig.Labels {
+		if _, isPayload := containerPayloadDataKeys[key]; isPayload && value != "" && !isPayloadReference(value) {
+			payloads[key] = value
+			value = buildPayloadReference(value)
+		}
+
+		containerConfig.Labels[key] = value
+	}
+
+	containerCreateResponse, err := adapter.cli.ContainerCreate(ctx,
+		&containerConfig,
+		cfg.HostConfig,
+		cfg.NetworkConfig,
+		nil,
+		containerName,
+	)
+	if err != nil {
+		return container.CreateResponse{}, err
+	}
+
+	if len(payloads) == 0 {
+		return containerCreateResponse, nil
+	}
+
+	err = adapter.storeContainerPayloads(containerCreateResponse.ID, payloads)
+	if err != nil {
+		removeErr := adapter.cli.ContainerRemove(ctx, containerCreateResponse.ID, types.ContainerRemoveOptions{Force: true})
+		if removeErr != nil {
+			adapter.logger.Warnf("unable to remove container %s after failing to store its configuration: %s", containerName, removeErr)
+		}
+
+		return container.CreateResponse{}, fmt.Errorf("unable to store container configuration: %w", err)
+	}
+
+	return containerCreateResponse, nil
+}
+
+// storeContainerPayloads stores the payloads of a container in its system configmap and in the cache.
+func (adapter *KubeDockerAdapter) storeContainerPayloads(containerID string, payloads map[string]string) error {
+	data := map[string]string{}
+	for key, payload := range payloads {
+		if strings.HasSuffix(key, payloadReferenceSuffix) {
+			data[containerPayloadDataKeys[strings.TrimSuffix(key, payloadReferenceSuffix)]+payloadReferenceSuffix] = payload
+			continue
+		}
+
+		data[containerPayloadDataKeys[key]] = payload
+	}
+
+	err := adapter.CreateSystemConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: naming.BuildContainerPayloadSystemConfigMapName(containerID),
+		},
+		Data: data,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to store container system configmap: %w", err)
+	}
+
+	adapter.containerPayloadCache.set(containerID, payloads)
+
+	return nil
+}
+
+// getContainerPayloads retrieves the payloads of a container from the cache or from its system configmap.
+// It returns an ErrResourceNotFound error if no payload is stored for the container.
+func (adapter *KubeDockerAdapter) getContainerPayloads(containerID string) (map[string]string, error) {
+	payloads, found := adapter.containerPayloadCache.get(containerID)
+	if found {
+		return payloads, nil
+	}
+
+	configMap, err := adapter.configMapStore.GetConfigMap(naming.BuildContainerPayloadSystemConfigMapName(containerID), k2dtypes.K2DNamespaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	payloads = map[string]string{}
+	for labelKey, dataKey := range containerPayloadDataKeys {
+		if payload, exists := configMap.Data[dataKey]; exists {
+			payloads[labelKey] = payload
+		}
+
+		if reference, exists := configMap.Data[dataKey+payloadReferenceSuffix]; exists {
+			payloads[labelKey+payloadReferenceSuffix] = reference
+		}
+	}
+
+	adapter.containerPayloadCache.set(containerID, payloads)
+
+	return payloads, nil
+}
+
+// updateContainerPayloads replaces the payloads of a container whose configuration has been updated in place
+// (e.g. when its resources are updated, see resizeContainer). The labels of a container cannot be updated,
+// the references held by the labels are therefore stored alongside the updated payloads so that they can still be resolved.
+// It returns an error if one of the labels does not hold a reference to a payload.
+//
+// Parameters:
+// - containerID: The ID of the container.
+// - labels: The labels of the container, holding the references to its payloads.
+// - updatedPayloads: The updated payloads, keyed by label.
+//
+// Returns:
+// - An error if the payloads cannot be stored.
+func (adapter *KubeDockerAdapter) updateContainerPayloads(containerID string, labels map[string]string, updatedPayloads map[string]string) error {
+	existingPayloads, err := adapter.getContainerPayloads(containerID)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the configuration of the container: %w", err)
+	}
+
+	payloads := map[string]string{}
+	for key, payload := range existingPayloads {
+		payloads[key] = payload
+	}
+
+	for labelKey, payload := range updatedPayloads {
+		if !isPayloadReference(labels[labelKey]) {
+			return fmt.Errorf("label %s does not reference a stored configuration", labelKey)
+		}
+
+		payloads[labelKey] = payload
+		payloads[labelKey+payloadReferenceSuffix] = labels[labelKey]
+	}
+
+	return adapter.storeContainerPayloads(containerID, payloads)
+}
+
+// resolveContainerLabels returns a copy of the labels of a container where the references to payloads are replaced
+// with the payloads stored in the store backend. Labels holding payloads directly (containers created before the payloads
+// were moved to the store backend) are returned as is.
+// A reference that cannot be resolved is removed from the returned labels and a warning is logged, the container is then
+// handled as if the payload was missing.
+func (adapter *KubeDockerAdapter) resolveContainerLabels(containerID string, labels map[string]string) map[string]string {
+	resolvedLabels := make(map[string]string, len(labels))
+	for key, value := range labels {
+		resolvedLabels[key] = value
+	}
+
+	var payloads map[string]string
+	for labelKey := range containerPayloadDataKeys {
+		reference := resolvedLabels[labelKey]
+		if !isPayloadReference(reference) {
+			continue
+		}
+
+		if payloads == nil {
+			var err error
+			payloads, err = adapter.getContainerPayloads(containerID)
+			if err != nil {
+				adapter.logger.Warnf("unable to retrieve the configuration of container %s: %s", containerID, err)
+				payloads = map[string]string{}
+			}
+		}
+
+		payload, found := payloads[labelKey]
+		if !found || (buildPayloadReference(payload) != reference && payloads[labelKey+payloadReferenceSuffix] != reference) {
+			adapter.logger.Warnf("unable to resolve label %s of container %s, the referenced configuration is missing or outdated", labelKey, containerID)
+			delete(resolvedLabels, labelKey)
+			continue
+		}
+
+		resolvedLabels[labelKey] = payload
+	}
+
+	return resolvedLabels
+}
+
+// deleteContainerPayloads removes the payloads of a container once the container has been removed.
+// Failures are only logged as the orphaned payloads are pruned when k2d starts (see PruneContainerPayloads).
+func (adapter *KubeDockerAdapter) deleteContainerPayloads(containerID string) {
+	adapter.containerPayloadCache.delete(containerID)
+
+	err := adapter.DeleteSystemConfigMap(naming.BuildContainerPayloadSystemConfigMapName(containerID))
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		adapter.logger.Warnf("unable to delete the configuration of container %s: %s", containerID, err)
+	}
+}
+
+// PruneContainerPayloads removes the payloads stored in the store backend for containers that no longer exist.
+// It is meant to be called on startup, the payloads of a container can be left behind when k2d is stopped
+// while a container is being removed (see deleteContainerPayloads).
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+//
+// Returns:
+// - An error if the containers or the stored payloads cannot be listed.
+func (adapter *KubeDockerAdapter) PruneContainerPayloads(ctx context.Context) error {
+	containers, err := adapter.cli.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filters.AllNamespaces()})
+	if err != nil {
+		return fmt.Errorf("unable to list containers: %w", err)
+	}
+
+	existingContainers := map[string]struct{}{}
+	for _, container := range containers {
+		existingContainers[container.ID] = struct{}{}
+	}
+
+	configMaps, err := adapter.ListSystemConfigMaps()
+	if err != nil {
+		return fmt.Errorf("unable to list system configmaps: %w", err)
+	}
+
+	for _, configMap := range configMaps.Items {
+		if !strings.HasPrefix(configMap.Name, naming.ContainerPayloadSystemConfigMapPrefix) {
+			continue
+		}
+
+		containerID := strings.TrimPrefix(configMap.Name, naming.ContainerPayloadSystemConfigMapPrefix)
+		if _, exists := existingContainers[containerID]; !exists {
+			adapter.deleteContainerPayloads(containerID)
+		}
+	}
+
+	return nil
+}
+
+// migrateLegacyContainerPayloads moves the payloads stored in the labels of the containers created by previous versions of k2d
+// into the store backend (see SchemaMigrations).
+// The labels of a container cannot be updated, the running containers holding payloads in their labels are therefore
+// re-created using their current configuration. Stopped containers are left untouched, their labels are still supported
+// and they are migrated the next time they are re-created.
+// A failure to migrate a container does not prevent the other containers from being migrated, the error is logged.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+//
+// Returns:
+// - An error if the containers cannot be listed.
+func (adapter *KubeDockerAdapter) migrateLegacyContainerPayloads(ctx context.Context) error {
+	containers, err := adapter.cli.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filters.AllNamespaces()})
+	if err != nil {
+		return fmt.Errorf("unable to list containers: %w", err)
+	}
+
+	for _, container := range containers {
+		if container.State != "running" || !hasLegacyPayloads(container.Labels) {
+			continue
+		}
+
+		adapter.logger.Infow("moving the configuration stored in the container labels to the store backend, the container will be re-created",
+			"container", strings.TrimPrefix(container.Names[0], "/"),
+		)
+
+		err := adapter.migrateContainerPayloads(ctx, container.ID)
+		if err != nil {
+			adapter.logger.Errorw("unable to migrate container configuration",
+				"container", strings.TrimPrefix(container.Names[0], "/"),
+				"error", err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// migrateContainerPayloads re-creates a container using its exact current configuration so that the payloads
+// held by its labels are moved to the store backend.
+func (adapter *KubeDockerAdapter) migrateContainerPayloads(ctx context.Context, containerID string) error {
+	containerDetails, err := adapter.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("unable to inspect container: %w", err)
+	}
+
+	return adapter.reCreateContainerWithNewConfiguration(ctx, containerID, converter.ContainerConfiguration{
+		ContainerName:   strings.TrimPrefix(containerDetails.Name, "/"),
+		ContainerConfig: containerDetails.Config,
+		HostConfig:      containerDetails.HostConfig,
+		NetworkConfig: &network.NetworkingConfig{
+			EndpointsConfig: withoutNetworkPolicyNetworks(containerDetails.NetworkSettings.Networks, containerDetails.Config.Labels),
+		},
+	})
+}
@@ -0,0 +1,331 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// The restart backoff of the Docker restart manager: the delay starts at 100ms and doubles after each restart,
+	// up to one minute. It is reset when the container ran for at least 10 seconds before exiting.
+	restartBackOffInitial     = 100 * time.Millisecond
+	restartBackOffMax         = time.Minute
+	restartBackOffResetWindow = 10 * time.Second
+
+	// crashLogLines is the number of log lines of a crashed container included in the Warning events
+	crashLogLines = 10
+	// crashLogMaxLength is the maximum length of the log lines included in the Warning events
+	crashLogMaxLength = 1024
+)
+
+type (
+	// containerRestartHistory is the exit and restart history of the container of a pod
+	containerRestartHistory struct {
+		containerID    string
+		restartCount   int32
+		backOff        time.Duration
+		startedAt      time.Time
+		lastStartedAt  time.Time
+		lastFinishedAt time.Time
+		lastExitCode   int
+		lastReason     string
+		lastMessage    string
+		oomKilled      bool
+	}
+
+	// containerRestartTracker tracks the exit and restart history of the containers of the pods managed by k2d
+	// from the Docker lifecycle events, see TrackContainerLifecycleEvent.
+	// The history is kept in memory and is lost when k2d restarts.
+	containerRestartTracker struct {
+		mu        sync.RWMutex
+		histories map[string]*containerRestartHistory
+	}
+)
+
+func newContainerRestartTracker() *containerRestartTracker {
+	return &containerRestartTracker{
+		histories: map[string]*containerRestartHistory{},
+	}
+}
+
+func containerRestartHistoryKey(podName, namespace string) string {
+	return namespace + "/" + podName
+}
+
+// record updates the history of the container of a pod with a lifecycle event and returns a copy of the updated history.
+// The history is reset when the container is re-created (e.g. when the pod is updated), as the kubelet does
+// when a pod is replaced. It returns false when the event removed the history.
+func (tracker *containerRestartTracker) record(event ContainerLifecycleEvent) (containerRestartHistory, bool) {
+	key := containerRestartHistoryKey(event.PodName, event.Namespace)
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if event.Action == ContainerDestroyedAction {
+		delete(tracker.histories, key)
+		return containerRestartHistory{}, false
+	}
+
+	history, exists := tracker.histories[key]
+	if !exists || history.containerID != event.ContainerID {
+		history = &containerRestartHistory{containerID: event.ContainerID}
+		tracker.histories[key] = history
+	}
+
+	switch event.Action {
+	case ContainerStartedAction:
+		if !history.lastFinishedAt.IsZero() {
+			history.restartCount++
+		}
+		history.startedAt = event.Time
+	case ContainerOOMAction:
+		history.oomKilled = true
+	case ContainerDiedAction:
+		history.backOff = nextRestartBackOff(history.backOff, event.Time.Sub(history.startedAt), history.startedAt.IsZero())
+		history.lastStartedAt = history.startedAt
+		history.lastFinishedAt = event.Time
+		history.lastExitCode = event.ExitCode
+		history.lastReason = getTerminationReason(event.ExitCode, history.oomKilled)
+		history.lastMessage = ""
+		history.oomKilled = false
+	}
+
+	return *history, true
+}
+
+// setLastMessage sets the termination message of the last termination of the container of a pod.
+func (tracker *containerRestartTracker) setLastMessage(podName, namespace, containerID, message string) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	history, exists := tracker.histories[containerRestartHistoryKey(podName, namespace)]
+	if exists && history.containerID == containerID {
+		history.lastMessage = message
+	}
+}
+
+// get returns a copy of the history of the container of a pod.
+func (tracker *containerRestartTracker) get(podName, namespace, containerID string) (containerRestartHistory, bool) {
+	tracker.mu.RLock()
+	defer tracker.mu.RUnlock()
+
+	history, exists := tracker.histories[containerRestartHistoryKey(podName, namespace)]
+	if !exists || history.containerID != containerID {
+		return containerRestartHistory{}, false
+	}
+
+	return *history, true
+}
+
+// nextRestartBackOff returns the delay applied by the Docker restart manager before restarting a container
+// that exited after running for the specified duration. It mirrors the restart manager of Docker so that the
+// backoff timer reported in the pod status matches the actual delay: k2d relies on the Docker restart policies
+// and does not apply the backoff of the kubelet (10s doubling up to 5 minutes).
+func nextRestartBackOff(previous, executionDuration time.Duration, unknownStart bool) time.Duration {
+	if unknownStart || executionDuration >= restartBackOffResetWindow {
+		previous = 0
+	}
+
+	next := previous * 2
+	if previous == 0 {
+		next = restartBackOffInitial
+	}
+
+	if next > restartBackOffMax {
+		next = restartBackOffMax
+	}
+
+	return next
+}
+
+// getTerminationReason returns the reason of the termination of a container, as reported by the kubelet.
+func getTerminationReason(exitCode int, oomKilled bool) string {
+	switch {
+	case oomKilled:
+		return "OOMKilled"
+	case exitCode == 0:
+		return "Completed"
+	default:
+		return "Error"
+	}
+}
+
+// TrackContainerLifecycleEvent updates the restart history of the container of a pod from a lifecycle event
+// (see WatchContainerLifecycleEvents). The history is used to report the restart count, the last termination state
+// and the CrashLoopBackOff state in the pod status.
+// When a container exits, its termination message is captured (see captureTerminationMessage).
+// When a container exits with a non-zero exit code, a Warning event including the exit code and the last lines of
+// the logs of the container is recorded: BackOff when the restart policy restarts the container, Failed otherwise.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - event: The lifecycle event of the container.
+func (adapter *KubeDockerAdapter) TrackContainerLifecycleEvent(ctx context.Context, event ContainerLifecycleEvent) {
+	history, tracked := adapter.restartTracker.record(event)
+	if !tracked || event.Action != ContainerDiedAction {
+		return
+	}
+
+	containerDetails, err := adapter.cli.ContainerInspect(ctx, event.ContainerID)
+	if err != nil {
+		adapter.logger.Warnw("unable to inspect exited container",
+			"pod", event.PodName,
+			"namespace", event.Namespace,
+			"error", err,
+		)
+		return
+	}
+
+	terminationMessage := adapter.captureTerminationMessage(ctx, containerDetails, event.ExitCode)
+	adapter.restartTracker.setLastMessage(event.PodName, event.Namespace, event.ContainerID, terminationMessage)
+
+	if event.ExitCode == 0 {
+		return
+	}
+
+	reason := "Failed"
+	message := fmt.Sprintf("Container %s exited with code %d (%s)", event.PodName, event.ExitCode, history.lastReason)
+
+	if restartPolicy := containerDetails.HostConfig.RestartPolicy.Name; restartPolicy == "always" || restartPolicy == "on-failure" {
+		reason = "BackOff"
+		message = fmt.Sprintf("Back-off %s restarting failed container %s, exited with code %d (%s)", history.backOff, event.PodName, event.ExitCode, history.lastReason)
+	}
+
+	logs, err := adapter.getLastContainerLogLines(ctx, event.ContainerID, containerDetails.Config.Tty, crashLogLines, crashLogMaxLength)
+	if err != nil {
+		adapter.logger.Debugw("unable to retrieve the logs of the crashed container",
+			"pod", event.PodName,
+			"namespace", event.Namespace,
+			"error", err,
+		)
+	} else if logs != "" {
+		message = fmt.Sprintf("%s, last log lines:\n%s", message, logs)
+	}
+
+	adapter.RecordEvent(core.ObjectReference{Kind: "Pod", Name: event.PodName, Namespace: event.Namespace}, core.EventTypeWarning, reason, message)
+}
+
+// captureTerminationMessage returns the termination message of an exited container, following the termination message
+// policy of its pod spec: the content of the termination message file, or the last lines of the logs of the container
+// when the file is empty, the container failed and the policy is FallbackToLogsOnError.
+func (adapter *KubeDockerAdapter) captureTerminationMessage(ctx context.Context, containerDetails types.ContainerJSON, exitCode int) string {
+	message, err := adapter.readTerminationMessage(containerDetails.Name)
+	if err != nil {
+		adapter.logger.Debugf("unable to read termination message of container %s: %s", containerDetails.Name, err)
+	}
+
+	if message != "" || exitCode == 0 {
+		return message
+	}
+
+	podSpec, err := getPodSpecFromLabels(adapter.resolveContainerLabels(containerDetails.ID, containerDetails.Config.Labels))
+	if err != nil || getTerminationMessagePolicy(podSpec) != core.TerminationMessageFallbackToLogsOnError {
+		return ""
+	}
+
+	message, err = adapter.getLastContainerLogLines(ctx, containerDetails.ID, containerDetails.Config.Tty, terminationMessageLogsMaxLines, terminationMessageLogsMaxLength)
+	if err != nil {
+		adapter.logger.Debugf("unable to retrieve the logs of container %s: %s", containerDetails.Name, err)
+	}
+
+	return message
+}
+
+// getLastContainerLogLines returns the last lines of the logs of a container, truncated to their last maxLength bytes.
+func (adapter *KubeDockerAdapter) getLastContainerLogLines(ctx context.Context, containerID string, tty bool, lines, maxLength int) (string, error) {
+	logs, err := adapter.cli.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       fmt.Sprint(lines),
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve container logs: %w", err)
+	}
+	defer logs.Close()
+
+	var output bytes.Buffer
+	if tty {
+		_, err = io.Copy(&output, logs)
+	} else {
+		_, err = stdcopy.StdCopy(&output, &output, logs)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read container logs: %w", err)
+	}
+
+	lastLines := strings.TrimSpace(output.String())
+	if len(lastLines) > maxLength {
+		lastLines = lastLines[len(lastLines)-maxLength:]
+	}
+
+	return lastLines, nil
+}
+
+// setPodRestartStatus sets the restart count, the last termination state and the start time of the container of a pod
+// from the restart history of its container. When the container is waiting to be restarted by the Docker restart policy,
+// the CrashLoopBackOff message includes the backoff timer, following the format of the kubelet.
+// The termination message of an exited container is set as well, it is read from the termination message file
+// when the container exited before k2d started.
+//
+// Parameters:
+// - pod: The pod converted from the container.
+// - containerID: The ID of the container.
+func (adapter *KubeDockerAdapter) setPodRestartStatus(pod *core.Pod, containerID string) {
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return
+	}
+
+	status := &pod.Status.ContainerStatuses[0]
+	history, exists := adapter.restartTracker.get(pod.Name, pod.Namespace, containerID)
+
+	if status.State.Terminated != nil {
+		if exists {
+			status.State.Terminated.Message = history.lastMessage
+			status.State.Terminated.FinishedAt = metav1.NewTime(history.lastFinishedAt)
+		} else {
+			message, err := adapter.readTerminationMessage(naming.BuildContainerName(pod.Name, pod.Namespace))
+			if err != nil {
+				adapter.logger.Debugf("unable to read termination message of pod %s: %s", pod.Name, err)
+			}
+			status.State.Terminated.Message = message
+		}
+	}
+
+	if !exists {
+		return
+	}
+
+	status.RestartCount = history.restartCount
+
+	if status.State.Running != nil && !history.startedAt.IsZero() {
+		status.State.Running.StartedAt = metav1.NewTime(history.startedAt)
+	}
+
+	if history.lastFinishedAt.IsZero() || status.State.Terminated != nil {
+		return
+	}
+
+	status.LastTerminationState.Terminated = &core.ContainerStateTerminated{
+		ExitCode:    int32(history.lastExitCode),
+		Reason:      history.lastReason,
+		Message:     history.lastMessage,
+		StartedAt:   metav1.NewTime(history.lastStartedAt),
+		FinishedAt:  metav1.NewTime(history.lastFinishedAt),
+		ContainerID: "docker://" + containerID,
+	}
+
+	if status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff" {
+		status.State.Waiting.Message = fmt.Sprintf("back-off %s restarting failed container=%s pod=%s_%s", history.backOff, status.Name, pod.Name, pod.Namespace)
+	}
+}
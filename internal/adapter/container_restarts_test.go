@@ -0,0 +1,170 @@
+package adapter
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestNextRestartBackOff(t *testing.T) {
+	tests := []struct {
+		name              string
+		previous          time.Duration
+		executionDuration time.Duration
+		unknownStart      bool
+		expected          time.Duration
+	}{
+		{name: "first crash", previous: 0, executionDuration: time.Second, expected: 100 * time.Millisecond},
+		{name: "doubles after each crash", previous: 400 * time.Millisecond, executionDuration: time.Second, expected: 800 * time.Millisecond},
+		{name: "capped to one minute", previous: 40 * time.Second, executionDuration: time.Second, expected: time.Minute},
+		{name: "reset after a long run", previous: 40 * time.Second, executionDuration: 10 * time.Second, expected: 100 * time.Millisecond},
+		{name: "reset when the start is unknown", previous: 40 * time.Second, unknownStart: true, expected: 100 * time.Millisecond},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backOff := nextRestartBackOff(test.previous, test.executionDuration, test.unknownStart)
+			if backOff != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, backOff)
+			}
+		})
+	}
+}
+
+func TestContainerRestartTrackerRecord(t *testing.T) {
+	tracker := newContainerRestartTracker()
+	start := time.Now()
+
+	event := func(action, containerID string, exitCode int, offset time.Duration) ContainerLifecycleEvent {
+		return ContainerLifecycleEvent{
+			Action:      action,
+			PodName:     "web",
+			Namespace:   "default",
+			ContainerID: containerID,
+			ExitCode:    exitCode,
+			Time:        start.Add(offset),
+		}
+	}
+
+	tracker.record(event(ContainerStartedAction, "a", 0, 0))
+	tracker.record(event(ContainerDiedAction, "a", 1, time.Second))
+	tracker.record(event(ContainerStartedAction, "a", 0, 2*time.Second))
+	tracker.record(event(ContainerOOMAction, "a", 0, 3*time.Second))
+	history, _ := tracker.record(event(ContainerDiedAction, "a", 137, 3*time.Second))
+
+	if history.restartCount != 1 {
+		t.Errorf("expected 1 restart, got %d", history.restartCount)
+	}
+
+	if history.lastExitCode != 137 || history.lastReason != "OOMKilled" {
+		t.Errorf("expected the last termination to be OOMKilled with exit code 137, got %s with exit code %d", history.lastReason, history.lastExitCode)
+	}
+
+	if history.backOff != 200*time.Millisecond {
+		t.Errorf("expected a 200ms backoff, got %s", history.backOff)
+	}
+
+	history, _ = tracker.record(event(ContainerStartedAction, "b", 0, 4*time.Second))
+	if history.restartCount != 0 || !history.lastFinishedAt.IsZero() {
+		t.Errorf("expected the history to be reset when the container is re-created, got %+v", history)
+	}
+
+	tracker.record(event(ContainerDestroyedAction, "b", 0, 5*time.Second))
+	if _, exists := tracker.get("web", "default", "b"); exists {
+		t.Error("expected the history to be removed when the container is destroyed")
+	}
+}
+
+func TestSetPodRestartStatus(t *testing.T) {
+	adapter := &KubeDockerAdapter{restartTracker: newContainerRestartTracker()}
+	start := time.Now()
+
+	adapter.restartTracker.record(ContainerLifecycleEvent{Action: ContainerStartedAction, PodName: "web", Namespace: "default", ContainerID: "a", Time: start})
+	adapter.restartTracker.record(ContainerLifecycleEvent{Action: ContainerDiedAction, PodName: "web", Namespace: "default", ContainerID: "a", ExitCode: 1, Time: start.Add(time.Second)})
+
+	pod := core.Pod{}
+	pod.Name = "web"
+	pod.Namespace = "default"
+	pod.Status.ContainerStatuses = []core.ContainerStatus{
+		{
+			Name: "web",
+			State: core.ContainerState{
+				Waiting: &core.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+			},
+		},
+	}
+
+	adapter.setPodRestartStatus(&pod, "a")
+
+	status := pod.Status.ContainerStatuses[0]
+	if status.LastTerminationState.Terminated == nil || status.LastTerminationState.Terminated.ExitCode != 1 {
+		t.Fatalf("expected the last termination state to be set, got %+v", status.LastTerminationState)
+	}
+
+	expectedMessage := "back-off 100ms restarting failed container=web pod=web_default"
+	if status.State.Waiting.Message != expectedMessage {
+		t.Errorf("expected message %q, got %q", expectedMessage, status.State.Waiting.Message)
+	}
+}
+
+func TestSetPodRestartStatusTerminationMessage(t *testing.T) {
+	adapter := &KubeDockerAdapter{restartTracker: newContainerRestartTracker(), terminationMessagesPath: t.TempDir()}
+	start := time.Now()
+
+	adapter.restartTracker.record(ContainerLifecycleEvent{Action: ContainerStartedAction, PodName: "job", Namespace: "default", ContainerID: "a", Time: start})
+	adapter.restartTracker.record(ContainerLifecycleEvent{Action: ContainerDiedAction, PodName: "job", Namespace: "default", ContainerID: "a", ExitCode: 2, Time: start.Add(time.Second)})
+	adapter.restartTracker.setLastMessage("job", "default", "a", "missing input file")
+
+	pod := core.Pod{}
+	pod.Name = "job"
+	pod.Namespace = "default"
+	pod.Status.ContainerStatuses = []core.ContainerStatus{
+		{
+			Name: "job",
+			State: core.ContainerState{
+				Terminated: &core.ContainerStateTerminated{ExitCode: 2, Reason: "Error"},
+			},
+		},
+	}
+
+	adapter.setPodRestartStatus(&pod, "a")
+
+	terminated := pod.Status.ContainerStatuses[0].State.Terminated
+	if terminated.Message != "missing input file" {
+		t.Errorf("expected the termination message to be set, got %q", terminated.Message)
+	}
+
+	if !terminated.FinishedAt.Time.Equal(start.Add(time.Second)) {
+		t.Errorf("expected the finish time to be set from the history, got %s", terminated.FinishedAt)
+	}
+
+	if pod.Status.ContainerStatuses[0].LastTerminationState.Terminated != nil {
+		t.Error("expected no last termination state for a terminated container")
+	}
+}
+
+func TestReadTerminationMessageIsTruncated(t *testing.T) {
+	adapter := &KubeDockerAdapter{terminationMessagesPath: t.TempDir()}
+
+	err := os.WriteFile(adapter.terminationMessageFilePath("/default-job"), []byte(strings.Repeat("x", terminationMessageMaxLength+10)), 0600)
+	if err != nil {
+		t.Fatalf("unable to write termination message file: %s", err)
+	}
+
+	message, err := adapter.readTerminationMessage("/default-job")
+	if err != nil {
+		t.Fatalf("unable to read termination message: %s", err)
+	}
+
+	if len(message) != terminationMessageMaxLength {
+		t.Errorf("expected the message to be truncated to %d bytes, got %d", terminationMessageMaxLength, len(message))
+	}
+
+	message, err = adapter.readTerminationMessage("/default-missing")
+	if err != nil || message != "" {
+		t.Errorf("expected an empty message when the file does not exist, got %q (%v)", message, err)
+	}
+}
@@ -0,0 +1,111 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/portainer/k2d/internal/adapter/converter"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// snapshotContainer captures the full configuration of a container so that it can be re-created as is if its replacement
+// fails (see rollbackContainer). Unlike buildContainerConfigurationFromExistingContainer, which only keeps the options
+// that k2d does not compute again, the whole configuration is kept, including the port bindings and the command.
+// The references to the configurations stored in the store backend are resolved in the labels of the snapshot
+// as the configurations of the container are removed with it.
+func (adapter *KubeDockerAdapter) snapshotContainer(ctx context.Context, containerID string) (converter.ContainerConfiguration, error) {
+	containerDetails, err := adapter.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return converter.ContainerConfiguration{}, fmt.Errorf("unable to inspect container: %w", err)
+	}
+
+	containerConfig := *containerDetails.Config
+	containerConfig.Labels = adapter.resolveContainerLabels(containerDetails.ID, containerDetails.Config.Labels)
+
+	hostConfig := *containerDetails.HostConfig
+
+	return converter.ContainerConfiguration{
+		ContainerName:   strings.TrimPrefix(containerDetails.Name, "/"),
+		ContainerConfig: &containerConfig,
+		HostConfig:      &hostConfig,
+		NetworkConfig: &network.NetworkingConfig{
+			EndpointsConfig: withoutNetworkPolicyNetworks(containerDetails.NetworkSettings.Networks, containerDetails.Config.Labels),
+		},
+	}, nil
+}
+
+// waitForContainerExit waits for a container to exit during the specified window.
+// It returns true and the exit code of the container if it exited during the window, false if it is still running at the end of the window.
+func (adapter *KubeDockerAdapter) waitForContainerExit(ctx context.Context, containerID string, window time.Duration) (bool, int64, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, window)
+	defer cancel()
+
+	statusCh, errCh := adapter.cli.ContainerWait(waitCtx, containerID, container.WaitConditionNotRunning)
+	select {
+	case status := <-statusCh:
+		return true, status.StatusCode, nil
+	case err := <-errCh:
+		if errors.Is(waitCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			return false, 0, nil
+		}
+		return false, 0, fmt.Errorf("unable to wait for container: %w", err)
+	}
+}
+
+// rollbackContainer replaces a container that failed after its re-creation with a container created from the snapshot
+// of the container it replaced (see snapshotContainer). The logs of the failed container are retained so that they can
+// be retrieved through the "previous" option of the pod logs API, and a Warning event is recorded against the pod.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - failedContainerID: The ID of the container that failed.
+// - snapshot: The configuration of the replaced container.
+// - reason: A description of the failure, used in the event and the returned error.
+//
+// Returns:
+// - An error describing the failure once the previous configuration has been restored.
+// - An error if the previous configuration cannot be restored.
+func (adapter *KubeDockerAdapter) rollbackContainer(ctx context.Context, failedContainerID string, snapshot converter.ContainerConfiguration, reason string) error {
+	err := adapter.retainContainerLogs(ctx, failedContainerID, snapshot.ContainerName)
+	if err != nil {
+		adapter.logger.Warnf("unable to retain logs of container %s: %s", snapshot.ContainerName, err)
+	}
+
+	err = adapter.cli.ContainerRemove(ctx, failedContainerID, types.ContainerRemoveOptions{Force: true})
+	if err != nil {
+		return fmt.Errorf("%s, unable to remove the new container to restore the previous configuration: %w", reason, err)
+	}
+	adapter.deleteContainerPayloads(failedContainerID)
+
+	containerCreateResponse, err := adapter.createContainer(ctx, snapshot, snapshot.ContainerName)
+	if err != nil {
+		return fmt.Errorf("%s, unable to re-create the container with the previous configuration: %w", reason, err)
+	}
+
+	err = adapter.cli.ContainerStart(ctx, containerCreateResponse.ID, types.ContainerStartOptions{})
+	if err != nil {
+		return fmt.Errorf("%s, unable to start the container with the previous configuration: %w", reason, err)
+	}
+
+	adapter.recordRollbackEvent(snapshot.ContainerConfig.Labels, reason)
+
+	return fmt.Errorf("%s, the previous configuration was restored", reason)
+}
+
+// recordRollbackEvent records a Warning event against the pod of a container whose configuration was rolled back.
+func (adapter *KubeDockerAdapter) recordRollbackEvent(labels map[string]string, reason string) {
+	podName := labels[k2dtypes.WorkloadNameLabelKey]
+	if podName == "" {
+		return
+	}
+
+	adapter.RecordEvent(core.ObjectReference{Kind: "Pod", Name: podName, Namespace: labels[k2dtypes.NamespaceNameLabelKey]},
+		core.EventTypeWarning, "RolledBack", fmt.Sprintf("Container re-creation rolled back: %s", reason))
+}
@@ -5,8 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
-	"os"
+	"sort"
 	"strings"
 
 	"github.com/docker/distribution/reference"
@@ -26,32 +25,64 @@ import (
 	"k8s.io/kubernetes/pkg/apis/core"
 )
 
-// findContainerMatchingSelector iterates over a slice of Container types, looking for a Container
-// whose Labels contain a key-value pair specified in the provided selector map.
-// The function returns a pointer to the first matching Container it finds.
-// If no matching Container is found, the function returns nil.
-func findContainerMatchingSelector(containers []types.Container, selector map[string]string) *types.Container {
+// findContainersMatchingSelector iterates over a slice of Container types, looking for the Containers
+// of the specified namespace whose Labels contain all the key-value pairs specified in the provided selector map,
+// as a Kubernetes service selects its pods.
+// The matching Containers are sorted by name, except for the Container currently publishing the ports of the
+// specified service (see ServiceNameLabelKey) which is returned first so that the primary backend of a service is stable.
+// If no matching Container is found or if the selector is empty, the function returns an empty slice.
+func findContainersMatchingSelector(containers []types.Container, namespace, serviceName string, selector map[string]string) []types.Container {
+	matchingContainers := []types.Container{}
+	if len(selector) == 0 {
+		return matchingContainers
+	}
+
 	for _, container := range containers {
-		for key, value := range container.Labels {
-			if maputils.ContainsKeyValuePairInMap(key, value, selector) {
-				return &container
-			}
+		if container.Labels[k2dtypes.NamespaceNameLabelKey] != namespace {
+			continue
+		}
+
+		if maputils.ContainsAllKeyValuePairsInMap(selector, container.Labels) {
+			matchingContainers = append(matchingContainers, container)
 		}
 	}
 
-	return nil
+	sort.SliceStable(matchingContainers, func(i, j int) bool {
+		iPrimary := matchingContainers[i].Labels[k2dtypes.ServiceNameLabelKey] == serviceName
+		jPrimary := matchingContainers[j].Labels[k2dtypes.ServiceNameLabelKey] == serviceName
+		if iPrimary != jPrimary {
+			return iPrimary
+		}
+
+		return containerListName(matchingContainers[i]) < containerListName(matchingContainers[j])
+	})
+
+	return matchingContainers
+}
+
+// containerListName returns the name of a container without its leading slash, or its ID when it has no name.
+func containerListName(container types.Container) string {
+	if len(container.Names) == 0 {
+		return container.ID
+	}
+
+	return strings.TrimPrefix(container.Names[0], "/")
 }
 
 // reCreateContainerWithNewConfiguration replaces an existing Docker container with a new one that has an updated configuration.
 // The function performs the following steps:
-// 1. Stops the existing container by its containerID.
-// 2. Creates a new container using the newContainerCfg with a temporary name.
-// 3. Starts the newly created container.
-// 4. If the new container starts successfully, removes the old container.
-// 5. Renames the new container to have the original name as specified in newContainerCfg.
+// 1. Snapshots the full configuration of the existing container when a rollback window is configured (see snapshotContainer).
+// 2. Stops the existing container by its containerID.
+// 3. Creates a new container using the newContainerCfg with a temporary name.
+// 4. Starts the newly created container.
+// 5. If the new container starts successfully, removes the old container.
+// 6. Renames the new container to have the original name as specified in newContainerCfg.
+// 7. When a rollback window is configured, waits for the window to elapse. If the new container exits during the window,
+// it is replaced by a container re-created from the snapshot (see rollbackContainer).
 // If any of the steps fail:
 // - When failing to create a new container, the function attempts to restart the old container.
-// - When failing to start the new container, the old container is removed, and the new container is left in a created state and renamed to the original name for inspection.
+// - When failing to start the new container and a rollback window is configured, the new container is removed and the old container is restarted.
+// - When failing to start the new container otherwise, the old container is removed, and the new container is left in a created state and renamed to the original name for inspection.
 //
 // Parameters:
 // - ctx: Context used for cancellation or timeouts.
@@ -59,26 +90,28 @@ func findContainerMatchingSelector(containers []types.Container, selector map[st
 // - newContainerCfg: The new container configuration.
 //
 // Returns:
-// - An error if any of the steps fail.
+// - An error if any of the steps fail, including when the configuration was rolled back.
 func (adapter *KubeDockerAdapter) reCreateContainerWithNewConfiguration(ctx context.Context, containerID string, newContainerCfg converter.ContainerConfiguration) error {
 	// Define temporary container name
-	tempContainerName := newContainerCfg.ContainerName + "_temp"
+	tempContainerName := naming.BuildTemporaryContainerName(newContainerCfg.ContainerName)
+
+	var snapshot converter.ContainerConfiguration
+	if adapter.rollbackWindow > 0 {
+		var err error
+		snapshot, err = adapter.snapshotContainer(ctx, containerID)
+		if err != nil {
+			return fmt.Errorf("unable to snapshot existing container: %w", err)
+		}
+	}
 
-	// Stop the existing container
-	containerStopTimeout := 3
-	err := adapter.cli.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &containerStopTimeout})
+	// Stop the existing container, running the preStop hook and honoring the termination grace period
+	err := adapter.stopContainer(ctx, containerID, newContainerCfg.ContainerConfig.Labels)
 	if err != nil {
 		return fmt.Errorf("unable to stop existing container: %w", err)
 	}
 
 	// Create a new container
-	containerCreateResponse, err := adapter.cli.ContainerCreate(ctx,
-		newContainerCfg.ContainerConfig,
-		newContainerCfg.HostConfig,
-		newContainerCfg.NetworkConfig,
-		nil,
-		tempContainerName,
-	)
+	containerCreateResponse, err := adapter.createContainer(ctx, newContainerCfg, tempContainerName)
 	if err != nil {
 		// Attempt to start the old container again in case of failure
 		if startErr := adapter.cli.ContainerStart(ctx, containerID, types.ContainerStartOptions{}); startErr != nil {
@@ -89,6 +122,22 @@ func (adapter *KubeDockerAdapter) reCreateContainerWithNewConfiguration(ctx cont
 
 	// Start the new container
 	err = adapter.cli.ContainerStart(ctx, containerCreateResponse.ID, types.ContainerStartOptions{})
+	if err != nil && adapter.rollbackWindow > 0 {
+		// The old container is still there, it is started again in place of the new container
+		removeErr := adapter.cli.ContainerRemove(ctx, containerCreateResponse.ID, types.ContainerRemoveOptions{Force: true})
+		if removeErr != nil {
+			return fmt.Errorf("unable to remove the new container after failed start: %w", removeErr)
+		}
+		adapter.deleteContainerPayloads(containerCreateResponse.ID)
+
+		if startErr := adapter.cli.ContainerStart(ctx, containerID, types.ContainerStartOptions{}); startErr != nil {
+			return fmt.Errorf("unable to start the old container after failed new container start: %w", startErr)
+		}
+
+		reason := fmt.Sprintf("unable to start container: %s", err)
+		adapter.recordRollbackEvent(snapshot.ContainerConfig.Labels, reason)
+		return fmt.Errorf("%s, the previous configuration was restored", reason)
+	}
 	if err != nil {
 		// If the new container fails to start, remove the old container and leave the new container in the created state.
 		// This way, the container can be inspected to see what went wrong.
@@ -96,6 +145,7 @@ func (adapter *KubeDockerAdapter) reCreateContainerWithNewConfiguration(ctx cont
 		if removeErr != nil {
 			return fmt.Errorf("unable to remove the old container after failed start: %w", removeErr)
 		}
+		adapter.deleteContainerPayloads(containerID)
 
 		// Rename the new container to the original name
 		// This way, the container with the known name can be inspected to see what went wrong.
@@ -112,6 +162,7 @@ func (adapter *KubeDockerAdapter) reCreateContainerWithNewConfiguration(ctx cont
 	if err != nil {
 		return fmt.Errorf("unable to remove old container: %w", err)
 	}
+	adapter.deleteContainerPayloads(containerID)
 
 	// Rename the new container to the original name
 	err = adapter.cli.ContainerRename(ctx, containerCreateResponse.ID, newContainerCfg.ContainerName)
@@ -119,10 +170,37 @@ func (adapter *KubeDockerAdapter) reCreateContainerWithNewConfiguration(ctx cont
 		return fmt.Errorf("unable to rename container: %w", err)
 	}
 
+	podSpec, err := getPodSpecFromLabels(newContainerCfg.ContainerConfig.Labels)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve pod spec: %w", err)
+	}
+
+	if podSpec != nil {
+		err = adapter.runPostStartHook(ctx, containerCreateResponse.ID, *podSpec)
+		if err != nil {
+			return err
+		}
+	}
+
+	if adapter.rollbackWindow == 0 {
+		return nil
+	}
+
+	exited, exitCode, err := adapter.waitForContainerExit(ctx, containerCreateResponse.ID, adapter.rollbackWindow)
+	if err != nil {
+		return err
+	}
+
+	if exited {
+		return adapter.rollbackContainer(ctx, containerCreateResponse.ID, snapshot,
+			fmt.Sprintf("container exited with code %d within the rollback window of %s", exitCode, adapter.rollbackWindow))
+	}
+
 	return nil
 }
 
 // buildContainerConfigurationFromExistingContainer builds a ContainerConfiguration from an existing Docker container.
+// The references to the configurations stored in the store backend are resolved in the labels of the returned configuration.
 // This function must be updated when adding support to new container configuration options.
 func (adapter *KubeDockerAdapter) buildContainerConfigurationFromExistingContainer(ctx context.Context, containerID string) (converter.ContainerConfiguration, error) {
 	containerDetails, err := adapter.cli.ContainerInspect(ctx, containerID)
@@ -134,7 +212,7 @@ func (adapter *KubeDockerAdapter) buildContainerConfigurationFromExistingContain
 		ContainerName: containerDetails.Name,
 		ContainerConfig: &container.Config{
 			Image:        containerDetails.Image,
-			Labels:       containerDetails.Config.Labels,
+			Labels:       adapter.resolveContainerLabels(containerDetails.ID, containerDetails.Config.Labels),
 			ExposedPorts: nat.PortSet{},
 			Env:          containerDetails.Config.Env,
 			User:         containerDetails.Config.User,
@@ -143,12 +221,23 @@ func (adapter *KubeDockerAdapter) buildContainerConfigurationFromExistingContain
 			PortBindings:  nat.PortMap{},
 			RestartPolicy: containerDetails.HostConfig.RestartPolicy,
 			Binds:         containerDetails.HostConfig.Binds,
+			DNS:           containerDetails.HostConfig.DNS,
+			DNSOptions:    containerDetails.HostConfig.DNSOptions,
+			DNSSearch:     containerDetails.HostConfig.DNSSearch,
 			ExtraHosts:    containerDetails.HostConfig.ExtraHosts,
 			Privileged:    containerDetails.HostConfig.Privileged,
 			Resources:     containerDetails.HostConfig.Resources,
+			ShmSize:       containerDetails.HostConfig.ShmSize,
+			Sysctls:       containerDetails.HostConfig.Sysctls,
+			Tmpfs:         containerDetails.HostConfig.Tmpfs,
+			UsernsMode:    containerDetails.HostConfig.UsernsMode,
+			CapAdd:        containerDetails.HostConfig.CapAdd,
+			CapDrop:       containerDetails.HostConfig.CapDrop,
+			SecurityOpt:   containerDetails.HostConfig.SecurityOpt,
+			NetworkMode:   containerDetails.HostConfig.NetworkMode,
 		},
 		NetworkConfig: &network.NetworkingConfig{
-			EndpointsConfig: containerDetails.NetworkSettings.Networks,
+			EndpointsConfig: withoutNetworkPolicyNetworks(containerDetails.NetworkSettings.Networks, containerDetails.Config.Labels),
 		},
 	}, nil
 }
@@ -156,6 +245,8 @@ func (adapter *KubeDockerAdapter) buildContainerConfigurationFromExistingContain
 // ContainerCreationOptions serves as a parameter object for container creation operations.
 // The struct encapsulates various attributes required for configuring a container, as described below:
 //
+//   - annotations: A map representing the annotations of the associated Pod. Some k2d specific annotations
+//     are used to configure Docker options that are not part of the PodSpec (e.g. device passthrough).
 //   - containerName: Specifies the name of the container to be created.
 //   - labels: A map representing key-value pairs of labels that will be attached to the container.
 //     These labels are useful for organizational and operational tasks like filtering and grouping.
@@ -165,12 +256,16 @@ func (adapter *KubeDockerAdapter) buildContainerConfigurationFromExistingContain
 //     This is used to ensure that the container is created in the correct network.
 //   - podSpec: Holds the corev1.PodSpec object representing the desired state of the associated Pod.
 //     This includes configurations like the container image, environment variables, and volume mounts.
+//   - rollingUpdate: Indicates that an existing container must be replaced using a rolling update,
+//     the new container being started before the existing one is removed (see rollContainer).
 type ContainerCreationOptions struct {
+	annotations              map[string]string
 	containerName            string
 	labels                   map[string]string
 	lastAppliedConfiguration string
 	namespace                string
 	podSpec                  corev1.PodSpec
+	rollingUpdate            bool
 }
 
 // getContainer inspects the specified container and returns its details in the form of a pointer to a types.ContainerJSON object.
@@ -204,13 +299,25 @@ func (adapter *KubeDockerAdapter) getContainer(ctx context.Context, containerNam
 //
 //  1. Initializes and updates container labels using the last applied configuration if provided.
 //  2. Converts the provided Kubernetes PodSpec into an internal PodSpec, which is then serialized to JSON.
-//     This serialized form is stored as a label on the Docker container for future reference.
-//  3. Constructs a Docker container configuration from the internal PodSpec.
+//     This serialized form is stored in the store backend and referenced in a label of the Docker container for future reference.
+//     When the pod is annotated with container.k2d.io/auto-host-ports=true, free host ports are allocated to the container
+//     ports without a host port beforehand (see allocateHostPorts).
+//  3. Constructs a Docker container configuration from the internal PodSpec and mounts a termination message
+//     file at the termination message path of the container (see setTerminationMessageBind).
 //  4. Checks for an existing Docker container with the same name:
 //     - If found with an identical last applied configuration, skips the update.
-//     - If found but but with a different last applied configuration, removes the existing container.
-//  5. Pulls the necessary Docker image using registry credentials from the Kubernetes PodSpec.
+//     - Otherwise, checks that the pod matches the node, that its host ports are free and that the node has enough capacity
+//     for its requests (see admitPodSpec).
+//     - If found but but with a different last applied configuration, gracefully stops (running the preStop hook)
+//     and removes the existing container.
+//     - When the resources of the containers are the only change, the existing container is updated in place
+//     instead (see resizeContainer).
+//     - When a rolling update is requested and the container can be rolled, the existing container is replaced
+//     by a new container started beforehand instead (see rollContainer).
+//  5. Pulls the necessary Docker image using registry credentials from the Kubernetes PodSpec. The progress of the pull
+//     is reported through events recorded against the pod.
 //  6. Creates and starts the Docker container.
+//  7. Runs the postStart hook of the container, if any.
 //
 // Parameters:
 // - ctx: The operational context within which the function runs. Used for timeouts and cancellation signals.
@@ -218,7 +325,7 @@ func (adapter *KubeDockerAdapter) getContainer(ctx context.Context, containerNam
 //   - containerName: Specifies the name of the Docker container to create.
 //   - labels: A map of labels to attach to the Docker container.
 //   - lastAppliedConfiguration: Stores the last configuration applied to the parent Kubernetes object.
-//     This is saved as a label on the Docker container.
+//     This is saved in the store backend and referenced in a label of the Docker container.
 //   - namespace: Used to determine the network in which the container should be created.
 //   - podSpec: The Kubernetes PodSpec that serves as the template for the Docker container.
 //
@@ -236,6 +343,13 @@ func (adapter *KubeDockerAdapter) createContainerFromPodSpec(ctx context.Context
 		return fmt.Errorf("unable to convert versioned pod spec to internal pod spec: %w", err)
 	}
 
+	if options.annotations[k2dtypes.AutoHostPortsAnnotationKey] == "true" {
+		err = adapter.allocateHostPorts(ctx, &internalPodSpec, naming.BuildContainerName(options.containerName, options.namespace))
+		if err != nil {
+			return err
+		}
+	}
+
 	internalPodSpecData, err := json.Marshal(internalPodSpec)
 	if err != nil {
 		return fmt.Errorf("unable to marshal internal pod spec: %w", err)
@@ -245,33 +359,66 @@ func (adapter *KubeDockerAdapter) createContainerFromPodSpec(ctx context.Context
 	options.labels[k2dtypes.WorkloadNameLabelKey] = options.containerName
 	options.labels[k2dtypes.NetworkNameLabelKey] = naming.BuildNetworkName(options.namespace)
 
-	containerCfg, err := adapter.converter.ConvertPodSpecToContainerConfiguration(internalPodSpec, options.namespace, options.labels)
+	containerCfg, err := adapter.converter.ConvertPodSpecToContainerConfiguration(internalPodSpec, options.namespace, options.labels, options.annotations)
 	if err != nil {
 		return fmt.Errorf("unable to build container configuration from pod spec: %w", err)
 	}
 	containerCfg.ContainerName = naming.BuildContainerName(options.containerName, options.namespace)
 
+	err = adapter.setTerminationMessageBind(&containerCfg, internalPodSpec)
+	if err != nil {
+		return err
+	}
+
 	existingContainer, err := adapter.getContainer(ctx, containerCfg.ContainerName)
 	if err != nil {
 		return fmt.Errorf("unable to inspect container: %w", err)
 	}
 
 	if existingContainer != nil {
+		existingContainer.Config.Labels = adapter.resolveContainerLabels(existingContainer.ID, existingContainer.Config.Labels)
+
 		if options.lastAppliedConfiguration == existingContainer.Config.Labels[k2dtypes.LastAppliedConfigLabelKey] {
 			adapter.logger.Infof("container with the name %s already exists with the same configuration. The update will be skipped", containerCfg.ContainerName)
 			return nil
 		}
+	}
+
+	err = adapter.admitPodSpec(ctx, internalPodSpec, options.containerName, options.namespace, existingContainer)
+	if err != nil {
+		return err
+	}
 
+	if existingContainer != nil {
 		adapter.logger.Infof("container with the name %s already exists with a different configuration. The container will be recreated", containerCfg.ContainerName)
 
 		if existingContainer.Config.Labels[k2dtypes.ServiceLastAppliedConfigLabelKey] != "" {
 			options.labels[k2dtypes.ServiceLastAppliedConfigLabelKey] = existingContainer.Config.Labels[k2dtypes.ServiceLastAppliedConfigLabelKey]
 		}
 
-		err := adapter.cli.ContainerRemove(ctx, existingContainer.ID, types.ContainerRemoveOptions{Force: true})
+		if adapter.resizeContainer(ctx, existingContainer, containerCfg, options) {
+			return nil
+		}
+
+		if options.rollingUpdate && canRollContainer(existingContainer, containerCfg) {
+			return adapter.rollContainer(ctx, existingContainer, containerCfg, internalPodSpec, options)
+		}
+
+		err := adapter.stopContainer(ctx, existingContainer.ID, existingContainer.Config.Labels)
+		if err != nil {
+			adapter.logger.Warnf("unable to gracefully stop container %s: %s", containerCfg.ContainerName, err)
+		}
+
+		err = adapter.retainContainerLogs(ctx, existingContainer.ID, containerCfg.ContainerName)
+		if err != nil {
+			adapter.logger.Warnf("unable to retain logs of container %s: %s", containerCfg.ContainerName, err)
+		}
+
+		err = adapter.cli.ContainerRemove(ctx, existingContainer.ID, types.ContainerRemoveOptions{Force: true})
 		if err != nil {
 			return fmt.Errorf("unable to remove container: %w", err)
 		}
+		adapter.deleteContainerPayloads(existingContainer.ID)
 	}
 
 	registryAuth, err := adapter.getRegistryCredentials(options.podSpec, options.namespace, containerCfg.ContainerConfig.Image)
@@ -279,37 +426,37 @@ func (adapter *KubeDockerAdapter) createContainerFromPodSpec(ctx context.Context
 		return fmt.Errorf("unable to get registry credentials: %w", err)
 	}
 
-	out, err := adapter.cli.ImagePull(ctx, containerCfg.ContainerConfig.Image, types.ImagePullOptions{
-		RegistryAuth: registryAuth,
+	err = adapter.pullImage(ctx, containerCfg.ContainerConfig.Image, registryAuth, &core.ObjectReference{
+		Kind:      "Pod",
+		Namespace: options.namespace,
+		Name:      options.containerName,
 	})
 	if err != nil {
-		return fmt.Errorf("unable to pull %s image: %w", containerCfg.ContainerConfig.Image, err)
+		return err
 	}
-	defer out.Close()
-
-	io.Copy(os.Stdout, out)
 
-	containerCreateResponse, err := adapter.cli.ContainerCreate(ctx,
-		containerCfg.ContainerConfig,
-		containerCfg.HostConfig,
-		containerCfg.NetworkConfig,
-		nil,
-		containerCfg.ContainerName,
-	)
+	containerCreateResponse, err := adapter.createContainer(ctx, containerCfg, containerCfg.ContainerName)
 	if err != nil {
 		return fmt.Errorf("unable to create container: %w", err)
 	}
 
-	return adapter.cli.ContainerStart(ctx, containerCreateResponse.ID, types.ContainerStartOptions{})
+	err = adapter.cli.ContainerStart(ctx, containerCreateResponse.ID, types.ContainerStartOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to start container: %w", err)
+	}
+
+	return adapter.runPostStartHook(ctx, containerCreateResponse.ID, internalPodSpec)
 }
 
 // DeleteContainer attempts to remove a Docker container based on its name and associated namespace.
 // The container name is fully qualified by appending the namespace to it using the buildContainerName function.
-// This function forcefully removes the container, regardless of whether it is running or not.
+// The container is gracefully stopped first (running the preStop hook and honoring the termination grace period
+// defined in the associated pod spec) and then forcefully removed.
 //
 // The function performs the following steps:
 // 1. Constructs the fully qualified container name by appending the namespace to the provided container name.
-// 2. Calls the Docker API's ContainerRemove method to forcefully remove the container.
+// 2. Gracefully stops the container if it exists.
+// 3. Calls the Docker API's ContainerRemove method to forcefully remove the container.
 //
 // If there is an error during the container removal process, a warning message will be logged.
 //
@@ -322,12 +469,46 @@ func (adapter *KubeDockerAdapter) createContainerFromPodSpec(ctx context.Context
 //   - This function does not return any value or error. Failures in container removal are only logged as warnings.
 //     This is because the container may not exist anymore, and the function should not fail in that case.
 func (adapter *KubeDockerAdapter) DeleteContainer(ctx context.Context, containerName, namespace string) {
+	// The definition of the workload is removed first so that the container is not re-created by the reconciliation loop
+	err := adapter.deleteWorkloadRecord(containerName, namespace)
+	if err != nil {
+		adapter.logger.Warnf("unable to delete workload definition: %s", err)
+	}
+
+	err = adapter.deleteDeploymentRevisions(containerName, namespace)
+	if err != nil {
+		adapter.logger.Warnf("unable to delete deployment revisions: %s", err)
+	}
+	adapter.DeletePendingPod(namespace, containerName)
+	adapter.deleteObjectMetadata("Pod", containerName, namespace)
+	adapter.deleteObjectMetadata("Deployment", containerName, namespace)
+
 	containerName = naming.BuildContainerName(containerName, namespace)
 
-	err := adapter.cli.ContainerRemove(ctx, containerName, types.ContainerRemoveOptions{Force: true})
+	existingContainer, err := adapter.getContainer(ctx, containerName)
+	if err != nil {
+		adapter.logger.Warnf("unable to inspect container: %s", err)
+	}
+
+	if existingContainer != nil && existingContainer.State != nil && existingContainer.State.Running {
+		err = adapter.stopContainer(ctx, existingContainer.ID, existingContainer.Config.Labels)
+		if err != nil {
+			adapter.logger.Warnf("unable to gracefully stop container: %s", err)
+		}
+	}
+
+	// The anonymous volumes of the container are removed with the container, the named volumes (persistent volumes) are preserved
+	err = adapter.cli.ContainerRemove(ctx, containerName, types.ContainerRemoveOptions{Force: true, RemoveVolumes: true})
 	if err != nil {
 		adapter.logger.Warnf("unable to remove container: %s", err)
+		return
 	}
+
+	if existingContainer != nil {
+		adapter.deleteContainerPayloads(existingContainer.ID)
+	}
+	adapter.removeTerminationMessage(containerName)
+	adapter.removePreviousContainerLogs(containerName)
 }
 
 // getRegistryCredentials attempts to retrieve the Docker registry credentials for a given image name
@@ -470,6 +651,10 @@ func (adapter *KubeDockerAdapter) DeployPortainerEdgeAgent(ctx context.Context,
 		return fmt.Errorf("unable to set service account token and CA cert: %w", err)
 	}
 
+	if err := adapter.converter.SetContainerLogConfig(hostConfig, nil); err != nil {
+		return fmt.Errorf("unable to set log configuration: %w", err)
+	}
+
 	networkName := naming.BuildNetworkName(k2dtypes.K2DNamespaceName)
 	networkConfig := &network.NetworkingConfig{
 		EndpointsConfig: map[string]*network.EndpointSettings{
@@ -477,13 +662,10 @@ func (adapter *KubeDockerAdapter) DeployPortainerEdgeAgent(ctx context.Context,
 		},
 	}
 
-	out, err := adapter.cli.ImagePull(ctx, containerConfig.Image, types.ImagePullOptions{})
+	err = adapter.pullImage(ctx, containerConfig.Image, "", nil)
 	if err != nil {
-		return fmt.Errorf("unable to pull %s image: %w", containerConfig.Image, err)
+		return err
 	}
-	defer out.Close()
-
-	io.Copy(os.Stdout, out)
 
 	_, err = adapter.cli.ContainerCreate(ctx, containerConfig, hostConfig, networkConfig, nil, "portainer-agent")
 
@@ -0,0 +1,67 @@
+package adapter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+)
+
+func TestFindContainersMatchingSelector(t *testing.T) {
+	containers := []types.Container{
+		{ID: "frontend", Names: []string{"/frontend"}, Labels: map[string]string{k2dtypes.NamespaceNameLabelKey: "default", "app": "shop", "tier": "frontend"}},
+		{ID: "backend-2", Names: []string{"/backend-2"}, Labels: map[string]string{k2dtypes.NamespaceNameLabelKey: "default", "app": "shop", "tier": "backend"}},
+		{ID: "backend-3", Names: []string{"/backend-3"}, Labels: map[string]string{k2dtypes.NamespaceNameLabelKey: "default", "app": "shop", "tier": "backend", k2dtypes.ServiceNameLabelKey: "api"}},
+		{ID: "backend-1", Names: []string{"/backend-1"}, Labels: map[string]string{k2dtypes.NamespaceNameLabelKey: "default", "app": "shop", "tier": "backend"}},
+		{ID: "other-namespace", Names: []string{"/other-namespace"}, Labels: map[string]string{k2dtypes.NamespaceNameLabelKey: "staging", "app": "api"}},
+	}
+
+	tests := []struct {
+		name        string
+		namespace   string
+		serviceName string
+		selector    map[string]string
+		expected    []string
+	}{
+		{name: "all the key/value pairs must match", namespace: "default", serviceName: "frontend", selector: map[string]string{"app": "shop", "tier": "frontend"}, expected: []string{"frontend"}},
+		{name: "the current primary backend is returned first", namespace: "default", serviceName: "api", selector: map[string]string{"app": "shop", "tier": "backend"}, expected: []string{"backend-3", "backend-1", "backend-2"}},
+		{name: "the containers are sorted by name without a primary backend", namespace: "default", serviceName: "backend", selector: map[string]string{"tier": "backend"}, expected: []string{"backend-1", "backend-2", "backend-3"}},
+		{name: "a partial match is not selected", namespace: "default", serviceName: "cache", selector: map[string]string{"app": "shop", "tier": "cache"}, expected: []string{}},
+		{name: "the containers of other namespaces are not selected", namespace: "default", serviceName: "api", selector: map[string]string{"app": "api"}, expected: []string{}},
+		{name: "an empty selector does not select any container", namespace: "default", serviceName: "api", selector: map[string]string{}, expected: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matchingContainers := findContainersMatchingSelector(containers, test.namespace, test.serviceName, test.selector)
+
+			ids := []string{}
+			for _, container := range matchingContainers {
+				ids = append(ids, container.ID)
+			}
+
+			if !reflect.DeepEqual(ids, test.expected) {
+				t.Errorf("expected containers %v, got %v", test.expected, ids)
+			}
+		})
+	}
+}
+
+func TestIsServiceBackend(t *testing.T) {
+	primary := types.Container{Labels: map[string]string{k2dtypes.ServiceNameLabelKey: "api"}}
+	additional := types.Container{Labels: map[string]string{k2dtypes.ServiceBackendLabelKey: "api"}}
+	unexposed := types.Container{Labels: map[string]string{}}
+
+	if !isServiceBackend(primary, "api") || !isServiceBackend(additional, "api") {
+		t.Error("expected the primary and the additional backends to be backends of the service")
+	}
+
+	if isServiceBackend(primary, "web") || isServiceBackend(additional, "web") {
+		t.Error("expected the backends not to be backends of another service")
+	}
+
+	if !isServiceBackend(additional, "") || isServiceBackend(unexposed, "") {
+		t.Error("expected only the exposed containers to be backends of any service")
+	}
+}
@@ -0,0 +1,135 @@
+package converter
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// ConvertContainerToPodSpec synthesizes a Kubernetes PodSpec from the configuration of a Docker container created outside of k2d,
+// so that the container can be re-created and managed through the Kubernetes API.
+//
+// Parameters:
+// - containerName: The name of the container in the PodSpec.
+// - containerDetails: The inspected Docker container.
+//
+// Behavior:
+//   - The image, entrypoint (command), command (args), working directory and environment variables are copied as is.
+//   - The published ports are converted to container ports with a host port.
+//   - The bind mounts and the volumes are converted to host path volumes using their location on the host.
+//     tmpfs mounts are not supported and are ignored.
+//   - The restart policy is converted to the closest pod restart policy (no: Never, on-failure: OnFailure, otherwise Always).
+//   - The memory and CPU limits are converted to resource limits.
+//   - The privileged mode is converted to a container security context.
+//
+// Returns:
+// - The synthesized PodSpec.
+func (converter *DockerAPIConverter) ConvertContainerToPodSpec(containerName string, containerDetails types.ContainerJSON) core.PodSpec {
+	config := containerDetails.Config
+	hostConfig := containerDetails.HostConfig
+
+	containerSpec := core.Container{
+		Name:       containerName,
+		Image:      config.Image,
+		Command:    config.Entrypoint,
+		Args:       config.Cmd,
+		WorkingDir: config.WorkingDir,
+	}
+
+	for _, env := range config.Env {
+		name, value, _ := strings.Cut(env, "=")
+		containerSpec.Env = append(containerSpec.Env, core.EnvVar{Name: name, Value: value})
+	}
+
+	containerSpec.Ports = convertPortBindingsToContainerPorts(hostConfig.PortBindings)
+
+	spec := core.PodSpec{
+		RestartPolicy: core.RestartPolicyAlways,
+	}
+
+	switch hostConfig.RestartPolicy.Name {
+	case "no", "":
+		spec.RestartPolicy = core.RestartPolicyNever
+	case "on-failure":
+		spec.RestartPolicy = core.RestartPolicyOnFailure
+	}
+
+	for i, containerMount := range containerDetails.Mounts {
+		if containerMount.Type != mount.TypeBind && containerMount.Type != mount.TypeVolume {
+			continue
+		}
+
+		volumeName := "volume-" + strconv.Itoa(i)
+		spec.Volumes = append(spec.Volumes, core.Volume{
+			Name: volumeName,
+			VolumeSource: core.VolumeSource{
+				HostPath: &core.HostPathVolumeSource{
+					Path: containerMount.Source,
+				},
+			},
+		})
+
+		containerSpec.VolumeMounts = append(containerSpec.VolumeMounts, core.VolumeMount{
+			Name:      volumeName,
+			MountPath: containerMount.Destination,
+			ReadOnly:  !containerMount.RW,
+		})
+	}
+
+	limits := core.ResourceList{}
+	if hostConfig.Memory > 0 {
+		limits[core.ResourceMemory] = *resource.NewQuantity(hostConfig.Memory, resource.BinarySI)
+	}
+	if hostConfig.NanoCPUs > 0 {
+		limits[core.ResourceCPU] = *resource.NewMilliQuantity(hostConfig.NanoCPUs/1000000, resource.DecimalSI)
+	}
+	if len(limits) > 0 {
+		containerSpec.Resources.Limits = limits
+	}
+
+	if hostConfig.Privileged {
+		privileged := true
+		containerSpec.SecurityContext = &core.SecurityContext{
+			Privileged: &privileged,
+		}
+	}
+
+	spec.Containers = []core.Container{containerSpec}
+
+	return spec
+}
+
+// convertPortBindingsToContainerPorts converts the published ports of a Docker container to container ports
+// sorted by container port. Only the first binding of each port is kept.
+func convertPortBindingsToContainerPorts(portBindings nat.PortMap) []core.ContainerPort {
+	containerPorts := []core.ContainerPort{}
+
+	for port, bindings := range portBindings {
+		if len(bindings) == 0 {
+			continue
+		}
+
+		hostPort, err := strconv.Atoi(bindings[0].HostPort)
+		if err != nil {
+			continue
+		}
+
+		containerPorts = append(containerPorts, core.ContainerPort{
+			ContainerPort: int32(port.Int()),
+			HostPort:      int32(hostPort),
+			Protocol:      core.Protocol(strings.ToUpper(port.Proto())),
+		})
+	}
+
+	sort.Slice(containerPorts, func(i, j int) bool {
+		return containerPorts[i].ContainerPort < containerPorts[j].ContainerPort
+	})
+
+	return containerPorts
+}
@@ -4,7 +4,9 @@ package converter
 import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
+	"github.com/portainer/k2d/internal/adapter/registry"
 	"github.com/portainer/k2d/internal/adapter/store"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
 	"github.com/portainer/k2d/internal/types"
 	"github.com/portainer/k2d/pkg/rand"
 )
@@ -14,9 +16,25 @@ import (
 // created containers.
 type DockerAPIConverter struct {
 	configMapStore         store.ConfigMapStore
+	containerRuntime       k2dtypes.ContainerRuntime
 	secretStore            store.SecretStore
 	k2dServerConfiguration *types.K2DServerConfiguration
 	portGenerator          *rand.PortGenerator
+	logOptions             LogOptions
+	offlineImages          []string
+	offlineMode            bool
+	registryOptions        registry.Options
+	usernsMode             string
+}
+
+// LogOptions represents the logging configuration applied to all the containers created by k2d
+type LogOptions struct {
+	// Driver is the Docker logging driver, the default driver of the Docker daemon is used when empty
+	Driver string
+	// MaxSize is the maximum size of a log file before it is rotated, only applied with the json-file and local drivers
+	MaxSize string
+	// MaxFiles is the maximum number of log files retained, only applied with the json-file and local drivers
+	MaxFiles int
 }
 
 // ContainerConfiguration is a wrapper around the Docker API container configuration
@@ -32,8 +50,41 @@ type ContainerConfiguration struct {
 func NewDockerAPIConverter(configMapStore store.ConfigMapStore, secretStore store.SecretStore, k2dServerConfig *types.K2DServerConfiguration) *DockerAPIConverter {
 	return &DockerAPIConverter{
 		configMapStore:         configMapStore,
+		containerRuntime:       k2dtypes.DockerContainerRuntime,
 		secretStore:            secretStore,
 		k2dServerConfiguration: k2dServerConfig,
 		portGenerator:          rand.NewPortGenerator(),
 	}
 }
+
+// SetContainerRuntime sets the container runtime used by the converter to apply runtime specific behaviors
+// (e.g. fully qualified image names when using Podman).
+// It must be called before any conversion is performed.
+func (converter *DockerAPIConverter) SetContainerRuntime(runtime k2dtypes.ContainerRuntime) {
+	converter.containerRuntime = runtime
+}
+
+// SetRegistryOptions sets the configuration of the registries, reported in the node annotations.
+// It must be called before any conversion is performed.
+func (converter *DockerAPIConverter) SetRegistryOptions(options registry.Options) {
+	converter.registryOptions = options
+}
+
+// SetOfflineMode defines whether k2d runs without access to the registries, using the images of the image archives.
+// The offline mode and the images available in the archives are advertised through the node annotations.
+func (converter *DockerAPIConverter) SetOfflineMode(offlineMode bool, images []string) {
+	converter.offlineMode = offlineMode
+	converter.offlineImages = images
+}
+
+// SetLogOptions sets the logging configuration applied to the containers created by k2d.
+// It must be called before any conversion is performed.
+func (converter *DockerAPIConverter) SetLogOptions(options LogOptions) {
+	converter.logOptions = options
+}
+
+// SetUsernsMode sets the user namespace mode applied to the containers created by k2d (e.g. host).
+// It must be called before any conversion is performed.
+func (converter *DockerAPIConverter) SetUsernsMode(mode string) {
+	converter.usernsMode = mode
+}
@@ -29,10 +29,11 @@ func (converter *DockerAPIConverter) UpdateDeploymentFromContainerInfo(deploymen
 		deployment.Spec.Replicas = 1
 	}
 
+	// the container is always created from the current pod template, the replica is therefore always up-to-date
 	deployment.Status.Replicas = 1
+	deployment.Status.UpdatedReplicas = 1
 
 	if containerState == "running" {
-		deployment.Status.UpdatedReplicas = 1
 		deployment.Status.ReadyReplicas = 1
 		deployment.Status.AvailableReplicas = 1
 
@@ -58,4 +59,14 @@ func (converter *DockerAPIConverter) UpdateDeploymentFromContainerInfo(deploymen
 			},
 		}
 	}
+
+	if deployment.Spec.Paused {
+		deployment.Status.Conditions = append(deployment.Status.Conditions, apps.DeploymentCondition{
+			Type:               apps.DeploymentProgressing,
+			Status:             "Unknown",
+			Message:            "Deployment is paused",
+			Reason:             "DeploymentPaused",
+			LastTransitionTime: metav1.NewTime(time.Now()),
+		})
+	}
 }
@@ -0,0 +1,96 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// prepareHostPath validates a HostPath volume source according to its type and creates
+// the associated directory or file on the host when requested.
+// Host paths are resolved using the filesystem of the k2d process, when k2d is running inside a container
+// the host paths must be mounted at the same location inside the k2d container.
+//
+// The following types are supported:
+//   - "" (unset): no validation is performed, Docker creates the directory if it does not exist.
+//   - DirectoryOrCreate: creates the directory (with 0755 permissions) if it does not exist.
+//   - Directory: the directory must exist.
+//   - FileOrCreate: creates an empty file (with 0644 permissions) if it does not exist, parent directories are created as well.
+//   - File: the file must exist.
+//   - Socket: a UNIX socket must exist at the given path.
+//   - CharDevice: a character device must exist at the given path.
+//   - BlockDevice: a block device must exist at the given path.
+//
+// It returns an error if the path does not match the expected type or if it cannot be created.
+func prepareHostPath(hostPath *core.HostPathVolumeSource) error {
+	if hostPath.Type == nil || *hostPath.Type == core.HostPathUnset {
+		return nil
+	}
+
+	info, err := os.Stat(hostPath.Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to inspect host path %s: %w", hostPath.Path, err)
+	}
+	exists := err == nil
+
+	switch *hostPath.Type {
+	case core.HostPathDirectoryOrCreate:
+		if !exists {
+			if err := os.MkdirAll(hostPath.Path, 0755); err != nil {
+				return fmt.Errorf("unable to create directory %s: %w", hostPath.Path, err)
+			}
+			return nil
+		}
+		return expectHostPathType(hostPath, info.IsDir(), "directory")
+	case core.HostPathDirectory:
+		if !exists {
+			return fmt.Errorf("host path %s does not exist, a directory is expected", hostPath.Path)
+		}
+		return expectHostPathType(hostPath, info.IsDir(), "directory")
+	case core.HostPathFileOrCreate:
+		if !exists {
+			if err := os.MkdirAll(filepath.Dir(hostPath.Path), 0755); err != nil {
+				return fmt.Errorf("unable to create parent directory of %s: %w", hostPath.Path, err)
+			}
+
+			file, err := os.OpenFile(hostPath.Path, os.O_CREATE|os.O_RDONLY, 0644)
+			if err != nil {
+				return fmt.Errorf("unable to create file %s: %w", hostPath.Path, err)
+			}
+			return file.Close()
+		}
+		return expectHostPathType(hostPath, info.Mode().IsRegular(), "file")
+	case core.HostPathFile:
+		if !exists {
+			return fmt.Errorf("host path %s does not exist, a file is expected", hostPath.Path)
+		}
+		return expectHostPathType(hostPath, info.Mode().IsRegular(), "file")
+	case core.HostPathSocket:
+		if !exists {
+			return fmt.Errorf("host path %s does not exist, a socket is expected", hostPath.Path)
+		}
+		return expectHostPathType(hostPath, info.Mode()&os.ModeSocket != 0, "socket")
+	case core.HostPathCharDev:
+		if !exists {
+			return fmt.Errorf("host path %s does not exist, a character device is expected", hostPath.Path)
+		}
+		return expectHostPathType(hostPath, info.Mode()&os.ModeCharDevice != 0, "character device")
+	case core.HostPathBlockDev:
+		if !exists {
+			return fmt.Errorf("host path %s does not exist, a block device is expected", hostPath.Path)
+		}
+		return expectHostPathType(hostPath, info.Mode()&os.ModeDevice != 0 && info.Mode()&os.ModeCharDevice == 0, "block device")
+	default:
+		return fmt.Errorf("unsupported host path type %s for %s", *hostPath.Type, hostPath.Path)
+	}
+}
+
+func expectHostPathType(hostPath *core.HostPathVolumeSource, matches bool, expectedType string) error {
+	if !matches {
+		return fmt.Errorf("host path %s is not a %s", hostPath.Path, expectedType)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package converter
+
+import (
+	"github.com/docker/distribution/reference"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+)
+
+// qualifyImageName returns the fully qualified name of an image (e.g. nginx becomes docker.io/library/nginx:latest)
+// when the container runtime is Podman.
+// Podman can be configured to enforce short-name resolution, in which case pulling an image using a short name fails
+// when the API is used in a non-interactive way.
+// The image name is returned unchanged when using Docker or when the name cannot be parsed.
+func (converter *DockerAPIConverter) qualifyImageName(image string) string {
+	if converter.containerRuntime != k2dtypes.PodmanContainerRuntime {
+		return image
+	}
+
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+
+	return reference.TagNameOnly(named).String()
+}
+
+// IsSameImage returns true when both image names reference the same image once normalized
+// (e.g. nginx and docker.io/library/nginx:latest), which allows the image of a container created using
+// a fully qualified image name to be compared with the image of the pod specification.
+// The names are compared as is when one of them cannot be parsed.
+func IsSameImage(image, otherImage string) bool {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image == otherImage
+	}
+
+	otherNamed, err := reference.ParseNormalizedNamed(otherImage)
+	if err != nil {
+		return image == otherImage
+	}
+
+	return reference.TagNameOnly(named).String() == reference.TagNameOnly(otherNamed).String()
+}
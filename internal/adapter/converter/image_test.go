@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"testing"
+
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+)
+
+func TestQualifyImageName(t *testing.T) {
+	tests := []struct {
+		runtime  k2dtypes.ContainerRuntime
+		image    string
+		expected string
+	}{
+		{runtime: k2dtypes.DockerContainerRuntime, image: "nginx", expected: "nginx"},
+		{runtime: k2dtypes.PodmanContainerRuntime, image: "nginx", expected: "docker.io/library/nginx:latest"},
+		{runtime: k2dtypes.PodmanContainerRuntime, image: "portainer/agent:2.19.0", expected: "docker.io/portainer/agent:2.19.0"},
+		{runtime: k2dtypes.PodmanContainerRuntime, image: "quay.io/podman/hello", expected: "quay.io/podman/hello:latest"},
+		{runtime: k2dtypes.PodmanContainerRuntime, image: "Invalid:Image", expected: "Invalid:Image"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.runtime)+" "+test.image, func(t *testing.T) {
+			converter := &DockerAPIConverter{containerRuntime: test.runtime}
+
+			image := converter.qualifyImageName(test.image)
+			if image != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, image)
+			}
+		})
+	}
+}
+
+func TestIsSameImage(t *testing.T) {
+	tests := []struct {
+		image      string
+		otherImage string
+		expected   bool
+	}{
+		{image: "nginx", otherImage: "nginx", expected: true},
+		{image: "docker.io/library/nginx:latest", otherImage: "nginx", expected: true},
+		{image: "docker.io/library/nginx:1.25", otherImage: "nginx:1.25", expected: true},
+		{image: "docker.io/library/nginx:latest", otherImage: "nginx:1.25", expected: false},
+		{image: "quay.io/nginx:latest", otherImage: "nginx", expected: false},
+		{image: "Invalid:Image", otherImage: "Invalid:Image", expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.image+" "+test.otherImage, func(t *testing.T) {
+			if IsSameImage(test.image, test.otherImage) != test.expected {
+				t.Errorf("expected IsSameImage to return %t", test.expected)
+			}
+		})
+	}
+}
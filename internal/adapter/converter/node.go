@@ -2,63 +2,228 @@ package converter
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"github.com/portainer/k2d/pkg/sysinfo"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/kubernetes/pkg/apis/core"
 )
 
-func (converter *DockerAPIConverter) ConvertInfoVersionToNode(info types.Info, version types.Version, startTime time.Time) core.Node {
+const (
+	// nodeMaxPods is the maximum number of pods reported in the node capacity.
+	// It matches the default value used by the kubelet.
+	nodeMaxPods = 110
+
+	// nodeDiskPressureThreshold is the minimum percentage of available disk space on the k2d data path
+	// under which the node reports disk pressure. It matches the default kubelet eviction threshold (nodefs.available<10%).
+	nodeDiskPressureThreshold = 10
+
+	// nodeMemoryPressureThreshold is the minimum amount of available memory (in bytes) under which the node
+	// reports memory pressure. It matches the default kubelet eviction threshold (memory.available<100Mi).
+	nodeMemoryPressureThreshold = 100 * 1024 * 1024
+)
+
+// NodeHostStats contains statistics about the host that are used to build the status of a node.
+// A nil value indicates that the associated statistics could not be retrieved.
+type NodeHostStats struct {
+	// Disk is the usage of the filesystem containing the k2d data path
+	Disk *sysinfo.DiskUsage
+	// Memory is the memory usage of the host
+	Memory *sysinfo.MemoryUsage
+}
+
+// ConvertInfoToNodeLabels builds the labels of the node from the Docker server information.
+// The labels are used to evaluate the node selector and the node affinity of the pods.
+// The architecture reported by Docker (e.g. x86_64) is converted to the architecture name used by Kubernetes (e.g. amd64)
+// so that the kubernetes.io/arch label matches the node selectors found in the manifests.
+func (converter *DockerAPIConverter) ConvertInfoToNodeLabels(info types.Info) map[string]string {
+	architecture := normalizeArchitecture(info.Architecture)
+
+	return map[string]string{
+		"beta.kubernetes.io/arch":             architecture,
+		"beta.kubernetes.io/os":               info.OSType,
+		"kubernetes.io/arch":                  architecture,
+		"kubernetes.io/hostname":              info.Name,
+		"kubernetes.io/os":                    info.OSType,
+		"node-role.kubernetes.io/master":      "",
+		k2dtypes.ContainerRuntimeNodeLabelKey: string(converter.containerRuntime),
+	}
+}
+
+// normalizeArchitecture converts the machine hardware name reported by Docker (uname -m) into the
+// architecture name used by Go and Kubernetes. Unknown architectures are returned as is.
+func normalizeArchitecture(architecture string) string {
+	switch architecture {
+	case "x86_64":
+		return "amd64"
+	case "aarch64", "armv8l":
+		return "arm64"
+	case "armv7l", "armv6l":
+		return "arm"
+	case "i386", "i686":
+		return "386"
+	default:
+		return architecture
+	}
+}
+
+// ConvertInfoVersionToNode converts the Docker server information and version into a Kubernetes Node object.
+// The container runtime (docker or podman) is exposed through the k2d.io/container-runtime label and the container runtime version.
+// The node capacity and allocatable resources are based on the CPU count and memory reported by Docker as well as
+// the size of the filesystem containing the k2d data path.
+// The node conditions are computed from the host statistics:
+//   - Ready is always true as the Docker API is reachable.
+//   - DiskPressure is true when the available disk space on the k2d data path is below 10%.
+//   - MemoryPressure is true when the available memory on the host is below 100Mi.
+//
+// The registries configured as insecure or with a custom CA bundle are listed in the k2d.io/insecure-registries
+// and k2d.io/registry-ca-bundles annotations. When k2d runs in offline mode (see SetOfflineMode), the node is annotated
+// with k2d.io/offline-mode and the images available in the image archives are listed in the k2d.io/offline-images annotation.
+//
+// DiskPressure and MemoryPressure are reported with an Unknown status when the associated statistics are not available.
+func (converter *DockerAPIConverter) ConvertInfoVersionToNode(info types.Info, version types.Version, startTime time.Time, hostStats NodeHostStats) core.Node {
+	now := metav1.NewTime(time.Now())
+
+	resources := core.ResourceList{
+		core.ResourceCPU:    *resource.NewQuantity(int64(info.NCPU), resource.DecimalSI),
+		core.ResourceMemory: *resource.NewQuantity(int64(info.MemTotal), resource.BinarySI),
+		core.ResourcePods:   *resource.NewQuantity(nodeMaxPods, resource.DecimalSI),
+	}
+
+	if hostStats.Disk != nil {
+		resources[core.ResourceEphemeralStorage] = *resource.NewQuantity(int64(hostStats.Disk.Total), resource.BinarySI)
+	}
+
+	addresses := []core.NodeAddress{
+		{
+			Type:    core.NodeHostName,
+			Address: info.Name,
+		},
+	}
+
+	if converter.k2dServerConfiguration != nil && converter.k2dServerConfiguration.ServerIpAddr != "" {
+		addresses = append([]core.NodeAddress{
+			{
+				Type:    core.NodeInternalIP,
+				Address: converter.k2dServerConfiguration.ServerIpAddr,
+			},
+		}, addresses...)
+	}
+
+	annotations := map[string]string{}
+	if len(converter.registryOptions.InsecureRegistries) > 0 {
+		annotations[k2dtypes.InsecureRegistriesNodeAnnotationKey] = strings.Join(converter.registryOptions.InsecureRegistries, ",")
+	}
+	if len(converter.registryOptions.CABundles) > 0 {
+		annotations[k2dtypes.RegistryCABundlesNodeAnnotationKey] = strings.Join(converter.registryOptions.CABundleRegistries(), ",")
+	}
+	if converter.offlineMode {
+		annotations[k2dtypes.OfflineModeNodeAnnotationKey] = "true"
+		annotations[k2dtypes.OfflineImagesNodeAnnotationKey] = strings.Join(converter.offlineImages, ",")
+	}
+
 	return core.Node{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Node",
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: info.Name,
-			UID:  k8stypes.UID(info.ID),
+			Name:        info.Name,
+			UID:         k8stypes.UID(info.ID),
+			Annotations: annotations,
 			CreationTimestamp: metav1.Time{
 				Time: startTime,
 			},
-			Labels: map[string]string{
-				"beta.kubernetes.io/arch":        info.Architecture,
-				"beta.kubernetes.io/os":          info.OSType,
-				"kubernetes.io/arch":             info.Architecture,
-				"kubernetes.io/hostname":         info.Name,
-				"kubernetes.io/os":               info.OSType,
-				"node-role.kubernetes.io/master": "",
-			},
+			Labels: converter.ConvertInfoToNodeLabels(info),
 		},
 		Spec: core.NodeSpec{
 			ProviderID: "k2d",
 		},
 		Status: core.NodeStatus{
+			Addresses: addresses,
 			Conditions: []core.NodeCondition{
 				{
-					Type:               "Ready",
-					Status:             "True",
+					Type:               core.NodeReady,
+					Status:             core.ConditionTrue,
 					Reason:             "KubeletReady",
 					Message:            "kubelet is posting ready status",
-					LastHeartbeatTime:  metav1.NewTime(time.Now()),
-					LastTransitionTime: metav1.NewTime(time.Now()),
+					LastHeartbeatTime:  now,
+					LastTransitionTime: metav1.NewTime(startTime),
 				},
+				buildDiskPressureCondition(hostStats.Disk, now, startTime),
+				buildMemoryPressureCondition(hostStats.Memory, now, startTime),
 			},
 			NodeInfo: core.NodeSystemInfo{
-				Architecture:            info.Architecture,
-				ContainerRuntimeVersion: version.Version,
+				Architecture:            normalizeArchitecture(info.Architecture),
+				ContainerRuntimeVersion: fmt.Sprintf("%s://%s", converter.containerRuntime, version.Version),
 				KernelVersion:           info.KernelVersion,
 				KubeletVersion:          fmt.Sprintf("docker-%s", version.Version),
 				MachineID:               info.ID,
 				OperatingSystem:         info.OSType,
+				OSImage:                 info.OperatingSystem,
 				SystemUUID:              info.ID,
 			},
-			Capacity: core.ResourceList{
-				core.ResourceCPU:    *resource.NewQuantity(int64(info.NCPU), resource.DecimalSI),
-				core.ResourceMemory: *resource.NewQuantity(int64(info.MemTotal), resource.BinarySI),
-			},
+			Capacity:    resources,
+			Allocatable: resources.DeepCopy(),
 		},
 	}
 }
+
+func buildDiskPressureCondition(disk *sysinfo.DiskUsage, now metav1.Time, startTime time.Time) core.NodeCondition {
+	condition := core.NodeCondition{
+		Type:               core.NodeDiskPressure,
+		Status:             core.ConditionUnknown,
+		Reason:             "NodeStatusUnknown",
+		Message:            "unable to retrieve disk usage",
+		LastHeartbeatTime:  now,
+		LastTransitionTime: metav1.NewTime(startTime),
+	}
+
+	if disk == nil || disk.Total == 0 {
+		return condition
+	}
+
+	if disk.Available*100/disk.Total < nodeDiskPressureThreshold {
+		condition.Status = core.ConditionTrue
+		condition.Reason = "KubeletHasDiskPressure"
+		condition.Message = "kubelet has disk pressure"
+		return condition
+	}
+
+	condition.Status = core.ConditionFalse
+	condition.Reason = "KubeletHasNoDiskPressure"
+	condition.Message = "kubelet has no disk pressure"
+	return condition
+}
+
+func buildMemoryPressureCondition(memory *sysinfo.MemoryUsage, now metav1.Time, startTime time.Time) core.NodeCondition {
+	condition := core.NodeCondition{
+		Type:               core.NodeMemoryPressure,
+		Status:             core.ConditionUnknown,
+		Reason:             "NodeStatusUnknown",
+		Message:            "unable to retrieve memory usage",
+		LastHeartbeatTime:  now,
+		LastTransitionTime: metav1.NewTime(startTime),
+	}
+
+	if memory == nil {
+		return condition
+	}
+
+	if memory.Available < nodeMemoryPressureThreshold {
+		condition.Status = core.ConditionTrue
+		condition.Reason = "KubeletHasInsufficientMemory"
+		condition.Message = "kubelet has insufficient memory available"
+		return condition
+	}
+
+	condition.Status = core.ConditionFalse
+	condition.Reason = "KubeletHasSufficientMemory"
+	condition.Message = "kubelet has sufficient memory available"
+	return condition
+}
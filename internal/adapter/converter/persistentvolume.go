@@ -11,6 +11,11 @@ import (
 	"k8s.io/kubernetes/pkg/apis/core"
 )
 
+// ConvertVolumeToPersistentVolume converts a Docker volume into a PersistentVolume.
+// The volume is Bound when a persistent volume claim is associated with it (through its system configmap) and Released otherwise,
+// e.g. once the claim has been deleted with a Retain reclaim policy. The reclaim policy is copied from the storage class when
+// the volume is provisioned, the volumes created without a storage class (e.g. statically assigned) are retained. When the volume is retrieved with its usage data (docker system df), the disk space
+// used by the volume is reported as the capacity of the PersistentVolume.
 func (converter *DockerAPIConverter) ConvertVolumeToPersistentVolume(volume *volume.Volume, pvcConfigMap *corev1.ConfigMap) (core.PersistentVolume, error) {
 	creationDate, err := time.Parse(time.RFC3339, volume.CreatedAt)
 	if err != nil {
@@ -29,6 +34,23 @@ func (converter *DockerAPIConverter) ConvertVolumeToPersistentVolume(volume *vol
 		}
 	}
 
+	storageClassName := DefaultStorageClassName
+	if volume.Labels[k2dtypes.StorageClassNameLabelKey] != "" {
+		storageClassName = volume.Labels[k2dtypes.StorageClassNameLabelKey]
+	}
+
+	reclaimPolicy := core.PersistentVolumeReclaimRetain
+	if volume.Labels[k2dtypes.ReclaimPolicyLabelKey] != "" {
+		reclaimPolicy = core.PersistentVolumeReclaimPolicy(volume.Labels[k2dtypes.ReclaimPolicyLabelKey])
+	}
+
+	var capacity core.ResourceList
+	if usage, found := volumeUsage(volume); found {
+		capacity = core.ResourceList{
+			core.ResourceStorage: usage,
+		}
+	}
+
 	return core.PersistentVolume{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: volume.Name,
@@ -44,14 +66,15 @@ func (converter *DockerAPIConverter) ConvertVolumeToPersistentVolume(volume *vol
 			AccessModes: []core.PersistentVolumeAccessMode{
 				core.ReadWriteOnce,
 			},
-			PersistentVolumeReclaimPolicy: core.PersistentVolumeReclaimRetain,
+			Capacity:                      capacity,
+			PersistentVolumeReclaimPolicy: reclaimPolicy,
 			PersistentVolumeSource: core.PersistentVolumeSource{
 				HostPath: &core.HostPathVolumeSource{
 					Path: volume.Mountpoint,
 				},
 			},
 			ClaimRef:         persistentVolumeClaimReference,
-			StorageClassName: "local",
+			StorageClassName: storageClassName,
 		},
 		Status: core.PersistentVolumeStatus{
 			Phase: phase,
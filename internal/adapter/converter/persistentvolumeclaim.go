@@ -1,14 +1,26 @@
 package converter
 
 import (
+	"github.com/docker/docker/api/types/volume"
 	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kubernetes/pkg/apis/core"
 )
 
-func (converter *DockerAPIConverter) UpdateConfigMapToPersistentVolumeClaim(persistentVolumeClaim *core.PersistentVolumeClaim, configMap *corev1.ConfigMap) error {
-	storageClassName := "local"
+// UpdateConfigMapToPersistentVolumeClaim updates a PersistentVolumeClaim from its system configmap and from the Docker volume
+// bound to the claim. The volume must be retrieved with its usage data (docker system df) so that the capacity of the claim can be reported.
+//
+// The status of the claim is set as follows:
+//   - When the volume exists, the claim is Bound and reports the access modes of its spec. The capacity is the storage requested
+//     by the claim or, when no storage is requested, the disk space used by the volume.
+//   - When the volume is nil (e.g. removed outside of k2d), the claim is Lost.
+func (converter *DockerAPIConverter) UpdateConfigMapToPersistentVolumeClaim(persistentVolumeClaim *core.PersistentVolumeClaim, configMap *corev1.ConfigMap, volume *volume.Volume) error {
+	storageClassName := DefaultStorageClassName
+	if configMap.Labels[k2dtypes.StorageClassNameLabelKey] != "" {
+		storageClassName = configMap.Labels[k2dtypes.StorageClassNameLabelKey]
+	}
 
 	persistentVolumeClaim.TypeMeta = metav1.TypeMeta{
 		Kind:       "PersistentVolumeClaim",
@@ -26,21 +38,52 @@ func (converter *DockerAPIConverter) UpdateConfigMapToPersistentVolumeClaim(pers
 		},
 	}
 
+	accessModes := persistentVolumeClaim.Spec.AccessModes
+	if len(accessModes) == 0 {
+		accessModes = []core.PersistentVolumeAccessMode{
+			core.ReadWriteOnce,
+		}
+	}
+
 	persistentVolumeClaim.Spec = core.PersistentVolumeClaimSpec{
 		StorageClassName: &storageClassName,
 		VolumeName:       configMap.Labels[k2dtypes.PersistentVolumeNameLabelKey],
-		AccessModes: []core.PersistentVolumeAccessMode{
-			core.ReadWriteOnce,
-		},
-		Resources: persistentVolumeClaim.Spec.Resources,
+		AccessModes:      accessModes,
+		Resources:        persistentVolumeClaim.Spec.Resources,
+	}
+
+	if volume == nil {
+		persistentVolumeClaim.Status = core.PersistentVolumeClaimStatus{
+			Phase: core.ClaimLost,
+		}
+		return nil
 	}
 
 	persistentVolumeClaim.Status = core.PersistentVolumeClaimStatus{
-		Phase: core.ClaimBound,
-		AccessModes: []core.PersistentVolumeAccessMode{
-			core.ReadWriteOnce,
-		},
+		Phase:       core.ClaimBound,
+		AccessModes: accessModes,
+	}
+
+	capacity, found := persistentVolumeClaim.Spec.Resources.Requests[core.ResourceStorage]
+	if !found {
+		capacity, found = volumeUsage(volume)
+	}
+
+	if found {
+		persistentVolumeClaim.Status.Capacity = core.ResourceList{
+			core.ResourceStorage: capacity,
+		}
 	}
 
 	return nil
 }
+
+// volumeUsage returns the disk space used by a Docker volume. It returns false when the usage data of the volume
+// is not available (the volume was not retrieved through docker system df or the driver does not report it).
+func volumeUsage(volume *volume.Volume) (resource.Quantity, bool) {
+	if volume.UsageData == nil || volume.UsageData.Size < 0 {
+		return resource.Quantity{}, false
+	}
+
+	return *resource.NewQuantity(volume.UsageData.Size, resource.BinarySI), true
+}
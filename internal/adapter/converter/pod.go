@@ -5,14 +5,17 @@ import (
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/go-connections/nat"
+	units "github.com/docker/go-units"
 	"github.com/portainer/k2d/internal/adapter/naming"
 	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kubernetes/pkg/apis/core"
 )
@@ -28,14 +31,20 @@ import (
 //   - Populates the 'TypeMeta' and 'ObjectMeta' fields of the Pod object from the Docker container's metadata.
 //   - Creates a single-container PodSpec based on the Docker container's image and name.
 //   - Sets the Pod's status based on the Docker container's state. If the Docker container is running,
-//     the Pod's phase is set to 'Running', and the container status is marked as 'Ready'. Otherwise,
-//     the Pod's phase is set to 'Unknown'.
+//     the Pod's phase is set to 'Running', and the container status is marked as 'Ready'. If the Docker container
+//     has exited, the Pod's phase is set to 'Succeeded' or 'Failed' depending on the exit code of the container.
+//     If the Docker container has been created but not started yet, the Pod's phase is set to 'Pending'.
+//     If the Docker container is waiting to be restarted by its restart policy, the Pod's phase is set to 'Running'
+//     and the container is waiting with the 'CrashLoopBackOff' reason.
+//     Otherwise, the Pod's phase is set to 'Unknown'.
+//   - Populates the pod IP from the container IP address in the k2d network and the host IP from the k2d server IP address.
 //
 // Returns:
 // - A Kubernetes Pod object derived from the Docker container.
 func (converter *DockerAPIConverter) ConvertContainerToPod(container types.Container) core.Pod {
 	containerName := container.Labels[k2dtypes.WorkloadNameLabelKey]
 	containerState := container.State
+	startTime := metav1.NewTime(time.Unix(container.Created, 0))
 
 	pod := core.Pod{
 		TypeMeta: metav1.TypeMeta{
@@ -59,17 +68,34 @@ func (converter *DockerAPIConverter) ConvertContainerToPod(container types.Conta
 			},
 		},
 		Status: core.PodStatus{
+			StartTime: &startTime,
 			ContainerStatuses: []core.ContainerStatus{
 				{
 					Name:         containerName,
-					ContainerID:  container.ID,
+					Image:        container.Image,
+					ImageID:      container.ImageID,
+					ContainerID:  "docker://" + container.ID,
 					RestartCount: 0,
 				},
 			},
 		},
 	}
 
-	if containerState == "running" {
+	if converter.k2dServerConfiguration != nil {
+		pod.Status.HostIP = converter.k2dServerConfiguration.ServerIpAddr
+	}
+
+	if container.NetworkSettings != nil {
+		if containerNetwork, ok := container.NetworkSettings.Networks[container.Labels[k2dtypes.NetworkNameLabelKey]]; ok && containerNetwork.IPAddress != "" {
+			pod.Status.PodIP = containerNetwork.IPAddress
+			pod.Status.PodIPs = []core.PodIP{{IP: containerNetwork.IPAddress}}
+		}
+	}
+
+	// the transition time of the conditions is derived from the container rather than the current time
+	// so that the pod is only reported as modified to the clients watching it when its state changes
+	switch containerState {
+	case "running":
 		ready := true
 
 		pod.Status.Phase = core.PodRunning
@@ -78,7 +104,7 @@ func (converter *DockerAPIConverter) ConvertContainerToPod(container types.Conta
 		pod.Status.ContainerStatuses[0].Started = &ready
 
 		pod.Status.ContainerStatuses[0].State.Running = &core.ContainerStateRunning{
-			StartedAt: metav1.NewTime(time.Unix(container.Created, 0)),
+			StartedAt: startTime,
 		}
 
 		// the conditions block with PodReady, PodScheduled, PodInitialized, and ContainersReady
@@ -88,28 +114,93 @@ func (converter *DockerAPIConverter) ConvertContainerToPod(container types.Conta
 				Type:               core.PodReady,
 				Status:             "True",
 				Message:            "Pod is ready",
-				LastTransitionTime: metav1.NewTime(time.Now()),
+				LastTransitionTime: startTime,
 			},
 			{
 				Type:               core.PodScheduled,
 				Status:             "True",
 				Message:            "Pod is scheduled",
-				LastTransitionTime: metav1.NewTime(time.Now()),
+				LastTransitionTime: startTime,
 			},
 			{
 				Type:               core.PodInitialized,
 				Status:             "True",
 				Message:            "Pod has been initialized",
-				LastTransitionTime: metav1.NewTime(time.Now()),
+				LastTransitionTime: startTime,
 			},
 			{
 				Type:               core.ContainersReady,
 				Status:             "True",
 				Message:            "Containers are ready",
-				LastTransitionTime: metav1.NewTime(time.Now()),
+				LastTransitionTime: startTime,
+			},
+		}
+	case "exited", "dead":
+		exitCode := getContainerExitCode(container.Status)
+
+		pod.Status.Phase = core.PodSucceeded
+		reason := "Completed"
+		if exitCode != 0 {
+			pod.Status.Phase = core.PodFailed
+			reason = "Error"
+		}
+
+		pod.Status.ContainerStatuses[0].State.Terminated = &core.ContainerStateTerminated{
+			ExitCode:    exitCode,
+			Reason:      reason,
+			StartedAt:   startTime,
+			ContainerID: "docker://" + container.ID,
+		}
+
+		pod.Status.Conditions = []core.PodCondition{
+			{
+				Type:               core.PodReady,
+				Status:             "False",
+				Reason:             "PodCompleted",
+				LastTransitionTime: startTime,
+			},
+			{
+				Type:               core.PodScheduled,
+				Status:             "True",
+				LastTransitionTime: startTime,
+			},
+		}
+	case "restarting":
+		pod.Status.Phase = core.PodRunning
+
+		pod.Status.ContainerStatuses[0].State.Waiting = &core.ContainerStateWaiting{
+			Reason:  "CrashLoopBackOff",
+			Message: "back-off restarting failed container",
+		}
+
+		pod.Status.Conditions = []core.PodCondition{
+			{
+				Type:               core.PodReady,
+				Status:             "False",
+				Reason:             "ContainersNotReady",
+				LastTransitionTime: startTime,
+			},
+			{
+				Type:               core.PodScheduled,
+				Status:             "True",
+				LastTransitionTime: startTime,
+			},
+		}
+	case "created":
+		pod.Status.Phase = core.PodPending
+
+		pod.Status.ContainerStatuses[0].State.Waiting = &core.ContainerStateWaiting{
+			Reason: "ContainerCreating",
+		}
+
+		pod.Status.Conditions = []core.PodCondition{
+			{
+				Type:               core.PodScheduled,
+				Status:             "True",
+				LastTransitionTime: startTime,
 			},
 		}
-	} else {
+	default:
 		pod.Status.Phase = core.PodUnknown
 
 		// this is to mark the pod's condition as unknown
@@ -118,7 +209,7 @@ func (converter *DockerAPIConverter) ConvertContainerToPod(container types.Conta
 				Type:               core.PodConditionType(core.PodUnknown),
 				Status:             "False",
 				Message:            "Pod is not running",
-				LastTransitionTime: metav1.NewTime(time.Now()),
+				LastTransitionTime: startTime,
 			},
 		}
 	}
@@ -126,10 +217,27 @@ func (converter *DockerAPIConverter) ConvertContainerToPod(container types.Conta
 	return pod
 }
 
+// getContainerExitCode extracts the exit code of a container from its human readable Docker status
+// (e.g. "Exited (137) 2 minutes ago"). It returns 0 when the exit code cannot be found in the status.
+func getContainerExitCode(status string) int32 {
+	start := strings.Index(status, "(")
+	end := strings.Index(status, ")")
+	if start == -1 || end <= start {
+		return 0
+	}
+
+	exitCode, err := strconv.ParseInt(status[start+1:end], 10, 32)
+	if err != nil {
+		return 0
+	}
+
+	return int32(exitCode)
+}
+
 // ConvertPodSpecToContainerConfiguration converts a Kubernetes PodSpec into a Docker ContainerConfiguration.
 //
 // This function takes a PodSpec (`spec`), the namespace where the pod is to be created (`namespace`),
-// and a set of labels (`labels`) as arguments. It returns a struct `ContainerConfiguration` which contains
+// a set of labels (`labels`) and the pod annotations (`annotations`) as arguments. It returns a struct `ContainerConfiguration` which contains
 // configurations to be used for creating a Docker container, and an error if any occurs.
 //
 // The function assumes the PodSpec contains at least one container specification. It only uses the first
@@ -141,18 +249,23 @@ func (converter *DockerAPIConverter) ConvertContainerToPod(container types.Conta
 //  4. It configures port mappings based on the Kubernetes container ports.
 //  5. It sets environment variables based on the Kubernetes container environment settings.
 //  6. It sets the container's command and arguments if they are specified in the PodSpec.
-//  7. It sets the container's restart policy based on the Kubernetes Pod's restart policy.
+//  7. It sets the container's restart policy based on the Kubernetes Pod's restart policy as well as
+//     the DNS settings based on the Pod's DNS policy and DNS configuration.
 //  8. It sets the container and host-level security context based on the PodSpec.
-//  9. It sets resource requirements (CPU, memory limits, etc.) based on the Kubernetes container resources.
-//  10. It configures volume mounts for the container based on the Kubernetes volume specifications.
-//  11. Finally, it sets the network settings for the container, using a network name retrieved from the labels.
+//  9. It sets resource requirements (CPU, memory limits, GPUs, etc.) based on the Kubernetes container resources.
+//  10. It passes through the host devices, the ulimits and the size of /dev/shm specified in the pod annotations.
+//  11. It configures the logging driver and the log rotation of the container using the k2d configuration and the pod annotations.
+//  12. It sets the user namespace mode of the container using the k2d configuration and the pod annotations.
+//  13. It applies the raw Docker options specified in the pod annotations (capabilities, security options, network mode and labels).
+//  14. It configures volume mounts for the container based on the Kubernetes volume specifications.
+//  15. Finally, it sets the network settings for the container, using a network name retrieved from the labels.
 //
 // If any of these steps fails, an error is returned.
-func (converter *DockerAPIConverter) ConvertPodSpecToContainerConfiguration(spec core.PodSpec, namespace string, labels map[string]string) (ContainerConfiguration, error) {
+func (converter *DockerAPIConverter) ConvertPodSpecToContainerConfiguration(spec core.PodSpec, namespace string, labels map[string]string, annotations map[string]string) (ContainerConfiguration, error) {
 	containerSpec := spec.Containers[0]
 
 	containerConfig := &container.Config{
-		Image:  containerSpec.Image,
+		Image:  converter.qualifyImageName(containerSpec.Image),
 		Labels: labels,
 		Env: []string{
 			fmt.Sprintf("KUBERNETES_SERVICE_HOST=%s", converter.k2dServerConfiguration.ServerIpAddr),
@@ -180,54 +293,434 @@ func (converter *DockerAPIConverter) ConvertPodSpecToContainerConfiguration(spec
 
 	setCommandAndArgs(containerConfig, containerSpec.Command, containerSpec.Args)
 	setRestartPolicy(hostConfig, spec.RestartPolicy)
+	setDNSConfig(hostConfig, spec.DNSConfig)
 	setSecurityContext(containerConfig, hostConfig, spec.SecurityContext, containerSpec.SecurityContext)
 	converter.setResourceRequirements(hostConfig, containerSpec.Resources)
 
+	if err := setDevices(hostConfig, annotations); err != nil {
+		return ContainerConfiguration{}, err
+	}
+
+	if err := setUlimits(hostConfig, annotations); err != nil {
+		return ContainerConfiguration{}, err
+	}
+
+	if err := setShmSize(hostConfig, annotations); err != nil {
+		return ContainerConfiguration{}, err
+	}
+
+	if err := converter.SetContainerLogConfig(hostConfig, annotations); err != nil {
+		return ContainerConfiguration{}, err
+	}
+
+	converter.setUsernsMode(hostConfig, spec.SecurityContext, annotations)
+
+	if err := setDockerOptions(containerConfig, hostConfig, annotations); err != nil {
+		return ContainerConfiguration{}, err
+	}
+
 	if err := converter.setVolumeMounts(namespace, hostConfig, spec.Volumes, containerSpec.VolumeMounts); err != nil {
 		return ContainerConfiguration{}, err
 	}
 
-	networkName := labels[k2dtypes.NetworkNameLabelKey]
+	networkConfig := &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{},
+	}
+
+	// a container using a specific network mode cannot be connected to the network of its namespace
+	if hostConfig.NetworkMode == "" {
+		networkName := labels[k2dtypes.NetworkNameLabelKey]
+		networkConfig.EndpointsConfig[networkName] = &network.EndpointSettings{}
+	}
+
 	return ContainerConfiguration{
 		ContainerConfig: containerConfig,
 		HostConfig:      hostConfig,
-		NetworkConfig: &network.NetworkingConfig{
-			EndpointsConfig: map[string]*network.EndpointSettings{
-				networkName: {},
-			},
-		},
+		NetworkConfig:   networkConfig,
 	}, nil
 }
 
-// setResourceRequirements configures the Docker container's resource constraints based on the provided core.ResourceRequirements.
+// nvidiaGPUResourceName is the name of the extended resource used to request NVIDIA GPUs
+const nvidiaGPUResourceName core.ResourceName = "nvidia.com/gpu"
+
+const (
+	// cpuSharesPerCPU is the number of CPU shares of a container requesting one CPU, following the cgroup convention used by the kubelet
+	cpuSharesPerCPU = 1024
+	// minCPUShares and maxCPUShares are the bounds of the CPU shares accepted by the kernel
+	minCPUShares = 2
+	maxCPUShares = 262144
+	// minNanoCPUs is the smallest CPU limit accepted by Docker (0.01 CPU), the kubelet uses the same minimum quota
+	minNanoCPUs = 10000000
+)
+
+// setResourceRequirements configures the Docker container's resource constraints based on the provided core.ResourceRequirements,
+// following the translation made by the kubelet:
+//   - The CPU request is converted to CPU shares using the 1024 shares per CPU convention (e.g. 100m becomes 102 shares),
+//     the kernel priority of the container being relative to the other containers. A container without any CPU request
+//     keeps the default Docker CPU shares.
+//   - The CPU limit is converted to a CPU quota (NanoCPUs, e.g. 500m becomes 0.5 CPU), bounded to the minimum of 0.01 CPU.
+//   - The memory request is converted to a memory reservation (soft limit) and the memory limit to a hard memory limit.
+//     Swap is disabled when a memory limit is set (the swap limit equals the memory limit), as the kubelet does by default.
+//   - As in Kubernetes, the requests default to the limits when only the limits are defined.
+//   - The nvidia.com/gpu extended resource limit is mapped to a Docker device request (equivalent to docker run --gpus).
+//
 // It receives a Docker HostConfig and a Kubernetes ResourceRequirements.
 func (converter *DockerAPIConverter) setResourceRequirements(hostConfig *container.HostConfig, resources core.ResourceRequirements) {
 	resourceRequirements := container.Resources{}
-	if resources.Requests != nil {
-		for resourceName, quantity := range resources.Requests {
-			switch resourceName {
-			case core.ResourceCPU:
-				resourceRequirements.CPUShares = int64(quantity.MilliValue())
-			case core.ResourceMemory:
-				resourceRequirements.MemoryReservation = int64(quantity.Value())
-			}
-		}
+
+	cpuRequest, hasCPURequest := resources.Requests[core.ResourceCPU]
+	cpuLimit, hasCPULimit := resources.Limits[core.ResourceCPU]
+	if !hasCPURequest && hasCPULimit {
+		cpuRequest, hasCPURequest = cpuLimit, true
 	}
 
-	if resources.Limits != nil {
-		for resourceName, quantity := range resources.Limits {
-			switch resourceName {
-			case core.ResourceCPU:
-				resourceRequirements.NanoCPUs = int64(quantity.MilliValue()) * 1000000
-			case core.ResourceMemory:
-				resourceRequirements.Memory = int64(quantity.Value())
-			}
+	if hasCPURequest {
+		resourceRequirements.CPUShares = convertCPURequestToShares(cpuRequest)
+	}
+
+	if hasCPULimit {
+		resourceRequirements.NanoCPUs = convertCPULimitToNanoCPUs(cpuLimit)
+	}
+
+	memoryRequest, hasMemoryRequest := resources.Requests[core.ResourceMemory]
+	memoryLimit, hasMemoryLimit := resources.Limits[core.ResourceMemory]
+
+	if hasMemoryLimit {
+		resourceRequirements.Memory = memoryLimit.Value()
+		resourceRequirements.MemorySwap = resourceRequirements.Memory
+	}
+
+	if hasMemoryRequest {
+		resourceRequirements.MemoryReservation = memoryRequest.Value()
+		// Docker rejects a memory reservation greater than the memory limit
+		if hasMemoryLimit && resourceRequirements.MemoryReservation > resourceRequirements.Memory {
+			resourceRequirements.MemoryReservation = resourceRequirements.Memory
 		}
+	} else if hasMemoryLimit {
+		resourceRequirements.MemoryReservation = resourceRequirements.Memory
+	}
+
+	if gpuLimit, hasGPULimit := resources.Limits[nvidiaGPUResourceName]; hasGPULimit {
+		resourceRequirements.DeviceRequests = append(resourceRequirements.DeviceRequests, container.DeviceRequest{
+			Driver:       "nvidia",
+			Count:        int(gpuLimit.Value()),
+			Capabilities: [][]string{{"gpu"}},
+		})
 	}
 
 	hostConfig.Resources = resourceRequirements
 }
 
+// convertCPURequestToShares converts a CPU request to CPU shares (1024 shares per CPU), bounded to the values accepted by the kernel.
+func convertCPURequestToShares(cpuRequest resource.Quantity) int64 {
+	shares := cpuRequest.MilliValue() * cpuSharesPerCPU / 1000
+	if shares < minCPUShares {
+		return minCPUShares
+	}
+	if shares > maxCPUShares {
+		return maxCPUShares
+	}
+
+	return shares
+}
+
+// convertCPULimitToNanoCPUs converts a CPU limit to a CPU quota expressed in billionths of a CPU,
+// bounded to the minimum quota accepted by Docker.
+func convertCPULimitToNanoCPUs(cpuLimit resource.Quantity) int64 {
+	nanoCPUs := cpuLimit.ScaledValue(resource.Nano)
+	if nanoCPUs < minNanoCPUs {
+		return minNanoCPUs
+	}
+
+	return nanoCPUs
+}
+
+// setDevices configures the host devices passed through to the Docker container based on the
+// container.k2d.io/devices annotation. The annotation value is a comma separated list of devices
+// using the Docker --device format: <host path>[:<container path>[:<cgroup permissions>]].
+// It returns an error if one of the devices cannot be parsed.
+func setDevices(hostConfig *container.HostConfig, annotations map[string]string) error {
+	devices := annotations[k2dtypes.DevicesAnnotationKey]
+	if devices == "" {
+		return nil
+	}
+
+	for _, device := range strings.Split(devices, ",") {
+		device = strings.TrimSpace(device)
+		if device == "" {
+			continue
+		}
+
+		deviceMapping, err := parseDevice(device)
+		if err != nil {
+			return fmt.Errorf("invalid value for annotation %s: %w", k2dtypes.DevicesAnnotationKey, err)
+		}
+
+		hostConfig.Devices = append(hostConfig.Devices, deviceMapping)
+	}
+
+	return nil
+}
+
+// setUlimits configures the ulimits of the Docker container based on the container.k2d.io/ulimits annotation.
+// The annotation value is a comma separated list of ulimits using the Docker --ulimit format: <name>=<soft limit>[:<hard limit>].
+// It returns an error if one of the ulimits cannot be parsed.
+func setUlimits(hostConfig *container.HostConfig, annotations map[string]string) error {
+	ulimits := annotations[k2dtypes.UlimitsAnnotationKey]
+	if ulimits == "" {
+		return nil
+	}
+
+	for _, ulimit := range strings.Split(ulimits, ",") {
+		ulimit = strings.TrimSpace(ulimit)
+		if ulimit == "" {
+			continue
+		}
+
+		parsedUlimit, err := units.ParseUlimit(ulimit)
+		if err != nil {
+			return fmt.Errorf("invalid value for annotation %s: %w", k2dtypes.UlimitsAnnotationKey, err)
+		}
+
+		hostConfig.Ulimits = append(hostConfig.Ulimits, parsedUlimit)
+	}
+
+	return nil
+}
+
+// setUsernsMode configures the user namespace mode of the Docker container. The mode configured in k2d is used by default
+// and can be overridden for a pod using the container.k2d.io/userns-mode annotation. A pod explicitly sharing the user
+// namespace of the host (spec.hostUsers set to true) opts out of the user namespace isolation.
+func (converter *DockerAPIConverter) setUsernsMode(hostConfig *container.HostConfig, securityContext *core.PodSecurityContext, annotations map[string]string) {
+	usernsMode := converter.usernsMode
+
+	if securityContext != nil && securityContext.HostUsers != nil && *securityContext.HostUsers {
+		usernsMode = "host"
+	}
+
+	if annotationMode := strings.TrimSpace(annotations[k2dtypes.UsernsModeAnnotationKey]); annotationMode != "" {
+		usernsMode = annotationMode
+	}
+
+	hostConfig.UsernsMode = container.UsernsMode(usernsMode)
+}
+
+// setDockerOptions applies the raw Docker options specified in the pod annotations to the Docker container configuration.
+// These annotations are an escape hatch for the Docker features without any Kubernetes equivalent:
+//   - container.k2d.io/cap-add and container.k2d.io/cap-drop: comma separated lists of Linux capabilities (--cap-add, --cap-drop).
+//   - container.k2d.io/security-opt: comma separated list of security options (--security-opt).
+//   - container.k2d.io/network-mode: network mode of the container (--network), one of host, none or container:<name>.
+//   - container.k2d.io/labels: comma separated list of key=value labels added to the container (--label).
+//
+// The capabilities must be known Linux capabilities (or ALL) and the security options are restricted to the options
+// supported by the Docker daemon (see allowedSecurityOptions).
+// The port mappings are removed when a network mode is specified, as the ports of the container are either published
+// on the host directly (host), not reachable (none) or managed by the container sharing its network stack (container:<name>).
+// For the latter, the custom hosts are removed as well since Docker rejects them with this network mode.
+//
+// It returns an error if a capability, a security option or the network mode is not supported,
+// or if a label is invalid or reserved to k2d.
+func setDockerOptions(containerConfig *container.Config, hostConfig *container.HostConfig, annotations map[string]string) error {
+	capAdd, err := parseCapabilities(k2dtypes.CapAddAnnotationKey, annotations)
+	if err != nil {
+		return err
+	}
+	hostConfig.CapAdd = append(hostConfig.CapAdd, capAdd...)
+
+	capDrop, err := parseCapabilities(k2dtypes.CapDropAnnotationKey, annotations)
+	if err != nil {
+		return err
+	}
+	hostConfig.CapDrop = append(hostConfig.CapDrop, capDrop...)
+
+	for _, securityOpt := range splitAnnotationList(annotations[k2dtypes.SecurityOptAnnotationKey]) {
+		key, _, _ := strings.Cut(securityOpt, "=")
+		if !allowedSecurityOptions[key] {
+			return fmt.Errorf("invalid value for annotation %s: unsupported security option %q", k2dtypes.SecurityOptAnnotationKey, securityOpt)
+		}
+
+		hostConfig.SecurityOpt = append(hostConfig.SecurityOpt, securityOpt)
+	}
+
+	if networkMode := strings.TrimSpace(annotations[k2dtypes.NetworkModeAnnotationKey]); networkMode != "" {
+		mode := container.NetworkMode(networkMode)
+		if !mode.IsHost() && !mode.IsNone() && !mode.IsContainer() {
+			return fmt.Errorf("invalid value for annotation %s: unsupported network mode %q", k2dtypes.NetworkModeAnnotationKey, networkMode)
+		}
+
+		hostConfig.NetworkMode = mode
+		hostConfig.PortBindings = nil
+		containerConfig.ExposedPorts = nil
+
+		if mode.IsContainer() {
+			hostConfig.ExtraHosts = nil
+		}
+	}
+
+	for _, label := range splitAnnotationList(annotations[k2dtypes.LabelsAnnotationKey]) {
+		key, value, _ := strings.Cut(label, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return fmt.Errorf("invalid value for annotation %s: invalid label %q", k2dtypes.LabelsAnnotationKey, label)
+		}
+
+		if strings.Contains(key, "k2d.io/") {
+			return fmt.Errorf("invalid value for annotation %s: label %q is reserved", k2dtypes.LabelsAnnotationKey, key)
+		}
+
+		if containerConfig.Labels == nil {
+			containerConfig.Labels = map[string]string{}
+		}
+		containerConfig.Labels[key] = value
+	}
+
+	return nil
+}
+
+// allowedSecurityOptions are the security options (--security-opt) that can be specified through the
+// container.k2d.io/security-opt annotation, the value of an option is passed as is to the Docker daemon.
+var allowedSecurityOptions = map[string]bool{
+	"apparmor":          true,
+	"label":             true,
+	"no-new-privileges": true,
+	"seccomp":           true,
+	"systempaths":       true,
+}
+
+// linuxCapabilities are the Linux capabilities that can be added or dropped through the
+// container.k2d.io/cap-add and container.k2d.io/cap-drop annotations, in addition to ALL.
+var linuxCapabilities = map[string]bool{
+	"AUDIT_CONTROL": true, "AUDIT_READ": true, "AUDIT_WRITE": true, "BLOCK_SUSPEND": true, "BPF": true,
+	"CHECKPOINT_RESTORE": true, "CHOWN": true, "DAC_OVERRIDE": true, "DAC_READ_SEARCH": true, "FOWNER": true,
+	"FSETID": true, "IPC_LOCK": true, "IPC_OWNER": true, "KILL": true, "LEASE": true, "LINUX_IMMUTABLE": true,
+	"MAC_ADMIN": true, "MAC_OVERRIDE": true, "MKNOD": true, "NET_ADMIN": true, "NET_BIND_SERVICE": true,
+	"NET_BROADCAST": true, "NET_RAW": true, "PERFMON": true, "SETFCAP": true, "SETGID": true, "SETPCAP": true,
+	"SETUID": true, "SYSLOG": true, "SYS_ADMIN": true, "SYS_BOOT": true, "SYS_CHROOT": true, "SYS_MODULE": true,
+	"SYS_NICE": true, "SYS_PACCT": true, "SYS_PTRACE": true, "SYS_RAWIO": true, "SYS_RESOURCE": true,
+	"SYS_TIME": true, "SYS_TTY_CONFIG": true, "WAKE_ALARM": true,
+}
+
+// parseCapabilities returns the Linux capabilities listed in the specified annotation, with or without the CAP_ prefix
+// and case insensitive as accepted by Docker. It returns an error if one of the capabilities is unknown.
+func parseCapabilities(annotationKey string, annotations map[string]string) ([]string, error) {
+	capabilities := splitAnnotationList(annotations[annotationKey])
+
+	for _, capability := range capabilities {
+		name := strings.TrimPrefix(strings.ToUpper(capability), "CAP_")
+		if name != "ALL" && !linuxCapabilities[name] {
+			return nil, fmt.Errorf("invalid value for annotation %s: unknown capability %q", annotationKey, capability)
+		}
+	}
+
+	return capabilities, nil
+}
+
+// splitAnnotationList splits a comma separated annotation value, ignoring the empty items.
+func splitAnnotationList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// setShmSize configures the size of the /dev/shm mount of the Docker container based on the
+// container.k2d.io/shm-size annotation, using the Docker --shm-size format (e.g. 512m, 2g).
+// It returns an error if the size cannot be parsed.
+func setShmSize(hostConfig *container.HostConfig, annotations map[string]string) error {
+	shmSize := strings.TrimSpace(annotations[k2dtypes.ShmSizeAnnotationKey])
+	if shmSize == "" {
+		return nil
+	}
+
+	size, err := units.RAMInBytes(shmSize)
+	if err != nil || size <= 0 {
+		return fmt.Errorf("invalid value for annotation %s: %q", k2dtypes.ShmSizeAnnotationKey, shmSize)
+	}
+
+	hostConfig.ShmSize = size
+
+	return nil
+}
+
+// SetContainerLogConfig configures the logging driver of the Docker container and the rotation of its logs.
+// The k2d logging configuration can be overridden using the container.k2d.io/log-driver, container.k2d.io/log-max-size
+// and container.k2d.io/log-max-files annotations.
+// The rotation options are only applied with the json-file and local logging drivers, which are the only drivers
+// storing the logs on the host.
+// It returns an error if the maximum number of log files cannot be parsed.
+func (converter *DockerAPIConverter) SetContainerLogConfig(hostConfig *container.HostConfig, annotations map[string]string) error {
+	driver := converter.logOptions.Driver
+	if value := annotations[k2dtypes.LogDriverAnnotationKey]; value != "" {
+		driver = value
+	}
+
+	if driver == "" {
+		return nil
+	}
+
+	hostConfig.LogConfig = container.LogConfig{
+		Type:   driver,
+		Config: map[string]string{},
+	}
+
+	if driver != "json-file" && driver != "local" {
+		return nil
+	}
+
+	maxSize := converter.logOptions.MaxSize
+	if value := annotations[k2dtypes.LogMaxSizeAnnotationKey]; value != "" {
+		maxSize = value
+	}
+
+	maxFiles := converter.logOptions.MaxFiles
+	if value := annotations[k2dtypes.LogMaxFilesAnnotationKey]; value != "" {
+		parsedMaxFiles, err := strconv.Atoi(value)
+		if err != nil || parsedMaxFiles < 1 {
+			return fmt.Errorf("invalid value for annotation %s: %s", k2dtypes.LogMaxFilesAnnotationKey, value)
+		}
+		maxFiles = parsedMaxFiles
+	}
+
+	if maxSize != "" {
+		hostConfig.LogConfig.Config["max-size"] = maxSize
+	}
+
+	if maxFiles > 0 {
+		hostConfig.LogConfig.Config["max-file"] = strconv.Itoa(maxFiles)
+	}
+
+	return nil
+}
+
+// parseDevice parses a device using the Docker --device format into a DeviceMapping.
+// When not specified, the container path defaults to the host path and the cgroup permissions default to rwm.
+func parseDevice(device string) (container.DeviceMapping, error) {
+	parts := strings.Split(device, ":")
+	if len(parts) > 3 || parts[0] == "" {
+		return container.DeviceMapping{}, fmt.Errorf("invalid device specification: %s", device)
+	}
+
+	deviceMapping := container.DeviceMapping{
+		PathOnHost:        parts[0],
+		PathInContainer:   parts[0],
+		CgroupPermissions: "rwm",
+	}
+
+	if len(parts) > 1 && parts[1] != "" {
+		deviceMapping.PathInContainer = parts[1]
+	}
+
+	if len(parts) > 2 && parts[2] != "" {
+		deviceMapping.CgroupPermissions = parts[2]
+	}
+
+	return deviceMapping, nil
+}
+
 // SetServiceAccountTokenAndCACert configures the Docker container to have access to the service account token
 // and CA certificate stored in a Kubernetes Secret. The function performs the following steps:
 //  1. Fetches the service account Secret from Kubernetes using the provided secretStore.
@@ -252,7 +745,7 @@ func (converter *DockerAPIConverter) SetServiceAccountTokenAndCACert(hostConfig
 	}
 
 	for containerBind, hostBind := range binds {
-		bind := fmt.Sprintf("%s:%s", hostBind, path.Join("/var/run/secrets/kubernetes.io/serviceaccount/", containerBind))
+		bind := fmt.Sprintf("%s:%s:ro", hostBind, path.Join("/var/run/secrets/kubernetes.io/serviceaccount/", containerBind))
 		hostConfig.Binds = append(hostConfig.Binds, bind)
 	}
 
@@ -270,7 +763,7 @@ func (converter *DockerAPIConverter) setHostPorts(containerConfig *container.Con
 
 	for _, port := range ports {
 		if port.HostPort != 0 {
-			containerPort, err := nat.NewPort(string(port.Protocol), strconv.Itoa(int(port.ContainerPort)))
+			containerPort, err := newContainerPort(port.Protocol, port.ContainerPort)
 			if err != nil {
 				return err
 			}
@@ -421,6 +914,30 @@ func setRestartPolicy(hostConfig *container.HostConfig, restartPolicy core.Resta
 	}
 }
 
+// setDNSConfig configures the DNS settings of the Docker container based on the Kubernetes pod DNS policy and DNS configuration.
+// The ClusterFirst, ClusterFirstWithHostNet and Default policies keep the Docker embedded DNS server which resolves
+// container names and forwards other queries to the DNS servers of the host.
+// With the None policy, the DNS settings are exclusively taken from the pod DNS configuration, which is also
+// the case for the other policies as Docker does not define any DNS settings by default.
+// The nameservers, searches and options defined in the pod DNS configuration are applied to the container.
+func setDNSConfig(hostConfig *container.HostConfig, dnsConfig *core.PodDNSConfig) {
+	if dnsConfig == nil {
+		return
+	}
+
+	hostConfig.DNS = append(hostConfig.DNS, dnsConfig.Nameservers...)
+	hostConfig.DNSSearch = append(hostConfig.DNSSearch, dnsConfig.Searches...)
+
+	for _, option := range dnsConfig.Options {
+		if option.Value != nil {
+			hostConfig.DNSOptions = append(hostConfig.DNSOptions, fmt.Sprintf("%s:%s", option.Name, *option.Value))
+			continue
+		}
+
+		hostConfig.DNSOptions = append(hostConfig.DNSOptions, option.Name)
+	}
+}
+
 // setCommandAndArgs configures the entrypoint and command arguments for a given Docker container configuration.
 // If the 'command' slice is non-empty, it is set as the container's entrypoint.
 // If the 'args' slice is non-empty, it is set as the container's command arguments.
@@ -435,7 +952,7 @@ func setCommandAndArgs(containerConfig *container.Config, command []string, args
 }
 
 // setSecurityContext sets the user and group ID in the Docker container configuration based on the provided
-// Kubernetes PodSecurityContext.
+// Kubernetes PodSecurityContext. The sysctls defined in the PodSecurityContext are also applied to the container.
 // If no security context is provided, the function does not modify the container configuration.
 func setSecurityContext(config *container.Config, hostConfig *container.HostConfig, podSecurityContext *core.PodSecurityContext, containerSecurityContext *core.SecurityContext) {
 	if podSecurityContext == nil {
@@ -446,6 +963,13 @@ func setSecurityContext(config *container.Config, hostConfig *container.HostConf
 		config.User = fmt.Sprintf("%d:%d", *podSecurityContext.RunAsUser, *podSecurityContext.RunAsGroup)
 	}
 
+	if len(podSecurityContext.Sysctls) > 0 {
+		hostConfig.Sysctls = map[string]string{}
+		for _, sysctl := range podSecurityContext.Sysctls {
+			hostConfig.Sysctls[sysctl.Name] = sysctl.Value
+		}
+	}
+
 	if containerSecurityContext == nil {
 		return
 	}
@@ -473,7 +997,7 @@ func (converter *DockerAPIConverter) setVolumeMounts(namespace string, hostConfi
 }
 
 // handleVolumeSource configures the Docker host configuration's volume bindings based on a Kubernetes VolumeSource.
-// The VolumeSource can be of type ConfigMap, Secret, HostPath, or PersistentVolumeClaim.
+// The VolumeSource can be of type ConfigMap, Secret, HostPath, PersistentVolumeClaim, or EmptyDir using the Memory medium.
 //
 // Parameters:
 // - namespace:    The Kubernetes namespace where the volume resources (ConfigMap, Secret, or PersistentVolumeClaim) are located.
@@ -490,9 +1014,16 @@ func (converter *DockerAPIConverter) setVolumeMounts(namespace string, hostConfi
 //
 //  3. Appends these binds to the 'Binds' field of the Docker host configuration.
 //     - For HostPath:
-//     Directly appends a bind between the HostPath and the volume mount path to the Docker host configuration.
+//     Validates the host path according to its type (creating it when requested) and appends a bind
+//     between the HostPath and the volume mount path to the Docker host configuration, honoring the mount propagation
+//     of the volume mount.
 //     - For PersistentVolumeClaim:
 //     Utilizes the volume name and namespace to generate the volume name and appends a bind to the Docker host configuration.
+//     - For EmptyDir using the Memory medium:
+//     Mounts a tmpfs filesystem, bounded by the size limit of the volume, at the volume mount path.
+//
+// The binds are mounted read-only when the volume mount is read-only. ConfigMaps and Secrets are always retrieved from the
+// namespace of the pod, the stores never return a resource of another namespace.
 //
 // Returns:
 // - An error if the retrieval of the ConfigMap, Secret, or PersistentVolumeClaim fails, or if bind generation encounters issues.
@@ -509,7 +1040,7 @@ func (converter *DockerAPIConverter) handleVolumeSource(namespace string, hostCo
 			return fmt.Errorf("unable to get binds for configmap %s: %w", volume.VolumeSource.ConfigMap.Name, err)
 		}
 
-		handleStoreBinds(hostConfig, binds, volumeMount.MountPath)
+		handleStoreBinds(hostConfig, binds, volumeMount.MountPath, volumeMount.ReadOnly)
 	} else if volume.VolumeSource.Secret != nil {
 		secret, err := converter.secretStore.GetSecret(volume.VolumeSource.Secret.SecretName, namespace)
 		if err != nil {
@@ -518,21 +1049,47 @@ func (converter *DockerAPIConverter) handleVolumeSource(namespace string, hostCo
 
 		binds, err := converter.secretStore.GetSecretBinds(secret)
 		if err != nil {
-			return fmt.Errorf("unable to get binds for secrets %s: %w", volume.VolumeSource.ConfigMap.Name, err)
+			return fmt.Errorf("unable to get binds for secrets %s: %w", volume.VolumeSource.Secret.SecretName, err)
 		}
 
-		handleStoreBinds(hostConfig, binds, volumeMount.MountPath)
+		handleStoreBinds(hostConfig, binds, volumeMount.MountPath, volumeMount.ReadOnly)
 	} else if volume.HostPath != nil {
+		if err := prepareHostPath(volume.HostPath); err != nil {
+			return fmt.Errorf("invalid host path volume %s: %w", volume.Name, err)
+		}
+
 		bind := fmt.Sprintf("%s:%s", volume.HostPath.Path, volumeMount.MountPath)
-		hostConfig.Binds = append(hostConfig.Binds, bind)
+		hostConfig.Binds = append(hostConfig.Binds, bind+buildBindMode(volumeMount.ReadOnly, volumeMount.MountPropagation))
 	} else if volume.VolumeSource.PersistentVolumeClaim != nil {
 		volumeName := naming.BuildPersistentVolumeName(volume.VolumeSource.PersistentVolumeClaim.ClaimName, namespace)
 		bind := fmt.Sprintf("%s:%s", volumeName, volumeMount.MountPath)
-		hostConfig.Binds = append(hostConfig.Binds, bind)
+		hostConfig.Binds = append(hostConfig.Binds, bind+buildBindMode(volumeMount.ReadOnly, nil))
+	} else if volume.VolumeSource.EmptyDir != nil && volume.VolumeSource.EmptyDir.Medium == core.StorageMediumMemory {
+		setTmpfsMount(hostConfig, volume.VolumeSource.EmptyDir, volumeMount)
 	}
 	return nil
 }
 
+// setTmpfsMount mounts a tmpfs filesystem in the Docker container for an emptyDir volume using the Memory medium.
+// The size of the tmpfs is bounded by the size limit of the volume, if any.
+// Mounting such a volume on /dev/shm replaces the default /dev/shm mount of the container (e.g. to enlarge it).
+func setTmpfsMount(hostConfig *container.HostConfig, emptyDir *core.EmptyDirVolumeSource, volumeMount core.VolumeMount) {
+	options := []string{}
+	if emptyDir.SizeLimit != nil && !emptyDir.SizeLimit.IsZero() {
+		options = append(options, fmt.Sprintf("size=%d", emptyDir.SizeLimit.Value()))
+	}
+
+	if volumeMount.ReadOnly {
+		options = append(options, "ro")
+	}
+
+	if hostConfig.Tmpfs == nil {
+		hostConfig.Tmpfs = map[string]string{}
+	}
+
+	hostConfig.Tmpfs[volumeMount.MountPath] = strings.Join(options, ",")
+}
+
 // handleStoreBinds constructs bind mounts for Docker containers based on given host paths and container paths.
 // It appends these binds to the Binds field in the given HostConfig.
 //
@@ -555,17 +1112,46 @@ func (converter *DockerAPIConverter) handleVolumeSource(namespace string, hostCo
 // - hostConfig: The Docker container's host configuration where the bind mounts are appended.
 // - binds: A map where the key is the container path (containerBind) and the value is the host path (hostBind).
 // - mountPath: The target file in the container where the host files will be mounted.
+// - readOnly: Whether the host files are mounted read-only.
 //
 // Note:
 // - For disk backend, binds map entries would be like {"filename": "/path/on/host"}
 // - For volume backend, binds map entries would be like {"": "volumename"}
-func handleStoreBinds(hostConfig *container.HostConfig, binds map[string]string, mountPath string) {
+func handleStoreBinds(hostConfig *container.HostConfig, binds map[string]string, mountPath string, readOnly bool) {
 	for containerBind, hostBind := range binds {
 		bind := fmt.Sprintf("%s:%s", hostBind, path.Join(mountPath, containerBind))
 		if len(binds) == 1 && filepath.Ext(mountPath) != "" {
 			bind = fmt.Sprintf("%s:%s", hostBind, path.Join(filepath.Dir(mountPath), containerBind))
 		}
 
-		hostConfig.Binds = append(hostConfig.Binds, bind)
+		hostConfig.Binds = append(hostConfig.Binds, bind+buildBindMode(readOnly, nil))
 	}
 }
+
+// buildBindMode returns the mode appended to a Docker bind (e.g. ":ro,rslave") for a volume mount:
+//   - ro when the volume mount is read-only.
+//   - The propagation of the mount, only supported for host paths: rslave for HostToContainer and rshared for Bidirectional,
+//     None using the default rprivate propagation.
+//
+// It returns an empty string when the bind uses the default Docker mode (read-write, rprivate propagation).
+func buildBindMode(readOnly bool, mountPropagation *core.MountPropagationMode) string {
+	options := []string{}
+	if readOnly {
+		options = append(options, "ro")
+	}
+
+	if mountPropagation != nil {
+		switch *mountPropagation {
+		case core.MountPropagationHostToContainer:
+			options = append(options, "rslave")
+		case core.MountPropagationBidirectional:
+			options = append(options, "rshared")
+		}
+	}
+
+	if len(options) == 0 {
+		return ""
+	}
+
+	return ":" + strings.Join(options, ",")
+}
@@ -0,0 +1,162 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestConvertCPURequestToShares(t *testing.T) {
+	tests := []struct {
+		request  string
+		expected int64
+	}{
+		{request: "100m", expected: 102},
+		{request: "1", expected: 1024},
+		{request: "1m", expected: minCPUShares},
+		{request: "0", expected: minCPUShares},
+		{request: "300", expected: maxCPUShares},
+	}
+
+	for _, test := range tests {
+		t.Run(test.request, func(t *testing.T) {
+			shares := convertCPURequestToShares(resource.MustParse(test.request))
+			if shares != test.expected {
+				t.Errorf("expected %d shares, got %d", test.expected, shares)
+			}
+		})
+	}
+}
+
+func TestSetResourceRequirements(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources core.ResourceRequirements
+		expected  container.Resources
+	}{
+		{
+			name:      "no resources",
+			resources: core.ResourceRequirements{},
+			expected:  container.Resources{},
+		},
+		{
+			name: "requests and limits",
+			resources: core.ResourceRequirements{
+				Requests: core.ResourceList{core.ResourceCPU: resource.MustParse("100m"), core.ResourceMemory: resource.MustParse("64Mi")},
+				Limits:   core.ResourceList{core.ResourceCPU: resource.MustParse("500m"), core.ResourceMemory: resource.MustParse("128Mi")},
+			},
+			expected: container.Resources{CPUShares: 102, NanoCPUs: 500000000, Memory: 134217728, MemorySwap: 134217728, MemoryReservation: 67108864},
+		},
+		{
+			name: "minimum CPU limit",
+			resources: core.ResourceRequirements{
+				Limits: core.ResourceList{core.ResourceCPU: resource.MustParse("1m")},
+			},
+			expected: container.Resources{CPUShares: minCPUShares, NanoCPUs: minNanoCPUs},
+		},
+		{
+			name: "requests default to the limits",
+			resources: core.ResourceRequirements{
+				Limits: core.ResourceList{core.ResourceCPU: resource.MustParse("2"), core.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			expected: container.Resources{CPUShares: 2048, NanoCPUs: 2000000000, Memory: 1073741824, MemorySwap: 1073741824, MemoryReservation: 1073741824},
+		},
+		{
+			name: "memory reservation greater than the limit",
+			resources: core.ResourceRequirements{
+				Requests: core.ResourceList{core.ResourceMemory: resource.MustParse("256Mi")},
+				Limits:   core.ResourceList{core.ResourceMemory: resource.MustParse("128Mi")},
+			},
+			expected: container.Resources{Memory: 134217728, MemorySwap: 134217728, MemoryReservation: 134217728},
+		},
+		{
+			name: "request only",
+			resources: core.ResourceRequirements{
+				Requests: core.ResourceList{core.ResourceCPU: resource.MustParse("250m"), core.ResourceMemory: resource.MustParse("64Mi")},
+			},
+			expected: container.Resources{CPUShares: 256, MemoryReservation: 67108864},
+		},
+	}
+
+	converter := &DockerAPIConverter{}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hostConfig := &container.HostConfig{}
+			converter.setResourceRequirements(hostConfig, test.resources)
+
+			actual := hostConfig.Resources
+			if actual.CPUShares != test.expected.CPUShares || actual.NanoCPUs != test.expected.NanoCPUs ||
+				actual.Memory != test.expected.Memory || actual.MemorySwap != test.expected.MemorySwap ||
+				actual.MemoryReservation != test.expected.MemoryReservation {
+				t.Errorf("expected resources %+v, got %+v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSetResourceRequirementsGPU(t *testing.T) {
+	hostConfig := &container.HostConfig{}
+	converter := &DockerAPIConverter{}
+
+	converter.setResourceRequirements(hostConfig, core.ResourceRequirements{
+		Limits: core.ResourceList{nvidiaGPUResourceName: resource.MustParse("2")},
+	})
+
+	if len(hostConfig.DeviceRequests) != 1 || hostConfig.DeviceRequests[0].Driver != "nvidia" || hostConfig.DeviceRequests[0].Count != 2 {
+		t.Errorf("expected a request for 2 NVIDIA GPUs, got %+v", hostConfig.DeviceRequests)
+	}
+}
+
+func TestSetDockerOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		err         bool
+	}{
+		{name: "capabilities", annotations: map[string]string{k2dtypes.CapAddAnnotationKey: "NET_ADMIN,cap_sys_time", k2dtypes.CapDropAnnotationKey: "ALL"}},
+		{name: "unknown capability", annotations: map[string]string{k2dtypes.CapAddAnnotationKey: "NET_ADMIN,SUPERPOWER"}, err: true},
+		{name: "security options", annotations: map[string]string{k2dtypes.SecurityOptAnnotationKey: "no-new-privileges,apparmor=unconfined"}},
+		{name: "unsupported security option", annotations: map[string]string{k2dtypes.SecurityOptAnnotationKey: "credentialspec=file://spec.json"}, err: true},
+		{name: "unsupported network mode", annotations: map[string]string{k2dtypes.NetworkModeAnnotationKey: "bridge"}, err: true},
+		{name: "reserved label", annotations: map[string]string{k2dtypes.LabelsAnnotationKey: "k2d.io/workload=pod"}, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := setDockerOptions(&container.Config{}, &container.HostConfig{}, test.annotations)
+			if test.err != (err != nil) {
+				t.Errorf("expected error: %t, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestSetDockerOptionsNetworkMode(t *testing.T) {
+	for _, networkMode := range []string{"host", "none", "container:web"} {
+		t.Run(networkMode, func(t *testing.T) {
+			containerConfig := &container.Config{ExposedPorts: nat.PortSet{"80/tcp": {}}}
+			hostConfig := &container.HostConfig{
+				PortBindings: nat.PortMap{"80/tcp": {{HostPort: "8080"}}},
+				ExtraHosts:   []string{"kubernetes.default.svc:10.0.0.1"},
+			}
+
+			err := setDockerOptions(containerConfig, hostConfig, map[string]string{k2dtypes.NetworkModeAnnotationKey: networkMode})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(hostConfig.PortBindings) != 0 || len(containerConfig.ExposedPorts) != 0 {
+				t.Errorf("expected the port mappings to be removed, got %v and %v", hostConfig.PortBindings, containerConfig.ExposedPorts)
+			}
+
+			if hostConfig.NetworkMode.IsContainer() != (len(hostConfig.ExtraHosts) == 0) {
+				t.Errorf("expected the custom hosts to be removed with the container network mode only, got %v", hostConfig.ExtraHosts)
+			}
+		})
+	}
+}
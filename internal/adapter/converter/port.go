@@ -0,0 +1,36 @@
+package converter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-connections/nat"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// getProtocol returns the specified protocol, or TCP when it is not specified.
+func getProtocol(protocol core.Protocol) core.Protocol {
+	if protocol == "" {
+		return core.ProtocolTCP
+	}
+	return protocol
+}
+
+// newContainerPort returns the Docker port of a container port published using the specified protocol.
+// Docker expects a lowercase protocol and TCP is used when the protocol is not specified, as in Kubernetes.
+// TCP and UDP are supported. SCTP is rejected as its support depends on the kernel modules of the host and is not
+// available with rootless container runtimes.
+func newContainerPort(protocol core.Protocol, port int32) (nat.Port, error) {
+	protocol = getProtocol(protocol)
+
+	switch protocol {
+	case core.ProtocolTCP, core.ProtocolUDP:
+	case core.ProtocolSCTP:
+		return "", fmt.Errorf("unable to publish port %d: the SCTP protocol is not supported by k2d", port)
+	default:
+		return "", fmt.Errorf("unable to publish port %d: unsupported protocol %s", port, protocol)
+	}
+
+	return nat.NewPort(strings.ToLower(string(protocol)), strconv.Itoa(int(port)))
+}
@@ -0,0 +1,71 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestNewContainerPort(t *testing.T) {
+	tests := []struct {
+		protocol core.Protocol
+		expected nat.Port
+		err      bool
+	}{
+		{protocol: "", expected: "53/tcp"},
+		{protocol: core.ProtocolTCP, expected: "53/tcp"},
+		{protocol: core.ProtocolUDP, expected: "53/udp"},
+		{protocol: core.ProtocolSCTP, err: true},
+		{protocol: "QUIC", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.protocol), func(t *testing.T) {
+			port, err := newContainerPort(test.protocol, 53)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected an error, got %s", port)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if port != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, port)
+			}
+		})
+	}
+}
+
+func TestSetHostPortsUDP(t *testing.T) {
+	converter := &DockerAPIConverter{}
+	containerConfig, hostConfig := &container.Config{}, &container.HostConfig{}
+
+	err := converter.setHostPorts(containerConfig, hostConfig, []core.ContainerPort{
+		{ContainerPort: 53, HostPort: 53, Protocol: core.ProtocolUDP},
+		{ContainerPort: 53, HostPort: 53, Protocol: core.ProtocolTCP},
+		{ContainerPort: 8080},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, port := range []nat.Port{"53/udp", "53/tcp"} {
+		if _, exposed := containerConfig.ExposedPorts[port]; !exposed {
+			t.Errorf("expected port %s to be exposed", port)
+		}
+
+		if bindings := hostConfig.PortBindings[port]; len(bindings) != 1 || bindings[0].HostPort != "53" {
+			t.Errorf("expected port %s to be published on host port 53, got %v", port, bindings)
+		}
+	}
+
+	if len(hostConfig.PortBindings) != 2 {
+		t.Errorf("expected the ports without a host port not to be published, got %v", hostConfig.PortBindings)
+	}
+}
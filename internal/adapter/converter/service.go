@@ -1,8 +1,10 @@
 package converter
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -13,6 +15,15 @@ import (
 	"k8s.io/kubernetes/pkg/apis/core"
 )
 
+// defaultSessionAffinityTimeoutSeconds is the default timeout of the ClientIP session affinity of a service (3 hours)
+const defaultSessionAffinityTimeoutSeconds int32 = 10800
+
+// ConvertServiceSpecIntoContainerConfiguration publishes the ports of a NodePort or LoadBalancer service on the host
+// using the port bindings of the container matching the service. The target ports of the service can be named,
+// they are resolved from the ports of the pod spec of the container (see resolveServiceTargetPort).
+//
+// The ClientIP session affinity is supported as is: a service is backed by a single container, which means that
+// the requests of a client always reach the same backend.
 func (converter *DockerAPIConverter) ConvertServiceSpecIntoContainerConfiguration(serviceSpec core.ServiceSpec, containerCfg *ContainerConfiguration, usedPorts map[int]struct{}) error {
 	// if service type is not specified from the YAML file, we default to ClusterIP
 	if serviceSpec.Type == "" {
@@ -22,8 +33,15 @@ func (converter *DockerAPIConverter) ConvertServiceSpecIntoContainerConfiguratio
 	// portBindings forces a random high port to be used for a non-NodePort service
 	// hence, we need to check for the non-NodePort service type and assign the right ports to it
 	if serviceSpec.Type != core.ServiceTypeClusterIP {
+		containerPorts := getContainerPortsFromLabels(containerCfg.ContainerConfig.Labels)
+
 		for _, port := range serviceSpec.Ports {
-			containerPort, err := nat.NewPort(string(port.Protocol), port.TargetPort.String())
+			targetPort, err := resolveServiceTargetPort(port, containerPorts)
+			if err != nil {
+				return err
+			}
+
+			containerPort, err := newContainerPort(port.Protocol, targetPort)
 			if err != nil {
 				return fmt.Errorf("invalid container port: %w", err)
 			}
@@ -55,6 +73,12 @@ func (converter *DockerAPIConverter) ConvertServiceSpecIntoContainerConfiguratio
 	return nil
 }
 
+// GenerateRandomPort returns a random port of the NodePort range (30000-32767) that is not part of the used ports.
+// The returned port is added to the used ports.
+func (converter *DockerAPIConverter) GenerateRandomPort(usedPorts map[int]struct{}) (int, error) {
+	return converter.portGenerator.GenerateRandomPort(&usedPorts)
+}
+
 func (converter *DockerAPIConverter) UpdateServiceFromContainerInfo(service *core.Service, container types.Container) {
 	service.TypeMeta = metav1.TypeMeta{
 		Kind:       "Service",
@@ -73,14 +97,28 @@ func (converter *DockerAPIConverter) UpdateServiceFromContainerInfo(service *cor
 		service.Spec.Type = core.ServiceTypeClusterIP
 	}
 
+	setSessionAffinityDefaults(&service.Spec)
+
+	for i := range service.Spec.Ports {
+		service.Spec.Ports[i].Protocol = getProtocol(service.Spec.Ports[i].Protocol)
+	}
+
 	networkName := container.Labels[k2dtypes.NetworkNameLabelKey]
 	service.Spec.ClusterIPs = []string{container.NetworkSettings.Networks[networkName].IPAddress}
+	service.Spec.ClusterIP = service.Spec.ClusterIPs[0]
 
 	if service.Spec.Type != core.ServiceTypeClusterIP {
+		podContainerPorts := getContainerPortsFromLabels(container.Labels)
+
 		servicePorts := []core.ServicePort{}
 		for _, port := range service.Spec.Ports {
+			targetPort, err := resolveServiceTargetPort(port, podContainerPorts)
+			if err != nil {
+				continue
+			}
+
 			for _, containerPort := range container.Ports {
-				if port.TargetPort == intstr.Parse(strconv.Itoa(int(containerPort.PrivatePort))) {
+				if targetPort == int32(containerPort.PrivatePort) && strings.EqualFold(string(port.Protocol), containerPort.Type) {
 					if service.Spec.Type == core.ServiceTypeNodePort {
 						servicePorts = append(servicePorts, core.ServicePort{
 							Name:       port.Name,
@@ -99,9 +137,82 @@ func (converter *DockerAPIConverter) UpdateServiceFromContainerInfo(service *cor
 							TargetPort: port.TargetPort,
 						})
 					}
+
+					// a port published on both IPv4 and IPv6 is listed twice
+					break
 				}
 			}
 		}
 		service.Spec.Ports = servicePorts
 	}
 }
+
+// resolveServiceTargetPort returns the container port targeted by a service port.
+// As in Kubernetes, the port of the service is targeted when the target port is not specified, and a named target port
+// is resolved by looking up a port with the same name and protocol in the ports of the container.
+func resolveServiceTargetPort(port core.ServicePort, containerPorts []core.ContainerPort) (int32, error) {
+	if port.TargetPort.Type == intstr.Int {
+		if port.TargetPort.IntVal == 0 {
+			return port.Port, nil
+		}
+		return port.TargetPort.IntVal, nil
+	}
+
+	if port.TargetPort.StrVal == "" {
+		return port.Port, nil
+	}
+
+	if value, err := strconv.Atoi(port.TargetPort.StrVal); err == nil {
+		return int32(value), nil
+	}
+
+	protocol := getProtocol(port.Protocol)
+	for _, containerPort := range containerPorts {
+		if containerPort.Name == port.TargetPort.StrVal && getProtocol(containerPort.Protocol) == protocol {
+			return containerPort.ContainerPort, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unable to resolve target port %s of service port %d: no %s container port named %s", port.TargetPort.StrVal, port.Port, protocol, port.TargetPort.StrVal)
+}
+
+// getContainerPortsFromLabels returns the ports of the container defined in the pod spec stored in the labels of a container.
+// It returns nil when the container was not created from a pod spec.
+func getContainerPortsFromLabels(labels map[string]string) []core.ContainerPort {
+	podSpecData := labels[k2dtypes.PodLastAppliedConfigLabelKey]
+	if podSpecData == "" {
+		return nil
+	}
+
+	podSpec := core.PodSpec{}
+	if err := json.Unmarshal([]byte(podSpecData), &podSpec); err != nil || len(podSpec.Containers) == 0 {
+		return nil
+	}
+
+	return podSpec.Containers[0].Ports
+}
+
+// setSessionAffinityDefaults sets the default session affinity of a service and the default timeout
+// of the ClientIP session affinity, as the Kubernetes API server does.
+func setSessionAffinityDefaults(serviceSpec *core.ServiceSpec) {
+	if serviceSpec.SessionAffinity == "" {
+		serviceSpec.SessionAffinity = core.ServiceAffinityNone
+	}
+
+	if serviceSpec.SessionAffinity != core.ServiceAffinityClientIP {
+		return
+	}
+
+	if serviceSpec.SessionAffinityConfig == nil {
+		serviceSpec.SessionAffinityConfig = &core.SessionAffinityConfig{}
+	}
+
+	if serviceSpec.SessionAffinityConfig.ClientIP == nil {
+		serviceSpec.SessionAffinityConfig.ClientIP = &core.ClientIPConfig{}
+	}
+
+	if serviceSpec.SessionAffinityConfig.ClientIP.TimeoutSeconds == nil {
+		timeout := defaultSessionAffinityTimeoutSeconds
+		serviceSpec.SessionAffinityConfig.ClientIP.TimeoutSeconds = &timeout
+	}
+}
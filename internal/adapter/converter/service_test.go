@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestResolveServiceTargetPort(t *testing.T) {
+	containerPorts := []core.ContainerPort{
+		{Name: "http", ContainerPort: 8080},
+		{Name: "dns", ContainerPort: 5353, Protocol: core.ProtocolUDP},
+	}
+
+	tests := []struct {
+		name     string
+		port     core.ServicePort
+		expected int32
+		err      bool
+	}{
+		{name: "numeric target port", port: core.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)}, expected: 8080},
+		{name: "numeric target port as a string", port: core.ServicePort{Port: 80, TargetPort: intstr.FromString("9090")}, expected: 9090},
+		{name: "unspecified target port", port: core.ServicePort{Port: 80}, expected: 80},
+		{name: "named target port", port: core.ServicePort{Port: 80, TargetPort: intstr.FromString("http")}, expected: 8080},
+		{name: "named target port with a protocol", port: core.ServicePort{Port: 53, Protocol: core.ProtocolUDP, TargetPort: intstr.FromString("dns")}, expected: 5353},
+		{name: "named target port with another protocol", port: core.ServicePort{Port: 53, Protocol: core.ProtocolTCP, TargetPort: intstr.FromString("dns")}, err: true},
+		{name: "unknown named target port", port: core.ServicePort{Port: 80, TargetPort: intstr.FromString("metrics")}, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			port, err := resolveServiceTargetPort(test.port, containerPorts)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected an error, got port %d", port)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if port != test.expected {
+				t.Errorf("expected port %d, got %d", test.expected, port)
+			}
+		})
+	}
+}
+
+func TestSetSessionAffinityDefaults(t *testing.T) {
+	spec := core.ServiceSpec{}
+	setSessionAffinityDefaults(&spec)
+	if spec.SessionAffinity != core.ServiceAffinityNone || spec.SessionAffinityConfig != nil {
+		t.Errorf("expected the None session affinity without configuration, got %s", spec.SessionAffinity)
+	}
+
+	spec = core.ServiceSpec{SessionAffinity: core.ServiceAffinityClientIP}
+	setSessionAffinityDefaults(&spec)
+	if timeout := spec.SessionAffinityConfig.ClientIP.TimeoutSeconds; timeout == nil || *timeout != defaultSessionAffinityTimeoutSeconds {
+		t.Errorf("expected the default ClientIP timeout to be set, got %v", timeout)
+	}
+}
@@ -8,13 +8,24 @@ import (
 	"k8s.io/kubernetes/pkg/apis/storage"
 )
 
+// DefaultStorageClassName is the name of the built-in storage class, backed by the local Docker volume driver.
+// It is used by the persistent volume claims that do not specify a storage class.
+const DefaultStorageClassName = "local"
+
+// StorageClassDriverParameter is the name of the storage class parameter used to specify the Docker volume driver
+// used to create the volumes of the class. The other parameters of the class are passed as options to the driver.
+const StorageClassDriverParameter = "driver"
+
+// defaultVolumeDriver is the Docker volume driver used when a storage class does not specify a driver
+const defaultVolumeDriver = "local"
+
 func BuildDefaultStorageClass(startTime time.Time) storage.StorageClass {
 	reclaimPolicy := core.PersistentVolumeReclaimRetain
 	volumeBindingMode := storage.VolumeBindingWaitForFirstConsumer
 
 	return storage.StorageClass{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "local",
+			Name: DefaultStorageClassName,
 			Annotations: map[string]string{
 				"storageclass.kubernetes.io/is-default-class": "true",
 			},
@@ -31,3 +42,37 @@ func BuildDefaultStorageClass(startTime time.Time) storage.StorageClass {
 		VolumeBindingMode: &volumeBindingMode,
 	}
 }
+
+// GetStorageClassVolumeDriver returns the Docker volume driver and the driver options used to create the volumes of a storage class.
+// The driver is specified through the driver parameter of the class (local when not specified) and all the other parameters
+// are passed as driver options. For example, an NFS share can be mounted using the local driver with the following parameters:
+//
+//	parameters:
+//	  type: nfs
+//	  o: addr=192.168.1.10,rw,nfsvers=4
+//	  device: ":/exports/data"
+func GetStorageClassVolumeDriver(storageClass *storage.StorageClass) (string, map[string]string) {
+	driver := defaultVolumeDriver
+	driverOptions := map[string]string{}
+
+	for key, value := range storageClass.Parameters {
+		if key == StorageClassDriverParameter {
+			driver = value
+			continue
+		}
+
+		driverOptions[key] = value
+	}
+
+	return driver, driverOptions
+}
+
+// GetStorageClassReclaimPolicy returns the reclaim policy of the volumes provisioned with a storage class.
+// As with Kubernetes, the reclaim policy defaults to Delete when it is not specified in the storage class.
+func GetStorageClassReclaimPolicy(storageClass *storage.StorageClass) core.PersistentVolumeReclaimPolicy {
+	if storageClass.ReclaimPolicy == nil || *storageClass.ReclaimPolicy == "" {
+		return core.PersistentVolumeReclaimDelete
+	}
+
+	return *storageClass.ReclaimPolicy
+}
@@ -0,0 +1,732 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/k8s"
+	"github.com/portainer/k2d/pkg/filesystem"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// CustomResourcesFolder is the name of the directory, relative to the k2d data path, where the custom resource
+	// definitions and the custom resources are stored
+	CustomResourcesFolder = "customresources"
+
+	// customResourceDefinitionsFolder is the name of the directory, relative to the custom resources directory,
+	// where the custom resource definitions are stored. The name of a custom resource group always contains a dot,
+	// so it cannot conflict with the directory of a group.
+	customResourceDefinitionsFolder = "definitions"
+	// clusterScopedCustomResourcesFolder is the name of the directory used in place of the namespace directory to store
+	// the cluster scoped custom resources. Namespace names cannot contain an underscore.
+	clusterScopedCustomResourcesFolder = "_cluster"
+)
+
+// CreateCustomResourceDefinition stores a custom resource definition in the custom resources directory of the data path.
+// The definition is accepted as-is: k2d does not run any controller for the custom resources, which are stored and
+// served back opaquely (see CreateCustomResource). The definition is reported as established right away.
+// Creating a definition with the name of an existing definition replaces it.
+// It returns an ErrInvalidResource error if the names, the scope or the versions of the definition are invalid.
+func (adapter *KubeDockerAdapter) CreateCustomResourceDefinition(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) error {
+	err := validateCustomResourceDefinition(crd)
+	if err != nil {
+		return err
+	}
+
+	adapter.customResourceLock.Lock()
+	defer adapter.customResourceLock.Unlock()
+
+	storedVersions := []string{}
+
+	existingCRD, err := adapter.getCustomResourceDefinition(crd.Name)
+	if err == nil {
+		crd.UID = existingCRD.UID
+		crd.CreationTimestamp = existingCRD.CreationTimestamp
+		crd.Generation = existingCRD.Generation + 1
+		storedVersions = existingCRD.Status.StoredVersions
+	} else if errors.Is(err, adaptererr.ErrResourceNotFound) {
+		crd.UID = uuid.NewUUID()
+		crd.CreationTimestamp = metav1.NewTime(time.Now())
+		crd.Generation = 1
+	} else {
+		return err
+	}
+
+	for _, version := range crd.Spec.Versions {
+		if version.Storage && !containsString(storedVersions, version.Name) {
+			storedVersions = append(storedVersions, version.Name)
+		}
+	}
+
+	now := metav1.NewTime(time.Now())
+	crd.Status = apiextensionsv1.CustomResourceDefinitionStatus{
+		Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+			{
+				Type:               apiextensionsv1.NamesAccepted,
+				Status:             apiextensionsv1.ConditionTrue,
+				LastTransitionTime: now,
+				Reason:             "NoConflicts",
+				Message:            "no conflicts found",
+			},
+			{
+				Type:               apiextensionsv1.Established,
+				Status:             apiextensionsv1.ConditionTrue,
+				LastTransitionTime: now,
+				Reason:             "InitialNamesAccepted",
+				Message:            "the initial names have been accepted",
+			},
+		},
+		AcceptedNames:  crd.Spec.Names,
+		StoredVersions: storedVersions,
+	}
+	crd.TypeMeta = metav1.TypeMeta{
+		Kind:       "CustomResourceDefinition",
+		APIVersion: "apiextensions.k8s.io/v1",
+	}
+
+	return writeJSONFile(adapter.buildCustomResourceDefinitionPath(crd.Name), crd)
+}
+
+// DeleteCustomResourceDefinition removes a custom resource definition as well as all the custom resources
+// of the definition, as in Kubernetes.
+// It returns an ErrResourceNotFound error if the definition does not exist.
+func (adapter *KubeDockerAdapter) DeleteCustomResourceDefinition(ctx context.Context, crdName string) error {
+	adapter.customResourceLock.Lock()
+	defer adapter.customResourceLock.Unlock()
+
+	crd, err := adapter.getCustomResourceDefinition(crdName)
+	if err != nil {
+		return err
+	}
+
+	for _, version := range crd.Spec.Versions {
+		kindPath := adapter.buildCustomResourceKindPath(crd, version.Name)
+		// A definition stored before its kind was validated can resolve to another directory of the data path
+		if !adapter.isCustomResourceKindPath(kindPath) {
+			adapter.logger.Warnf("skipping the removal of the custom resources of version %s of %s, %s is not a custom resources directory",
+				version.Name, crdName, kindPath)
+			continue
+		}
+
+		err = os.RemoveAll(kindPath)
+		if err != nil {
+			return fmt.Errorf("unable to remove custom resources of version %s: %w", version.Name, err)
+		}
+	}
+
+	err = os.Remove(adapter.buildCustomResourceDefinitionPath(crdName))
+	if err != nil {
+		return fmt.Errorf("unable to remove custom resource definition file: %w", err)
+	}
+
+	return nil
+}
+
+func (adapter *KubeDockerAdapter) GetCustomResourceDefinition(ctx context.Context, crdName string) (*apiextensionsv1.CustomResourceDefinition, error) {
+	adapter.customResourceLock.RLock()
+	defer adapter.customResourceLock.RUnlock()
+
+	return adapter.getCustomResourceDefinition(crdName)
+}
+
+func (adapter *KubeDockerAdapter) ListCustomResourceDefinitions(ctx context.Context) (apiextensionsv1.CustomResourceDefinitionList, error) {
+	adapter.customResourceLock.RLock()
+	defer adapter.customResourceLock.RUnlock()
+
+	crds, err := adapter.listCustomResourceDefinitions()
+	if err != nil {
+		return apiextensionsv1.CustomResourceDefinitionList{}, fmt.Errorf("unable to list custom resource definitions: %w", err)
+	}
+
+	return apiextensionsv1.CustomResourceDefinitionList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CustomResourceDefinitionList",
+			APIVersion: "apiextensions.k8s.io/v1",
+		},
+		Items: crds,
+	}, nil
+}
+
+func (adapter *KubeDockerAdapter) GetCustomResourceDefinitionTable(ctx context.Context) (*metav1.Table, error) {
+	crdList, err := adapter.ListCustomResourceDefinitions(ctx)
+	if err != nil {
+		return &metav1.Table{}, err
+	}
+
+	objects := make([]metav1.Object, 0, len(crdList.Items))
+	for i := range crdList.Items {
+		objects = append(objects, &crdList.Items[i])
+	}
+
+	return k8s.GenerateMetadataTable("CustomResourceDefinition", "apiextensions.k8s.io/v1", objects, k8s.CreatedAtColumn), nil
+}
+
+// GetCustomResourceDefinitionForResource returns the custom resource definition serving a resource (plural name)
+// in a specific version of a group, used to route the requests targeting custom resources.
+// It returns an ErrResourceNotFound error if no definition serves the resource.
+func (adapter *KubeDockerAdapter) GetCustomResourceDefinitionForResource(ctx context.Context, group, version, resource string) (*apiextensionsv1.CustomResourceDefinition, error) {
+	adapter.customResourceLock.RLock()
+	defer adapter.customResourceLock.RUnlock()
+
+	crds, err := adapter.listCustomResourceDefinitions()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list custom resource definitions: %w", err)
+	}
+
+	for _, crd := range crds {
+		if crd.Spec.Group == group && crd.Spec.Names.Plural == resource && isCustomResourceVersionServed(&crd, version) {
+			return &crd, nil
+		}
+	}
+
+	return nil, adaptererr.ErrResourceNotFound
+}
+
+// ListCustomResourceAPIGroups returns the API groups of the custom resource definitions, used by the clients
+// to discover the custom resources. The storage version of the first definition of a group is used as the
+// preferred version of the group.
+func (adapter *KubeDockerAdapter) ListCustomResourceAPIGroups(ctx context.Context) ([]metav1.APIGroup, error) {
+	crdList, err := adapter.ListCustomResourceDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []metav1.APIGroup{}
+	groupIndexes := map[string]int{}
+
+	for _, crd := range crdList.Items {
+		index, exists := groupIndexes[crd.Spec.Group]
+		if !exists {
+			groups = append(groups, metav1.APIGroup{Name: crd.Spec.Group})
+			index = len(groups) - 1
+			groupIndexes[crd.Spec.Group] = index
+		}
+
+		for _, version := range crd.Spec.Versions {
+			if !version.Served {
+				continue
+			}
+
+			groupVersion := metav1.GroupVersionForDiscovery{
+				GroupVersion: crd.Spec.Group + "/" + version.Name,
+				Version:      version.Name,
+			}
+
+			if version.Storage && groups[index].PreferredVersion.Version == "" {
+				groups[index].PreferredVersion = groupVersion
+			}
+
+			if !containsGroupVersion(groups[index].Versions, groupVersion) {
+				groups[index].Versions = append(groups[index].Versions, groupVersion)
+			}
+		}
+	}
+
+	for i := range groups {
+		if groups[i].PreferredVersion.Version == "" && len(groups[i].Versions) > 0 {
+			groups[i].PreferredVersion = groups[i].Versions[0]
+		}
+	}
+
+	return groups, nil
+}
+
+// ListCustomResourceAPIResources returns the custom resources served in a specific version of a group.
+// It returns an ErrResourceNotFound error if no custom resource definition serves the version of the group.
+func (adapter *KubeDockerAdapter) ListCustomResourceAPIResources(ctx context.Context, group, version string) ([]metav1.APIResource, error) {
+	crdList, err := adapter.ListCustomResourceDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := []metav1.APIResource{}
+	for _, crd := range crdList.Items {
+		if crd.Spec.Group != group || !isCustomResourceVersionServed(&crd, version) {
+			continue
+		}
+
+		resources = append(resources, metav1.APIResource{
+			Name:         crd.Spec.Names.Plural,
+			SingularName: crd.Spec.Names.Singular,
+			Namespaced:   crd.Spec.Scope == apiextensionsv1.NamespaceScoped,
+			Kind:         crd.Spec.Names.Kind,
+			Verbs:        []string{"create", "delete", "get", "list", "patch", "update"},
+			ShortNames:   crd.Spec.Names.ShortNames,
+			Categories:   crd.Spec.Names.Categories,
+		})
+
+		if hasCustomResourceStatusSubresource(&crd, version) {
+			resources = append(resources, metav1.APIResource{
+				Name:       crd.Spec.Names.Plural + "/status",
+				Namespaced: crd.Spec.Scope == apiextensionsv1.NamespaceScoped,
+				Kind:       crd.Spec.Names.Kind,
+				Verbs:      []string{"get", "update"},
+			})
+		}
+	}
+
+	if len(resources) == 0 {
+		return nil, adaptererr.ErrResourceNotFound
+	}
+
+	return resources, nil
+}
+
+// CreateCustomResource stores a custom resource opaquely in the custom resources directory of the data path,
+// under the group, version and kind of the resource. No validation is performed against the schema of the
+// custom resource definition and no controller acts on the resource.
+// The resource version of the resource is initialized and incremented each time the resource is updated,
+// allowing the clients relying on optimistic concurrency (e.g. operators) to detect concurrent updates.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - crd: The custom resource definition of the resource.
+// - version: The version of the resource, as requested by the client.
+// - resource: The custom resource. Its namespace must be set for a namespaced resource.
+//
+// Returns:
+// - An ErrInvalidResource error if the name of the resource is invalid.
+// - An ErrResourceAlreadyExists error if a resource with the same name already exists.
+func (adapter *KubeDockerAdapter) CreateCustomResource(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, version string, resource *unstructured.Unstructured) error {
+	if resource.GetName() == "" && resource.GetGenerateName() != "" {
+		resource.SetName(resource.GetGenerateName() + utilrand.String(5))
+	}
+
+	err := prepareCustomResource(crd, version, resource)
+	if err != nil {
+		return err
+	}
+
+	adapter.customResourceLock.Lock()
+	defer adapter.customResourceLock.Unlock()
+
+	resourcePath := adapter.buildCustomResourcePath(crd, version, resource.GetNamespace(), resource.GetName())
+
+	exists, err := filesystem.FileExists(resourcePath)
+	if err != nil {
+		return fmt.Errorf("unable to check if custom resource %s exists: %w", resource.GetName(), err)
+	}
+
+	if exists {
+		return fmt.Errorf("%w: %s %s already exists", adaptererr.ErrResourceAlreadyExists, crd.Spec.Names.Singular, resource.GetName())
+	}
+
+	resource.SetUID(uuid.NewUUID())
+	resource.SetCreationTimestamp(metav1.NewTime(time.Now()))
+	resource.SetResourceVersion("1")
+	resource.SetGeneration(1)
+
+	return writeJSONFile(resourcePath, resource.Object)
+}
+
+// UpdateCustomResource replaces an existing custom resource.
+// When the resource version of the resource is set, it must match the resource version of the stored resource,
+// otherwise an ErrResourceConflict error is returned, as with the optimistic concurrency of the Kubernetes API.
+// The generation of the resource is incremented when its spec changes.
+//
+// When statusOnly is true, only the status of the stored resource is replaced, as with the status subresource.
+// Otherwise, the status of the stored resource is preserved if the custom resource definition enables the
+// status subresource.
+//
+// It returns an ErrResourceNotFound error if the resource does not exist.
+func (adapter *KubeDockerAdapter) UpdateCustomResource(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, version string, resource *unstructured.Unstructured, statusOnly bool) error {
+	err := prepareCustomResource(crd, version, resource)
+	if err != nil {
+		return err
+	}
+
+	adapter.customResourceLock.Lock()
+	defer adapter.customResourceLock.Unlock()
+
+	existingResource, err := adapter.getCustomResource(crd, version, resource.GetNamespace(), resource.GetName())
+	if err != nil {
+		return err
+	}
+
+	if resource.GetResourceVersion() != "" && resource.GetResourceVersion() != existingResource.GetResourceVersion() {
+		return fmt.Errorf("%w: the %s %s has been modified, please apply your changes to the latest version and try again",
+			adaptererr.ErrResourceConflict, crd.Spec.Names.Singular, resource.GetName())
+	}
+
+	if statusOnly {
+		status := resource.Object["status"]
+		resource.Object = existingResource.DeepCopy().Object
+		resource.Object["status"] = status
+	} else if hasCustomResourceStatusSubresource(crd, version) {
+		resource.Object["status"] = existingResource.Object["status"]
+	}
+
+	if resource.Object["status"] == nil {
+		delete(resource.Object, "status")
+	}
+
+	resourceVersion, err := strconv.ParseInt(existingResource.GetResourceVersion(), 10, 64)
+	if err != nil {
+		resourceVersion = 0
+	}
+
+	generation := existingResource.GetGeneration()
+	if !reflect.DeepEqual(resource.Object["spec"], existingResource.Object["spec"]) {
+		generation++
+	}
+
+	resource.SetUID(existingResource.GetUID())
+	resource.SetCreationTimestamp(existingResource.GetCreationTimestamp())
+	resource.SetResourceVersion(strconv.FormatInt(resourceVersion+1, 10))
+	resource.SetGeneration(generation)
+
+	return writeJSONFile(adapter.buildCustomResourcePath(crd, version, resource.GetNamespace(), resource.GetName()), resource.Object)
+}
+
+// DeleteCustomResource removes a custom resource.
+// It returns an ErrResourceNotFound error if the resource does not exist.
+func (adapter *KubeDockerAdapter) DeleteCustomResource(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, version, namespace, name string) error {
+	adapter.customResourceLock.Lock()
+	defer adapter.customResourceLock.Unlock()
+
+	err := os.Remove(adapter.buildCustomResourcePath(crd, version, namespace, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return adaptererr.ErrResourceNotFound
+		}
+		return fmt.Errorf("unable to remove custom resource file: %w", err)
+	}
+
+	return nil
+}
+
+func (adapter *KubeDockerAdapter) GetCustomResource(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, version, namespace, name string) (*unstructured.Unstructured, error) {
+	adapter.customResourceLock.RLock()
+	defer adapter.customResourceLock.RUnlock()
+
+	return adapter.getCustomResource(crd, version, namespace, name)
+}
+
+// ListCustomResources returns the custom resources of a namespace, or of all namespaces when the namespace is empty,
+// sorted by namespace and name. The namespace is ignored for the cluster scoped resources.
+func (adapter *KubeDockerAdapter) ListCustomResources(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, version, namespace string) (*unstructured.UnstructuredList, error) {
+	adapter.customResourceLock.RLock()
+	defer adapter.customResourceLock.RUnlock()
+
+	kindPath := adapter.buildCustomResourceKindPath(crd, version)
+
+	namespaceFolders := []string{}
+	switch {
+	case crd.Spec.Scope != apiextensionsv1.NamespaceScoped:
+		namespaceFolders = append(namespaceFolders, clusterScopedCustomResourcesFolder)
+	case namespace != "":
+		namespaceFolders = append(namespaceFolders, namespace)
+	default:
+		entries, err := os.ReadDir(kindPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("unable to read custom resources directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				namespaceFolders = append(namespaceFolders, entry.Name())
+			}
+		}
+	}
+
+	list := &unstructured.UnstructuredList{
+		Object: map[string]interface{}{
+			"kind":       crd.Spec.Names.ListKind,
+			"apiVersion": crd.Spec.Group + "/" + version,
+			"metadata":   map[string]interface{}{},
+		},
+		Items: []unstructured.Unstructured{},
+	}
+
+	if crd.Spec.Names.ListKind == "" {
+		list.SetKind(crd.Spec.Names.Kind + "List")
+	}
+
+	for _, namespaceFolder := range namespaceFolders {
+		entries, err := os.ReadDir(path.Join(kindPath, namespaceFolder))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("unable to read custom resources directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+				continue
+			}
+
+			resource := unstructured.Unstructured{}
+			err := readJSONFile(path.Join(kindPath, namespaceFolder, entry.Name()), &resource.Object)
+			if err != nil {
+				adapter.logger.Warnf("unable to read custom resource file %s: %s", entry.Name(), err)
+				continue
+			}
+
+			list.Items = append(list.Items, resource)
+		}
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		if list.Items[i].GetNamespace() != list.Items[j].GetNamespace() {
+			return list.Items[i].GetNamespace() < list.Items[j].GetNamespace()
+		}
+		return list.Items[i].GetName() < list.Items[j].GetName()
+	})
+
+	return list, nil
+}
+
+func (adapter *KubeDockerAdapter) GetCustomResourceTable(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, version, namespace string) (*metav1.Table, error) {
+	list, err := adapter.ListCustomResources(ctx, crd, version, namespace)
+	if err != nil {
+		return &metav1.Table{}, err
+	}
+
+	objects := make([]metav1.Object, 0, len(list.Items))
+	for i := range list.Items {
+		objects = append(objects, &list.Items[i])
+	}
+
+	return k8s.GenerateMetadataTable(crd.Spec.Names.Kind, crd.Spec.Group+"/"+version, objects, k8s.AgeColumn), nil
+}
+
+// IsCustomResourceNamespaced returns true if the custom resources of a definition are namespaced.
+func IsCustomResourceNamespaced(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	return crd.Spec.Scope == apiextensionsv1.NamespaceScoped
+}
+
+func (adapter *KubeDockerAdapter) getCustomResourceDefinition(crdName string) (*apiextensionsv1.CustomResourceDefinition, error) {
+	crd := apiextensionsv1.CustomResourceDefinition{}
+	err := readJSONFile(adapter.buildCustomResourceDefinitionPath(crdName), &crd)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, adaptererr.ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("unable to read custom resource definition %s: %w", crdName, err)
+	}
+
+	return &crd, nil
+}
+
+// listCustomResourceDefinitions returns the custom resource definitions sorted by name.
+func (adapter *KubeDockerAdapter) listCustomResourceDefinitions() ([]apiextensionsv1.CustomResourceDefinition, error) {
+	entries, err := os.ReadDir(path.Join(adapter.customResourcesPath, customResourceDefinitionsFolder))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []apiextensionsv1.CustomResourceDefinition{}, nil
+		}
+		return nil, fmt.Errorf("unable to read custom resource definitions directory: %w", err)
+	}
+
+	crds := []apiextensionsv1.CustomResourceDefinition{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		crd, err := adapter.getCustomResourceDefinition(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			adapter.logger.Warnf("unable to read custom resource definition file %s: %s", entry.Name(), err)
+			continue
+		}
+
+		crds = append(crds, *crd)
+	}
+
+	sort.Slice(crds, func(i, j int) bool {
+		return crds[i].Name < crds[j].Name
+	})
+
+	return crds, nil
+}
+
+func (adapter *KubeDockerAdapter) getCustomResource(crd *apiextensionsv1.CustomResourceDefinition, version, namespace, name string) (*unstructured.Unstructured, error) {
+	resource := unstructured.Unstructured{}
+	err := readJSONFile(adapter.buildCustomResourcePath(crd, version, namespace, name), &resource.Object)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, adaptererr.ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("unable to read custom resource %s: %w", name, err)
+	}
+
+	return &resource, nil
+}
+
+// Each custom resource definition is stored using the following path:
+// [data-path]/customresources/definitions/[crd-name].json
+func (adapter *KubeDockerAdapter) buildCustomResourceDefinitionPath(crdName string) string {
+	return path.Join(adapter.customResourcesPath, customResourceDefinitionsFolder, crdName+".json")
+}
+
+// The custom resources of a kind are stored under the following path:
+// [data-path]/customresources/[group]/[version]/[kind]
+func (adapter *KubeDockerAdapter) buildCustomResourceKindPath(crd *apiextensionsv1.CustomResourceDefinition, version string) string {
+	return path.Join(adapter.customResourcesPath, crd.Spec.Group, version, crd.Spec.Names.Kind)
+}
+
+// isCustomResourceKindPath returns true when a path built by buildCustomResourceKindPath is a [group]/[version]/[kind]
+// directory of the custom resources directory. It protects the removal of the custom resources of a definition
+// against names that would resolve to another directory of the data path.
+func (adapter *KubeDockerAdapter) isCustomResourceKindPath(kindPath string) bool {
+	relativePath, err := filepath.Rel(adapter.customResourcesPath, kindPath)
+	if err != nil {
+		return false
+	}
+
+	segments := strings.Split(filepath.ToSlash(relativePath), "/")
+	if len(segments) != 3 || segments[0] == customResourceDefinitionsFolder {
+		return false
+	}
+
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Each custom resource is stored using the following path:
+// [data-path]/customresources/[group]/[version]/[kind]/[namespace]/[name].json
+// The _cluster directory is used in place of the namespace for the cluster scoped resources.
+func (adapter *KubeDockerAdapter) buildCustomResourcePath(crd *apiextensionsv1.CustomResourceDefinition, version, namespace, name string) string {
+	if crd.Spec.Scope != apiextensionsv1.NamespaceScoped {
+		namespace = clusterScopedCustomResourcesFolder
+	}
+
+	return path.Join(adapter.buildCustomResourceKindPath(crd, version), namespace, name+".json")
+}
+
+// validateCustomResourceDefinition validates the names, the scope and the versions of a custom resource definition.
+// The schemas of the versions are not validated as they are not used by k2d.
+func validateCustomResourceDefinition(crd *apiextensionsv1.CustomResourceDefinition) error {
+	names := crd.Spec.Names
+
+	if !strings.Contains(crd.Spec.Group, ".") || len(validation.IsDNS1123Subdomain(crd.Spec.Group)) != 0 {
+		return fmt.Errorf("%w: spec.group %q must be a valid DNS subdomain containing at least one dot", adaptererr.ErrInvalidResource, crd.Spec.Group)
+	}
+
+	if names.Plural == "" || len(validation.IsDNS1035Label(names.Plural)) != 0 {
+		return fmt.Errorf("%w: spec.names.plural must be a valid DNS label", adaptererr.ErrInvalidResource)
+	}
+
+	// The kind is part of the path where the custom resources are stored, it is validated as in Kubernetes
+	if names.Kind == "" || len(validation.IsDNS1035Label(strings.ToLower(names.Kind))) != 0 {
+		return fmt.Errorf("%w: spec.names.kind %q must be a valid DNS label once lowercased", adaptererr.ErrInvalidResource, names.Kind)
+	}
+
+	if crd.Name != names.Plural+"."+crd.Spec.Group {
+		return fmt.Errorf("%w: metadata.name must be spec.names.plural+\".\"+spec.group (%s.%s)", adaptererr.ErrInvalidResource, names.Plural, crd.Spec.Group)
+	}
+
+	if crd.Spec.Scope != apiextensionsv1.NamespaceScoped && crd.Spec.Scope != apiextensionsv1.ClusterScoped {
+		return fmt.Errorf("%w: spec.scope must be either Namespaced or Cluster", adaptererr.ErrInvalidResource)
+	}
+
+	storageVersions := 0
+	for _, version := range crd.Spec.Versions {
+		if len(validation.IsDNS1035Label(version.Name)) != 0 {
+			return fmt.Errorf("%w: invalid version name %q", adaptererr.ErrInvalidResource, version.Name)
+		}
+
+		if version.Storage {
+			storageVersions++
+		}
+	}
+
+	if storageVersions != 1 {
+		return fmt.Errorf("%w: exactly one version must be marked as the storage version", adaptererr.ErrInvalidResource)
+	}
+
+	return nil
+}
+
+// prepareCustomResource validates the name of a custom resource and sets its API version and kind from its definition.
+// The namespace of a cluster scoped resource is cleared.
+func prepareCustomResource(crd *apiextensionsv1.CustomResourceDefinition, version string, resource *unstructured.Unstructured) error {
+	if errs := validation.IsDNS1123Subdomain(resource.GetName()); len(errs) != 0 {
+		return fmt.Errorf("%w: invalid name %q: %s", adaptererr.ErrInvalidResource, resource.GetName(), strings.Join(errs, ", "))
+	}
+
+	if crd.Spec.Scope != apiextensionsv1.NamespaceScoped {
+		resource.SetNamespace("")
+	}
+
+	resource.SetAPIVersion(crd.Spec.Group + "/" + version)
+	resource.SetKind(crd.Spec.Names.Kind)
+
+	return nil
+}
+
+func isCustomResourceVersionServed(crd *apiextensionsv1.CustomResourceDefinition, version string) bool {
+	for _, crdVersion := range crd.Spec.Versions {
+		if crdVersion.Name == version && crdVersion.Served {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasCustomResourceStatusSubresource(crd *apiextensionsv1.CustomResourceDefinition, version string) bool {
+	for _, crdVersion := range crd.Spec.Versions {
+		if crdVersion.Name == version {
+			return crdVersion.Subresources != nil && crdVersion.Subresources.Status != nil
+		}
+	}
+
+	return false
+}
+
+func containsGroupVersion(groupVersions []metav1.GroupVersionForDiscovery, groupVersion metav1.GroupVersionForDiscovery) bool {
+	for _, existingGroupVersion := range groupVersions {
+		if existingGroupVersion == groupVersion {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeJSONFile(filePath string, data interface{}) error {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("unable to marshal %s: %w", path.Base(filePath), err)
+	}
+
+	err = filesystem.CreateFileWithDirectories(filePath, content)
+	if err != nil {
+		return fmt.Errorf("unable to write %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+func readJSONFile(filePath string, data interface{}) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, data)
+}
@@ -0,0 +1,220 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"go.uber.org/zap"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestCustomResourceDefinition(kind string, scope apiextensionsv1.ResourceScope) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   "widgets",
+				Singular: "widget",
+				Kind:     kind,
+			},
+			Scope: scope,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true},
+			},
+		},
+	}
+}
+
+func TestValidateCustomResourceDefinitionKind(t *testing.T) {
+	for _, kind := range []string{"", "../../..", "../../definitions", "Wid/get", "Widget."} {
+		t.Run(kind, func(t *testing.T) {
+			err := validateCustomResourceDefinition(newTestCustomResourceDefinition(kind, apiextensionsv1.NamespaceScoped))
+			if !errors.Is(err, adaptererr.ErrInvalidResource) {
+				t.Errorf("expected an invalid resource error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDeleteCustomResourceDefinitionOutsideCustomResourcesPath(t *testing.T) {
+	dataPath := t.TempDir()
+	adapter := &KubeDockerAdapter{customResourcesPath: path.Join(dataPath, CustomResourcesFolder), logger: zap.NewNop().Sugar()}
+
+	// A definition stored before the kind was validated
+	crd := newTestCustomResourceDefinition("../../..", apiextensionsv1.NamespaceScoped)
+	err := writeJSONFile(adapter.buildCustomResourceDefinitionPath(crd.Name), crd)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	sentinelPath := path.Join(dataPath, "token")
+	err = os.WriteFile(sentinelPath, []byte("secret"), 0600)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = adapter.DeleteCustomResourceDefinition(context.Background(), crd.Name)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := os.Stat(sentinelPath); err != nil {
+		t.Errorf("expected the data path to be left untouched, got %v", err)
+	}
+
+	if _, err := adapter.getCustomResourceDefinition(crd.Name); !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		t.Errorf("expected the definition to be removed, got %v", err)
+	}
+}
+
+func TestValidateCustomResourceDefinition(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(crd *apiextensionsv1.CustomResourceDefinition)
+		valid  bool
+	}{
+		{name: "valid", modify: func(crd *apiextensionsv1.CustomResourceDefinition) {}, valid: true},
+		{name: "group without dot", modify: func(crd *apiextensionsv1.CustomResourceDefinition) { crd.Spec.Group = "example" }},
+		{name: "invalid plural", modify: func(crd *apiextensionsv1.CustomResourceDefinition) { crd.Spec.Names.Plural = "Widgets" }},
+		{name: "name mismatch", modify: func(crd *apiextensionsv1.CustomResourceDefinition) { crd.Name = "gadgets.example.com" }},
+		{name: "invalid scope", modify: func(crd *apiextensionsv1.CustomResourceDefinition) { crd.Spec.Scope = "Global" }},
+		{name: "invalid version", modify: func(crd *apiextensionsv1.CustomResourceDefinition) { crd.Spec.Versions[0].Name = "V1" }},
+		{name: "no storage version", modify: func(crd *apiextensionsv1.CustomResourceDefinition) { crd.Spec.Versions[0].Storage = false }},
+		{name: "several storage versions", modify: func(crd *apiextensionsv1.CustomResourceDefinition) {
+			crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: "v2", Served: true, Storage: true})
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			crd := newTestCustomResourceDefinition("Widget", apiextensionsv1.NamespaceScoped)
+			test.modify(crd)
+
+			err := validateCustomResourceDefinition(crd)
+			if test.valid && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if !test.valid && !errors.Is(err, adaptererr.ErrInvalidResource) {
+				t.Errorf("expected an invalid resource error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPrepareCustomResource(t *testing.T) {
+	clusterCRD := newTestCustomResourceDefinition("Widget", apiextensionsv1.ClusterScoped)
+
+	resource := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	resource.SetName("blue")
+	resource.SetNamespace("default")
+
+	err := prepareCustomResource(clusterCRD, "v1", resource)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if resource.GetAPIVersion() != "example.com/v1" || resource.GetKind() != "Widget" {
+		t.Errorf("expected the API version and kind to be set from the definition, got %s %s", resource.GetAPIVersion(), resource.GetKind())
+	}
+
+	if resource.GetNamespace() != "" {
+		t.Errorf("expected the namespace of a cluster scoped resource to be cleared, got %s", resource.GetNamespace())
+	}
+
+	for _, name := range []string{"", "Blue", "../blue"} {
+		invalid := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		invalid.SetName(name)
+
+		err := prepareCustomResource(clusterCRD, "v1", invalid)
+		if !errors.Is(err, adaptererr.ErrInvalidResource) {
+			t.Errorf("expected an invalid resource error for name %q, got %v", name, err)
+		}
+	}
+}
+
+func TestCustomResourceRoundTrip(t *testing.T) {
+	tests := []struct {
+		scope      apiextensionsv1.ResourceScope
+		namespaces []string
+	}{
+		{scope: apiextensionsv1.NamespaceScoped, namespaces: []string{"default", "edge"}},
+		{scope: apiextensionsv1.ClusterScoped, namespaces: []string{""}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.scope), func(t *testing.T) {
+			ctx := context.Background()
+			adapter := &KubeDockerAdapter{customResourcesPath: path.Join(t.TempDir(), CustomResourcesFolder), logger: zap.NewNop().Sugar()}
+
+			crd := newTestCustomResourceDefinition("Widget", test.scope)
+			err := adapter.CreateCustomResourceDefinition(ctx, crd)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			for _, namespace := range test.namespaces {
+				resource := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"color": "blue"}}}
+				resource.SetName("blue")
+				resource.SetNamespace(namespace)
+
+				err = adapter.CreateCustomResource(ctx, crd, "v1", resource)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			duplicate := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			duplicate.SetName("blue")
+			duplicate.SetNamespace(test.namespaces[0])
+			err = adapter.CreateCustomResource(ctx, crd, "v1", duplicate)
+			if !errors.Is(err, adaptererr.ErrResourceAlreadyExists) {
+				t.Errorf("expected an already exists error, got %v", err)
+			}
+
+			resource, err := adapter.GetCustomResource(ctx, crd, "v1", test.namespaces[0], "blue")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if resource.GetKind() != "Widget" || resource.GetResourceVersion() != "1" || resource.Object["spec"] == nil {
+				t.Errorf("unexpected custom resource: %v", resource.Object)
+			}
+
+			list, err := adapter.ListCustomResources(ctx, crd, "v1", "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(list.Items) != len(test.namespaces) || list.GetKind() != "WidgetList" {
+				t.Errorf("expected %d resources in a WidgetList, got %d in a %s", len(test.namespaces), len(list.Items), list.GetKind())
+			}
+
+			err = adapter.DeleteCustomResource(ctx, crd, "v1", test.namespaces[0], "blue")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			_, err = adapter.GetCustomResource(ctx, crd, "v1", test.namespaces[0], "blue")
+			if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+				t.Errorf("expected a not found error, got %v", err)
+			}
+
+			err = adapter.DeleteCustomResourceDefinition(ctx, crd.Name)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			list, err = adapter.ListCustomResources(ctx, crd, "v1", "")
+			if err != nil || len(list.Items) != 0 {
+				t.Errorf("expected the custom resources to be removed with their definition, got %d: %v", len(list.Items), err)
+			}
+		})
+	}
+}
@@ -17,11 +17,17 @@ import (
 )
 
 func (adapter *KubeDockerAdapter) CreateContainerFromDeployment(ctx context.Context, deployment *appsv1.Deployment) error {
+	// The labels of the pod template are updated with the k2d labels during the creation of the container
+	template := *deployment.Spec.Template.DeepCopy()
+
 	opts := ContainerCreationOptions{
+		annotations:   deployment.Spec.Template.Annotations,
 		containerName: deployment.Name,
 		namespace:     deployment.Namespace,
 		podSpec:       deployment.Spec.Template.Spec,
 		labels:        deployment.Spec.Template.Labels,
+		rollingUpdate: deployment.Spec.Strategy.Type != appsv1.RecreateDeploymentStrategyType &&
+			(deployment.Spec.Replicas == nil || *deployment.Spec.Replicas >= 1),
 	}
 
 	opts.labels[k2dtypes.WorkloadTypeLabelKey] = k2dtypes.DeploymentWorkloadType
@@ -46,7 +52,61 @@ func (adapter *KubeDockerAdapter) CreateContainerFromDeployment(ctx context.Cont
 
 	opts.lastAppliedConfiguration = deployment.ObjectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"]
 
-	return adapter.createContainerFromPodSpec(ctx, opts)
+	// As in Kubernetes, the pods of a deployment are labelled with the hash of their template,
+	// which matches the pod-template-hash label of the replica set of the revision (see recordDeploymentRevision)
+	templateHash, err := computeTemplateHash(template)
+	if err != nil {
+		return err
+	}
+	opts.labels[appsv1.DefaultDeploymentUniqueLabelKey] = templateHash
+
+	// The metadata is stored before the container is created as it is not part of the container configuration:
+	// the container is not re-created when only the metadata of the deployment changes
+	err = adapter.storeObjectMetadata("Deployment", deployment.ObjectMeta)
+	if err != nil {
+		adapter.logger.Warnf("unable to store the metadata of deployment %s: %s", deployment.Name, err)
+	}
+
+	if deployment.Spec.Paused {
+		return adapter.pauseDeployment(ctx, deployment, opts)
+	}
+
+	canaryWeight, isCanary, err := getCanaryWeight(deployment.Annotations)
+	if err != nil {
+		return err
+	}
+
+	if isCanary {
+		deployed, err := adapter.deployCanary(ctx, deployment, opts, canaryWeight)
+		if err != nil || deployed {
+			return err
+		}
+	}
+
+	err = adapter.resumeDeployment(ctx, deployment.Name, deployment.Namespace)
+	if err != nil {
+		return fmt.Errorf("unable to resume deployment %s: %w", deployment.Name, err)
+	}
+
+	err = adapter.createContainerFromPodSpec(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	// The canary, if any, is promoted once the deployment is fully rolled out
+	adapter.DeleteDeploymentCanary(ctx, deployment.Name, deployment.Namespace)
+
+	err = adapter.recordDeploymentRevision(deployment, template)
+	if err != nil {
+		adapter.logger.Warnf("unable to record the revision of deployment %s: %s", deployment.Name, err)
+	}
+
+	err = adapter.storeWorkloadRecord(k2dtypes.DeploymentWorkloadType, deployment.Name, deployment.Namespace, deployment)
+	if err != nil {
+		adapter.logger.Warnf("unable to store the definition of deployment %s, it will not be reconciled: %s", deployment.Name, err)
+	}
+
+	return nil
 }
 
 func (adapter *KubeDockerAdapter) getContainerFromDeploymentName(ctx context.Context, deploymentName, namespace string) (types.Container, error) {
@@ -124,6 +184,8 @@ func (adapter *KubeDockerAdapter) ListDeployments(ctx context.Context, namespace
 }
 
 func (adapter *KubeDockerAdapter) buildDeploymentFromContainer(container types.Container) (*apps.Deployment, error) {
+	container.Labels = adapter.resolveContainerLabels(container.ID, container.Labels)
+
 	if container.Labels[k2dtypes.LastAppliedConfigLabelKey] == "" {
 		return nil, fmt.Errorf("unable to build deployment, missing %s label on container %s", k2dtypes.LastAppliedConfigLabelKey, container.Names[0])
 	}
@@ -143,7 +205,20 @@ func (adapter *KubeDockerAdapter) buildDeploymentFromContainer(container types.C
 		return nil, fmt.Errorf("unable to convert versioned deployment spec to internal deployment spec: %w", err)
 	}
 
+	// The container of a paused deployment is stopped, the paused state is only recorded in the deployment record
+	if container.State != "running" {
+		record, err := adapter.getDeploymentRecord(deployment.Name, deployment.Namespace)
+		if err != nil {
+			adapter.logger.Warnf("unable to retrieve the definition of deployment %s: %s", deployment.Name, err)
+		}
+
+		if record != nil {
+			deployment.Spec.Paused = record.Spec.Paused
+		}
+	}
+
 	adapter.converter.UpdateDeploymentFromContainerInfo(&deployment, container)
+	adapter.restoreObjectMetadata("Deployment", &deployment.ObjectMeta)
 
 	return &deployment, nil
 }
@@ -0,0 +1,123 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/portainer/k2d/internal/adapter/converter"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"github.com/portainer/k2d/pkg/maputils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// The dry-run functions below are used to support the dryRun query parameter (e.g. kubectl diff, kubectl apply --dry-run=server).
+// They run the same conversions that are performed when the resource is created (versioned to internal object,
+// internal object to Docker configuration) and return any error that would prevent the resource from being created.
+// They never create, update or remove any Docker resource or store entry.
+
+// DryRunDeployment validates a deployment without creating the associated container.
+func (adapter *KubeDockerAdapter) DryRunDeployment(deployment *appsv1.Deployment) error {
+	internalDeployment := apps.Deployment{}
+	err := adapter.ConvertK8SResource(deployment, &internalDeployment)
+	if err != nil {
+		return fmt.Errorf("unable to convert versioned deployment to internal deployment: %w", err)
+	}
+
+	return adapter.dryRunPodSpec(deployment.Spec.Template.Spec, deployment.Namespace, deployment.Spec.Template.Labels, deployment.Spec.Template.Annotations)
+}
+
+// DryRunPod validates a pod without creating the associated container.
+func (adapter *KubeDockerAdapter) DryRunPod(pod *corev1.Pod) error {
+	internalPod := core.Pod{}
+	err := adapter.ConvertK8SResource(pod, &internalPod)
+	if err != nil {
+		return fmt.Errorf("unable to convert versioned pod to internal pod: %w", err)
+	}
+
+	return adapter.dryRunPodSpec(pod.Spec, pod.Namespace, pod.Labels, pod.Annotations)
+}
+
+// DryRunService validates a service without re-creating the container matching its selector.
+// Headless and ExternalName services are ignored by k2d and are always considered valid.
+func (adapter *KubeDockerAdapter) DryRunService(service *corev1.Service) error {
+	internalService := core.Service{}
+	err := adapter.ConvertK8SResource(service, &internalService)
+	if err != nil {
+		return fmt.Errorf("unable to convert versioned service to internal service: %w", err)
+	}
+
+	if service.Spec.ClusterIP == "None" || service.Spec.Type == corev1.ServiceTypeExternalName {
+		return nil
+	}
+
+	cfg := converter.ContainerConfiguration{
+		ContainerConfig: &container.Config{
+			ExposedPorts: nat.PortSet{},
+		},
+		HostConfig: &container.HostConfig{
+			PortBindings: nat.PortMap{},
+		},
+		NetworkConfig: &network.NetworkingConfig{},
+	}
+
+	err = adapter.converter.ConvertServiceSpecIntoContainerConfiguration(internalService.Spec, &cfg, map[int]struct{}{})
+	if err != nil {
+		return fmt.Errorf("unable to convert service spec into container configuration: %w", err)
+	}
+
+	return nil
+}
+
+// DryRunConfigMap validates a configmap without storing it.
+func (adapter *KubeDockerAdapter) DryRunConfigMap(configMap *corev1.ConfigMap) error {
+	internalConfigMap := core.ConfigMap{}
+	err := adapter.ConvertK8SResource(configMap, &internalConfigMap)
+	if err != nil {
+		return fmt.Errorf("unable to convert versioned configmap to internal configmap: %w", err)
+	}
+
+	return nil
+}
+
+// DryRunSecret validates a secret without storing it.
+func (adapter *KubeDockerAdapter) DryRunSecret(secret *corev1.Secret) error {
+	internalSecret := core.Secret{}
+	err := adapter.ConvertK8SResource(secret, &internalSecret)
+	if err != nil {
+		return fmt.Errorf("unable to convert versioned secret to internal secret: %w", err)
+	}
+
+	return nil
+}
+
+// dryRunPodSpec converts a pod spec into a Docker container configuration, as done in createContainerFromPodSpec.
+// The labels are copied so that the labels of the resource are not modified.
+func (adapter *KubeDockerAdapter) dryRunPodSpec(podSpec corev1.PodSpec, namespace string, labels, annotations map[string]string) error {
+	if len(podSpec.Containers) == 0 {
+		return errors.New("at least one container must be specified")
+	}
+
+	internalPodSpec := core.PodSpec{}
+	err := adapter.ConvertK8SResource(&podSpec, &internalPodSpec)
+	if err != nil {
+		return fmt.Errorf("unable to convert versioned pod spec to internal pod spec: %w", err)
+	}
+
+	containerLabels := map[string]string{}
+	maputils.MergeMapsInPlace(containerLabels, labels)
+	containerLabels[k2dtypes.NetworkNameLabelKey] = naming.BuildNetworkName(namespace)
+
+	_, err = adapter.converter.ConvertPodSpecToContainerConfiguration(internalPodSpec, namespace, containerLabels, annotations)
+	if err != nil {
+		return fmt.Errorf("unable to build container configuration from pod spec: %w", err)
+	}
+
+	return nil
+}
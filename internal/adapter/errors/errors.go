@@ -4,3 +4,18 @@ import "errors"
 
 // ErrResourceNotFound is an error returned when a Kubernetes resource is not found
 var ErrResourceNotFound = errors.New("resource not found")
+
+// ErrResourceAlreadyExists is an error returned when a Kubernetes resource already exists
+var ErrResourceAlreadyExists = errors.New("resource already exists")
+
+// ErrResourceConflict is an error returned when an operation conflicts with the current state of a Kubernetes resource
+var ErrResourceConflict = errors.New("resource conflict")
+
+// ErrInvalidResource is an error returned when a Kubernetes resource fails validation
+var ErrInvalidResource = errors.New("invalid resource")
+
+// ErrImagePull is an error returned when the image of a container cannot be pulled
+var ErrImagePull = errors.New("unable to pull image")
+
+// ErrUnschedulable is an error returned when a pod cannot be scheduled on the node (e.g. the node lacks the capacity requested by the pod)
+var ErrUnschedulable = errors.New("unschedulable")
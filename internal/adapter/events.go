@@ -2,15 +2,95 @@ package adapter
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/portainer/k2d/internal/k8s"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/kubernetes/pkg/apis/core"
 )
 
-func (adapter *KubeDockerAdapter) ListEvents() (corev1.EventList, error) {
-	eventList := adapter.listEvents()
+const (
+	// maxEvents is the maximum number of events retained in memory. The oldest events are discarded first.
+	maxEvents = 1000
+
+	// eventSourceComponent is the component reported as the source of the events recorded by k2d
+	eventSourceComponent = "k2d"
+)
+
+// eventStore is an in-memory store of the events recorded by k2d.
+// Events are not persisted and are lost when k2d restarts.
+type eventStore struct {
+	mu     sync.RWMutex
+	events []core.Event
+}
+
+func newEventStore() *eventStore {
+	return &eventStore{
+		events: []core.Event{},
+	}
+}
+
+// RecordEvent records an event associated with a Kubernetes object.
+// When an event with the same object, type, reason and message was already recorded, its count and
+// last timestamp are updated instead of recording a new event, as done by the Kubernetes event recorder.
+//
+// Parameters:
+// - involvedObject: A reference to the object the event is about.
+// - eventType: The type of the event (core.EventTypeNormal or core.EventTypeWarning).
+// - reason: A short, machine understandable reason (e.g. FailedCreate).
+// - message: A human readable description of the event.
+func (adapter *KubeDockerAdapter) RecordEvent(involvedObject core.ObjectReference, eventType, reason, message string) {
+	store := adapter.eventStore
+	now := metav1.NewTime(time.Now())
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for i := range store.events {
+		event := &store.events[i]
+		if event.InvolvedObject == involvedObject && event.Type == eventType && event.Reason == reason && event.Message == message {
+			event.Count++
+			event.LastTimestamp = now
+			return
+		}
+	}
+
+	namespace := involvedObject.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	store.events = append(store.events, core.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("%s.%s", involvedObject.Name, uuid.NewUUID()),
+			Namespace:         namespace,
+			CreationTimestamp: now,
+		},
+		InvolvedObject: involvedObject,
+		Type:           eventType,
+		Reason:         reason,
+		Message:        message,
+		Source: core.EventSource{
+			Component: eventSourceComponent,
+		},
+		Count:          1,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+	})
+
+	if len(store.events) > maxEvents {
+		store.events = store.events[len(store.events)-maxEvents:]
+	}
+}
+
+// ListEvents lists the events of a namespace (or of all the namespaces when the namespace is empty)
+// matching the specified field selector.
+func (adapter *KubeDockerAdapter) ListEvents(namespace string, selector fields.Selector) (corev1.EventList, error) {
+	eventList := adapter.listEvents(namespace, selector)
 
 	versionedEventList := corev1.EventList{
 		TypeMeta: metav1.TypeMeta{
@@ -27,17 +107,60 @@ func (adapter *KubeDockerAdapter) ListEvents() (corev1.EventList, error) {
 	return versionedEventList, nil
 }
 
-func (adapter *KubeDockerAdapter) GetEventTable() (*metav1.Table, error) {
-	eventList := adapter.listEvents()
+func (adapter *KubeDockerAdapter) GetEventTable(namespace string, selector fields.Selector) (*metav1.Table, error) {
+	eventList := adapter.listEvents(namespace, selector)
 	return k8s.GenerateTable(&eventList)
 }
 
-func (adapter *KubeDockerAdapter) listEvents() core.EventList {
+func (adapter *KubeDockerAdapter) listEvents(namespace string, selector fields.Selector) core.EventList {
+	store := adapter.eventStore
+
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	events := []core.Event{}
+	for _, event := range store.events {
+		if (namespace == "" || event.Namespace == namespace) && eventMatchesFieldSelector(event, selector) {
+			events = append(events, event)
+		}
+	}
+
 	return core.EventList{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "EventList",
 			APIVersion: "v1",
 		},
-		Items: []core.Event{},
+		Items: events,
+	}
+}
+
+// eventMatchesFieldSelector returns true when an event matches a field selector, such as the one used by kubectl describe
+// to retrieve the events of an object (e.g. involvedObject.name=nginx,involvedObject.namespace=default,involvedObject.kind=Pod).
+// The objects managed by k2d do not always have a UID: the involvedObject.uid field is ignored for the events
+// recorded without a UID.
+func eventMatchesFieldSelector(event core.Event, selector fields.Selector) bool {
+	if selector == nil || selector.Empty() {
+		return true
 	}
+
+	involvedObjectUID := string(event.InvolvedObject.UID)
+	if involvedObjectUID == "" {
+		involvedObjectUID, _ = selector.RequiresExactMatch("involvedObject.uid")
+	}
+
+	return selector.Matches(fields.Set{
+		"metadata.name":                  event.Name,
+		"metadata.namespace":             event.Namespace,
+		"involvedObject.kind":            event.InvolvedObject.Kind,
+		"involvedObject.namespace":       event.InvolvedObject.Namespace,
+		"involvedObject.name":            event.InvolvedObject.Name,
+		"involvedObject.uid":             involvedObjectUID,
+		"involvedObject.apiVersion":      event.InvolvedObject.APIVersion,
+		"involvedObject.resourceVersion": event.InvolvedObject.ResourceVersion,
+		"involvedObject.fieldPath":       event.InvolvedObject.FieldPath,
+		"reason":                         event.Reason,
+		"reportingComponent":             event.ReportingController,
+		"source":                         event.Source.Component,
+		"type":                           event.Type,
+	})
 }
@@ -0,0 +1,228 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/portainer/k2d/internal/adapter/converter"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+// ExportOptions represents the options used when exporting the resources managed by k2d
+type ExportOptions struct {
+	// Namespace restricts the export to the resources of a namespace, all namespaces are exported when empty
+	Namespace string
+	// RedactSecrets replaces the values of the secrets with empty values, the keys of the secrets are preserved
+	RedactSecrets bool
+}
+
+// ExportResources dumps the resources managed by k2d as a multi-document YAML manifest that can be re-applied
+// on a Kubernetes cluster (e.g. kubectl apply -f), to ease the migration of the workloads off k2d.
+// The documents are ordered so that each resource is applied after the resources it depends on:
+// namespaces, configmaps, secrets, persistent volume claims, deployments, pods and services.
+//
+// The resources are stripped of the fields populated by k2d so that they can be applied on another cluster:
+//   - The status and the server populated metadata (UID, resource version, creation timestamp, last applied configuration).
+//   - The node of the pods and the cluster IPs of the services.
+//   - The volume bound to the persistent volume claims, as well as the built-in local storage class so that the
+//     default storage class of the cluster is used.
+//
+// The resources of the k2d namespace and the pods created by deployments are not exported.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - opts: The export options.
+//
+// Returns:
+// - The YAML manifest.
+// - An error if a resource cannot be listed or serialized.
+func (adapter *KubeDockerAdapter) ExportResources(ctx context.Context, opts ExportOptions) ([]byte, error) {
+	objects := []interface{}{}
+
+	if opts.Namespace == "" {
+		namespaces, err := adapter.ListNamespaces(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list namespaces: %w", err)
+		}
+
+		for _, namespace := range namespaces.Items {
+			if namespace.Name == "default" || namespace.Name == k2dtypes.K2DNamespaceName {
+				continue
+			}
+
+			objects = append(objects, &corev1.Namespace{
+				TypeMeta:   metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"},
+				ObjectMeta: exportObjectMeta(namespace.ObjectMeta),
+			})
+		}
+	}
+
+	configMaps, err := adapter.ListConfigMaps(opts.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list configmaps: %w", err)
+	}
+
+	for _, configMap := range configMaps.Items {
+		if configMap.Namespace == k2dtypes.K2DNamespaceName {
+			continue
+		}
+
+		configMap.TypeMeta = metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"}
+		configMap.ObjectMeta = exportObjectMeta(configMap.ObjectMeta)
+		objects = append(objects, &configMap)
+	}
+
+	secrets, err := adapter.ListSecrets(opts.Namespace, labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("unable to list secrets: %w", err)
+	}
+
+	for _, secret := range secrets.Items {
+		if secret.Namespace == k2dtypes.K2DNamespaceName {
+			continue
+		}
+
+		secret.TypeMeta = metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"}
+		secret.ObjectMeta = exportObjectMeta(secret.ObjectMeta)
+		secret.StringData = nil
+
+		if opts.RedactSecrets {
+			for key := range secret.Data {
+				secret.Data[key] = []byte{}
+			}
+		}
+
+		objects = append(objects, &secret)
+	}
+
+	persistentVolumeClaims, err := adapter.ListPersistentVolumeClaims(ctx, opts.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list persistent volume claims: %w", err)
+	}
+
+	for _, persistentVolumeClaim := range persistentVolumeClaims.Items {
+		persistentVolumeClaim.TypeMeta = metav1.TypeMeta{Kind: "PersistentVolumeClaim", APIVersion: "v1"}
+		persistentVolumeClaim.ObjectMeta = exportObjectMeta(persistentVolumeClaim.ObjectMeta)
+		persistentVolumeClaim.Spec.VolumeName = ""
+		if persistentVolumeClaim.Spec.StorageClassName != nil && *persistentVolumeClaim.Spec.StorageClassName == converter.DefaultStorageClassName {
+			persistentVolumeClaim.Spec.StorageClassName = nil
+		}
+		persistentVolumeClaim.Status = corev1.PersistentVolumeClaimStatus{}
+		objects = append(objects, &persistentVolumeClaim)
+	}
+
+	deployments, err := adapter.ListDeployments(ctx, opts.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list deployments: %w", err)
+	}
+
+	deploymentPods := map[string]struct{}{}
+	for _, deployment := range deployments.Items {
+		deploymentPods[deployment.Namespace+"/"+deployment.Name] = struct{}{}
+
+		deployment.TypeMeta = metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}
+		ExportDeployment(&deployment, true)
+		objects = append(objects, &deployment)
+	}
+
+	pods, err := adapter.ListPods(ctx, opts.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list pods: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		// The pods created by a deployment share the name of the deployment
+		if _, isDeploymentPod := deploymentPods[pod.Namespace+"/"+pod.Name]; isDeploymentPod {
+			continue
+		}
+
+		pod.TypeMeta = metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"}
+		ExportPod(&pod, true)
+		objects = append(objects, &pod)
+	}
+
+	services, err := adapter.ListServices(ctx, opts.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list services: %w", err)
+	}
+
+	for _, service := range services.Items {
+		service.TypeMeta = metav1.TypeMeta{Kind: "Service", APIVersion: "v1"}
+		service.ObjectMeta = exportObjectMeta(service.ObjectMeta)
+		if service.Spec.ClusterIP != corev1.ClusterIPNone {
+			service.Spec.ClusterIP = ""
+			service.Spec.ClusterIPs = nil
+		}
+		service.Status = corev1.ServiceStatus{}
+		objects = append(objects, &service)
+	}
+
+	manifest := &bytes.Buffer{}
+	for _, object := range objects {
+		document, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal resource: %w", err)
+		}
+
+		manifest.WriteString("---\n")
+		manifest.Write(document)
+	}
+
+	return manifest.Bytes(), nil
+}
+
+// ExportPod strips a pod of the fields populated by k2d (status, node, server populated metadata and annotations)
+// so that it can be re-applied on another cluster, as done by ExportResources.
+// The namespace is removed as well when the export is not exact, as done by the export option of the Kubernetes API.
+func ExportPod(pod *corev1.Pod, exact bool) {
+	pod.ObjectMeta = exportObjectMeta(pod.ObjectMeta)
+	pod.Spec.NodeName = ""
+	pod.Status = corev1.PodStatus{}
+
+	if !exact {
+		pod.Namespace = ""
+	}
+}
+
+// ExportDeployment strips a deployment of the fields populated by k2d (status and server populated metadata)
+// so that it can be re-applied on another cluster, as done by ExportResources.
+// The namespace is removed as well when the export is not exact, as done by the export option of the Kubernetes API.
+func ExportDeployment(deployment *appsv1.Deployment, exact bool) {
+	deployment.ObjectMeta = exportObjectMeta(deployment.ObjectMeta)
+	deployment.Status = appsv1.DeploymentStatus{}
+
+	if !exact {
+		deployment.Namespace = ""
+	}
+}
+
+// exportObjectMeta returns the metadata of a resource stripped of the fields populated by k2d,
+// keeping only the fields that are meaningful when the resource is applied on another cluster.
+// The annotations reporting the state of a resource (e.g. the host ports allocated to a pod) are removed as well.
+func exportObjectMeta(objectMeta metav1.ObjectMeta) metav1.ObjectMeta {
+	exportedObjectMeta := metav1.ObjectMeta{
+		Name:      objectMeta.Name,
+		Namespace: objectMeta.Namespace,
+		Labels:    objectMeta.Labels,
+	}
+
+	for key, value := range objectMeta.Annotations {
+		if key == "kubectl.kubernetes.io/last-applied-configuration" || key == k2dtypes.AllocatedHostPortsAnnotationKey ||
+			key == k2dtypes.EphemeralStorageUsageAnnotationKey {
+			continue
+		}
+
+		if exportedObjectMeta.Annotations == nil {
+			exportedObjectMeta.Annotations = map[string]string{}
+		}
+		exportedObjectMeta.Annotations[key] = value
+	}
+
+	return exportedObjectMeta
+}
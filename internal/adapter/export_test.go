@@ -0,0 +1,57 @@
+package adapter
+
+import (
+	"testing"
+
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExportPod(t *testing.T) {
+	newPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "web",
+				Namespace:         "default",
+				UID:               "uid",
+				ResourceVersion:   "42",
+				CreationTimestamp: metav1.Now(),
+				Labels:            map[string]string{"app": "web"},
+				Annotations: map[string]string{
+					"kubectl.kubernetes.io/last-applied-configuration": "{}",
+					k2dtypes.AllocatedHostPortsAnnotationKey:           "8080/tcp=32768",
+					"team":                                             "platform",
+				},
+			},
+			Spec: corev1.PodSpec{NodeName: "k2d"},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{ContainerID: "docker://abc"}},
+			},
+		}
+	}
+
+	pod := newPod()
+	ExportPod(pod, true)
+
+	if pod.UID != "" || pod.ResourceVersion != "" || !pod.CreationTimestamp.IsZero() {
+		t.Errorf("expected the server populated metadata to be removed, got %+v", pod.ObjectMeta)
+	}
+	if pod.Spec.NodeName != "" || pod.Status.Phase != "" || len(pod.Status.ContainerStatuses) != 0 {
+		t.Errorf("expected the node and the status to be removed, got %+v", pod)
+	}
+	if len(pod.Annotations) != 1 || pod.Annotations["team"] != "platform" {
+		t.Errorf("expected only the user annotations to be kept, got %v", pod.Annotations)
+	}
+	if pod.Namespace != "default" || pod.Labels["app"] != "web" {
+		t.Errorf("expected the namespace and the labels to be kept, got %+v", pod.ObjectMeta)
+	}
+
+	pod = newPod()
+	ExportPod(pod, false)
+
+	if pod.Namespace != "" {
+		t.Errorf("expected the namespace to be removed when the export is not exact, got %s", pod.Namespace)
+	}
+}
@@ -157,3 +157,19 @@ func ByService(namespace, serviceName string) filters.Args {
 func AllPersistentVolumes() filters.Args {
 	return filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", types.StorageTypeLabelKey, types.PersistentVolumeStorageType)))
 }
+
+// ByVolume creates a Docker filter argument to target the containers using a specific Docker volume.
+//
+// Parameters:
+//   - volumeName: The name of the Docker volume to filter by.
+//
+// Returns:
+// - filters.Args: A Docker filter object to be used in Docker API calls to filter containers that mount the specified volume.
+//
+// Usage Example:
+//
+//	filter := ByVolume("k2d-pv-default-data")
+//	// Now 'filter' can be used in Docker API calls to filter the containers using the 'k2d-pv-default-data' volume.
+func ByVolume(volumeName string) filters.Args {
+	return filters.NewArgs(filters.Arg("volume", volumeName))
+}
@@ -0,0 +1,203 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// DeleteDependents garbage collects the resources of a namespace owned by a deleted resource, mirroring the background
+// cascading deletion of the Kubernetes garbage collector. A resource is a dependent of the deleted resource when one of its
+// owner references matches the kind and the name of the deleted resource, as k2d does not assign UIDs to the resources.
+// The dependents of the deleted dependents are deleted as well.
+//
+// The following kinds of dependents are supported: ConfigMap, Secret, Service, Deployment and Pod.
+// The owner references of the ConfigMaps and Secrets are persisted in their labels (see persistOwnerReferences) while
+// the owner references of the workloads and services are read from their last applied configuration.
+//
+// A failure to list or delete a dependent is logged and does not prevent the other dependents from being deleted.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - ownerKind: The kind of the deleted resource (e.g. Deployment).
+// - ownerName: The name of the deleted resource.
+// - namespace: The namespace of the deleted resource.
+func (adapter *KubeDockerAdapter) DeleteDependents(ctx context.Context, ownerKind, ownerName, namespace string) {
+	adapter.deleteDependents(ctx, ownerKind, ownerName, namespace, map[string]bool{})
+}
+
+// dependent represents a resource owned by another resource.
+type dependent struct {
+	kind string
+	name string
+}
+
+// deleteDependents deletes the dependents of a resource recursively. The visited map records the resources
+// already processed to prevent cycles between owner references from looping forever.
+func (adapter *KubeDockerAdapter) deleteDependents(ctx context.Context, ownerKind, ownerName, namespace string, visited map[string]bool) {
+	visited[ownerKind+"/"+ownerName] = true
+
+	for _, dependent := range adapter.findDependents(ctx, ownerKind, ownerName, namespace) {
+		if visited[dependent.kind+"/"+dependent.name] {
+			continue
+		}
+
+		adapter.logger.Infof("deleting %s %s/%s owned by %s %s", dependent.kind, namespace, dependent.name, ownerKind, ownerName)
+
+		err := adapter.deleteDependent(ctx, dependent, namespace)
+		if err != nil {
+			adapter.logger.Warnf("unable to delete %s %s/%s owned by %s %s: %s", dependent.kind, namespace, dependent.name, ownerKind, ownerName, err)
+			continue
+		}
+
+		adapter.deleteDependents(ctx, dependent.kind, dependent.name, namespace, visited)
+	}
+}
+
+// findDependents returns the resources of a namespace that reference the specified owner in their owner references.
+func (adapter *KubeDockerAdapter) findDependents(ctx context.Context, ownerKind, ownerName, namespace string) []dependent {
+	dependents := []dependent{}
+
+	configMapList, err := adapter.ListConfigMaps(namespace)
+	if err != nil {
+		adapter.logger.Warnf("unable to list configmaps: %s", err)
+	}
+	for _, configMap := range configMapList.Items {
+		if isOwnedBy(configMap.OwnerReferences, ownerKind, ownerName) {
+			dependents = append(dependents, dependent{kind: "ConfigMap", name: configMap.Name})
+		}
+	}
+
+	secretList, err := adapter.ListSecrets(namespace, labels.Everything())
+	if err != nil {
+		adapter.logger.Warnf("unable to list secrets: %s", err)
+	}
+	for _, secret := range secretList.Items {
+		if isOwnedBy(secret.OwnerReferences, ownerKind, ownerName) {
+			dependents = append(dependents, dependent{kind: "Secret", name: secret.Name})
+		}
+	}
+
+	serviceList, err := adapter.ListServices(ctx, namespace)
+	if err != nil {
+		adapter.logger.Warnf("unable to list services: %s", err)
+	}
+	for _, service := range serviceList.Items {
+		if isOwnedBy(getOwnerReferences("Service", service.ObjectMeta), ownerKind, ownerName) {
+			dependents = append(dependents, dependent{kind: "Service", name: service.Name})
+		}
+	}
+
+	deploymentList, err := adapter.ListDeployments(ctx, namespace)
+	if err != nil {
+		adapter.logger.Warnf("unable to list deployments: %s", err)
+	}
+	for _, deployment := range deploymentList.Items {
+		if isOwnedBy(getOwnerReferences("Deployment", deployment.ObjectMeta), ownerKind, ownerName) {
+			dependents = append(dependents, dependent{kind: "Deployment", name: deployment.Name})
+		}
+	}
+
+	podList, err := adapter.ListPods(ctx, namespace)
+	if err != nil {
+		adapter.logger.Warnf("unable to list pods: %s", err)
+	}
+	for _, pod := range podList.Items {
+		if isOwnedBy(getOwnerReferences("Pod", pod.ObjectMeta), ownerKind, ownerName) {
+			dependents = append(dependents, dependent{kind: "Pod", name: pod.Name})
+		}
+	}
+
+	return dependents
+}
+
+// deleteDependent deletes a dependent resource the same way the API deletes a resource of the same kind.
+func (adapter *KubeDockerAdapter) deleteDependent(ctx context.Context, dependent dependent, namespace string) error {
+	switch dependent.kind {
+	case "ConfigMap":
+		return adapter.DeleteConfigMap(dependent.name, namespace)
+	case "Secret":
+		return adapter.DeleteSecret(dependent.name, namespace)
+	case "Service":
+		return adapter.DeleteService(ctx, dependent.name, namespace)
+	case "Deployment":
+		adapter.DeleteDeploymentCanary(ctx, dependent.name, namespace)
+		adapter.DeleteContainer(ctx, dependent.name, namespace)
+		return nil
+	case "Pod":
+		return adapter.DeletePod(ctx, dependent.name, namespace)
+	default:
+		return fmt.Errorf("unsupported dependent kind: %s", dependent.kind)
+	}
+}
+
+// isOwnedBy returns true when one of the owner references matches the specified kind and name.
+func isOwnedBy(ownerReferences []metav1.OwnerReference, ownerKind, ownerName string) bool {
+	for _, ownerReference := range ownerReferences {
+		if ownerReference.Kind == ownerKind && ownerReference.Name == ownerName {
+			return true
+		}
+	}
+	return false
+}
+
+// getOwnerReferences returns the owner references of a workload or a service. They are read from the last applied
+// configuration of the resource when they are not part of its metadata.
+// The last applied configuration is ignored when it describes a resource of another kind, which is the case for the
+// pods created by a deployment.
+func getOwnerReferences(kind string, objectMeta metav1.ObjectMeta) []metav1.OwnerReference {
+	if len(objectMeta.OwnerReferences) != 0 {
+		return objectMeta.OwnerReferences
+	}
+
+	lastAppliedConfiguration := objectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"]
+	if lastAppliedConfiguration == "" {
+		return nil
+	}
+
+	object := metav1.PartialObjectMetadata{}
+	err := json.Unmarshal([]byte(lastAppliedConfiguration), &object)
+	if err != nil || object.Kind != kind {
+		return nil
+	}
+
+	return object.OwnerReferences
+}
+
+// persistOwnerReferences stores the owner references of a ConfigMap or a Secret in its labels, as the stores
+// only persist the labels of the resources. Any owner references label sent by the client is discarded.
+func persistOwnerReferences(objectMeta *metav1.ObjectMeta) error {
+	delete(objectMeta.Labels, k2dtypes.OwnerReferencesLabelKey)
+
+	if len(objectMeta.OwnerReferences) == 0 {
+		return nil
+	}
+
+	ownerReferences, err := json.Marshal(objectMeta.OwnerReferences)
+	if err != nil {
+		return fmt.Errorf("unable to marshal owner references: %w", err)
+	}
+
+	if objectMeta.Labels == nil {
+		objectMeta.Labels = map[string]string{}
+	}
+	objectMeta.Labels[k2dtypes.OwnerReferencesLabelKey] = string(ownerReferences)
+
+	return nil
+}
+
+// restoreOwnerReferences restores the owner references of a ConfigMap or a Secret persisted in its labels
+// (see persistOwnerReferences) and removes the associated label.
+func restoreOwnerReferences(objectMeta *metav1.ObjectMeta) {
+	ownerReferences, found := objectMeta.Labels[k2dtypes.OwnerReferencesLabelKey]
+	if !found {
+		return
+	}
+	delete(objectMeta.Labels, k2dtypes.OwnerReferencesLabelKey)
+
+	_ = json.Unmarshal([]byte(ownerReferences), &objectMeta.OwnerReferences)
+}
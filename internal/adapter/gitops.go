@@ -0,0 +1,236 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/config"
+	"github.com/portainer/k2d/pkg/filesystem"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// GitOpsFolder is the name of the directory, relative to the k2d data path, where the git repository
+	// synchronized by the GitOps agent is checked out.
+	GitOpsFolder = "gitops"
+
+	// gitOpsContainerPath is the path where the GitOps directory is mounted inside the git containers
+	gitOpsContainerPath = "/git"
+	// gitOpsRepositoryFolder is the name of the directory, relative to the GitOps directory, containing the checkout
+	gitOpsRepositoryFolder = "repository"
+	// gitOpsSSHKeyFile is the name of the file, relative to the GitOps directory, containing the SSH private key
+	gitOpsSSHKeyFile = "ssh-privatekey"
+)
+
+// GitRepositoryOptions represents the options used to synchronize a git repository
+type GitRepositoryOptions struct {
+	// URL is the URL of the git repository
+	URL string
+	// Branch is the branch of the git repository to check out
+	Branch string
+	// AuthSecretName is the name of the secret used to authenticate against the git repository, optional
+	AuthSecretName string
+	// AuthSecretNamespace is the namespace of the secret used to authenticate against the git repository
+	AuthSecretNamespace string
+	// ImageName is the name of the container image used to run the git commands
+	ImageName string
+}
+
+// SyncGitRepository checks out the latest revision of a branch of a git repository in the GitOps directory of the k2d data path.
+// As the k2d image does not ship a git client, the git commands are executed in short lived containers created from
+// the configured git image, mounting the GitOps directory. The k2d data path is expected to be located at the same path
+// on the host, as for the service account tokens mounted in the containers.
+//
+// The repository is initialized on the first synchronization. The branch is then fetched (shallow fetch) from the
+// repository URL on each synchronization and the checkout is reset to the fetched revision, removing any local change
+// as well as the files removed from the repository.
+//
+// The secret used to authenticate against the repository can either contain the username and password keys
+// (HTTP basic authentication, also compatible with access tokens) or the ssh-privatekey key (SSH authentication).
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - options: The options of the git repository.
+//
+// Returns:
+// - The path of the checkout of the repository.
+// - The revision (commit SHA) of the checkout.
+// - An error if the repository cannot be synchronized.
+func (adapter *KubeDockerAdapter) SyncGitRepository(ctx context.Context, options GitRepositoryOptions) (string, string, error) {
+	gitOpsPath := path.Join(adapter.dataPath, GitOpsFolder)
+	err := filesystem.CreateDir(gitOpsPath)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to create gitops directory: %w", err)
+	}
+
+	env, err := adapter.buildGitEnvironment(gitOpsPath, options)
+	if err != nil {
+		return "", "", err
+	}
+
+	repositoryPath := path.Join(gitOpsPath, gitOpsRepositoryFolder)
+	containerRepositoryPath := path.Join(gitOpsContainerPath, gitOpsRepositoryFolder)
+
+	_, err = os.Stat(path.Join(repositoryPath, ".git"))
+	if errors.Is(err, os.ErrNotExist) {
+		_, err = adapter.runGitCommand(ctx, options.ImageName, gitOpsPath, env, "init", "--quiet", containerRepositoryPath)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to initialize git repository: %w", err)
+		}
+	} else if err != nil {
+		return "", "", fmt.Errorf("unable to check for git repository existence: %w", err)
+	}
+
+	commands := [][]string{
+		{"-C", containerRepositoryPath, "fetch", "--quiet", "--depth", "1", options.URL, options.Branch},
+		{"-C", containerRepositoryPath, "reset", "--quiet", "--hard", "FETCH_HEAD"},
+		{"-C", containerRepositoryPath, "clean", "--quiet", "-ffdx"},
+	}
+
+	// The URL can contain credentials, it is redacted in the errors including in the output of git
+	redactedURL := config.RedactURLCredentials(options.URL)
+	for _, command := range commands {
+		_, err = adapter.runGitCommand(ctx, options.ImageName, gitOpsPath, env, command...)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to synchronize git repository %s: %s", redactedURL, strings.ReplaceAll(err.Error(), options.URL, redactedURL))
+		}
+	}
+
+	revision, err := adapter.runGitCommand(ctx, options.ImageName, gitOpsPath, env, "-C", containerRepositoryPath, "rev-parse", "HEAD")
+	if err != nil {
+		return "", "", fmt.Errorf("unable to retrieve git repository revision: %w", err)
+	}
+
+	return repositoryPath, strings.TrimSpace(revision), nil
+}
+
+// buildGitEnvironment returns the environment variables used to run the git commands, configuring git through
+// the GIT_CONFIG_* variables and the authentication against the repository using the specified secret.
+// The SSH private key of the secret is written in the GitOps directory so that it can be mounted in the git containers.
+func (adapter *KubeDockerAdapter) buildGitEnvironment(gitOpsPath string, options GitRepositoryOptions) ([]string, error) {
+	// the checkout is owned by the user running the git containers, which can differ from the owner of the data path
+	gitConfig := [][2]string{
+		{"safe.directory", "*"},
+	}
+	env := []string{}
+
+	if options.AuthSecretName != "" {
+		secret, err := adapter.GetSecret(options.AuthSecretName, options.AuthSecretNamespace)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve git authentication secret %s/%s: %w", options.AuthSecretNamespace, options.AuthSecretName, err)
+		}
+
+		switch {
+		case len(secret.Data[corev1.SSHAuthPrivateKey]) != 0:
+			err = os.WriteFile(path.Join(gitOpsPath, gitOpsSSHKeyFile), secret.Data[corev1.SSHAuthPrivateKey], 0600)
+			if err != nil {
+				return nil, fmt.Errorf("unable to write git SSH private key: %w", err)
+			}
+
+			keyPath := path.Join(gitOpsContainerPath, gitOpsSSHKeyFile)
+			knownHostsPath := path.Join(gitOpsContainerPath, "known_hosts")
+			env = append(env, fmt.Sprintf("GIT_SSH_COMMAND=ssh -i %s -o IdentitiesOnly=yes -o StrictHostKeyChecking=accept-new -o UserKnownHostsFile=%s", keyPath, knownHostsPath))
+		case len(secret.Data[corev1.BasicAuthPasswordKey]) != 0:
+			credentials := fmt.Sprintf("%s:%s", secret.Data[corev1.BasicAuthUsernameKey], secret.Data[corev1.BasicAuthPasswordKey])
+			gitConfig = append(gitConfig, [2]string{"http.extraHeader", "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))})
+		default:
+			return nil, fmt.Errorf("%w: git authentication secret %s/%s must contain either the %s key or the %s and %s keys", adaptererr.ErrInvalidResource,
+				options.AuthSecretNamespace, options.AuthSecretName, corev1.SSHAuthPrivateKey, corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey)
+		}
+	}
+
+	env = append(env, fmt.Sprintf("GIT_CONFIG_COUNT=%d", len(gitConfig)))
+	for i, entry := range gitConfig {
+		env = append(env, fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i, entry[0]), fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, entry[1]))
+	}
+
+	return env, nil
+}
+
+// runGitCommand runs a git command in a container created from the git image, mounting the GitOps directory.
+// The git image is pulled if it is not available locally. The container is removed once the command has exited.
+// It returns the standard output of the command, or an error including the output of the command if it fails.
+func (adapter *KubeDockerAdapter) runGitCommand(ctx context.Context, imageName, gitOpsPath string, env []string, args ...string) (string, error) {
+	_, _, err := adapter.cli.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		if !errdefs.IsNotFound(err) {
+			return "", fmt.Errorf("unable to inspect image %s: %w", imageName, err)
+		}
+
+		err = adapter.pullImage(ctx, imageName, "", nil)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	containerCreateResponse, err := adapter.cli.ContainerCreate(ctx,
+		&container.Config{
+			Image: imageName,
+			Cmd:   args,
+			Env:   env,
+		},
+		&container.HostConfig{
+			Binds: []string{fmt.Sprintf("%s:%s", gitOpsPath, gitOpsContainerPath)},
+		},
+		nil,
+		nil,
+		fmt.Sprintf("k2d-gitops-%d", time.Now().UnixNano()),
+	)
+	if err != nil {
+		return "", fmt.Errorf("unable to create git container: %w", err)
+	}
+
+	defer func() {
+		err := adapter.cli.ContainerRemove(context.Background(), containerCreateResponse.ID, types.ContainerRemoveOptions{Force: true})
+		if err != nil {
+			adapter.logger.Warnf("unable to remove git container %s: %s", containerCreateResponse.ID, err)
+		}
+	}()
+
+	err = adapter.cli.ContainerStart(ctx, containerCreateResponse.ID, types.ContainerStartOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to start git container: %w", err)
+	}
+
+	var exitCode int64
+	statusCh, errCh := adapter.cli.ContainerWait(ctx, containerCreateResponse.ID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errCh:
+		return "", fmt.Errorf("unable to wait for git container: %w", err)
+	case status := <-statusCh:
+		exitCode = status.StatusCode
+	}
+
+	logs, err := adapter.cli.ContainerLogs(ctx, containerCreateResponse.ID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve git container logs: %w", err)
+	}
+	defer logs.Close()
+
+	var stdout, stderr bytes.Buffer
+	_, err = stdcopy.StdCopy(&stdout, &stderr, logs)
+	if err != nil {
+		return "", fmt.Errorf("unable to read git container logs: %w", err)
+	}
+
+	if exitCode != 0 {
+		return "", fmt.Errorf("git %s exited with code %d: %s", args[len(args)-1], exitCode, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,296 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// hostPortAllocationAttempts is the maximum number of random ports tried when allocating a host port
+// before giving up, a port can be free for Docker while another process of the host listens on it.
+const hostPortAllocationAttempts = 50
+
+// hostPort is a port published on the host for a protocol (tcp, udp or sctp).
+type hostPort struct {
+	port     int
+	protocol string
+}
+
+func (p hostPort) String() string {
+	return fmt.Sprintf("%d/%s", p.port, strings.ToUpper(p.protocol))
+}
+
+func newHostPort(port int32, protocol core.Protocol) hostPort {
+	if protocol == "" {
+		protocol = core.ProtocolTCP
+	}
+
+	return hostPort{port: int(port), protocol: strings.ToLower(string(protocol))}
+}
+
+// hostPortUsage describes the host ports published by the containers of the Docker host.
+type hostPortUsage struct {
+	// owners associates the host ports to the name of the container publishing them
+	owners map[hostPort]string
+	// replaced associates the container ports of the replaced container to the host ports they are published on,
+	// see listHostPortUsage
+	replaced map[hostPort]int
+}
+
+// listHostPortUsage lists the host ports published by the running containers of the Docker host, including the containers
+// that are not managed by k2d. The ports published by the container with the specified name are not considered as used
+// as this container is replaced, they are returned separately so that they can be reused.
+func (adapter *KubeDockerAdapter) listHostPortUsage(ctx context.Context, replacedContainerName string) (hostPortUsage, error) {
+	containers, err := adapter.cli.ContainerList(ctx, types.ContainerListOptions{})
+	if err != nil {
+		return hostPortUsage{}, fmt.Errorf("unable to list containers: %w", err)
+	}
+
+	usage := hostPortUsage{
+		owners:   map[hostPort]string{},
+		replaced: map[hostPort]int{},
+	}
+
+	for _, container := range containers {
+		name := ""
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+
+		for _, port := range container.Ports {
+			if port.PublicPort == 0 {
+				continue
+			}
+
+			if replacedContainerName != "" && name == replacedContainerName {
+				usage.replaced[hostPort{port: int(port.PrivatePort), protocol: port.Type}] = int(port.PublicPort)
+				continue
+			}
+
+			usage.owners[hostPort{port: int(port.PublicPort), protocol: port.Type}] = name
+		}
+	}
+
+	return usage, nil
+}
+
+// isHostPortAvailable returns true when no process of the host listens on a port. It can only detect the listeners of the host
+// when k2d uses the network of the host, the ports published by Docker are detected using listHostPortUsage.
+func isHostPortAvailable(port hostPort) bool {
+	address := net.JoinHostPort("", strconv.Itoa(port.port))
+
+	switch port.protocol {
+	case "tcp":
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			return false
+		}
+		listener.Close()
+	case "udp":
+		conn, err := net.ListenPacket("udp", address)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+	}
+
+	return true
+}
+
+// findHostPortConflict returns a description of the first conflict between the requested host ports and the ports
+// published by the other containers or the listeners of the host. It returns an empty string when there is no conflict.
+// The ports already published by the replaced container are not probed as they are released when it is removed.
+func findHostPortConflict(requested []hostPort, usage hostPortUsage) string {
+	publishedByReplaced := map[hostPort]struct{}{}
+	for containerPort, port := range usage.replaced {
+		publishedByReplaced[hostPort{port: port, protocol: containerPort.protocol}] = struct{}{}
+	}
+
+	for _, port := range requested {
+		if owner, used := usage.owners[port]; used {
+			return fmt.Sprintf("host port %s is already used by container %s", port, owner)
+		}
+
+		if _, published := publishedByReplaced[port]; published {
+			continue
+		}
+
+		if !isHostPortAvailable(port) {
+			return fmt.Sprintf("host port %s is already used by another process of the host", port)
+		}
+	}
+
+	return ""
+}
+
+// checkPodHostPorts ensures that the host ports requested by a pod spec are free, as the Kubernetes scheduler does,
+// instead of letting Docker fail with an opaque error when the container is started.
+// A pod whose host ports are used is kept pending and a FailedScheduling event is recorded (see rejectPodSpec).
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - podSpec: The internal pod spec of the container to create.
+// - podName: The name of the pod, used to record the event.
+// - namespace: The namespace of the pod.
+// - replacedContainer: The container replaced by the new container, if any. Its ports are not considered as used.
+func (adapter *KubeDockerAdapter) checkPodHostPorts(ctx context.Context, podSpec core.PodSpec, podName, namespace string, replacedContainer *types.ContainerJSON) error {
+	requested := []hostPort{}
+	for _, port := range podSpec.Containers[0].Ports {
+		if port.HostPort != 0 {
+			requested = append(requested, newHostPort(port.HostPort, port.Protocol))
+		}
+	}
+
+	if len(requested) == 0 {
+		return nil
+	}
+
+	replacedContainerName := ""
+	if replacedContainer != nil {
+		replacedContainerName = strings.TrimPrefix(replacedContainer.Name, "/")
+	}
+
+	usage, err := adapter.listHostPortUsage(ctx, replacedContainerName)
+	if err != nil {
+		return err
+	}
+
+	conflict := findHostPortConflict(requested, usage)
+	if conflict == "" {
+		return nil
+	}
+
+	return adapter.rejectPodSpec(podName, namespace, fmt.Sprintf("0/1 nodes are available: 1 node(s) didn't have free ports for the requested pod ports (%s).", conflict))
+}
+
+// checkServiceHostPorts ensures that the node ports and the load balancer ports of a service are free before
+// the container matching the service is re-created to publish them.
+func (adapter *KubeDockerAdapter) checkServiceHostPorts(ctx context.Context, serviceSpec core.ServiceSpec, matchingContainer *types.Container) error {
+	requested := []hostPort{}
+	for _, port := range serviceSpec.Ports {
+		switch {
+		case serviceSpec.Type == core.ServiceTypeNodePort && port.NodePort != 0:
+			requested = append(requested, newHostPort(port.NodePort, port.Protocol))
+		case serviceSpec.Type == core.ServiceTypeLoadBalancer:
+			requested = append(requested, newHostPort(port.Port, port.Protocol))
+		}
+	}
+
+	if len(requested) == 0 {
+		return nil
+	}
+
+	usage, err := adapter.listHostPortUsage(ctx, strings.TrimPrefix(matchingContainer.Names[0], "/"))
+	if err != nil {
+		return err
+	}
+
+	if conflict := findHostPortConflict(requested, usage); conflict != "" {
+		return errors.New("unable to expose the service: " + conflict)
+	}
+
+	return nil
+}
+
+// allocateHostPorts publishes the container ports of a pod spec that do not define a host port on a free host port.
+// It is used when the pod is annotated with container.k2d.io/auto-host-ports=true. The host ports allocated to
+// the replaced container are reused so that the ports do not change each time the pod is updated.
+// The allocated ports are set in the pod spec, they are reported in the container.k2d.io/allocated-host-ports
+// annotation of the pod (see setAllocatedHostPortsAnnotation).
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - podSpec: The internal pod spec of the container to create, updated with the allocated ports.
+// - containerName: The name of the container, used to find the replaced container.
+func (adapter *KubeDockerAdapter) allocateHostPorts(ctx context.Context, podSpec *core.PodSpec, containerName string) error {
+	usage, err := adapter.listHostPortUsage(ctx, containerName)
+	if err != nil {
+		return err
+	}
+
+	usedPorts := map[int]struct{}{}
+	for port := range usage.owners {
+		usedPorts[port.port] = struct{}{}
+	}
+
+	ports := podSpec.Containers[0].Ports
+	for i := range ports {
+		if ports[i].HostPort != 0 {
+			usedPorts[int(ports[i].HostPort)] = struct{}{}
+		}
+	}
+
+	for i := range ports {
+		if ports[i].HostPort != 0 {
+			continue
+		}
+
+		containerPort := newHostPort(ports[i].ContainerPort, ports[i].Protocol)
+		if port, exists := usage.replaced[containerPort]; exists {
+			if _, used := usedPorts[port]; !used {
+				ports[i].HostPort = int32(port)
+				usedPorts[port] = struct{}{}
+				continue
+			}
+		}
+
+		port, err := adapter.generateFreeHostPort(containerPort.protocol, usedPorts)
+		if err != nil {
+			return fmt.Errorf("unable to allocate a host port for container port %s: %w", containerPort, err)
+		}
+		ports[i].HostPort = int32(port)
+	}
+
+	return nil
+}
+
+// generateFreeHostPort returns a random port of the NodePort range that is neither used by a container nor
+// by a listener of the host. The returned port is added to the used ports.
+func (adapter *KubeDockerAdapter) generateFreeHostPort(protocol string, usedPorts map[int]struct{}) (int, error) {
+	for attempt := 0; attempt < hostPortAllocationAttempts; attempt++ {
+		port, err := adapter.converter.GenerateRandomPort(usedPorts)
+		if err != nil {
+			return 0, err
+		}
+
+		if isHostPortAvailable(hostPort{port: port, protocol: protocol}) {
+			return port, nil
+		}
+	}
+
+	return 0, errors.New("no free port found")
+}
+
+// setAllocatedHostPortsAnnotation reports the host ports allocated to a pod annotated with
+// container.k2d.io/auto-host-ports=true in the container.k2d.io/allocated-host-ports annotation,
+// using the containerPort/protocol=hostPort format (e.g. 8080/TCP=30080).
+func setAllocatedHostPortsAnnotation(pod *core.Pod, ports []types.Port) {
+	if pod.Annotations[k2dtypes.AutoHostPortsAnnotationKey] != "true" {
+		return
+	}
+
+	allocated := map[string]struct{}{}
+	for _, port := range ports {
+		if port.PublicPort == 0 {
+			continue
+		}
+
+		allocated[fmt.Sprintf("%d/%s=%d", port.PrivatePort, strings.ToUpper(port.Type), port.PublicPort)] = struct{}{}
+	}
+
+	values := make([]string, 0, len(allocated))
+	for value := range allocated {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	pod.Annotations[k2dtypes.AllocatedHostPortsAnnotationKey] = strings.Join(values, ",")
+}
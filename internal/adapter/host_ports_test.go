@@ -0,0 +1,81 @@
+package adapter
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestFindHostPortConflict(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	defer listener.Close()
+
+	listenedPort := listener.Addr().(*net.TCPAddr).Port
+
+	usage := hostPortUsage{
+		owners: map[hostPort]string{
+			{port: 8080, protocol: "tcp"}: "default-web",
+		},
+		replaced: map[hostPort]int{
+			{port: 80, protocol: "tcp"}: listenedPort,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		port     hostPort
+		conflict string
+	}{
+		{name: "used by a container", port: hostPort{port: 8080, protocol: "tcp"}, conflict: "container default-web"},
+		{name: "same port with another protocol", port: hostPort{port: 8080, protocol: "udp"}},
+		{name: "published by the replaced container", port: hostPort{port: listenedPort, protocol: "tcp"}},
+		{name: "used by a listener of the host", port: hostPort{port: listenedPort, protocol: "tcp"}, conflict: "another process"},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testUsage := usage
+			if i == len(tests)-1 {
+				testUsage.replaced = map[hostPort]int{}
+			}
+
+			conflict := findHostPortConflict([]hostPort{test.port}, testUsage)
+			if test.conflict == "" && conflict != "" {
+				t.Errorf("expected no conflict, got %q", conflict)
+			}
+			if test.conflict != "" && !strings.Contains(conflict, test.conflict) {
+				t.Errorf("expected a conflict containing %q, got %q", test.conflict, conflict)
+			}
+		})
+	}
+}
+
+func TestSetAllocatedHostPortsAnnotation(t *testing.T) {
+	ports := []types.Port{
+		{PrivatePort: 8080, PublicPort: 30080, Type: "tcp"},
+		{PrivatePort: 8080, PublicPort: 30080, Type: "tcp", IP: "::"},
+		{PrivatePort: 5353, PublicPort: 31053, Type: "udp"},
+		{PrivatePort: 9090, Type: "tcp"},
+	}
+
+	pod := core.Pod{}
+	setAllocatedHostPortsAnnotation(&pod, ports)
+	if _, exists := pod.Annotations[k2dtypes.AllocatedHostPortsAnnotationKey]; exists {
+		t.Error("expected no annotation when the host ports are not allocated automatically")
+	}
+
+	pod.Annotations = map[string]string{k2dtypes.AutoHostPortsAnnotationKey: "true"}
+	setAllocatedHostPortsAnnotation(&pod, ports)
+
+	expected := "5353/UDP=31053,8080/TCP=30080"
+	if value := pod.Annotations[k2dtypes.AllocatedHostPortsAnnotationKey]; value != expected {
+		t.Errorf("expected %q, got %q", expected, value)
+	}
+}
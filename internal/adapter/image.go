@@ -0,0 +1,124 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/jsonmessage"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/registry"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// pullImage pulls an image using the specified registry credentials (base64-encoded JSON, empty for anonymous pulls).
+// When the image is hosted on a registry configured as insecure, it first ensures that the container runtime allows
+// insecure connections to this registry, as the pull itself cannot enable them. The CA bundles of the registries using a private CA are installed
+// in the certificates directory of the Docker daemon when the adapter is created (see registry.InstallCABundles).
+//
+// When the image is available in an image archive (see K2D_IMAGE_ARCHIVE_PATH), it is loaded from the archive instead
+// of being pulled. The image is pulled from its registry when the archive cannot be loaded.
+//
+// The progress of the pull is parsed and reported through structured log entries. When an involved object is specified
+// (e.g. the pod associated with the container), Pulling, Pulled and Failed events are also recorded against it, as done by the kubelet.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - image: The name of the image to pull.
+// - registryAuth: The registry credentials.
+// - involvedObject: The object the pull events are recorded against, nil to only log the progress.
+//
+// Returns:
+// - An error wrapping ErrImagePull if the image cannot be pulled.
+func (adapter *KubeDockerAdapter) pullImage(ctx context.Context, image, registryAuth string, involvedObject *core.ObjectReference) error {
+	recordEvent := func(eventType, reason, message string) {
+		if involvedObject != nil {
+			adapter.RecordEvent(*involvedObject, eventType, reason, message)
+		}
+	}
+
+	archivePath, err := adapter.loadImageFromArchive(ctx, image)
+	if err != nil {
+		adapter.logger.Warnw("unable to load image from archive, pulling the image from its registry",
+			"image", image,
+			"error", err,
+		)
+	} else if archivePath != "" {
+		adapter.logger.Debugw("image loaded from archive",
+			"image", image,
+			"archive", archivePath,
+		)
+
+		recordEvent(core.EventTypeNormal, "Pulled", fmt.Sprintf("Container image %q loaded from archive %s", image, filepath.Base(archivePath)))
+		return nil
+	}
+
+	recordEvent(core.EventTypeNormal, "Pulling", fmt.Sprintf("Pulling image %q", image))
+	start := time.Now()
+
+	err = adapter.pullImageAndReportProgress(ctx, image, registryAuth)
+	if err != nil {
+		recordEvent(core.EventTypeWarning, "Failed", fmt.Sprintf("Failed to pull image %q: %s", image, err))
+		return fmt.Errorf("%w %s: %w", adaptererr.ErrImagePull, image, err)
+	}
+
+	adapter.logger.Infow("image pulled",
+		"image", image,
+		"duration", time.Since(start).String(),
+	)
+
+	recordEvent(core.EventTypeNormal, "Pulled", fmt.Sprintf("Successfully pulled image %q in %s", image, time.Since(start).Round(time.Millisecond)))
+
+	return nil
+}
+
+// pullImageAndReportProgress pulls an image and parses the progress messages returned by the Docker API.
+// The status of each layer (e.g. Pull complete, Already exists) is logged at the debug level, the download and
+// extraction progress messages are discarded. It returns an error if the pull fails, including when the failure
+// is reported in the progress messages.
+func (adapter *KubeDockerAdapter) pullImageAndReportProgress(ctx context.Context, image, registryAuth string) error {
+	err := registry.CheckInsecureRegistry(ctx, adapter.cli, adapter.registryOptions, image)
+	if err != nil {
+		return err
+	}
+
+	out, err := adapter.cli.ImagePull(ctx, image, types.ImagePullOptions{
+		RegistryAuth: registryAuth,
+	})
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	decoder := json.NewDecoder(out)
+	for {
+		message := jsonmessage.JSONMessage{}
+		err := decoder.Decode(&message)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("unable to decode pull progress: %w", err)
+		}
+
+		if message.Error != nil {
+			return message.Error
+		}
+
+		if message.Progress != nil || strings.HasPrefix(message.Status, "Downloading") || strings.HasPrefix(message.Status, "Extracting") {
+			continue
+		}
+
+		adapter.logger.Debugw("image pull progress",
+			"image", image,
+			"layer", message.ID,
+			"status", message.Status,
+		)
+	}
+}
@@ -0,0 +1,236 @@
+package adapter
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/client"
+	"go.uber.org/zap"
+)
+
+// imageArchiveIndex maps the images available in the archives of the image archive directory to the path of their archive.
+// The images are indexed by their fully qualified name (see normalizeImageReference).
+type imageArchiveIndex map[string]string
+
+// isImageArchive returns true when a file of the image archive directory is an image archive, created with docker save
+// or in the OCI image layout format, optionally compressed with gzip.
+func isImageArchive(fileName string) bool {
+	return strings.HasSuffix(fileName, ".tar") || strings.HasSuffix(fileName, ".tar.gz") || strings.HasSuffix(fileName, ".tgz")
+}
+
+// scanImageArchives builds the index of the images available in the archives of a directory.
+// The archives that cannot be read are reported and skipped, so that a corrupted archive does not prevent k2d from starting.
+//
+// Parameters:
+// - logger: The logger used to report the archives that cannot be read.
+// - dirPath: The path to the image archive directory.
+//
+// Returns:
+// - The index of the images available in the archives.
+// - An error if the directory cannot be read.
+func scanImageArchives(logger *zap.SugaredLogger, dirPath string) (imageArchiveIndex, error) {
+	files, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read image archive directory: %w", err)
+	}
+
+	index := imageArchiveIndex{}
+	for _, file := range files {
+		if file.IsDir() || !isImageArchive(file.Name()) {
+			continue
+		}
+
+		archivePath := filepath.Join(dirPath, file.Name())
+		images, err := readImageArchiveReferences(archivePath)
+		if err != nil {
+			logger.Warnw("unable to read image archive, the archive is ignored",
+				"archive", archivePath,
+				"error", err,
+			)
+			continue
+		}
+
+		for _, image := range images {
+			index[normalizeImageReference(image)] = archivePath
+		}
+	}
+
+	return index, nil
+}
+
+// readImageArchiveReferences returns the names of the images contained in an image archive.
+// The names are read from the manifest.json file of the archives created with docker save,
+// and from the io.containerd.image.name annotation of the index.json file of the archives in the OCI image layout format.
+func readImageArchiveReferences(archivePath string) ([]string, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if !strings.HasSuffix(archivePath, ".tar") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decompress archive: %w", err)
+		}
+		defer gzipReader.Close()
+
+		reader = gzipReader
+	}
+
+	images := []string{}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read archive: %w", err)
+		}
+
+		switch path.Clean(header.Name) {
+		case "manifest.json":
+			manifest := []struct {
+				RepoTags []string `json:"RepoTags"`
+			}{}
+
+			err := json.NewDecoder(tarReader).Decode(&manifest)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode manifest.json: %w", err)
+			}
+
+			for _, entry := range manifest {
+				images = append(images, entry.RepoTags...)
+			}
+		case "index.json":
+			index := struct {
+				Manifests []struct {
+					Annotations map[string]string `json:"annotations"`
+				} `json:"manifests"`
+			}{}
+
+			err := json.NewDecoder(tarReader).Decode(&index)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode index.json: %w", err)
+			}
+
+			for _, manifest := range index.Manifests {
+				if name := manifest.Annotations["io.containerd.image.name"]; name != "" {
+					images = append(images, name)
+				}
+			}
+		}
+	}
+
+	return images, nil
+}
+
+// normalizeImageReference returns the fully qualified name of an image (e.g. nginx becomes docker.io/library/nginx:latest)
+// so that the names used in the manifests match the names recorded in the archives.
+// The name is returned unchanged when it cannot be parsed.
+func normalizeImageReference(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+
+	return reference.TagNameOnly(named).String()
+}
+
+// images returns the fully qualified names of the images available in the archives, in alphabetical order.
+func (index imageArchiveIndex) images() []string {
+	images := make([]string, 0, len(index))
+	for image := range index {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+
+	return images
+}
+
+// loadImageArchives loads the archives of the image archive directory on startup, so that the images they contain
+// are available without a registry, including the images used by k2d (e.g. the volume copy image).
+// An archive is only loaded when one of its images is not available in the cache of the container runtime.
+// The archives that cannot be loaded are reported, the images they contain are pulled from their registry when used.
+func loadImageArchives(ctx context.Context, cli *client.Client, logger *zap.SugaredLogger, index imageArchiveIndex) {
+	archives := map[string]bool{}
+	for image, archivePath := range index {
+		if _, _, err := cli.ImageInspectWithRaw(ctx, image); err != nil {
+			archives[archivePath] = true
+		}
+	}
+
+	for archivePath := range archives {
+		err := loadImageArchive(ctx, cli, archivePath)
+		if err != nil {
+			logger.Warnw("unable to load image archive",
+				"archive", archivePath,
+				"error", err,
+			)
+			continue
+		}
+
+		logger.Infow("image archive loaded",
+			"archive", archivePath,
+		)
+	}
+}
+
+// loadImageArchive loads the images contained in an archive in the container runtime (docker load).
+func loadImageArchive(ctx context.Context, cli *client.Client, archivePath string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	response, err := cli.ImageLoad(ctx, file, true)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	_, err = io.Copy(io.Discard, response.Body)
+	return err
+}
+
+// loadImageFromArchive makes an image available from the archive containing it, instead of pulling it from its registry.
+// The archive is loaded again when the image was removed from the cache of the container runtime since k2d started.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - image: The name of the image.
+//
+// Returns:
+// - The path to the archive containing the image, empty when no archive contains the image.
+// - An error if the archive cannot be loaded.
+func (adapter *KubeDockerAdapter) loadImageFromArchive(ctx context.Context, image string) (string, error) {
+	archivePath, found := adapter.imageArchives[normalizeImageReference(image)]
+	if !found {
+		return "", nil
+	}
+
+	if _, _, err := adapter.cli.ImageInspectWithRaw(ctx, image); err == nil {
+		return archivePath, nil
+	}
+
+	err := loadImageArchive(ctx, adapter.cli, archivePath)
+	if err != nil {
+		return archivePath, fmt.Errorf("unable to load image archive %s: %w", filepath.Base(archivePath), err)
+	}
+
+	return archivePath, nil
+}
@@ -0,0 +1,84 @@
+package adapter
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func writeTestImageArchive(t *testing.T, archivePath string, files map[string]string) {
+	t.Helper()
+
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var writer io.Writer = file
+	if filepath.Ext(archivePath) == ".tgz" {
+		gzipWriter := gzip.NewWriter(file)
+		defer gzipWriter.Close()
+		writer = gzipWriter
+	}
+
+	tarWriter := tar.NewWriter(writer)
+	defer tarWriter.Close()
+
+	for name, content := range files {
+		err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScanImageArchives(t *testing.T) {
+	dirPath := t.TempDir()
+
+	writeTestImageArchive(t, filepath.Join(dirPath, "nginx.tar"), map[string]string{
+		"manifest.json": `[{"Config":"config.json","RepoTags":["nginx:1.25","nginx:latest"],"Layers":[]}]`,
+	})
+	writeTestImageArchive(t, filepath.Join(dirPath, "app.tgz"), map[string]string{
+		"oci-layout": `{"imageLayoutVersion":"1.0.0"}`,
+		"index.json": `{"manifests":[{"annotations":{"io.containerd.image.name":"registry.example.com/app:v1"}}]}`,
+	})
+	if err := os.WriteFile(filepath.Join(dirPath, "broken.tar.gz"), []byte("not an archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dirPath, "README.md"), []byte("images"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := scanImageArchives(zap.NewNop().Sugar(), dirPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]string{
+		"docker.io/library/nginx:1.25":   "nginx.tar",
+		"docker.io/library/nginx:latest": "nginx.tar",
+		"registry.example.com/app:v1":    "app.tgz",
+	}
+
+	if len(index) != len(expected) {
+		t.Fatalf("expected %d images, got %v", len(expected), index)
+	}
+	for image, archive := range expected {
+		if filepath.Base(index[image]) != archive {
+			t.Errorf("expected %s to be provided by %s, got %q", image, archive, index[image])
+		}
+	}
+
+	if _, found := index[normalizeImageReference("nginx")]; !found {
+		t.Error("expected the short name of an image to match the archive")
+	}
+}
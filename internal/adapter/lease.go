@@ -0,0 +1,288 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"github.com/portainer/k2d/internal/k8s"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/kubernetes/pkg/apis/coordination"
+)
+
+const (
+	// leaseManifestDataKey is the key used to store the definition of a lease in its system configmap
+	leaseManifestDataKey = "manifest"
+	// nodeLeaseDurationSeconds is the duration of the heartbeat lease of the node, as set by the kubelet
+	nodeLeaseDurationSeconds = int32(40)
+)
+
+// CreateLease stores a lease inside a system configmap.
+// The resource version of the lease is initialized and incremented each time the lease is updated,
+// allowing the clients relying on optimistic concurrency (e.g. leader election) to detect concurrent updates.
+// It returns an ErrResourceAlreadyExists error if a lease with the same name already exists in the namespace.
+func (adapter *KubeDockerAdapter) CreateLease(ctx context.Context, lease *coordinationv1.Lease) error {
+	adapter.leaseLock.Lock()
+	defer adapter.leaseLock.Unlock()
+
+	_, err := adapter.getLease(lease.Name, lease.Namespace)
+	if err == nil {
+		return fmt.Errorf("%w: lease %s already exists in namespace %s", adaptererr.ErrResourceAlreadyExists, lease.Name, lease.Namespace)
+	} else if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		return err
+	}
+
+	lease.UID = uuid.NewUUID()
+	lease.CreationTimestamp = metav1.NewTime(time.Now())
+	lease.ResourceVersion = "1"
+
+	return adapter.storeLease(lease)
+}
+
+// UpdateLease replaces the definition of an existing lease.
+// When the resource version of the lease is set, it must match the resource version of the stored lease,
+// otherwise an ErrResourceConflict error is returned, as with the optimistic concurrency of the Kubernetes API.
+// It returns an ErrResourceNotFound error if the lease does not exist.
+func (adapter *KubeDockerAdapter) UpdateLease(ctx context.Context, lease *coordinationv1.Lease) error {
+	adapter.leaseLock.Lock()
+	defer adapter.leaseLock.Unlock()
+
+	return adapter.updateLease(lease)
+}
+
+// DeleteLease removes a lease.
+// It returns an ErrResourceNotFound error if the lease does not exist.
+func (adapter *KubeDockerAdapter) DeleteLease(ctx context.Context, leaseName, namespace string) error {
+	adapter.leaseLock.Lock()
+	defer adapter.leaseLock.Unlock()
+
+	_, err := adapter.getLease(leaseName, namespace)
+	if err != nil {
+		return err
+	}
+
+	err = adapter.DeleteSystemConfigMap(naming.BuildLeaseSystemConfigMapName(leaseName, namespace))
+	if err != nil {
+		return fmt.Errorf("unable to delete lease system configmap: %w", err)
+	}
+
+	return nil
+}
+
+func (adapter *KubeDockerAdapter) GetLease(ctx context.Context, leaseName, namespace string) (*coordinationv1.Lease, error) {
+	return adapter.getLease(leaseName, namespace)
+}
+
+func (adapter *KubeDockerAdapter) ListLeases(ctx context.Context, namespace string) (coordinationv1.LeaseList, error) {
+	leases, err := adapter.listLeases(namespace)
+	if err != nil {
+		return coordinationv1.LeaseList{}, fmt.Errorf("unable to list leases: %w", err)
+	}
+
+	return coordinationv1.LeaseList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "LeaseList",
+			APIVersion: "coordination.k8s.io/v1",
+		},
+		Items: leases,
+	}, nil
+}
+
+func (adapter *KubeDockerAdapter) GetLeaseTable(ctx context.Context, namespace string) (*metav1.Table, error) {
+	leaseList, err := adapter.ListLeases(ctx, namespace)
+	if err != nil {
+		return &metav1.Table{}, err
+	}
+
+	internalLeaseList := coordination.LeaseList{}
+	err = adapter.ConvertK8SResource(&leaseList, &internalLeaseList)
+	if err != nil {
+		return &metav1.Table{}, fmt.Errorf("unable to convert versioned LeaseList to internal LeaseList: %w", err)
+	}
+
+	return k8s.GenerateTable(&internalLeaseList)
+}
+
+// RenewNodeLease renews the heartbeat lease of the node, stored in the kube-node-lease namespace under the name of the node.
+// In Kubernetes, each kubelet periodically renews this lease and the clients (and the node lifecycle controller)
+// use its renew time to determine whether the node is alive. The lease is created if it does not exist yet.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+//
+// Returns:
+// - An error if the node information cannot be retrieved or if the lease cannot be stored.
+func (adapter *KubeDockerAdapter) RenewNodeLease(ctx context.Context) error {
+	info, err := adapter.cli.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve docker server info: %w", err)
+	}
+
+	adapter.leaseLock.Lock()
+	defer adapter.leaseLock.Unlock()
+
+	now := metav1.NewMicroTime(time.Now())
+
+	lease, err := adapter.getLease(info.Name, k2dtypes.NodeLeaseNamespaceName)
+	if err == nil {
+		lease.Spec.RenewTime = &now
+		return adapter.updateLease(lease)
+	} else if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		return err
+	}
+
+	holderIdentity := info.Name
+	leaseDurationSeconds := nodeLeaseDurationSeconds
+
+	lease = &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              info.Name,
+			Namespace:         k2dtypes.NodeLeaseNamespaceName,
+			UID:               uuid.NewUUID(),
+			CreationTimestamp: metav1.NewTime(time.Now()),
+			ResourceVersion:   "1",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "v1",
+					Kind:       "Node",
+					Name:       info.Name,
+					UID:        k8stypes.UID(info.ID),
+				},
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holderIdentity,
+			LeaseDurationSeconds: &leaseDurationSeconds,
+			RenewTime:            &now,
+		},
+	}
+
+	return adapter.storeLease(lease)
+}
+
+// updateLease replaces the definition of an existing lease, see UpdateLease.
+// The caller must hold the lease lock.
+func (adapter *KubeDockerAdapter) updateLease(lease *coordinationv1.Lease) error {
+	existingLease, err := adapter.getLease(lease.Name, lease.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if lease.ResourceVersion != "" && lease.ResourceVersion != existingLease.ResourceVersion {
+		return fmt.Errorf("%w: the lease %s in namespace %s has been modified, please apply your changes to the latest version and try again",
+			adaptererr.ErrResourceConflict, lease.Name, lease.Namespace)
+	}
+
+	resourceVersion, err := strconv.ParseInt(existingLease.ResourceVersion, 10, 64)
+	if err != nil {
+		resourceVersion = 0
+	}
+
+	lease.UID = existingLease.UID
+	lease.CreationTimestamp = existingLease.CreationTimestamp
+	lease.ResourceVersion = strconv.FormatInt(resourceVersion+1, 10)
+
+	return adapter.storeLease(lease)
+}
+
+func (adapter *KubeDockerAdapter) storeLease(lease *coordinationv1.Lease) error {
+	leaseData, err := json.Marshal(lease)
+	if err != nil {
+		return fmt.Errorf("unable to marshal lease: %w", err)
+	}
+
+	err = adapter.CreateSystemConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: naming.BuildLeaseSystemConfigMapName(lease.Name, lease.Namespace),
+			Labels: map[string]string{
+				k2dtypes.NamespaceNameLabelKey: lease.Namespace,
+			},
+		},
+		Data: map[string]string{
+			leaseManifestDataKey: string(leaseData),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to store lease system configmap: %w", err)
+	}
+
+	return nil
+}
+
+// getLease returns a lease stored in a system configmap.
+// It returns an ErrResourceNotFound error if the lease does not exist.
+func (adapter *KubeDockerAdapter) getLease(leaseName, namespace string) (*coordinationv1.Lease, error) {
+	configMap, err := adapter.GetSystemConfigMap(naming.BuildLeaseSystemConfigMapName(leaseName, namespace))
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			return nil, adaptererr.ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("unable to get lease system configmap: %w", err)
+	}
+
+	return decodeLease(configMap.Data[leaseManifestDataKey])
+}
+
+func decodeLease(leaseData string) (*coordinationv1.Lease, error) {
+	lease := coordinationv1.Lease{}
+	err := json.Unmarshal([]byte(leaseData), &lease)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal lease: %w", err)
+	}
+
+	lease.TypeMeta = metav1.TypeMeta{
+		Kind:       "Lease",
+		APIVersion: "coordination.k8s.io/v1",
+	}
+
+	return &lease, nil
+}
+
+// listLeases returns the leases of a namespace, or of all namespaces when the namespace is empty,
+// sorted by namespace and name.
+func (adapter *KubeDockerAdapter) listLeases(namespace string) ([]coordinationv1.Lease, error) {
+	configMaps, err := adapter.ListSystemConfigMaps()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list system configmaps: %w", err)
+	}
+
+	leases := []coordinationv1.Lease{}
+	for _, configMap := range configMaps.Items {
+		if !strings.HasPrefix(configMap.Name, naming.LeaseSystemConfigMapPrefix) {
+			continue
+		}
+
+		if namespace != "" && configMap.Labels[k2dtypes.NamespaceNameLabelKey] != namespace {
+			continue
+		}
+
+		lease, err := decodeLease(configMap.Data[leaseManifestDataKey])
+		if err != nil {
+			adapter.logger.Warnf("unable to decode lease stored in system configmap %s: %s", configMap.Name, err)
+			continue
+		}
+
+		leases = append(leases, *lease)
+	}
+
+	sort.Slice(leases, func(i, j int) bool {
+		if leases[i].Namespace != leases[j].Namespace {
+			return leases[i].Namespace < leases[j].Namespace
+		}
+		return leases[i].Name < leases[j].Name
+	})
+
+	return leases, nil
+}
@@ -0,0 +1,249 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// defaultTerminationGracePeriodSeconds is the grace period used when stopping a container
+// whose pod spec does not define terminationGracePeriodSeconds. It matches the Kubernetes default.
+const defaultTerminationGracePeriodSeconds = 30
+
+// lifecycleHookHTTPTimeout is the timeout applied to HTTP lifecycle hooks.
+const lifecycleHookHTTPTimeout = 10 * time.Second
+
+// getPodSpecFromLabels returns the internal pod spec stored in the labels of a container.
+// It returns nil if the container was not created from a pod spec.
+func getPodSpecFromLabels(labels map[string]string) (*core.PodSpec, error) {
+	podSpecData := labels[k2dtypes.PodLastAppliedConfigLabelKey]
+	if podSpecData == "" {
+		return nil, nil
+	}
+
+	podSpec := &core.PodSpec{}
+	err := json.Unmarshal([]byte(podSpecData), podSpec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal pod spec: %w", err)
+	}
+
+	return podSpec, nil
+}
+
+// getTerminationGracePeriod returns the termination grace period defined in the pod spec
+// or the default Kubernetes grace period when it is not defined.
+func getTerminationGracePeriod(podSpec *core.PodSpec) time.Duration {
+	if podSpec == nil || podSpec.TerminationGracePeriodSeconds == nil {
+		return defaultTerminationGracePeriodSeconds * time.Second
+	}
+
+	return time.Duration(*podSpec.TerminationGracePeriodSeconds) * time.Second
+}
+
+// stopContainer gracefully stops a container created from a pod spec.
+// The pod spec is retrieved from the container labels and used to:
+//  1. Run the preStop lifecycle hook of the container, if any. The hook is bound to the termination grace period.
+//  2. Stop the container using the remaining termination grace period as the Docker stop timeout.
+//
+// A failing preStop hook does not prevent the container from being stopped, the failure is only logged.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - containerID: The ID of the container to stop.
+// - labels: The labels of the container, used to retrieve the associated pod spec.
+//
+// Returns:
+// - An error if the container cannot be stopped.
+func (adapter *KubeDockerAdapter) stopContainer(ctx context.Context, containerID string, labels map[string]string) error {
+	return adapter.stopContainerWithGracePeriod(ctx, containerID, labels, nil)
+}
+
+// stopContainerWithGracePeriod works like stopContainer but allows the termination grace period defined in the
+// pod spec to be overridden (e.g. by the gracePeriodSeconds option of an eviction).
+// The grace period defined in the pod spec is used when gracePeriodSeconds is nil.
+func (adapter *KubeDockerAdapter) stopContainerWithGracePeriod(ctx context.Context, containerID string, labels map[string]string, gracePeriodSeconds *int64) error {
+	podSpec, err := getPodSpecFromLabels(adapter.resolveContainerLabels(containerID, labels))
+	if err != nil {
+		adapter.logger.Warnf("unable to retrieve pod spec of container %s, using the default grace period: %s", containerID, err)
+	}
+
+	gracePeriod := getTerminationGracePeriod(podSpec)
+	if gracePeriodSeconds != nil {
+		gracePeriod = time.Duration(*gracePeriodSeconds) * time.Second
+	}
+	start := time.Now()
+
+	if podSpec != nil && len(podSpec.Containers) > 0 && podSpec.Containers[0].Lifecycle != nil && podSpec.Containers[0].Lifecycle.PreStop != nil {
+		hookCtx, cancel := context.WithTimeout(ctx, gracePeriod)
+		err := adapter.runLifecycleHandler(hookCtx, containerID, podSpec, podSpec.Containers[0].Lifecycle.PreStop)
+		cancel()
+
+		if err != nil {
+			adapter.logger.Warnf("preStop hook failed for container %s: %s", containerID, err)
+		}
+	}
+
+	remaining := gracePeriod - time.Since(start)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	stopTimeout := int(remaining.Seconds())
+	return adapter.cli.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &stopTimeout})
+}
+
+// runPostStartHook runs the postStart lifecycle hook of the first container of the pod spec, if any.
+// As in Kubernetes, the container is stopped when the hook fails.
+func (adapter *KubeDockerAdapter) runPostStartHook(ctx context.Context, containerID string, podSpec core.PodSpec) error {
+	if len(podSpec.Containers) == 0 || podSpec.Containers[0].Lifecycle == nil || podSpec.Containers[0].Lifecycle.PostStart == nil {
+		return nil
+	}
+
+	err := adapter.runLifecycleHandler(ctx, containerID, &podSpec, podSpec.Containers[0].Lifecycle.PostStart)
+	if err != nil {
+		stopTimeout := 0
+		stopErr := adapter.cli.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &stopTimeout})
+		if stopErr != nil {
+			adapter.logger.Warnf("unable to stop container %s after postStart hook failure: %s", containerID, stopErr)
+		}
+
+		return fmt.Errorf("postStart hook failed: %w", err)
+	}
+
+	return nil
+}
+
+// runLifecycleHandler runs an exec or HTTP lifecycle handler against the specified container.
+// TCPSocket and Sleep handlers are not supported and are ignored.
+func (adapter *KubeDockerAdapter) runLifecycleHandler(ctx context.Context, containerID string, podSpec *core.PodSpec, handler *core.LifecycleHandler) error {
+	if handler.Exec != nil {
+		return adapter.runExecHandler(ctx, containerID, handler.Exec.Command)
+	}
+
+	if handler.HTTPGet != nil {
+		return adapter.runHTTPGetHandler(ctx, containerID, podSpec, handler.HTTPGet)
+	}
+
+	return nil
+}
+
+// runExecHandler executes a command inside the container and waits for it to complete.
+// It returns an error if the command exits with a non-zero exit code.
+func (adapter *KubeDockerAdapter) runExecHandler(ctx context.Context, containerID string, command []string) error {
+	execResponse, err := adapter.cli.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		Cmd:          command,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create exec instance: %w", err)
+	}
+
+	attachResponse, err := adapter.cli.ContainerExecAttach(ctx, execResponse.ID, types.ExecStartCheck{})
+	if err != nil {
+		return fmt.Errorf("unable to start exec instance: %w", err)
+	}
+	defer attachResponse.Close()
+
+	_, err = io.Copy(io.Discard, attachResponse.Reader)
+	if err != nil {
+		return fmt.Errorf("unable to read exec output: %w", err)
+	}
+
+	execInspect, err := adapter.cli.ContainerExecInspect(ctx, execResponse.ID)
+	if err != nil {
+		return fmt.Errorf("unable to inspect exec instance: %w", err)
+	}
+
+	if execInspect.ExitCode != 0 {
+		return fmt.Errorf("command %v exited with code %d", command, execInspect.ExitCode)
+	}
+
+	return nil
+}
+
+// runHTTPGetHandler sends an HTTP GET request to the container.
+// When no host is specified, the IP address of the container in its namespace network is used.
+// It returns an error if the request fails or if the response status code is not in the 2xx-3xx range.
+func (adapter *KubeDockerAdapter) runHTTPGetHandler(ctx context.Context, containerID string, podSpec *core.PodSpec, action *core.HTTPGetAction) error {
+	host := action.Host
+	if host == "" {
+		containerDetails, err := adapter.cli.ContainerInspect(ctx, containerID)
+		if err != nil {
+			return fmt.Errorf("unable to inspect container: %w", err)
+		}
+
+		for _, endpoint := range containerDetails.NetworkSettings.Networks {
+			if endpoint.IPAddress != "" {
+				host = endpoint.IPAddress
+				break
+			}
+		}
+
+		if host == "" {
+			return fmt.Errorf("unable to find the IP address of container %s", containerID)
+		}
+	}
+
+	port, err := resolveContainerPort(podSpec, action.Port)
+	if err != nil {
+		return err
+	}
+
+	scheme := "http"
+	if action.Scheme == core.URISchemeHTTPS {
+		scheme = "https"
+	}
+
+	url := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(host, strconv.Itoa(port)), action.Path)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("unable to build HTTP request: %w", err)
+	}
+
+	for _, header := range action.HTTPHeaders {
+		request.Header.Add(header.Name, header.Value)
+	}
+
+	client := &http.Client{Timeout: lifecycleHookHTTPTimeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("unable to send HTTP request to %s: %w", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("HTTP request to %s returned status code %d", url, response.StatusCode)
+	}
+
+	return nil
+}
+
+// resolveContainerPort resolves a numeric or named port against the ports of the first container of the pod spec.
+func resolveContainerPort(podSpec *core.PodSpec, port intstr.IntOrString) (int, error) {
+	if port.Type == intstr.Int {
+		return port.IntValue(), nil
+	}
+
+	if podSpec != nil && len(podSpec.Containers) > 0 {
+		for _, containerPort := range podSpec.Containers[0].Ports {
+			if containerPort.Name == port.StrVal {
+				return int(containerPort.ContainerPort), nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("unable to resolve named port %s", port.StrVal)
+}
@@ -0,0 +1,87 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/portainer/k2d/internal/adapter/store/filesystem"
+	"github.com/portainer/k2d/internal/migration"
+	"github.com/portainer/k2d/internal/types"
+)
+
+// SchemaMigrations returns the migrations of the data managed by k2d (container labels, store layout...),
+// to be applied on startup by a migration.Runner, after the system resources are provisioned.
+// A new migration must be appended with the next version each time the format of the data changes.
+func (adapter *KubeDockerAdapter) SchemaMigrations() []migration.Migration {
+	return []migration.Migration{
+		{
+			Version:     1,
+			Description: "move the configuration stored in the container labels to the store backend",
+			Migrate:     adapter.migrateLegacyContainerPayloads,
+		},
+		{
+			Version:     2,
+			Description: "record the namespace in the metadata of the ConfigMaps and Secrets of the disk store",
+			Migrate:     adapter.migrateStoreMetadataNamespaces,
+		},
+		{
+			Version:     3,
+			Description: "shard the ConfigMaps and Secrets of the disk store by namespace",
+			Migrate:     adapter.migrateStoreShards,
+		},
+	}
+}
+
+// migrateStoreMetadataNamespaces records the namespace in the metadata files of the ConfigMaps and Secrets
+// created by previous versions of k2d with the disk store backend (see filesystem.MigrateMetadataNamespaces).
+// The metadata files whose namespace cannot be deduced are reported and left untouched.
+func (adapter *KubeDockerAdapter) migrateStoreMetadataNamespaces(ctx context.Context) error {
+	if adapter.storeBackend != types.DiskStoreBackend {
+		return nil
+	}
+
+	namespaceList, err := adapter.listNamespaces(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list namespaces: %w", err)
+	}
+
+	namespaces := []string{}
+	for _, namespace := range namespaceList.Items {
+		namespaces = append(namespaces, namespace.Name)
+	}
+
+	unmigrated, err := filesystem.MigrateMetadataNamespaces(adapter.dataPath, namespaces)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range unmigrated {
+		adapter.logger.Warnw("unable to deduce the namespace of a stored resource, the resource is only listed across all namespaces",
+			"metadata_file", file,
+		)
+	}
+
+	return nil
+}
+
+// migrateStoreShards moves the ConfigMaps and Secrets stored in the flat directories of the disk store by previous
+// versions of k2d to the directory of their namespace (see filesystem.MigrateShards).
+// The resources whose metadata does not record a namespace cannot be migrated and are not returned by the store anymore.
+func (adapter *KubeDockerAdapter) migrateStoreShards(ctx context.Context) error {
+	if adapter.storeBackend != types.DiskStoreBackend {
+		return nil
+	}
+
+	unmigrated, err := filesystem.MigrateShards(adapter.dataPath)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range unmigrated {
+		adapter.logger.Warnw("unable to move a stored resource without namespace to the directory of its namespace, the resource is ignored",
+			"metadata_file", file,
+		)
+	}
+
+	return nil
+}
@@ -55,6 +55,11 @@ func (adapter *KubeDockerAdapter) CreateNetworkFromNamespace(ctx context.Context
 		},
 	}
 
+	err = setNetworkOptionsFromAnnotations(&networkOptions, namespace.Annotations)
+	if err != nil {
+		return fmt.Errorf("invalid network configuration for namespace %s: %w", namespace.Name, err)
+	}
+
 	_, err = adapter.cli.NetworkCreate(ctx, networkName, networkOptions)
 	if err != nil {
 		return fmt.Errorf("unable to create network %s: %w", networkName, err)
@@ -4,8 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"strconv"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -73,3 +78,89 @@ func (adapter *KubeDockerAdapter) provisionNamespace(ctx context.Context, namesp
 
 	return nil
 }
+
+// setNetworkOptionsFromAnnotations translates the networking annotations of a namespace into the options used
+// to create the bridge network of the namespace:
+//   - networking.k2d.io/subnet and networking.k2d.io/gateway configure the IPv4 address range of the network.
+//   - networking.k2d.io/mtu configures the MTU of the bridge.
+//   - networking.k2d.io/internal creates an internal network, isolating the namespace from the external networks.
+//   - networking.k2d.io/ipv6 and networking.k2d.io/ipv6-subnet enable IPv6 on the network.
+//
+// Parameters:
+// - networkOptions: The options used to create the network, updated in place.
+// - annotations: The annotations of the namespace.
+//
+// Returns:
+// - An ErrInvalidResource error if one of the annotations has an invalid value.
+func setNetworkOptionsFromAnnotations(networkOptions *types.NetworkCreate, annotations map[string]string) error {
+	ipamConfigs := []network.IPAMConfig{}
+
+	subnet := annotations[k2dtypes.NetworkSubnetAnnotationKey]
+	gateway := annotations[k2dtypes.NetworkGatewayAnnotationKey]
+	if subnet != "" {
+		_, subnetNetwork, err := net.ParseCIDR(subnet)
+		if err != nil || subnetNetwork.IP.To4() == nil {
+			return fmt.Errorf("%w: invalid value for annotation %s: %q is not an IPv4 CIDR", adaptererr.ErrInvalidResource, k2dtypes.NetworkSubnetAnnotationKey, subnet)
+		}
+
+		if gateway != "" {
+			gatewayIP := net.ParseIP(gateway)
+			if gatewayIP == nil || !subnetNetwork.Contains(gatewayIP) {
+				return fmt.Errorf("%w: invalid value for annotation %s: %q is not an IP address of the subnet %s", adaptererr.ErrInvalidResource, k2dtypes.NetworkGatewayAnnotationKey, gateway, subnet)
+			}
+		}
+
+		ipamConfigs = append(ipamConfigs, network.IPAMConfig{Subnet: subnet, Gateway: gateway})
+	} else if gateway != "" {
+		return fmt.Errorf("%w: annotation %s requires annotation %s", adaptererr.ErrInvalidResource, k2dtypes.NetworkGatewayAnnotationKey, k2dtypes.NetworkSubnetAnnotationKey)
+	}
+
+	if mtu := annotations[k2dtypes.NetworkMTUAnnotationKey]; mtu != "" {
+		value, err := strconv.Atoi(mtu)
+		if err != nil || value < 68 {
+			return fmt.Errorf("%w: invalid value for annotation %s: %q", adaptererr.ErrInvalidResource, k2dtypes.NetworkMTUAnnotationKey, mtu)
+		}
+
+		networkOptions.Options["com.docker.network.driver.mtu"] = mtu
+	}
+
+	if internal := annotations[k2dtypes.NetworkInternalAnnotationKey]; internal != "" {
+		value, err := strconv.ParseBool(internal)
+		if err != nil {
+			return fmt.Errorf("%w: invalid value for annotation %s: %q", adaptererr.ErrInvalidResource, k2dtypes.NetworkInternalAnnotationKey, internal)
+		}
+
+		networkOptions.Internal = value
+	}
+
+	if ipv6 := annotations[k2dtypes.NetworkIPv6AnnotationKey]; ipv6 != "" {
+		value, err := strconv.ParseBool(ipv6)
+		if err != nil {
+			return fmt.Errorf("%w: invalid value for annotation %s: %q", adaptererr.ErrInvalidResource, k2dtypes.NetworkIPv6AnnotationKey, ipv6)
+		}
+
+		networkOptions.EnableIPv6 = value
+	}
+
+	if ipv6Subnet := annotations[k2dtypes.NetworkIPv6SubnetAnnotationKey]; ipv6Subnet != "" {
+		if !networkOptions.EnableIPv6 {
+			return fmt.Errorf("%w: annotation %s requires annotation %s", adaptererr.ErrInvalidResource, k2dtypes.NetworkIPv6SubnetAnnotationKey, k2dtypes.NetworkIPv6AnnotationKey)
+		}
+
+		_, subnetNetwork, err := net.ParseCIDR(ipv6Subnet)
+		if err != nil || subnetNetwork.IP.To4() != nil {
+			return fmt.Errorf("%w: invalid value for annotation %s: %q is not an IPv6 CIDR", adaptererr.ErrInvalidResource, k2dtypes.NetworkIPv6SubnetAnnotationKey, ipv6Subnet)
+		}
+
+		ipamConfigs = append(ipamConfigs, network.IPAMConfig{Subnet: ipv6Subnet})
+	}
+
+	if len(ipamConfigs) != 0 {
+		networkOptions.IPAM = &network.IPAM{
+			Driver: "default",
+			Config: ipamConfigs,
+		}
+	}
+
+	return nil
+}
@@ -1,15 +1,70 @@
 package naming
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"regexp"
 	"strings"
 )
 
+// TemporaryContainerNameSuffix is the suffix of the name of the temporary containers created when a container is replaced.
+// The temporary container is renamed once the replaced container has been removed.
+const TemporaryContainerNameSuffix = "_temp"
+
+// containerNameHashLength is the length of the hash suffix of the container names
+const containerNameHashLength = 8
+
+// containerNamePrefixPattern matches the container name prefixes accepted by Docker
+var containerNamePrefixPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ContainerNamingOptions represents the naming scheme of the containers created by k2d
+type ContainerNamingOptions struct {
+	// Prefix is added to the names of the containers
+	Prefix string
+	// HashSuffix appends a hash of the namespace and the name of the workload to the names of the containers
+	HashSuffix bool
+}
+
+var containerNaming ContainerNamingOptions
+
+// ConfigureContainerNaming sets the naming scheme used by BuildContainerName.
+// It must be called before any container name is built.
+// It returns an error if the prefix cannot be used in a container name.
+func ConfigureContainerNaming(options ContainerNamingOptions) error {
+	if options.Prefix != "" && !containerNamePrefixPattern.MatchString(options.Prefix) {
+		return fmt.Errorf("invalid container name prefix %q, only [a-zA-Z0-9_.-] are allowed and it must start with a letter or a digit", options.Prefix)
+	}
+
+	containerNaming = options
+	return nil
+}
+
 // Each container is named using the following format:
-// [namespace]-[container-name]
+// [prefix][namespace]-[container-name](-[hash])
+// The prefix and the hash of the namespace and container name are optional, see ConfigureContainerNaming.
 func BuildContainerName(containerName, namespace string) string {
 	containerName = strings.TrimPrefix(containerName, "/")
-	return fmt.Sprintf("%s-%s", namespace, containerName)
+	name := containerNaming.Prefix + buildResourceName(containerName, namespace)
+
+	if containerNaming.HashSuffix {
+		hash := sha256.Sum256([]byte(namespace + "/" + containerName))
+		name += "-" + hex.EncodeToString(hash[:])[:containerNameHashLength]
+	}
+
+	return name
+}
+
+// Each temporary container is named using the following format:
+// [container-name]_temp
+func BuildTemporaryContainerName(containerName string) string {
+	return containerName + TemporaryContainerNameSuffix
+}
+
+// buildResourceName builds the name of a namespaced resource stored by k2d. Unlike the container names,
+// it does not depend on the container naming scheme so that the stored resources are found when the scheme changes.
+func buildResourceName(name, namespace string) string {
+	return fmt.Sprintf("%s-%s", namespace, name)
 }
 
 // Each network is named using the following format:
@@ -29,3 +84,93 @@ func BuildPersistentVolumeName(volumeName string, namespace string) string {
 func BuildPVCSystemConfigMapName(persistentVolumeClaimName, namespace string) string {
 	return fmt.Sprintf("pvc-%s-%s", namespace, persistentVolumeClaimName)
 }
+
+// Each system configmap used to store the state of a node is named using the following format:
+// node-[node-name]
+func BuildNodeSystemConfigMapName(nodeName string) string {
+	return fmt.Sprintf("node-%s", nodeName)
+}
+
+// WorkloadSystemConfigMapPrefix is the prefix of the system configmaps used to store the desired state of the workloads
+const WorkloadSystemConfigMapPrefix = "workload-"
+
+// Each system configmap used to store the desired state of a workload is named using the following format:
+// workload-[namespace]-[workload-name]
+func BuildWorkloadSystemConfigMapName(workloadName, namespace string) string {
+	return WorkloadSystemConfigMapPrefix + buildResourceName(workloadName, namespace)
+}
+
+// ContainerPayloadSystemConfigMapPrefix is the prefix of the system configmaps used to store the configurations
+// referenced in the labels of a container
+const ContainerPayloadSystemConfigMapPrefix = "container-"
+
+// Each system configmap used to store the configurations referenced in the labels of a container is named using the following format:
+// container-[container-id]
+func BuildContainerPayloadSystemConfigMapName(containerID string) string {
+	return ContainerPayloadSystemConfigMapPrefix + containerID
+}
+
+// StorageClassSystemConfigMapPrefix is the prefix of the system configmaps used to store the storage classes
+const StorageClassSystemConfigMapPrefix = "storageclass-"
+
+// Each system configmap used to store a storage class is named using the following format:
+// storageclass-[storage-class-name]
+func BuildStorageClassSystemConfigMapName(storageClassName string) string {
+	return StorageClassSystemConfigMapPrefix + storageClassName
+}
+
+// DeploymentRevisionsSystemConfigMapPrefix is the prefix of the system configmaps used to store the revision history of the deployments
+const DeploymentRevisionsSystemConfigMapPrefix = "revisions-"
+
+// Each system configmap used to store the revision history of a deployment is named using the following format:
+// revisions-[namespace]-[deployment-name]
+func BuildDeploymentRevisionsSystemConfigMapName(deploymentName, namespace string) string {
+	return DeploymentRevisionsSystemConfigMapPrefix + buildResourceName(deploymentName, namespace)
+}
+
+// HorizontalPodAutoscalerSystemConfigMapPrefix is the prefix of the system configmaps used to store the horizontal pod autoscalers
+const HorizontalPodAutoscalerSystemConfigMapPrefix = "hpa-"
+
+// Each system configmap used to store a horizontal pod autoscaler is named using the following format:
+// hpa-[namespace]-[horizontal-pod-autoscaler-name]
+func BuildHorizontalPodAutoscalerSystemConfigMapName(horizontalPodAutoscalerName, namespace string) string {
+	return HorizontalPodAutoscalerSystemConfigMapPrefix + buildResourceName(horizontalPodAutoscalerName, namespace)
+}
+
+// LeaseSystemConfigMapPrefix is the prefix of the system configmaps used to store the leases
+const LeaseSystemConfigMapPrefix = "lease-"
+
+// Each system configmap used to store a lease is named using the following format:
+// lease-[namespace]-[lease-name]
+func BuildLeaseSystemConfigMapName(leaseName, namespace string) string {
+	return LeaseSystemConfigMapPrefix + buildResourceName(leaseName, namespace)
+}
+
+// NetworkPolicySystemConfigMapPrefix is the prefix of the system configmaps used to store the network policies
+const NetworkPolicySystemConfigMapPrefix = "netpol-"
+
+// Each system configmap used to store a network policy is named using the following format:
+// netpol-[namespace]-[network-policy-name]
+func BuildNetworkPolicySystemConfigMapName(networkPolicyName, namespace string) string {
+	return NetworkPolicySystemConfigMapPrefix + buildResourceName(networkPolicyName, namespace)
+}
+
+// NetworkPolicyNetworkPrefix is the prefix of the networks used to approximate the network policies.
+// The dot cannot appear in a namespace name, which prevents any collision with the network of a namespace.
+const NetworkPolicyNetworkPrefix = "k2d-netpol."
+
+// Each network used to approximate a network policy is named using the following format:
+// k2d-netpol.[namespace].[network-policy-name]
+func BuildNetworkPolicyNetworkName(networkPolicyName, namespace string) string {
+	return fmt.Sprintf("%s%s.%s", NetworkPolicyNetworkPrefix, namespace, networkPolicyName)
+}
+
+// ObjectMetadataSystemConfigMapPrefix is the prefix of the system configmaps used to store the metadata of the
+// resources served from the containers (pods, deployments and services)
+const ObjectMetadataSystemConfigMapPrefix = "metadata-"
+
+// Each system configmap used to store the metadata of a resource is named using the following format:
+// metadata-[kind]-[namespace]-[resource-name]
+func BuildObjectMetadataSystemConfigMapName(kind, name, namespace string) string {
+	return ObjectMetadataSystemConfigMapPrefix + strings.ToLower(kind) + "-" + buildResourceName(name, namespace)
+}
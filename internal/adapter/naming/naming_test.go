@@ -0,0 +1,41 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildContainerName(t *testing.T) {
+	defer ConfigureContainerNaming(ContainerNamingOptions{})
+
+	if name := BuildContainerName("/web", "default"); name != "default-web" {
+		t.Errorf("expected default-web, got %s", name)
+	}
+
+	err := ConfigureContainerNaming(ContainerNamingOptions{Prefix: "k2d-", HashSuffix: true})
+	if err != nil {
+		t.Fatalf("unable to configure container naming: %s", err)
+	}
+
+	name := BuildContainerName("b-c", "a")
+	if !strings.HasPrefix(name, "k2d-a-b-c-") || len(name) != len("k2d-a-b-c-")+containerNameHashLength {
+		t.Errorf("expected a prefixed and hashed name, got %s", name)
+	}
+
+	if name == BuildContainerName("c", "a-b") {
+		t.Errorf("expected the hash suffix to prevent collisions, got %s for both workloads", name)
+	}
+
+	if BuildWorkloadSystemConfigMapName("web", "default") != "workload-default-web" {
+		t.Error("expected the system configmap names not to depend on the container naming scheme")
+	}
+}
+
+func TestConfigureContainerNamingRejectsInvalidPrefix(t *testing.T) {
+	defer ConfigureContainerNaming(ContainerNamingOptions{})
+
+	err := ConfigureContainerNaming(ContainerNamingOptions{Prefix: "-k2d/"})
+	if err == nil {
+		t.Error("expected an error for an invalid prefix")
+	}
+}
@@ -0,0 +1,198 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"github.com/portainer/k2d/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/kubernetes/pkg/apis/networking"
+)
+
+// networkPolicyManifestDataKey is the key used to store the definition of a network policy in its system configmap
+const networkPolicyManifestDataKey = "manifest"
+
+// CreateNetworkPolicy stores a network policy inside a system configmap.
+// The policy is enforced asynchronously by the network policy loop, see ApplyNetworkPolicies.
+// It returns an ErrResourceAlreadyExists error if a network policy with the same name already exists in the namespace.
+func (adapter *KubeDockerAdapter) CreateNetworkPolicy(ctx context.Context, networkPolicy *networkingv1.NetworkPolicy) error {
+	_, err := adapter.getNetworkPolicy(networkPolicy.Name, networkPolicy.Namespace)
+	if err == nil {
+		return fmt.Errorf("%w: network policy %s already exists in namespace %s", adaptererr.ErrResourceAlreadyExists, networkPolicy.Name, networkPolicy.Namespace)
+	} else if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		return err
+	}
+
+	networkPolicy.UID = uuid.NewUUID()
+	networkPolicy.CreationTimestamp = metav1.NewTime(time.Now())
+	networkPolicy.Generation = 1
+
+	return adapter.storeNetworkPolicy(networkPolicy)
+}
+
+// UpdateNetworkPolicy replaces the definition of an existing network policy.
+// It returns an ErrResourceNotFound error if the network policy does not exist.
+func (adapter *KubeDockerAdapter) UpdateNetworkPolicy(ctx context.Context, networkPolicy *networkingv1.NetworkPolicy) error {
+	existingNetworkPolicy, err := adapter.getNetworkPolicy(networkPolicy.Name, networkPolicy.Namespace)
+	if err != nil {
+		return err
+	}
+
+	networkPolicy.UID = existingNetworkPolicy.UID
+	networkPolicy.CreationTimestamp = existingNetworkPolicy.CreationTimestamp
+	networkPolicy.Generation = existingNetworkPolicy.Generation + 1
+
+	return adapter.storeNetworkPolicy(networkPolicy)
+}
+
+// DeleteNetworkPolicy removes a network policy.
+// The network used to approximate the policy is removed by the network policy loop.
+// It returns an ErrResourceNotFound error if the network policy does not exist.
+func (adapter *KubeDockerAdapter) DeleteNetworkPolicy(ctx context.Context, networkPolicyName, namespace string) error {
+	_, err := adapter.getNetworkPolicy(networkPolicyName, namespace)
+	if err != nil {
+		return err
+	}
+
+	err = adapter.DeleteSystemConfigMap(naming.BuildNetworkPolicySystemConfigMapName(networkPolicyName, namespace))
+	if err != nil {
+		return fmt.Errorf("unable to delete network policy system configmap: %w", err)
+	}
+
+	return nil
+}
+
+func (adapter *KubeDockerAdapter) GetNetworkPolicy(ctx context.Context, networkPolicyName, namespace string) (*networkingv1.NetworkPolicy, error) {
+	return adapter.getNetworkPolicy(networkPolicyName, namespace)
+}
+
+func (adapter *KubeDockerAdapter) ListNetworkPolicies(ctx context.Context, namespace string) (networkingv1.NetworkPolicyList, error) {
+	networkPolicies, err := adapter.listNetworkPolicies(namespace)
+	if err != nil {
+		return networkingv1.NetworkPolicyList{}, fmt.Errorf("unable to list network policies: %w", err)
+	}
+
+	return networkingv1.NetworkPolicyList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPolicyList",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		Items: networkPolicies,
+	}, nil
+}
+
+func (adapter *KubeDockerAdapter) GetNetworkPolicyTable(ctx context.Context, namespace string) (*metav1.Table, error) {
+	networkPolicyList, err := adapter.ListNetworkPolicies(ctx, namespace)
+	if err != nil {
+		return &metav1.Table{}, err
+	}
+
+	internalNetworkPolicyList := networking.NetworkPolicyList{}
+	err = adapter.ConvertK8SResource(&networkPolicyList, &internalNetworkPolicyList)
+	if err != nil {
+		return &metav1.Table{}, fmt.Errorf("unable to convert versioned NetworkPolicyList to internal NetworkPolicyList: %w", err)
+	}
+
+	return k8s.GenerateTable(&internalNetworkPolicyList)
+}
+
+func (adapter *KubeDockerAdapter) storeNetworkPolicy(networkPolicy *networkingv1.NetworkPolicy) error {
+	networkPolicyData, err := json.Marshal(networkPolicy)
+	if err != nil {
+		return fmt.Errorf("unable to marshal network policy: %w", err)
+	}
+
+	err = adapter.CreateSystemConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: naming.BuildNetworkPolicySystemConfigMapName(networkPolicy.Name, networkPolicy.Namespace),
+			Labels: map[string]string{
+				k2dtypes.NamespaceNameLabelKey: networkPolicy.Namespace,
+			},
+		},
+		Data: map[string]string{
+			networkPolicyManifestDataKey: string(networkPolicyData),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to store network policy system configmap: %w", err)
+	}
+
+	return nil
+}
+
+// getNetworkPolicy returns a network policy stored in a system configmap.
+// It returns an ErrResourceNotFound error if the network policy does not exist.
+func (adapter *KubeDockerAdapter) getNetworkPolicy(networkPolicyName, namespace string) (*networkingv1.NetworkPolicy, error) {
+	configMap, err := adapter.GetSystemConfigMap(naming.BuildNetworkPolicySystemConfigMapName(networkPolicyName, namespace))
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			return nil, adaptererr.ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("unable to get network policy system configmap: %w", err)
+	}
+
+	return decodeNetworkPolicy(configMap.Data[networkPolicyManifestDataKey])
+}
+
+func decodeNetworkPolicy(networkPolicyData string) (*networkingv1.NetworkPolicy, error) {
+	networkPolicy := networkingv1.NetworkPolicy{}
+	err := json.Unmarshal([]byte(networkPolicyData), &networkPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal network policy: %w", err)
+	}
+
+	networkPolicy.TypeMeta = metav1.TypeMeta{
+		Kind:       "NetworkPolicy",
+		APIVersion: "networking.k8s.io/v1",
+	}
+
+	return &networkPolicy, nil
+}
+
+// listNetworkPolicies returns the network policies of a namespace, or of all namespaces when the namespace is empty,
+// sorted by namespace and name.
+func (adapter *KubeDockerAdapter) listNetworkPolicies(namespace string) ([]networkingv1.NetworkPolicy, error) {
+	configMaps, err := adapter.ListSystemConfigMaps()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list system configmaps: %w", err)
+	}
+
+	networkPolicies := []networkingv1.NetworkPolicy{}
+	for _, configMap := range configMaps.Items {
+		if !strings.HasPrefix(configMap.Name, naming.NetworkPolicySystemConfigMapPrefix) {
+			continue
+		}
+
+		if namespace != "" && configMap.Labels[k2dtypes.NamespaceNameLabelKey] != namespace {
+			continue
+		}
+
+		networkPolicy, err := decodeNetworkPolicy(configMap.Data[networkPolicyManifestDataKey])
+		if err != nil {
+			adapter.logger.Warnf("unable to decode network policy stored in system configmap %s: %s", configMap.Name, err)
+			continue
+		}
+
+		networkPolicies = append(networkPolicies, *networkPolicy)
+	}
+
+	sort.Slice(networkPolicies, func(i, j int) bool {
+		if networkPolicies[i].Namespace != networkPolicies[j].Namespace {
+			return networkPolicies[i].Namespace < networkPolicies[j].Namespace
+		}
+		return networkPolicies[i].Name < networkPolicies[j].Name
+	})
+
+	return networkPolicies, nil
+}
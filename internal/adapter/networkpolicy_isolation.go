@@ -0,0 +1,484 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	dockerfilters "github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/portainer/k2d/internal/adapter/filters"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// namespaceNameLabel is the label automatically set on each namespace by Kubernetes,
+// commonly used in the namespace selectors of the network policies
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+type (
+	// networkPolicyNetwork is the desired state of the network used to approximate a network policy
+	networkPolicyNetwork struct {
+		policy   networkingv1.NetworkPolicy
+		internal bool
+		members  map[string]types.Container
+	}
+
+	// containerIsolation aggregates the effect of the network policies selecting a container.
+	// As in Kubernetes, the policies are additive: a direction is open as soon as one policy allows all the traffic in that direction.
+	containerIsolation struct {
+		ingressRestricted bool
+		ingressOpen       bool
+		egressRestricted  bool
+		egressOpen        bool
+	}
+)
+
+func (isolation containerIsolation) isolated() bool {
+	return (isolation.ingressRestricted && !isolation.ingressOpen) || (isolation.egressRestricted && !isolation.egressOpen)
+}
+
+// ApplyNetworkPolicies approximates the network policies using Docker network isolation.
+// Docker has no notion of per-container firewall rules, so the following best-effort model is used:
+//   - Each network policy selecting at least one container is backed by a dedicated bridge network
+//     (see naming.BuildNetworkPolicyNetworkName). The containers selected by the policy and the peers allowed by its
+//     ingress and egress rules (pod and namespace selectors) are connected to that network.
+//   - A container selected by a policy restricting its ingress or egress traffic is disconnected from the network of its namespace,
+//     so that it can only reach and be reached by the peers sharing one of its policy networks (default deny).
+//   - A policy network is internal, cutting the access to the external networks, when the policy restricts the egress traffic
+//     without any rule allowing all destinations or an ipBlock.
+//
+// Docker connectivity is symmetric, so an ingress rule also allows the egress traffic to the peer and reciprocally.
+// Ports and ipBlock peers cannot be represented and are ignored.
+// The containers are reconnected to the network of their namespace when the policies selecting them are removed.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+//
+// Returns:
+// - An error if the network policies, the containers or the namespaces cannot be listed.
+func (adapter *KubeDockerAdapter) ApplyNetworkPolicies(ctx context.Context) error {
+	policies, err := adapter.listNetworkPolicies("")
+	if err != nil {
+		return fmt.Errorf("unable to list network policies: %w", err)
+	}
+
+	filter := filters.AllNamespaces()
+	filter.Add("label", k2dtypes.WorkloadNameLabelKey)
+	containers, err := adapter.cli.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filter})
+	if err != nil {
+		return fmt.Errorf("unable to list containers: %w", err)
+	}
+
+	namespaces, err := adapter.listNamespaces(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list namespaces: %w", err)
+	}
+
+	namespaceLabels := map[string]labels.Set{}
+	for _, namespace := range namespaces.Items {
+		namespaceLabels[namespace.Name] = labels.Merge(namespace.Labels, labels.Set{namespaceNameLabel: namespace.Name})
+	}
+
+	attachableContainers := []types.Container{}
+	for _, container := range containers {
+		if isNetworkPolicyAttachable(container) {
+			attachableContainers = append(attachableContainers, container)
+		}
+	}
+
+	desiredNetworks := map[string]*networkPolicyNetwork{}
+	isolations := map[string]*containerIsolation{}
+
+	for _, policy := range policies {
+		policyNetwork, err := adapter.buildNetworkPolicyNetwork(policy, attachableContainers, namespaceLabels, isolations)
+		if err != nil {
+			adapter.logger.Warnw("unable to evaluate network policy",
+				"network_policy", policy.Name,
+				"namespace", policy.Namespace,
+				"error", err,
+			)
+			continue
+		}
+
+		if policyNetwork != nil {
+			desiredNetworks[naming.BuildNetworkPolicyNetworkName(policy.Name, policy.Namespace)] = policyNetwork
+		}
+	}
+
+	err = adapter.syncNetworkPolicyNetworks(ctx, desiredNetworks)
+	if err != nil {
+		return err
+	}
+
+	for _, container := range attachableContainers {
+		isolation, selected := isolations[container.ID]
+		err := adapter.syncNamespaceNetworkConnection(ctx, container, selected && isolation.isolated())
+		if err != nil {
+			adapter.logger.Warnw("unable to apply network policies to container",
+				"container", strings.TrimPrefix(container.Names[0], "/"),
+				"error", err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// buildNetworkPolicyNetwork returns the desired state of the network of a network policy,
+// or nil when the policy does not select any container.
+// The isolation of each selected container is updated in place.
+func (adapter *KubeDockerAdapter) buildNetworkPolicyNetwork(policy networkingv1.NetworkPolicy, containers []types.Container, namespaceLabels map[string]labels.Set, isolations map[string]*containerIsolation) (*networkPolicyNetwork, error) {
+	podSelector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pod selector: %w", err)
+	}
+
+	selected := []types.Container{}
+	for _, container := range containers {
+		if container.Labels[k2dtypes.NamespaceNameLabelKey] == policy.Namespace && podSelector.Matches(labels.Set(container.Labels)) {
+			selected = append(selected, container)
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	restrictsIngress, restrictsEgress := networkPolicyTypes(policy)
+
+	allowsAllIngress := false
+	for _, rule := range policy.Spec.Ingress {
+		if len(rule.From) == 0 {
+			allowsAllIngress = true
+		}
+	}
+
+	// an egress rule without destination or with an ipBlock allows the traffic to the external networks
+	allowsExternalEgress := false
+	allowsAllEgress := false
+	for _, rule := range policy.Spec.Egress {
+		if len(rule.To) == 0 {
+			allowsAllEgress = true
+			allowsExternalEgress = true
+		}
+
+		for _, peer := range rule.To {
+			if peer.IPBlock != nil {
+				allowsExternalEgress = true
+			}
+		}
+	}
+
+	policyNetwork := &networkPolicyNetwork{
+		policy:   policy,
+		internal: restrictsEgress && !allowsExternalEgress,
+		members:  map[string]types.Container{},
+	}
+
+	for _, container := range selected {
+		policyNetwork.members[container.ID] = container
+
+		isolation, ok := isolations[container.ID]
+		if !ok {
+			isolation = &containerIsolation{}
+			isolations[container.ID] = isolation
+		}
+
+		isolation.ingressRestricted = isolation.ingressRestricted || restrictsIngress
+		isolation.ingressOpen = isolation.ingressOpen || (restrictsIngress && allowsAllIngress)
+		isolation.egressRestricted = isolation.egressRestricted || restrictsEgress
+		isolation.egressOpen = isolation.egressOpen || (restrictsEgress && allowsAllEgress)
+	}
+
+	peers := []networkingv1.NetworkPolicyPeer{}
+	if restrictsIngress {
+		for _, rule := range policy.Spec.Ingress {
+			peers = append(peers, rule.From...)
+		}
+	}
+	if restrictsEgress {
+		for _, rule := range policy.Spec.Egress {
+			peers = append(peers, rule.To...)
+		}
+	}
+
+	for _, peer := range peers {
+		if peer.IPBlock != nil {
+			continue
+		}
+
+		peerContainers, err := selectNetworkPolicyPeers(policy.Namespace, peer, containers, namespaceLabels)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, container := range peerContainers {
+			policyNetwork.members[container.ID] = container
+		}
+	}
+
+	return policyNetwork, nil
+}
+
+// networkPolicyTypes returns whether a network policy restricts the ingress and the egress traffic.
+// As in Kubernetes, when the policy types are not specified, the policy always restricts the ingress traffic
+// and restricts the egress traffic only when it has egress rules.
+func networkPolicyTypes(policy networkingv1.NetworkPolicy) (bool, bool) {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return true, len(policy.Spec.Egress) != 0
+	}
+
+	ingress, egress := false, false
+	for _, policyType := range policy.Spec.PolicyTypes {
+		switch policyType {
+		case networkingv1.PolicyTypeIngress:
+			ingress = true
+		case networkingv1.PolicyTypeEgress:
+			egress = true
+		}
+	}
+
+	return ingress, egress
+}
+
+// selectNetworkPolicyPeers returns the containers matching a peer of a network policy.
+// A peer without namespace selector targets the namespace of the policy, a peer without pod selector targets all the pods
+// of the selected namespaces.
+func selectNetworkPolicyPeers(policyNamespace string, peer networkingv1.NetworkPolicyPeer, containers []types.Container, namespaceLabels map[string]labels.Set) ([]types.Container, error) {
+	podSelector := labels.Everything()
+	if peer.PodSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(peer.PodSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer pod selector: %w", err)
+		}
+		podSelector = selector
+	}
+
+	var namespaceSelector labels.Selector
+	if peer.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(peer.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer namespace selector: %w", err)
+		}
+		namespaceSelector = selector
+	}
+
+	peers := []types.Container{}
+	for _, container := range containers {
+		namespace := container.Labels[k2dtypes.NamespaceNameLabelKey]
+
+		if namespaceSelector == nil && namespace != policyNamespace {
+			continue
+		}
+
+		if namespaceSelector != nil && !namespaceSelector.Matches(namespaceLabels[namespace]) {
+			continue
+		}
+
+		if podSelector.Matches(labels.Set(container.Labels)) {
+			peers = append(peers, container)
+		}
+	}
+
+	return peers, nil
+}
+
+// syncNetworkPolicyNetworks creates, updates and removes the networks used to approximate the network policies
+// so that they match the desired networks.
+func (adapter *KubeDockerAdapter) syncNetworkPolicyNetworks(ctx context.Context, desiredNetworks map[string]*networkPolicyNetwork) error {
+	existingNetworks, err := adapter.cli.NetworkList(ctx, types.NetworkListOptions{
+		Filters: dockerfilters.NewArgs(dockerfilters.Arg("label", k2dtypes.NetworkPolicyNameLabelKey)),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to list network policy networks: %w", err)
+	}
+
+	existing := map[string]bool{}
+	for _, existingNetwork := range existingNetworks {
+		desiredNetwork, ok := desiredNetworks[existingNetwork.Name]
+
+		// the internal option of a network cannot be updated, the network is re-created instead
+		if ok && desiredNetwork.internal == existingNetwork.Internal {
+			existing[existingNetwork.Name] = true
+			continue
+		}
+
+		err := adapter.removeNetworkPolicyNetwork(ctx, existingNetwork.Name)
+		if err != nil {
+			adapter.logger.Warnf("unable to remove network policy network %s: %s", existingNetwork.Name, err)
+		}
+	}
+
+	for networkName, desiredNetwork := range desiredNetworks {
+		if !existing[networkName] {
+			_, err := adapter.cli.NetworkCreate(ctx, networkName, types.NetworkCreate{
+				Driver:   "bridge",
+				Internal: desiredNetwork.internal,
+				Labels: map[string]string{
+					k2dtypes.NetworkPolicyNameLabelKey:      desiredNetwork.policy.Name,
+					k2dtypes.NetworkPolicyNamespaceLabelKey: desiredNetwork.policy.Namespace,
+				},
+			})
+			if err != nil {
+				adapter.logger.Warnf("unable to create network policy network %s: %s", networkName, err)
+				continue
+			}
+		}
+
+		err := adapter.syncNetworkPolicyNetworkMembers(ctx, networkName, desiredNetwork.members)
+		if err != nil {
+			adapter.logger.Warnf("unable to update the containers of network policy network %s: %s", networkName, err)
+		}
+	}
+
+	return nil
+}
+
+// syncNetworkPolicyNetworkMembers connects the members of a network policy network and disconnects the other containers.
+func (adapter *KubeDockerAdapter) syncNetworkPolicyNetworkMembers(ctx context.Context, networkName string, members map[string]types.Container) error {
+	policyNetwork, err := adapter.getNetwork(ctx, networkName)
+	if err != nil {
+		return err
+	}
+
+	for containerID := range policyNetwork.Containers {
+		if _, ok := members[containerID]; ok {
+			continue
+		}
+
+		err := adapter.cli.NetworkDisconnect(ctx, networkName, containerID, true)
+		if err != nil {
+			return fmt.Errorf("unable to disconnect container %s: %w", containerID, err)
+		}
+	}
+
+	for containerID, container := range members {
+		if _, ok := policyNetwork.Containers[containerID]; ok {
+			continue
+		}
+
+		err := adapter.cli.NetworkConnect(ctx, networkName, containerID, &network.EndpointSettings{
+			Aliases: buildContainerServiceAliases(container),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to connect container %s: %w", containerID, err)
+		}
+	}
+
+	return nil
+}
+
+// removeNetworkPolicyNetwork disconnects all the containers from a network policy network and removes it.
+func (adapter *KubeDockerAdapter) removeNetworkPolicyNetwork(ctx context.Context, networkName string) error {
+	policyNetwork, err := adapter.getNetwork(ctx, networkName)
+	if err != nil {
+		return err
+	}
+
+	for containerID := range policyNetwork.Containers {
+		err := adapter.cli.NetworkDisconnect(ctx, networkName, containerID, true)
+		if err != nil {
+			return fmt.Errorf("unable to disconnect container %s: %w", containerID, err)
+		}
+	}
+
+	return adapter.cli.NetworkRemove(ctx, networkName)
+}
+
+// syncNamespaceNetworkConnection disconnects an isolated container from the network of its namespace,
+// and reconnects a container that is no longer isolated to it.
+func (adapter *KubeDockerAdapter) syncNamespaceNetworkConnection(ctx context.Context, container types.Container, isolated bool) error {
+	networkName := container.Labels[k2dtypes.NetworkNameLabelKey]
+
+	connected := false
+	if container.NetworkSettings != nil {
+		_, connected = container.NetworkSettings.Networks[networkName]
+	}
+
+	if isolated && connected {
+		adapter.logger.Infow("isolating container from its namespace network",
+			"container", strings.TrimPrefix(container.Names[0], "/"),
+			"network", networkName,
+		)
+
+		return adapter.cli.NetworkDisconnect(ctx, networkName, container.ID, false)
+	}
+
+	if !isolated && !connected {
+		adapter.logger.Infow("reconnecting container to its namespace network",
+			"container", strings.TrimPrefix(container.Names[0], "/"),
+			"network", networkName,
+		)
+
+		return adapter.cli.NetworkConnect(ctx, networkName, container.ID, &network.EndpointSettings{
+			Aliases: buildContainerServiceAliases(container),
+		})
+	}
+
+	return nil
+}
+
+// isNetworkPolicyAttachable returns true when a container is attached to the network of its namespace,
+// containers using a specific network mode (e.g. host) being out of the scope of the network policies.
+func isNetworkPolicyAttachable(container types.Container) bool {
+	if container.Labels[k2dtypes.NetworkNameLabelKey] == "" {
+		return false
+	}
+
+	networkMode := container.HostConfig.NetworkMode
+	return networkMode != "host" && networkMode != "none" && !strings.HasPrefix(networkMode, "container:")
+}
+
+// isConnectedToNetworkPolicyNetwork returns true when a container is connected to at least one network policy network.
+func isConnectedToNetworkPolicyNetwork(networks map[string]*network.EndpointSettings) bool {
+	for networkName := range networks {
+		if strings.HasPrefix(networkName, naming.NetworkPolicyNetworkPrefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withoutNetworkPolicyNetworks returns the endpoints of a container without the network policy networks,
+// which are re-attached by the network policy loop. When the container has been isolated from the network of its namespace,
+// the namespace network is restored so that the container can be re-created (a container can only be created with a single network).
+func withoutNetworkPolicyNetworks(networks map[string]*network.EndpointSettings, containerLabels map[string]string) map[string]*network.EndpointSettings {
+	if !isConnectedToNetworkPolicyNetwork(networks) {
+		return networks
+	}
+
+	endpoints := map[string]*network.EndpointSettings{}
+	for networkName, endpoint := range networks {
+		if !strings.HasPrefix(networkName, naming.NetworkPolicyNetworkPrefix) {
+			endpoints[networkName] = endpoint
+		}
+	}
+
+	namespaceNetworkName := containerLabels[k2dtypes.NetworkNameLabelKey]
+	if len(endpoints) == 0 && namespaceNetworkName != "" {
+		endpoints[namespaceNetworkName] = &network.EndpointSettings{}
+	}
+
+	return endpoints
+}
+
+// buildContainerServiceAliases returns the service aliases of a container exposed by a service, as its primary
+// or an additional backend, or nil otherwise.
+func buildContainerServiceAliases(container types.Container) []string {
+	serviceName := container.Labels[k2dtypes.ServiceNameLabelKey]
+	if serviceName == "" {
+		serviceName = container.Labels[k2dtypes.ServiceBackendLabelKey]
+	}
+
+	if serviceName == "" {
+		return nil
+	}
+
+	return buildServiceAliases(serviceName, container.Labels[k2dtypes.NamespaceNameLabelKey])
+}
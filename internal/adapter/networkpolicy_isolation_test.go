@@ -0,0 +1,141 @@
+package adapter
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestNetworkPolicyTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    networkingv1.NetworkPolicySpec
+		ingress bool
+		egress  bool
+	}{
+		{
+			name:    "default types without egress rules",
+			spec:    networkingv1.NetworkPolicySpec{},
+			ingress: true,
+		},
+		{
+			name:    "default types with egress rules",
+			spec:    networkingv1.NetworkPolicySpec{Egress: []networkingv1.NetworkPolicyEgressRule{{}}},
+			ingress: true,
+			egress:  true,
+		},
+		{
+			name:   "egress only",
+			spec:   networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}},
+			egress: true,
+		},
+		{
+			name: "ingress and egress",
+			spec: networkingv1.NetworkPolicySpec{
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			},
+			ingress: true,
+			egress:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ingress, egress := networkPolicyTypes(networkingv1.NetworkPolicy{Spec: test.spec})
+			if ingress != test.ingress || egress != test.egress {
+				t.Errorf("expected ingress %t and egress %t, got %t and %t", test.ingress, test.egress, ingress, egress)
+			}
+		})
+	}
+}
+
+func TestSelectNetworkPolicyPeers(t *testing.T) {
+	newContainer := func(id, namespace, app string) types.Container {
+		return types.Container{ID: id, Labels: map[string]string{k2dtypes.NamespaceNameLabelKey: namespace, "app": app}}
+	}
+
+	containers := []types.Container{
+		newContainer("web", "default", "web"),
+		newContainer("db", "default", "db"),
+		newContainer("monitoring", "monitoring", "prometheus"),
+		newContainer("staging", "staging", "web"),
+	}
+
+	namespaceLabels := map[string]labels.Set{
+		"default":    {namespaceNameLabel: "default"},
+		"monitoring": {namespaceNameLabel: "monitoring", "team": "ops"},
+		"staging":    {namespaceNameLabel: "staging"},
+	}
+
+	tests := []struct {
+		name     string
+		peer     networkingv1.NetworkPolicyPeer
+		expected []string
+	}{
+		{
+			name:     "pod selector in the namespace of the policy",
+			peer:     networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+			expected: []string{"web"},
+		},
+		{
+			name:     "no selector selects the pods of the namespace of the policy",
+			peer:     networkingv1.NetworkPolicyPeer{},
+			expected: []string{"db", "web"},
+		},
+		{
+			name:     "namespace selector without pod selector",
+			peer:     networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ops"}}},
+			expected: []string{"monitoring"},
+		},
+		{
+			name: "namespace selector with pod selector",
+			peer: networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: &metav1.LabelSelector{},
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			expected: []string{"staging", "web"},
+		},
+		{
+			name:     "namespace selector on the namespace name label",
+			peer:     networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: "staging"}}},
+			expected: []string{"staging"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			peers, err := selectNetworkPolicyPeers("default", test.peer, containers, namespaceLabels)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			ids := []string{}
+			for _, peer := range peers {
+				ids = append(ids, peer.ID)
+			}
+			sort.Strings(ids)
+
+			if len(ids) != len(test.expected) {
+				t.Fatalf("expected peers %v, got %v", test.expected, ids)
+			}
+			for i := range ids {
+				if ids[i] != test.expected[i] {
+					t.Errorf("expected peers %v, got %v", test.expected, ids)
+					break
+				}
+			}
+		})
+	}
+
+	invalid := networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Unknown"}},
+	}}
+	if _, err := selectNetworkPolicyPeers("default", invalid, containers, namespaceLabels); err == nil {
+		t.Error("expected an error for an invalid pod selector")
+	}
+}
@@ -2,10 +2,16 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 
+	"github.com/portainer/k2d/internal/adapter/converter"
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
 	"github.com/portainer/k2d/internal/k8s"
+	"github.com/portainer/k2d/pkg/sysinfo"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kubernetes/pkg/apis/core"
@@ -53,6 +59,41 @@ func (adapter *KubeDockerAdapter) GetNode(ctx context.Context, nodeName string)
 	return &versionedNode, nil
 }
 
+// SetNodeUnschedulable cordons or uncordons a node.
+// The scheduling state of the node is persisted inside a system configmap so that it survives k2d restarts.
+// As k2d manages a single node, cordoning the node does not evict running pods, it is up to the client
+// to evict them (e.g. kubectl drain). It returns an ErrResourceNotFound error if the node does not exist.
+func (adapter *KubeDockerAdapter) SetNodeUnschedulable(ctx context.Context, nodeName string, unschedulable bool) error {
+	_, err := adapter.getNode(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+
+	configMapName := naming.BuildNodeSystemConfigMapName(nodeName)
+
+	if !unschedulable {
+		err = adapter.DeleteSystemConfigMap(configMapName)
+		if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+			return fmt.Errorf("unable to delete node system configmap: %w", err)
+		}
+		return nil
+	}
+
+	err = adapter.CreateSystemConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: configMapName,
+		},
+		Data: map[string]string{
+			nodeUnschedulableDataKey: strconv.FormatBool(unschedulable),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create node system configmap: %w", err)
+	}
+
+	return nil
+}
+
 func (adapter *KubeDockerAdapter) GetNodeTable(ctx context.Context) (*metav1.Table, error) {
 	nodeList, err := adapter.listNodes(ctx)
 	if err != nil {
@@ -77,10 +118,16 @@ func (adapter *KubeDockerAdapter) getNode(ctx context.Context, nodeName string)
 		return nil, fmt.Errorf("unable to retrieve docker server version: %w", err)
 	}
 
-	node := adapter.converter.ConvertInfoVersionToNode(info, version, adapter.startTime)
+	node := adapter.converter.ConvertInfoVersionToNode(info, version, adapter.startTime, adapter.getNodeHostStats())
+	adapter.setNodeSchedulingState(&node)
+
 	return &node, nil
 }
 
+// listNodes returns a list containing the single node backed by the Docker host the adapter is connected to.
+// Exposing several Docker hosts as separate nodes is not supported: the namespace networks are bridge networks local
+// to a host, so the service aliases and the DNS names of the pods would not resolve across hosts without an overlay
+// network (Swarm), and the volume backed ConfigMap and Secret stores live on a single host.
 func (adapter *KubeDockerAdapter) listNodes(ctx context.Context) (core.NodeList, error) {
 	info, err := adapter.cli.Info(ctx)
 	if err != nil {
@@ -92,13 +139,65 @@ func (adapter *KubeDockerAdapter) listNodes(ctx context.Context) (core.NodeList,
 		return core.NodeList{}, fmt.Errorf("unable to retrieve docker server version: %w", err)
 	}
 
+	node := adapter.converter.ConvertInfoVersionToNode(info, version, adapter.startTime, adapter.getNodeHostStats())
+	adapter.setNodeSchedulingState(&node)
+
 	return core.NodeList{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "NodeList",
 			APIVersion: "v1",
 		},
 		Items: []core.Node{
-			adapter.converter.ConvertInfoVersionToNode(info, version, adapter.startTime),
+			node,
 		},
 	}, nil
 }
+
+// getNodeHostStats retrieves the disk usage of the k2d data path and the memory usage of the host.
+// Statistics that cannot be retrieved are left empty and a warning is logged.
+func (adapter *KubeDockerAdapter) getNodeHostStats() converter.NodeHostStats {
+	stats := converter.NodeHostStats{}
+
+	diskUsage, err := sysinfo.GetDiskUsage(adapter.dataPath)
+	if err != nil {
+		adapter.logger.Warnf("unable to retrieve disk usage: %s", err)
+	} else {
+		stats.Disk = &diskUsage
+	}
+
+	memoryUsage, err := sysinfo.GetMemoryUsage()
+	if err != nil {
+		adapter.logger.Warnf("unable to retrieve memory usage: %s", err)
+	} else {
+		stats.Memory = &memoryUsage
+	}
+
+	return stats
+}
+
+// nodeUnschedulableDataKey is the key used to store the scheduling state of a node in its system configmap.
+const nodeUnschedulableDataKey = "unschedulable"
+
+// setNodeSchedulingState reflects the scheduling state persisted in the node system configmap
+// into the node spec. A cordoned node is marked as unschedulable and receives the
+// node.kubernetes.io/unschedulable:NoSchedule taint, as done by Kubernetes.
+func (adapter *KubeDockerAdapter) setNodeSchedulingState(node *core.Node) {
+	configMap, err := adapter.configMapStore.GetConfigMap(naming.BuildNodeSystemConfigMapName(node.Name), k2dtypes.K2DNamespaceName)
+	if err != nil {
+		if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+			adapter.logger.Warnf("unable to retrieve node system configmap: %s", err)
+		}
+		return
+	}
+
+	unschedulable, _ := strconv.ParseBool(configMap.Data[nodeUnschedulableDataKey])
+	if !unschedulable {
+		return
+	}
+
+	node.Spec.Unschedulable = true
+	node.Spec.Taints = append(node.Spec.Taints, core.Taint{
+		Key:    corev1.TaintNodeUnschedulable,
+		Effect: core.TaintEffectNoSchedule,
+	})
+}
@@ -0,0 +1,133 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// objectMetadataDataKey is the key used to store the metadata of a resource in its system configmap
+const objectMetadataDataKey = "metadata"
+
+// storeObjectMetadata persists the metadata of a resource served from a container (pod, deployment or service)
+// inside a system configmap. The container labels only hold the last applied configuration of the resource,
+// which does not include the labels, annotations and owner references set by kubectl and other tools afterwards
+// (e.g. kubectl annotate). The fields computed by k2d (UID, creation timestamp, resource version...) are not stored.
+//
+// Parameters:
+// - kind: The kind of the resource (Pod, Deployment or Service).
+// - objectMeta: The metadata of the resource, as submitted by the client.
+//
+// Returns:
+// - An error if the metadata cannot be stored.
+func (adapter *KubeDockerAdapter) storeObjectMetadata(kind string, objectMeta metav1.ObjectMeta) error {
+	metadata := metav1.ObjectMeta{
+		Labels:          objectMeta.Labels,
+		Annotations:     objectMeta.Annotations,
+		OwnerReferences: objectMeta.OwnerReferences,
+		Finalizers:      objectMeta.Finalizers,
+		ManagedFields:   objectMeta.ManagedFields,
+		GenerateName:    objectMeta.GenerateName,
+	}
+
+	metadataData, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("unable to marshal %s metadata: %w", kind, err)
+	}
+
+	err = adapter.CreateSystemConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: naming.BuildObjectMetadataSystemConfigMapName(kind, objectMeta.Name, objectMeta.Namespace),
+			Labels: map[string]string{
+				k2dtypes.NamespaceNameLabelKey: objectMeta.Namespace,
+			},
+		},
+		Data: map[string]string{
+			objectMetadataDataKey: string(metadataData),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to store %s metadata system configmap: %w", kind, err)
+	}
+
+	return nil
+}
+
+// getObjectMetadata returns the metadata of a resource stored in a system configmap (see storeObjectMetadata).
+// It returns an ErrResourceNotFound error if no metadata was stored for the resource.
+func (adapter *KubeDockerAdapter) getObjectMetadata(kind, name, namespace string) (*metav1.ObjectMeta, error) {
+	configMap, err := adapter.GetSystemConfigMap(naming.BuildObjectMetadataSystemConfigMapName(kind, name, namespace))
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			return nil, adaptererr.ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("unable to get %s metadata system configmap: %w", kind, err)
+	}
+
+	metadata := metav1.ObjectMeta{}
+	err = json.Unmarshal([]byte(configMap.Data[objectMetadataDataKey]), &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal %s metadata: %w", kind, err)
+	}
+
+	return &metadata, nil
+}
+
+// deleteObjectMetadata removes the metadata stored for a resource, if any.
+func (adapter *KubeDockerAdapter) deleteObjectMetadata(kind, name, namespace string) {
+	err := adapter.DeleteSystemConfigMap(naming.BuildObjectMetadataSystemConfigMapName(kind, name, namespace))
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		adapter.logger.Warnf("unable to delete the metadata of %s %s/%s: %s", kind, namespace, name, err)
+	}
+}
+
+// restoreObjectMetadata merges the metadata stored for a resource (see storeObjectMetadata) into the metadata
+// of the resource built from its container. A failure to retrieve the stored metadata is logged and ignored so that
+// the resource can still be served.
+func (adapter *KubeDockerAdapter) restoreObjectMetadata(kind string, objectMeta *metav1.ObjectMeta) {
+	metadata, err := adapter.getObjectMetadata(kind, objectMeta.Name, objectMeta.Namespace)
+	if err != nil {
+		if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+			adapter.logger.Warnf("unable to retrieve the metadata of %s %s/%s: %s", kind, objectMeta.Namespace, objectMeta.Name, err)
+		}
+		return
+	}
+
+	mergeObjectMetadata(objectMeta, *metadata)
+}
+
+// mergeObjectMetadata merges the stored metadata of a resource into the metadata built from its container.
+// The stored labels and annotations are the latest ones submitted by the client and replace the labels and annotations
+// built from the last applied configuration, so that the removed keys are not served anymore. The last applied
+// configuration annotation is preserved when it is not part of the stored annotations.
+func mergeObjectMetadata(objectMeta *metav1.ObjectMeta, metadata metav1.ObjectMeta) {
+	lastAppliedConfiguration, hasLastAppliedConfiguration := objectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"]
+
+	objectMeta.Labels = metadata.Labels
+
+	objectMeta.Annotations = map[string]string{}
+	for key, value := range metadata.Annotations {
+		objectMeta.Annotations[key] = value
+	}
+
+	if _, exists := objectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"]; !exists && hasLastAppliedConfiguration {
+		objectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = lastAppliedConfiguration
+	}
+
+	if len(metadata.OwnerReferences) != 0 {
+		objectMeta.OwnerReferences = metadata.OwnerReferences
+	}
+
+	objectMeta.Finalizers = metadata.Finalizers
+	objectMeta.ManagedFields = metadata.ManagedFields
+
+	if metadata.GenerateName != "" {
+		objectMeta.GenerateName = metadata.GenerateName
+	}
+}
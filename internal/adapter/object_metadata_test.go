@@ -0,0 +1,49 @@
+package adapter
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMergeObjectMetadata(t *testing.T) {
+	objectMeta := metav1.ObjectMeta{
+		Name:      "web",
+		Namespace: "default",
+		UID:       "uid",
+		Labels:    map[string]string{"app": "web"},
+		Annotations: map[string]string{
+			"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			"removed": "true",
+		},
+	}
+
+	ownerReferences := []metav1.OwnerReference{{Kind: "ConfigMap", Name: "owner"}}
+	mergeObjectMetadata(&objectMeta, metav1.ObjectMeta{
+		Labels:          map[string]string{"app": "web", "tier": "frontend"},
+		Annotations:     map[string]string{"team": "edge"},
+		OwnerReferences: ownerReferences,
+		Finalizers:      []string{"example.com/cleanup"},
+	})
+
+	if !reflect.DeepEqual(objectMeta.Labels, map[string]string{"app": "web", "tier": "frontend"}) {
+		t.Errorf("expected the stored labels to be served, got %v", objectMeta.Labels)
+	}
+
+	expectedAnnotations := map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+		"team": "edge",
+	}
+	if !reflect.DeepEqual(objectMeta.Annotations, expectedAnnotations) {
+		t.Errorf("expected annotations %v, got %v", expectedAnnotations, objectMeta.Annotations)
+	}
+
+	if !reflect.DeepEqual(objectMeta.OwnerReferences, ownerReferences) || len(objectMeta.Finalizers) != 1 {
+		t.Errorf("expected the owner references and finalizers to be restored, got %v %v", objectMeta.OwnerReferences, objectMeta.Finalizers)
+	}
+
+	if objectMeta.Name != "web" || objectMeta.UID != "uid" {
+		t.Errorf("expected the fields computed by k2d to be preserved, got %s %s", objectMeta.Name, objectMeta.UID)
+	}
+}
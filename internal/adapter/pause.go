@@ -0,0 +1,135 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// pauseDeployment pauses a deployment (spec.paused set to true, e.g. kubectl rollout pause) by stopping its container
+// without removing it, so that the state of the container is preserved and restored when the deployment is resumed.
+// The changes made to the deployment while it is paused are not applied to the container, they are recorded
+// and rolled out when the deployment is resumed.
+//
+// A container is created and immediately stopped when a paused deployment does not have any container yet,
+// so that the deployment can still be listed.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - deployment: The paused deployment.
+// - opts: The options used to create the container of the deployment when it does not exist.
+//
+// Returns:
+// - An error if the container cannot be created or stopped.
+func (adapter *KubeDockerAdapter) pauseDeployment(ctx context.Context, deployment *appsv1.Deployment, opts ContainerCreationOptions) error {
+	containerName := naming.BuildContainerName(deployment.Name, deployment.Namespace)
+
+	container, err := adapter.getContainer(ctx, containerName)
+	if err != nil {
+		return fmt.Errorf("unable to inspect container: %w", err)
+	}
+
+	if container == nil {
+		err = adapter.createContainerFromPodSpec(ctx, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	stopped, err := adapter.ensureDeploymentContainerStopped(ctx, containerName)
+	if err != nil {
+		return err
+	}
+
+	err = adapter.storeWorkloadRecord(k2dtypes.DeploymentWorkloadType, deployment.Name, deployment.Namespace, deployment)
+	if err != nil {
+		return fmt.Errorf("unable to store the definition of deployment %s: %w", deployment.Name, err)
+	}
+
+	if stopped {
+		adapter.RecordEvent(core.ObjectReference{Kind: "Deployment", Name: deployment.Name, Namespace: deployment.Namespace},
+			core.EventTypeNormal, "DeploymentPaused", "Deployment paused, container stopped")
+	}
+
+	return nil
+}
+
+// resumeDeployment starts the container of a deployment that was previously paused.
+// It is a no-op if the deployment was not paused or if its container does not exist.
+func (adapter *KubeDockerAdapter) resumeDeployment(ctx context.Context, deploymentName, namespace string) error {
+	record, err := adapter.getDeploymentRecord(deploymentName, namespace)
+	if err != nil {
+		return err
+	}
+
+	if record == nil || !record.Spec.Paused {
+		return nil
+	}
+
+	container, err := adapter.getContainer(ctx, naming.BuildContainerName(deploymentName, namespace))
+	if err != nil {
+		return fmt.Errorf("unable to inspect container: %w", err)
+	}
+
+	if container == nil || (container.State != nil && container.State.Running) {
+		return nil
+	}
+
+	err = adapter.cli.ContainerStart(ctx, container.ID, types.ContainerStartOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to start container: %w", err)
+	}
+
+	adapter.RecordEvent(core.ObjectReference{Kind: "Deployment", Name: deploymentName, Namespace: namespace},
+		core.EventTypeNormal, "DeploymentResumed", "Deployment resumed, container started")
+
+	return nil
+}
+
+// ensureDeploymentContainerStopped stops the container of a paused deployment if it is running,
+// using the termination grace period of the deployment. It returns true when the container was stopped.
+func (adapter *KubeDockerAdapter) ensureDeploymentContainerStopped(ctx context.Context, containerName string) (bool, error) {
+	container, err := adapter.getContainer(ctx, containerName)
+	if err != nil {
+		return false, fmt.Errorf("unable to inspect container: %w", err)
+	}
+
+	if container == nil || container.State == nil || !container.State.Running {
+		return false, nil
+	}
+
+	err = adapter.stopContainer(ctx, container.ID, container.Config.Labels)
+	if err != nil {
+		return false, fmt.Errorf("unable to stop container: %w", err)
+	}
+
+	return true, nil
+}
+
+// getDeploymentRecord returns the definition of a deployment stored by storeWorkloadRecord,
+// nil when the deployment does not have any record.
+func (adapter *KubeDockerAdapter) getDeploymentRecord(deploymentName, namespace string) (*appsv1.Deployment, error) {
+	record, err := adapter.GetSystemConfigMap(naming.BuildWorkloadSystemConfigMapName(deploymentName, namespace))
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to get workload system configmap: %w", err)
+	}
+
+	deployment := &appsv1.Deployment{}
+	err = json.Unmarshal([]byte(record.Data[workloadManifestDataKey]), deployment)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal deployment: %w", err)
+	}
+
+	return deployment, nil
+}
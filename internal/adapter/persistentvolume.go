@@ -3,7 +3,9 @@ package adapter
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/errdefs"
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
@@ -16,7 +18,27 @@ import (
 )
 
 func (adapter *KubeDockerAdapter) DeletePersistentVolume(ctx context.Context, persistentVolumeName string) error {
-	err := adapter.cli.VolumeRemove(ctx, persistentVolumeName, true)
+	return adapter.removeVolume(ctx, persistentVolumeName)
+}
+
+// removeVolume removes a Docker volume after ensuring that it is not used by any container, running or stopped.
+// It returns an ErrResourceConflict error listing the containers using the volume otherwise.
+func (adapter *KubeDockerAdapter) removeVolume(ctx context.Context, volumeName string) error {
+	containers, err := adapter.cli.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filters.ByVolume(volumeName)})
+	if err != nil {
+		return fmt.Errorf("unable to list containers using volume %s: %w", volumeName, err)
+	}
+
+	if len(containers) > 0 {
+		containerNames := make([]string, 0, len(containers))
+		for _, container := range containers {
+			containerNames = append(containerNames, strings.TrimPrefix(container.Names[0], "/"))
+		}
+
+		return fmt.Errorf("%w: volume %s is still used by the following containers: %s", adaptererr.ErrResourceConflict, volumeName, strings.Join(containerNames, ", "))
+	}
+
+	err = adapter.cli.VolumeRemove(ctx, volumeName, true)
 	if err != nil {
 		return fmt.Errorf("unable to remove Docker volume: %w", err)
 	}
@@ -33,6 +55,15 @@ func (adapter *KubeDockerAdapter) GetPersistentVolume(ctx context.Context, persi
 		return nil, fmt.Errorf("unable to inspect docker volume %s: %w", persistentVolumeName, err)
 	}
 
+	volumes, err := adapter.getVolumesWithUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if volumeWithUsage, found := volumes[volume.Name]; found {
+		volume.UsageData = volumeWithUsage.UsageData
+	}
+
 	configMaps, err := adapter.ListSystemConfigMaps()
 	if err != nil {
 		return nil, fmt.Errorf("unable to list configmaps: %w", err)
@@ -110,6 +141,11 @@ func (adapter *KubeDockerAdapter) listPersistentVolumes(ctx context.Context) (co
 		return core.PersistentVolumeList{}, fmt.Errorf("unable to list volumes to return the output values from a Docker volume: %w", err)
 	}
 
+	volumes, err := adapter.getVolumesWithUsage(ctx)
+	if err != nil {
+		return core.PersistentVolumeList{}, err
+	}
+
 	configMaps, err := adapter.ListSystemConfigMaps()
 	if err != nil {
 		return core.PersistentVolumeList{}, fmt.Errorf("unable to list system configmaps: %w", err)
@@ -118,6 +154,10 @@ func (adapter *KubeDockerAdapter) listPersistentVolumes(ctx context.Context) (co
 	persistentVolumes := []core.PersistentVolume{}
 
 	for _, volume := range volumeList.Volumes {
+		if volumeWithUsage, found := volumes[volume.Name]; found {
+			volume.UsageData = volumeWithUsage.UsageData
+		}
+
 		var boundPVCConfigMap *corev1.ConfigMap
 
 		for _, configMap := range configMaps.Items {
@@ -150,3 +190,21 @@ func (adapter *KubeDockerAdapter) listPersistentVolumes(ctx context.Context) (co
 		Items: persistentVolumes,
 	}, nil
 }
+
+// getVolumesWithUsage returns the Docker volumes along with their usage data (docker system df), indexed by volume name.
+// The usage data is used to report the capacity of the persistent volumes and persistent volume claims.
+func (adapter *KubeDockerAdapter) getVolumesWithUsage(ctx context.Context) (map[string]*volume.Volume, error) {
+	diskUsage, err := adapter.cli.DiskUsage(ctx, types.DiskUsageOptions{
+		Types: []types.DiskUsageObject{types.VolumeObject},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve volumes disk usage: %w", err)
+	}
+
+	volumes := make(map[string]*volume.Volume, len(diskUsage.Volumes))
+	for _, volume := range diskUsage.Volumes {
+		volumes[volume.Name] = volume
+	}
+
+	return volumes, nil
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/portainer/k2d/internal/adapter/converter"
 	"github.com/portainer/k2d/internal/adapter/naming"
 	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
 	"github.com/portainer/k2d/internal/k8s"
@@ -33,8 +35,10 @@ import (
 //   - Dynamic Volume Creation:
 //     If the PVC's `Spec.VolumeName` is empty, the function dynamically creates a Docker volume.
 //     1. Generates a name for the Docker volume based on the PVC's name and namespace.
-//     2. Creates the Docker volume with the generated name.
-//     3. Labels the volume with k2d-specific labels for identification (See `k2dtypes.StorageTypeLabelKey` and `k2dtypes.PersistentVolumeNameLabelKey`).
+//     2. Retrieves the storage class of the PVC (`Spec.StorageClassName`, the built-in local storage class when not specified)
+//     to determine the Docker volume driver and driver options (e.g. an NFS share mounted through the local driver).
+//     3. Creates the Docker volume with the generated name.
+//     4. Labels the volume with k2d-specific labels for identification (See `k2dtypes.StorageTypeLabelKey` and `k2dtypes.PersistentVolumeNameLabelKey`).
 //
 //   - Helm-managed PVCs:
 //     If the PVC has a label "app.kubernetes.io/managed-by" set to "Helm," the PVC's state is serialized and stored as an annotation for later use.
@@ -45,9 +49,15 @@ import (
 //     2. The name of the corresponding Docker volume.
 //     3. The name of the PVC itself.
 //     4. The last-applied configuration of the PVC, if available.
+//     5. The name of the storage class of the PVC.
 func (adapter *KubeDockerAdapter) CreatePersistentVolumeClaim(ctx context.Context, persistentVolumeClaim *corev1.PersistentVolumeClaim) error {
 	var volumeName string
 
+	storageClassName := converter.DefaultStorageClassName
+	if persistentVolumeClaim.Spec.StorageClassName != nil && *persistentVolumeClaim.Spec.StorageClassName != "" {
+		storageClassName = *persistentVolumeClaim.Spec.StorageClassName
+	}
+
 	if persistentVolumeClaim.Spec.VolumeName != "" {
 		volumeName = persistentVolumeClaim.Spec.VolumeName
 		adapter.logger.Debugf("using existing persistent volume %s for the requested persistent volume claim", volumeName)
@@ -60,12 +70,22 @@ func (adapter *KubeDockerAdapter) CreatePersistentVolumeClaim(ctx context.Contex
 		volumeName = naming.BuildPersistentVolumeName(persistentVolumeClaim.Name, persistentVolumeClaim.Namespace)
 		adapter.logger.Debugf("creating persistent volume %s for the requested persistent volume claim", volumeName)
 
-		_, err := adapter.cli.VolumeCreate(ctx, volume.CreateOptions{
-			Name:   volumeName,
-			Driver: "local",
+		storageClass, err := adapter.getStorageClass(storageClassName)
+		if err != nil {
+			return fmt.Errorf("unable to get storage class %s: %w", storageClassName, err)
+		}
+
+		driver, driverOptions := converter.GetStorageClassVolumeDriver(storageClass)
+
+		_, err = adapter.cli.VolumeCreate(ctx, volume.CreateOptions{
+			Name:       volumeName,
+			Driver:     driver,
+			DriverOpts: driverOptions,
 			Labels: map[string]string{
 				k2dtypes.StorageTypeLabelKey:          k2dtypes.PersistentVolumeStorageType,
 				k2dtypes.PersistentVolumeNameLabelKey: volumeName,
+				k2dtypes.StorageClassNameLabelKey:     storageClassName,
+				k2dtypes.ReclaimPolicyLabelKey:        string(converter.GetStorageClassReclaimPolicy(storageClass)),
 			},
 		})
 
@@ -90,6 +110,7 @@ func (adapter *KubeDockerAdapter) CreatePersistentVolumeClaim(ctx context.Contex
 				k2dtypes.PersistentVolumeClaimNameLabelKey:            persistentVolumeClaim.Name,
 				k2dtypes.PersistentVolumeClaimTargetNamespaceLabelKey: persistentVolumeClaim.Namespace,
 				k2dtypes.LastAppliedConfigLabelKey:                    persistentVolumeClaim.ObjectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"],
+				k2dtypes.StorageClassNameLabelKey:                     storageClassName,
 			},
 		},
 	}
@@ -102,9 +123,30 @@ func (adapter *KubeDockerAdapter) CreatePersistentVolumeClaim(ctx context.Contex
 	return nil
 }
 
+// DeletePersistentVolumeClaim removes a persistent volume claim and applies the reclaim policy of the Docker volume bound to it:
+//   - Delete: the Docker volume is removed. The claim is not removed and an ErrResourceConflict error is returned
+//     while the volume is still used by a container.
+//   - Retain: the Docker volume is kept and the associated persistent volume is reported as Released.
+//
+// The reclaim policy is copied from the storage class when the volume is provisioned. Statically assigned volumes
+// and volumes provisioned by previous versions of k2d are always retained.
 func (adapter *KubeDockerAdapter) DeletePersistentVolumeClaim(ctx context.Context, persistentVolumeClaimName string, namespaceName string) error {
 	pvcName := naming.BuildPVCSystemConfigMapName(persistentVolumeClaimName, namespaceName)
-	err := adapter.DeleteSystemConfigMap(pvcName)
+
+	pvcConfigMap, err := adapter.GetSystemConfigMap(pvcName)
+	if err != nil {
+		return fmt.Errorf("unable to get the system configmap associated to the persistent volume claim: %w", err)
+	}
+
+	volumeName := pvcConfigMap.Labels[k2dtypes.PersistentVolumeNameLabelKey]
+	if volumeName != "" {
+		err = adapter.reclaimVolume(ctx, volumeName)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = adapter.DeleteSystemConfigMap(pvcName)
 	if err != nil {
 		return fmt.Errorf("unable to delete persistent volume claim: %w", err)
 	}
@@ -112,6 +154,26 @@ func (adapter *KubeDockerAdapter) DeletePersistentVolumeClaim(ctx context.Contex
 	return nil
 }
 
+// reclaimVolume removes the Docker volume bound to a deleted persistent volume claim when its reclaim policy is Delete.
+func (adapter *KubeDockerAdapter) reclaimVolume(ctx context.Context, volumeName string) error {
+	volume, err := adapter.cli.VolumeInspect(ctx, volumeName)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("unable to inspect docker volume %s: %w", volumeName, err)
+	}
+
+	if volume.Labels[k2dtypes.ReclaimPolicyLabelKey] != string(core.PersistentVolumeReclaimDelete) {
+		adapter.logger.Debugw("retaining volume of the deleted persistent volume claim",
+			"volume", volumeName,
+		)
+		return nil
+	}
+
+	return adapter.removeVolume(ctx, volumeName)
+}
+
 func (adapter *KubeDockerAdapter) GetPersistentVolumeClaim(ctx context.Context, persistentVolumeClaimName string, namespaceName string) (*corev1.PersistentVolumeClaim, error) {
 	pvcName := naming.BuildPVCSystemConfigMapName(persistentVolumeClaimName, namespaceName)
 	persistentVolumeClaimConfigMap, err := adapter.GetSystemConfigMap(pvcName)
@@ -119,7 +181,12 @@ func (adapter *KubeDockerAdapter) GetPersistentVolumeClaim(ctx context.Context,
 		return nil, fmt.Errorf("unable to get the system configmap associated to the persistent volume claim: %w", err)
 	}
 
-	persistentVolumeClaim, err := adapter.updatePersistentVolumeClaimFromVolume(persistentVolumeClaimConfigMap.Labels[k2dtypes.LastAppliedConfigLabelKey], persistentVolumeClaimConfigMap)
+	volumes, err := adapter.getVolumesWithUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	persistentVolumeClaim, err := adapter.updatePersistentVolumeClaimFromVolume(persistentVolumeClaimConfigMap.Labels[k2dtypes.LastAppliedConfigLabelKey], persistentVolumeClaimConfigMap, volumes)
 	if err != nil {
 		return nil, fmt.Errorf("unable to update persistent volume claim from volume: %w", err)
 	}
@@ -139,21 +206,25 @@ func (adapter *KubeDockerAdapter) GetPersistentVolumeClaim(ctx context.Context,
 	return &versionedpersistentVolumeClaim, nil
 }
 
-func (adapter *KubeDockerAdapter) updatePersistentVolumeClaimFromVolume(persistentVolumeClaimData string, configMap *corev1.ConfigMap) (*core.PersistentVolumeClaim, error) {
+// updatePersistentVolumeClaimFromVolume builds a PersistentVolumeClaim from its last applied configuration (if any),
+// its system configmap and the Docker volume bound to it, looked up in the specified volumes (see getVolumesWithUsage).
+func (adapter *KubeDockerAdapter) updatePersistentVolumeClaimFromVolume(persistentVolumeClaimData string, configMap *corev1.ConfigMap, volumes map[string]*volume.Volume) (*core.PersistentVolumeClaim, error) {
 	versionedPersistentVolumeClaim := &corev1.PersistentVolumeClaim{}
 
-	err := json.Unmarshal([]byte(persistentVolumeClaimData), &versionedPersistentVolumeClaim)
-	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal versioned service: %w", err)
+	if persistentVolumeClaimData != "" {
+		err := json.Unmarshal([]byte(persistentVolumeClaimData), &versionedPersistentVolumeClaim)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal versioned persistent volume claim: %w", err)
+		}
 	}
 
 	persistentVolumeClaim := core.PersistentVolumeClaim{}
-	err = adapter.ConvertK8SResource(versionedPersistentVolumeClaim, &persistentVolumeClaim)
+	err := adapter.ConvertK8SResource(versionedPersistentVolumeClaim, &persistentVolumeClaim)
 	if err != nil {
 		return nil, fmt.Errorf("unable to convert internal object to versioned object: %w", err)
 	}
 
-	err = adapter.converter.UpdateConfigMapToPersistentVolumeClaim(&persistentVolumeClaim, configMap)
+	err = adapter.converter.UpdateConfigMapToPersistentVolumeClaim(&persistentVolumeClaim, configMap, volumes[configMap.Labels[k2dtypes.PersistentVolumeNameLabelKey]])
 	if err != nil {
 		return nil, fmt.Errorf("unable to convert Docker volume to PersistentVolumeClaim: %w", err)
 	}
@@ -197,6 +268,11 @@ func (adapter *KubeDockerAdapter) listPersistentVolumeClaims(ctx context.Context
 		return core.PersistentVolumeClaimList{}, fmt.Errorf("unable to list configmaps: %w", err)
 	}
 
+	volumes, err := adapter.getVolumesWithUsage(ctx)
+	if err != nil {
+		return core.PersistentVolumeClaimList{}, err
+	}
+
 	persistentVolumeClaims := core.PersistentVolumeClaimList{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "PersistentVolumeClaimList",
@@ -205,18 +281,20 @@ func (adapter *KubeDockerAdapter) listPersistentVolumeClaims(ctx context.Context
 	}
 
 	for _, configMap := range configMaps.Items {
+		if configMap.Labels[k2dtypes.PersistentVolumeClaimNameLabelKey] == "" {
+			continue
+		}
+
 		namespace := configMap.Labels[k2dtypes.PersistentVolumeClaimTargetNamespaceLabelKey]
 
 		if namespaceName == "" || namespace == namespaceName {
 			pvcLastAppliedConfig := configMap.Labels[k2dtypes.LastAppliedConfigLabelKey]
 
-			if pvcLastAppliedConfig != "" {
-				persistentVolumeClaim, err := adapter.updatePersistentVolumeClaimFromVolume(pvcLastAppliedConfig, &configMap)
-				if err != nil {
-					return core.PersistentVolumeClaimList{}, fmt.Errorf("unable to update persistent volume claim from volume: %w", err)
-				}
-				persistentVolumeClaims.Items = append(persistentVolumeClaims.Items, *persistentVolumeClaim)
+			persistentVolumeClaim, err := adapter.updatePersistentVolumeClaimFromVolume(pvcLastAppliedConfig, &configMap, volumes)
+			if err != nil {
+				return core.PersistentVolumeClaimList{}, fmt.Errorf("unable to update persistent volume claim from volume: %w", err)
 			}
+			persistentVolumeClaims.Items = append(persistentVolumeClaims.Items, *persistentVolumeClaim)
 		}
 	}
 
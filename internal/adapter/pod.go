@@ -3,23 +3,39 @@ package adapter
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/docker/docker/api/types"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
 	"github.com/portainer/k2d/internal/k8s"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/apis/core"
 )
 
 type PodLogOptions struct {
 	Timestamps bool
 	Follow     bool
 	Tail       string
+	// Since only returns logs newer than a relative duration (e.g. 10s) or a RFC3339 timestamp.
+	Since string
+	// Previous returns the logs retained for the previous instance of the container.
+	Previous bool
+}
+
+type PodAttachOptions struct {
+	Stdin  bool
+	Stdout bool
+	Stderr bool
 }
 
 func (adapter *KubeDockerAdapter) CreateContainerFromPod(ctx context.Context, pod *corev1.Pod) error {
 	opts := ContainerCreationOptions{
+		annotations:   pod.Annotations,
 		containerName: pod.Name,
 		namespace:     pod.Namespace,
 		podSpec:       pod.Spec,
@@ -36,19 +52,83 @@ func (adapter *KubeDockerAdapter) CreateContainerFromPod(ctx context.Context, po
 
 	opts.lastAppliedConfiguration = pod.ObjectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"]
 
-	return adapter.createContainerFromPodSpec(ctx, opts)
+	err := adapter.createContainerFromPodSpec(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	err = adapter.storeWorkloadRecord(k2dtypes.PodWorkloadType, pod.Name, pod.Namespace, pod)
+	if err != nil {
+		adapter.logger.Warnf("unable to store the definition of pod %s, it will not be reconciled: %s", pod.Name, err)
+	}
+
+	err = adapter.storeObjectMetadata("Pod", pod.ObjectMeta)
+	if err != nil {
+		adapter.logger.Warnf("unable to store the metadata of pod %s: %s", pod.Name, err)
+	}
+
+	return nil
 }
 
 func (adapter *KubeDockerAdapter) DeletePod(ctx context.Context, podName string, namespace string) error {
 	container, err := adapter.findContainerFromPodAndNamespace(ctx, podName, namespace)
 	if err != nil {
+		if _, pendingErr := adapter.getPendingPod(namespace, podName); pendingErr == nil {
+			adapter.DeletePendingPod(namespace, podName)
+			return nil
+		}
 		return fmt.Errorf("unable to find container associated to the pod %s/%s: %w", namespace, podName, err)
 	}
 
-	err = adapter.cli.ContainerRemove(ctx, container.Names[0], types.ContainerRemoveOptions{Force: true})
+	err = adapter.deleteWorkloadRecord(podName, namespace)
+	if err != nil {
+		adapter.logger.Warnf("unable to delete pod definition: %s", err)
+	}
+
+	if container.State == "running" {
+		err = adapter.stopContainer(ctx, container.ID, container.Labels)
+		if err != nil {
+			adapter.logger.Warnf("unable to gracefully stop container: %s", err)
+		}
+	}
+
+	err = adapter.cli.ContainerRemove(ctx, container.Names[0], types.ContainerRemoveOptions{Force: true, RemoveVolumes: true})
 	if err != nil {
 		adapter.logger.Warnf("unable to remove container: %s", err)
+		return nil
 	}
+	adapter.deleteContainerPayloads(container.ID)
+
+	return nil
+}
+
+// EvictPod gracefully evicts a pod by running the preStop hook of its container, stopping it within
+// the termination grace period and removing it.
+// The termination grace period defined in the pod spec can be overridden with gracePeriodSeconds.
+// It returns an ErrResourceNotFound error if the pod does not exist.
+func (adapter *KubeDockerAdapter) EvictPod(ctx context.Context, podName string, namespace string, gracePeriodSeconds *int64) error {
+	container, err := adapter.findContainerFromPodAndNamespace(ctx, podName, namespace)
+	if err != nil {
+		return fmt.Errorf("unable to find container associated to the pod %s/%s: %w", namespace, podName, err)
+	}
+
+	err = adapter.deleteWorkloadRecord(podName, namespace)
+	if err != nil {
+		adapter.logger.Warnf("unable to delete pod definition: %s", err)
+	}
+
+	if container.State == "running" {
+		err = adapter.stopContainerWithGracePeriod(ctx, container.ID, container.Labels, gracePeriodSeconds)
+		if err != nil {
+			return fmt.Errorf("unable to stop container: %w", err)
+		}
+	}
+
+	err = adapter.cli.ContainerRemove(ctx, container.ID, types.ContainerRemoveOptions{Force: true, RemoveVolumes: true})
+	if err != nil {
+		return fmt.Errorf("unable to remove container: %w", err)
+	}
+	adapter.deleteContainerPayloads(container.ID)
 
 	return nil
 }
@@ -58,15 +138,29 @@ func (adapter *KubeDockerAdapter) DeletePod(ctx context.Context, podName string,
 // The logic used to build a pod from a container is based on the type returned by the list operation (types.Container)
 // and not the inspect operation (types.ContainerJSON).
 // This is because using the inspect operation everywhere would be more expensive overall.
+// When the container does not exist, the pod is looked up in the pending pods (e.g. its image cannot be pulled).
 func (adapter *KubeDockerAdapter) GetPod(ctx context.Context, podName string, namespace string) (*corev1.Pod, error) {
+	var pod core.Pod
+
 	container, err := adapter.findContainerFromPodAndNamespace(ctx, podName, namespace)
 	if err != nil {
-		return nil, fmt.Errorf("unable to find container associated to the pod %s/%s: %w", namespace, podName, err)
-	}
+		if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+			return nil, fmt.Errorf("unable to find container associated to the pod %s/%s: %w", namespace, podName, err)
+		}
 
-	pod, err := adapter.buildPodFromContainer(*container)
-	if err != nil {
-		return nil, fmt.Errorf("unable to get pod: %w", err)
+		pod, err = adapter.getPendingPod(namespace, podName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find container associated to the pod %s/%s: %w", namespace, podName, err)
+		}
+	} else {
+		if adapter.ephemeralStorageUsage {
+			adapter.setContainerSize(ctx, container)
+		}
+
+		pod, err = adapter.buildPodFromContainer(*container)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get pod: %w", err)
+		}
 	}
 
 	versionedPod := corev1.Pod{
@@ -84,18 +178,69 @@ func (adapter *KubeDockerAdapter) GetPod(ctx context.Context, podName string, na
 	return &versionedPod, nil
 }
 
+// setContainerSize sets the size of the writable layer and of the root filesystem of a container, which are only
+// computed by Docker on demand. Failures are only logged as the size is informational.
+func (adapter *KubeDockerAdapter) setContainerSize(ctx context.Context, container *types.Container) {
+	containerDetails, _, err := adapter.cli.ContainerInspectWithRaw(ctx, container.ID, true)
+	if err != nil {
+		adapter.logger.Warnf("unable to compute the size of container %s: %s", container.ID, err)
+		return
+	}
+
+	if containerDetails.SizeRw != nil {
+		container.SizeRw = *containerDetails.SizeRw
+	}
+	if containerDetails.SizeRootFs != nil {
+		container.SizeRootFs = *containerDetails.SizeRootFs
+	}
+}
+
+// AttachToPod attaches to the main process of the container associated with the specified pod.
+// The returned HijackedResponse must be closed by the caller once the attach session is over.
+func (adapter *KubeDockerAdapter) AttachToPod(ctx context.Context, namespace string, podName string, opts PodAttachOptions) (types.HijackedResponse, error) {
+	container, err := adapter.findContainerFromPodAndNamespace(ctx, podName, namespace)
+	if err != nil {
+		return types.HijackedResponse{}, fmt.Errorf("unable to find container associated to the pod %s/%s: %w", namespace, podName, err)
+	}
+
+	return adapter.cli.ContainerAttach(ctx, container.ID, types.ContainerAttachOptions{
+		Stream: true,
+		Stdin:  opts.Stdin,
+		Stdout: opts.Stdout,
+		Stderr: opts.Stderr,
+	})
+}
+
+// ResizePodTerminal resizes the TTY of the container associated with the specified pod.
+func (adapter *KubeDockerAdapter) ResizePodTerminal(ctx context.Context, namespace string, podName string, height, width uint) error {
+	container, err := adapter.findContainerFromPodAndNamespace(ctx, podName, namespace)
+	if err != nil {
+		return fmt.Errorf("unable to find container associated to the pod %s/%s: %w", namespace, podName, err)
+	}
+
+	return adapter.cli.ContainerResize(ctx, container.ID, types.ResizeOptions{
+		Height: height,
+		Width:  width,
+	})
+}
+
 func (adapter *KubeDockerAdapter) GetPodLogs(ctx context.Context, namespace string, podName string, opts PodLogOptions) (io.ReadCloser, error) {
 	container, err := adapter.findContainerFromPodAndNamespace(ctx, podName, namespace)
 	if err != nil {
 		return nil, fmt.Errorf("unable to find container associated to the pod %s/%s: %w", namespace, podName, err)
 	}
 
+	if opts.Previous {
+		return adapter.getPreviousContainerLogs(ctx, container.ID, strings.TrimPrefix(container.Names[0], "/"))
+	}
+
 	return adapter.cli.ContainerLogs(ctx, container.ID, types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
 		Timestamps: opts.Timestamps,
 		Follow:     opts.Follow,
 		Tail:       opts.Tail,
+		Since:      opts.Since,
 	})
 }
 
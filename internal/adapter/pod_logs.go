@@ -0,0 +1,95 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/docker/docker/api/types"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+)
+
+// LogsFolder is the name of the directory, relative to the k2d data path, where the logs
+// of previous container instances are retained.
+const LogsFolder = "logs"
+
+// retainedLogsTailLines is the maximum number of lines retained for the previous instance of a container,
+// so that the retained logs of a verbose container do not fill the disk.
+const retainedLogsTailLines = "10000"
+
+func (adapter *KubeDockerAdapter) previousLogsFilePath(containerName string) string {
+	return path.Join(adapter.logsPath, containerName+".previous.log")
+}
+
+// retainContainerLogs saves the logs of a container on disk before it is removed so that
+// they can be retrieved later on through the "previous" option of the pod logs API.
+// The logs are streamed to disk in the raw format returned by the Docker API, only the last lines are retained
+// (see retainedLogsTailLines) and any existing logs retained for the same container name are overwritten.
+// The retained logs are removed with the pod (see removePreviousContainerLogs).
+func (adapter *KubeDockerAdapter) retainContainerLogs(ctx context.Context, containerID, containerName string) error {
+	logs, err := adapter.cli.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       retainedLogsTailLines,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to retrieve container logs: %w", err)
+	}
+	defer logs.Close()
+
+	file, err := os.Create(adapter.previousLogsFilePath(containerName))
+	if err != nil {
+		return fmt.Errorf("unable to create logs file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, logs)
+	if err != nil {
+		return fmt.Errorf("unable to write logs file: %w", err)
+	}
+
+	return nil
+}
+
+// removePreviousContainerLogs removes the logs retained for the previous instance of a container once its pod is deleted.
+// Failures are only logged as the pod is already removed.
+func (adapter *KubeDockerAdapter) removePreviousContainerLogs(containerName string) {
+	err := os.Remove(adapter.previousLogsFilePath(containerName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		adapter.logger.Warnf("unable to remove previous logs file of container %s: %s", containerName, err)
+	}
+}
+
+// getPreviousContainerLogs returns the logs of the previous instance of a container.
+// When the container was restarted by the Docker daemon according to its restart policy, the container is not re-created
+// and its logs are not retained: the logs written before the current start of the container are returned instead,
+// which include the output of every previous run of the container.
+// Otherwise the logs retained when the container was last re-created are returned.
+// It returns an ErrResourceNotFound error if the container was never restarted nor re-created.
+func (adapter *KubeDockerAdapter) getPreviousContainerLogs(ctx context.Context, containerID, containerName string) (io.ReadCloser, error) {
+	containerDetails, err := adapter.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to inspect container: %w", err)
+	}
+
+	if containerDetails.RestartCount > 0 && containerDetails.State != nil && containerDetails.State.StartedAt != "" {
+		return adapter.cli.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+			Until:      containerDetails.State.StartedAt,
+		})
+	}
+
+	file, err := os.Open(adapter.previousLogsFilePath(containerName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, adaptererr.ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("unable to open logs file: %w", err)
+	}
+
+	return file, nil
+}
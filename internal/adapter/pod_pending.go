@@ -0,0 +1,194 @@
+package adapter
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// pendingPodStore is an in-memory store of the pods whose container does not exist yet, either because the operation
+// creating the container is still being processed or because the creation failed (e.g. the image cannot be pulled).
+// These pods are reported with a Pending phase so that users can diagnose the failure (e.g. kubectl get pods, kubectl describe pod).
+type pendingPodStore struct {
+	mu   sync.RWMutex
+	pods map[string]*core.Pod
+}
+
+func newPendingPodStore() *pendingPodStore {
+	return &pendingPodStore{
+		pods: map[string]*core.Pod{},
+	}
+}
+
+func pendingPodKey(namespace, name string) string {
+	if isDefaultOrEmptyNamespace(namespace) {
+		namespace = "default"
+	}
+
+	return namespace + "/" + name
+}
+
+// SetPendingPod records a pod whose container is being created. The pod is reported with a Pending phase and
+// a ContainerCreating waiting reason until the container is created (see DeletePendingPod) or the creation fails
+// (see SetPendingPodFailure).
+func (adapter *KubeDockerAdapter) SetPendingPod(pod *corev1.Pod) error {
+	internalPod := core.Pod{}
+	err := adapter.ConvertK8SResource(pod, &internalPod)
+	if err != nil {
+		return err
+	}
+
+	if internalPod.Namespace == "" {
+		internalPod.Namespace = "default"
+	}
+
+	if internalPod.UID == "" {
+		internalPod.UID = uuid.NewUUID()
+	}
+	internalPod.CreationTimestamp = metav1.NewTime(time.Now())
+	internalPod.Status = core.PodStatus{
+		Phase: core.PodPending,
+		Conditions: []core.PodCondition{
+			{
+				Type:               core.PodScheduled,
+				Status:             core.ConditionTrue,
+				LastTransitionTime: internalPod.CreationTimestamp,
+			},
+		},
+	}
+	setPendingPodContainerStatus(&internalPod, "ContainerCreating", "")
+
+	store := adapter.pendingPodStore
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.pods[pendingPodKey(internalPod.Namespace, internalPod.Name)] = &internalPod
+
+	return nil
+}
+
+// SetPendingPodFailure reports the failure of the creation of the container of a pending pod through the waiting reason
+// of its container, using the same reasons as the kubelet:
+//   - ErrImagePull, or ImagePullBackOff while the creation is retried, when the image cannot be pulled.
+//   - CreateContainerConfigError when a resource referenced by the pod (e.g. a configmap) does not exist.
+//   - CreateContainerError for any other failure.
+//
+// A pod that cannot be scheduled (e.g. the node lacks the requested capacity) is reported with a PodScheduled condition
+// set to false with the Unschedulable reason instead, as done by the Kubernetes scheduler.
+//
+// It does nothing if the pod is not pending.
+func (adapter *KubeDockerAdapter) SetPendingPodFailure(namespace, name string, err error, retrying bool) {
+	reason := "CreateContainerError"
+	switch {
+	case errors.Is(err, adaptererr.ErrImagePull) && retrying:
+		reason = "ImagePullBackOff"
+	case errors.Is(err, adaptererr.ErrImagePull):
+		reason = "ErrImagePull"
+	case errors.Is(err, adaptererr.ErrResourceNotFound):
+		reason = "CreateContainerConfigError"
+	}
+
+	store := adapter.pendingPodStore
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	pod, found := store.pods[pendingPodKey(namespace, name)]
+	if !found {
+		return
+	}
+
+	if errors.Is(err, adaptererr.ErrUnschedulable) {
+		pod.Status.ContainerStatuses = nil
+		pod.Status.Conditions = []core.PodCondition{
+			{
+				Type:               core.PodScheduled,
+				Status:             core.ConditionFalse,
+				Reason:             corev1.PodReasonUnschedulable,
+				Message:            err.Error(),
+				LastTransitionTime: metav1.NewTime(time.Now()),
+			},
+		}
+		return
+	}
+
+	setPendingPodContainerStatus(pod, reason, err.Error())
+}
+
+// DeletePendingPod removes a pod from the pending pods, usually once its container has been created.
+func (adapter *KubeDockerAdapter) DeletePendingPod(namespace, name string) {
+	store := adapter.pendingPodStore
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.pods, pendingPodKey(namespace, name))
+}
+
+// getPendingPod returns a copy of a pending pod. It returns an ErrResourceNotFound error if the pod is not pending.
+func (adapter *KubeDockerAdapter) getPendingPod(namespace, name string) (core.Pod, error) {
+	store := adapter.pendingPodStore
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	pod, found := store.pods[pendingPodKey(namespace, name)]
+	if !found {
+		return core.Pod{}, adaptererr.ErrResourceNotFound
+	}
+
+	return *pod.DeepCopy(), nil
+}
+
+// listPendingPods returns a copy of the pending pods of a namespace, or of all namespaces when the namespace is empty,
+// that are not already part of the specified pods.
+func (adapter *KubeDockerAdapter) listPendingPods(namespace string, pods []core.Pod) []core.Pod {
+	existingPods := map[string]struct{}{}
+	for _, pod := range pods {
+		existingPods[pendingPodKey(pod.Namespace, pod.Name)] = struct{}{}
+	}
+
+	store := adapter.pendingPodStore
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	pendingPods := []core.Pod{}
+	for key, pod := range store.pods {
+		if namespace != "" && pendingPodKey(namespace, pod.Name) != key {
+			continue
+		}
+
+		if _, exists := existingPods[key]; exists {
+			continue
+		}
+
+		pendingPods = append(pendingPods, *pod.DeepCopy())
+	}
+
+	return pendingPods
+}
+
+// setPendingPodContainerStatus sets the status of the first container of a pending pod to waiting with the specified reason.
+func setPendingPodContainerStatus(pod *core.Pod, reason, message string) {
+	containerName, image := "", ""
+	if len(pod.Spec.Containers) > 0 {
+		containerName = pod.Spec.Containers[0].Name
+		image = pod.Spec.Containers[0].Image
+	}
+
+	pod.Status.ContainerStatuses = []core.ContainerStatus{
+		{
+			Name:  containerName,
+			Image: image,
+			State: core.ContainerState{
+				Waiting: &core.ContainerStateWaiting{
+					Reason:  reason,
+					Message: message,
+				},
+			},
+		},
+	}
+}
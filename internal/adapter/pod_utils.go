@@ -11,6 +11,7 @@ import (
 	"github.com/portainer/k2d/internal/adapter/filters"
 	"github.com/portainer/k2d/internal/adapter/naming"
 	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kubernetes/pkg/apis/core"
 )
@@ -18,7 +19,11 @@ import (
 // buildPodFromContainer converts a Docker container into a Kubernetes Pod object.
 // The function leverages an internal converter to map the basic attributes of a container
 // to a Pod. Additionally, it attempts to extract the last-applied PodSpec configuration
-// (if available) from the container labels and sets it to the Pod's Spec field.
+// (if available) from the container labels and sets it to the Pod's Spec field, as well as
+// the labels and annotations of the Pod from the last applied configuration of the workload.
+// The restart count and the last termination state of the container are set from its restart history.
+// The metadata stored for the pod, if any, is merged last (see restoreObjectMetadata).
+// The references to the configurations stored in the store backend are resolved before the conversion.
 //
 // Parameters:
 // - container: The Docker container that needs to be converted into a Pod.
@@ -27,7 +32,9 @@ import (
 // - core.Pod: The converted Pod object.
 // - error: An error object if any error occurs during the conversion.
 func (adapter *KubeDockerAdapter) buildPodFromContainer(container types.Container) (core.Pod, error) {
+	container.Labels = adapter.resolveContainerLabels(container.ID, container.Labels)
 	pod := adapter.converter.ConvertContainerToPod(container)
+	adapter.setPodRestartStatus(&pod, container.ID)
 
 	if container.Labels[k2dtypes.PodLastAppliedConfigLabelKey] != "" {
 		internalPodSpecData := container.Labels[k2dtypes.PodLastAppliedConfigLabelKey]
@@ -41,9 +48,81 @@ func (adapter *KubeDockerAdapter) buildPodFromContainer(container types.Containe
 		pod.Spec = podSpec
 	}
 
+	setPodMetadataFromLastAppliedConfiguration(&pod, container.Labels[k2dtypes.LastAppliedConfigLabelKey])
+	adapter.restoreObjectMetadata("Pod", &pod.ObjectMeta)
+	setAllocatedHostPortsAnnotation(&pod, container.Ports)
+	setEphemeralStorageUsageAnnotation(&pod, container)
+
 	return pod, nil
 }
 
+// setEphemeralStorageUsageAnnotation reports the size of the writable layer of the container of a pod
+// in the container.k2d.io/ephemeral-storage-usage annotation.
+// The size is only computed by Docker when requested (see ContainerListOptions.Size), which is detected
+// using the size of the root filesystem of the container as it always includes the image.
+func setEphemeralStorageUsageAnnotation(pod *core.Pod, container types.Container) {
+	if container.SizeRootFs == 0 {
+		return
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+
+	pod.Annotations[k2dtypes.EphemeralStorageUsageAnnotationKey] = resource.NewQuantity(container.SizeRw, resource.BinarySI).String()
+}
+
+// setPodMetadataFromLastAppliedConfiguration sets the labels and annotations of a Pod from the last applied
+// configuration of the workload that created it. The metadata of the Pod is used when the workload is a Pod and
+// the metadata of the Pod template is used when the workload is a Deployment.
+// The last applied configuration annotation set by the converter is preserved.
+// An invalid last applied configuration is ignored.
+//
+// Parameters:
+// - pod: The Pod to update.
+// - lastAppliedConfiguration: The last applied configuration of the workload (JSON encoded).
+func setPodMetadataFromLastAppliedConfiguration(pod *core.Pod, lastAppliedConfiguration string) {
+	if lastAppliedConfiguration == "" {
+		return
+	}
+
+	workload := struct {
+		metav1.TypeMeta   `json:",inline"`
+		metav1.ObjectMeta `json:"metadata,omitempty"`
+		Spec              struct {
+			Template struct {
+				metav1.ObjectMeta `json:"metadata,omitempty"`
+			} `json:"template,omitempty"`
+		} `json:"spec,omitempty"`
+	}{}
+
+	err := json.Unmarshal([]byte(lastAppliedConfiguration), &workload)
+	if err != nil {
+		return
+	}
+
+	var workloadMetadata metav1.ObjectMeta
+	switch workload.Kind {
+	case "Pod":
+		workloadMetadata = workload.ObjectMeta
+	case "Deployment":
+		workloadMetadata = workload.Spec.Template.ObjectMeta
+	default:
+		return
+	}
+
+	pod.Labels = workloadMetadata.Labels
+
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	for key, value := range workloadMetadata.Annotations {
+		if _, exists := pod.Annotations[key]; !exists {
+			pod.Annotations[key] = value
+		}
+	}
+}
+
 // findContainerFromPodAndNamespace searches for a Docker container based on a given Pod name and namespace.
 // It lists all the containers and filters them based on the Pod and namespace information.
 // If the namespace is neither 'default' nor empty, it adds specific filters to pinpoint the search.
@@ -94,13 +173,16 @@ func (adapter *KubeDockerAdapter) findContainerFromPodAndNamespace(ctx context.C
 //
 //  1. Prepares Docker container listing options. If the namespace is neither 'default' nor empty,
 //     it adds a filter to only include containers that are part of the given Kubernetes namespace.
+//     The size of the containers is requested when the ephemeral-storage usage of the pods is reported.
 //
 // 2. Calls the Docker API to list all containers that match the prepared listing options.
 //
 //  3. Invokes buildPodList to convert the list of Docker containers into a list of Kubernetes Pod objects.
 //     During this conversion, each container's metadata and spec are translated to the corresponding fields in a Pod object.
 //
-// 4. Returns a PodList object, which is a collection of the generated Pod objects, wrapped with metadata.
+//  4. Adds the pending pods of the namespace, whose container does not exist yet (e.g. its image cannot be pulled).
+//
+// 5. Returns a PodList object, which is a collection of the generated Pod objects, wrapped with metadata.
 //
 // Parameters:
 // - ctx: The context within which the function should operate. This is used for timeouts and cancellations.
@@ -110,7 +192,7 @@ func (adapter *KubeDockerAdapter) findContainerFromPodAndNamespace(ctx context.C
 // - core.PodList: A list of Kubernetes Pods encapsulated in a PodList object, along with Kubernetes metadata.
 // - error: An error object which could contain various types of errors including API call failures, JSON unmarshalling errors, etc.
 func (adapter *KubeDockerAdapter) getPodListFromContainers(ctx context.Context, namespace string) (core.PodList, error) {
-	listOptions := types.ContainerListOptions{All: true}
+	listOptions := types.ContainerListOptions{All: true, Size: adapter.ephemeralStorageUsage}
 	if !isDefaultOrEmptyNamespace(namespace) {
 		listOptions.Filters = filters.ByNamespace(namespace)
 	}
@@ -125,6 +207,8 @@ func (adapter *KubeDockerAdapter) getPodListFromContainers(ctx context.Context,
 		return core.PodList{}, err
 	}
 
+	pods = append(pods, adapter.listPendingPods(namespace, pods)...)
+
 	return core.PodList{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "PodList",
@@ -0,0 +1,26 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestSetEphemeralStorageUsageAnnotation(t *testing.T) {
+	pod := core.Pod{}
+	setEphemeralStorageUsageAnnotation(&pod, types.Container{SizeRw: 12 * 1024 * 1024, SizeRootFs: 80 * 1024 * 1024})
+
+	if usage := pod.Annotations[k2dtypes.EphemeralStorageUsageAnnotationKey]; usage != "12Mi" {
+		t.Errorf("expected an ephemeral-storage usage of 12Mi, got %q", usage)
+	}
+
+	pod = core.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+	setEphemeralStorageUsageAnnotation(&pod, types.Container{})
+
+	if _, found := pod.Annotations[k2dtypes.EphemeralStorageUsageAnnotationKey]; found {
+		t.Error("expected no ephemeral-storage usage when the size of the container was not computed")
+	}
+}
@@ -0,0 +1,229 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/portainer/k2d/internal/adapter/converter"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// workloadManifestDataKey is the key used to store the definition of a workload in its system configmap
+const workloadManifestDataKey = "manifest"
+
+// storeWorkloadRecord persists the definition of a workload (deployment or pod) inside a system configmap.
+// Unlike the container labels, the record survives the removal of the container and is used by the
+// reconciliation loop to re-create the containers that have drifted from their definition.
+func (adapter *KubeDockerAdapter) storeWorkloadRecord(workloadType, name, namespace string, workload interface{}) error {
+	workloadData, err := json.Marshal(workload)
+	if err != nil {
+		return fmt.Errorf("unable to marshal workload: %w", err)
+	}
+
+	err = adapter.CreateSystemConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: naming.BuildWorkloadSystemConfigMapName(name, namespace),
+			Labels: map[string]string{
+				k2dtypes.WorkloadTypeLabelKey:  workloadType,
+				k2dtypes.NamespaceNameLabelKey: namespace,
+			},
+		},
+		Data: map[string]string{
+			workloadManifestDataKey: string(workloadData),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to store workload system configmap: %w", err)
+	}
+
+	return nil
+}
+
+// deleteWorkloadRecord removes the definition of a workload so that it is no longer reconciled.
+func (adapter *KubeDockerAdapter) deleteWorkloadRecord(name, namespace string) error {
+	err := adapter.DeleteSystemConfigMap(naming.BuildWorkloadSystemConfigMapName(name, namespace))
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		return fmt.Errorf("unable to delete workload system configmap: %w", err)
+	}
+
+	return nil
+}
+
+// ReconcileWorkloads compares the definition of each workload (deployment or pod) created through k2d
+// with the actual state of its container and corrects any drift:
+//   - When the container is missing (e.g. removed manually), it is re-created from the workload definition.
+//   - When the container runs an image that differs from the workload definition, it is re-created.
+//   - When the container is detached from the network of its namespace, it is reconnected to it,
+//     unless it has been isolated by a network policy.
+//   - When the deployment is paused, its container is stopped if it is running and is not re-created.
+//
+// A failure to reconcile a workload does not prevent the other workloads from being reconciled, the error is logged
+// and a Warning event is recorded against the workload.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+//
+// Returns:
+// - An error if the workload definitions cannot be listed.
+func (adapter *KubeDockerAdapter) ReconcileWorkloads(ctx context.Context) error {
+	configMaps, err := adapter.ListSystemConfigMaps()
+	if err != nil {
+		return fmt.Errorf("unable to list system configmaps: %w", err)
+	}
+
+	for _, configMap := range configMaps.Items {
+		if !strings.HasPrefix(configMap.Name, naming.WorkloadSystemConfigMapPrefix) {
+			continue
+		}
+
+		err := adapter.reconcileWorkload(ctx, configMap)
+		if err != nil {
+			adapter.logger.Errorw("unable to reconcile workload",
+				"system_configmap", configMap.Name,
+				"error", err,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (adapter *KubeDockerAdapter) reconcileWorkload(ctx context.Context, record corev1.ConfigMap) error {
+	switch record.Labels[k2dtypes.WorkloadTypeLabelKey] {
+	case k2dtypes.DeploymentWorkloadType:
+		deployment := &appsv1.Deployment{}
+		err := json.Unmarshal([]byte(record.Data[workloadManifestDataKey]), deployment)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshal deployment: %w", err)
+		}
+
+		// The container of a paused deployment is kept stopped (e.g. after a restart of the Docker daemon) and not re-created
+		if deployment.Spec.Paused {
+			_, err := adapter.ensureDeploymentContainerStopped(ctx, naming.BuildContainerName(deployment.Name, deployment.Namespace))
+			return err
+		}
+
+		reason, err := adapter.detectWorkloadDrift(ctx, "Deployment", deployment.Name, deployment.Namespace, deployment.Spec.Template.Spec)
+		if err != nil || reason == "" {
+			return err
+		}
+
+		return adapter.recordReconcileResult("Deployment", deployment.Name, deployment.Namespace, reason,
+			adapter.CreateContainerFromDeployment(ctx, deployment))
+	case k2dtypes.PodWorkloadType:
+		pod := &corev1.Pod{}
+		err := json.Unmarshal([]byte(record.Data[workloadManifestDataKey]), pod)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshal pod: %w", err)
+		}
+
+		reason, err := adapter.detectWorkloadDrift(ctx, "Pod", pod.Name, pod.Namespace, pod.Spec)
+		if err != nil || reason == "" {
+			return err
+		}
+
+		return adapter.recordReconcileResult("Pod", pod.Name, pod.Namespace, reason,
+			adapter.CreateContainerFromPod(ctx, pod))
+	}
+
+	return nil
+}
+
+// detectWorkloadDrift inspects the container of a workload and returns the reason why it must be re-created,
+// or an empty string when the container does not need to be re-created.
+// Containers detached from the network of their namespace are reconnected to it.
+// Containers that must be re-created are removed by this function.
+func (adapter *KubeDockerAdapter) detectWorkloadDrift(ctx context.Context, kind, name, namespace string, podSpec corev1.PodSpec) (string, error) {
+	containerName := naming.BuildContainerName(name, namespace)
+
+	container, err := adapter.getContainer(ctx, containerName)
+	if err != nil {
+		return "", fmt.Errorf("unable to inspect container: %w", err)
+	}
+
+	if container == nil {
+		return "container is missing", nil
+	}
+
+	if len(podSpec.Containers) > 0 && !converter.IsSameImage(container.Config.Image, podSpec.Containers[0].Image) {
+		reason := fmt.Sprintf("container image %s differs from the expected image %s", container.Config.Image, podSpec.Containers[0].Image)
+
+		err = adapter.stopContainer(ctx, container.ID, container.Config.Labels)
+		if err != nil {
+			adapter.logger.Warnf("unable to gracefully stop container %s: %s", containerName, err)
+		}
+
+		err = adapter.cli.ContainerRemove(ctx, container.ID, types.ContainerRemoveOptions{Force: true})
+		if err != nil {
+			return "", fmt.Errorf("unable to remove container: %w", err)
+		}
+		adapter.deleteContainerPayloads(container.ID)
+
+		return reason, nil
+	}
+
+	networkName := naming.BuildNetworkName(namespace)
+	if podSpec.HostNetwork || container.NetworkSettings == nil || usesSpecificNetworkMode(container.HostConfig) {
+		return "", nil
+	}
+
+	// a container connected to a network policy network has been isolated on purpose, see ApplyNetworkPolicies
+	_, connected := container.NetworkSettings.Networks[networkName]
+	if !connected && !isConnectedToNetworkPolicyNetwork(container.NetworkSettings.Networks) {
+		adapter.logger.Infow("reconnecting container to its namespace network",
+			"container", containerName,
+			"network", networkName,
+		)
+
+		err = adapter.cli.NetworkConnect(ctx, networkName, container.ID, nil)
+		if err != nil {
+			return "", fmt.Errorf("unable to connect container to network %s: %w", networkName, err)
+		}
+
+		adapter.RecordEvent(core.ObjectReference{Kind: kind, Name: name, Namespace: namespace}, core.EventTypeNormal, "Reconciled",
+			fmt.Sprintf("container reconnected to network %s", networkName))
+	}
+
+	return "", nil
+}
+
+// usesSpecificNetworkMode returns true when a container uses the host, none or container:<name> network mode
+// (see the container.k2d.io/network-mode annotation), such a container is never connected to the network of its namespace.
+func usesSpecificNetworkMode(hostConfig *container.HostConfig) bool {
+	if hostConfig == nil {
+		return false
+	}
+
+	return hostConfig.NetworkMode.IsHost() || hostConfig.NetworkMode.IsNone() || hostConfig.NetworkMode.IsContainer()
+}
+
+// recordReconcileResult records an event describing the outcome of the re-creation of a drifted workload.
+func (adapter *KubeDockerAdapter) recordReconcileResult(kind, name, namespace, reason string, err error) error {
+	involvedObject := core.ObjectReference{Kind: kind, Name: name, Namespace: namespace}
+
+	if err != nil {
+		adapter.RecordEvent(involvedObject, core.EventTypeWarning, "FailedReconcile", fmt.Sprintf("unable to re-create container (%s): %s", reason, err))
+		return fmt.Errorf("unable to re-create container: %w", err)
+	}
+
+	adapter.logger.Infow("workload reconciled",
+		"kind", kind,
+		"name", name,
+		"namespace", namespace,
+		"reason", reason,
+	)
+	adapter.RecordEvent(involvedObject, core.EventTypeNormal, "Reconciled", fmt.Sprintf("container re-created: %s", reason))
+
+	return nil
+}
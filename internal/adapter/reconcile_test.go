@@ -0,0 +1,27 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestUsesSpecificNetworkMode(t *testing.T) {
+	tests := []struct {
+		hostConfig *container.HostConfig
+		expected   bool
+	}{
+		{hostConfig: nil, expected: false},
+		{hostConfig: &container.HostConfig{}, expected: false},
+		{hostConfig: &container.HostConfig{NetworkMode: "k2d-default"}, expected: false},
+		{hostConfig: &container.HostConfig{NetworkMode: "host"}, expected: true},
+		{hostConfig: &container.HostConfig{NetworkMode: "none"}, expected: true},
+		{hostConfig: &container.HostConfig{NetworkMode: "container:web"}, expected: true},
+	}
+
+	for _, test := range tests {
+		if usesSpecificNetworkMode(test.hostConfig) != test.expected {
+			t.Errorf("expected usesSpecificNetworkMode(%+v) to return %t", test.hostConfig, test.expected)
+		}
+	}
+}
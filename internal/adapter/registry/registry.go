@@ -0,0 +1,143 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/client"
+	"github.com/portainer/k2d/pkg/filesystem"
+)
+
+// caCertificateFilename is the name of the CA certificate file looked up by the Docker daemon
+// in the certificates directory of a registry (e.g. /etc/docker/certs.d/registry.local:5000/ca.crt)
+const caCertificateFilename = "ca.crt"
+
+// Options represents the configuration of the registries used when pulling images
+type Options struct {
+	// InsecureRegistries are the registries (host[:port]) served over HTTP or with a certificate that cannot be verified.
+	// Insecure connections are allowed by the Docker daemon configuration only, see CheckInsecureRegistry
+	InsecureRegistries []string
+	// CABundles associates a registry (host[:port]) with the path of the CA bundle used to verify its certificate
+	CABundles map[string]string
+	// CertsPath is the certificates directory of the Docker daemon (e.g. /etc/docker/certs.d), mounted inside the k2d container
+	CertsPath string
+}
+
+// IsInsecure returns true if the registry is configured as an insecure registry.
+func (opts Options) IsInsecure(registry string) bool {
+	for _, insecureRegistry := range opts.InsecureRegistries {
+		if insecureRegistry == registry {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CABundleRegistries returns the sorted list of registries associated with a custom CA bundle.
+func (opts Options) CABundleRegistries() []string {
+	registries := make([]string, 0, len(opts.CABundles))
+	for registry := range opts.CABundles {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+
+	return registries
+}
+
+// InstallCABundles copies the CA bundle of each registry inside the certificates directory of the Docker daemon,
+// where it is picked up by the daemon the next time an image is pulled from this registry. No restart of the daemon is required.
+// It returns an error if the certificates directory does not exist, as the CA bundles would not be visible to the daemon
+// when the directory is not mounted inside the k2d container, or if a CA bundle cannot be read or written.
+func InstallCABundles(opts Options) error {
+	if len(opts.CABundles) == 0 {
+		return nil
+	}
+
+	certsPathInfo, err := os.Stat(opts.CertsPath)
+	if err != nil {
+		return fmt.Errorf("unable to access the certificates directory %s of the container runtime, make sure it is mounted inside the k2d container: %w", opts.CertsPath, err)
+	}
+
+	if !certsPathInfo.IsDir() {
+		return fmt.Errorf("the certificates directory %s of the container runtime is not a directory", opts.CertsPath)
+	}
+
+	for registry, caBundlePath := range opts.CABundles {
+		data, err := os.ReadFile(caBundlePath)
+		if err != nil {
+			return fmt.Errorf("unable to read CA bundle of registry %s: %w", registry, err)
+		}
+
+		registryCertsPath := path.Join(opts.CertsPath, registry)
+		err = filesystem.CreateDir(registryCertsPath)
+		if err != nil {
+			return fmt.Errorf("unable to create certificates directory of registry %s: %w", registry, err)
+		}
+
+		err = os.WriteFile(path.Join(registryCertsPath, caCertificateFilename), data, 0644)
+		if err != nil {
+			return fmt.Errorf("unable to write CA bundle of registry %s: %w", registry, err)
+		}
+	}
+
+	return nil
+}
+
+// ImageRegistry returns the registry (host[:port]) hosting an image, docker.io for images without a registry.
+// It returns an empty string if the image name cannot be parsed.
+func ImageRegistry(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return ""
+	}
+
+	return reference.Domain(named)
+}
+
+// CheckInsecureRegistry ensures that an image hosted on a registry configured as insecure can be pulled.
+// It does not enable insecure connections: the Docker API does not support insecure pulls on a per request basis,
+// insecure registries must be declared in the configuration of the Docker daemon (insecure-registries). This function
+// returns an error explaining how to fix the configuration when the daemon does not consider the registry as insecure,
+// instead of letting the pull fail with a TLS error.
+func CheckInsecureRegistry(ctx context.Context, cli *client.Client, opts Options, image string) error {
+	registry := ImageRegistry(image)
+	if registry == "" || !opts.IsInsecure(registry) {
+		return nil
+	}
+
+	info, err := cli.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve docker server info: %w", err)
+	}
+
+	if info.RegistryConfig == nil {
+		return nil
+	}
+
+	indexConfig, found := info.RegistryConfig.IndexConfigs[registry]
+	if found && !indexConfig.Secure {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(registry)
+	if err != nil {
+		host = registry
+	}
+
+	ip := net.ParseIP(host)
+	if ip != nil {
+		for _, cidr := range info.RegistryConfig.InsecureRegistryCIDRs {
+			if (*net.IPNet)(cidr).Contains(ip) {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("registry %s is configured as insecure but the container runtime does not allow insecure connections to it, add it to the insecure-registries of the daemon configuration", registry)
+}
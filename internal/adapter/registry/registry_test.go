@@ -0,0 +1,40 @@
+package registry
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestInstallCABundles(t *testing.T) {
+	caBundlePath := path.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caBundlePath, []byte("certificate"), 0600)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	certsPath := t.TempDir()
+	err = InstallCABundles(Options{CABundles: map[string]string{"registry.local:5000": caBundlePath}, CertsPath: certsPath})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	content, err := os.ReadFile(path.Join(certsPath, "registry.local:5000", caCertificateFilename))
+	if err != nil || string(content) != "certificate" {
+		t.Errorf("expected the CA bundle to be installed, got %q: %v", content, err)
+	}
+}
+
+func TestInstallCABundlesMissingCertsPath(t *testing.T) {
+	certsPath := path.Join(t.TempDir(), "certs.d")
+
+	err := InstallCABundles(Options{CABundles: map[string]string{"registry.local:5000": "/certs/ca.pem"}, CertsPath: certsPath})
+	if err == nil {
+		t.Fatal("expected an error when the certificates directory does not exist")
+	}
+
+	err = InstallCABundles(Options{CertsPath: certsPath})
+	if err != nil {
+		t.Errorf("expected no error without CA bundles, got %s", err)
+	}
+}
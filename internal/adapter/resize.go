@@ -0,0 +1,147 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/portainer/k2d/internal/adapter/converter"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// resizeContainer applies a change of the resource requirements of a workload (e.g. kubectl set resources) to its
+// existing container in place using the Docker update API, avoiding the downtime of a re-creation.
+// The container is only updated in place when the resources of the containers are the only change between the last applied
+// configuration of the container and the new configuration, and when the new resources can be applied by the Docker update API:
+// a limit or a reservation cannot be removed and the GPU requests cannot be changed.
+// The stored configuration of the container is then updated so that the workload reflects the new resources.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - existingContainer: The existing container, with its labels resolved.
+// - containerCfg: The configuration of the container built from the new configuration.
+// - options: The container creation options.
+//
+// Returns:
+// - true when the container has been updated in place, false when it must be re-created. A failure to update the container
+// in place is logged and the container is re-created.
+func (adapter *KubeDockerAdapter) resizeContainer(ctx context.Context, existingContainer *types.ContainerJSON, containerCfg converter.ContainerConfiguration, options ContainerCreationOptions) bool {
+	if !onlyContainerResourcesChanged(existingContainer.Config.Labels[k2dtypes.LastAppliedConfigLabelKey], options.lastAppliedConfiguration) {
+		return false
+	}
+
+	if !canUpdateResourcesInPlace(existingContainer.HostConfig.Resources, containerCfg.HostConfig.Resources) {
+		return false
+	}
+
+	// The labels of the container are resolved by the caller, the references to the payloads are read from Docker
+	containerDetails, err := adapter.cli.ContainerInspect(ctx, existingContainer.ID)
+	if err != nil {
+		adapter.logger.Warnf("unable to inspect container %s, the container will be recreated: %s", containerCfg.ContainerName, err)
+		return false
+	}
+
+	if !isPayloadReference(containerDetails.Config.Labels[k2dtypes.LastAppliedConfigLabelKey]) ||
+		!isPayloadReference(containerDetails.Config.Labels[k2dtypes.PodLastAppliedConfigLabelKey]) {
+		return false
+	}
+
+	desiredResources := containerCfg.HostConfig.Resources
+	updatedResources := container.Resources{
+		CPUShares:         desiredResources.CPUShares,
+		NanoCPUs:          desiredResources.NanoCPUs,
+		Memory:            desiredResources.Memory,
+		MemoryReservation: desiredResources.MemoryReservation,
+		MemorySwap:        desiredResources.MemorySwap,
+	}
+
+	_, err = adapter.cli.ContainerUpdate(ctx, existingContainer.ID, container.UpdateConfig{Resources: updatedResources})
+	if err != nil {
+		adapter.logger.Warnf("unable to update the resources of container %s in place, the container will be recreated: %s", containerCfg.ContainerName, err)
+		return false
+	}
+
+	err = adapter.updateContainerPayloads(existingContainer.ID, containerDetails.Config.Labels, map[string]string{
+		k2dtypes.LastAppliedConfigLabelKey:    options.lastAppliedConfiguration,
+		k2dtypes.PodLastAppliedConfigLabelKey: containerCfg.ContainerConfig.Labels[k2dtypes.PodLastAppliedConfigLabelKey],
+	})
+	if err != nil {
+		adapter.logger.Warnf("unable to store the configuration of container %s, the container will be recreated: %s", containerCfg.ContainerName, err)
+		return false
+	}
+
+	adapter.logger.Infof("resources of container %s updated in place", containerCfg.ContainerName)
+
+	adapter.RecordEvent(core.ObjectReference{Kind: "Pod", Name: options.containerName, Namespace: options.namespace},
+		core.EventTypeNormal, "Resized", "Container resources updated without restarting the container")
+
+	return true
+}
+
+// onlyContainerResourcesChanged returns true when two last applied configurations of a workload only differ
+// by the resources of their containers.
+func onlyContainerResourcesChanged(previousConfiguration, desiredConfiguration string) bool {
+	if previousConfiguration == "" || desiredConfiguration == "" {
+		return false
+	}
+
+	var previousObject, desiredObject interface{}
+
+	err := json.Unmarshal([]byte(previousConfiguration), &previousObject)
+	if err != nil {
+		return false
+	}
+
+	err = json.Unmarshal([]byte(desiredConfiguration), &desiredObject)
+	if err != nil {
+		return false
+	}
+
+	removeContainerResources(previousObject)
+	removeContainerResources(desiredObject)
+
+	return reflect.DeepEqual(previousObject, desiredObject)
+}
+
+// removeContainerResources removes the resources of the containers found at any level of a decoded JSON object
+// (e.g. spec.containers of a pod or spec.template.spec.containers of a deployment).
+func removeContainerResources(node interface{}) {
+	switch typedNode := node.(type) {
+	case map[string]interface{}:
+		if containers, ok := typedNode["containers"].([]interface{}); ok {
+			for _, containerSpec := range containers {
+				if containerMap, ok := containerSpec.(map[string]interface{}); ok {
+					delete(containerMap, "resources")
+				}
+			}
+		}
+
+		for _, value := range typedNode {
+			removeContainerResources(value)
+		}
+	case []interface{}:
+		for _, value := range typedNode {
+			removeContainerResources(value)
+		}
+	}
+}
+
+// canUpdateResourcesInPlace returns true when the resources of a container can be changed to the desired resources
+// using the Docker update API, which ignores the zero values and cannot update the device requests.
+func canUpdateResourcesInPlace(currentResources, desiredResources container.Resources) bool {
+	if len(currentResources.DeviceRequests) != 0 || len(desiredResources.DeviceRequests) != 0 {
+		if !reflect.DeepEqual(currentResources.DeviceRequests, desiredResources.DeviceRequests) {
+			return false
+		}
+	}
+
+	removedLimit := (desiredResources.CPUShares == 0 && currentResources.CPUShares != 0) ||
+		(desiredResources.NanoCPUs == 0 && currentResources.NanoCPUs != 0) ||
+		(desiredResources.Memory == 0 && currentResources.Memory != 0) ||
+		(desiredResources.MemoryReservation == 0 && currentResources.MemoryReservation != 0)
+
+	return !removedLimit
+}
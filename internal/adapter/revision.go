@@ -0,0 +1,334 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/filters"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"github.com/portainer/k2d/internal/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/kubernetes/pkg/apis/apps"
+)
+
+const (
+	// defaultRevisionHistoryLimit is the number of old revisions retained for a deployment when its
+	// spec.revisionHistoryLimit is not set, as in Kubernetes
+	defaultRevisionHistoryLimit = 10
+
+	// revisionAnnotationKey is the annotation holding the revision number of a replica set, as used by kubectl rollout
+	revisionAnnotationKey = "deployment.kubernetes.io/revision"
+)
+
+// recordDeploymentRevision adds the pod template of a deployment to its revision history, stored in a system configmap.
+// Each revision is exposed as a replica set owned by the deployment (see ListReplicaSets), which allows
+// kubectl rollout history and kubectl rollout undo to be used against k2d: the rollback is performed by kubectl
+// by patching the deployment with the pod template of the previous revision, which re-creates the container.
+//
+// As in Kubernetes, a template that matches an existing revision does not create a new revision, the matching
+// revision becomes the latest revision instead. The number of old revisions retained is defined by the
+// spec.revisionHistoryLimit field of the deployment (10 by default).
+//
+// Parameters:
+// - deployment: The deployment that was rolled out.
+// - template: The pod template of the deployment, before it was updated with the labels set by k2d.
+//
+// Returns:
+// - An error if the revision history cannot be read or stored.
+func (adapter *KubeDockerAdapter) recordDeploymentRevision(deployment *appsv1.Deployment, template corev1.PodTemplateSpec) error {
+	revisions, err := adapter.getDeploymentRevisions(deployment.Name, deployment.Namespace)
+	if err != nil {
+		return err
+	}
+
+	templateHash, err := computeTemplateHash(template)
+	if err != nil {
+		return err
+	}
+
+	latestRevision := int64(0)
+	retainedRevisions := []appsv1.ReplicaSet{}
+	for i, revision := range revisions {
+		latestRevision = getRevisionNumber(revision)
+
+		if revision.Labels[appsv1.DefaultDeploymentUniqueLabelKey] == templateHash {
+			// The template is already the latest revision
+			if i == len(revisions)-1 {
+				return nil
+			}
+			continue
+		}
+
+		retainedRevisions = append(retainedRevisions, revision)
+	}
+
+	annotations := map[string]string{}
+	for key, value := range deployment.Annotations {
+		if key == "kubectl.kubernetes.io/last-applied-configuration" {
+			continue
+		}
+		annotations[key] = value
+	}
+	annotations[revisionAnnotationKey] = strconv.FormatInt(latestRevision+1, 10)
+
+	templateLabels := map[string]string{}
+	for key, value := range template.Labels {
+		templateLabels[key] = value
+	}
+	templateLabels[appsv1.DefaultDeploymentUniqueLabelKey] = templateHash
+	template.Labels = templateLabels
+
+	replicaSet := appsv1.ReplicaSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ReplicaSet",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("%s-%s", deployment.Name, templateHash),
+			Namespace:         deployment.Namespace,
+			Labels:            templateLabels,
+			Annotations:       annotations,
+			CreationTimestamp: metav1.Now(),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(deployment, appsv1.SchemeGroupVersion.WithKind("Deployment")),
+			},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Selector: deployment.Spec.Selector,
+			Template: template,
+		},
+	}
+
+	historyLimit := defaultRevisionHistoryLimit
+	if deployment.Spec.RevisionHistoryLimit != nil {
+		historyLimit = int(*deployment.Spec.RevisionHistoryLimit)
+	}
+
+	if len(retainedRevisions) > historyLimit {
+		retainedRevisions = retainedRevisions[len(retainedRevisions)-historyLimit:]
+	}
+	retainedRevisions = append(retainedRevisions, replicaSet)
+
+	data := map[string]string{}
+	for _, revision := range retainedRevisions {
+		revisionData, err := json.Marshal(revision)
+		if err != nil {
+			return fmt.Errorf("unable to marshal revision: %w", err)
+		}
+		data[revision.Annotations[revisionAnnotationKey]] = string(revisionData)
+	}
+
+	err = adapter.CreateSystemConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: naming.BuildDeploymentRevisionsSystemConfigMapName(deployment.Name, deployment.Namespace),
+			Labels: map[string]string{
+				k2dtypes.NamespaceNameLabelKey: deployment.Namespace,
+				k2dtypes.WorkloadNameLabelKey:  deployment.Name,
+			},
+		},
+		Data: data,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to store deployment revisions system configmap: %w", err)
+	}
+
+	return nil
+}
+
+// getDeploymentRevisions returns the revisions of a deployment sorted by revision number.
+func (adapter *KubeDockerAdapter) getDeploymentRevisions(deploymentName, namespace string) ([]appsv1.ReplicaSet, error) {
+	configMap, err := adapter.GetSystemConfigMap(naming.BuildDeploymentRevisionsSystemConfigMapName(deploymentName, namespace))
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			return []appsv1.ReplicaSet{}, nil
+		}
+		return nil, fmt.Errorf("unable to get deployment revisions system configmap: %w", err)
+	}
+
+	return parseDeploymentRevisions(configMap)
+}
+
+// parseDeploymentRevisions decodes the revisions stored in a deployment revisions system configmap,
+// sorted by revision number.
+func parseDeploymentRevisions(configMap *corev1.ConfigMap) ([]appsv1.ReplicaSet, error) {
+	revisions := []appsv1.ReplicaSet{}
+	for _, revisionData := range configMap.Data {
+		revision := appsv1.ReplicaSet{}
+		err := json.Unmarshal([]byte(revisionData), &revision)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return getRevisionNumber(revisions[i]) < getRevisionNumber(revisions[j])
+	})
+
+	return revisions, nil
+}
+
+// deleteDeploymentRevisions removes the revision history of a deployment.
+func (adapter *KubeDockerAdapter) deleteDeploymentRevisions(deploymentName, namespace string) error {
+	err := adapter.DeleteSystemConfigMap(naming.BuildDeploymentRevisionsSystemConfigMapName(deploymentName, namespace))
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		return fmt.Errorf("unable to delete deployment revisions system configmap: %w", err)
+	}
+
+	return nil
+}
+
+// getRevisionNumber returns the revision number of a replica set, 0 when it cannot be parsed.
+func getRevisionNumber(replicaSet appsv1.ReplicaSet) int64 {
+	revision, err := strconv.ParseInt(replicaSet.Annotations[revisionAnnotationKey], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return revision
+}
+
+// computeTemplateHash returns a hash of a pod template, used as the pod-template-hash label and in the name of
+// the replica set of a revision. The pod-template-hash label is ignored so that the hash of a template restored
+// from a revision matches the hash of the revision.
+func computeTemplateHash(template corev1.PodTemplateSpec) (string, error) {
+	template = *template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+	templateData, err := json.Marshal(template)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal pod template: %w", err)
+	}
+
+	hasher := fnv.New32a()
+	hasher.Write(templateData)
+
+	return rand.SafeEncodeString(strconv.FormatUint(uint64(hasher.Sum32()), 10)), nil
+}
+
+// ListReplicaSets returns the revisions of the deployments as replica sets, the replica set of the latest revision
+// of a deployment reflecting the state of the deployment container.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - namespace: The namespace of the deployments, all namespaces when empty.
+// - selector: The label selector used to filter the replica sets.
+//
+// Returns:
+// - The list of replica sets.
+// - An error if the revisions or the deployment containers cannot be listed.
+func (adapter *KubeDockerAdapter) ListReplicaSets(ctx context.Context, namespace string, selector labels.Selector) (appsv1.ReplicaSetList, error) {
+	replicaSetList, err := adapter.listReplicaSets(ctx, namespace, selector)
+	if err != nil {
+		return appsv1.ReplicaSetList{}, fmt.Errorf("unable to list replica sets: %w", err)
+	}
+
+	versionedReplicaSetList := appsv1.ReplicaSetList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ReplicaSetList",
+			APIVersion: "apps/v1",
+		},
+	}
+
+	err = adapter.ConvertK8SResource(&replicaSetList, &versionedReplicaSetList)
+	if err != nil {
+		return appsv1.ReplicaSetList{}, fmt.Errorf("unable to convert internal ReplicaSetList to versioned ReplicaSetList: %w", err)
+	}
+
+	return versionedReplicaSetList, nil
+}
+
+func (adapter *KubeDockerAdapter) GetReplicaSetTable(ctx context.Context, namespace string, selector labels.Selector) (*metav1.Table, error) {
+	replicaSetList, err := adapter.listReplicaSets(ctx, namespace, selector)
+	if err != nil {
+		return &metav1.Table{}, fmt.Errorf("unable to list replica sets: %w", err)
+	}
+
+	return k8s.GenerateTable(&replicaSetList)
+}
+
+func (adapter *KubeDockerAdapter) listReplicaSets(ctx context.Context, namespace string, selector labels.Selector) (apps.ReplicaSetList, error) {
+	configMaps, err := adapter.ListSystemConfigMaps()
+	if err != nil {
+		return apps.ReplicaSetList{}, fmt.Errorf("unable to list system configmaps: %w", err)
+	}
+
+	containers, err := adapter.cli.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filters.AllDeployments(namespace)})
+	if err != nil {
+		return apps.ReplicaSetList{}, fmt.Errorf("unable to list containers: %w", err)
+	}
+
+	deploymentContainers := map[string]types.Container{}
+	for _, container := range containers {
+		deploymentContainers[naming.BuildContainerName(container.Labels[k2dtypes.WorkloadNameLabelKey], container.Labels[k2dtypes.NamespaceNameLabelKey])] = container
+	}
+
+	replicaSets := []apps.ReplicaSet{}
+	for _, configMap := range configMaps.Items {
+		if !strings.HasPrefix(configMap.Name, naming.DeploymentRevisionsSystemConfigMapPrefix) {
+			continue
+		}
+
+		if namespace != "" && configMap.Labels[k2dtypes.NamespaceNameLabelKey] != namespace {
+			continue
+		}
+
+		deploymentName := configMap.Labels[k2dtypes.WorkloadNameLabelKey]
+		revisions, err := parseDeploymentRevisions(&configMap)
+		if err != nil {
+			return apps.ReplicaSetList{}, fmt.Errorf("unable to parse revisions of deployment %s: %w", deploymentName, err)
+		}
+
+		for i, revision := range revisions {
+			if !selector.Matches(labels.Set(revision.Labels)) {
+				continue
+			}
+
+			// Only the latest revision is backed by the deployment container
+			if i == len(revisions)-1 {
+				container, exists := deploymentContainers[naming.BuildContainerName(deploymentName, revision.Namespace)]
+				if exists {
+					replicas := int32(1)
+					revision.Spec.Replicas = &replicas
+					revision.Status.Replicas = 1
+					if container.State == "running" {
+						revision.Status.ReadyReplicas = 1
+						revision.Status.AvailableReplicas = 1
+					}
+				}
+			} else {
+				replicas := int32(0)
+				revision.Spec.Replicas = &replicas
+			}
+
+			replicaSet := apps.ReplicaSet{}
+			err := adapter.ConvertK8SResource(&revision, &replicaSet)
+			if err != nil {
+				return apps.ReplicaSetList{}, fmt.Errorf("unable to convert versioned replica set to internal replica set: %w", err)
+			}
+
+			replicaSets = append(replicaSets, replicaSet)
+		}
+	}
+
+	return apps.ReplicaSetList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ReplicaSetList",
+			APIVersion: "apps/v1",
+		},
+		Items: replicaSets,
+	}, nil
+}
@@ -0,0 +1,222 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/portainer/k2d/internal/adapter/converter"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// rollingUpdateReadyTimeout is the maximum amount of time to wait for the new container of a rolling update to be ready
+	rollingUpdateReadyTimeout = 2 * time.Minute
+	// rollingUpdatePollInterval is the interval at which the state of the new container of a rolling update is inspected
+	rollingUpdatePollInterval = time.Second
+)
+
+// canRollContainer returns true when the existing container can be replaced using a rolling update, that is when
+// the new container can run alongside it: the existing container must be running, and the new container must not
+// publish any host port nor use the host network as they would conflict with the existing container.
+func canRollContainer(existingContainer *types.ContainerJSON, containerCfg converter.ContainerConfiguration) bool {
+	if existingContainer.State == nil || !existingContainer.State.Running {
+		return false
+	}
+
+	if containerCfg.HostConfig.NetworkMode.IsHost() {
+		return false
+	}
+
+	for _, bindings := range containerCfg.HostConfig.PortBindings {
+		if len(bindings) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rollContainer replaces an existing container with a new container using a rolling update, reducing the downtime
+// of the workload during an update (e.g. kubectl apply or helm upgrade).
+// The function performs the following steps:
+// 1. Pulls the image of the new container while the existing container keeps running.
+// 2. Creates and starts the new container under a temporary name and runs its postStart hook.
+// 3. Waits for the new container to be running, and healthy when the image defines a health check.
+// 4. Attaches the aliases of the service associated with the existing container to the new container.
+// 5. Gracefully stops (running the preStop hook) and removes the existing container.
+// 6. Renames the new container to the original name.
+//
+// If the new container cannot be started or does not become ready, it is removed and the existing container is left untouched.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - existingContainer: The container to replace.
+// - containerCfg: The configuration of the new container.
+// - podSpec: The internal pod spec of the new container, used to run its postStart hook.
+// - options: The container creation options.
+//
+// Returns:
+// - An error if any of the steps fail.
+func (adapter *KubeDockerAdapter) rollContainer(ctx context.Context, existingContainer *types.ContainerJSON, containerCfg converter.ContainerConfiguration, podSpec core.PodSpec, options ContainerCreationOptions) error {
+	serviceName := existingContainer.Config.Labels[k2dtypes.ServiceNameLabelKey]
+	if serviceName != "" {
+		containerCfg.ContainerConfig.Labels[k2dtypes.ServiceNameLabelKey] = serviceName
+	} else if serviceName = existingContainer.Config.Labels[k2dtypes.ServiceBackendLabelKey]; serviceName != "" {
+		containerCfg.ContainerConfig.Labels[k2dtypes.ServiceBackendLabelKey] = serviceName
+	}
+
+	registryAuth, err := adapter.getRegistryCredentials(options.podSpec, options.namespace, containerCfg.ContainerConfig.Image)
+	if err != nil {
+		return fmt.Errorf("unable to get registry credentials: %w", err)
+	}
+
+	err = adapter.pullImage(ctx, containerCfg.ContainerConfig.Image, registryAuth, &core.ObjectReference{
+		Kind:      "Pod",
+		Namespace: options.namespace,
+		Name:      options.containerName,
+	})
+	if err != nil {
+		return err
+	}
+
+	tempContainerName := naming.BuildTemporaryContainerName(containerCfg.ContainerName)
+
+	containerCreateResponse, err := adapter.createContainer(ctx, containerCfg, tempContainerName)
+	if err != nil {
+		return fmt.Errorf("unable to create container: %w", err)
+	}
+
+	err = adapter.cli.ContainerStart(ctx, containerCreateResponse.ID, types.ContainerStartOptions{})
+	if err == nil {
+		err = adapter.runPostStartHook(ctx, containerCreateResponse.ID, podSpec)
+	}
+	if err == nil {
+		err = adapter.waitForContainerReady(ctx, containerCreateResponse.ID)
+	}
+	if err != nil {
+		adapter.removeRolledContainer(ctx, containerCreateResponse.ID, tempContainerName)
+		return fmt.Errorf("rolling update of container %s failed, the existing container is kept: %w", containerCfg.ContainerName, err)
+	}
+
+	if serviceName != "" {
+		err = adapter.attachServiceAliases(ctx, containerCreateResponse.ID, serviceName, options.namespace)
+		if err != nil {
+			adapter.removeRolledContainer(ctx, containerCreateResponse.ID, tempContainerName)
+			return fmt.Errorf("unable to attach service aliases to container %s: %w", tempContainerName, err)
+		}
+	}
+
+	err = adapter.stopContainer(ctx, existingContainer.ID, existingContainer.Config.Labels)
+	if err != nil {
+		adapter.logger.Warnf("unable to gracefully stop container %s: %s", containerCfg.ContainerName, err)
+	}
+
+	err = adapter.retainContainerLogs(ctx, existingContainer.ID, containerCfg.ContainerName)
+	if err != nil {
+		adapter.logger.Warnf("unable to retain logs of container %s: %s", containerCfg.ContainerName, err)
+	}
+
+	err = adapter.cli.ContainerRemove(ctx, existingContainer.ID, types.ContainerRemoveOptions{Force: true})
+	if err != nil {
+		return fmt.Errorf("unable to remove old container: %w", err)
+	}
+	adapter.deleteContainerPayloads(existingContainer.ID)
+
+	err = adapter.cli.ContainerRename(ctx, containerCreateResponse.ID, containerCfg.ContainerName)
+	if err != nil {
+		return fmt.Errorf("unable to rename container: %w", err)
+	}
+
+	return nil
+}
+
+// waitForContainerReady waits for a container to be running, and healthy when its image defines a health check.
+// It returns an error if the container exits, becomes unhealthy or is not ready within rollingUpdateReadyTimeout.
+func (adapter *KubeDockerAdapter) waitForContainerReady(ctx context.Context, containerID string) error {
+	ctx, cancel := context.WithTimeout(ctx, rollingUpdateReadyTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(rollingUpdatePollInterval)
+	defer ticker.Stop()
+
+	for {
+		containerDetails, err := adapter.cli.ContainerInspect(ctx, containerID)
+		if err != nil {
+			return fmt.Errorf("unable to inspect container: %w", err)
+		}
+
+		if containerDetails.State == nil {
+			return fmt.Errorf("unable to retrieve the state of the container")
+		}
+
+		if !containerDetails.State.Running {
+			return fmt.Errorf("container is not running (status: %s)", containerDetails.State.Status)
+		}
+
+		if containerDetails.State.Health == nil || containerDetails.State.Health.Status == types.Healthy {
+			return nil
+		}
+
+		if containerDetails.State.Health.Status == types.Unhealthy {
+			return fmt.Errorf("container is unhealthy")
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("container is not ready after %s: %w", rollingUpdateReadyTimeout, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// attachServiceAliases reconnects a container to the network of its namespace with the aliases of a service,
+// so that the service resolves to the container.
+func (adapter *KubeDockerAdapter) attachServiceAliases(ctx context.Context, containerID, serviceName, namespace string) error {
+	networkName := naming.BuildNetworkName(namespace)
+
+	err := adapter.cli.NetworkDisconnect(ctx, networkName, containerID, false)
+	if err != nil {
+		return fmt.Errorf("unable to disconnect container from network %s: %w", networkName, err)
+	}
+
+	err = adapter.cli.NetworkConnect(ctx, networkName, containerID, &network.EndpointSettings{
+		Aliases: buildServiceAliases(serviceName, namespace),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to connect container to network %s: %w", networkName, err)
+	}
+
+	return nil
+}
+
+// hasNetworkAlias returns true when the container is connected to the specified network with the specified alias.
+func hasNetworkAlias(containerDetails *types.ContainerJSON, networkName, alias string) bool {
+	if containerDetails.NetworkSettings == nil || containerDetails.NetworkSettings.Networks[networkName] == nil {
+		return false
+	}
+
+	for _, networkAlias := range containerDetails.NetworkSettings.Networks[networkName].Aliases {
+		if networkAlias == alias {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeRolledContainer removes the new container of a failed rolling update.
+// Failures are only logged as the rolling update error is returned to the caller.
+func (adapter *KubeDockerAdapter) removeRolledContainer(ctx context.Context, containerID, containerName string) {
+	err := adapter.cli.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{Force: true})
+	if err != nil {
+		adapter.logger.Warnf("unable to remove container %s: %s", containerName, err)
+		return
+	}
+
+	adapter.deleteContainerPayloads(containerID)
+}
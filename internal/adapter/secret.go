@@ -12,7 +12,21 @@ import (
 	"k8s.io/kubernetes/pkg/apis/core"
 )
 
+// HelmReleaseSecretType is the type of the secrets used by Helm to store releases.
+// Helm expects these secrets to be available as soon as they are created and relies on label selectors
+// (owner=helm, name=<release>, status=<status>...) to list them.
+const HelmReleaseSecretType corev1.SecretType = "helm.sh/release.v1"
+
+// CreateSecret stores a secret. Its owner references are persisted in its labels so that the secret
+// can be garbage collected when its owner is deleted (see DeleteDependents).
 func (adapter *KubeDockerAdapter) CreateSecret(secret *corev1.Secret) error {
+	secret = secret.DeepCopy()
+
+	err := persistOwnerReferences(&secret.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
 	if secret.Type == corev1.SecretTypeDockerConfigJson {
 		return adapter.registrySecretStore.StoreSecret(secret)
 	}
@@ -85,6 +99,7 @@ func (adapter *KubeDockerAdapter) getSecret(secretName, namespace string) (*core
 		return nil, fmt.Errorf("unable to get secret: %w", err)
 	}
 	if secret != nil {
+		restoreOwnerReferences(&secret.ObjectMeta)
 		return secret, nil
 	}
 
@@ -93,6 +108,7 @@ func (adapter *KubeDockerAdapter) getSecret(secretName, namespace string) (*core
 		return nil, fmt.Errorf("unable to get registry secret: %w", err)
 	}
 	if registrySecret != nil {
+		restoreOwnerReferences(&registrySecret.ObjectMeta)
 		return registrySecret, nil
 	}
 
@@ -112,5 +128,9 @@ func (adapter *KubeDockerAdapter) listSecrets(namespace string, selector labels.
 
 	secretList.Items = append(secretList.Items, registrySecretList.Items...)
 
+	for i := range secretList.Items {
+		restoreOwnerReferences(&secretList.Items[i].ObjectMeta)
+	}
+
 	return secretList, nil
 }
@@ -51,3 +51,10 @@ func (adapter *KubeDockerAdapter) storeServiceAccountSecret(tokenPath, caPath st
 
 	return adapter.secretStore.StoreSecret(&secret)
 }
+
+// RefreshServiceAccountSecret updates the system service account secret with the content of the
+// service account token file and CA certificate file. It is used after the token has been rotated so that
+// the containers mounting the service account secret are provided with the new token.
+func (adapter *KubeDockerAdapter) RefreshServiceAccountSecret(tokenPath, caPath string) error {
+	return adapter.storeServiceAccountSecret(tokenPath, caPath)
+}
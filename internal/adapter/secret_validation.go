@@ -0,0 +1,92 @@
+package adapter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidateSecretDataEncoding ensures that the values of the data field of a JSON encoded secret are valid base64 strings.
+// It is used before decoding a secret received through the API so that an invalid value is reported with its key,
+// rather than with the error of the JSON decoder. A body that is not a valid JSON object is left to the decoder.
+func ValidateSecretDataEncoding(body []byte) error {
+	secret := struct {
+		Data map[string]*string `json:"data"`
+	}{}
+
+	err := json.Unmarshal(body, &secret)
+	if err != nil {
+		return nil
+	}
+
+	for _, key := range sortedKeys(secret.Data) {
+		value := secret.Data[key]
+		if value == nil {
+			continue
+		}
+
+		_, err := base64.StdEncoding.DecodeString(*value)
+		if err != nil {
+			return fmt.Errorf("%w: data[%s]: invalid base64 value: %s", adaptererr.ErrInvalidResource, key, err)
+		}
+	}
+
+	return nil
+}
+
+// PrepareSecret validates and normalizes a secret received through the API, as done by the Kubernetes API server:
+//   - The keys of the data and stringData fields must be valid keys (alphanumeric characters, '-', '_' or '.').
+//   - The values of the stringData field are merged into the data field, overriding the values of the same keys,
+//     and the stringData field is cleared as it is a write-only field.
+//
+// Parameters:
+// - secret: The secret to prepare, updated in place.
+//
+// Returns:
+// - An ErrInvalidResource error describing the first invalid key.
+func PrepareSecret(secret *corev1.Secret) error {
+	for _, key := range sortedKeys(secret.Data) {
+		if errs := validation.IsConfigMapKey(key); len(errs) != 0 {
+			return fmt.Errorf("%w: data[%s]: invalid key: %s", adaptererr.ErrInvalidResource, key, strings.Join(errs, ", "))
+		}
+	}
+
+	for _, key := range sortedKeys(secret.StringData) {
+		if errs := validation.IsConfigMapKey(key); len(errs) != 0 {
+			return fmt.Errorf("%w: stringData[%s]: invalid key: %s", adaptererr.ErrInvalidResource, key, strings.Join(errs, ", "))
+		}
+	}
+
+	if len(secret.StringData) == 0 {
+		secret.StringData = nil
+		return nil
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte, len(secret.StringData))
+	}
+
+	for key, value := range secret.StringData {
+		secret.Data[key] = []byte(value)
+	}
+	secret.StringData = nil
+
+	return nil
+}
+
+// sortedKeys returns the keys of a map in alphabetical order, so that the validation errors are deterministic.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,64 @@
+package adapter
+
+import (
+	"errors"
+	"testing"
+
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidateSecretDataEncoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		invalid bool
+	}{
+		{name: "valid base64 values", body: `{"data":{"password":"c2VjcmV0","empty":""}}`},
+		{name: "null value", body: `{"data":{"password":null}}`},
+		{name: "invalid base64 value", body: `{"data":{"password":"secret!"}}`, invalid: true},
+		{name: "invalid JSON is left to the decoder", body: `{"data":`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateSecretDataEncoding([]byte(test.body))
+
+			if test.invalid != errors.Is(err, adaptererr.ErrInvalidResource) {
+				t.Errorf("unexpected validation result: %v", err)
+			}
+		})
+	}
+}
+
+func TestPrepareSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("old")},
+		StringData: map[string]string{"password": "new", "token": "abc"},
+	}
+
+	err := PrepareSecret(secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]string{"username": "admin", "password": "new", "token": "abc"}
+	for key, value := range expected {
+		if string(secret.Data[key]) != value {
+			t.Errorf("expected data[%s] to be %q, got %q", key, value, secret.Data[key])
+		}
+	}
+
+	if secret.StringData != nil {
+		t.Error("expected stringData to be cleared")
+	}
+
+	for _, invalidSecret := range []*corev1.Secret{
+		{Data: map[string][]byte{"invalid/key": []byte("value")}},
+		{StringData: map[string]string{"invalid key": "value"}},
+	} {
+		if err := PrepareSecret(invalidSecret); !errors.Is(err, adaptererr.ErrInvalidResource) {
+			t.Errorf("expected an invalid resource error, got %v", err)
+		}
+	}
+}
@@ -18,33 +18,52 @@ import (
 	"k8s.io/kubernetes/pkg/apis/core"
 )
 
+// DeleteService removes the configuration of a service from the containers it is associated with.
+// The primary backend of the service (see ServiceNameLabelKey) and its additional backends (see ServiceBackendLabelKey)
+// are re-created without the service labels, the network aliases of the service and the published ports.
 func (adapter *KubeDockerAdapter) DeleteService(ctx context.Context, serviceName, namespace string) error {
-	container, err := adapter.getContainerFromServiceName(ctx, serviceName, namespace)
-	if err != nil {
-		adapter.logger.Warnf("unable to get container from service name %s: %s", serviceName, err)
-		return nil
-	}
+	adapter.serviceLock.Lock()
+	defer adapter.serviceLock.Unlock()
 
-	adapter.logger.Infow("found the container with the associated service. The container will be re-created and the associated service configuration will be removed.",
-		"container_id", container.ID,
-		"service_name", serviceName,
-	)
+	adapter.deleteObjectMetadata("Service", serviceName, namespace)
 
-	cfg, err := adapter.buildContainerConfigurationFromExistingContainer(ctx, container.ID)
+	containers, err := adapter.cli.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filters.ByNamespace(namespace)})
 	if err != nil {
-		return fmt.Errorf("unable to build container configuration from existing container: %w", err)
+		adapter.logger.Warnf("unable to list the containers associated with service %s: %s", serviceName, err)
+		return nil
 	}
 
-	delete(cfg.ContainerConfig.Labels, k2dtypes.ServiceNameLabelKey)
-	delete(cfg.ContainerConfig.Labels, k2dtypes.ServiceLastAppliedConfigLabelKey)
+	for _, container := range containers {
+		if !isServiceBackend(container, serviceName) {
+			continue
+		}
 
-	networkName := naming.BuildNetworkName(namespace)
-	cfg.NetworkConfig.EndpointsConfig[networkName].Aliases = []string{}
+		adapter.logger.Infow("found a container associated with the service. The container will be re-created and the associated service configuration will be removed.",
+			"container_id", container.ID,
+			"service_name", serviceName,
+		)
 
-	return adapter.reCreateContainerWithNewConfiguration(ctx, container.ID, cfg)
+		err := adapter.detachServiceFromContainer(ctx, container.ID, namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
+// CreateContainerFromService exposes the containers matching the selector of a service.
+// The aliases of the service are attached to all the matching containers so that the Docker DNS resolves the name
+// of the service to each of them (round-robin). The ports of the service can only be published by a single container:
+// the first matching container (see findContainersMatchingSelector) is the primary backend of the service and
+// stores the service definition, the other containers are labelled as additional backends of the service.
+// The containers that were associated with the service but no longer match its selector are detached from it.
+// The services are applied one at a time: two services selecting the same container would otherwise re-create it
+// concurrently, and could allocate the same NodePort.
 func (adapter *KubeDockerAdapter) CreateContainerFromService(ctx context.Context, service *corev1.Service) error {
+	adapter.serviceLock.Lock()
+	defer adapter.serviceLock.Unlock()
+
 	logger := logging.LoggerFromContext(ctx)
 
 	// headless services are not supported
@@ -68,12 +87,19 @@ func (adapter *KubeDockerAdapter) CreateContainerFromService(ctx context.Context
 		return fmt.Errorf("unable to list containers: %w", err)
 	}
 
-	matchingContainer := findContainerMatchingSelector(containers, service.Spec.Selector)
+	matchingContainers := findContainersMatchingSelector(containers, service.Namespace, service.Name, service.Spec.Selector)
 
-	if matchingContainer == nil {
+	if len(matchingContainers) == 0 {
 		return errors.New("no container was found matching the service selector")
 	}
 
+	// The metadata is stored before the container is re-created as it is not part of the container configuration:
+	// the container is not re-created when only the metadata of the service changes
+	err = adapter.storeObjectMetadata("Service", service.ObjectMeta)
+	if err != nil {
+		logger.Warnf("unable to store the metadata of service %s: %s", service.Name, err)
+	}
+
 	if service.Labels["app.kubernetes.io/managed-by"] == "Helm" {
 		serviceData, err := json.Marshal(service)
 		if err != nil {
@@ -82,7 +108,34 @@ func (adapter *KubeDockerAdapter) CreateContainerFromService(ctx context.Context
 		service.ObjectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = string(serviceData)
 	}
 
-	if service.ObjectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"] == matchingContainer.Labels[k2dtypes.ServiceLastAppliedConfigLabelKey] {
+	err = adapter.detachServiceFromUnselectedContainers(ctx, containers, matchingContainers, service)
+	if err != nil {
+		return err
+	}
+
+	err = adapter.exposePrimaryServiceBackend(ctx, containers, matchingContainers[0], service)
+	if err != nil {
+		return err
+	}
+
+	for _, container := range matchingContainers[1:] {
+		err := adapter.exposeAdditionalServiceBackend(ctx, container, service)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exposePrimaryServiceBackend re-creates the primary backend of a service with the service definition, the network aliases
+// of the service and the ports of the service. The container is not re-created when it already exposes the same service definition.
+func (adapter *KubeDockerAdapter) exposePrimaryServiceBackend(ctx context.Context, containers []types.Container, matchingContainer types.Container, service *corev1.Service) error {
+	logger := logging.LoggerFromContext(ctx)
+
+	matchingContainerLabels := adapter.resolveContainerLabels(matchingContainer.ID, matchingContainer.Labels)
+	if service.ObjectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"] == matchingContainerLabels[k2dtypes.ServiceLastAppliedConfigLabelKey] &&
+		matchingContainerLabels[k2dtypes.ServiceNameLabelKey] == service.Name {
 		logger.Infow("the container matching the service selector already exists with the same service configuration. The update will be skipped",
 			"container_id", matchingContainer.ID,
 			"service_name", service.Name,
@@ -99,6 +152,7 @@ func (adapter *KubeDockerAdapter) CreateContainerFromService(ctx context.Context
 		return fmt.Errorf("unable to build container configuration from existing container: %w", err)
 	}
 
+	delete(cfg.ContainerConfig.Labels, k2dtypes.ServiceBackendLabelKey)
 	cfg.ContainerConfig.Labels[k2dtypes.ServiceNameLabelKey] = service.Name
 	cfg.ContainerConfig.Labels[k2dtypes.ServiceLastAppliedConfigLabelKey] = service.ObjectMeta.Annotations["kubectl.kubernetes.io/last-applied-configuration"]
 
@@ -108,6 +162,11 @@ func (adapter *KubeDockerAdapter) CreateContainerFromService(ctx context.Context
 		return fmt.Errorf("unable to convert versioned service spec to internal service spec: %w", err)
 	}
 
+	err = adapter.checkServiceHostPorts(ctx, internalServiceSpec, &matchingContainer)
+	if err != nil {
+		return err
+	}
+
 	usedPorts := make(map[int]struct{})
 	for _, container := range containers {
 		for _, port := range container.Ports {
@@ -121,16 +180,125 @@ func (adapter *KubeDockerAdapter) CreateContainerFromService(ctx context.Context
 	}
 
 	networkName := naming.BuildNetworkName(service.Namespace)
-	cfg.NetworkConfig.EndpointsConfig[networkName].Aliases = []string{
-		service.Name,
-		fmt.Sprintf("%s.%s", service.Name, service.Namespace),
-		fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace),
-		fmt.Sprintf("%s.%s.svc.cluster.local", service.Name, service.Namespace),
+	endpointSettings, ok := cfg.NetworkConfig.EndpointsConfig[networkName]
+	if !ok {
+		return fmt.Errorf("unable to expose container %s: the container is not attached to the network %s", matchingContainer.ID, networkName)
+	}
+	endpointSettings.Aliases = buildServiceAliases(service.Name, service.Namespace)
+
+	return adapter.reCreateContainerWithNewConfiguration(ctx, matchingContainer.ID, cfg)
+}
+
+// exposeAdditionalServiceBackend re-creates a container matching the selector of a service, other than its primary backend,
+// with the network aliases of the service. The ports of the service are not published by the container.
+// A container can only be associated with a single service: a container already exposed by another service is left untouched.
+func (adapter *KubeDockerAdapter) exposeAdditionalServiceBackend(ctx context.Context, matchingContainer types.Container, service *corev1.Service) error {
+	logger := logging.LoggerFromContext(ctx)
+
+	if matchingContainer.Labels[k2dtypes.ServiceBackendLabelKey] == service.Name {
+		return nil
+	}
+
+	if isServiceBackend(matchingContainer, "") {
+		logger.Warnw("the container matching the service selector is already associated with another service. The service aliases will not be attached to the container",
+			"container_id", matchingContainer.ID,
+			"service_name", service.Name,
+		)
+		return nil
+	}
+
+	logger.Infow("container found matching the service selector. The container will be re-created as an additional backend of the service",
+		"container_id", matchingContainer.ID,
+		"service_name", service.Name,
+	)
+
+	cfg, err := adapter.buildContainerConfigurationFromExistingContainer(ctx, matchingContainer.ID)
+	if err != nil {
+		return fmt.Errorf("unable to build container configuration from existing container: %w", err)
+	}
+
+	cfg.ContainerConfig.Labels[k2dtypes.ServiceBackendLabelKey] = service.Name
+
+	networkName := naming.BuildNetworkName(service.Namespace)
+	endpointSettings, ok := cfg.NetworkConfig.EndpointsConfig[networkName]
+	if !ok {
+		return fmt.Errorf("unable to expose container %s: the container is not attached to the network %s", matchingContainer.ID, networkName)
 	}
+	endpointSettings.Aliases = buildServiceAliases(service.Name, service.Namespace)
 
 	return adapter.reCreateContainerWithNewConfiguration(ctx, matchingContainer.ID, cfg)
 }
 
+// detachServiceFromUnselectedContainers detaches a service from the containers associated with it that no longer match its selector.
+func (adapter *KubeDockerAdapter) detachServiceFromUnselectedContainers(ctx context.Context, containers, matchingContainers []types.Container, service *corev1.Service) error {
+	selected := make(map[string]struct{}, len(matchingContainers))
+	for _, container := range matchingContainers {
+		selected[container.ID] = struct{}{}
+	}
+
+	for _, container := range containers {
+		if container.Labels[k2dtypes.NamespaceNameLabelKey] != service.Namespace || !isServiceBackend(container, service.Name) {
+			continue
+		}
+
+		if _, ok := selected[container.ID]; ok {
+			continue
+		}
+
+		logging.LoggerFromContext(ctx).Infow("the container associated with the service no longer matches the service selector. The container will be re-created without the service configuration",
+			"container_id", container.ID,
+			"service_name", service.Name,
+		)
+
+		err := adapter.detachServiceFromContainer(ctx, container.ID, service.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// detachServiceFromContainer re-creates a container without the service labels, the network aliases of the service
+// and the published ports.
+func (adapter *KubeDockerAdapter) detachServiceFromContainer(ctx context.Context, containerID, namespace string) error {
+	cfg, err := adapter.buildContainerConfigurationFromExistingContainer(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("unable to build container configuration from existing container: %w", err)
+	}
+
+	delete(cfg.ContainerConfig.Labels, k2dtypes.ServiceNameLabelKey)
+	delete(cfg.ContainerConfig.Labels, k2dtypes.ServiceLastAppliedConfigLabelKey)
+	delete(cfg.ContainerConfig.Labels, k2dtypes.ServiceBackendLabelKey)
+
+	networkName := naming.BuildNetworkName(namespace)
+	if endpointSettings, ok := cfg.NetworkConfig.EndpointsConfig[networkName]; ok {
+		endpointSettings.Aliases = []string{}
+	}
+
+	return adapter.reCreateContainerWithNewConfiguration(ctx, containerID, cfg)
+}
+
+// isServiceBackend returns true when a container is the primary or an additional backend of the specified service,
+// or of any service when the service name is empty.
+func isServiceBackend(container types.Container, serviceName string) bool {
+	if serviceName == "" {
+		return container.Labels[k2dtypes.ServiceNameLabelKey] != "" || container.Labels[k2dtypes.ServiceBackendLabelKey] != ""
+	}
+
+	return container.Labels[k2dtypes.ServiceNameLabelKey] == serviceName || container.Labels[k2dtypes.ServiceBackendLabelKey] == serviceName
+}
+
+// buildServiceAliases returns the network aliases used to reach a service from the network of its namespace.
+func buildServiceAliases(serviceName, namespace string) []string {
+	return []string{
+		serviceName,
+		fmt.Sprintf("%s.%s", serviceName, namespace),
+		fmt.Sprintf("%s.%s.svc", serviceName, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, namespace),
+	}
+}
+
 func (adapter *KubeDockerAdapter) GetService(ctx context.Context, serviceName, namespace string) (*corev1.Service, error) {
 	container, err := adapter.getContainerFromServiceName(ctx, serviceName, namespace)
 	if err != nil {
@@ -206,6 +374,8 @@ func (adapter *KubeDockerAdapter) getContainerFromServiceName(ctx context.Contex
 }
 
 func (adapter *KubeDockerAdapter) buildServiceFromContainer(container types.Container) (*core.Service, error) {
+	container.Labels = adapter.resolveContainerLabels(container.ID, container.Labels)
+
 	if container.Labels[k2dtypes.ServiceLastAppliedConfigLabelKey] == "" {
 		return nil, fmt.Errorf("unable to build service, missing %s label on container %s", k2dtypes.ServiceLastAppliedConfigLabelKey, container.Names[0])
 	}
@@ -226,6 +396,7 @@ func (adapter *KubeDockerAdapter) buildServiceFromContainer(container types.Cont
 	}
 
 	adapter.converter.UpdateServiceFromContainerInfo(&service, container)
+	adapter.restoreObjectMetadata("Service", &service.ObjectMeta)
 
 	return &service, nil
 }
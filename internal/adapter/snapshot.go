@@ -0,0 +1,334 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/filters"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// SnapshotsFolder is the name of the directory, relative to the k2d data path, where the volume snapshots are stored
+	SnapshotsFolder = "snapshots"
+
+	// snapshotVolumeMountPath is the path where a volume is mounted inside the helper container used to take and restore snapshots.
+	// The content of the volume is archived under the base name of this path.
+	snapshotVolumeMountPath = "/volume"
+)
+
+func (adapter *KubeDockerAdapter) snapshotArchivePath(snapshotName string) string {
+	return path.Join(adapter.snapshotsPath, snapshotName+".tar")
+}
+
+func (adapter *KubeDockerAdapter) snapshotMetadataPath(snapshotName string) string {
+	return path.Join(adapter.snapshotsPath, snapshotName+".json")
+}
+
+// CreateVolumeSnapshot exports the content of a Docker volume as a tar archive stored in the snapshots directory of the data path.
+// The volume is mounted inside a helper container (created from the volume copy image but never started) and its content
+// is retrieved through the Docker copy API, the workloads using the volume do not need to be stopped.
+// However, the snapshot is not atomic: files written while the snapshot is taken may be partially captured.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - volumeName: The name of the Docker volume (e.g. the volume name of a persistent volume claim).
+// - snapshotName: The name of the snapshot, a name based on the volume name and the current time is generated when empty.
+//
+// Returns:
+// - The snapshot.
+// - An ErrResourceNotFound error if the volume does not exist, an ErrResourceAlreadyExists error if a snapshot with the same name exists
+// or an ErrInvalidResource error if the snapshot name is invalid.
+func (adapter *KubeDockerAdapter) CreateVolumeSnapshot(ctx context.Context, volumeName, snapshotName string) (k2dtypes.VolumeSnapshot, error) {
+	if snapshotName == "" {
+		snapshotName = fmt.Sprintf("%s-%d", volumeName, time.Now().Unix())
+	}
+
+	err := validateSnapshotName(snapshotName)
+	if err != nil {
+		return k2dtypes.VolumeSnapshot{}, err
+	}
+
+	_, err = os.Stat(adapter.snapshotMetadataPath(snapshotName))
+	if err == nil {
+		return k2dtypes.VolumeSnapshot{}, fmt.Errorf("%w: snapshot %s", adaptererr.ErrResourceAlreadyExists, snapshotName)
+	}
+
+	_, err = adapter.cli.VolumeInspect(ctx, volumeName)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return k2dtypes.VolumeSnapshot{}, fmt.Errorf("%w: volume %s", adaptererr.ErrResourceNotFound, volumeName)
+		}
+		return k2dtypes.VolumeSnapshot{}, fmt.Errorf("unable to inspect docker volume %s: %w", volumeName, err)
+	}
+
+	containerID, err := adapter.createVolumeHelperContainer(ctx, volumeName)
+	if err != nil {
+		return k2dtypes.VolumeSnapshot{}, err
+	}
+	defer adapter.removeVolumeHelperContainer(containerID)
+
+	content, _, err := adapter.cli.CopyFromContainer(ctx, containerID, snapshotVolumeMountPath)
+	if err != nil {
+		return k2dtypes.VolumeSnapshot{}, fmt.Errorf("unable to copy volume content: %w", err)
+	}
+	defer content.Close()
+
+	archivePath := adapter.snapshotArchivePath(snapshotName)
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return k2dtypes.VolumeSnapshot{}, fmt.Errorf("unable to create snapshot archive: %w", err)
+	}
+	defer file.Close()
+
+	size, err := io.Copy(file, content)
+	if err != nil {
+		os.Remove(archivePath)
+		return k2dtypes.VolumeSnapshot{}, fmt.Errorf("unable to write snapshot archive: %w", err)
+	}
+
+	snapshot := k2dtypes.VolumeSnapshot{
+		Name:              snapshotName,
+		VolumeName:        volumeName,
+		Size:              size,
+		CreationTimestamp: time.Now().UTC(),
+	}
+
+	metadata, err := json.Marshal(snapshot)
+	if err != nil {
+		os.Remove(archivePath)
+		return k2dtypes.VolumeSnapshot{}, fmt.Errorf("unable to marshal snapshot metadata: %w", err)
+	}
+
+	err = os.WriteFile(adapter.snapshotMetadataPath(snapshotName), metadata, 0600)
+	if err != nil {
+		os.Remove(archivePath)
+		return k2dtypes.VolumeSnapshot{}, fmt.Errorf("unable to write snapshot metadata: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// RestoreVolumeSnapshot restores the content of a snapshot inside a Docker volume. The volume is created when it does not exist.
+// The files of the snapshot overwrite the files of the volume with the same path, the other files of the volume are left untouched.
+// To prevent the corruption of the data of a running workload, the volume must not be used by any container.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - snapshotName: The name of the snapshot to restore.
+// - volumeName: The name of the Docker volume to restore the snapshot into, the volume the snapshot was taken from when empty.
+//
+// Returns:
+// - An ErrResourceNotFound error if the snapshot does not exist or an ErrResourceConflict error if the volume is used by a container.
+func (adapter *KubeDockerAdapter) RestoreVolumeSnapshot(ctx context.Context, snapshotName, volumeName string) error {
+	snapshot, err := adapter.GetVolumeSnapshot(snapshotName)
+	if err != nil {
+		return err
+	}
+
+	if volumeName == "" {
+		volumeName = snapshot.VolumeName
+	}
+
+	containers, err := adapter.cli.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filters.ByVolume(volumeName)})
+	if err != nil {
+		return fmt.Errorf("unable to list containers using volume %s: %w", volumeName, err)
+	}
+
+	if len(containers) > 0 {
+		return fmt.Errorf("%w: volume %s is used by %d container(s), remove the workloads using it before restoring a snapshot", adaptererr.ErrResourceConflict, volumeName, len(containers))
+	}
+
+	_, err = adapter.cli.VolumeInspect(ctx, volumeName)
+	if err != nil {
+		if !errdefs.IsNotFound(err) {
+			return fmt.Errorf("unable to inspect docker volume %s: %w", volumeName, err)
+		}
+
+		_, err = adapter.cli.VolumeCreate(ctx, volume.CreateOptions{
+			Name:   volumeName,
+			Driver: "local",
+		})
+		if err != nil {
+			return fmt.Errorf("unable to create docker volume %s: %w", volumeName, err)
+		}
+	}
+
+	archive, err := os.Open(adapter.snapshotArchivePath(snapshotName))
+	if err != nil {
+		return fmt.Errorf("unable to open snapshot archive: %w", err)
+	}
+	defer archive.Close()
+
+	containerID, err := adapter.createVolumeHelperContainer(ctx, volumeName)
+	if err != nil {
+		return err
+	}
+	defer adapter.removeVolumeHelperContainer(containerID)
+
+	err = adapter.cli.CopyToContainer(ctx, containerID, path.Dir(snapshotVolumeMountPath), archive, types.CopyToContainerOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to copy snapshot content to volume: %w", err)
+	}
+
+	adapter.logger.Infow("volume snapshot restored",
+		"snapshot", snapshotName,
+		"volume", volumeName,
+	)
+
+	return nil
+}
+
+// GetVolumeSnapshot returns a snapshot. It returns an ErrResourceNotFound error if the snapshot does not exist.
+func (adapter *KubeDockerAdapter) GetVolumeSnapshot(snapshotName string) (k2dtypes.VolumeSnapshot, error) {
+	err := validateSnapshotName(snapshotName)
+	if err != nil {
+		return k2dtypes.VolumeSnapshot{}, err
+	}
+
+	data, err := os.ReadFile(adapter.snapshotMetadataPath(snapshotName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return k2dtypes.VolumeSnapshot{}, fmt.Errorf("%w: snapshot %s", adaptererr.ErrResourceNotFound, snapshotName)
+		}
+		return k2dtypes.VolumeSnapshot{}, fmt.Errorf("unable to read snapshot metadata: %w", err)
+	}
+
+	snapshot := k2dtypes.VolumeSnapshot{}
+	err = json.Unmarshal(data, &snapshot)
+	if err != nil {
+		return k2dtypes.VolumeSnapshot{}, fmt.Errorf("unable to unmarshal snapshot metadata: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// GetVolumeSnapshotArchive returns the tar archive of a snapshot so that it can be downloaded.
+// It returns an ErrResourceNotFound error if the snapshot does not exist.
+func (adapter *KubeDockerAdapter) GetVolumeSnapshotArchive(snapshotName string) (io.ReadCloser, error) {
+	_, err := adapter.GetVolumeSnapshot(snapshotName)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := os.Open(adapter.snapshotArchivePath(snapshotName))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open snapshot archive: %w", err)
+	}
+
+	return archive, nil
+}
+
+// ListVolumeSnapshots returns the snapshots sorted by creation time, the most recent first.
+func (adapter *KubeDockerAdapter) ListVolumeSnapshots() ([]k2dtypes.VolumeSnapshot, error) {
+	entries, err := os.ReadDir(adapter.snapshotsPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read snapshots directory: %w", err)
+	}
+
+	snapshots := []k2dtypes.VolumeSnapshot{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		snapshot, err := adapter.GetVolumeSnapshot(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			adapter.logger.Warnf("unable to read snapshot %s: %s", entry.Name(), err)
+			continue
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreationTimestamp.After(snapshots[j].CreationTimestamp)
+	})
+
+	return snapshots, nil
+}
+
+// DeleteVolumeSnapshot removes a snapshot. It returns an ErrResourceNotFound error if the snapshot does not exist.
+func (adapter *KubeDockerAdapter) DeleteVolumeSnapshot(snapshotName string) error {
+	_, err := adapter.GetVolumeSnapshot(snapshotName)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(adapter.snapshotArchivePath(snapshotName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to remove snapshot archive: %w", err)
+	}
+
+	err = os.Remove(adapter.snapshotMetadataPath(snapshotName))
+	if err != nil {
+		return fmt.Errorf("unable to remove snapshot metadata: %w", err)
+	}
+
+	return nil
+}
+
+// createVolumeHelperContainer creates a container mounting the specified volume, used to copy data from and to the volume.
+// The container is never started. The volume copy image is pulled if it is not available locally.
+func (adapter *KubeDockerAdapter) createVolumeHelperContainer(ctx context.Context, volumeName string) (string, error) {
+	_, _, err := adapter.cli.ImageInspectWithRaw(ctx, adapter.volumeCopyImageName)
+	if err != nil {
+		if !errdefs.IsNotFound(err) {
+			return "", fmt.Errorf("unable to inspect image %s: %w", adapter.volumeCopyImageName, err)
+		}
+
+		err = adapter.pullImage(ctx, adapter.volumeCopyImageName, "", nil)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	containerCreateResponse, err := adapter.cli.ContainerCreate(ctx,
+		&container.Config{
+			Image: adapter.volumeCopyImageName,
+		},
+		&container.HostConfig{
+			Binds: []string{fmt.Sprintf("%s:%s", volumeName, snapshotVolumeMountPath)},
+		},
+		nil,
+		nil,
+		fmt.Sprintf("k2d-volume-snapshot-%s-%d", volumeName, time.Now().UnixNano()),
+	)
+	if err != nil {
+		return "", fmt.Errorf("unable to create volume helper container: %w", err)
+	}
+
+	return containerCreateResponse.ID, nil
+}
+
+func (adapter *KubeDockerAdapter) removeVolumeHelperContainer(containerID string) {
+	err := adapter.cli.ContainerRemove(context.Background(), containerID, types.ContainerRemoveOptions{Force: true})
+	if err != nil {
+		adapter.logger.Warnf("unable to remove volume helper container %s: %s", containerID, err)
+	}
+}
+
+// validateSnapshotName ensures that a snapshot name is a valid DNS subdomain, which also prevents path traversals
+// as the name is used to build the path of the snapshot files.
+func validateSnapshotName(snapshotName string) error {
+	errs := validation.IsDNS1123Subdomain(snapshotName)
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: invalid snapshot name %s: %s", adaptererr.ErrInvalidResource, snapshotName, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
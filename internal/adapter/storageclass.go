@@ -2,22 +2,81 @@ package adapter
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/portainer/k2d/internal/adapter/converter"
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/naming"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
 	"github.com/portainer/k2d/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kubernetes/pkg/apis/storage"
 )
 
-func (adapter *KubeDockerAdapter) GetStorageClass(ctx context.Context, storageClassName string) (*storagev1.StorageClass, error) {
-	if storageClassName != "local" {
-		return nil, adaptererr.ErrResourceNotFound
+// storageClassManifestDataKey is the key used to store the definition of a storage class in its system configmap
+const storageClassManifestDataKey = "manifest"
+
+// CreateStorageClass stores a storage class inside a system configmap. Each storage class maps to a Docker volume driver
+// and a set of driver options (see converter.GetStorageClassVolumeDriver) used when provisioning the volumes
+// of the persistent volume claims referencing the class.
+// Creating a storage class with the name of an existing class replaces it. The volumes already provisioned are not updated.
+// It returns an ErrResourceConflict error when the storage class is the built-in local storage class.
+func (adapter *KubeDockerAdapter) CreateStorageClass(ctx context.Context, storageClass *storagev1.StorageClass) error {
+	if storageClass.Name == converter.DefaultStorageClassName {
+		return fmt.Errorf("%w: the storage class %s is built-in and cannot be modified", adaptererr.ErrResourceConflict, converter.DefaultStorageClassName)
 	}
 
-	defaultStorageClass := converter.BuildDefaultStorageClass(adapter.startTime)
+	if storageClass.CreationTimestamp.IsZero() {
+		storageClass.CreationTimestamp = metav1.NewTime(time.Now())
+	}
+
+	storageClassData, err := json.Marshal(storageClass)
+	if err != nil {
+		return fmt.Errorf("unable to marshal storage class: %w", err)
+	}
+
+	err = adapter.CreateSystemConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: naming.BuildStorageClassSystemConfigMapName(storageClass.Name),
+		},
+		Data: map[string]string{
+			storageClassManifestDataKey: string(storageClassData),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to store storage class system configmap: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStorageClass removes a storage class. The volumes provisioned with the class are not removed.
+// It returns an ErrResourceConflict error when the storage class is the built-in local storage class.
+func (adapter *KubeDockerAdapter) DeleteStorageClass(ctx context.Context, storageClassName string) error {
+	if storageClassName == converter.DefaultStorageClassName {
+		return fmt.Errorf("%w: the storage class %s is built-in and cannot be removed", adaptererr.ErrResourceConflict, converter.DefaultStorageClassName)
+	}
+
+	err := adapter.DeleteSystemConfigMap(naming.BuildStorageClassSystemConfigMapName(storageClassName))
+	if err != nil {
+		return fmt.Errorf("unable to delete storage class system configmap: %w", err)
+	}
+
+	return nil
+}
+
+func (adapter *KubeDockerAdapter) GetStorageClass(ctx context.Context, storageClassName string) (*storagev1.StorageClass, error) {
+	storageClass, err := adapter.getStorageClass(storageClassName)
+	if err != nil {
+		return nil, err
+	}
 
 	versionedStorageClass := storagev1.StorageClass{
 		TypeMeta: metav1.TypeMeta{
@@ -26,7 +85,7 @@ func (adapter *KubeDockerAdapter) GetStorageClass(ctx context.Context, storageCl
 		},
 	}
 
-	err := adapter.ConvertK8SResource(&defaultStorageClass, &versionedStorageClass)
+	err = adapter.ConvertK8SResource(storageClass, &versionedStorageClass)
 	if err != nil {
 		return nil, fmt.Errorf("unable to convert internal object to versioned object: %w", err)
 	}
@@ -64,11 +123,76 @@ func (adapter *KubeDockerAdapter) GetStorageClassTable(ctx context.Context) (*me
 	return k8s.GenerateTable(&storageClassList)
 }
 
+// getStorageClass returns the built-in local storage class or a storage class stored in a system configmap.
+// It returns an ErrResourceNotFound error if the storage class does not exist.
+func (adapter *KubeDockerAdapter) getStorageClass(storageClassName string) (*storage.StorageClass, error) {
+	if storageClassName == converter.DefaultStorageClassName {
+		defaultStorageClass := converter.BuildDefaultStorageClass(adapter.startTime)
+		return &defaultStorageClass, nil
+	}
+
+	configMap, err := adapter.configMapStore.GetConfigMap(naming.BuildStorageClassSystemConfigMapName(storageClassName), k2dtypes.K2DNamespaceName)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			return nil, adaptererr.ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("unable to get storage class system configmap: %w", err)
+	}
+
+	return adapter.decodeStorageClass(configMap.Data[storageClassManifestDataKey])
+}
+
+func (adapter *KubeDockerAdapter) decodeStorageClass(storageClassData string) (*storage.StorageClass, error) {
+	versionedStorageClass := storagev1.StorageClass{}
+	err := json.Unmarshal([]byte(storageClassData), &versionedStorageClass)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal storage class: %w", err)
+	}
+
+	storageClass := storage.StorageClass{}
+	err = adapter.ConvertK8SResource(&versionedStorageClass, &storageClass)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert versioned storage class to internal storage class: %w", err)
+	}
+
+	storageClass.TypeMeta = metav1.TypeMeta{
+		Kind:       "StorageClass",
+		APIVersion: "storage.k8s.io/v1",
+	}
+
+	return &storageClass, nil
+}
+
 func (adapter *KubeDockerAdapter) listStorageClasses(ctx context.Context) (storage.StorageClassList, error) {
 	defaultStorageClass := converter.BuildDefaultStorageClass(adapter.startTime)
 
+	configMaps, err := adapter.ListSystemConfigMaps()
+	if err != nil {
+		return storage.StorageClassList{}, fmt.Errorf("unable to list system configmaps: %w", err)
+	}
+
+	storedStorageClasses := []storage.StorageClass{}
+	for _, configMap := range configMaps.Items {
+		if !strings.HasPrefix(configMap.Name, naming.StorageClassSystemConfigMapPrefix) {
+			continue
+		}
+
+		storageClass, err := adapter.decodeStorageClass(configMap.Data[storageClassManifestDataKey])
+		if err != nil {
+			adapter.logger.Warnf("unable to decode storage class stored in system configmap %s: %s", configMap.Name, err)
+			continue
+		}
+
+		storedStorageClasses = append(storedStorageClasses, *storageClass)
+	}
+
+	sort.Slice(storedStorageClasses, func(i, j int) bool {
+		return storedStorageClasses[i].Name < storedStorageClasses[j].Name
+	})
+
 	storageClasses := []storage.StorageClass{}
 	storageClasses = append(storageClasses, defaultStorageClass)
+	storageClasses = append(storageClasses, storedStorageClasses...)
 
 	return storage.StorageClassList{
 		TypeMeta: metav1.TypeMeta{
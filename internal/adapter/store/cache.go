@@ -0,0 +1,185 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+type cacheEntry[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+// CachedStore is a read-through cache that sits in front of a ConfigMapStore and a SecretStore.
+// It keeps the result of single resource lookups (GetConfigMap, GetSecret) in memory for a configurable
+// duration so that hot paths such as environment variable resolution during batch deployments
+// do not hit the disk or spawn a copy container for every lookup.
+//
+// Entries are invalidated when they expire and every time the associated resource is stored or deleted
+// through the cache (write-through invalidation). Each invalidation increments a generation counter: a lookup
+// only caches its result if no invalidation happened while the resource was retrieved from the underlying store,
+// as the retrieved resource may predate the write.
+// List and bind operations are always forwarded to the underlying stores.
+type CachedStore struct {
+	configMapStore       ConfigMapStore
+	secretStore          SecretStore
+	ttl                  time.Duration
+	mutex                sync.RWMutex
+	configMaps           map[string]cacheEntry[*core.ConfigMap]
+	configMapsGeneration uint64
+	secrets              map[string]cacheEntry[*core.Secret]
+	secretsGeneration    uint64
+}
+
+// NewCachedStore creates a new CachedStore wrapping the specified ConfigMapStore and SecretStore.
+// Cached entries are kept for the duration specified by ttl.
+func NewCachedStore(configMapStore ConfigMapStore, secretStore SecretStore, ttl time.Duration) *CachedStore {
+	return &CachedStore{
+		configMapStore: configMapStore,
+		secretStore:    secretStore,
+		ttl:            ttl,
+		mutex:          sync.RWMutex{},
+		configMaps:     make(map[string]cacheEntry[*core.ConfigMap]),
+		secrets:        make(map[string]cacheEntry[*core.Secret]),
+	}
+}
+
+func buildCacheKey(name, namespace string) string {
+	return namespace + "/" + name
+}
+
+// DeleteConfigMap deletes the ConfigMap from the underlying store and invalidates the associated cache entry.
+func (s *CachedStore) DeleteConfigMap(configMapName, namespace string) error {
+	defer s.invalidateConfigMap(configMapName, namespace)
+
+	return s.configMapStore.DeleteConfigMap(configMapName, namespace)
+}
+
+// GetConfigMapBinds forwards the call to the underlying store.
+func (s *CachedStore) GetConfigMapBinds(configMap *core.ConfigMap) (map[string]string, error) {
+	return s.configMapStore.GetConfigMapBinds(configMap)
+}
+
+// GetConfigMap returns the ConfigMap from the cache if a valid entry exists.
+// Otherwise it retrieves the ConfigMap from the underlying store and caches it.
+// A copy of the cached ConfigMap is always returned so that callers can safely modify it.
+func (s *CachedStore) GetConfigMap(configMapName, namespace string) (*core.ConfigMap, error) {
+	key := buildCacheKey(configMapName, namespace)
+
+	s.mutex.RLock()
+	entry, found := s.configMaps[key]
+	generation := s.configMapsGeneration
+	s.mutex.RUnlock()
+
+	if found && time.Now().Before(entry.expiresAt) {
+		return entry.value.DeepCopy(), nil
+	}
+
+	configMap, err := s.configMapStore.GetConfigMap(configMapName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// The ConfigMap is not cached when it was stored or deleted while it was retrieved, the value may be stale
+	s.mutex.Lock()
+	if s.configMapsGeneration == generation {
+		s.configMaps[key] = cacheEntry[*core.ConfigMap]{
+			value:     configMap.DeepCopy(),
+			expiresAt: time.Now().Add(s.ttl),
+		}
+	}
+	s.mutex.Unlock()
+
+	return configMap, nil
+}
+
+// GetConfigMaps forwards the call to the underlying store.
+func (s *CachedStore) GetConfigMaps(namespace string) (core.ConfigMapList, error) {
+	return s.configMapStore.GetConfigMaps(namespace)
+}
+
+// StoreConfigMap stores the ConfigMap in the underlying store and invalidates the associated cache entry.
+func (s *CachedStore) StoreConfigMap(configMap *corev1.ConfigMap) error {
+	defer s.invalidateConfigMap(configMap.Name, configMap.Namespace)
+
+	return s.configMapStore.StoreConfigMap(configMap)
+}
+
+// DeleteSecret deletes the Secret from the underlying store and invalidates the associated cache entry.
+func (s *CachedStore) DeleteSecret(secretName, namespace string) error {
+	defer s.invalidateSecret(secretName, namespace)
+
+	return s.secretStore.DeleteSecret(secretName, namespace)
+}
+
+// GetSecretBinds forwards the call to the underlying store.
+func (s *CachedStore) GetSecretBinds(secret *core.Secret) (map[string]string, error) {
+	return s.secretStore.GetSecretBinds(secret)
+}
+
+// GetSecret returns the Secret from the cache if a valid entry exists.
+// Otherwise it retrieves the Secret from the underlying store and caches it.
+// A copy of the cached Secret is always returned so that callers can safely modify it.
+func (s *CachedStore) GetSecret(secretName, namespace string) (*core.Secret, error) {
+	key := buildCacheKey(secretName, namespace)
+
+	s.mutex.RLock()
+	entry, found := s.secrets[key]
+	generation := s.secretsGeneration
+	s.mutex.RUnlock()
+
+	if found && time.Now().Before(entry.expiresAt) {
+		return entry.value.DeepCopy(), nil
+	}
+
+	secret, err := s.secretStore.GetSecret(secretName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// The Secret is not cached when it was stored or deleted while it was retrieved, the value may be stale
+	s.mutex.Lock()
+	if s.secretsGeneration == generation {
+		s.secrets[key] = cacheEntry[*core.Secret]{
+			value:     secret.DeepCopy(),
+			expiresAt: time.Now().Add(s.ttl),
+		}
+	}
+	s.mutex.Unlock()
+
+	return secret, nil
+}
+
+// GetSecrets forwards the call to the underlying store.
+func (s *CachedStore) GetSecrets(namespace string, selector labels.Selector) (core.SecretList, error) {
+	return s.secretStore.GetSecrets(namespace, selector)
+}
+
+// StoreSecret stores the Secret in the underlying store and invalidates the associated cache entry.
+func (s *CachedStore) StoreSecret(secret *corev1.Secret) error {
+	defer s.invalidateSecret(secret.Name, secret.Namespace)
+
+	return s.secretStore.StoreSecret(secret)
+}
+
+// invalidateConfigMap removes the cache entry of a ConfigMap once it has been stored or deleted, and increments
+// the ConfigMaps generation so that the lookups that started before the write do not cache a stale ConfigMap.
+func (s *CachedStore) invalidateConfigMap(configMapName, namespace string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.configMaps, buildCacheKey(configMapName, namespace))
+	s.configMapsGeneration++
+}
+
+// invalidateSecret removes the cache entry of a Secret once it has been stored or deleted, and increments
+// the Secrets generation so that the lookups that started before the write do not cache a stale Secret.
+func (s *CachedStore) invalidateSecret(secretName, namespace string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.secrets, buildCacheKey(secretName, namespace))
+	s.secretsGeneration++
+}
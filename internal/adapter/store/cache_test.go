@@ -0,0 +1,101 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// blockingConfigMapStore is a ConfigMapStore that waits for a signal before returning the ConfigMap
+// it retrieved, which allows a write to happen while a lookup is in progress.
+type blockingConfigMapStore struct {
+	ConfigMapStore
+	value     string
+	retrieved chan struct{}
+	release   chan struct{}
+}
+
+func (s *blockingConfigMapStore) GetConfigMap(configMapName, namespace string) (*core.ConfigMap, error) {
+	configMap := &core.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: configMapName, Namespace: namespace},
+		Data:       map[string]string{"value": s.value},
+	}
+
+	if s.release != nil {
+		s.retrieved <- struct{}{}
+		<-s.release
+	}
+
+	return configMap, nil
+}
+
+func (s *blockingConfigMapStore) StoreConfigMap(configMap *corev1.ConfigMap) error {
+	s.value = configMap.Data["value"]
+	return nil
+}
+
+func TestCachedStoreDoesNotCacheStaleConfigMap(t *testing.T) {
+	backend := &blockingConfigMapStore{
+		value:     "old",
+		retrieved: make(chan struct{}),
+		release:   make(chan struct{}),
+	}
+	cache := NewCachedStore(backend, nil, time.Minute)
+
+	done := make(chan *core.ConfigMap)
+	go func() {
+		configMap, _ := cache.GetConfigMap("web", "default")
+		done <- configMap
+	}()
+
+	// The ConfigMap is updated after the lookup retrieved the old value but before it is cached
+	<-backend.retrieved
+	err := cache.StoreConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Data:       map[string]string{"value": "new"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	close(backend.release)
+
+	if configMap := <-done; configMap.Data["value"] != "old" {
+		t.Fatalf("expected the concurrent lookup to return the old value, got %q", configMap.Data["value"])
+	}
+
+	backend.release = nil
+
+	configMap, err := cache.GetConfigMap("web", "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if configMap.Data["value"] != "new" {
+		t.Errorf("expected the new value to be returned, got %q", configMap.Data["value"])
+	}
+}
+
+func TestCachedStoreCachesConfigMap(t *testing.T) {
+	backend := &blockingConfigMapStore{value: "old"}
+	cache := NewCachedStore(backend, nil, time.Minute)
+
+	_, err := cache.GetConfigMap("web", "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The underlying value changes without going through the cache
+	backend.value = "new"
+
+	configMap, err := cache.GetConfigMap("web", "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if configMap.Data["value"] != "old" {
+		t.Errorf("expected the cached value to be returned, got %q", configMap.Data["value"])
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,10 +22,11 @@ import (
 // using a mutex to ensure thread-safety during the delete operation.
 //
 // The function performs the following steps:
-// 1. Reads all the files in the ConfigMap directory.
-// 2. Searches for a specific ConfigMap file by its prefix, which is a combination of its name and namespace.
-// 3. Removes the metadata file associated with the ConfigMap from the disk.
-// 4. Removes all data files associated with the ConfigMap from the disk.
+// 1. Loads the metadata file of the ConfigMap from the directory of its namespace.
+// 2. Removes the metadata file associated with the ConfigMap from the disk, unless it belongs to a ConfigMap of another namespace.
+// 3. Removes all data files associated with the ConfigMap from the disk, using the keys recorded in the index of the namespace.
+// 4. Removes the ConfigMap from the index of the namespace.
+// 5. Removes the files kept in the flat directory of the store for a ConfigMap created by a previous version of k2d.
 //
 // Parameters:
 // - configMapName: The name of the ConfigMap to delete.
@@ -36,8 +38,9 @@ func (s *FileSystemStore) DeleteConfigMap(configMapName, namespace string) error
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	shard := shardPath(s.configMapPath, namespace)
 	metadataFileName := buildConfigMapMetadataFileName(configMapName, namespace)
-	metadataFilePath := path.Join(s.configMapPath, metadataFileName)
+	metadataFilePath := path.Join(shard, metadataFileName)
 
 	metadataFileExists, err := filesystem.FileExists(metadataFilePath)
 	if err != nil {
@@ -48,24 +51,40 @@ func (s *FileSystemStore) DeleteConfigMap(configMapName, namespace string) error
 		return errors.ErrResourceNotFound
 	}
 
+	metadata, err := filesystem.LoadMetadataFromDisk(metadataFilePath)
+	if err != nil {
+		return fmt.Errorf("unable to load configmap metadata from disk: %w", err)
+	}
+
+	if checkMetadataNamespace(metadata, "configmap", configMapName, namespace) != nil {
+		return errors.ErrResourceNotFound
+	}
+
+	index, err := loadShardIndex(s.configMapPath, namespace, configMapMetadataSuffix, ConfigMapSeparator)
+	if err != nil {
+		return fmt.Errorf("unable to load configmap index: %w", err)
+	}
+
 	err = os.Remove(metadataFilePath)
 	if err != nil {
 		return fmt.Errorf("unable to remove configmap metadata file %s: %w", metadataFileName, err)
 	}
 
-	files, err := os.ReadDir(s.configMapPath)
+	filePrefix := buildConfigMapFilePrefix(configMapName, namespace)
+	err = removeDataFiles(shard, filePrefix, index[configMapName])
 	if err != nil {
-		return fmt.Errorf("unable to read configmap directory: %w", err)
+		return fmt.Errorf("unable to remove configmap data files: %w", err)
 	}
 
-	filePrefix := buildConfigMapFilePrefix(configMapName, namespace)
-	for _, file := range files {
-		if strings.HasPrefix(file.Name(), filePrefix) {
-			err := os.Remove(path.Join(s.configMapPath, file.Name()))
-			if err != nil {
-				return fmt.Errorf("unable to remove configmap data file %s: %w", file.Name(), err)
-			}
-		}
+	delete(index, configMapName)
+	err = storeShardIndex(s.configMapPath, namespace, index)
+	if err != nil {
+		return err
+	}
+
+	err = removeLegacyFiles(s.configMapPath, metadataFileName, filePrefix, namespace)
+	if err != nil {
+		return fmt.Errorf("unable to remove legacy configmap files: %w", err)
 	}
 
 	return nil
@@ -92,11 +111,10 @@ func (s *FileSystemStore) GetConfigMapBinds(configMap *core.ConfigMap) (map[stri
 // using a mutex to ensure thread-safety during the read operation.
 //
 // The function performs the following steps:
-// 1. Reads all the files in the ConfigMap directory.
-// 2. Searches for a specific ConfigMap file by its prefix, which is a combination of its name and namespace.
-// 3. Loads the metadata associated with the ConfigMap from the disk.
-// 4. Creates a ConfigMap object based on the loaded metadata.
-// 5. Updates the ConfigMap object with data loaded from the ConfigMap data file(s).
+// 1. Loads the metadata associated with the ConfigMap from the directory of its namespace. The ConfigMap is reported
+// as not found when the metadata belongs to a ConfigMap of another namespace (see checkMetadataNamespace).
+// 2. Creates a ConfigMap object based on the loaded metadata.
+// 3. Updates the ConfigMap object with data loaded from the data file(s) of the keys recorded in the index of the namespace.
 //
 // Parameters:
 // - configMapName: The name of the ConfigMap to retrieve.
@@ -109,8 +127,9 @@ func (s *FileSystemStore) GetConfigMap(configMapName, namespace string) (*core.C
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
+	shard := shardPath(s.configMapPath, namespace)
 	metadataFileName := buildConfigMapMetadataFileName(configMapName, namespace)
-	metadataFilePath := path.Join(s.configMapPath, metadataFileName)
+	metadataFilePath := path.Join(shard, metadataFileName)
 
 	metadataFileExists, err := filesystem.FileExists(metadataFilePath)
 	if err != nil {
@@ -126,23 +145,25 @@ func (s *FileSystemStore) GetConfigMap(configMapName, namespace string) (*core.C
 		return nil, fmt.Errorf("unable to load configmap metadata from disk: %w", err)
 	}
 
+	if checkMetadataNamespace(metadata, "configmap", configMapName, namespace) != nil {
+		return nil, errors.ErrResourceNotFound
+	}
+
 	configMap, err := createConfigMapFromMetadata(configMapName, namespace, metadata)
 	if err != nil {
 		return nil, fmt.Errorf("unable to build configmap from metadata: %w", err)
 	}
 
-	files, err := os.ReadDir(s.configMapPath)
+	index, err := loadShardIndex(s.configMapPath, namespace, configMapMetadataSuffix, ConfigMapSeparator)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read configmap directory: %w", err)
+		return nil, fmt.Errorf("unable to load configmap index: %w", err)
 	}
 
 	filePrefix := buildConfigMapFilePrefix(configMapName, namespace)
-	for _, file := range files {
-		if strings.HasPrefix(file.Name(), filePrefix) {
-			err := s.updateConfigMapDataFromFile(&configMap, file.Name())
-			if err != nil {
-				return nil, fmt.Errorf("unable to update configmap data from file %s: %w", file.Name(), err)
-			}
+	for _, key := range index[configMapName] {
+		err := s.updateConfigMapDataFromFile(&configMap, shard, filePrefix+key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to update configmap data from file %s: %w", filePrefix+key, err)
 		}
 	}
 
@@ -154,10 +175,9 @@ func (s *FileSystemStore) GetConfigMap(configMapName, namespace string) (*core.C
 // using a mutex to ensure thread-safety during the read operation.
 //
 // The function performs the following steps:
-// 1. Reads all the files in the ConfigMap directory.
-// 2. Segregates the files into metadata and data files.
-// 3. Builds ConfigMap objects based on the segregated files.
-// 4. Returns a ConfigMapList containing all the constructed ConfigMaps.
+// 1. Lists the namespaces to read: the given namespace, or all the namespaces stored on disk when it is empty.
+// 2. Builds the ConfigMap objects of each namespace based on the index of the namespace (see buildNamespaceConfigMaps).
+// 3. Returns a ConfigMapList containing all the constructed ConfigMaps.
 //
 // Parameters:
 // - namespace: The namespace for which to retrieve ConfigMaps.
@@ -169,16 +189,23 @@ func (s *FileSystemStore) GetConfigMaps(namespace string) (core.ConfigMapList, e
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	files, err := os.ReadDir(s.configMapPath)
-	if err != nil {
-		return core.ConfigMapList{}, fmt.Errorf("unable to read configmap directory: %w", err)
+	namespaces := []string{namespace}
+	if namespace == "" {
+		var err error
+		namespaces, err = listShardNamespaces(s.configMapPath)
+		if err != nil {
+			return core.ConfigMapList{}, fmt.Errorf("unable to read configmap directory: %w", err)
+		}
 	}
 
-	metadataFiles, dataFiles := s.isolateConfigMapMetadataAndDataFiles(files)
+	configMaps := []core.ConfigMap{}
+	for _, namespace := range namespaces {
+		namespaceConfigMaps, err := s.buildNamespaceConfigMaps(namespace)
+		if err != nil {
+			return core.ConfigMapList{}, fmt.Errorf("unable to build configmaps: %w", err)
+		}
 
-	configMaps, err := s.buildConfigMaps(metadataFiles, dataFiles, namespace)
-	if err != nil {
-		return core.ConfigMapList{}, fmt.Errorf("unable to build configmaps: %w", err)
+		configMaps = append(configMaps, namespaceConfigMaps...)
 	}
 
 	return core.ConfigMapList{
@@ -195,13 +222,17 @@ func (s *FileSystemStore) GetConfigMaps(namespace string) (core.ConfigMapList, e
 //
 // The function performs the following steps:
 // 1. Merges any existing labels with new ones including namespace and creation timestamp.
-// 2. Stores metadata associated with the ConfigMap on the disk.
-// 3. Stores the ConfigMap data on the disk.
+// 2. Stores metadata associated with the ConfigMap in the directory of its namespace.
+// 3. Stores the ConfigMap data and binary data on the disk. The binary data is stored as raw bytes and its keys
+// are recorded in the metadata so that they can be returned as binary data.
+// 4. Removes the data files of the keys that are not part of the ConfigMap anymore and records the keys
+// of the ConfigMap in the index of the namespace.
 //
 // Parameters:
 // - configMap: A pointer to the ConfigMap object to store.
 //
 // Returns:
+// - An ErrResourceConflict error if the files of the ConfigMap are already used by a ConfigMap of another namespace.
 // - An error object if the function fails to store the ConfigMap.
 func (s *FileSystemStore) StoreConfigMap(configMap *corev1.ConfigMap) error {
 	s.mutex.Lock()
@@ -213,112 +244,112 @@ func (s *FileSystemStore) StoreConfigMap(configMap *corev1.ConfigMap) error {
 	}
 	maputils.MergeMapsInPlace(labels, configMap.Labels)
 
-	metadataFileName := buildConfigMapMetadataFileName(configMap.Name, configMap.Namespace)
-	err := filesystem.StoreMetadataOnDisk(s.configMapPath, metadataFileName, labels)
-	if err != nil {
-		return fmt.Errorf("unable to store configmap metadata on disk: %w", err)
+	dataMap := map[string]string{}
+	maputils.MergeMapsInPlace(dataMap, configMap.Data)
+
+	if len(configMap.BinaryData) > 0 {
+		binaryDataKeys := make([]string, 0, len(configMap.BinaryData))
+		for key, value := range configMap.BinaryData {
+			binaryDataKeys = append(binaryDataKeys, key)
+			dataMap[key] = string(value)
+		}
+		sort.Strings(binaryDataKeys)
+
+		labels[BinaryDataKeysLabelKey] = strings.Join(binaryDataKeys, ",")
 	}
 
-	filePrefix := buildConfigMapFilePrefix(configMap.Name, configMap.Namespace)
-	err = filesystem.StoreDataMapOnDisk(s.configMapPath, filePrefix, configMap.Data)
+	shard := shardPath(s.configMapPath, configMap.Namespace)
+	err := filesystem.CreateDir(shard)
 	if err != nil {
-		return fmt.Errorf("unable to store configmap data on disk: %w", err)
+		return fmt.Errorf("unable to create configmap directory of namespace %s: %w", configMap.Namespace, err)
 	}
 
-	return nil
-}
+	metadataFileName := buildConfigMapMetadataFileName(configMap.Name, configMap.Namespace)
+	metadataFilePath := path.Join(shard, metadataFileName)
 
-// isolateConfigMapMetadataAndDataFiles segregates the given directory entries into
-// configmap metadata files and data files based on their file name suffixes and prefixes.
-func (s *FileSystemStore) isolateConfigMapMetadataAndDataFiles(files []os.DirEntry) ([]string, map[string][]string) {
-	metadataFiles := []string{}
-	dataFiles := map[string][]string{}
+	metadataFileExists, err := filesystem.FileExists(metadataFilePath)
+	if err != nil {
+		return fmt.Errorf("unable to check if configmap metadata file %s exists: %w", metadataFileName, err)
+	}
 
-	for _, file := range files {
-		// Skip non configmap files
-		if !strings.Contains(file.Name(), ConfigMapSeparator) && !strings.Contains(file.Name(), "k2dcm.metadata") {
-			continue
+	if metadataFileExists {
+		existingMetadata, err := filesystem.LoadMetadataFromDisk(metadataFilePath)
+		if err != nil {
+			return fmt.Errorf("unable to load configmap metadata from disk: %w", err)
 		}
 
-		if strings.HasSuffix(file.Name(), ".metadata") {
-			metadataFiles = append(metadataFiles, file.Name())
-		} else {
-			namespacedConfigMapName, _, err := getNamespacedConfigMapNameAndKeyFromFileName(file.Name())
-			if err != nil {
-				s.logger.Warnf("unable to get namespaced configmap name from file name %s: %s", file.Name(), err.Error())
-				continue
-			}
-
-			dataFiles[namespacedConfigMapName] = append(dataFiles[namespacedConfigMapName], file.Name())
+		err = checkMetadataNamespace(existingMetadata, "configmap", configMap.Name, configMap.Namespace)
+		if err != nil {
+			return err
 		}
 	}
 
-	return metadataFiles, dataFiles
-}
+	index, err := loadShardIndex(s.configMapPath, configMap.Namespace, configMapMetadataSuffix, ConfigMapSeparator)
+	if err != nil {
+		return fmt.Errorf("unable to load configmap index: %w", err)
+	}
 
-// buildConfigMaps constructs a list of ConfigMap objects based on the given metadata
-// files, data files and namespace. It also updates the ConfigMap objects
-// with data loaded from the data files.
-func (s *FileSystemStore) buildConfigMaps(metadataFiles []string, dataFiles map[string][]string, namespace string) ([]core.ConfigMap, error) {
-	// Load metadata from disk and build initial configmaps
-	configMaps, err := s.loadMetadataAndInitConfigMaps(metadataFiles, namespace)
+	err = filesystem.StoreMetadataOnDisk(shard, metadataFileName, labels)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("unable to store configmap metadata on disk: %w", err)
 	}
 
-	// Populate configmaps with data
-	for namespacedConfigMapName, dataFiles := range dataFiles {
-		for _, dataFile := range dataFiles {
-			if configMap, found := configMaps[namespacedConfigMapName]; found {
-				s.updateConfigMapDataFromFile(&configMap, dataFile)
-				configMaps[namespacedConfigMapName] = configMap
-			}
-		}
+	filePrefix := buildConfigMapFilePrefix(configMap.Name, configMap.Namespace)
+	err = removeStaleDataFiles(shard, filePrefix, index[configMap.Name], dataMap)
+	if err != nil {
+		return fmt.Errorf("unable to remove stale configmap data files: %w", err)
 	}
 
-	// Convert map values to slice
-	configMapSlice := make([]core.ConfigMap, 0, len(configMaps))
-	for _, configMap := range configMaps {
-		configMapSlice = append(configMapSlice, configMap)
+	err = filesystem.StoreDataMapOnDisk(shard, filePrefix, dataMap)
+	if err != nil {
+		return fmt.Errorf("unable to store configmap data on disk: %w", err)
 	}
 
-	return configMapSlice, nil
+	index[configMap.Name] = storedDataKeys(dataMap)
+	return storeShardIndex(s.configMapPath, configMap.Namespace, index)
 }
 
-// loadMetadataAndInitConfigMaps loads configmap metadata from disk and initializes a map
-// of ConfigMap objects based on the loaded metadata and namespace.
-func (s *FileSystemStore) loadMetadataAndInitConfigMaps(metadataFiles []string, namespace string) (map[string]core.ConfigMap, error) {
-	configMaps := map[string]core.ConfigMap{}
+// buildNamespaceConfigMaps constructs the ConfigMap objects of a namespace based on the index of the namespace.
+// The metadata and the data of each ConfigMap are loaded from the directory of the namespace.
+func (s *FileSystemStore) buildNamespaceConfigMaps(namespace string) ([]core.ConfigMap, error) {
+	index, err := loadShardIndex(s.configMapPath, namespace, configMapMetadataSuffix, ConfigMapSeparator)
+	if err != nil {
+		return nil, err
+	}
+
+	shard := shardPath(s.configMapPath, namespace)
+	configMaps := make([]core.ConfigMap, 0, len(index))
 
-	for _, metadataFile := range metadataFiles {
-		metadataFilePath := path.Join(s.configMapPath, metadataFile)
-		metadata, err := filesystem.LoadMetadataFromDisk(metadataFilePath)
+	for _, configMapName := range storedResourceNames(index) {
+		metadata, err := filesystem.LoadMetadataFromDisk(path.Join(shard, buildConfigMapMetadataFileName(configMapName, namespace)))
 		if err != nil {
-			return configMaps, fmt.Errorf("unable to load configmap metadata from disk: %w", err)
-		}
-
-		namespaceName := metadata[types.NamespaceNameLabelKey]
-		if namespace != "" && namespace != namespaceName {
+			s.logger.Warnf("unable to load configmap metadata from disk: %s", err.Error())
 			continue
 		}
 
-		namespacedConfigMapName := getNamespacedConfigMapNameFromMetadataFileName(metadataFile)
-		configMapName := getConfigMapNameFromNamespacedConfigMapName(namespacedConfigMapName, namespaceName)
-
-		configMap, err := createConfigMapFromMetadata(configMapName, namespaceName, metadata)
+		configMap, err := createConfigMapFromMetadata(configMapName, namespace, metadata)
 		if err != nil {
 			s.logger.Warnf("unable to build configmap from metadata: %s", err.Error())
 			continue
 		}
 
-		configMaps[namespacedConfigMapName] = configMap
+		filePrefix := buildConfigMapFilePrefix(configMapName, namespace)
+		for _, key := range index[configMapName] {
+			err := s.updateConfigMapDataFromFile(&configMap, shard, filePrefix+key)
+			if err != nil {
+				s.logger.Warnf("unable to update configmap data from file: %s", err.Error())
+			}
+		}
+
+		configMaps = append(configMaps, configMap)
 	}
 
 	return configMaps, nil
 }
 
 // createConfigMapFromMetadata creates a new ConfigMap object based on the given metadata,
-// configmap name, and namespace.
+// configmap name, and namespace. The binaryData keys recorded in the metadata are initialized
+// in the BinaryData map so that their content is loaded as binary data (see updateConfigMapDataFromFile).
 func createConfigMapFromMetadata(configMapName, namespace string, metadata map[string]string) (core.ConfigMap, error) {
 	configMap := core.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
@@ -331,7 +362,15 @@ func createConfigMapFromMetadata(configMapName, namespace string, metadata map[s
 			Name:        configMapName,
 			Annotations: map[string]string{},
 		},
-		Data: map[string]string{},
+		Data:       map[string]string{},
+		BinaryData: map[string][]byte{},
+	}
+
+	if binaryDataKeys := metadata[BinaryDataKeysLabelKey]; binaryDataKeys != "" {
+		for _, key := range strings.Split(binaryDataKeys, ",") {
+			configMap.BinaryData[key] = nil
+		}
+		delete(configMap.Labels, BinaryDataKeysLabelKey)
 	}
 
 	creationTimestamp, ok := metadata[CreationTimestampLabelKey]
@@ -347,9 +386,9 @@ func createConfigMapFromMetadata(configMapName, namespace string, metadata map[s
 }
 
 // updateConfigMapDataFromFile updates a ConfigMap object with data loaded from a given
-// data file.
-func (s *FileSystemStore) updateConfigMapDataFromFile(configMap *core.ConfigMap, dataFile string) error {
-	dataFilePath := path.Join(s.configMapPath, dataFile)
+// data file of the directory of its namespace.
+func (s *FileSystemStore) updateConfigMapDataFromFile(configMap *core.ConfigMap, shard, dataFile string) error {
+	dataFilePath := path.Join(shard, dataFile)
 
 	data, err := os.ReadFile(dataFilePath)
 	if err != nil {
@@ -361,7 +400,11 @@ func (s *FileSystemStore) updateConfigMapDataFromFile(configMap *core.ConfigMap,
 		return fmt.Errorf("unable to get configmap key from file name %s: %w", dataFile, err)
 	}
 
-	configMap.Data[configMapKey] = string(data)
+	if _, isBinary := configMap.BinaryData[configMapKey]; isBinary {
+		configMap.BinaryData[configMapKey] = data
+	} else {
+		configMap.Data[configMapKey] = string(data)
+	}
 
 	// The path to the file is stored in the annotation so that it can be mounted
 	// inside a container by reading the store.k2d.io/filesystem/path/* annotations.
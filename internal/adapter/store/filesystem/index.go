@@ -0,0 +1,229 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/portainer/k2d/pkg/filesystem"
+)
+
+const (
+	// IndexFileExtension is the extension of the index file of a namespace.
+	// The index of a namespace is stored next to the directory of the namespace: [folder]/[namespace].index
+	IndexFileExtension = ".index"
+
+	// configMapMetadataSuffix is the suffix of the name of a ConfigMap metadata file
+	configMapMetadataSuffix = "-k2dcm.metadata"
+
+	// secretMetadataSuffix is the suffix of the name of a Secret metadata file
+	secretMetadataSuffix = "-k2dsec.metadata"
+)
+
+// The ConfigMaps and Secrets are sharded by namespace: the files of a resource are stored in the directory of its namespace,
+// [folder]/[namespace]/, using the same file names as the previous flat layout (see naming.go).
+//
+// Each namespace has an index file listing the names of its resources with the keys of their data, using the format
+// of the metadata files: one [name]=[comma separated keys] line per resource. The index is used to list and read the
+// resources of a namespace without reading its directory. It is rewritten each time a resource is stored or deleted,
+// and rebuilt from the directory of the namespace when it is missing or older than the directory (e.g. when k2d stopped
+// between the creation of the files of a resource and the update of the index).
+
+// shardPath returns the directory where the resources of a namespace are stored.
+func shardPath(folderPath, namespace string) string {
+	return path.Join(folderPath, namespace)
+}
+
+// indexFilePath returns the path to the index file of a namespace.
+func indexFilePath(folderPath, namespace string) string {
+	return path.Join(folderPath, namespace+IndexFileExtension)
+}
+
+// listShardNamespaces returns the namespaces that have a directory in the specified store folder.
+func listShardNamespaces(folderPath string) ([]string, error) {
+	files, err := os.ReadDir(folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read directory %s: %w", folderPath, err)
+	}
+
+	namespaces := []string{}
+	for _, file := range files {
+		if file.IsDir() {
+			namespaces = append(namespaces, file.Name())
+		}
+	}
+
+	return namespaces, nil
+}
+
+// loadShardIndex returns the index of the resources of a namespace, as a map of resource names to data keys.
+// The index is rebuilt from the directory of the namespace when the index file is missing, invalid or outdated.
+//
+// Parameters:
+//   - folderPath: The store folder (ConfigMaps or Secrets).
+//   - namespace: The namespace of the resources.
+//   - metadataSuffix: The suffix of the metadata files of the resources.
+//   - separator: The separator used to build the names of the data files of the resources.
+//
+// Returns:
+//   - The index of the namespace, empty when the namespace has no directory.
+//   - An error if the directory of the namespace cannot be read or the index cannot be stored.
+func loadShardIndex(folderPath, namespace, metadataSuffix, separator string) (map[string][]string, error) {
+	shardInfo, err := os.Stat(shardPath(folderPath, namespace))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string][]string{}, nil
+		}
+		return nil, fmt.Errorf("unable to inspect the directory of namespace %s: %w", namespace, err)
+	}
+
+	indexInfo, err := os.Stat(indexFilePath(folderPath, namespace))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("unable to inspect the index of namespace %s: %w", namespace, err)
+	}
+
+	if err == nil && !indexInfo.ModTime().Before(shardInfo.ModTime()) {
+		entries, err := filesystem.LoadMetadataFromDisk(indexFilePath(folderPath, namespace))
+		if err == nil {
+			index := make(map[string][]string, len(entries))
+			for name, keys := range entries {
+				index[name] = []string{}
+				if keys != "" {
+					index[name] = strings.Split(keys, ",")
+				}
+			}
+
+			return index, nil
+		}
+	}
+
+	index, err := buildShardIndex(folderPath, namespace, metadataSuffix, separator)
+	if err != nil {
+		return nil, err
+	}
+
+	err = storeShardIndex(folderPath, namespace, index)
+	if err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// buildShardIndex builds the index of the resources of a namespace from the files stored in the directory of the namespace.
+func buildShardIndex(folderPath, namespace, metadataSuffix, separator string) (map[string][]string, error) {
+	files, err := os.ReadDir(shardPath(folderPath, namespace))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the directory of namespace %s: %w", namespace, err)
+	}
+
+	index := map[string][]string{}
+	keys := map[string][]string{}
+	namespacePrefix := namespace + "-"
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasPrefix(file.Name(), namespacePrefix) {
+			continue
+		}
+
+		if strings.HasSuffix(file.Name(), metadataSuffix) {
+			name := strings.TrimPrefix(strings.TrimSuffix(file.Name(), metadataSuffix), namespacePrefix)
+			index[name] = []string{}
+			continue
+		}
+
+		namespacedName, key, found := strings.Cut(file.Name(), separator)
+		if !found {
+			continue
+		}
+
+		name := strings.TrimPrefix(namespacedName, namespacePrefix)
+		keys[name] = append(keys[name], key)
+	}
+
+	for name := range index {
+		if len(keys[name]) != 0 {
+			sort.Strings(keys[name])
+			index[name] = keys[name]
+		}
+	}
+
+	return index, nil
+}
+
+// storeShardIndex writes the index of the resources of a namespace. The index is written to a temporary file
+// that replaces the index file, so that the index is never read while it is partially written.
+func storeShardIndex(folderPath, namespace string, index map[string][]string) error {
+	content := strings.Builder{}
+	for _, name := range storedResourceNames(index) {
+		content.WriteString(fmt.Sprintf("%s=%s\n", name, strings.Join(index[name], ",")))
+	}
+
+	file, err := os.CreateTemp(folderPath, namespace+IndexFileExtension+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create the index of namespace %s: %w", namespace, err)
+	}
+
+	_, err = file.WriteString(content.String())
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(file.Name(), indexFilePath(folderPath, namespace))
+	}
+
+	if err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("unable to store the index of namespace %s: %w", namespace, err)
+	}
+
+	return nil
+}
+
+// storedDataKeys returns the keys of the data of a resource in alphabetical order, as recorded in the index.
+func storedDataKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// removeStaleDataFiles removes the data files of a resource whose keys are recorded in the index but are not part
+// of the new data of the resource. It ensures that keys removed from a resource during an update are not returned anymore.
+func removeStaleDataFiles(shard, filePrefix string, indexedKeys []string, data map[string]string) error {
+	for _, key := range indexedKeys {
+		if _, found := data[key]; found {
+			continue
+		}
+
+		err := os.Remove(path.Join(shard, filePrefix+key))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove data file %s: %w", filePrefix+key, err)
+		}
+	}
+
+	return nil
+}
+
+// removeDataFiles removes the data files of a resource, using the keys recorded in the index.
+func removeDataFiles(shard, filePrefix string, indexedKeys []string) error {
+	return removeStaleDataFiles(shard, filePrefix, indexedKeys, nil)
+}
+
+// storedResourceNames returns the names of the resources recorded in an index in alphabetical order.
+func storedResourceNames(index map[string][]string) []string {
+	names := make([]string, 0, len(index))
+	for name := range index {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
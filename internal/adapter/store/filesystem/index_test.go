@@ -0,0 +1,117 @@
+package filesystem
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestShardedConfigMapStore(t *testing.T) {
+	dataPath := t.TempDir()
+	store, err := NewFileSystemStore(zap.NewNop().Sugar(), FileSystemStoreOptions{DataPath: dataPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configMaps := []*corev1.ConfigMap{
+		{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "a"}, Data: map[string]string{"mode": "dev", "level": "debug"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "a-b"}, Data: map[string]string{"key": "value"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b-c", Namespace: "a"}, Data: map[string]string{"key": "other"}},
+	}
+	for _, configMap := range configMaps {
+		if err := store.StoreConfigMap(configMap); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	list, err := store.GetConfigMaps("a")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(list.Items) != 2 {
+		t.Errorf("expected 2 configmaps in namespace a, got %d", len(list.Items))
+	}
+
+	list, err = store.GetConfigMaps("")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(list.Items) != 3 {
+		t.Errorf("expected 3 configmaps across all namespaces, got %d", len(list.Items))
+	}
+
+	configMap, err := store.GetConfigMap("c", "a-b")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if configMap.Data["key"] != "value" {
+		t.Errorf("expected the configmap of namespace a-b to be returned, got %v", configMap.Data)
+	}
+
+	err = store.StoreConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "a"}, Data: map[string]string{"mode": "prod"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The index is rebuilt from the directory of the namespace when it is missing
+	if err := os.Remove(indexFilePath(store.configMapPath, "a")); err != nil {
+		t.Fatal(err)
+	}
+
+	configMap, err = store.GetConfigMap("settings", "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(configMap.Data) != 1 || configMap.Data["mode"] != "prod" {
+		t.Errorf("expected the removed keys not to be returned, got %v", configMap.Data)
+	}
+
+	if err := store.DeleteConfigMap("settings", "a"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	files, err := os.ReadDir(shardPath(store.configMapPath, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("expected only the files of the configmap b-c to remain, got %d files", len(files))
+	}
+}
+
+func TestBuildShardIndex(t *testing.T) {
+	folderPath := t.TempDir()
+	shard := shardPath(folderPath, "default")
+	if err := os.MkdirAll(shard, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range []string{
+		"default-web-k2dsec.metadata",
+		"default-web-k2dsec-tls.key",
+		"default-web-k2dsec-tls.crt",
+		"default-empty-k2dsec.metadata",
+		"default-orphan-k2dsec-key",
+	} {
+		if err := os.WriteFile(path.Join(shard, file), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	index, err := buildShardIndex(folderPath, "default", secretMetadataSuffix, SecretSeparator)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(index) != 2 || len(index["empty"]) != 0 {
+		t.Errorf("expected the secrets web and empty to be indexed, got %v", index)
+	}
+
+	if keys := index["web"]; len(keys) != 2 || keys[0] != "tls.crt" || keys[1] != "tls.key" {
+		t.Errorf("expected the keys of the secret web to be indexed, got %v", keys)
+	}
+}
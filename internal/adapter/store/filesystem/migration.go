@@ -0,0 +1,232 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/portainer/k2d/internal/adapter/types"
+	"github.com/portainer/k2d/pkg/filesystem"
+)
+
+// MigrateMetadataNamespaces records the namespace of the ConfigMaps and Secrets stored by previous versions of k2d
+// in their metadata file. The metadata files that do not record a namespace are not returned when listing
+// the resources of a namespace (see checkMetadataNamespace).
+// The namespace is deduced from the name of the metadata file ([namespace]-[name]-k2dcm.metadata) using the existing
+// namespaces. The files whose name matches none or several of the namespaces are left untouched.
+//
+// Parameters:
+//   - dataPath: The data path of k2d, containing the directories of the store.
+//   - namespaces: The names of the existing namespaces.
+//
+// Returns:
+//   - The names of the metadata files that could not be migrated.
+//   - An error if the directories of the store or a metadata file cannot be read or written.
+func MigrateMetadataNamespaces(dataPath string, namespaces []string) ([]string, error) {
+	folders := map[string]string{
+		ConfigMapFolder: configMapMetadataSuffix,
+		SecretFolder:    secretMetadataSuffix,
+	}
+
+	unmigrated := []string{}
+
+	for folder, metadataSuffix := range folders {
+		folderPath := path.Join(dataPath, folder)
+
+		files, err := os.ReadDir(folderPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("unable to read directory %s: %w", folder, err)
+		}
+
+		for _, file := range files {
+			if file.IsDir() || !strings.HasSuffix(file.Name(), metadataSuffix) {
+				continue
+			}
+
+			metadata, err := filesystem.LoadMetadataFromDisk(path.Join(folderPath, file.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("unable to load metadata file %s: %w", file.Name(), err)
+			}
+
+			if _, found := metadata[types.NamespaceNameLabelKey]; found {
+				continue
+			}
+
+			namespace, found := findMetadataNamespace(strings.TrimSuffix(file.Name(), metadataSuffix), namespaces)
+			if !found {
+				unmigrated = append(unmigrated, path.Join(folder, file.Name()))
+				continue
+			}
+
+			metadata[types.NamespaceNameLabelKey] = namespace
+			err = filesystem.StoreMetadataOnDisk(folderPath, file.Name(), metadata)
+			if err != nil {
+				return nil, fmt.Errorf("unable to store metadata file %s: %w", file.Name(), err)
+			}
+		}
+	}
+
+	return unmigrated, nil
+}
+
+// findMetadataNamespace returns the only namespace that prefixes the namespaced name of a resource ([namespace]-[name]).
+func findMetadataNamespace(namespacedName string, namespaces []string) (string, bool) {
+	match := ""
+	matches := 0
+
+	for _, namespace := range namespaces {
+		if strings.HasPrefix(namespacedName, namespace+"-") && len(namespacedName) > len(namespace)+1 {
+			match = namespace
+			matches++
+		}
+	}
+
+	if matches != 1 {
+		return "", false
+	}
+
+	return match, true
+}
+
+// MigrateShards moves the ConfigMaps and Secrets stored by previous versions of k2d in the flat directories of the store
+// to the directory of their namespace (see index.go). The namespace is read from the metadata file of each resource
+// (see MigrateMetadataNamespaces). The files are hard linked, or copied when they cannot be linked, and the original
+// files are kept so that the containers created by previous versions of k2d keep mounting up to date data files.
+// The original files are removed with the resource (see removeLegacyFiles).
+//
+// Parameters:
+//   - dataPath: The data path of k2d, containing the directories of the store.
+//
+// Returns:
+//   - The names of the metadata files that could not be migrated because they do not record a namespace.
+//   - An error if the directories of the store cannot be read or a file cannot be migrated.
+func MigrateShards(dataPath string) ([]string, error) {
+	folders := map[string][2]string{
+		ConfigMapFolder: {configMapMetadataSuffix, ConfigMapSeparator},
+		SecretFolder:    {secretMetadataSuffix, SecretSeparator},
+	}
+
+	unmigrated := []string{}
+
+	for folder, naming := range folders {
+		metadataSuffix, separator := naming[0], naming[1]
+		folderPath := path.Join(dataPath, folder)
+
+		files, err := os.ReadDir(folderPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("unable to read directory %s: %w", folder, err)
+		}
+
+		metadataFiles := []string{}
+		dataFiles := map[string][]string{}
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+
+			if strings.HasSuffix(file.Name(), metadataSuffix) {
+				metadataFiles = append(metadataFiles, file.Name())
+			} else if namespacedName, _, found := strings.Cut(file.Name(), separator); found {
+				dataFiles[namespacedName] = append(dataFiles[namespacedName], file.Name())
+			}
+		}
+
+		for _, metadataFile := range metadataFiles {
+			metadata, err := filesystem.LoadMetadataFromDisk(path.Join(folderPath, metadataFile))
+			if err != nil {
+				return nil, fmt.Errorf("unable to load metadata file %s: %w", metadataFile, err)
+			}
+
+			namespace, found := metadata[types.NamespaceNameLabelKey]
+			if !found || namespace == "" {
+				unmigrated = append(unmigrated, path.Join(folder, metadataFile))
+				continue
+			}
+
+			shard := shardPath(folderPath, namespace)
+			err = filesystem.CreateDir(shard)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create the directory of namespace %s: %w", namespace, err)
+			}
+
+			namespacedName := strings.TrimSuffix(metadataFile, metadataSuffix)
+			for _, file := range append([]string{metadataFile}, dataFiles[namespacedName]...) {
+				err := linkOrCopyFile(path.Join(folderPath, file), path.Join(shard, file))
+				if err != nil {
+					return nil, fmt.Errorf("unable to migrate file %s: %w", path.Join(folder, file), err)
+				}
+			}
+		}
+	}
+
+	return unmigrated, nil
+}
+
+// linkOrCopyFile hard links a file to the destination path, or copies it when it cannot be linked
+// (e.g. on a filesystem that does not support hard links). An existing destination file is left untouched.
+func linkOrCopyFile(sourcePath, destinationPath string) error {
+	exists, err := filesystem.FileExists(destinationPath)
+	if err != nil || exists {
+		return err
+	}
+
+	if os.Link(sourcePath, destinationPath) == nil {
+		return nil
+	}
+
+	content, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(destinationPath, content, 0644)
+}
+
+// removeLegacyFiles removes the files of a resource kept in the flat directory of the store by MigrateShards.
+// The files are only removed when the legacy metadata file belongs to the namespace of the resource,
+// as two resources of different namespaces could share the same files in the flat directory (see checkMetadataNamespace).
+func removeLegacyFiles(folderPath, metadataFileName, filePrefix, namespace string) error {
+	metadataFilePath := path.Join(folderPath, metadataFileName)
+
+	metadataFileExists, err := filesystem.FileExists(metadataFilePath)
+	if err != nil || !metadataFileExists {
+		return err
+	}
+
+	metadata, err := filesystem.LoadMetadataFromDisk(metadataFilePath)
+	if err != nil {
+		return err
+	}
+
+	if metadata[types.NamespaceNameLabelKey] != namespace {
+		return nil
+	}
+
+	err = os.Remove(metadataFilePath)
+	if err != nil {
+		return err
+	}
+
+	files, err := os.ReadDir(folderPath)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if !file.IsDir() && strings.HasPrefix(file.Name(), filePrefix) {
+			err := os.Remove(path.Join(folderPath, file.Name()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package filesystem
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/portainer/k2d/internal/adapter/types"
+	"github.com/portainer/k2d/pkg/filesystem"
+)
+
+func TestFindMetadataNamespace(t *testing.T) {
+	namespaces := []string{"default", "a", "a-b"}
+
+	tests := []struct {
+		namespacedName string
+		namespace      string
+		found          bool
+	}{
+		{namespacedName: "default-settings", namespace: "default", found: true},
+		{namespacedName: "a-config", namespace: "a", found: true},
+		{namespacedName: "a-b-c", found: false},
+		{namespacedName: "other-config", found: false},
+		{namespacedName: "default-", found: false},
+	}
+
+	for _, test := range tests {
+		namespace, found := findMetadataNamespace(test.namespacedName, namespaces)
+		if namespace != test.namespace || found != test.found {
+			t.Errorf("%s: expected (%q, %t), got (%q, %t)", test.namespacedName, test.namespace, test.found, namespace, found)
+		}
+	}
+}
+
+func TestMigrateMetadataNamespaces(t *testing.T) {
+	dataPath := t.TempDir()
+	configMapPath := path.Join(dataPath, ConfigMapFolder)
+	if err := os.MkdirAll(configMapPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]map[string]string{
+		"default-settings-k2dcm.metadata": {},
+		"a-b-c-k2dcm.metadata":            {},
+		"a-config-k2dcm.metadata":         {types.NamespaceNameLabelKey: "a"},
+	}
+	for name, metadata := range files {
+		if err := filesystem.StoreMetadataOnDisk(configMapPath, name, metadata); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	unmigrated, err := MigrateMetadataNamespaces(dataPath, []string{"default", "a", "a-b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(unmigrated) != 1 || unmigrated[0] != path.Join(ConfigMapFolder, "a-b-c-k2dcm.metadata") {
+		t.Errorf("expected the ambiguous metadata file to be reported, got %v", unmigrated)
+	}
+
+	metadata, err := filesystem.LoadMetadataFromDisk(path.Join(configMapPath, "default-settings-k2dcm.metadata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata[types.NamespaceNameLabelKey] != "default" {
+		t.Errorf("expected the namespace to be recorded, got %v", metadata)
+	}
+}
+
+func TestMigrateShards(t *testing.T) {
+	dataPath := t.TempDir()
+	secretPath := path.Join(dataPath, SecretFolder)
+	if err := os.MkdirAll(secretPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]map[string]string{
+		"default-web-k2dsec.metadata":   {types.NamespaceNameLabelKey: "default"},
+		"legacy-config-k2dsec.metadata": {},
+	}
+	for name, metadata := range files {
+		if err := filesystem.StoreMetadataOnDisk(secretPath, name, metadata); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(path.Join(secretPath, "default-web-k2dsec-password"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	unmigrated, err := MigrateShards(dataPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(unmigrated) != 1 || unmigrated[0] != path.Join(SecretFolder, "legacy-config-k2dsec.metadata") {
+		t.Errorf("expected the metadata file without namespace to be reported, got %v", unmigrated)
+	}
+
+	content, err := os.ReadFile(path.Join(secretPath, "default", "default-web-k2dsec-password"))
+	if err != nil || string(content) != "secret" {
+		t.Errorf("expected the data file to be migrated, got %q (%v)", content, err)
+	}
+
+	if _, err := os.Stat(path.Join(secretPath, "default-web-k2dsec-password")); err != nil {
+		t.Errorf("expected the legacy data file to be kept for the existing containers: %v", err)
+	}
+}
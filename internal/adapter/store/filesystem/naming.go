@@ -14,7 +14,7 @@ func buildConfigMapFilePrefix(configMapName, namespace string) string {
 // Each configmap has its own metadata file that follows the naming convention below:
 // [namespace]-[configmap-name]-k2dcm.metadata
 func buildConfigMapMetadataFileName(configMapName, namespace string) string {
-	return fmt.Sprintf("%s-%s-k2dcm.metadata", namespace, configMapName)
+	return fmt.Sprintf("%s-%s%s", namespace, configMapName, configMapMetadataSuffix)
 }
 
 // Each key of a secret is stored in a separate file using the following naming convention:
@@ -26,17 +26,7 @@ func buildSecretFilePrefix(secretName, namespace string) string {
 // Each secret has its own metadata file that follows the naming convention below:
 // [namespace]-[secret-name]-k2dsec.metadata
 func buildSecretMetadataFileName(secretName, namespace string) string {
-	return fmt.Sprintf("%s-%s-k2dsec.metadata", namespace, secretName)
-}
-
-// Returns [configmap-name] from [namespace]-[configmap-name]
-func getConfigMapNameFromNamespacedConfigMapName(namespacedConfigMapName, namespace string) string {
-	return strings.TrimPrefix(namespacedConfigMapName, namespace+"-")
-}
-
-// Returns [namespace]-[configmap-name] from [namespace]-[configmap-name]-k2dcm.metadata
-func getNamespacedConfigMapNameFromMetadataFileName(fileName string) string {
-	return strings.TrimSuffix(fileName, "-k2dcm.metadata")
+	return fmt.Sprintf("%s-%s%s", namespace, secretName, secretMetadataSuffix)
 }
 
 // Returns [namespace]-[configmap-name] and [key] from [namespace]-[configmap-name]-k2dcm-[key]
@@ -62,13 +52,3 @@ func getNamespacedSecretNameAndKeyFromFileName(fileName string) (string, string,
 
 	return split[0], split[1], nil
 }
-
-// Returns [namespace]-[secret-name] from [namespace]-[secret-name]-k2dsec.metadata
-func getNamespacedSecretNameFromMetadataFileName(fileName string) string {
-	return strings.TrimSuffix(fileName, "-k2dsec.metadata")
-}
-
-// Returns [secret-name] from [namespace]-[secret-name]
-func getSecretNameFromNamespacedSecretName(namespacedSecretName, namespace string) string {
-	return strings.TrimPrefix(namespacedSecretName, namespace+"-")
-}
@@ -20,10 +20,11 @@ import (
 // DeleteSecret removes a secret identified by its name and namespace.
 // The function performs the following tasks:
 // 1. Locks the mutex to ensure thread-safety.
-// 2. Reads the directory where secrets are stored.
-// 3. Verifies if the secret file with the specified prefix exists.
-// 4. If found, deletes the metadata file associated with the secret.
-// 5. Iterates over all secret data files and removes them.
+// 2. Loads the metadata file of the secret from the directory of its namespace.
+// 3. If found and if it belongs to the namespace, deletes the metadata file associated with the secret.
+// 4. Removes the data files of the secret, using the keys recorded in the index of the namespace.
+// 5. Removes the secret from the index of the namespace.
+// 6. Removes the files kept in the flat directory of the store for a secret created by a previous version of k2d.
 //
 // Parameters:
 //   - secretName: The name of the secret to be deleted.
@@ -36,8 +37,9 @@ func (s *FileSystemStore) DeleteSecret(secretName, namespace string) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	shard := shardPath(s.secretPath, namespace)
 	metadataFileName := buildSecretMetadataFileName(secretName, namespace)
-	metadataFilePath := path.Join(s.secretPath, metadataFileName)
+	metadataFilePath := path.Join(shard, metadataFileName)
 
 	metadataFileExists, err := filesystem.FileExists(metadataFilePath)
 	if err != nil {
@@ -48,24 +50,40 @@ func (s *FileSystemStore) DeleteSecret(secretName, namespace string) error {
 		return errors.ErrResourceNotFound
 	}
 
+	metadata, err := filesystem.LoadMetadataFromDisk(metadataFilePath)
+	if err != nil {
+		return fmt.Errorf("unable to load secret metadata from disk: %w", err)
+	}
+
+	if checkMetadataNamespace(metadata, "secret", secretName, namespace) != nil {
+		return errors.ErrResourceNotFound
+	}
+
+	index, err := loadShardIndex(s.secretPath, namespace, secretMetadataSuffix, SecretSeparator)
+	if err != nil {
+		return fmt.Errorf("unable to load secret index: %w", err)
+	}
+
 	err = os.Remove(metadataFilePath)
 	if err != nil {
 		return fmt.Errorf("unable to remove secret metadata file %s: %w", metadataFileName, err)
 	}
 
-	files, err := os.ReadDir(s.secretPath)
+	filePrefix := buildSecretFilePrefix(secretName, namespace)
+	err = removeDataFiles(shard, filePrefix, index[secretName])
 	if err != nil {
-		return fmt.Errorf("unable to read secret directory: %w", err)
+		return fmt.Errorf("unable to remove secret data files: %w", err)
 	}
 
-	filePrefix := buildSecretFilePrefix(secretName, namespace)
-	for _, file := range files {
-		if strings.HasPrefix(file.Name(), filePrefix) {
-			err := os.Remove(path.Join(s.secretPath, file.Name()))
-			if err != nil {
-				return fmt.Errorf("unable to remove secret data file %s: %w", file.Name(), err)
-			}
-		}
+	delete(index, secretName)
+	err = storeShardIndex(s.secretPath, namespace, index)
+	if err != nil {
+		return err
+	}
+
+	err = removeLegacyFiles(s.secretPath, metadataFileName, filePrefix, namespace)
+	if err != nil {
+		return fmt.Errorf("unable to remove legacy secret files: %w", err)
 	}
 
 	return nil
@@ -92,11 +110,10 @@ func (s *FileSystemStore) GetSecretBinds(secret *core.Secret) (map[string]string
 // using a mutex to ensure thread-safety during the read operation.
 //
 // The function performs the following steps:
-// 1. Reads all the files in the secret directory.
-// 2. Searches for a specific secret file by its prefix which is a combination of its name and namespace.
-// 3. Loads the metadata associated with the secret from the disk.
-// 4. Creates a Secret object based on the loaded metadata.
-// 5. Updates the Secret object with data loaded from the secret data file(s).
+// 1. Loads the metadata associated with the secret from the directory of its namespace. The secret is reported
+// as not found when the metadata belongs to a secret of another namespace (see checkMetadataNamespace).
+// 2. Creates a Secret object based on the loaded metadata.
+// 3. Updates the Secret object with data loaded from the data file(s) of the keys recorded in the index of the namespace.
 //
 // Parameters:
 // - secretName: The name of the secret to retrieve.
@@ -109,8 +126,9 @@ func (s *FileSystemStore) GetSecret(secretName, namespace string) (*core.Secret,
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
+	shard := shardPath(s.secretPath, namespace)
 	metadataFileName := buildSecretMetadataFileName(secretName, namespace)
-	metadataFilePath := path.Join(s.secretPath, metadataFileName)
+	metadataFilePath := path.Join(shard, metadataFileName)
 
 	metadataFileExists, err := filesystem.FileExists(metadataFilePath)
 	if err != nil {
@@ -126,24 +144,25 @@ func (s *FileSystemStore) GetSecret(secretName, namespace string) (*core.Secret,
 		return nil, fmt.Errorf("unable to load secret metadata from disk: %w", err)
 	}
 
+	if checkMetadataNamespace(metadata, "secret", secretName, namespace) != nil {
+		return nil, errors.ErrResourceNotFound
+	}
+
 	secret, err := createSecretFromMetadata(secretName, namespace, metadata)
 	if err != nil {
 		return nil, fmt.Errorf("unable to build secret from metadata: %w", err)
 	}
 
-	files, err := os.ReadDir(s.secretPath)
+	index, err := loadShardIndex(s.secretPath, namespace, secretMetadataSuffix, SecretSeparator)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read secret directory: %w", err)
+		return nil, fmt.Errorf("unable to load secret index: %w", err)
 	}
 
 	filePrefix := buildSecretFilePrefix(secretName, namespace)
-	for _, file := range files {
-		if strings.HasPrefix(file.Name(), filePrefix) {
-
-			err := s.updateSecretDataFromFile(&secret, file.Name())
-			if err != nil {
-				return nil, fmt.Errorf("unable to update secret data from file %s: %w", file.Name(), err)
-			}
+	for _, key := range index[secretName] {
+		err := s.updateSecretDataFromFile(&secret, shard, filePrefix+key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to update secret data from file %s: %w", filePrefix+key, err)
 		}
 	}
 
@@ -155,11 +174,10 @@ func (s *FileSystemStore) GetSecret(secretName, namespace string) (*core.Secret,
 // with a mutex to ensure thread-safety during read operations.
 //
 // The function performs the following steps:
-// 1. Reads all the files in the secret directory.
-// 2. Segregates the files into metadata files and data files.
-// 3. Builds a list of Secret objects based on the metadata files.
-// 4. Filters the Secret objects based on the namespace and selector.
-// 5. Updates the Secret objects with data loaded from the secret data files.
+// 1. Lists the namespaces to read: the given namespace, or all the namespaces stored on disk when it is empty.
+// 2. Builds the Secret objects of each namespace matching the selector, based on the index of the namespace
+// (see buildNamespaceSecrets).
+// 3. Returns a SecretList containing all the constructed secrets.
 //
 // Parameters:
 // - namespace: The namespace where the secrets are located.
@@ -172,16 +190,23 @@ func (s *FileSystemStore) GetSecrets(namespace string, selector labels.Selector)
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	files, err := os.ReadDir(s.secretPath)
-	if err != nil {
-		return core.SecretList{}, fmt.Errorf("unable to read secret directory: %w", err)
+	namespaces := []string{namespace}
+	if namespace == "" {
+		var err error
+		namespaces, err = listShardNamespaces(s.secretPath)
+		if err != nil {
+			return core.SecretList{}, fmt.Errorf("unable to read secret directory: %w", err)
+		}
 	}
 
-	metadataFiles, dataFiles := s.isolateSecretMetadataAndDataFiles(files)
+	secrets := []core.Secret{}
+	for _, namespace := range namespaces {
+		namespaceSecrets, err := s.buildNamespaceSecrets(namespace, selector)
+		if err != nil {
+			return core.SecretList{}, fmt.Errorf("unable to build secrets: %w", err)
+		}
 
-	secrets, err := s.buildSecrets(metadataFiles, dataFiles, namespace, selector)
-	if err != nil {
-		return core.SecretList{}, fmt.Errorf("unable to build secrets: %w", err)
+		secrets = append(secrets, namespaceSecrets...)
 	}
 
 	return core.SecretList{
@@ -197,30 +222,74 @@ func (s *FileSystemStore) GetSecrets(namespace string, selector labels.Selector)
 // The function performs the following tasks:
 //  1. Locks the mutex to ensure thread-safety.
 //  2. Prepares the labels for the secret, merging any existing labels.
-//  3. Stores the metadata of the secret in the disk.
+//     The type of the secret is stored alongside the labels and the creation timestamp
+//     of an existing secret is preserved.
+//  3. Stores the metadata of the secret in the directory of its namespace.
 //  4. Iterates over the 'Data' and 'StringData' fields of the secret,
 //     preparing the data to be stored.
-//  5. Stores the prepared data on the disk.
+//  5. Removes the data files of an existing secret that are not part of the new data.
+//  6. Stores the prepared data on the disk and records its keys in the index of the namespace.
 //
 // Parameters:
 //   - secret: A pointer to the corev1.Secret object containing the secret data
 //     to be stored.
 //
 // Returns:
-//   - error: Returns an error if any step of the storage process fails,
-//     otherwise returns nil.
+//   - error: Returns an ErrResourceConflict error if the files of the secret are already used by a secret
+//     of another namespace, an error if any step of the storage process fails, otherwise returns nil.
 func (s *FileSystemStore) StoreSecret(secret *corev1.Secret) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	secretType := secret.Type
+	if secretType == "" {
+		secretType = corev1.SecretTypeOpaque
+	}
+
 	labels := map[string]string{
 		types.NamespaceNameLabelKey: secret.Namespace,
 		CreationTimestampLabelKey:   time.Now().UTC().Format(time.RFC3339),
+		SecretTypeLabelKey:          string(secretType),
+	}
+
+	shard := shardPath(s.secretPath, secret.Namespace)
+	err := filesystem.CreateDir(shard)
+	if err != nil {
+		return fmt.Errorf("unable to create secret directory of namespace %s: %w", secret.Namespace, err)
 	}
-	maputils.MergeMapsInPlace(labels, secret.Labels)
 
 	metadataFileName := buildSecretMetadataFileName(secret.Name, secret.Namespace)
-	err := filesystem.StoreMetadataOnDisk(s.secretPath, metadataFileName, labels)
+	metadataFilePath := path.Join(shard, metadataFileName)
+
+	metadataFileExists, err := filesystem.FileExists(metadataFilePath)
+	if err != nil {
+		return fmt.Errorf("unable to check if secret metadata file %s exists: %w", metadataFileName, err)
+	}
+
+	if metadataFileExists {
+		existingMetadata, err := filesystem.LoadMetadataFromDisk(metadataFilePath)
+		if err != nil {
+			return fmt.Errorf("unable to load secret metadata from disk: %w", err)
+		}
+
+		err = checkMetadataNamespace(existingMetadata, "secret", secret.Name, secret.Namespace)
+		if err != nil {
+			return err
+		}
+
+		if creationTimestamp, ok := existingMetadata[CreationTimestampLabelKey]; ok {
+			labels[CreationTimestampLabelKey] = creationTimestamp
+		}
+	}
+
+	maputils.MergeMapsInPlace(labels, secret.Labels)
+
+	index, err := loadShardIndex(s.secretPath, secret.Namespace, secretMetadataSuffix, SecretSeparator)
+	if err != nil {
+		return fmt.Errorf("unable to load secret index: %w", err)
+	}
+
+	err = filesystem.StoreMetadataOnDisk(shard, metadataFileName, labels)
 	if err != nil {
 		return fmt.Errorf("unable to store secret metadata on disk: %w", err)
 	}
@@ -236,102 +305,58 @@ func (s *FileSystemStore) StoreSecret(secret *corev1.Secret) error {
 	}
 
 	filePrefix := buildSecretFilePrefix(secret.Name, secret.Namespace)
-	err = filesystem.StoreDataMapOnDisk(s.secretPath, filePrefix, data)
+
+	err = removeStaleDataFiles(shard, filePrefix, index[secret.Name], data)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to remove stale secret data files: %w", err)
 	}
 
-	return nil
-}
-
-// isolateSecretMetadataAndDataFiles segregates the given directory entries into
-// secret metadata files and data files based on their file name suffixes and prefixes.
-func (s *FileSystemStore) isolateSecretMetadataAndDataFiles(files []os.DirEntry) ([]string, map[string][]string) {
-	metadataFiles := []string{}
-	dataFiles := map[string][]string{}
-
-	for _, file := range files {
-		// Skip non secret files
-		if !strings.Contains(file.Name(), SecretSeparator) && !strings.Contains(file.Name(), "k2dsec.metadata") {
-			continue
-		}
-
-		if strings.HasSuffix(file.Name(), ".metadata") {
-			metadataFiles = append(metadataFiles, file.Name())
-		} else {
-			namespacedSecretName, _, err := getNamespacedSecretNameAndKeyFromFileName(file.Name())
-			if err != nil {
-				s.logger.Warnf("unable to get namespaced secret name from file name %s: %s", file.Name(), err.Error())
-				continue
-			}
-
-			dataFiles[namespacedSecretName] = append(dataFiles[namespacedSecretName], file.Name())
-		}
+	err = filesystem.StoreDataMapOnDisk(shard, filePrefix, data)
+	if err != nil {
+		return err
 	}
 
-	return metadataFiles, dataFiles
+	index[secret.Name] = storedDataKeys(data)
+	return storeShardIndex(s.secretPath, secret.Namespace, index)
 }
 
-// buildSecrets constructs a list of Secret objects based on the given metadata
-// and data files, namespace, and selector. It also updates the Secret objects
-// with data loaded from the data files.
-func (s *FileSystemStore) buildSecrets(metadataFiles []string, dataFiles map[string][]string, namespace string, selector labels.Selector) ([]core.Secret, error) {
-	// Load metadata from disk and build initial secrets
-	secrets, err := s.loadMetadataAndInitSecrets(metadataFiles, namespace, selector)
+// buildNamespaceSecrets constructs the Secret objects of a namespace matching the selector, based on the index
+// of the namespace. The metadata and the data of each secret are loaded from the directory of the namespace.
+func (s *FileSystemStore) buildNamespaceSecrets(namespace string, selector labels.Selector) ([]core.Secret, error) {
+	index, err := loadShardIndex(s.secretPath, namespace, secretMetadataSuffix, SecretSeparator)
 	if err != nil {
 		return nil, err
 	}
 
-	// Populate secrets with data
-	for namespacedSecretName, dataFiles := range dataFiles {
-		for _, dataFile := range dataFiles {
-			if secret, found := secrets[namespacedSecretName]; found {
-				s.updateSecretDataFromFile(&secret, dataFile)
-				secrets[namespacedSecretName] = secret
-			}
-		}
-	}
-
-	// Convert map values to slice
-	secretsSlice := make([]core.Secret, 0, len(secrets))
-	for _, secret := range secrets {
-		secretsSlice = append(secretsSlice, secret)
-	}
-
-	return secretsSlice, nil
-}
-
-// loadMetadataAndInitSecrets loads secret metadata from disk and initializes a map
-// of Secret objects based on the loaded metadata, namespace, and selector.
-func (s *FileSystemStore) loadMetadataAndInitSecrets(metadataFiles []string, namespace string, selector labels.Selector) (map[string]core.Secret, error) {
-	secrets := map[string]core.Secret{}
+	shard := shardPath(s.secretPath, namespace)
+	secrets := make([]core.Secret, 0, len(index))
 
-	for _, metadataFile := range metadataFiles {
-		metadataFilePath := path.Join(s.secretPath, metadataFile)
-		metadata, err := filesystem.LoadMetadataFromDisk(metadataFilePath)
+	for _, secretName := range storedResourceNames(index) {
+		metadata, err := filesystem.LoadMetadataFromDisk(path.Join(shard, buildSecretMetadataFileName(secretName, namespace)))
 		if err != nil {
-			return secrets, fmt.Errorf("unable to load secret metadata from disk: %w", err)
-		}
-
-		if !selector.Matches(labels.Set(metadata)) {
+			s.logger.Warnf("unable to load secret metadata from disk: %s", err.Error())
 			continue
 		}
 
-		namespaceName := metadata[types.NamespaceNameLabelKey]
-		if namespace != "" && namespace != namespaceName {
+		if !selector.Matches(labels.Set(metadata)) {
 			continue
 		}
 
-		namespacedSecretName := getNamespacedSecretNameFromMetadataFileName(metadataFile)
-		secretName := getSecretNameFromNamespacedSecretName(namespacedSecretName, namespaceName)
-
-		secret, err := createSecretFromMetadata(secretName, namespaceName, metadata)
+		secret, err := createSecretFromMetadata(secretName, namespace, metadata)
 		if err != nil {
 			s.logger.Warnf("unable to build secret from metadata: %s", err.Error())
 			continue
 		}
 
-		secrets[namespacedSecretName] = secret
+		filePrefix := buildSecretFilePrefix(secretName, namespace)
+		for _, key := range index[secretName] {
+			err := s.updateSecretDataFromFile(&secret, shard, filePrefix+key)
+			if err != nil {
+				s.logger.Warnf("unable to update secret data from file: %s", err.Error())
+			}
+		}
+
+		secrets = append(secrets, secret)
 	}
 
 	return secrets, nil
@@ -355,6 +380,10 @@ func createSecretFromMetadata(secretName, namespace string, metadata map[string]
 		Type: core.SecretTypeOpaque,
 	}
 
+	if secretType, ok := metadata[SecretTypeLabelKey]; ok && secretType != "" {
+		secret.Type = core.SecretType(secretType)
+	}
+
 	creationTimestamp, ok := metadata[CreationTimestampLabelKey]
 	if ok {
 		parsedTime, err := time.Parse(time.RFC3339, creationTimestamp)
@@ -368,9 +397,9 @@ func createSecretFromMetadata(secretName, namespace string, metadata map[string]
 }
 
 // updateSecretDataFromFile updates a Secret object with data loaded from a given
-// data file.
-func (s *FileSystemStore) updateSecretDataFromFile(secret *core.Secret, dataFile string) error {
-	dataFilePath := path.Join(s.secretPath, dataFile)
+// data file of the directory of its namespace.
+func (s *FileSystemStore) updateSecretDataFromFile(secret *core.Secret, shard, dataFile string) error {
+	dataFilePath := path.Join(shard, dataFile)
 
 	data, err := os.ReadFile(dataFilePath)
 	if err != nil {
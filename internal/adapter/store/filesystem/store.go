@@ -5,6 +5,8 @@ import (
 	"path"
 	"sync"
 
+	"github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/types"
 	"github.com/portainer/k2d/pkg/filesystem"
 	"go.uber.org/zap"
 )
@@ -24,6 +26,10 @@ const (
 )
 
 const (
+	// BinaryDataKeysLabelKey is the key used to store the comma separated list of the binaryData keys of a ConfigMap resource
+	// in the associated metadata file. The binary data is stored as raw bytes, like the other keys of the ConfigMap.
+	BinaryDataKeysLabelKey = "store.k2d.io/filesystem/binary-data-keys"
+
 	// CreationTimestampLabelKey is the key used to store the creation timestamp of a Configmap or Secret resource
 	// in the associated metadata file
 	CreationTimestampLabelKey = "store.k2d.io/filesystem/creation-timestamp"
@@ -31,6 +37,10 @@ const (
 	// FilePathAnnotationKey is the key used to store the path to a data file for a ConfigMap or Secret resource
 	// It is used to construct binds when mounting these files in containers
 	FilePathAnnotationKey = "store.k2d.io/filesystem/path"
+
+	// SecretTypeLabelKey is the key used to store the type of a Secret resource in the associated metadata file
+	// It is used to restore the type of the Secret such as Opaque, helm.sh/release.v1, etc...
+	SecretTypeLabelKey = "store.k2d.io/filesystem/secret-type"
 )
 
 // FileSystemStore is a structure that represents a file system store.
@@ -81,3 +91,27 @@ func NewFileSystemStore(logger *zap.SugaredLogger, opts FileSystemStoreOptions)
 		logger:        logger,
 	}, nil
 }
+
+// checkMetadataNamespace ensures that the metadata file of a ConfigMap or Secret belongs to the specified namespace.
+// As the namespace and the name of a resource are joined with a dash to build the file names, two resources of different
+// namespaces can share the same files (e.g. the configmap b-c in the namespace a and the configmap c in the namespace a-b).
+// The namespace recorded in the metadata file is used to make sure that a resource is never read, updated or deleted
+// from another namespace. Metadata files that do not record a namespace are accepted for backward compatibility.
+//
+// Parameters:
+//   - metadata: The metadata loaded from the metadata file.
+//   - kind: The kind of the resource (configmap or secret), used in the error message.
+//   - name: The name of the resource.
+//   - namespace: The namespace of the resource.
+//
+// Returns:
+//   - An ErrResourceConflict error if the metadata file belongs to a resource of another namespace.
+func checkMetadataNamespace(metadata map[string]string, kind, name, namespace string) error {
+	metadataNamespace, found := metadata[types.NamespaceNameLabelKey]
+	if !found || metadataNamespace == namespace {
+		return nil
+	}
+
+	return fmt.Errorf("%w: the %s %s in namespace %s shares its storage with a %s of namespace %s",
+		errors.ErrResourceConflict, kind, name, namespace, kind, metadataNamespace)
+}
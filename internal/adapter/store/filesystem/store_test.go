@@ -0,0 +1,92 @@
+package filesystem
+
+import (
+	"errors"
+	"testing"
+
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/types"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckMetadataNamespace(t *testing.T) {
+	tests := []struct {
+		name      string
+		metadata  map[string]string
+		namespace string
+		conflict  bool
+	}{
+		{name: "same namespace", metadata: map[string]string{types.NamespaceNameLabelKey: "a-b"}, namespace: "a-b"},
+		{name: "namespace not recorded", metadata: map[string]string{}, namespace: "a-b"},
+		{name: "other namespace", metadata: map[string]string{types.NamespaceNameLabelKey: "a"}, namespace: "a-b", conflict: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkMetadataNamespace(test.metadata, "configmap", "c", test.namespace)
+			if test.conflict != errors.Is(err, adaptererr.ErrResourceConflict) {
+				t.Errorf("expected a conflict: %t, got %v", test.conflict, err)
+			}
+		})
+	}
+}
+
+func TestFileSystemStoreNamespaceCollision(t *testing.T) {
+	store, err := NewFileSystemStore(zap.NewNop().Sugar(), FileSystemStoreOptions{DataPath: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		kind   string
+		store  func(name, namespace string) error
+		get    func(name, namespace string) error
+		delete func(name, namespace string) error
+	}{
+		{
+			kind: "configmap",
+			store: func(name, namespace string) error {
+				return store.StoreConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}, Data: map[string]string{"key": "value"}})
+			},
+			get: func(name, namespace string) error {
+				_, err := store.GetConfigMap(name, namespace)
+				return err
+			},
+			delete: store.DeleteConfigMap,
+		},
+		{
+			kind: "secret",
+			store: func(name, namespace string) error {
+				return store.StoreSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}, Data: map[string][]byte{"key": []byte("value")}})
+			},
+			get: func(name, namespace string) error {
+				_, err := store.GetSecret(name, namespace)
+				return err
+			},
+			delete: store.DeleteSecret,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.kind, func(t *testing.T) {
+			// b-c in the namespace a and c in the namespace a-b share the same name once joined with a dash
+			if err := test.store("b-c", "a"); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if err := test.get("c", "a-b"); !errors.Is(err, adaptererr.ErrResourceNotFound) {
+				t.Errorf("expected the %s not to be found from the other namespace, got %v", test.kind, err)
+			}
+
+			if err := test.delete("c", "a-b"); !errors.Is(err, adaptererr.ErrResourceNotFound) {
+				t.Errorf("expected the %s not to be deleted from the other namespace, got %v", test.kind, err)
+			}
+
+			if err := test.get("b-c", "a"); err != nil {
+				t.Errorf("expected the %s to be kept in its namespace, got %v", test.kind, err)
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package memory
 import (
 	"errors"
 	"fmt"
-	"strings"
 	"sync"
 
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
@@ -14,8 +13,11 @@ import (
 )
 
 type secretData struct {
-	Data map[string][]byte
-	Type string
+	Name      string
+	Namespace string
+	Data      map[string][]byte
+	Labels    map[string]string
+	Type      string
 }
 
 // InMemoryStore is a simple in-memory that can be used
@@ -25,17 +27,16 @@ type InMemoryStore struct {
 	secretMap map[string]secretData
 }
 
+// buildSecretKey builds the key of a secret in the store. A slash is used as a separator as it cannot
+// be part of a namespace or secret name, which prevents secrets of different namespaces from sharing the same key
+// (e.g. secret b-c in namespace a and secret c in namespace a-b).
 func buildSecretKey(secretName, namespace string) string {
-	return fmt.Sprintf("%s-%s", namespace, secretName)
-}
-
-func getSecretNameFromKey(key, namespace string) string {
-	return strings.TrimPrefix(key, fmt.Sprintf("%s-", namespace))
+	return fmt.Sprintf("%s/%s", namespace, secretName)
 }
 
 // NewInMemoryStore creates a new in-memory store
 // Secrets are stored in a map with the key using a specific format:
-// <namespace>-<secretName>
+// <namespace>/<secretName>
 func NewInMemoryStore() *InMemoryStore {
 	return &InMemoryStore{
 		m:         sync.RWMutex{},
@@ -44,10 +45,17 @@ func NewInMemoryStore() *InMemoryStore {
 }
 
 // DeleteSecret deletes a secret from the in-memory store
+// It returns an ErrResourceNotFound error if the secret does not exist, as the other stores.
 func (s *InMemoryStore) DeleteSecret(secretName, namespace string) error {
 	s.m.Lock()
 	defer s.m.Unlock()
-	delete(s.secretMap, buildSecretKey(secretName, namespace))
+
+	key := buildSecretKey(secretName, namespace)
+	if _, found := s.secretMap[key]; !found {
+		return adaptererr.ErrResourceNotFound
+	}
+
+	delete(s.secretMap, key)
 	return nil
 }
 
@@ -73,6 +81,7 @@ func (s *InMemoryStore) GetSecret(secretName, namespace string) (*core.Secret, e
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        secretName,
 			Annotations: map[string]string{},
+			Labels:      data.Labels,
 			Namespace:   namespace,
 		},
 		Data: data.Data,
@@ -81,14 +90,19 @@ func (s *InMemoryStore) GetSecret(secretName, namespace string) (*core.Secret, e
 }
 
 // GetSecrets gets all secrets from the in-memory store
+// Secrets are filtered using the specified namespace and label selector.
 func (s *InMemoryStore) GetSecrets(namespace string, selector labels.Selector) (core.SecretList, error) {
 	s.m.RLock()
 	defer s.m.RUnlock()
 	var secrets []core.Secret
 
-	for key, data := range s.secretMap {
+	for _, data := range s.secretMap {
+
+		if namespace != "" && data.Namespace != namespace {
+			continue
+		}
 
-		if !strings.HasPrefix(key, namespace) {
+		if !selector.Matches(labels.Set(data.Labels)) {
 			continue
 		}
 
@@ -98,9 +112,10 @@ func (s *InMemoryStore) GetSecrets(namespace string, selector labels.Selector) (
 				APIVersion: "v1",
 			},
 			ObjectMeta: metav1.ObjectMeta{
-				Name:        getSecretNameFromKey(key, namespace),
+				Name:        data.Name,
 				Annotations: map[string]string{},
-				Namespace:   namespace,
+				Labels:      data.Labels,
+				Namespace:   data.Namespace,
 			},
 			Data: data.Data,
 			Type: core.SecretType(data.Type),
@@ -119,9 +134,21 @@ func (s *InMemoryStore) StoreSecret(secret *corev1.Secret) error {
 	s.m.Lock()
 	defer s.m.Unlock()
 
+	data := map[string][]byte{}
+	for key, value := range secret.Data {
+		data[key] = value
+	}
+
+	for key, value := range secret.StringData {
+		data[key] = []byte(value)
+	}
+
 	s.secretMap[buildSecretKey(secret.Name, secret.Namespace)] = secretData{
-		Data: secret.Data,
-		Type: string(secret.Type),
+		Name:      secret.Name,
+		Namespace: secret.Namespace,
+		Data:      data,
+		Labels:    secret.Labels,
+		Type:      string(secret.Type),
 	}
 
 	return nil
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInMemoryStoreNamespaceCollision(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		found     bool
+	}{
+		{name: "b-c", namespace: "a", found: true},
+		{name: "c", namespace: "a-b"},
+		{name: "a-b-c", namespace: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.namespace+"/"+test.name, func(t *testing.T) {
+			store := NewInMemoryStore()
+
+			err := store.StoreSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "b-c", Namespace: "a"}, Data: map[string][]byte{"key": []byte("value")}})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			_, err = store.GetSecret(test.name, test.namespace)
+			if test.found != (err == nil) {
+				t.Errorf("expected the secret to be found: %t, got %v", test.found, err)
+			}
+			if !test.found && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+				t.Errorf("expected a not found error, got %v", err)
+			}
+
+			err = store.DeleteSecret(test.name, test.namespace)
+			if test.found != (err == nil) {
+				t.Errorf("expected the secret to be deleted: %t, got %v", test.found, err)
+			}
+			if !test.found && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+				t.Errorf("expected a not found error, got %v", err)
+			}
+
+			_, err = store.GetSecret("b-c", "a")
+			if test.found == (err == nil) {
+				t.Errorf("unexpected state of the secret of namespace a after the deletion: %v", err)
+			}
+		})
+	}
+}
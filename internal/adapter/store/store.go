@@ -36,6 +36,7 @@ package store
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/portainer/k2d/internal/adapter/store/filesystem"
 	"github.com/portainer/k2d/internal/adapter/store/memory"
@@ -71,13 +72,17 @@ type StoreOptions struct {
 	Backend         string
 	RegistryBackend string
 	Logger          *zap.SugaredLogger
-	Filesystem      filesystem.FileSystemStoreOptions
-	Volume          volume.VolumeStoreOptions
+	// CacheTTL is the duration for which ConfigMaps and Secrets are cached in memory.
+	// Caching is disabled when set to 0.
+	CacheTTL   time.Duration
+	Filesystem filesystem.FileSystemStoreOptions
+	Volume     volume.VolumeStoreOptions
 }
 
 // ConfigureStore initializes and configures a storage backend for ConfigMap and Secret resources based on the provided StoreOptions.
 // It supports multiple backends: "disk" and "volume". For the "disk" backend, it uses a filesystem-based store.
 // For the "volume" backend, it uses a volume-based store that relies on Docker volumes.
+// When a cache TTL is specified, the selected backend is wrapped in a CachedStore.
 //
 // Parameters:
 // - opts: StoreOptions object containing configurations for initializing the storage backend.
@@ -100,7 +105,7 @@ func ConfigureStore(opts StoreOptions) (ConfigMapStore, SecretStore, error) {
 		}
 
 		opts.Logger.Info("using disk store for ConfigMaps and Secrets")
-		return filesystemStore, filesystemStore, nil
+		return withCache(opts, filesystemStore, filesystemStore)
 	case types.VolumeStoreBackend:
 		opts.Volume.SecretKind = volume.SecretResourceType
 		volumeStore, err := volume.NewVolumeStore(opts.Logger, opts.Volume)
@@ -109,12 +114,25 @@ func ConfigureStore(opts StoreOptions) (ConfigMapStore, SecretStore, error) {
 		}
 
 		opts.Logger.Info("using volume store for ConfigMaps and Secrets")
-		return volumeStore, volumeStore, nil
+		return withCache(opts, volumeStore, volumeStore)
 	default:
 		return nil, nil, fmt.Errorf("invalid store backend: %s", opts.Backend)
 	}
 }
 
+func withCache(opts StoreOptions, configMapStore ConfigMapStore, secretStore SecretStore) (ConfigMapStore, SecretStore, error) {
+	if opts.CacheTTL <= 0 {
+		return configMapStore, secretStore, nil
+	}
+
+	opts.Logger.Infow("using in-memory cache for ConfigMaps and Secrets",
+		"ttl", opts.CacheTTL,
+	)
+
+	cachedStore := NewCachedStore(configMapStore, secretStore, opts.CacheTTL)
+	return cachedStore, cachedStore, nil
+}
+
 // ConfigureRegistrySecretStore initializes and configures a storage backend for Registry Secrets based on the provided StoreOptions.
 // It supports multiple backends: "memory" and "volume". For the "memory" backend, it uses an in-memory store.
 // The "volume" backend utilizes a volume-based store, which relies on Docker volumes and an encryption key to store encrypted data.
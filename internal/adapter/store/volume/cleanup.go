@@ -0,0 +1,134 @@
+package volume
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"go.uber.org/zap"
+)
+
+const (
+	// CopyContainerNamePrefix is the prefix of the name of the temporary containers used to copy data to a volume
+	CopyContainerNamePrefix = "k2d-volume-copy-"
+
+	// ReadContainerNamePrefix is the prefix of the name of the temporary containers used to read data from volumes
+	ReadContainerNamePrefix = "k2d-volume-read-"
+)
+
+// CopyContainerStatistics reports the temporary containers used by the volume stores to copy and read data
+// since k2d started.
+type CopyContainerStatistics struct {
+	// Cleaned is the number of temporary containers removed after use
+	Cleaned int64 `json:"cleaned"`
+	// Leaked is the number of temporary containers that could not be removed after use.
+	// They are removed the next time k2d starts.
+	Leaked int64 `json:"leaked"`
+	// Swept is the number of temporary containers left by a previous run of k2d and removed on startup
+	Swept int64 `json:"swept"`
+}
+
+var (
+	cleanedCopyContainers atomic.Int64
+	leakedCopyContainers  atomic.Int64
+	sweptCopyContainers   atomic.Int64
+
+	sweepCopyContainersOnce sync.Once
+)
+
+// GetCopyContainerStatistics returns the statistics of the temporary containers used by the volume stores.
+func GetCopyContainerStatistics() CopyContainerStatistics {
+	return CopyContainerStatistics{
+		Cleaned: cleanedCopyContainers.Load(),
+		Leaked:  leakedCopyContainers.Load(),
+		Swept:   sweptCopyContainers.Load(),
+	}
+}
+
+// withCopyContainer creates and starts a temporary container with the specified volume bindings, runs the
+// specified function with the ID of the container and removes the container, whatever the outcome of the function.
+// A container that cannot be removed is reported as leaked and removed the next time k2d starts
+// (see sweepOrphanedCopyContainers).
+func (s *VolumeStore) withCopyContainer(volumeBinds []string, containerName string, fn func(containerID string) error) error {
+	containerID, err := s.createAndStartCopyContainer(volumeBinds, containerName)
+	if containerID != "" {
+		defer s.removeCopyContainer(containerID, containerName)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return fn(containerID)
+}
+
+// removeCopyContainer forcibly removes a temporary container and updates the statistics of the temporary containers.
+func (s *VolumeStore) removeCopyContainer(containerID, containerName string) {
+	err := s.cli.ContainerRemove(context.Background(), containerID, types.ContainerRemoveOptions{Force: true})
+	if err != nil {
+		leakedCopyContainers.Add(1)
+		s.logger.Warnw("unable to remove temporary volume copy container, it will be removed on the next startup",
+			"container_name", containerName,
+			"error", err,
+		)
+		return
+	}
+
+	cleanedCopyContainers.Add(1)
+}
+
+// sweepOrphanedCopyContainers removes the temporary containers left by a previous run of k2d, e.g. when k2d was stopped
+// while copying data or when a container could not be removed. It only runs once, when the first volume store is created,
+// so that it cannot remove the containers used by another volume store of the same process.
+// The containers that cannot be removed are reported but do not prevent k2d from starting.
+func sweepOrphanedCopyContainers(cli *client.Client, logger *zap.SugaredLogger) {
+	sweepCopyContainersOnce.Do(func() {
+		containers, err := cli.ContainerList(context.TODO(), types.ContainerListOptions{All: true, Filters: copyContainerListFilter()})
+		if err != nil {
+			logger.Warnw("unable to list orphaned temporary volume copy containers",
+				"error", err,
+			)
+			return
+		}
+
+		for _, container := range containers {
+			if !isCopyContainer(container.Names) {
+				continue
+			}
+
+			err := cli.ContainerRemove(context.TODO(), container.ID, types.ContainerRemoveOptions{Force: true})
+			if err != nil {
+				logger.Warnw("unable to remove orphaned temporary volume copy container",
+					"container_names", container.Names,
+					"error", err,
+				)
+				continue
+			}
+
+			sweptCopyContainers.Add(1)
+		}
+
+		if swept := sweptCopyContainers.Load(); swept > 0 {
+			logger.Infow("removed orphaned temporary volume copy containers",
+				"count", swept,
+			)
+		}
+	})
+}
+
+// isCopyContainer returns true when one of the names of a container is the name of a temporary volume copy container.
+// The name filter of the Docker API matches substrings, the prefix of the names is checked to avoid removing
+// a container whose name only contains the prefix.
+func isCopyContainer(containerNames []string) bool {
+	for _, name := range containerNames {
+		name = strings.TrimPrefix(name, "/")
+		if strings.HasPrefix(name, CopyContainerNamePrefix) || strings.HasPrefix(name, ReadContainerNamePrefix) {
+			return true
+		}
+	}
+
+	return false
+}
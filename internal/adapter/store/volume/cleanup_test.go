@@ -0,0 +1,21 @@
+package volume
+
+import "testing"
+
+func TestIsCopyContainer(t *testing.T) {
+	tests := []struct {
+		names    []string
+		expected bool
+	}{
+		{names: []string{"/k2d-volume-copy-k2d-cm-default-settings-1700000000"}, expected: true},
+		{names: []string{"/k2d-volume-read-1700000000"}, expected: true},
+		{names: []string{"/default-k2d-volume-read-app"}, expected: false},
+		{names: []string{"/web"}, expected: false},
+	}
+
+	for _, test := range tests {
+		if isCopyContainer(test.names) != test.expected {
+			t.Errorf("%v: expected %t", test.names, test.expected)
+		}
+	}
+}
@@ -3,7 +3,10 @@ package volume
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/errdefs"
@@ -31,7 +34,12 @@ import (
 func (store *VolumeStore) DeleteConfigMap(configMapName, namespace string) error {
 	volumeName := buildConfigMapVolumeName(configMapName, namespace)
 
-	err := store.cli.VolumeRemove(context.TODO(), volumeName, true)
+	existingVolume, err := store.cli.VolumeInspect(context.TODO(), volumeName)
+	if err == nil && checkVolumeNamespace(existingVolume.Labels, "configmap", configMapName, namespace) != nil {
+		return errors.ErrResourceNotFound
+	}
+
+	err = store.cli.VolumeRemove(context.TODO(), volumeName, true)
 	if err != nil {
 		return fmt.Errorf("unable to remove Docker volume: %w", err)
 	}
@@ -75,6 +83,10 @@ func (s *VolumeStore) GetConfigMap(configMapName, namespace string) (*core.Confi
 		return nil, fmt.Errorf("unable to inspect Docker volume: %w", err)
 	}
 
+	if checkVolumeNamespace(volume.Labels, "configmap", configMapName, namespace) != nil {
+		return nil, errors.ErrResourceNotFound
+	}
+
 	configMap, err := createConfigMapFromVolume(&volume)
 	if err != nil {
 		return nil, fmt.Errorf("unable to build config map from volume: %w", err)
@@ -85,7 +97,7 @@ func (s *VolumeStore) GetConfigMap(configMapName, namespace string) (*core.Confi
 		return nil, fmt.Errorf("unable to get data map from volume: %w", err)
 	}
 
-	configMap.Data = data
+	setConfigMapData(&configMap, data)
 
 	return &configMap, nil
 }
@@ -139,7 +151,7 @@ func (store *VolumeStore) GetConfigMaps(namespace string) (core.ConfigMapList, e
 			continue
 		}
 
-		configMap.Data = volumeData[volume.Name]
+		setConfigMapData(&configMap, volumeData[volume.Name])
 
 		configMaps.Items = append(configMaps.Items, configMap)
 	}
@@ -152,12 +164,14 @@ func (store *VolumeStore) GetConfigMaps(namespace string) (core.ConfigMapList, e
 // The function performs the following steps:
 // 1. Builds the Docker volume name for the ConfigMap based on its name and namespace.
 // 2. Creates a new Docker volume with the constructed name and attaches labels to it.
-// 3. Copies the data map of the ConfigMap to the created Docker volume.
+// 3. Copies the data and binary data of the ConfigMap to the created Docker volume. The binaryData keys are recorded
+// in the volume labels so that they can be returned as binary data.
 //
 // Parameters:
 // - configMap: A pointer to the ConfigMap object to store.
 //
 // Returns:
+// - An ErrResourceConflict error if the volume of the ConfigMap is already used by a ConfigMap of another namespace.
 // - An error object if the function fails to store the ConfigMap.
 func (store *VolumeStore) StoreConfigMap(configMap *corev1.ConfigMap) error {
 	volumeName := buildConfigMapVolumeName(configMap.Name, configMap.Namespace)
@@ -168,6 +182,32 @@ func (store *VolumeStore) StoreConfigMap(configMap *corev1.ConfigMap) error {
 	}
 	maputils.MergeMapsInPlace(labels, configMap.Labels)
 
+	dataMap := map[string]string{}
+	maputils.MergeMapsInPlace(dataMap, configMap.Data)
+
+	if len(configMap.BinaryData) > 0 {
+		binaryDataKeys := make([]string, 0, len(configMap.BinaryData))
+		for key, value := range configMap.BinaryData {
+			binaryDataKeys = append(binaryDataKeys, key)
+			dataMap[key] = string(value)
+		}
+		sort.Strings(binaryDataKeys)
+
+		labels[BinaryDataKeysLabelKey] = strings.Join(binaryDataKeys, ",")
+	}
+
+	existingVolume, err := store.cli.VolumeInspect(context.TODO(), volumeName)
+	if err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("unable to inspect Docker volume: %w", err)
+	}
+
+	if err == nil {
+		err = checkVolumeNamespace(existingVolume.Labels, "configmap", configMap.Name, configMap.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+
 	volume, err := store.cli.VolumeCreate(context.TODO(), volume.CreateOptions{
 		Name:   volumeName,
 		Labels: labels,
@@ -176,7 +216,7 @@ func (store *VolumeStore) StoreConfigMap(configMap *corev1.ConfigMap) error {
 		return fmt.Errorf("unable to create Docker volume: %w", err)
 	}
 
-	err = store.copyDataMapToVolume(volume.Name, configMap.Data)
+	err = store.copyDataMapToVolume(volume.Name, dataMap)
 	if err != nil {
 		return fmt.Errorf("unable to copy data map to volume: %w", err)
 	}
@@ -214,3 +254,29 @@ func createConfigMapFromVolume(volume *volume.Volume) (core.ConfigMap, error) {
 
 	return configMap, nil
 }
+
+// setConfigMapData sets the data read from a Docker volume in a ConfigMap.
+// A key is returned as binary data when it is listed in the binaryData keys recorded in the volume labels or when
+// its content is not valid UTF-8, as the labels of an existing volume cannot be updated when the ConfigMap is updated.
+func setConfigMapData(configMap *core.ConfigMap, data map[string]string) {
+	binaryDataKeys := map[string]struct{}{}
+	if keys := configMap.Labels[BinaryDataKeysLabelKey]; keys != "" {
+		for _, key := range strings.Split(keys, ",") {
+			binaryDataKeys[key] = struct{}{}
+		}
+	}
+	delete(configMap.Labels, BinaryDataKeysLabelKey)
+
+	configMap.Data = map[string]string{}
+	for key, value := range data {
+		if _, isBinary := binaryDataKeys[key]; isBinary || !utf8.ValidString(value) {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = map[string][]byte{}
+			}
+			configMap.BinaryData[key] = []byte(value)
+			continue
+		}
+
+		configMap.Data[key] = value
+	}
+}
@@ -24,20 +24,23 @@ import (
 // - dataMap: A map where the keys are file names and the values are file contents.
 //
 // Returns:
-// - Returns an error if any step in the pipeline (container creation, data encryption, data copying, or container removal) fails.
+// - Returns an error if any step in the pipeline (data encryption, container creation or data copying) fails.
 //
 // Implementation Details:
-// - Creates a temporary container for data copying, with the target Docker volume mounted.
 // - Optionally encrypts the data using the encryption key, if provided.
 // - Writes the (possibly encrypted) data to a tar archive.
+// - Creates a temporary container for data copying, with the target Docker volume mounted.
 // - Copies the tar archive to the temporary container.
-// - Removes the temporary container after data copying is complete.
+// - Removes the temporary container after data copying, even when the copy fails (see withCopyContainer).
+// - When the direct access is enabled, the data is written to the mountpoint of the volume instead (see direct.go).
 func (s *VolumeStore) copyDataMapToVolume(volumeName string, dataMap map[string]string) error {
-	volumeBinds := []string{fmt.Sprintf("%s:%s", volumeName, WorkingDirName)}
-	copyContainerName := fmt.Sprintf("k2d-volume-copy-%s-%d", volumeName, time.Now().UnixNano())
-	containerID, err := s.createAndStartCopyContainer(volumeBinds, copyContainerName)
-	if err != nil {
-		return fmt.Errorf("unable to create temporary volume copy container: %w", err)
+	if s.directAccess {
+		mountpoint, err := s.volumeMountpoint(volumeName)
+		if err != nil {
+			return err
+		}
+
+		return writeDataMapToDir(mountpoint, dataMap, s.encryptionKey)
 	}
 
 	var buf bytes.Buffer
@@ -68,19 +71,17 @@ func (s *VolumeStore) copyDataMapToVolume(volumeName string, dataMap map[string]
 		return fmt.Errorf("unable to close tar writer: %w", err)
 	}
 
-	err = s.cli.CopyToContainer(context.TODO(), containerID, WorkingDirName, &buf, types.CopyToContainerOptions{})
-	if err != nil {
-		return fmt.Errorf("unable to copy data to temporary volume copy container: %w", err)
-	}
+	volumeBinds := []string{fmt.Sprintf("%s:%s", volumeName, WorkingDirName)}
+	copyContainerName := fmt.Sprintf("%s%s-%d", CopyContainerNamePrefix, volumeName, time.Now().UnixNano())
 
-	err = s.cli.ContainerRemove(context.TODO(), containerID, types.ContainerRemoveOptions{
-		Force: true,
-	})
-	if err != nil {
-		return fmt.Errorf("unable to remove temporary volume copy container: %w", err)
-	}
+	return s.withCopyContainer(volumeBinds, copyContainerName, func(containerID string) error {
+		err := s.cli.CopyToContainer(context.TODO(), containerID, WorkingDirName, &buf, types.CopyToContainerOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to copy data to temporary volume copy container: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // createAndStartCopyContainer creates a new Docker container with specified volume bindings.
@@ -90,11 +91,19 @@ func (s *VolumeStore) copyDataMapToVolume(volumeName string, dataMap map[string]
 // - volumeBinds: A list of volume bindings, which are strings that specify the volumes to attach to the container.
 // - containerName: The name to give to the temporary container.
 //
+// The copy image is pulled when it is not available yet (see copyImage).
+//
 // Returns:
-// - The ID of the newly created container or an error if the container creation fails.
+// - The ID of the newly created container, also returned when the container cannot be started so that it can be removed.
+// - An error if the container creation or start fails.
 func (s *VolumeStore) createAndStartCopyContainer(volumeBinds []string, containerName string) (string, error) {
+	err := s.copyImage.ensure(context.TODO())
+	if err != nil {
+		return "", err
+	}
+
 	containerConfig := &container.Config{
-		Image: s.copyImageName,
+		Image: s.copyImage.name,
 	}
 	hostConfig := &container.HostConfig{
 		Binds: volumeBinds,
@@ -102,11 +111,11 @@ func (s *VolumeStore) createAndStartCopyContainer(volumeBinds []string, containe
 
 	resp, err := s.cli.ContainerCreate(context.TODO(), containerConfig, hostConfig, nil, nil, containerName)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("unable to create temporary volume copy container: %w", err)
 	}
 
 	if err = s.cli.ContainerStart(context.TODO(), resp.ID, types.ContainerStartOptions{}); err != nil {
-		return "", err
+		return resp.ID, fmt.Errorf("unable to start temporary volume copy container: %w", err)
 	}
 
 	return resp.ID, nil
@@ -123,27 +132,30 @@ func (s *VolumeStore) createAndStartCopyContainer(volumeBinds []string, containe
 // - An error if the operation fails.
 //
 // Implementation Details:
-// - A temporary container is created to read from the mounted volume.
+// - A temporary container is created to read from the mounted volume, and removed even when the read fails.
 // - If an encryption key is provided, the data is decrypted before being returned.
+// - When the direct access is enabled, the data is read from the mountpoint of the volume instead (see direct.go).
 func (store *VolumeStore) getDataMapFromVolume(volumeName string) (map[string]string, error) {
-	copyContainerName := fmt.Sprintf("k2d-volume-read-%s-%d", volumeName, time.Now().UnixNano())
-	volumeBinds := []string{fmt.Sprintf("%s:%s", volumeName, WorkingDirName)}
-	containerID, err := store.createAndStartCopyContainer(volumeBinds, copyContainerName)
-	if err != nil {
-		return nil, err
-	}
+	if store.directAccess {
+		mountpoint, err := store.volumeMountpoint(volumeName)
+		if err != nil {
+			return nil, err
+		}
 
-	content, _, err := store.cli.CopyFromContainer(context.TODO(), containerID, WorkingDirName)
-	if err != nil {
-		return nil, err
+		return readDataMapFromDir(mountpoint, store.encryptionKey)
 	}
 
-	err = store.cli.ContainerRemove(context.TODO(), containerID, types.ContainerRemoveOptions{Force: true})
-	if err != nil {
-		return nil, err
-	}
+	copyContainerName := fmt.Sprintf("%s%s-%d", ReadContainerNamePrefix, volumeName, time.Now().UnixNano())
+	volumeBinds := []string{fmt.Sprintf("%s:%s", volumeName, WorkingDirName)}
 
-	return parseTarToMap(content, store.encryptionKey)
+	var dataMap map[string]string
+	err := store.withCopyContainer(volumeBinds, copyContainerName, func(containerID string) error {
+		var err error
+		dataMap, err = store.readDataMapFromContainer(containerID, WorkingDirName)
+		return err
+	})
+
+	return dataMap, err
 }
 
 // getDataMapsFromVolumes extracts and optionally decrypts the data stored in multiple Docker volumes and returns it as a map of maps.
@@ -157,41 +169,60 @@ func (store *VolumeStore) getDataMapFromVolume(volumeName string) (map[string]st
 // - An error if the operation fails.
 //
 // Implementation Details:
-// - A single temporary container is created to read from multiple mounted volumes.
+// - A single temporary container is created to read from multiple mounted volumes, and removed even when a read fails.
 // - If an encryption key is provided, the data from each volume is decrypted before being returned.
+// - When the direct access is enabled, the data is read from the mountpoint of each volume instead (see direct.go).
 func (store *VolumeStore) getDataMapsFromVolumes(volumeNames []string) (map[string]map[string]string, error) {
+	if store.directAccess {
+		result := make(map[string]map[string]string)
+		for _, volumeName := range volumeNames {
+			dataMap, err := store.getDataMapFromVolume(volumeName)
+			if err != nil {
+				return nil, err
+			}
+
+			result[volumeName] = dataMap
+		}
+
+		return result, nil
+	}
+
 	var volumeBinds []string
 	for _, volumeName := range volumeNames {
 		volumeBinds = append(volumeBinds, fmt.Sprintf("%s:%s", volumeName, path.Join(WorkingDirName, volumeName)))
 	}
 
-	copyContainerName := fmt.Sprintf("k2d-volume-read-%d", time.Now().UnixNano())
-	containerID, err := store.createAndStartCopyContainer(volumeBinds, copyContainerName)
-	if err != nil {
-		return nil, err
-	}
+	copyContainerName := fmt.Sprintf("%s%d", ReadContainerNamePrefix, time.Now().UnixNano())
 
 	result := make(map[string]map[string]string)
-	for _, volumeName := range volumeNames {
-		content, _, err := store.cli.CopyFromContainer(context.TODO(), containerID, path.Join(WorkingDirName, volumeName))
-		if err != nil {
-			return nil, err
-		}
+	err := store.withCopyContainer(volumeBinds, copyContainerName, func(containerID string) error {
+		for _, volumeName := range volumeNames {
+			dataMap, err := store.readDataMapFromContainer(containerID, path.Join(WorkingDirName, volumeName))
+			if err != nil {
+				return err
+			}
 
-		dataMap, err := parseTarToMap(content, store.encryptionKey)
-		if err != nil {
-			return nil, err
+			result[volumeName] = dataMap
 		}
 
-		result[volumeName] = dataMap
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err = store.cli.ContainerRemove(context.Background(), containerID, types.ContainerRemoveOptions{Force: true})
+	return result, nil
+}
+
+// readDataMapFromContainer reads the files of a directory of a temporary container as a map (see parseTarToMap).
+func (store *VolumeStore) readDataMapFromContainer(containerID, dirPath string) (map[string]string, error) {
+	content, _, err := store.cli.CopyFromContainer(context.TODO(), containerID, dirPath)
 	if err != nil {
 		return nil, err
 	}
+	defer content.Close()
 
-	return result, nil
+	return parseTarToMap(content, store.encryptionKey)
 }
 
 // parseTarToMap takes a TAR archive Reader and converts it into a map where each key is a file name and
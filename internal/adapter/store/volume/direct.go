@@ -0,0 +1,99 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// The volume store can read and write the data of the volumes directly from their mountpoint on the host
+// instead of using temporary containers, when the Docker daemon runs on the same host as k2d and the Docker volumes
+// directory (usually /var/lib/docker/volumes) is accessible from k2d at the same path,
+// e.g. by mounting it with the same path in the k2d container.
+
+// volumeMountpoint returns the mountpoint of a volume on the host, after ensuring that it is accessible from k2d.
+func (s *VolumeStore) volumeMountpoint(volumeName string) (string, error) {
+	volume, err := s.cli.VolumeInspect(context.TODO(), volumeName)
+	if err != nil {
+		return "", fmt.Errorf("unable to inspect volume %s: %w", volumeName, err)
+	}
+
+	if volume.Mountpoint == "" {
+		return "", fmt.Errorf("volume %s does not have a mountpoint", volumeName)
+	}
+
+	_, err = os.Stat(volume.Mountpoint)
+	if err != nil {
+		return "", fmt.Errorf("the mountpoint of volume %s is not accessible, make sure that the Docker volumes directory is available at the same path in k2d: %w", volumeName, err)
+	}
+
+	return volume.Mountpoint, nil
+}
+
+// writeDataMapToDir writes each entry of a data map to a file of a directory, optionally encrypted, with the same
+// permissions as the files copied through a temporary container. Each file is written to a temporary file that replaces
+// the existing file, so that a partially written file is never read.
+func writeDataMapToDir(dirPath string, dataMap map[string]string, encryptionKey []byte) error {
+	for key, value := range dataMap {
+		data, err := encryptIfKeyProvided([]byte(value), encryptionKey)
+		if err != nil {
+			return fmt.Errorf("unable to write data: %w", err)
+		}
+
+		file, err := os.CreateTemp(dirPath, "."+key+".*.tmp")
+		if err != nil {
+			return fmt.Errorf("unable to create file for key %s: %w", key, err)
+		}
+
+		_, err = file.Write(data)
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Chmod(file.Name(), 0400)
+		}
+		if err == nil {
+			err = os.Rename(file.Name(), filepath.Join(dirPath, key))
+		}
+
+		if err != nil {
+			os.Remove(file.Name())
+			return fmt.Errorf("unable to write file for key %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// readDataMapFromDir reads the regular files of a directory as a map where each key is a file name
+// and the corresponding value is the optionally decrypted content of the file.
+// The hidden files are skipped, as they are the temporary files of a write in progress (see writeDataMapToDir).
+func readDataMapFromDir(dirPath string, encryptionKey []byte) (map[string]string, error) {
+	files, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read directory: %w", err)
+	}
+
+	dataMap := make(map[string]string)
+	for _, file := range files {
+		if !file.Type().IsRegular() || file.Name()[0] == '.' {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dirPath, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read file %s: %w", file.Name(), err)
+		}
+
+		data, err := decryptIfKeyProvided(content, encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read data: %w", err)
+		}
+
+		dataMap[file.Name()] = string(data)
+	}
+
+	return dataMap, nil
+}
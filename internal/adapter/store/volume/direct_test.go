@@ -0,0 +1,49 @@
+package volume
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDataMapDirRoundTrip(t *testing.T) {
+	encryptionKeys := map[string][]byte{
+		"plain":     nil,
+		"encrypted": []byte("0123456789abcdef0123456789abcdef"),
+	}
+
+	for name, encryptionKey := range encryptionKeys {
+		t.Run(name, func(t *testing.T) {
+			dirPath := t.TempDir()
+			dataMap := map[string]string{"username": "admin", "password": "secret"}
+
+			if err := writeDataMapToDir(dirPath, dataMap, encryptionKey); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			// A key updated with a new value replaces the read-only file of the previous value
+			dataMap["password"] = "updated"
+			if err := writeDataMapToDir(dirPath, dataMap, encryptionKey); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if err := os.WriteFile(filepath.Join(dirPath, ".password.123.tmp"), []byte("partial"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			readDataMap, err := readDataMapFromDir(dirPath, encryptionKey)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(readDataMap) != len(dataMap) {
+				t.Fatalf("expected %d keys, got %v", len(dataMap), readDataMap)
+			}
+			for key, value := range dataMap {
+				if readDataMap[key] != value {
+					t.Errorf("expected %s to be %q, got %q", key, value, readDataMap[key])
+				}
+			}
+		})
+	}
+}
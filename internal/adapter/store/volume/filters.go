@@ -18,3 +18,10 @@ func secretListFilter(namespace, secretKind string) filters.Args {
 	filter.Add("label", fmt.Sprintf("%s=%s", ResourceTypeLabelKey, secretKind))
 	return filter
 }
+
+func copyContainerListFilter() filters.Args {
+	return filters.NewArgs(
+		filters.Arg("name", CopyContainerNamePrefix),
+		filters.Arg("name", ReadContainerNamePrefix),
+	)
+}
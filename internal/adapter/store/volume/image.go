@@ -0,0 +1,194 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/portainer/k2d/internal/adapter/registry"
+	"go.uber.org/zap"
+)
+
+const (
+	// copyImagePullRetryInterval is the initial interval between two attempts to pull the copy image in the background
+	copyImagePullRetryInterval = 10 * time.Second
+
+	// copyImagePullMaxRetryInterval is the maximum interval between two attempts to pull the copy image in the background
+	copyImagePullMaxRetryInterval = 5 * time.Minute
+)
+
+// copyImage is the image of the temporary containers used to copy and read the data of the volumes.
+// The image is pulled lazily, when the first temporary container is created, so that k2d does not wait for the pull
+// on startup. It is also pulled in the background on startup, with retries, so that it is usually available
+// when the volume store is used for the first time. The image cached by the Docker daemon is used when it cannot be pulled.
+// Concurrent pulls of the image share the same pull.
+type copyImage struct {
+	name   string
+	logger *zap.SugaredLogger
+	cli    *client.Client
+	// pullImage pulls the image, it can be replaced in tests
+	pullImage func() error
+
+	mutex  sync.Mutex
+	ready  bool
+	pullOp *imagePullOperation
+}
+
+// imagePullOperation is a pull of the copy image shared by the concurrent callers of copyImage.pull.
+type imagePullOperation struct {
+	done chan struct{}
+	err  error
+}
+
+var (
+	copyImages      = map[string]*copyImage{}
+	copyImagesMutex sync.Mutex
+)
+
+// getCopyImage returns the copy image with the specified name, shared by the volume stores.
+// The image is pulled in the background the first time it is requested.
+func getCopyImage(logger *zap.SugaredLogger, cli *client.Client, registryOptions registry.Options, imageName string) *copyImage {
+	copyImagesMutex.Lock()
+	defer copyImagesMutex.Unlock()
+
+	if image, found := copyImages[imageName]; found {
+		return image
+	}
+
+	image := &copyImage{
+		name:   imageName,
+		logger: logger,
+		cli:    cli,
+	}
+	image.pullImage = func() error {
+		err := registry.CheckInsecureRegistry(context.TODO(), cli, registryOptions, imageName)
+		if err != nil {
+			return err
+		}
+
+		out, err := cli.ImagePull(context.TODO(), imageName, types.ImagePullOptions{})
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		io.Copy(io.Discard, out)
+
+		return nil
+	}
+
+	copyImages[imageName] = image
+	go image.pullInBackground()
+
+	return image
+}
+
+// ensure makes sure that the copy image is available before creating a temporary container.
+// The image is pulled when it is not available in the cache of the Docker daemon.
+func (image *copyImage) ensure(ctx context.Context) error {
+	image.mutex.Lock()
+	ready := image.ready
+	image.mutex.Unlock()
+
+	if ready {
+		return nil
+	}
+
+	if image.isCached(ctx) {
+		image.mutex.Lock()
+		image.ready = true
+		image.mutex.Unlock()
+
+		return nil
+	}
+
+	err := image.pull(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to pull volume copy image %s: %w", image.name, err)
+	}
+
+	return nil
+}
+
+// isCached returns true when the copy image is available in the cache of the Docker daemon.
+func (image *copyImage) isCached(ctx context.Context) bool {
+	_, _, err := image.cli.ImageInspectWithRaw(ctx, image.name)
+	return err == nil
+}
+
+// pull pulls the copy image, or waits for the pull started by another caller.
+// The pull is not canceled when the context of a caller is done, as it is shared with the other callers.
+func (image *copyImage) pull(ctx context.Context) error {
+	image.mutex.Lock()
+	op := image.pullOp
+	if op == nil {
+		op = &imagePullOperation{done: make(chan struct{})}
+		image.pullOp = op
+
+		go func() {
+			op.err = image.pullImage()
+
+			image.mutex.Lock()
+			image.pullOp = nil
+			if op.err == nil {
+				image.ready = true
+			}
+			image.mutex.Unlock()
+
+			close(op.done)
+		}()
+	}
+	image.mutex.Unlock()
+
+	select {
+	case <-op.done:
+		return op.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pullInBackground pulls the copy image on startup, to refresh the cached image or to make it available
+// before the volume store is used. When the image is not cached, the pull is retried with an increasing interval
+// until it succeeds. When the image is cached, the cached image is used if the pull fails.
+func (image *copyImage) pullInBackground() {
+	retryInterval := copyImagePullRetryInterval
+
+	for {
+		err := image.pull(context.Background())
+		if err == nil {
+			image.logger.Debugw("volume copy image pulled",
+				"image", image.name,
+			)
+			return
+		}
+
+		if image.isCached(context.Background()) {
+			image.logger.Warnw("unable to pull volume copy image, using the cached image",
+				"image", image.name,
+				"error", err,
+			)
+
+			image.mutex.Lock()
+			image.ready = true
+			image.mutex.Unlock()
+			return
+		}
+
+		image.logger.Warnw("unable to pull volume copy image, retrying in the background",
+			"image", image.name,
+			"retry_interval", retryInterval,
+			"error", err,
+		)
+
+		time.Sleep(retryInterval)
+
+		retryInterval *= 2
+		if retryInterval > copyImagePullMaxRetryInterval {
+			retryInterval = copyImagePullMaxRetryInterval
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package volume
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCopyImagePullIsShared(t *testing.T) {
+	pulls := atomic.Int32{}
+	release := make(chan struct{})
+
+	image := &copyImage{
+		name: "portainer/pause:latest",
+		pullImage: func() error {
+			pulls.Add(1)
+			<-release
+			return nil
+		},
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := image.pull(context.Background()); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if pulls.Load() != 1 {
+		t.Errorf("expected a single pull, got %d", pulls.Load())
+	}
+
+	if !image.ready {
+		t.Error("expected the image to be ready after a successful pull")
+	}
+}
+
+func TestCopyImagePullCanceledCaller(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	image := &copyImage{
+		name: "portainer/pause:latest",
+		pullImage: func() error {
+			<-release
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := image.pull(ctx); err != context.Canceled {
+		t.Errorf("expected the canceled caller to return, got %v", err)
+	}
+}
@@ -3,6 +3,9 @@ package volume
 import (
 	"fmt"
 	"strings"
+
+	"github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/types"
 )
 
 const (
@@ -36,3 +39,19 @@ func getConfigMapNameFromVolumeName(volumeName, namespace string) string {
 func getSecretNameFromVolumeName(volumeName, namespace string) string {
 	return strings.TrimPrefix(volumeName, fmt.Sprintf("%s%s-", SecretVolumePrefix, namespace))
 }
+
+// checkVolumeNamespace ensures that the volume of a ConfigMap or Secret belongs to the specified namespace.
+// As the namespace and the name of a resource are joined with a dash to build the volume name, two resources of different
+// namespaces can share the same volume (e.g. the configmap b-c in the namespace a and the configmap c in the namespace a-b).
+// The namespace recorded in the volume labels is used to make sure that a resource is never read, updated or deleted
+// from another namespace.
+// It returns an ErrResourceConflict error if the volume belongs to a resource of another namespace.
+func checkVolumeNamespace(volumeLabels map[string]string, kind, name, namespace string) error {
+	volumeNamespace := volumeLabels[types.NamespaceNameLabelKey]
+	if volumeNamespace == namespace {
+		return nil
+	}
+
+	return fmt.Errorf("%w: the %s %s in namespace %s shares its storage with a %s of namespace %s",
+		errors.ErrResourceConflict, kind, name, namespace, kind, volumeNamespace)
+}
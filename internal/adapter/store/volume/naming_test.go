@@ -0,0 +1,129 @@
+package volume
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/adapter/types"
+	"go.uber.org/zap"
+)
+
+func TestCheckVolumeNamespace(t *testing.T) {
+	tests := []struct {
+		name      string
+		labels    map[string]string
+		namespace string
+		conflict  bool
+	}{
+		{name: "same namespace", labels: map[string]string{types.NamespaceNameLabelKey: "a-b"}, namespace: "a-b"},
+		{name: "other namespace", labels: map[string]string{types.NamespaceNameLabelKey: "a"}, namespace: "a-b", conflict: true},
+		{name: "namespace not recorded", labels: map[string]string{}, namespace: "a-b", conflict: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkVolumeNamespace(test.labels, "configmap", "c", test.namespace)
+			if test.conflict != errors.Is(err, adaptererr.ErrResourceConflict) {
+				t.Errorf("expected a conflict: %t, got %v", test.conflict, err)
+			}
+		})
+	}
+}
+
+// newFakeVolumeStore returns a volume store using a fake Docker API serving the specified volumes.
+// The names of the volumes removed through the API are added to removed.
+func newFakeVolumeStore(t *testing.T, volumes map[string]volume.Volume, removed *[]string) *VolumeStore {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/volumes/") {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+
+		name := path.Base(r.URL.Path)
+		vol, found := volumes[name]
+		if !found {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"no such volume"}`))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(vol)
+		case http.MethodDelete:
+			*removed = append(*removed, name)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+server.Listener.Addr().String()), client.WithVersion("1.43"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return &VolumeStore{cli: cli, logger: zap.NewNop().Sugar()}
+}
+
+func TestVolumeStoreNamespaceCollision(t *testing.T) {
+	tests := []struct {
+		kind       string
+		volumeName string
+		get        func(store *VolumeStore, name, namespace string) error
+		delete     func(store *VolumeStore, name, namespace string) error
+	}{
+		{
+			kind:       "configmap",
+			volumeName: buildConfigMapVolumeName("b-c", "a"),
+			get: func(store *VolumeStore, name, namespace string) error {
+				_, err := store.GetConfigMap(name, namespace)
+				return err
+			},
+			delete: (*VolumeStore).DeleteConfigMap,
+		},
+		{
+			kind:       "secret",
+			volumeName: buildSecretVolumeName("b-c", "a"),
+			get: func(store *VolumeStore, name, namespace string) error {
+				_, err := store.GetSecret(name, namespace)
+				return err
+			},
+			delete: (*VolumeStore).DeleteSecret,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.kind, func(t *testing.T) {
+			volumes := map[string]volume.Volume{
+				test.volumeName: {Name: test.volumeName, Labels: map[string]string{types.NamespaceNameLabelKey: "a"}},
+			}
+			removed := []string{}
+			store := newFakeVolumeStore(t, volumes, &removed)
+
+			// b-c in the namespace a and c in the namespace a-b share the same volume name once joined with a dash
+			if err := test.get(store, "c", "a-b"); !errors.Is(err, adaptererr.ErrResourceNotFound) {
+				t.Errorf("expected the %s not to be found from the other namespace, got %v", test.kind, err)
+			}
+
+			if err := test.delete(store, "c", "a-b"); !errors.Is(err, adaptererr.ErrResourceNotFound) {
+				t.Errorf("expected the %s not to be deleted from the other namespace, got %v", test.kind, err)
+			}
+
+			if len(removed) != 0 {
+				t.Errorf("expected the volume of the namespace a to be kept, removed %v", removed)
+			}
+		})
+	}
+}
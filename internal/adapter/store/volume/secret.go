@@ -32,7 +32,12 @@ import (
 func (s *VolumeStore) DeleteSecret(secretName, namespace string) error {
 	volumeName := buildSecretVolumeName(secretName, namespace)
 
-	err := s.cli.VolumeRemove(context.TODO(), volumeName, true)
+	existingVolume, err := s.cli.VolumeInspect(context.TODO(), volumeName)
+	if err == nil && checkVolumeNamespace(existingVolume.Labels, "secret", secretName, namespace) != nil {
+		return errors.ErrResourceNotFound
+	}
+
+	err = s.cli.VolumeRemove(context.TODO(), volumeName, true)
 	if err != nil {
 		return fmt.Errorf("unable to remove Docker volume: %w", err)
 	}
@@ -76,6 +81,10 @@ func (s *VolumeStore) GetSecret(secretName, namespace string) (*core.Secret, err
 		return nil, fmt.Errorf("unable to inspect Docker volume: %w", err)
 	}
 
+	if checkVolumeNamespace(volume.Labels, "secret", secretName, namespace) != nil {
+		return nil, errors.ErrResourceNotFound
+	}
+
 	secret, err := createSecretFromVolume(&volume)
 	if err != nil {
 		return nil, fmt.Errorf("unable to build secret from volume: %w", err)
@@ -161,13 +170,16 @@ func (s *VolumeStore) GetSecrets(namespace string, selector labels.Selector) (co
 //
 // The function performs the following steps:
 // 1. Builds the Docker volume name for the secret based on its name and namespace.
-// 2. Creates a new Docker volume with the constructed name and attaches labels to it.
-// 3. Copies both the data map and string data of the Secret to the created Docker volume.
+// 2. Removes the existing Docker volume if its labels differ from the labels of the secret, as
+// Docker volume labels cannot be updated (e.g. the status label of Helm release secrets).
+// 3. Creates a new Docker volume with the constructed name and attaches labels to it.
+// 4. Copies both the data map and string data of the Secret to the created Docker volume.
 //
 // Parameters:
 // - secret: A pointer to the Secret object to store.
 //
 // Returns:
+// - An ErrResourceConflict error if the volume of the secret is already used by a secret of another namespace.
 // - An error object if the function fails to store the secret.
 func (s *VolumeStore) StoreSecret(secret *corev1.Secret) error {
 	volumeName := buildSecretVolumeName(secret.Name, secret.Namespace)
@@ -178,6 +190,26 @@ func (s *VolumeStore) StoreSecret(secret *corev1.Secret) error {
 		types.NamespaceNameLabelKey: secret.Namespace,
 	}
 	maputils.MergeMapsInPlace(labels, secret.Labels)
+	delete(labels, VolumeNameLabelKey)
+
+	existingVolume, err := s.cli.VolumeInspect(context.TODO(), volumeName)
+	if err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("unable to inspect Docker volume: %w", err)
+	}
+
+	if err == nil {
+		err = checkVolumeNamespace(existingVolume.Labels, "secret", secret.Name, secret.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err == nil && !maputils.EqualMaps(existingVolume.Labels, labels) {
+		err = s.cli.VolumeRemove(context.TODO(), volumeName, false)
+		if err != nil {
+			s.logger.Warnf("unable to remove Docker volume %s to update its labels, existing labels are preserved: %s", volumeName, err)
+		}
+	}
 
 	volume, err := s.cli.VolumeCreate(context.TODO(), volume.CreateOptions{
 		Name:   volumeName,
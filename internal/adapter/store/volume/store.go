@@ -1,19 +1,21 @@
 package volume
 
 import (
-	"context"
 	"crypto/rand"
 	"fmt"
-	"io"
 	"path/filepath"
 
-	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+	"github.com/portainer/k2d/internal/adapter/registry"
 	"github.com/portainer/k2d/pkg/filesystem"
 	"go.uber.org/zap"
 )
 
 const (
+	// BinaryDataKeysLabelKey is the key used to store the comma separated list of the binaryData keys of a ConfigMap in the volume labels
+	// The binary data is stored as raw bytes in the volume, like the other keys of the ConfigMap.
+	BinaryDataKeysLabelKey = "store.k2d.io/volume/binary-data-keys"
+
 	// ResourceTypeLabelKey is the key used to store the associated Kubernetes resource type in the volume labels
 	// It is used to identify the type of resource that the volume is associated with such as a ConfigMap or a Secret
 	ResourceTypeLabelKey = "store.k2d.io/volume/resource-type"
@@ -59,9 +61,10 @@ const EncryptionKeyFileName = "volume-encryption.key"
 type VolumeStore struct {
 	cli           *client.Client
 	logger        *zap.SugaredLogger
-	copyImageName string
+	copyImage     *copyImage
 	secretKind    string
 	encryptionKey []byte
+	directAccess  bool
 }
 
 // VolumeStoreOptions represents options used to create a new VolumeStore.
@@ -70,13 +73,21 @@ type VolumeStoreOptions struct {
 	CopyImageName string
 	EncryptionKey []byte
 	SecretKind    string
+	// DirectAccess enables reading and writing the data of the volumes from their mountpoint
+	// instead of using temporary containers (see direct.go)
+	DirectAccess bool
+	// Registry is the configuration of the registries used to pull the volume copy image
+	Registry registry.Options
 }
 
 // NewVolumeStore creates a new instance of VolumeStore.
 //
-// The function attempts to pull a specific Docker image (defined by the CopyImageName constant)
-// that will be used for ephemeral containers responsible for copying and reading data.
-// If the image pulling fails, the function returns an error.
+// The Docker image defined by the CopyImageName option is used for ephemeral containers responsible for copying
+// and reading data. It is pulled in the background and when the first ephemeral container is created (see copyImage),
+// so that the creation of the store does not wait for the pull.
+// The image is not used when the direct access to the mountpoint of the volumes is enabled, as no temporary
+// container is used.
+// The temporary containers left by a previous run of k2d are removed when the first volume store is created.
 //
 // Parameters:
 // - cli: A Docker client used to interact with the Docker engine.
@@ -84,19 +95,26 @@ type VolumeStoreOptions struct {
 //
 // Returns:
 // - A pointer to the created VolumeStore instance.
-// - An error if any occurs during the initialization, like failing to pull the copy image.
+// - An error if any occurs during the initialization.
 func NewVolumeStore(logger *zap.SugaredLogger, opts VolumeStoreOptions) (*VolumeStore, error) {
-	out, err := opts.DockerCli.ImagePull(context.TODO(), opts.CopyImageName, types.ImagePullOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("unable to pull volume copy image: %w", err)
+	sweepOrphanedCopyContainers(opts.DockerCli, logger)
+
+	if opts.DirectAccess {
+		logger.Info("using the mountpoint of the volumes to read and write the data of the volume store")
+
+		return &VolumeStore{
+			cli:           opts.DockerCli,
+			logger:        logger,
+			encryptionKey: opts.EncryptionKey,
+			secretKind:    opts.SecretKind,
+			directAccess:  true,
+		}, nil
 	}
-	defer out.Close()
-	io.Copy(io.Discard, out)
 
 	return &VolumeStore{
 		cli:           opts.DockerCli,
 		logger:        logger,
-		copyImageName: opts.CopyImageName,
+		copyImage:     getCopyImage(logger, opts.DockerCli, opts.Registry, opts.CopyImageName),
 		encryptionKey: opts.EncryptionKey,
 		secretKind:    opts.SecretKind,
 	}, nil
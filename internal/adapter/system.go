@@ -5,12 +5,28 @@ import (
 	"fmt"
 
 	"github.com/docker/docker/api/types"
+	k2dtypes "github.com/portainer/k2d/internal/adapter/types"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func (adapter *KubeDockerAdapter) Ping(ctx context.Context) (types.Ping, error) {
 	return adapter.cli.Ping(ctx)
 }
 
+// DetectContainerRuntime detects the container runtime exposing the Docker API (Docker or Podman)
+// and configures the adapter to apply the runtime specific behaviors.
+func (adapter *KubeDockerAdapter) DetectContainerRuntime(ctx context.Context) (k2dtypes.ContainerRuntime, error) {
+	version, err := adapter.cli.ServerVersion(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve Docker version: %w", err)
+	}
+
+	runtime := k2dtypes.DetectContainerRuntime(version)
+	adapter.converter.SetContainerRuntime(runtime)
+
+	return runtime, nil
+}
+
 func (adapter *KubeDockerAdapter) InfoAndVersion(ctx context.Context) (types.Info, types.Version, error) {
 	info, err := adapter.cli.Info(ctx)
 	if err != nil {
@@ -24,3 +40,14 @@ func (adapter *KubeDockerAdapter) InfoAndVersion(ctx context.Context) (types.Inf
 
 	return info, version, nil
 }
+
+// CheckStoreBackend verifies that the store backend used for secrets and configmaps is usable
+// by listing the secrets of the k2d namespace, which always contains the service account secret.
+func (adapter *KubeDockerAdapter) CheckStoreBackend() error {
+	_, err := adapter.secretStore.GetSecrets(k2dtypes.K2DNamespaceName, labels.Everything())
+	if err != nil {
+		return fmt.Errorf("unable to list secrets from the store backend: %w", err)
+	}
+
+	return nil
+}
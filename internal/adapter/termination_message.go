@@ -0,0 +1,99 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/portainer/k2d/internal/adapter/converter"
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// TerminationMessagesFolder is the name of the directory, relative to the k2d data path, where the files
+	// mounted at the termination message path of the containers are stored.
+	TerminationMessagesFolder = "termination-messages"
+
+	// defaultTerminationMessagePath is the termination message path used when the container does not define one
+	defaultTerminationMessagePath = "/dev/termination-log"
+
+	// The limits applied by the kubelet to the termination messages: the termination message file is truncated
+	// to 4096 bytes and the logs used as a fallback are truncated to their last 80 lines and 2048 bytes.
+	terminationMessageMaxLength     = 4096
+	terminationMessageLogsMaxLines  = 80
+	terminationMessageLogsMaxLength = 2048
+)
+
+func (adapter *KubeDockerAdapter) terminationMessageFilePath(containerName string) string {
+	return path.Join(adapter.terminationMessagesPath, strings.TrimPrefix(containerName, "/")+".log")
+}
+
+// setTerminationMessageBind mounts an empty file at the termination message path of the container, as the kubelet does,
+// so that the termination message written by the container can be read once it has exited (see readTerminationMessage).
+// The file is stored in the k2d data path, which is expected to be mounted at the same path on the Docker host.
+//
+// Parameters:
+// - containerCfg: The configuration of the container to create.
+// - podSpec: The internal pod spec of the container.
+//
+// Returns:
+// - An error if the termination message file cannot be created.
+func (adapter *KubeDockerAdapter) setTerminationMessageBind(containerCfg *converter.ContainerConfiguration, podSpec core.PodSpec) error {
+	terminationMessagePath := defaultTerminationMessagePath
+	if len(podSpec.Containers) > 0 && podSpec.Containers[0].TerminationMessagePath != "" {
+		terminationMessagePath = podSpec.Containers[0].TerminationMessagePath
+	}
+
+	filePath := adapter.terminationMessageFilePath(containerCfg.ContainerName)
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("unable to create termination message file: %w", err)
+	}
+	file.Close()
+
+	containerCfg.HostConfig.Binds = append(containerCfg.HostConfig.Binds, filePath+":"+terminationMessagePath)
+
+	return nil
+}
+
+// readTerminationMessage returns the termination message written by a container, truncated to the first
+// terminationMessageMaxLength bytes. It returns an empty message if the container did not write any.
+func (adapter *KubeDockerAdapter) readTerminationMessage(containerName string) (string, error) {
+	file, err := os.Open(adapter.terminationMessageFilePath(containerName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("unable to open termination message file: %w", err)
+	}
+	defer file.Close()
+
+	message, err := io.ReadAll(io.LimitReader(file, terminationMessageMaxLength))
+	if err != nil {
+		return "", fmt.Errorf("unable to read termination message file: %w", err)
+	}
+
+	return string(message), nil
+}
+
+// removeTerminationMessage removes the termination message file of a container.
+func (adapter *KubeDockerAdapter) removeTerminationMessage(containerName string) {
+	err := os.Remove(adapter.terminationMessageFilePath(containerName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		adapter.logger.Warnf("unable to remove termination message file of container %s: %s", containerName, err)
+	}
+}
+
+// getTerminationMessagePolicy returns the termination message policy of the container of a pod,
+// defaulting to TerminationMessageReadFile as Kubernetes does.
+func getTerminationMessagePolicy(podSpec *core.PodSpec) core.TerminationMessagePolicy {
+	if podSpec == nil || len(podSpec.Containers) == 0 || podSpec.Containers[0].TerminationMessagePolicy == "" {
+		return core.TerminationMessageReadFile
+	}
+
+	return podSpec.Containers[0].TerminationMessagePolicy
+}
@@ -0,0 +1,128 @@
+package types
+
+const (
+	// DevicesAnnotationKey is the key of the pod annotation used to pass host devices through to the container.
+	// The value is a comma separated list of devices using the Docker --device format
+	// (e.g. /dev/ttyUSB0,/dev/video0:/dev/video0:rw).
+	DevicesAnnotationKey = "container.k2d.io/devices"
+
+	// UlimitsAnnotationKey is the key of the pod annotation used to configure the ulimits of the container.
+	// The value is a comma separated list of ulimits using the Docker --ulimit format
+	// (e.g. nofile=65536:65536,memlock=-1).
+	UlimitsAnnotationKey = "container.k2d.io/ulimits"
+
+	// ShmSizeAnnotationKey is the key of the pod annotation used to configure the size of the /dev/shm mount of the container
+	// using the Docker --shm-size format (e.g. 1g). An emptyDir volume with the Memory medium mounted on /dev/shm takes precedence.
+	ShmSizeAnnotationKey = "container.k2d.io/shm-size"
+
+	// UsernsModeAnnotationKey is the key of the pod annotation used to override the user namespace mode of the container
+	// configured with K2D_USERNS_MODE, using the Docker --userns format (e.g. host).
+	UsernsModeAnnotationKey = "container.k2d.io/userns-mode"
+
+	// LogDriverAnnotationKey is the key of the pod annotation used to override the Docker logging driver of the container
+	// (e.g. local, journald, none).
+	LogDriverAnnotationKey = "container.k2d.io/log-driver"
+
+	// LogMaxSizeAnnotationKey is the key of the pod annotation used to override the maximum size of a log file
+	// before it is rotated (e.g. 50m).
+	LogMaxSizeAnnotationKey = "container.k2d.io/log-max-size"
+
+	// LogMaxFilesAnnotationKey is the key of the pod annotation used to override the maximum number of log files
+	// retained for the container (e.g. 5).
+	LogMaxFilesAnnotationKey = "container.k2d.io/log-max-files"
+
+	// CapAddAnnotationKey is the key of the pod annotation used to add Linux capabilities to the container.
+	// The value is a comma separated list of capabilities using the Docker --cap-add format (e.g. NET_ADMIN,SYS_TIME).
+	CapAddAnnotationKey = "container.k2d.io/cap-add"
+
+	// CapDropAnnotationKey is the key of the pod annotation used to drop Linux capabilities from the container.
+	// The value is a comma separated list of capabilities using the Docker --cap-drop format (e.g. ALL,MKNOD).
+	CapDropAnnotationKey = "container.k2d.io/cap-drop"
+
+	// SecurityOptAnnotationKey is the key of the pod annotation used to configure the security options of the container.
+	// The value is a comma separated list of options using the Docker --security-opt format
+	// (e.g. no-new-privileges,apparmor=unconfined).
+	SecurityOptAnnotationKey = "container.k2d.io/security-opt"
+
+	// NetworkModeAnnotationKey is the key of the pod annotation used to configure the network mode of the container
+	// using the Docker --network format (host, none or container:<name>). The container is not attached to the network
+	// of its namespace when a network mode is specified and therefore cannot be exposed by a service.
+	NetworkModeAnnotationKey = "container.k2d.io/network-mode"
+
+	// LabelsAnnotationKey is the key of the pod annotation used to add Docker labels to the container.
+	// The value is a comma separated list of labels using the Docker --label format (e.g. com.example.team=edge,backup=daily).
+	// The labels used internally by k2d (*.k2d.io/*) cannot be set using this annotation.
+	LabelsAnnotationKey = "container.k2d.io/labels"
+
+	// AutoHostPortsAnnotationKey is the key of the pod annotation used to publish the container ports that do not define
+	// a hostPort on a free port of the host when set to true. The allocated ports are kept when the pod is updated.
+	AutoHostPortsAnnotationKey = "container.k2d.io/auto-host-ports"
+
+	// AllocatedHostPortsAnnotationKey is the key of the pod annotation set by k2d to report the host ports allocated
+	// to a pod annotated with container.k2d.io/auto-host-ports, using the containerPort/protocol=hostPort format
+	// (e.g. 8080/TCP=30080,5353/UDP=31053)
+	AllocatedHostPortsAnnotationKey = "container.k2d.io/allocated-host-ports"
+
+	// EphemeralStorageUsageAnnotationKey is the key of the pod annotation set by k2d to report the size of the writable
+	// layer of the container of a pod as a quantity (e.g. 12Mi), when K2D_EPHEMERAL_STORAGE_USAGE is enabled
+	EphemeralStorageUsageAnnotationKey = "container.k2d.io/ephemeral-storage-usage"
+)
+
+const (
+	// NetworkSubnetAnnotationKey is the key of the namespace annotation used to configure the IPv4 subnet of the network
+	// of the namespace using the CIDR format (e.g. 172.30.0.0/16)
+	NetworkSubnetAnnotationKey = "networking.k2d.io/subnet"
+
+	// NetworkGatewayAnnotationKey is the key of the namespace annotation used to configure the IPv4 gateway of the network
+	// of the namespace (e.g. 172.30.0.1). It requires the networking.k2d.io/subnet annotation.
+	NetworkGatewayAnnotationKey = "networking.k2d.io/gateway"
+
+	// NetworkMTUAnnotationKey is the key of the namespace annotation used to configure the MTU of the bridge
+	// of the network of the namespace (e.g. 1450)
+	NetworkMTUAnnotationKey = "networking.k2d.io/mtu"
+
+	// NetworkInternalAnnotationKey is the key of the namespace annotation used to create an internal network for the namespace
+	// when set to true, restricting the external access of the containers of the namespace (e.g. access to the internet)
+	NetworkInternalAnnotationKey = "networking.k2d.io/internal"
+
+	// NetworkIPv6AnnotationKey is the key of the namespace annotation used to enable IPv6 on the network of the namespace
+	// when set to true
+	NetworkIPv6AnnotationKey = "networking.k2d.io/ipv6"
+
+	// NetworkIPv6SubnetAnnotationKey is the key of the namespace annotation used to configure the IPv6 subnet of the network
+	// of the namespace using the CIDR format (e.g. fd00:dead:beef::/64). It requires the networking.k2d.io/ipv6 annotation.
+	NetworkIPv6SubnetAnnotationKey = "networking.k2d.io/ipv6-subnet"
+)
+
+const (
+	// AdoptedContainerAnnotationKey is the key of the pod annotation recording the name of the Docker container
+	// created outside of k2d that was adopted by the pod
+	AdoptedContainerAnnotationKey = "workload.k2d.io/adopted-container"
+)
+
+const (
+	// CanaryWeightAnnotationKey is the key of the deployment annotation used to roll out a new version of a deployment
+	// as a canary. The value is the weight of the canary between 0 and 100: the canary container is placed behind the
+	// aliases of the service of the deployment when the weight is greater than 0, and the deployment is fully rolled
+	// out when the weight is 100 or when the annotation is removed. The traffic of the service is split evenly between
+	// the canary and the current version for any weight between 1 and 99, as Docker DNS round-robins between them.
+	CanaryWeightAnnotationKey = "k2d.io/canary-weight"
+)
+
+const (
+	// InsecureRegistriesNodeAnnotationKey is the key of the node annotation listing the registries configured as insecure
+	// (comma separated)
+	InsecureRegistriesNodeAnnotationKey = "k2d.io/insecure-registries"
+
+	// RegistryCABundlesNodeAnnotationKey is the key of the node annotation listing the registries configured
+	// with a custom CA bundle (comma separated)
+	RegistryCABundlesNodeAnnotationKey = "k2d.io/registry-ca-bundles"
+
+	// OfflineModeNodeAnnotationKey is the key of the node annotation set to true when k2d loads the images
+	// from image archives (K2D_IMAGE_ARCHIVE_PATH) to run without access to the registries
+	OfflineModeNodeAnnotationKey = "k2d.io/offline-mode"
+
+	// OfflineImagesNodeAnnotationKey is the key of the node annotation listing the images available in the image archives
+	// (comma separated)
+	OfflineImagesNodeAnnotationKey = "k2d.io/offline-images"
+)
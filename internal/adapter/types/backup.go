@@ -0,0 +1,36 @@
+package types
+
+import "time"
+
+// BackupMetadata describes a backup archive of the state of k2d
+type BackupMetadata struct {
+	// Version is the version of k2d that created the backup
+	Version string `json:"version"`
+	// CreationTimestamp is the time at which the backup was created
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+}
+
+// RestoreReport summarizes the restoration of a backup archive
+type RestoreReport struct {
+	// Backup is the metadata of the restored backup
+	Backup BackupMetadata `json:"backup"`
+	// Files is the number of files restored inside the data path
+	Files int `json:"files"`
+	// Namespaces is the number of namespaces created
+	Namespaces int `json:"namespaces"`
+	// Volumes is the number of Docker volumes created
+	Volumes int `json:"volumes"`
+	// ConfigMaps is the number of configmaps created, including the system configmaps
+	ConfigMaps int `json:"configMaps"`
+	// Secrets is the number of secrets created
+	Secrets int `json:"secrets"`
+	// Services is the number of services re-applied
+	Services int `json:"services"`
+	// Skipped is the number of resources that already existed on this host and were left untouched
+	Skipped int `json:"skipped"`
+	// Errors contains the errors encountered while restoring the resources, a failure does not stop the restoration
+	Errors []string `json:"errors,omitempty"`
+	// RestartRequired is true when files of the data path used at startup (e.g. the secret or the TLS certificates)
+	// were restored, they are only used after k2d is restarted
+	RestartRequired bool `json:"restartRequired"`
+}
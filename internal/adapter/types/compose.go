@@ -0,0 +1,23 @@
+package types
+
+const (
+	// ComposeProjectLabelKey is the label set by Docker Compose on the containers of a project, its value is the name of the project
+	ComposeProjectLabelKey = "com.docker.compose.project"
+
+	// ComposeServiceLabelKey is the label set by Docker Compose on the containers of a project, its value is the name of the service
+	ComposeServiceLabelKey = "com.docker.compose.service"
+)
+
+// ComposeImportReport summarizes the import of a Docker Compose project
+type ComposeImportReport struct {
+	// Namespace is the namespace the project was imported into
+	Namespace string `json:"namespace"`
+	// Deployments are the names of the deployments created from the services of the project
+	Deployments []string `json:"deployments"`
+	// Services are the names of the services created for the services of the project publishing ports
+	Services []string `json:"services"`
+	// Skipped are the names of the Compose services that were not imported, with the reason
+	Skipped []string `json:"skipped,omitempty"`
+	// Errors contains the errors encountered while importing the services, a failure does not stop the import
+	Errors []string `json:"errors,omitempty"`
+}
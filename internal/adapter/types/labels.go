@@ -8,17 +8,33 @@ const (
 	// NamespaceNameLabelKey is the key used to store the namespace name associated to a Docker resource in its labels
 	NamespaceNameLabelKey = "resource.k2d.io/namespace-name"
 
+	// OwnerReferencesLabelKey is the key used to store the owner references of a ConfigMap or a Secret (JSON encoded) in its labels
+	// as the stores only persist the labels of the resources. It is used to garbage collect the resources when their owner is deleted.
+	OwnerReferencesLabelKey = "resource.k2d.io/owner-references"
+
 	// PodLastAppliedConfigLabelKey is the key used to store the pod definition in the container labels
 	// It can be used to retrieve the pod definition from a container created via a deployment
 	PodLastAppliedConfigLabelKey = "resource.k2d.io/pod/last-applied-configuration"
 
 	// ServiceLastAppliedConfigLabelKey is the key used to store the service definition associated to a workload in the container labels
 	ServiceLastAppliedConfigLabelKey = "resource.k2d.io/service/last-applied-configuration"
+
+	// PayloadReferencePrefix is the prefix of the value of a last applied configuration label when the configuration is stored
+	// in the store backend instead of the container labels. The prefix is followed by the SHA-256 digest of the configuration
+	// (e.g. store-ref:sha256:9f86d08...)
+	PayloadReferencePrefix = "store-ref:sha256:"
 )
 
 const (
 	// NetworkNameLabelKey is the key used to store the network name in the container labels
 	NetworkNameLabelKey = "networking.k2d.io/network-name"
+
+	// NetworkPolicyNameLabelKey is the key used to store the name of the network policy approximated by a Docker network in its labels
+	NetworkPolicyNameLabelKey = "networking.k2d.io/network-policy-name"
+
+	// NetworkPolicyNamespaceLabelKey is the key used to store the namespace of the network policy approximated by a Docker network in its labels.
+	// The namespace name label is not used as it identifies the networks of the namespaces.
+	NetworkPolicyNamespaceLabelKey = "networking.k2d.io/network-policy-namespace"
 )
 
 const (
@@ -35,6 +51,14 @@ const (
 	// StorageTypeLabelKey is the key used to store the storage type in the labels of a system configmap or a Docker volume
 	// It is used to differentiate between persistent volumes and config maps when listing volumes
 	StorageTypeLabelKey = "storage.k2d.io/type"
+
+	// StorageClassNameLabelKey is the key used to store the name of the storage class used to provision a Docker volume
+	// in the labels of the volume and of the system configmap of the associated persistent volume claim
+	StorageClassNameLabelKey = "storage.k2d.io/storage-class"
+
+	// ReclaimPolicyLabelKey is the key used to store the reclaim policy (Delete or Retain) of a persistent volume in the labels of a Docker volume
+	// It is copied from the storage class when the volume is provisioned
+	ReclaimPolicyLabelKey = "storage.k2d.io/reclaim-policy"
 )
 
 const (
@@ -47,15 +71,26 @@ const (
 	// ServiceNameLabelKey is the key used to store the service name associated to the workload in the container labels
 	ServiceNameLabelKey = "workload.k2d.io/service-name"
 
+	// ServiceBackendLabelKey is the key used to store, in the container labels, the name of the service for which the workload is an additional backend
+	// The aliases of a service are attached to all the containers matching its selector, but only the container labelled with ServiceNameLabelKey publishes its ports
+	ServiceBackendLabelKey = "workload.k2d.io/service-backend"
+
 	// WorkloadTypeLabelKey is the key used to store the workload type in the container labels
 	WorkloadTypeLabelKey = "workload.k2d.io/type"
 
 	// WorkloadNameLabelKey is the key used to store the workload name in the container labels
 	WorkloadNameLabelKey = "workload.k2d.io/name"
+
+	// CanaryOfLabelKey is the key used to store the name of the deployment a canary container belongs to in the container labels
+	CanaryOfLabelKey = "workload.k2d.io/canary-of"
 )
 
 const (
 	// DeploymentWorkloadType is the label value used to identify a Deployment workload
 	// It is stored on a container as a label and used to filter containers when listing deployments
 	DeploymentWorkloadType = "deployment"
+
+	// PodWorkloadType is the label value used to identify a Pod workload
+	// It is stored on the system configmap used to store the desired state of a pod
+	PodWorkloadType = "pod"
 )
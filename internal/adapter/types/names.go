@@ -4,6 +4,10 @@ const (
 	// K2DNamespaceName is the name of the namespace where k2d resources are stored
 	K2DNamespaceName = "k2d"
 
+	// NodeLeaseNamespaceName is the name of the namespace where the heartbeat lease of the node is stored,
+	// as in Kubernetes where each kubelet renews a lease named after its node in this namespace
+	NodeLeaseNamespaceName = "kube-node-lease"
+
 	// K2dServiceAccountSecretName is the name of the secret used to store the system service account token and CA
 	// certificate. This secret contains everything needed to authenticate with the Kubernetes API server.
 	K2dServiceAccountSecretName = "k2d-serviceaccount"
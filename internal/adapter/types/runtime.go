@@ -0,0 +1,36 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ContainerRuntime represents the container runtime exposing the Docker API used by k2d
+// The only behavior specific to Podman is the use of fully qualified image names when creating containers.
+// The restart policies, the network aliases and the copy container used by the volume store rely on
+// the same Docker API calls for both runtimes and are not adjusted.
+type ContainerRuntime string
+
+const (
+	// DockerContainerRuntime is the runtime used when k2d is connected to a Docker engine
+	DockerContainerRuntime ContainerRuntime = "docker"
+
+	// PodmanContainerRuntime is the runtime used when k2d is connected to the Docker-compatible API of Podman
+	PodmanContainerRuntime ContainerRuntime = "podman"
+)
+
+// ContainerRuntimeNodeLabelKey is the key of the node label used to expose the container runtime
+const ContainerRuntimeNodeLabelKey = "k2d.io/container-runtime"
+
+// DetectContainerRuntime returns the container runtime associated with the specified server version.
+// Podman reports a "Podman Engine" component in its version information, any other runtime is considered to be Docker.
+func DetectContainerRuntime(version types.Version) ContainerRuntime {
+	for _, component := range version.Components {
+		if strings.Contains(strings.ToLower(component.Name), string(PodmanContainerRuntime)) {
+			return PodmanContainerRuntime
+		}
+	}
+
+	return DockerContainerRuntime
+}
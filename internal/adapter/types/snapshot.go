@@ -0,0 +1,30 @@
+package types
+
+import "time"
+
+// VolumeSnapshot represents a snapshot of the content of a Docker volume, stored as a tar archive in the k2d data path
+type VolumeSnapshot struct {
+	// Name is the name of the snapshot
+	Name string `json:"name"`
+	// VolumeName is the name of the Docker volume the snapshot was taken from
+	VolumeName string `json:"volumeName"`
+	// Size is the size of the tar archive in bytes
+	Size int64 `json:"size"`
+	// CreationTimestamp is the time at which the snapshot was taken
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+}
+
+// ContainerCheckpoint represents a checkpoint of the processes of the container of a pod, taken through the Docker checkpoint API (CRIU)
+// and stored in the k2d data path
+type ContainerCheckpoint struct {
+	// Name is the name of the checkpoint
+	Name string `json:"name"`
+	// Namespace is the namespace of the pod the checkpoint was taken from
+	Namespace string `json:"namespace"`
+	// PodName is the name of the pod the checkpoint was taken from
+	PodName string `json:"podName"`
+	// Image is the image of the container the checkpoint was taken from, a checkpoint can only be restored in a container using the same image
+	Image string `json:"image"`
+	// CreationTimestamp is the time at which the checkpoint was taken
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+}
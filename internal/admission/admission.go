@@ -0,0 +1,300 @@
+package admission
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/portainer/k2d/pkg/jsonpatch"
+	"go.uber.org/zap"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+const (
+	// FailurePolicyFail rejects the resources when a webhook cannot be called or returns an invalid response
+	FailurePolicyFail = "Fail"
+	// FailurePolicyIgnore admits the resources when a webhook cannot be called or returns an invalid response
+	FailurePolicyIgnore = "Ignore"
+)
+
+type (
+	// ReviewerOptions represents options that can be used to configure a new Reviewer
+	ReviewerOptions struct {
+		// Logger is the logger that will be used by the reviewer
+		Logger *zap.SugaredLogger
+		// MutatingWebhookURLs are the URLs of the mutating webhooks, called in order
+		MutatingWebhookURLs []string
+		// ValidatingWebhookURLs are the URLs of the validating webhooks, called after the mutating webhooks
+		ValidatingWebhookURLs []string
+		// Timeout is the timeout applied to each webhook call
+		Timeout time.Duration
+		// FailurePolicy defines how the webhook call errors are handled, either FailurePolicyFail or FailurePolicyIgnore
+		FailurePolicy string
+		// CAPath is the path of a PEM encoded CA bundle used to verify the certificates of the webhooks, optional
+		CAPath string
+	}
+
+	// Reviewer submits the resources to external admission webhooks (e.g. OPA Gatekeeper or Kyverno running as sidecars)
+	// using the AdmissionReview API of Kubernetes. The webhooks are configured statically, the Kubernetes
+	// MutatingWebhookConfiguration and ValidatingWebhookConfiguration resources are not supported.
+	Reviewer struct {
+		logger                *zap.SugaredLogger
+		client                *http.Client
+		mutatingWebhookURLs   []string
+		validatingWebhookURLs []string
+		failurePolicy         string
+	}
+
+	// resourceAttributes describes the resource submitted to the webhooks
+	resourceAttributes struct {
+		kind      schema.GroupVersionKind
+		resource  schema.GroupVersionResource
+		namespace string
+		name      string
+	}
+)
+
+// NewReviewer returns a Reviewer calling the configured webhooks.
+// It returns an error if the failure policy is invalid or if the CA bundle cannot be loaded.
+func NewReviewer(options *ReviewerOptions) (*Reviewer, error) {
+	if options.FailurePolicy != FailurePolicyFail && options.FailurePolicy != FailurePolicyIgnore {
+		return nil, fmt.Errorf("invalid admission webhook failure policy %q, expected %s or %s", options.FailurePolicy, FailurePolicyFail, FailurePolicyIgnore)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if options.CAPath != "" {
+		caBundle, err := os.ReadFile(options.CAPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read admission webhook CA bundle: %w", err)
+		}
+
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("no valid certificate found in admission webhook CA bundle %s", options.CAPath)
+		}
+
+		transport.TLSClientConfig = &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &Reviewer{
+		logger:                options.Logger,
+		client:                &http.Client{Timeout: options.Timeout, Transport: transport},
+		mutatingWebhookURLs:   options.MutatingWebhookURLs,
+		validatingWebhookURLs: options.ValidatingWebhookURLs,
+		failurePolicy:         options.FailurePolicy,
+	}, nil
+}
+
+// Review submits a resource to the mutating webhooks, then to the validating webhooks.
+// The patches returned by the mutating webhooks are applied to the resource in place, each webhook receiving the
+// resource mutated by the previous ones. k2d applies the resources declaratively, so each review is sent as a CREATE operation.
+// The resources that are not supported by the operation controller are not reviewed.
+//
+// Parameters:
+// - ctx: The context within which the function operates.
+// - object: A pointer to the versioned resource (e.g. *appsv1.Deployment).
+//
+// Returns:
+//   - A Forbidden API error if a webhook denies the resource.
+//   - An error if a webhook cannot be called or returns an invalid response and the failure policy is Fail.
+func (reviewer *Reviewer) Review(ctx context.Context, object interface{}) error {
+	attributes, ok := getResourceAttributes(object)
+	if !ok {
+		return nil
+	}
+
+	for _, url := range reviewer.mutatingWebhookURLs {
+		err := reviewer.callWebhook(ctx, url, attributes, object, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, url := range reviewer.validatingWebhookURLs {
+		err := reviewer.callWebhook(ctx, url, attributes, object, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// callWebhook sends an AdmissionReview to a webhook and handles its response according to the failure policy.
+func (reviewer *Reviewer) callWebhook(ctx context.Context, url string, attributes resourceAttributes, object interface{}, mutating bool) error {
+	response, err := reviewer.sendReview(ctx, url, attributes, object)
+	if err != nil {
+		if reviewer.failurePolicy == FailurePolicyIgnore {
+			reviewer.logger.Warnw("unable to call admission webhook, ignoring",
+				"url", url,
+				"error", err,
+			)
+			return nil
+		}
+
+		return fmt.Errorf("unable to call admission webhook %s: %w", url, err)
+	}
+
+	if !response.Allowed {
+		message := "denied by admission webhook"
+		if response.Result != nil && response.Result.Message != "" {
+			message = response.Result.Message
+		}
+
+		return apierr.NewForbidden(attributes.resource.GroupResource(), attributes.name, fmt.Errorf("admission webhook %s denied the request: %s", url, message))
+	}
+
+	// the validating webhooks cannot mutate the resource
+	if !mutating || len(response.Patch) == 0 {
+		return nil
+	}
+
+	if response.PatchType == nil || *response.PatchType != admissionv1.PatchTypeJSONPatch {
+		return fmt.Errorf("admission webhook %s returned an unsupported patch type", url)
+	}
+
+	return applyPatch(object, response.Patch)
+}
+
+// sendReview sends an AdmissionReview describing the resource to a webhook and returns the response of the webhook.
+func (reviewer *Reviewer) sendReview(ctx context.Context, url string, attributes resourceAttributes, object interface{}) (*admissionv1.AdmissionResponse, error) {
+	objectData, err := json.Marshal(object)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal resource: %w", err)
+	}
+
+	dryRun := false
+	kind := metav1.GroupVersionKind(attributes.kind)
+	resource := metav1.GroupVersionResource(attributes.resource)
+
+	review := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "AdmissionReview",
+			APIVersion: "admission.k8s.io/v1",
+		},
+		Request: &admissionv1.AdmissionRequest{
+			UID:             uuid.NewUUID(),
+			Kind:            kind,
+			Resource:        resource,
+			RequestKind:     &kind,
+			RequestResource: &resource,
+			Name:            attributes.name,
+			Namespace:       attributes.namespace,
+			Operation:       admissionv1.Create,
+			Object:          runtime.RawExtension{Raw: objectData},
+			DryRun:          &dryRun,
+		},
+	}
+
+	reviewData, err := json.Marshal(review)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal admission review: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reviewData))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create admission review request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := reviewer.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("admission webhook returned status code %d", response.StatusCode)
+	}
+
+	reviewResponse := admissionv1.AdmissionReview{}
+	err = json.NewDecoder(response.Body).Decode(&reviewResponse)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode admission review response: %w", err)
+	}
+
+	if reviewResponse.Response == nil {
+		return nil, fmt.Errorf("admission review response is empty")
+	}
+
+	if reviewResponse.Response.UID != review.Request.UID {
+		return nil, fmt.Errorf("admission review response UID %q does not match request UID %q", reviewResponse.Response.UID, review.Request.UID)
+	}
+
+	return reviewResponse.Response, nil
+}
+
+// applyPatch applies a JSON patch to a resource in place.
+// The resource is reset before decoding the patched resource so that the fields removed by the patch are not kept.
+func applyPatch(object interface{}, patch []byte) error {
+	objectData, err := json.Marshal(object)
+	if err != nil {
+		return fmt.Errorf("unable to marshal resource: %w", err)
+	}
+
+	patchedData, err := jsonpatch.Apply(objectData, patch)
+	if err != nil {
+		return fmt.Errorf("unable to apply admission webhook patch: %w", err)
+	}
+
+	value := reflect.ValueOf(object).Elem()
+	value.Set(reflect.Zero(value.Type()))
+
+	err = json.Unmarshal(patchedData, object)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal patched resource: %w", err)
+	}
+
+	return nil
+}
+
+// getResourceAttributes returns the attributes of the resources supported by the operation controller.
+func getResourceAttributes(object interface{}) (resourceAttributes, bool) {
+	switch resource := object.(type) {
+	case *corev1.Pod:
+		return newCoreResourceAttributes("Pod", "pods", resource.ObjectMeta), true
+	case *corev1.Service:
+		return newCoreResourceAttributes("Service", "services", resource.ObjectMeta), true
+	case *corev1.ConfigMap:
+		return newCoreResourceAttributes("ConfigMap", "configmaps", resource.ObjectMeta), true
+	case *corev1.Secret:
+		return newCoreResourceAttributes("Secret", "secrets", resource.ObjectMeta), true
+	case *corev1.PersistentVolumeClaim:
+		return newCoreResourceAttributes("PersistentVolumeClaim", "persistentvolumeclaims", resource.ObjectMeta), true
+	case *corev1.Namespace:
+		return newCoreResourceAttributes("Namespace", "namespaces", resource.ObjectMeta), true
+	case *appsv1.Deployment:
+		return resourceAttributes{
+			kind:      appsv1.SchemeGroupVersion.WithKind("Deployment"),
+			resource:  appsv1.SchemeGroupVersion.WithResource("deployments"),
+			namespace: resource.Namespace,
+			name:      resource.Name,
+		}, true
+	}
+
+	return resourceAttributes{}, false
+}
+
+func newCoreResourceAttributes(kind, resource string, objectMeta metav1.ObjectMeta) resourceAttributes {
+	return resourceAttributes{
+		kind:      corev1.SchemeGroupVersion.WithKind(kind),
+		resource:  corev1.SchemeGroupVersion.WithResource(resource),
+		namespace: objectMeta.Namespace,
+		name:      objectMeta.Name,
+	}
+}
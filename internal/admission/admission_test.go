@@ -0,0 +1,184 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newWebhook starts a webhook answering each review with the response built by the handler.
+func newWebhook(t *testing.T, handler func(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		review := admissionv1.AdmissionReview{}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		response := handler(review.Request)
+		response.UID = review.Request.UID
+		review.Response = response
+		review.Request = nil
+
+		json.NewEncoder(w).Encode(review)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newTestReviewer(t *testing.T, failurePolicy string, mutating, validating []string) *Reviewer {
+	t.Helper()
+
+	reviewer, err := NewReviewer(&ReviewerOptions{
+		Logger:                zap.NewNop().Sugar(),
+		MutatingWebhookURLs:   mutating,
+		ValidatingWebhookURLs: validating,
+		Timeout:               time.Second,
+		FailurePolicy:         failurePolicy,
+	})
+	if err != nil {
+		t.Fatalf("unable to create reviewer: %s", err)
+	}
+
+	return reviewer
+}
+
+func newTestConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	}
+}
+
+func TestReviewAllowsResource(t *testing.T) {
+	var received *admissionv1.AdmissionRequest
+	webhook := newWebhook(t, func(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		received = request
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	})
+
+	reviewer := newTestReviewer(t, FailurePolicyFail, nil, []string{webhook.URL})
+
+	err := reviewer.Review(context.Background(), newTestConfigMap())
+	if err != nil {
+		t.Fatalf("expected the resource to be admitted, got %s", err)
+	}
+
+	if received == nil {
+		t.Fatal("expected the webhook to be called")
+	}
+
+	if received.Kind.Kind != "ConfigMap" || received.Resource.Resource != "configmaps" {
+		t.Errorf("unexpected kind or resource: %v %v", received.Kind, received.Resource)
+	}
+
+	if received.Namespace != "default" || received.Name != "settings" || received.Operation != admissionv1.Create {
+		t.Errorf("unexpected request attributes: %s/%s %s", received.Namespace, received.Name, received.Operation)
+	}
+}
+
+func TestReviewDeniedResourceIsForbidden(t *testing.T) {
+	webhook := newWebhook(t, func(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &metav1.Status{Message: "configmaps must be labelled"},
+		}
+	})
+
+	reviewer := newTestReviewer(t, FailurePolicyFail, nil, []string{webhook.URL})
+
+	err := reviewer.Review(context.Background(), newTestConfigMap())
+	if !apierr.IsForbidden(err) {
+		t.Fatalf("expected a forbidden error, got %v", err)
+	}
+}
+
+func TestReviewAppliesMutatingPatch(t *testing.T) {
+	patchType := admissionv1.PatchTypeJSONPatch
+	mutating := newWebhook(t, func(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		return &admissionv1.AdmissionResponse{
+			Allowed:   true,
+			PatchType: &patchType,
+			Patch:     []byte(`[{"op":"add","path":"/metadata/labels","value":{"team":"edge"}},{"op":"remove","path":"/data/key"}]`),
+		}
+	})
+
+	var validated map[string]string
+	validating := newWebhook(t, func(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		configMap := corev1.ConfigMap{}
+		json.Unmarshal(request.Object.Raw, &configMap)
+		validated = configMap.Labels
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	})
+
+	reviewer := newTestReviewer(t, FailurePolicyFail, []string{mutating.URL}, []string{validating.URL})
+
+	configMap := newTestConfigMap()
+	err := reviewer.Review(context.Background(), configMap)
+	if err != nil {
+		t.Fatalf("expected the resource to be admitted, got %s", err)
+	}
+
+	if configMap.Labels["team"] != "edge" {
+		t.Errorf("expected the label added by the mutating webhook, got %v", configMap.Labels)
+	}
+
+	if _, ok := configMap.Data["key"]; ok {
+		t.Errorf("expected the data key removed by the mutating webhook to be removed, got %v", configMap.Data)
+	}
+
+	if validated["team"] != "edge" {
+		t.Errorf("expected the validating webhook to receive the mutated resource, got %v", validated)
+	}
+}
+
+func TestReviewFailurePolicy(t *testing.T) {
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+
+	err := newTestReviewer(t, FailurePolicyFail, nil, []string{unavailable.URL}).Review(context.Background(), newTestConfigMap())
+	if err == nil {
+		t.Error("expected an error with the Fail failure policy")
+	}
+
+	err = newTestReviewer(t, FailurePolicyIgnore, nil, []string{unavailable.URL}).Review(context.Background(), newTestConfigMap())
+	if err != nil {
+		t.Errorf("expected the resource to be admitted with the Ignore failure policy, got %s", err)
+	}
+}
+
+func TestReviewIgnoresUnsupportedResources(t *testing.T) {
+	called := false
+	webhook := newWebhook(t, func(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		called = true
+		return &admissionv1.AdmissionResponse{Allowed: false}
+	})
+
+	reviewer := newTestReviewer(t, FailurePolicyFail, nil, []string{webhook.URL})
+
+	err := reviewer.Review(context.Background(), &corev1.Node{})
+	if err != nil || called {
+		t.Errorf("expected unsupported resources not to be reviewed, got error %v, called %t", err, called)
+	}
+}
+
+func TestNewReviewerRejectsInvalidFailurePolicy(t *testing.T) {
+	_, err := NewReviewer(&ReviewerOptions{FailurePolicy: "Retry"})
+	if err == nil {
+		t.Error("expected an error for an invalid failure policy")
+	}
+}
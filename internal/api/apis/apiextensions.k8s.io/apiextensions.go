@@ -0,0 +1,56 @@
+package apiextensions
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/apis/apiextensions.k8s.io/customresourcedefinitions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ApiExtensionsService struct {
+	customResourceDefinitions customresourcedefinitions.CustomResourceDefinitionService
+}
+
+func NewApiExtensionsService(adapter *adapter.KubeDockerAdapter) ApiExtensionsService {
+	return ApiExtensionsService{
+		customResourceDefinitions: customresourcedefinitions.NewCustomResourceDefinitionService(adapter),
+	}
+}
+
+func (svc ApiExtensionsService) GetAPIVersions(r *restful.Request, w *restful.Response) {
+	apiVersion := metav1.APIVersions{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "APIVersions",
+		},
+		Versions: []string{"apiextensions.k8s.io/v1"},
+	}
+
+	w.WriteAsJson(apiVersion)
+}
+
+func (svc ApiExtensionsService) ListAPIResources(r *restful.Request, w *restful.Response) {
+	resourceList := metav1.APIResourceList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "APIResourceList",
+			APIVersion: "v1",
+		},
+		GroupVersion: "apiextensions.k8s.io/v1",
+		APIResources: []metav1.APIResource{
+			{
+				Kind:         "CustomResourceDefinition",
+				SingularName: "",
+				Name:         "customresourcedefinitions",
+				ShortNames:   []string{"crd", "crds"},
+				Verbs:        []string{"create", "list", "delete", "get", "patch", "update"},
+				Namespaced:   false,
+			},
+		},
+	}
+
+	w.WriteAsJson(resourceList)
+}
+
+func (svc ApiExtensionsService) RegisterApiExtensionsAPI(routes *restful.WebService) {
+	// customresourcedefinitions
+	svc.customResourceDefinitions.RegisterCustomResourceDefinitionAPI(routes)
+}
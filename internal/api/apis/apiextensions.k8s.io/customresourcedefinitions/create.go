@@ -0,0 +1,41 @@
+package customresourcedefinitions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	httputils "github.com/portainer/k2d/pkg/http"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func (svc CustomResourceDefinitionService) CreateCustomResourceDefinition(r *restful.Request, w *restful.Response) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	err := httputils.ParseJSONBody(r.Request, &crd)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(crd)
+		return
+	}
+
+	err = svc.adapter.CreateCustomResourceDefinition(r.Request.Context(), crd)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrInvalidResource) {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid custom resource definition: %w", err))
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create custom resource definition: %w", err))
+		return
+	}
+
+	w.WriteAsJson(crd)
+}
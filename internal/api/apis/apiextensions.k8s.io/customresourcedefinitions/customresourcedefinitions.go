@@ -0,0 +1,50 @@
+package customresourcedefinitions
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+)
+
+type CustomResourceDefinitionService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+func NewCustomResourceDefinitionService(adapter *adapter.KubeDockerAdapter) CustomResourceDefinitionService {
+	return CustomResourceDefinitionService{
+		adapter: adapter,
+	}
+}
+
+func (svc CustomResourceDefinitionService) RegisterCustomResourceDefinitionAPI(ws *restful.WebService) {
+	customResourceDefinitionGVKExtension := map[string]string{
+		"group":   "apiextensions.k8s.io",
+		"kind":    "CustomResourceDefinition",
+		"version": "v1",
+	}
+
+	ws.Route(ws.POST("/v1/customresourcedefinitions").
+		To(svc.CreateCustomResourceDefinition).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+	ws.Route(ws.GET("/v1/customresourcedefinitions").
+		To(svc.ListCustomResourceDefinitions))
+
+	ws.Route(ws.GET("/v1/customresourcedefinitions/{name}").
+		To(svc.GetCustomResourceDefinition).
+		Param(ws.PathParameter("name", "name of the custom resource definition").DataType("string")))
+
+	ws.Route(ws.DELETE("/v1/customresourcedefinitions/{name}").
+		To(svc.DeleteCustomResourceDefinition).
+		Param(ws.PathParameter("name", "name of the custom resource definition").DataType("string")))
+
+	ws.Route(ws.PUT("/v1/customresourcedefinitions/{name}").
+		To(svc.PutCustomResourceDefinition).
+		Param(ws.PathParameter("name", "name of the custom resource definition").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+	ws.Route(ws.PATCH("/v1/customresourcedefinitions/{name}").
+		To(svc.PatchCustomResourceDefinition).
+		Param(ws.PathParameter("name", "name of the custom resource definition").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")).
+		AddExtension("x-kubernetes-group-version-kind", customResourceDefinitionGVKExtension))
+}
@@ -0,0 +1,37 @@
+package customresourcedefinitions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc CustomResourceDefinitionService) DeleteCustomResourceDefinition(r *restful.Request, w *restful.Response) {
+	crdName := r.PathParameter("name")
+
+	err := svc.adapter.DeleteCustomResourceDefinition(r.Request.Context(), crdName)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}, crdName)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to delete custom resource definition: %w", err))
+		return
+	}
+
+	w.WriteAsJson(metav1.Status{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Status",
+			APIVersion: "v1",
+		},
+		Status: "Success",
+		Code:   http.StatusOK,
+	})
+}
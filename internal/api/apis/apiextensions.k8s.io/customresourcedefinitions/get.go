@@ -0,0 +1,29 @@
+package customresourcedefinitions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc CustomResourceDefinitionService) GetCustomResourceDefinition(r *restful.Request, w *restful.Response) {
+	crdName := r.PathParameter("name")
+
+	crd, err := svc.adapter.GetCustomResourceDefinition(r.Request.Context(), crdName)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}, crdName)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get custom resource definition: %w", err))
+		return
+	}
+
+	w.WriteAsJson(crd)
+}
@@ -0,0 +1,80 @@
+package customresourcedefinitions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc CustomResourceDefinitionService) PatchCustomResourceDefinition(r *restful.Request, w *restful.Response) {
+	crdName := r.PathParameter("name")
+
+	patch, err := io.ReadAll(r.Request.Body)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	crd, err := svc.adapter.GetCustomResourceDefinition(r.Request.Context(), crdName)
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get custom resource definition: %w", err))
+		return
+	}
+
+	if crd == nil && !utils.IsServerSideApply(r) {
+		utils.ResourceNotFound(w, schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}, crdName)
+		return
+	}
+
+	var data []byte
+	if crd != nil {
+		data, err = json.Marshal(crd)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal custom resource definition: %w", err))
+			return
+		}
+	}
+
+	mergedData, err := utils.PatchResource(r, data, patch, apiextensionsv1.CustomResourceDefinition{})
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
+		return
+	}
+
+	updatedCRD := &apiextensionsv1.CustomResourceDefinition{}
+
+	err = json.Unmarshal(mergedData, updatedCRD)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to unmarshal custom resource definition: %w", err))
+		return
+	}
+
+	updatedCRD.Name = crdName
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(updatedCRD)
+		return
+	}
+
+	err = svc.adapter.CreateCustomResourceDefinition(r.Request.Context(), updatedCRD)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrInvalidResource) {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid custom resource definition: %w", err))
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to patch custom resource definition: %w", err))
+		return
+	}
+
+	w.WriteAsJson(updatedCRD)
+}
@@ -1,17 +1,30 @@
 package apis
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func ListAPIGroups(r *restful.Request, w *restful.Response) {
+func (api ApisAPI) ListAPIGroups(r *restful.Request, w *restful.Response) {
 	groupList := metav1.APIGroupList{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "APIGroupList",
 			APIVersion: "v1",
 		},
 		Groups: []metav1.APIGroup{
+			{
+				Name: "apiextensions.k8s.io",
+				Versions: []metav1.GroupVersionForDiscovery{
+					{
+						GroupVersion: "apiextensions.k8s.io/v1",
+						Version:      "v1",
+					},
+				},
+			},
 			{
 				Name: "apps",
 				Versions: []metav1.GroupVersionForDiscovery{
@@ -21,6 +34,24 @@ func ListAPIGroups(r *restful.Request, w *restful.Response) {
 					},
 				},
 			},
+			{
+				Name: "autoscaling",
+				Versions: []metav1.GroupVersionForDiscovery{
+					{
+						GroupVersion: "autoscaling/v2",
+						Version:      "v2",
+					},
+				},
+			},
+			{
+				Name: "coordination.k8s.io",
+				Versions: []metav1.GroupVersionForDiscovery{
+					{
+						GroupVersion: "coordination.k8s.io/v1",
+						Version:      "v1",
+					},
+				},
+			},
 			{
 				Name: "events.k8s.io",
 				Versions: []metav1.GroupVersionForDiscovery{
@@ -39,6 +70,24 @@ func ListAPIGroups(r *restful.Request, w *restful.Response) {
 					},
 				},
 			},
+			{
+				Name: "flowcontrol.apiserver.k8s.io",
+				Versions: []metav1.GroupVersionForDiscovery{
+					{
+						GroupVersion: "flowcontrol.apiserver.k8s.io/v1beta3",
+						Version:      "v1beta3",
+					},
+				},
+			},
+			{
+				Name: "networking.k8s.io",
+				Versions: []metav1.GroupVersionForDiscovery{
+					{
+						GroupVersion: "networking.k8s.io/v1",
+						Version:      "v1",
+					},
+				},
+			},
 			{
 				Name: "storage.k8s.io",
 				Versions: []metav1.GroupVersionForDiscovery{
@@ -51,5 +100,12 @@ func ListAPIGroups(r *restful.Request, w *restful.Response) {
 		},
 	}
 
+	customResourceGroups, err := api.customResources.ListAPIGroups(r.Request.Context())
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to list custom resource API groups: %w", err))
+		return
+	}
+	groupList.Groups = append(groupList.Groups, customResourceGroups...)
+
 	w.WriteAsJson(groupList)
 }
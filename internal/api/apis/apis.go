@@ -3,42 +3,82 @@ package apis
 import (
 	restful "github.com/emicklei/go-restful/v3"
 	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/apis/apiextensions.k8s.io"
 	"github.com/portainer/k2d/internal/api/apis/apps"
 	"github.com/portainer/k2d/internal/api/apis/authorization.k8s.io"
+	"github.com/portainer/k2d/internal/api/apis/autoscaling"
+	"github.com/portainer/k2d/internal/api/apis/coordination.k8s.io"
+	"github.com/portainer/k2d/internal/api/apis/customresources"
 	"github.com/portainer/k2d/internal/api/apis/events.k8s.io"
+	"github.com/portainer/k2d/internal/api/apis/flowcontrol.apiserver.k8s.io"
+	"github.com/portainer/k2d/internal/api/apis/networking.k8s.io"
 	"github.com/portainer/k2d/internal/api/apis/storage.k8s.io"
+	"github.com/portainer/k2d/internal/api/utils"
 	"github.com/portainer/k2d/internal/controller"
 )
 
 type (
 	ApisAPI struct {
-		apps          apps.AppsService
-		events        events.EventsService
-		authorization authorization.AuthorizationService
-		storage       storage.StorageService
+		apiExtensions   apiextensions.ApiExtensionsService
+		apps            apps.AppsService
+		autoscaling     autoscaling.AutoscalingService
+		coordination    coordination.CoordinationService
+		customResources customresources.CustomResourceService
+		events          events.EventsService
+		authorization   authorization.AuthorizationService
+		flowcontrol     flowcontrol.FlowControlService
+		networking      networking.NetworkingService
+		storage         storage.StorageService
 	}
 )
 
 func NewApisAPI(adapter *adapter.KubeDockerAdapter, operations chan controller.Operation) *ApisAPI {
 	return &ApisAPI{
-		apps:          apps.NewAppsService(operations, adapter),
-		events:        events.NewEventsService(adapter),
-		authorization: authorization.NewAuthorizationService(),
-		storage:       storage.NewStorageService(adapter),
+		apiExtensions:   apiextensions.NewApiExtensionsService(adapter),
+		apps:            apps.NewAppsService(operations, adapter),
+		autoscaling:     autoscaling.NewAutoscalingService(adapter),
+		coordination:    coordination.NewCoordinationService(adapter),
+		customResources: customresources.NewCustomResourceService(adapter),
+		events:          events.NewEventsService(adapter),
+		authorization:   authorization.NewAuthorizationService(),
+		flowcontrol:     flowcontrol.NewFlowControlService(),
+		networking:      networking.NewNetworkingService(adapter),
+		storage:         storage.NewStorageService(adapter),
 	}
 }
 
 // /apis
 // Used by Kubernetes clients to discover available APIs
+// The custom resources are also served under this path, as their groups are not known in advance
 func (api ApisAPI) APIs() *restful.WebService {
 	routes := new(restful.WebService).
 		Path("/apis").
-		Consumes(restful.MIME_JSON).
+		Consumes(restful.MIME_JSON, "application/yml", "application/json-patch+json", "application/merge-patch+json", "application/strategic-merge-patch+json", utils.ApplyPatchMIME).
+		Produces(restful.MIME_JSON)
+
+	routes.Route(routes.GET("").
+		To(api.ListAPIGroups))
+
+	api.customResources.RegisterCustomResourceAPI(routes)
+	return routes
+}
+
+// /apis/apiextensions.k8s.io
+func (api ApisAPI) ApiExtensions() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/apis/apiextensions.k8s.io").
+		Consumes(restful.MIME_JSON, "application/yml", "application/json-patch+json", "application/merge-patch+json", "application/strategic-merge-patch+json", utils.ApplyPatchMIME).
 		Produces(restful.MIME_JSON)
 
+	// which versions are served by this api
 	routes.Route(routes.GET("").
-		To(ListAPIGroups))
+		To(api.apiExtensions.GetAPIVersions))
 
+	// which resources are available under /apis/apiextensions.k8s.io/v1
+	routes.Route(routes.GET("/v1").
+		To(api.apiExtensions.ListAPIResources))
+
+	api.apiExtensions.RegisterApiExtensionsAPI(routes)
 	return routes
 }
 
@@ -102,7 +142,7 @@ func (api ApisAPI) Authorization() *restful.WebService {
 func (api ApisAPI) Apps() *restful.WebService {
 	routes := new(restful.WebService).
 		Path("/apis/apps").
-		Consumes(restful.MIME_JSON, "application/yml", "application/json-patch+json", "application/merge-patch+json", "application/strategic-merge-patch+json").
+		Consumes(restful.MIME_JSON, "application/yml", "application/json-patch+json", "application/merge-patch+json", "application/strategic-merge-patch+json", utils.ApplyPatchMIME).
 		Produces(restful.MIME_JSON)
 
 	// which versions are served by this api
@@ -116,3 +156,77 @@ func (api ApisAPI) Apps() *restful.WebService {
 	api.apps.RegisterAppsAPI(routes)
 	return routes
 }
+
+// /apis/autoscaling
+func (api ApisAPI) Autoscaling() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/apis/autoscaling").
+		Consumes(restful.MIME_JSON, "application/yml", "application/json-patch+json", "application/merge-patch+json", "application/strategic-merge-patch+json", utils.ApplyPatchMIME).
+		Produces(restful.MIME_JSON)
+
+	// which versions are served by this api
+	routes.Route(routes.GET("").
+		To(api.autoscaling.GetAPIVersions))
+
+	// which resources are available under /apis/autoscaling/v2
+	routes.Route(routes.GET("/v2").
+		To(api.autoscaling.ListAPIResources))
+
+	api.autoscaling.RegisterAutoscalingAPI(routes)
+	return routes
+}
+
+// /apis/coordination.k8s.io
+func (api ApisAPI) Coordination() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/apis/coordination.k8s.io").
+		Consumes(restful.MIME_JSON, "application/yml", "application/json-patch+json", "application/merge-patch+json", "application/strategic-merge-patch+json", utils.ApplyPatchMIME).
+		Produces(restful.MIME_JSON)
+
+	// which versions are served by this api
+	routes.Route(routes.GET("").
+		To(api.coordination.GetAPIVersions))
+
+	// which resources are available under /apis/coordination.k8s.io/v1
+	routes.Route(routes.GET("/v1").
+		To(api.coordination.ListAPIResources))
+
+	api.coordination.RegisterCoordinationAPI(routes)
+	return routes
+}
+
+// /apis/networking.k8s.io
+func (api ApisAPI) Networking() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/apis/networking.k8s.io").
+		Consumes(restful.MIME_JSON, "application/yml", "application/json-patch+json", "application/merge-patch+json", "application/strategic-merge-patch+json", utils.ApplyPatchMIME).
+		Produces(restful.MIME_JSON)
+
+	// which versions are served by this api
+	routes.Route(routes.GET("").
+		To(api.networking.GetAPIVersions))
+
+	// which resources are available under /apis/networking.k8s.io/v1
+	routes.Route(routes.GET("/v1").
+		To(api.networking.ListAPIResources))
+
+	api.networking.RegisterNetworkingAPI(routes)
+	return routes
+}
+
+// /apis/flowcontrol.apiserver.k8s.io
+func (api ApisAPI) FlowControl() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/apis/flowcontrol.apiserver.k8s.io").
+		Produces(restful.MIME_JSON)
+
+	// which versions are served by this api
+	routes.Route(routes.GET("").
+		To(api.flowcontrol.GetAPIVersions))
+
+	// which resources are available under /apis/flowcontrol.apiserver.k8s.io/v1beta3
+	routes.Route(routes.GET("/v1beta3").
+		To(api.flowcontrol.ListAPIResources))
+
+	return routes
+}
@@ -4,17 +4,20 @@ import (
 	"github.com/emicklei/go-restful/v3"
 	"github.com/portainer/k2d/internal/adapter"
 	"github.com/portainer/k2d/internal/api/apis/apps/deployments"
+	"github.com/portainer/k2d/internal/api/apis/apps/replicasets"
 	"github.com/portainer/k2d/internal/controller"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type AppsService struct {
 	deployments deployments.DeploymentService
+	replicaSets replicasets.ReplicaSetService
 }
 
 func NewAppsService(operations chan controller.Operation, adapter *adapter.KubeDockerAdapter) AppsService {
 	return AppsService{
 		deployments: deployments.NewDeploymentService(adapter, operations),
+		replicaSets: replicasets.NewReplicaSetService(adapter),
 	}
 }
 
@@ -41,7 +44,14 @@ func (svc AppsService) ListAPIResources(r *restful.Request, w *restful.Response)
 				Kind:         "Deployment",
 				SingularName: "",
 				Name:         "deployments",
-				Verbs:        []string{"create", "list", "delete", "get", "patch"},
+				Verbs:        []string{"create", "list", "delete", "deletecollection", "get", "patch"},
+				Namespaced:   true,
+			},
+			{
+				Kind:         "ReplicaSet",
+				SingularName: "",
+				Name:         "replicasets",
+				Verbs:        []string{"list"},
 				Namespaced:   true,
 			},
 		},
@@ -53,4 +63,7 @@ func (svc AppsService) ListAPIResources(r *restful.Request, w *restful.Response)
 func (svc AppsService) RegisterAppsAPI(routes *restful.WebService) {
 	// deployments
 	svc.deployments.RegisterDeploymentAPI(routes)
+
+	// replicasets
+	svc.replicaSets.RegisterReplicaSetAPI(routes)
 }
@@ -26,11 +26,24 @@ func (svc DeploymentService) CreateDeployment(r *restful.Request, w *restful.Res
 
 	dryRun := r.QueryParameter("dryRun") != ""
 	if dryRun {
+		err = svc.adapter.DryRunDeployment(deployment)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid deployment: %w", err))
+			return
+		}
+
 		w.WriteAsJson(deployment)
 		return
 	}
 
-	svc.operations <- controller.NewOperation(deployment, controller.MediumPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	operation := controller.NewOperation(deployment, controller.MediumPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	svc.operations <- operation
+
+	err = operation.Wait()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create deployment: %w", err))
+		return
+	}
 
 	w.WriteAsJson(deployment)
 }
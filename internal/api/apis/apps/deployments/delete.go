@@ -12,8 +12,13 @@ func (svc DeploymentService) DeleteDeployment(r *restful.Request, w *restful.Res
 	namespace := utils.GetNamespaceFromRequest(r)
 
 	deploymentName := r.PathParameter("name")
+	svc.adapter.DeleteDeploymentCanary(r.Request.Context(), deploymentName, namespace)
 	svc.adapter.DeleteContainer(r.Request.Context(), deploymentName, namespace)
 
+	if utils.GetDeletionPropagationPolicy(r) != metav1.DeletePropagationOrphan {
+		svc.adapter.DeleteDependents(r.Request.Context(), "Deployment", deploymentName, namespace)
+	}
+
 	w.WriteAsJson(metav1.Status{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Status",
@@ -0,0 +1,51 @@
+package deployments
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func (svc DeploymentService) DeleteDeploymentCollection(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	propagationPolicy := utils.GetDeletionPropagationPolicy(r)
+
+	selector, err := labels.Parse(r.QueryParameter("labelSelector"))
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("invalid selector parameter: %w", err))
+		return
+	}
+
+	deploymentList, err := svc.adapter.ListDeployments(r.Request.Context(), namespace)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to list deployments: %w", err))
+		return
+	}
+
+	deletedDeployments := appsv1.DeploymentList{
+		TypeMeta: deploymentList.TypeMeta,
+		Items:    []appsv1.Deployment{},
+	}
+
+	for _, deployment := range deploymentList.Items {
+		if !selector.Matches(labels.Set(deployment.Labels)) {
+			continue
+		}
+
+		svc.adapter.DeleteDeploymentCanary(r.Request.Context(), deployment.Name, namespace)
+		svc.adapter.DeleteContainer(r.Request.Context(), deployment.Name, namespace)
+
+		if propagationPolicy != metav1.DeletePropagationOrphan {
+			svc.adapter.DeleteDependents(r.Request.Context(), "Deployment", deployment.Name, namespace)
+		}
+
+		deletedDeployments.Items = append(deletedDeployments.Items, deployment)
+	}
+
+	w.WriteAsJson(deletedDeployments)
+}
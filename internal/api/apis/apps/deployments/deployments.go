@@ -44,6 +44,12 @@ func (svc DeploymentService) RegisterDeploymentAPI(ws *restful.WebService) {
 		To(svc.ListDeployments).
 		Param(ws.PathParameter("namespace", "namespace name").DataType("string")))
 
+	ws.Route(ws.DELETE("/v1/namespaces/{namespace}/deployments").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.DeleteDeploymentCollection).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.QueryParameter("labelSelector", "a selector to restrict the list of deleted objects by their labels").DataType("string")))
+
 	ws.Route(ws.DELETE("/v1/deployments/{name}").
 		To(svc.DeleteDeployment).
 		Param(ws.PathParameter("name", "name of the deployment").DataType("string")))
@@ -55,13 +61,17 @@ func (svc DeploymentService) RegisterDeploymentAPI(ws *restful.WebService) {
 
 	ws.Route(ws.GET("/v1/deployments/{name}").
 		To(svc.GetDeployment).
-		Param(ws.PathParameter("name", "name of the deployment").DataType("string")))
+		Param(ws.PathParameter("name", "name of the deployment").DataType("string")).
+		Param(ws.QueryParameter("export", "when true, the deployment is stripped of the fields populated by the server").DataType("boolean")).
+		Param(ws.QueryParameter("exact", "when true, the namespace is kept in the exported deployment").DataType("boolean")))
 
 	ws.Route(ws.GET("/v1/namespaces/{namespace}/deployments/{name}").
 		Filter(utils.NamespaceValidation(svc.adapter)).
 		To(svc.GetDeployment).
 		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
-		Param(ws.PathParameter("name", "name of the deployment").DataType("string")))
+		Param(ws.PathParameter("name", "name of the deployment").DataType("string")).
+		Param(ws.QueryParameter("export", "when true, the deployment is stripped of the fields populated by the server").DataType("boolean")).
+		Param(ws.QueryParameter("exact", "when true, the namespace is kept in the exported deployment").DataType("boolean")))
 
 	ws.Route(ws.PATCH("/v1/deployments/{name}").
 		To(svc.PatchDeployment).
@@ -6,18 +6,26 @@ import (
 	"net/http"
 
 	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc DeploymentService) GetDeployment(r *restful.Request, w *restful.Response) {
 	namespace := utils.GetNamespaceFromRequest(r)
 	deploymentName := r.PathParameter("name")
 
+	export, exact, err := utils.GetExportOptions(r)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, err)
+		return
+	}
+
 	deployment, err := svc.adapter.GetDeployment(r.Request.Context(), deploymentName, namespace)
 	if err != nil {
 		if errors.Is(err, adaptererr.ErrResourceNotFound) {
-			w.WriteHeader(http.StatusNotFound)
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "apps", Resource: "deployments"}, deploymentName)
 			return
 		}
 
@@ -25,5 +33,9 @@ func (svc DeploymentService) GetDeployment(r *restful.Request, w *restful.Respon
 		return
 	}
 
+	if export {
+		adapter.ExportDeployment(deployment, exact)
+	}
+
 	w.WriteAsJson(deployment)
 }
@@ -2,16 +2,18 @@ package deployments
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
 	"github.com/portainer/k2d/internal/controller"
 	"github.com/portainer/k2d/internal/types"
 	appsv1 "k8s.io/api/apps/v1"
-	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc DeploymentService) PatchDeployment(r *restful.Request, w *restful.Response) {
@@ -25,23 +27,26 @@ func (svc DeploymentService) PatchDeployment(r *restful.Request, w *restful.Resp
 	}
 
 	deployment, err := svc.adapter.GetDeployment(r.Request.Context(), deploymentName, namespace)
-	if err != nil {
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get deployment: %w", err))
 		return
 	}
 
-	if deployment == nil {
-		w.WriteHeader(http.StatusNotFound)
+	if deployment == nil && !utils.IsServerSideApply(r) {
+		utils.ResourceNotFound(w, schema.GroupResource{Group: "apps", Resource: "deployments"}, deploymentName)
 		return
 	}
 
-	data, err := json.Marshal(deployment)
-	if err != nil {
-		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal deployment: %w", err))
-		return
+	var data []byte
+	if deployment != nil {
+		data, err = json.Marshal(deployment)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal deployment: %w", err))
+			return
+		}
 	}
 
-	mergedData, err := strategicpatch.StrategicMergePatch(data, patch, appsv1.Deployment{})
+	mergedData, err := utils.PatchResource(r, data, patch, appsv1.Deployment{})
 	if err != nil {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
 		return
@@ -57,11 +62,24 @@ func (svc DeploymentService) PatchDeployment(r *restful.Request, w *restful.Resp
 
 	dryRun := r.QueryParameter("dryRun") != ""
 	if dryRun {
+		err = svc.adapter.DryRunDeployment(updatedDeployment)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid deployment: %w", err))
+			return
+		}
+
 		w.WriteAsJson(updatedDeployment)
 		return
 	}
 
-	svc.operations <- controller.NewOperation(updatedDeployment, controller.MediumPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	operation := controller.NewOperation(updatedDeployment, controller.MediumPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	svc.operations <- operation
+
+	err = operation.Wait()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to patch deployment: %w", err))
+		return
+	}
 
 	w.WriteAsJson(updatedDeployment)
 }
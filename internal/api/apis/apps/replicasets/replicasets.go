@@ -0,0 +1,29 @@
+package replicasets
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/utils"
+)
+
+type ReplicaSetService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+func NewReplicaSetService(adapter *adapter.KubeDockerAdapter) ReplicaSetService {
+	return ReplicaSetService{
+		adapter: adapter,
+	}
+}
+
+func (svc ReplicaSetService) RegisterReplicaSetAPI(ws *restful.WebService) {
+	ws.Route(ws.GET("/v1/replicasets").
+		To(svc.ListReplicaSets).
+		Param(ws.QueryParameter("labelSelector", "a selector to restrict the list of returned objects by their labels").DataType("string")))
+
+	ws.Route(ws.GET("/v1/namespaces/{namespace}/replicasets").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.ListReplicaSets).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.QueryParameter("labelSelector", "a selector to restrict the list of returned objects by their labels").DataType("string")))
+}
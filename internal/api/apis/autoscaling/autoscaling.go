@@ -0,0 +1,56 @@
+package autoscaling
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/apis/autoscaling/horizontalpodautoscalers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type AutoscalingService struct {
+	horizontalPodAutoscalers horizontalpodautoscalers.HorizontalPodAutoscalerService
+}
+
+func NewAutoscalingService(adapter *adapter.KubeDockerAdapter) AutoscalingService {
+	return AutoscalingService{
+		horizontalPodAutoscalers: horizontalpodautoscalers.NewHorizontalPodAutoscalerService(adapter),
+	}
+}
+
+func (svc AutoscalingService) GetAPIVersions(r *restful.Request, w *restful.Response) {
+	apiVersion := metav1.APIVersions{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "APIVersions",
+		},
+		Versions: []string{"autoscaling/v2"},
+	}
+
+	w.WriteAsJson(apiVersion)
+}
+
+func (svc AutoscalingService) ListAPIResources(r *restful.Request, w *restful.Response) {
+	resourceList := metav1.APIResourceList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "APIResourceList",
+			APIVersion: "v1",
+		},
+		GroupVersion: "autoscaling/v2",
+		APIResources: []metav1.APIResource{
+			{
+				Kind:         "HorizontalPodAutoscaler",
+				SingularName: "",
+				Name:         "horizontalpodautoscalers",
+				ShortNames:   []string{"hpa"},
+				Verbs:        []string{"create", "list", "delete", "get", "patch"},
+				Namespaced:   true,
+			},
+		},
+	}
+
+	w.WriteAsJson(resourceList)
+}
+
+func (svc AutoscalingService) RegisterAutoscalingAPI(routes *restful.WebService) {
+	// horizontalpodautoscalers
+	svc.horizontalPodAutoscalers.RegisterHorizontalPodAutoscalerAPI(routes)
+}
@@ -0,0 +1,45 @@
+package horizontalpodautoscalers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	httputils "github.com/portainer/k2d/pkg/http"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+func (svc HorizontalPodAutoscalerService) CreateHorizontalPodAutoscaler(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+
+	horizontalPodAutoscaler := &autoscalingv2.HorizontalPodAutoscaler{}
+	err := httputils.ParseJSONBody(r.Request, &horizontalPodAutoscaler)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	horizontalPodAutoscaler.Namespace = namespace
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(horizontalPodAutoscaler)
+		return
+	}
+
+	err = svc.adapter.CreateHorizontalPodAutoscaler(r.Request.Context(), horizontalPodAutoscaler)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrInvalidResource) {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid horizontal pod autoscaler: %w", err))
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create horizontal pod autoscaler: %w", err))
+		return
+	}
+
+	w.WriteAsJson(horizontalPodAutoscaler)
+}
@@ -0,0 +1,38 @@
+package horizontalpodautoscalers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc HorizontalPodAutoscalerService) DeleteHorizontalPodAutoscaler(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	horizontalPodAutoscalerName := r.PathParameter("name")
+
+	err := svc.adapter.DeleteHorizontalPodAutoscaler(r.Request.Context(), horizontalPodAutoscalerName, namespace)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "autoscaling", Resource: "horizontalpodautoscalers"}, horizontalPodAutoscalerName)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to delete horizontal pod autoscaler: %w", err))
+		return
+	}
+
+	w.WriteAsJson(metav1.Status{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Status",
+			APIVersion: "v1",
+		},
+		Status: "Success",
+		Code:   http.StatusOK,
+	})
+}
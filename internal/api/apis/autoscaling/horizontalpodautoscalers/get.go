@@ -0,0 +1,30 @@
+package horizontalpodautoscalers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc HorizontalPodAutoscalerService) GetHorizontalPodAutoscaler(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	horizontalPodAutoscalerName := r.PathParameter("name")
+
+	horizontalPodAutoscaler, err := svc.adapter.GetHorizontalPodAutoscaler(r.Request.Context(), horizontalPodAutoscalerName, namespace)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "autoscaling", Resource: "horizontalpodautoscalers"}, horizontalPodAutoscalerName)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get horizontal pod autoscaler: %w", err))
+		return
+	}
+
+	w.WriteAsJson(horizontalPodAutoscaler)
+}
@@ -0,0 +1,76 @@
+package horizontalpodautoscalers
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/utils"
+)
+
+type HorizontalPodAutoscalerService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+func NewHorizontalPodAutoscalerService(adapter *adapter.KubeDockerAdapter) HorizontalPodAutoscalerService {
+	return HorizontalPodAutoscalerService{
+		adapter: adapter,
+	}
+}
+
+func (svc HorizontalPodAutoscalerService) RegisterHorizontalPodAutoscalerAPI(ws *restful.WebService) {
+	horizontalPodAutoscalerGVKExtension := map[string]string{
+		"group":   "autoscaling",
+		"kind":    "HorizontalPodAutoscaler",
+		"version": "v2",
+	}
+
+	ws.Route(ws.POST("/v2/horizontalpodautoscalers").
+		To(svc.CreateHorizontalPodAutoscaler).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+	ws.Route(ws.POST("/v2/namespaces/{namespace}/horizontalpodautoscalers").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.CreateHorizontalPodAutoscaler).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+	ws.Route(ws.GET("/v2/horizontalpodautoscalers").
+		To(svc.ListHorizontalPodAutoscalers))
+
+	ws.Route(ws.GET("/v2/namespaces/{namespace}/horizontalpodautoscalers").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.ListHorizontalPodAutoscalers).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")))
+
+	ws.Route(ws.DELETE("/v2/horizontalpodautoscalers/{name}").
+		To(svc.DeleteHorizontalPodAutoscaler).
+		Param(ws.PathParameter("name", "name of the horizontal pod autoscaler").DataType("string")))
+
+	ws.Route(ws.DELETE("/v2/namespaces/{namespace}/horizontalpodautoscalers/{name}").
+		To(svc.DeleteHorizontalPodAutoscaler).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the horizontal pod autoscaler").DataType("string")))
+
+	ws.Route(ws.GET("/v2/horizontalpodautoscalers/{name}").
+		To(svc.GetHorizontalPodAutoscaler).
+		Param(ws.PathParameter("name", "name of the horizontal pod autoscaler").DataType("string")))
+
+	ws.Route(ws.GET("/v2/namespaces/{namespace}/horizontalpodautoscalers/{name}").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.GetHorizontalPodAutoscaler).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the horizontal pod autoscaler").DataType("string")))
+
+	ws.Route(ws.PATCH("/v2/horizontalpodautoscalers/{name}").
+		To(svc.PatchHorizontalPodAutoscaler).
+		Param(ws.PathParameter("name", "name of the horizontal pod autoscaler").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")).
+		AddExtension("x-kubernetes-group-version-kind", horizontalPodAutoscalerGVKExtension))
+
+	ws.Route(ws.PATCH("/v2/namespaces/{namespace}/horizontalpodautoscalers/{name}").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.PatchHorizontalPodAutoscaler).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the horizontal pod autoscaler").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")).
+		AddExtension("x-kubernetes-group-version-kind", horizontalPodAutoscalerGVKExtension))
+}
@@ -0,0 +1,82 @@
+package horizontalpodautoscalers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc HorizontalPodAutoscalerService) PatchHorizontalPodAutoscaler(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	horizontalPodAutoscalerName := r.PathParameter("name")
+
+	patch, err := io.ReadAll(r.Request.Body)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	horizontalPodAutoscaler, err := svc.adapter.GetHorizontalPodAutoscaler(r.Request.Context(), horizontalPodAutoscalerName, namespace)
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get horizontal pod autoscaler: %w", err))
+		return
+	}
+
+	if horizontalPodAutoscaler == nil && !utils.IsServerSideApply(r) {
+		utils.ResourceNotFound(w, schema.GroupResource{Group: "autoscaling", Resource: "horizontalpodautoscalers"}, horizontalPodAutoscalerName)
+		return
+	}
+
+	var data []byte
+	if horizontalPodAutoscaler != nil {
+		data, err = json.Marshal(horizontalPodAutoscaler)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal horizontal pod autoscaler: %w", err))
+			return
+		}
+	}
+
+	mergedData, err := utils.PatchResource(r, data, patch, autoscalingv2.HorizontalPodAutoscaler{})
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
+		return
+	}
+
+	updatedHorizontalPodAutoscaler := &autoscalingv2.HorizontalPodAutoscaler{}
+
+	err = json.Unmarshal(mergedData, updatedHorizontalPodAutoscaler)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to unmarshal horizontal pod autoscaler: %w", err))
+		return
+	}
+
+	updatedHorizontalPodAutoscaler.Name = horizontalPodAutoscalerName
+	updatedHorizontalPodAutoscaler.Namespace = namespace
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(updatedHorizontalPodAutoscaler)
+		return
+	}
+
+	err = svc.adapter.CreateHorizontalPodAutoscaler(r.Request.Context(), updatedHorizontalPodAutoscaler)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrInvalidResource) {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid horizontal pod autoscaler: %w", err))
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to patch horizontal pod autoscaler: %w", err))
+		return
+	}
+
+	w.WriteAsJson(updatedHorizontalPodAutoscaler)
+}
@@ -0,0 +1,55 @@
+package coordination
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/apis/coordination.k8s.io/leases"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CoordinationService struct {
+	leases leases.LeaseService
+}
+
+func NewCoordinationService(adapter *adapter.KubeDockerAdapter) CoordinationService {
+	return CoordinationService{
+		leases: leases.NewLeaseService(adapter),
+	}
+}
+
+func (svc CoordinationService) GetAPIVersions(r *restful.Request, w *restful.Response) {
+	apiVersion := metav1.APIVersions{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "APIVersions",
+		},
+		Versions: []string{"coordination.k8s.io/v1"},
+	}
+
+	w.WriteAsJson(apiVersion)
+}
+
+func (svc CoordinationService) ListAPIResources(r *restful.Request, w *restful.Response) {
+	resourceList := metav1.APIResourceList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "APIResourceList",
+			APIVersion: "v1",
+		},
+		GroupVersion: "coordination.k8s.io/v1",
+		APIResources: []metav1.APIResource{
+			{
+				Kind:         "Lease",
+				SingularName: "",
+				Name:         "leases",
+				Verbs:        []string{"create", "list", "delete", "get", "patch", "update"},
+				Namespaced:   true,
+			},
+		},
+	}
+
+	w.WriteAsJson(resourceList)
+}
+
+func (svc CoordinationService) RegisterCoordinationAPI(routes *restful.WebService) {
+	// leases
+	svc.leases.RegisterLeaseAPI(routes)
+}
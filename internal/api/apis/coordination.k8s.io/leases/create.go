@@ -0,0 +1,45 @@
+package leases
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	httputils "github.com/portainer/k2d/pkg/http"
+	coordinationv1 "k8s.io/api/coordination/v1"
+)
+
+func (svc LeaseService) CreateLease(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+
+	lease := &coordinationv1.Lease{}
+	err := httputils.ParseJSONBody(r.Request, &lease)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	lease.Namespace = namespace
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(lease)
+		return
+	}
+
+	err = svc.adapter.CreateLease(r.Request.Context(), lease)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceAlreadyExists) {
+			utils.HttpError(r, w, http.StatusConflict, err)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create lease: %w", err))
+		return
+	}
+
+	w.WriteAsJson(lease)
+}
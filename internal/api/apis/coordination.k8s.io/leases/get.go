@@ -0,0 +1,30 @@
+package leases
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc LeaseService) GetLease(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	leaseName := r.PathParameter("name")
+
+	lease, err := svc.adapter.GetLease(r.Request.Context(), leaseName, namespace)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "coordination.k8s.io", Resource: "leases"}, leaseName)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get lease: %w", err))
+		return
+	}
+
+	w.WriteAsJson(lease)
+}
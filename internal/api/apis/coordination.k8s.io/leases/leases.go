@@ -0,0 +1,83 @@
+package leases
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/utils"
+)
+
+type LeaseService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+func NewLeaseService(adapter *adapter.KubeDockerAdapter) LeaseService {
+	return LeaseService{
+		adapter: adapter,
+	}
+}
+
+func (svc LeaseService) RegisterLeaseAPI(ws *restful.WebService) {
+	leaseGVKExtension := map[string]string{
+		"group":   "coordination.k8s.io",
+		"kind":    "Lease",
+		"version": "v1",
+	}
+
+	ws.Route(ws.POST("/v1/leases").
+		To(svc.CreateLease).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+	ws.Route(ws.POST("/v1/namespaces/{namespace}/leases").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.CreateLease).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+	ws.Route(ws.GET("/v1/leases").
+		To(svc.ListLeases))
+
+	ws.Route(ws.GET("/v1/namespaces/{namespace}/leases").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.ListLeases).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")))
+
+	ws.Route(ws.DELETE("/v1/leases/{name}").
+		To(svc.DeleteLease).
+		Param(ws.PathParameter("name", "name of the lease").DataType("string")))
+
+	ws.Route(ws.DELETE("/v1/namespaces/{namespace}/leases/{name}").
+		To(svc.DeleteLease).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the lease").DataType("string")))
+
+	ws.Route(ws.GET("/v1/leases/{name}").
+		To(svc.GetLease).
+		Param(ws.PathParameter("name", "name of the lease").DataType("string")))
+
+	ws.Route(ws.GET("/v1/namespaces/{namespace}/leases/{name}").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.GetLease).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the lease").DataType("string")))
+
+	ws.Route(ws.PUT("/v1/namespaces/{namespace}/leases/{name}").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.PutLease).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the lease").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+	ws.Route(ws.PATCH("/v1/leases/{name}").
+		To(svc.PatchLease).
+		Param(ws.PathParameter("name", "name of the lease").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")).
+		AddExtension("x-kubernetes-group-version-kind", leaseGVKExtension))
+
+	ws.Route(ws.PATCH("/v1/namespaces/{namespace}/leases/{name}").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.PatchLease).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the lease").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")).
+		AddExtension("x-kubernetes-group-version-kind", leaseGVKExtension))
+}
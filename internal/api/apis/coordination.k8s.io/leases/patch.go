@@ -0,0 +1,87 @@
+package leases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc LeaseService) PatchLease(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	leaseName := r.PathParameter("name")
+
+	patch, err := io.ReadAll(r.Request.Body)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	lease, err := svc.adapter.GetLease(r.Request.Context(), leaseName, namespace)
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get lease: %w", err))
+		return
+	}
+
+	if lease == nil && !utils.IsServerSideApply(r) {
+		utils.ResourceNotFound(w, schema.GroupResource{Group: "coordination.k8s.io", Resource: "leases"}, leaseName)
+		return
+	}
+
+	var data []byte
+	if lease != nil {
+		data, err = json.Marshal(lease)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal lease: %w", err))
+			return
+		}
+	}
+
+	mergedData, err := utils.PatchResource(r, data, patch, coordinationv1.Lease{})
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
+		return
+	}
+
+	updatedLease := &coordinationv1.Lease{}
+
+	err = json.Unmarshal(mergedData, updatedLease)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to unmarshal lease: %w", err))
+		return
+	}
+
+	updatedLease.Name = leaseName
+	updatedLease.Namespace = namespace
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(updatedLease)
+		return
+	}
+
+	if lease == nil {
+		err = svc.adapter.CreateLease(r.Request.Context(), updatedLease)
+	} else {
+		err = svc.adapter.UpdateLease(r.Request.Context(), updatedLease)
+	}
+
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceConflict) || errors.Is(err, adaptererr.ErrResourceAlreadyExists) {
+			utils.HttpError(r, w, http.StatusConflict, err)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to patch lease: %w", err))
+		return
+	}
+
+	w.WriteAsJson(updatedLease)
+}
@@ -0,0 +1,53 @@
+package leases
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	httputils "github.com/portainer/k2d/pkg/http"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc LeaseService) PutLease(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	leaseName := r.PathParameter("name")
+
+	lease := &coordinationv1.Lease{}
+	err := httputils.ParseJSONBody(r.Request, &lease)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	lease.Name = leaseName
+	lease.Namespace = namespace
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(lease)
+		return
+	}
+
+	err = svc.adapter.UpdateLease(r.Request.Context(), lease)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "coordination.k8s.io", Resource: "leases"}, leaseName)
+			return
+		}
+
+		if errors.Is(err, adaptererr.ErrResourceConflict) {
+			utils.HttpError(r, w, http.StatusConflict, err)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to update lease: %w", err))
+		return
+	}
+
+	w.WriteAsJson(lease)
+}
@@ -0,0 +1,41 @@
+package customresources
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	httputils "github.com/portainer/k2d/pkg/http"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func (svc CustomResourceService) CreateCustomResource(r *restful.Request, w *restful.Response) {
+	crd, version, ok := svc.getCustomResourceDefinition(r, w, false)
+	if !ok {
+		return
+	}
+
+	resource := &unstructured.Unstructured{}
+	err := httputils.ParseJSONBody(r.Request, &resource.Object)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	resource.SetNamespace(utils.GetNamespaceFromRequest(r))
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(resource.Object)
+		return
+	}
+
+	err = svc.adapter.CreateCustomResource(r.Request.Context(), crd, version, resource)
+	if err != nil {
+		httpError(r, w, crd, resource.GetName(), err, "unable to create custom resource")
+		return
+	}
+
+	w.WriteAsJson(resource.Object)
+}
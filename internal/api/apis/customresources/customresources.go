@@ -0,0 +1,195 @@
+package customresources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CustomResourceService serves the custom resources of the custom resource definitions, under /apis/{group}/{version}.
+// The custom resources are stored and served back opaquely, without any controller acting on them.
+type CustomResourceService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+func NewCustomResourceService(adapter *adapter.KubeDockerAdapter) CustomResourceService {
+	return CustomResourceService{
+		adapter: adapter,
+	}
+}
+
+// ListAPIGroups returns the API groups of the custom resource definitions, listed under /apis with the built-in groups.
+func (svc CustomResourceService) ListAPIGroups(ctx context.Context) ([]metav1.APIGroup, error) {
+	return svc.adapter.ListCustomResourceAPIGroups(ctx)
+}
+
+func (svc CustomResourceService) GetAPIGroup(r *restful.Request, w *restful.Response) {
+	groupName := r.PathParameter("group")
+
+	groups, err := svc.adapter.ListCustomResourceAPIGroups(r.Request.Context())
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to list custom resource API groups: %w", err))
+		return
+	}
+
+	for _, group := range groups {
+		if group.Name == groupName {
+			group.TypeMeta = metav1.TypeMeta{
+				Kind:       "APIGroup",
+				APIVersion: "v1",
+			}
+
+			w.WriteAsJson(group)
+			return
+		}
+	}
+
+	utils.RouteNotFound(r, w)
+}
+
+func (svc CustomResourceService) ListAPIResources(r *restful.Request, w *restful.Response) {
+	group := r.PathParameter("group")
+	version := r.PathParameter("version")
+
+	resources, err := svc.adapter.ListCustomResourceAPIResources(r.Request.Context(), group, version)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.RouteNotFound(r, w)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to list custom resource API resources: %w", err))
+		return
+	}
+
+	w.WriteAsJson(metav1.APIResourceList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "APIResourceList",
+			APIVersion: "v1",
+		},
+		GroupVersion: group + "/" + version,
+		APIResources: resources,
+	})
+}
+
+func (svc CustomResourceService) RegisterCustomResourceAPI(ws *restful.WebService) {
+	ws.Route(ws.GET("/{group}").
+		To(svc.GetAPIGroup).
+		Param(ws.PathParameter("group", "name of the API group").DataType("string")))
+
+	ws.Route(ws.GET("/{group}/{version}").
+		To(svc.ListAPIResources).
+		Param(ws.PathParameter("group", "name of the API group").DataType("string")).
+		Param(ws.PathParameter("version", "version of the API group").DataType("string")))
+
+	clusterPath := "/{group}/{version}/{resource}"
+	namespacedPath := "/{group}/{version}/namespaces/{namespace}/{resource}"
+
+	for _, resourcePath := range []string{clusterPath, namespacedPath} {
+		namespaced := resourcePath == namespacedPath
+
+		ws.Route(svc.route(ws, ws.POST(resourcePath), namespaced, false).
+			To(svc.CreateCustomResource).
+			Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+		ws.Route(svc.route(ws, ws.GET(resourcePath), namespaced, false).
+			To(svc.ListCustomResources))
+
+		ws.Route(svc.route(ws, ws.GET(resourcePath+"/{name}"), namespaced, true).
+			To(svc.GetCustomResource))
+
+		ws.Route(svc.route(ws, ws.GET(resourcePath+"/{name}/status"), namespaced, true).
+			To(svc.GetCustomResource))
+
+		ws.Route(svc.route(ws, ws.PUT(resourcePath+"/{name}"), namespaced, true).
+			To(svc.PutCustomResource).
+			Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+		ws.Route(svc.route(ws, ws.PUT(resourcePath+"/{name}/status"), namespaced, true).
+			To(svc.PutCustomResourceStatus).
+			Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+		ws.Route(svc.route(ws, ws.PATCH(resourcePath+"/{name}"), namespaced, true).
+			To(svc.PatchCustomResource).
+			Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+		ws.Route(svc.route(ws, ws.DELETE(resourcePath+"/{name}"), namespaced, true).
+			To(svc.DeleteCustomResource))
+	}
+}
+
+// route adds the path parameters (and the namespace validation for the namespaced paths) to a custom resource route.
+func (svc CustomResourceService) route(ws *restful.WebService, builder *restful.RouteBuilder, namespaced, named bool) *restful.RouteBuilder {
+	builder = builder.
+		Param(ws.PathParameter("group", "name of the API group").DataType("string")).
+		Param(ws.PathParameter("version", "version of the API group").DataType("string")).
+		Param(ws.PathParameter("resource", "plural name of the custom resource").DataType("string"))
+
+	if namespaced {
+		builder = builder.
+			Filter(utils.NamespaceValidation(svc.adapter)).
+			Param(ws.PathParameter("namespace", "namespace name").DataType("string"))
+	}
+
+	if named {
+		builder = builder.
+			Param(ws.PathParameter("name", "name of the custom resource").DataType("string"))
+	}
+
+	return builder
+}
+
+// getCustomResourceDefinition returns the custom resource definition serving the resource of a request, as well as
+// the requested version. It writes a not found response and returns false if no definition serves the resource, or if
+// the scope of the definition does not match the path of the request. The cluster path of a namespaced resource is
+// only allowed to list the resources of all namespaces.
+func (svc CustomResourceService) getCustomResourceDefinition(r *restful.Request, w *restful.Response, allowAllNamespaces bool) (*apiextensionsv1.CustomResourceDefinition, string, bool) {
+	group := r.PathParameter("group")
+	version := r.PathParameter("version")
+	resource := r.PathParameter("resource")
+
+	crd, err := svc.adapter.GetCustomResourceDefinitionForResource(r.Request.Context(), group, version, resource)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.RouteNotFound(r, w)
+			return nil, "", false
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get custom resource definition: %w", err))
+		return nil, "", false
+	}
+
+	namespaced := r.PathParameter("namespace") != ""
+	if adapter.IsCustomResourceNamespaced(crd) != namespaced && !(allowAllNamespaces && !namespaced) {
+		utils.RouteNotFound(r, w)
+		return nil, "", false
+	}
+
+	return crd, version, true
+}
+
+// notFound writes a not found response for a custom resource.
+func notFound(w *restful.Response, crd *apiextensionsv1.CustomResourceDefinition, name string) {
+	utils.ResourceNotFound(w, schema.GroupResource{Group: crd.Spec.Group, Resource: crd.Spec.Names.Plural}, name)
+}
+
+// httpError writes the error response of a failed custom resource operation.
+// The status code is derived from the adapter error by utils.NewStatusFromError, a not found error
+// is reported against the custom resource.
+func httpError(r *restful.Request, w *restful.Response, crd *apiextensionsv1.CustomResourceDefinition, name string, err error, message string) {
+	if errors.Is(err, adaptererr.ErrResourceNotFound) {
+		notFound(w, crd, name)
+		return
+	}
+
+	utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("%s: %w", message, err))
+}
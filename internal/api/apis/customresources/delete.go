@@ -0,0 +1,34 @@
+package customresources
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (svc CustomResourceService) DeleteCustomResource(r *restful.Request, w *restful.Response) {
+	crd, version, ok := svc.getCustomResourceDefinition(r, w, false)
+	if !ok {
+		return
+	}
+
+	namespace := utils.GetNamespaceFromRequest(r)
+	name := r.PathParameter("name")
+
+	err := svc.adapter.DeleteCustomResource(r.Request.Context(), crd, version, namespace, name)
+	if err != nil {
+		httpError(r, w, crd, name, err, "unable to delete custom resource")
+		return
+	}
+
+	w.WriteAsJson(metav1.Status{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Status",
+			APIVersion: "v1",
+		},
+		Status: "Success",
+		Code:   http.StatusOK,
+	})
+}
@@ -0,0 +1,24 @@
+package customresources
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+)
+
+func (svc CustomResourceService) GetCustomResource(r *restful.Request, w *restful.Response) {
+	crd, version, ok := svc.getCustomResourceDefinition(r, w, false)
+	if !ok {
+		return
+	}
+
+	namespace := utils.GetNamespaceFromRequest(r)
+	name := r.PathParameter("name")
+
+	resource, err := svc.adapter.GetCustomResource(r.Request.Context(), crd, version, namespace, name)
+	if err != nil {
+		httpError(r, w, crd, name, err, "unable to get custom resource")
+		return
+	}
+
+	w.WriteAsJson(resource.Object)
+}
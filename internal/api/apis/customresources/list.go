@@ -0,0 +1,34 @@
+package customresources
+
+import (
+	"context"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (svc CustomResourceService) ListCustomResources(r *restful.Request, w *restful.Response) {
+	crd, version, ok := svc.getCustomResourceDefinition(r, w, true)
+	if !ok {
+		return
+	}
+
+	namespace := utils.GetNamespaceFromRequest(r)
+
+	utils.ListResources(
+		r,
+		w,
+		func(ctx context.Context) (interface{}, error) {
+			list, err := svc.adapter.ListCustomResources(ctx, crd, version, namespace)
+			if err != nil {
+				return nil, err
+			}
+
+			return list.UnstructuredContent(), nil
+		},
+		func(ctx context.Context) (*metav1.Table, error) {
+			return svc.adapter.GetCustomResourceTable(ctx, crd, version, namespace)
+		},
+	)
+}
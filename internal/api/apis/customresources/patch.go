@@ -0,0 +1,86 @@
+package customresources
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func (svc CustomResourceService) PatchCustomResource(r *restful.Request, w *restful.Response) {
+	crd, version, ok := svc.getCustomResourceDefinition(r, w, false)
+	if !ok {
+		return
+	}
+
+	namespace := utils.GetNamespaceFromRequest(r)
+	name := r.PathParameter("name")
+
+	patch, err := io.ReadAll(r.Request.Body)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	resource, err := svc.adapter.GetCustomResource(r.Request.Context(), crd, version, namespace, name)
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get custom resource: %w", err))
+		return
+	}
+
+	if resource == nil && !utils.IsServerSideApply(r) {
+		notFound(w, crd, name)
+		return
+	}
+
+	var data []byte
+	if resource != nil {
+		data, err = json.Marshal(resource.Object)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal custom resource: %w", err))
+			return
+		}
+	}
+
+	mergedData, err := utils.PatchUnstructuredResource(r, data, patch)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
+		return
+	}
+
+	updatedResource := &unstructured.Unstructured{}
+
+	err = json.Unmarshal(mergedData, &updatedResource.Object)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to unmarshal custom resource: %w", err))
+		return
+	}
+
+	updatedResource.SetName(name)
+	updatedResource.SetNamespace(namespace)
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(updatedResource.Object)
+		return
+	}
+
+	if resource == nil {
+		err = svc.adapter.CreateCustomResource(r.Request.Context(), crd, version, updatedResource)
+	} else {
+		err = svc.adapter.UpdateCustomResource(r.Request.Context(), crd, version, updatedResource, false)
+	}
+
+	if err != nil {
+		httpError(r, w, crd, name, err, "unable to patch custom resource")
+		return
+	}
+
+	w.WriteAsJson(updatedResource.Object)
+}
@@ -0,0 +1,52 @@
+package customresources
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	httputils "github.com/portainer/k2d/pkg/http"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func (svc CustomResourceService) PutCustomResource(r *restful.Request, w *restful.Response) {
+	svc.putCustomResource(r, w, false)
+}
+
+func (svc CustomResourceService) PutCustomResourceStatus(r *restful.Request, w *restful.Response) {
+	svc.putCustomResource(r, w, true)
+}
+
+func (svc CustomResourceService) putCustomResource(r *restful.Request, w *restful.Response, statusOnly bool) {
+	crd, version, ok := svc.getCustomResourceDefinition(r, w, false)
+	if !ok {
+		return
+	}
+
+	name := r.PathParameter("name")
+
+	resource := &unstructured.Unstructured{}
+	err := httputils.ParseJSONBody(r.Request, &resource.Object)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	resource.SetName(name)
+	resource.SetNamespace(utils.GetNamespaceFromRequest(r))
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(resource.Object)
+		return
+	}
+
+	err = svc.adapter.UpdateCustomResource(r.Request.Context(), crd, version, resource, statusOnly)
+	if err != nil {
+		httpError(r, w, crd, name, err, "unable to update custom resource")
+		return
+	}
+
+	w.WriteAsJson(resource.Object)
+}
@@ -6,17 +6,20 @@ import (
 	"github.com/emicklei/go-restful/v3"
 	"github.com/portainer/k2d/internal/api/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 )
 
 func (svc EventsService) ListEvents(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+
 	utils.ListResources(
 		r,
 		w,
 		func(ctx context.Context) (interface{}, error) {
-			return svc.adapter.ListEvents()
+			return svc.adapter.ListEvents(namespace, fields.Everything())
 		},
 		func(ctx context.Context) (*metav1.Table, error) {
-			return svc.adapter.GetEventTable()
+			return svc.adapter.GetEventTable(namespace, fields.Everything())
 		},
 	)
 }
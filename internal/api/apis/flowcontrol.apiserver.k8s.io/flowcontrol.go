@@ -0,0 +1,40 @@
+package flowcontrol
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FlowControlService is a discovery stub for the flowcontrol.apiserver.k8s.io API group.
+// k2d does not implement API priority and fairness, the group is only exposed so that
+// client-go discovery (used by kubectl) completes without errors.
+type FlowControlService struct {
+}
+
+func NewFlowControlService() FlowControlService {
+	return FlowControlService{}
+}
+
+func (svc FlowControlService) GetAPIVersions(r *restful.Request, w *restful.Response) {
+	apiVersion := metav1.APIVersions{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "APIVersions",
+		},
+		Versions: []string{"flowcontrol.apiserver.k8s.io/v1beta3"},
+	}
+
+	w.WriteAsJson(apiVersion)
+}
+
+func (svc FlowControlService) ListAPIResources(r *restful.Request, w *restful.Response) {
+	resourceList := metav1.APIResourceList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "APIResourceList",
+			APIVersion: "v1",
+		},
+		GroupVersion: "flowcontrol.apiserver.k8s.io/v1beta3",
+		APIResources: []metav1.APIResource{},
+	}
+
+	w.WriteAsJson(resourceList)
+}
@@ -0,0 +1,55 @@
+package networking
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/apis/networking.k8s.io/networkpolicies"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NetworkingService struct {
+	networkPolicies networkpolicies.NetworkPolicyService
+}
+
+func NewNetworkingService(adapter *adapter.KubeDockerAdapter) NetworkingService {
+	return NetworkingService{
+		networkPolicies: networkpolicies.NewNetworkPolicyService(adapter),
+	}
+}
+
+func (svc NetworkingService) GetAPIVersions(r *restful.Request, w *restful.Response) {
+	apiVersion := metav1.APIVersions{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "APIVersions",
+		},
+		Versions: []string{"networking.k8s.io/v1"},
+	}
+
+	w.WriteAsJson(apiVersion)
+}
+
+func (svc NetworkingService) ListAPIResources(r *restful.Request, w *restful.Response) {
+	resourceList := metav1.APIResourceList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "APIResourceList",
+			APIVersion: "v1",
+		},
+		GroupVersion: "networking.k8s.io/v1",
+		APIResources: []metav1.APIResource{
+			{
+				Kind:         "NetworkPolicy",
+				SingularName: "",
+				Name:         "networkpolicies",
+				Verbs:        []string{"create", "list", "delete", "get", "patch", "update"},
+				Namespaced:   true,
+			},
+		},
+	}
+
+	w.WriteAsJson(resourceList)
+}
+
+func (svc NetworkingService) RegisterNetworkingAPI(routes *restful.WebService) {
+	// networkpolicies
+	svc.networkPolicies.RegisterNetworkPolicyAPI(routes)
+}
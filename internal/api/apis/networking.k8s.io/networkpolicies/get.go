@@ -0,0 +1,30 @@
+package networkpolicies
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc NetworkPolicyService) GetNetworkPolicy(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	networkPolicyName := r.PathParameter("name")
+
+	networkPolicy, err := svc.adapter.GetNetworkPolicy(r.Request.Context(), networkPolicyName, namespace)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "networking.k8s.io", Resource: "networkpolicies"}, networkPolicyName)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get network policy: %w", err))
+		return
+	}
+
+	w.WriteAsJson(networkPolicy)
+}
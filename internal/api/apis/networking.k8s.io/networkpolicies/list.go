@@ -0,0 +1,24 @@
+package networkpolicies
+
+import (
+	"context"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (svc NetworkPolicyService) ListNetworkPolicies(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+
+	utils.ListResources(
+		r,
+		w,
+		func(ctx context.Context) (interface{}, error) {
+			return svc.adapter.ListNetworkPolicies(ctx, namespace)
+		},
+		func(ctx context.Context) (*metav1.Table, error) {
+			return svc.adapter.GetNetworkPolicyTable(ctx, namespace)
+		},
+	)
+}
@@ -0,0 +1,83 @@
+package networkpolicies
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/utils"
+)
+
+type NetworkPolicyService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+func NewNetworkPolicyService(adapter *adapter.KubeDockerAdapter) NetworkPolicyService {
+	return NetworkPolicyService{
+		adapter: adapter,
+	}
+}
+
+func (svc NetworkPolicyService) RegisterNetworkPolicyAPI(ws *restful.WebService) {
+	networkPolicyGVKExtension := map[string]string{
+		"group":   "networking.k8s.io",
+		"kind":    "NetworkPolicy",
+		"version": "v1",
+	}
+
+	ws.Route(ws.POST("/v1/networkpolicies").
+		To(svc.CreateNetworkPolicy).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+	ws.Route(ws.POST("/v1/namespaces/{namespace}/networkpolicies").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.CreateNetworkPolicy).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+	ws.Route(ws.GET("/v1/networkpolicies").
+		To(svc.ListNetworkPolicies))
+
+	ws.Route(ws.GET("/v1/namespaces/{namespace}/networkpolicies").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.ListNetworkPolicies).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")))
+
+	ws.Route(ws.DELETE("/v1/networkpolicies/{name}").
+		To(svc.DeleteNetworkPolicy).
+		Param(ws.PathParameter("name", "name of the networkPolicy").DataType("string")))
+
+	ws.Route(ws.DELETE("/v1/namespaces/{namespace}/networkpolicies/{name}").
+		To(svc.DeleteNetworkPolicy).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the networkPolicy").DataType("string")))
+
+	ws.Route(ws.GET("/v1/networkpolicies/{name}").
+		To(svc.GetNetworkPolicy).
+		Param(ws.PathParameter("name", "name of the networkPolicy").DataType("string")))
+
+	ws.Route(ws.GET("/v1/namespaces/{namespace}/networkpolicies/{name}").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.GetNetworkPolicy).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the networkPolicy").DataType("string")))
+
+	ws.Route(ws.PUT("/v1/namespaces/{namespace}/networkpolicies/{name}").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.PutNetworkPolicy).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the networkPolicy").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
+	ws.Route(ws.PATCH("/v1/networkpolicies/{name}").
+		To(svc.PatchNetworkPolicy).
+		Param(ws.PathParameter("name", "name of the networkPolicy").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")).
+		AddExtension("x-kubernetes-group-version-kind", networkPolicyGVKExtension))
+
+	ws.Route(ws.PATCH("/v1/namespaces/{namespace}/networkpolicies/{name}").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.PatchNetworkPolicy).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the networkPolicy").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")).
+		AddExtension("x-kubernetes-group-version-kind", networkPolicyGVKExtension))
+}
@@ -0,0 +1,87 @@
+package networkpolicies
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc NetworkPolicyService) PatchNetworkPolicy(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	networkPolicyName := r.PathParameter("name")
+
+	patch, err := io.ReadAll(r.Request.Body)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	networkPolicy, err := svc.adapter.GetNetworkPolicy(r.Request.Context(), networkPolicyName, namespace)
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get network policy: %w", err))
+		return
+	}
+
+	if networkPolicy == nil && !utils.IsServerSideApply(r) {
+		utils.ResourceNotFound(w, schema.GroupResource{Group: "networking.k8s.io", Resource: "networkpolicies"}, networkPolicyName)
+		return
+	}
+
+	var data []byte
+	if networkPolicy != nil {
+		data, err = json.Marshal(networkPolicy)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal network policy: %w", err))
+			return
+		}
+	}
+
+	mergedData, err := utils.PatchResource(r, data, patch, networkingv1.NetworkPolicy{})
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
+		return
+	}
+
+	updatedNetworkPolicy := &networkingv1.NetworkPolicy{}
+
+	err = json.Unmarshal(mergedData, updatedNetworkPolicy)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to unmarshal network policy: %w", err))
+		return
+	}
+
+	updatedNetworkPolicy.Name = networkPolicyName
+	updatedNetworkPolicy.Namespace = namespace
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(updatedNetworkPolicy)
+		return
+	}
+
+	if networkPolicy == nil {
+		err = svc.adapter.CreateNetworkPolicy(r.Request.Context(), updatedNetworkPolicy)
+	} else {
+		err = svc.adapter.UpdateNetworkPolicy(r.Request.Context(), updatedNetworkPolicy)
+	}
+
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceAlreadyExists) {
+			utils.HttpError(r, w, http.StatusConflict, err)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to patch network policy: %w", err))
+		return
+	}
+
+	w.WriteAsJson(updatedNetworkPolicy)
+}
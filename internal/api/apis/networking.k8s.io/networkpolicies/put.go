@@ -0,0 +1,48 @@
+package networkpolicies
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	httputils "github.com/portainer/k2d/pkg/http"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc NetworkPolicyService) PutNetworkPolicy(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	networkPolicyName := r.PathParameter("name")
+
+	networkPolicy := &networkingv1.NetworkPolicy{}
+	err := httputils.ParseJSONBody(r.Request, &networkPolicy)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	networkPolicy.Name = networkPolicyName
+	networkPolicy.Namespace = namespace
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(networkPolicy)
+		return
+	}
+
+	err = svc.adapter.UpdateNetworkPolicy(r.Request.Context(), networkPolicy)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "networking.k8s.io", Resource: "networkpolicies"}, networkPolicyName)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to update network policy: %w", err))
+		return
+	}
+
+	w.WriteAsJson(networkPolicy)
+}
@@ -41,7 +41,7 @@ func (svc StorageService) ListAPIResources(r *restful.Request, w *restful.Respon
 				SingularName: "",
 				Name:         "storageclasses",
 				ShortNames:   []string{"sc"},
-				Verbs:        []string{"list", "get"},
+				Verbs:        []string{"create", "list", "delete", "get", "patch"},
 				Namespaced:   false,
 			},
 		},
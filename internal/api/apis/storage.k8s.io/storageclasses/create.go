@@ -0,0 +1,35 @@
+package storageclasses
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	httputils "github.com/portainer/k2d/pkg/http"
+	storagev1 "k8s.io/api/storage/v1"
+)
+
+func (svc StorageClassService) CreateStorageClass(r *restful.Request, w *restful.Response) {
+	storageClass := &storagev1.StorageClass{}
+
+	err := httputils.ParseJSONBody(r.Request, &storageClass)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(storageClass)
+		return
+	}
+
+	err = svc.adapter.CreateStorageClass(r.Request.Context(), storageClass)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create storage class: %w", err))
+		return
+	}
+
+	w.WriteAsJson(storageClass)
+}
@@ -9,6 +9,7 @@ import (
 
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc StorageClassService) GetStorageClass(r *restful.Request, w *restful.Response) {
@@ -17,7 +18,7 @@ func (svc StorageClassService) GetStorageClass(r *restful.Request, w *restful.Re
 	sc, err := svc.adapter.GetStorageClass(r.Request.Context(), storageClassName)
 	if err != nil {
 		if errors.Is(err, adaptererr.ErrResourceNotFound) {
-			w.WriteHeader(http.StatusNotFound)
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "storage.k8s.io", Resource: "storageclasses"}, storageClassName)
 			return
 		}
 
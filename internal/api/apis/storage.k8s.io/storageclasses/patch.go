@@ -0,0 +1,73 @@
+package storageclasses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (svc StorageClassService) PatchStorageClass(r *restful.Request, w *restful.Response) {
+	storageClassName := r.PathParameter("name")
+
+	patch, err := io.ReadAll(r.Request.Body)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	storageClass, err := svc.adapter.GetStorageClass(r.Request.Context(), storageClassName)
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get storage class: %w", err))
+		return
+	}
+
+	if storageClass == nil && !utils.IsServerSideApply(r) {
+		utils.ResourceNotFound(w, schema.GroupResource{Group: "storage.k8s.io", Resource: "storageclasses"}, storageClassName)
+		return
+	}
+
+	var data []byte
+	if storageClass != nil {
+		data, err = json.Marshal(storageClass)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal storage class: %w", err))
+			return
+		}
+	}
+
+	mergedData, err := utils.PatchResource(r, data, patch, storagev1.StorageClass{})
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
+		return
+	}
+
+	updatedStorageClass := &storagev1.StorageClass{}
+
+	err = json.Unmarshal(mergedData, updatedStorageClass)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to unmarshal storage class: %w", err))
+		return
+	}
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(updatedStorageClass)
+		return
+	}
+
+	err = svc.adapter.CreateStorageClass(r.Request.Context(), updatedStorageClass)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to patch storage class: %w", err))
+		return
+	}
+
+	w.WriteAsJson(updatedStorageClass)
+}
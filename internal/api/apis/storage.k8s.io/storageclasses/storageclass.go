@@ -16,10 +16,30 @@ func NewStorageClassService(adapter *adapter.KubeDockerAdapter) StorageClassServ
 }
 
 func (svc StorageClassService) RegisterStorageClassAPI(ws *restful.WebService) {
+	storageClassGVKExtension := map[string]string{
+		"group":   "storage.k8s.io",
+		"kind":    "StorageClass",
+		"version": "v1",
+	}
+
+	ws.Route(ws.POST("/v1/storageclasses").
+		To(svc.CreateStorageClass).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
 	ws.Route(ws.GET("/v1/storageclasses").
 		To(svc.ListStorageClass))
 
 	ws.Route(ws.GET("/v1/storageclasses/{name}").
 		To(svc.GetStorageClass).
 		Param(ws.PathParameter("name", "name of the storageclass").DataType("string")))
+
+	ws.Route(ws.DELETE("/v1/storageclasses/{name}").
+		To(svc.DeleteStorageClass).
+		Param(ws.PathParameter("name", "name of the storageclass").DataType("string")))
+
+	ws.Route(ws.PATCH("/v1/storageclasses/{name}").
+		To(svc.PatchStorageClass).
+		Param(ws.PathParameter("name", "name of the storageclass").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")).
+		AddExtension("x-kubernetes-group-version-kind", storageClassGVKExtension))
 }
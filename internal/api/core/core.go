@@ -4,6 +4,7 @@ import (
 	restful "github.com/emicklei/go-restful/v3"
 	"github.com/portainer/k2d/internal/adapter"
 	v1 "github.com/portainer/k2d/internal/api/core/v1"
+	"github.com/portainer/k2d/internal/api/utils"
 	"github.com/portainer/k2d/internal/controller"
 )
 
@@ -23,7 +24,7 @@ func NewCoreAPI(adapter *adapter.KubeDockerAdapter, operations chan controller.O
 func (api Core) V1() *restful.WebService {
 	routes := new(restful.WebService).
 		Path("/api").
-		Consumes(restful.MIME_JSON, "application/yml", "application/json-patch+json", "application/merge-patch+json", "application/strategic-merge-patch+json").
+		Consumes(restful.MIME_JSON, "application/yml", "application/json-patch+json", "application/merge-patch+json", "application/strategic-merge-patch+json", utils.ApplyPatchMIME).
 		Produces(restful.MIME_JSON, "application/yml")
 
 	// which versions are served by this api
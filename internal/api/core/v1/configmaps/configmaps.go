@@ -44,6 +44,12 @@ func (svc ConfigMapService) RegisterConfigMapAPI(ws *restful.WebService) {
 		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
 		To(svc.ListConfigMaps))
 
+	ws.Route(ws.DELETE("/v1/namespaces/{namespace}/configmaps").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.DeleteConfigMapCollection).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.QueryParameter("labelSelector", "a selector to restrict the list of deleted objects by their labels").DataType("string")))
+
 	ws.Route(ws.DELETE("/v1/configmaps/{name}").
 		To(svc.DeleteConfigMap).
 		Param(ws.PathParameter("name", "name of the configmap").DataType("string")))
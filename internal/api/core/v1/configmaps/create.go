@@ -28,11 +28,24 @@ func (svc ConfigMapService) CreateConfigMap(r *restful.Request, w *restful.Respo
 
 	dryRun := r.QueryParameter("dryRun") != ""
 	if dryRun {
+		err = svc.adapter.DryRunConfigMap(configMap)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid configMap: %w", err))
+			return
+		}
+
 		w.WriteAsJson(configMap)
 		return
 	}
 
-	svc.operations <- controller.NewOperation(configMap, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	operation := controller.NewOperation(configMap, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	svc.operations <- operation
+
+	err = operation.Wait()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create configmap: %w", err))
+		return
+	}
 
 	w.WriteAsJson(configMap)
 }
@@ -19,6 +19,10 @@ func (svc ConfigMapService) DeleteConfigMap(r *restful.Request, w *restful.Respo
 		return
 	}
 
+	if utils.GetDeletionPropagationPolicy(r) != metav1.DeletePropagationOrphan {
+		svc.adapter.DeleteDependents(r.Request.Context(), "ConfigMap", configMapName, namespace)
+	}
+
 	w.WriteAsJson(metav1.Status{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Status",
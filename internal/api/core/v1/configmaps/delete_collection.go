@@ -0,0 +1,54 @@
+package configmaps
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func (svc ConfigMapService) DeleteConfigMapCollection(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	propagationPolicy := utils.GetDeletionPropagationPolicy(r)
+
+	selector, err := labels.Parse(r.QueryParameter("labelSelector"))
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("invalid selector parameter: %w", err))
+		return
+	}
+
+	configMapList, err := svc.adapter.ListConfigMaps(namespace)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to list configmaps: %w", err))
+		return
+	}
+
+	deletedConfigMaps := corev1.ConfigMapList{
+		TypeMeta: configMapList.TypeMeta,
+		Items:    []corev1.ConfigMap{},
+	}
+
+	for _, configMap := range configMapList.Items {
+		if !selector.Matches(labels.Set(configMap.Labels)) {
+			continue
+		}
+
+		err = svc.adapter.DeleteConfigMap(configMap.Name, namespace)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to delete configmap %s: %w", configMap.Name, err))
+			return
+		}
+
+		if propagationPolicy != metav1.DeletePropagationOrphan {
+			svc.adapter.DeleteDependents(r.Request.Context(), "ConfigMap", configMap.Name, namespace)
+		}
+
+		deletedConfigMaps.Items = append(deletedConfigMaps.Items, configMap)
+	}
+
+	w.WriteAsJson(deletedConfigMaps)
+}
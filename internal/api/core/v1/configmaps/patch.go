@@ -13,7 +13,7 @@ import (
 	"github.com/portainer/k2d/internal/controller"
 	"github.com/portainer/k2d/internal/types"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc ConfigMapService) PatchConfigMap(r *restful.Request, w *restful.Response) {
@@ -27,21 +27,26 @@ func (svc ConfigMapService) PatchConfigMap(r *restful.Request, w *restful.Respon
 	}
 
 	configMap, err := svc.adapter.GetConfigMap(configMapName, namespace)
-	if err != nil && errors.Is(err, adaptererr.ErrResourceNotFound) {
-		w.WriteHeader(http.StatusNotFound)
-		return
-	} else if err != nil {
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get configMap: %w", err))
 		return
 	}
 
-	data, err := json.Marshal(configMap)
-	if err != nil {
-		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal configMap: %w", err))
+	if configMap == nil && !utils.IsServerSideApply(r) {
+		utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "configmaps"}, configMapName)
 		return
 	}
 
-	mergedData, err := strategicpatch.StrategicMergePatch(data, patch, corev1.ConfigMap{})
+	var data []byte
+	if configMap != nil {
+		data, err = json.Marshal(configMap)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal configMap: %w", err))
+			return
+		}
+	}
+
+	mergedData, err := utils.PatchResource(r, data, patch, corev1.ConfigMap{})
 	if err != nil {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
 		return
@@ -57,11 +62,24 @@ func (svc ConfigMapService) PatchConfigMap(r *restful.Request, w *restful.Respon
 
 	dryRun := r.QueryParameter("dryRun") != ""
 	if dryRun {
+		err = svc.adapter.DryRunConfigMap(updatedConfigMap)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid configMap: %w", err))
+			return
+		}
+
 		w.WriteAsJson(updatedConfigMap)
 		return
 	}
 
-	svc.operations <- controller.NewOperation(updatedConfigMap, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	operation := controller.NewOperation(updatedConfigMap, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	svc.operations <- operation
+
+	err = operation.Wait()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to patch configmap: %w", err))
+		return
+	}
 
 	w.WriteAsJson(updatedConfigMap)
 }
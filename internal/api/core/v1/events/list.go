@@ -2,21 +2,32 @@ package events
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 
 	"github.com/emicklei/go-restful/v3"
 	"github.com/portainer/k2d/internal/api/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 )
 
 func (svc EventService) ListEvents(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+
+	selector, err := fields.ParseSelector(r.QueryParameter("fieldSelector"))
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("invalid field selector parameter: %w", err))
+		return
+	}
+
 	utils.ListResources(
 		r,
 		w,
 		func(ctx context.Context) (interface{}, error) {
-			return svc.adapter.ListEvents()
+			return svc.adapter.ListEvents(namespace, selector)
 		},
 		func(ctx context.Context) (*metav1.Table, error) {
-			return svc.adapter.GetEventTable()
+			return svc.adapter.GetEventTable(namespace, selector)
 		},
 	)
 }
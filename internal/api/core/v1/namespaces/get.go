@@ -8,6 +8,7 @@ import (
 	"github.com/emicklei/go-restful/v3"
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc NamespaceService) GetNamespace(r *restful.Request, w *restful.Response) {
@@ -16,7 +17,7 @@ func (svc NamespaceService) GetNamespace(r *restful.Request, w *restful.Response
 	namespace, err := svc.adapter.GetNamespace(r.Request.Context(), namespaceName)
 	if err != nil {
 		if errors.Is(err, adaptererr.ErrResourceNotFound) {
-			w.WriteHeader(http.StatusNotFound)
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "namespaces"}, namespaceName)
 			return
 		}
 
@@ -4,18 +4,15 @@ import (
 	"github.com/emicklei/go-restful/v3"
 	"github.com/portainer/k2d/internal/adapter"
 	"github.com/portainer/k2d/internal/api/utils"
-	"github.com/portainer/k2d/internal/controller"
 )
 
 type NamespaceService struct {
-	adapter    *adapter.KubeDockerAdapter
-	operations chan controller.Operation
+	adapter *adapter.KubeDockerAdapter
 }
 
-func NewNamespaceService(adapter *adapter.KubeDockerAdapter, operations chan controller.Operation) NamespaceService {
+func NewNamespaceService(adapter *adapter.KubeDockerAdapter) NamespaceService {
 	return NamespaceService{
-		adapter:    adapter,
-		operations: operations,
+		adapter: adapter,
 	}
 }
 
@@ -39,7 +36,6 @@ func (svc NamespaceService) RegisterNamespaceAPI(ws *restful.WebService) {
 		Param(ws.PathParameter("namespace", "name of the namespace").DataType("string")))
 
 	ws.Route(ws.PATCH("/v1/namespaces/{namespace}").
-		Filter(utils.NamespaceValidation(svc.adapter)).
 		To(svc.PatchNamespace).
 		Param(ws.PathParameter("namespace", "name of the namespace").DataType("string")).
 		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")).
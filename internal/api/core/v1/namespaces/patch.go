@@ -2,16 +2,18 @@ package namespaces
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
-	"github.com/portainer/k2d/internal/controller"
-	"github.com/portainer/k2d/internal/types"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
 )
 
 func (svc NamespaceService) PatchNamespace(r *restful.Request, w *restful.Response) {
@@ -24,18 +26,26 @@ func (svc NamespaceService) PatchNamespace(r *restful.Request, w *restful.Respon
 	}
 
 	namespace, err := svc.adapter.GetNamespace(r.Request.Context(), namespaceName)
-	if err != nil {
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get namespace: %w", err))
 		return
 	}
 
-	data, err := json.Marshal(namespace)
-	if err != nil {
-		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal namespace: %w", err))
+	if namespace == nil && !utils.IsServerSideApply(r) {
+		utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "namespaces"}, namespaceName)
 		return
 	}
 
-	mergedData, err := strategicpatch.StrategicMergePatch(data, patch, corev1.Namespace{})
+	var data []byte
+	if namespace != nil {
+		data, err = json.Marshal(namespace)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal namespace: %w", err))
+			return
+		}
+	}
+
+	mergedData, err := utils.PatchResource(r, data, patch, corev1.Namespace{})
 	if err != nil {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
 		return
@@ -55,7 +65,19 @@ func (svc NamespaceService) PatchNamespace(r *restful.Request, w *restful.Respon
 		return
 	}
 
-	svc.operations <- controller.NewOperation(updatedNamespace, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	// The network associated with an existing namespace cannot be updated, only the namespaces created
+	// through server-side apply are applied
+	if namespace == nil {
+		err = svc.adapter.CreateNetworkFromNamespace(r.Request.Context(), updatedNamespace)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create namespace: %w", err))
+			return
+		}
+
+		updatedNamespace.CreationTimestamp = metav1.Now()
+		updatedNamespace.UID = uuid.NewUUID()
+		updatedNamespace.ResourceVersion = "1"
+	}
 
 	w.WriteAsJson(updatedNamespace)
 }
@@ -9,6 +9,7 @@ import (
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 
 	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc NodeService) GetNode(r *restful.Request, w *restful.Response) {
@@ -17,7 +18,7 @@ func (svc NodeService) GetNode(r *restful.Request, w *restful.Response) {
 	node, err := svc.adapter.GetNode(r.Request.Context(), name)
 	if err != nil {
 		if errors.Is(err, adaptererr.ErrResourceNotFound) {
-			w.WriteHeader(http.StatusNotFound)
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "nodes"}, name)
 			return
 		}
 
@@ -22,4 +22,8 @@ func (svc NodeService) RegisterNodeAPI(ws *restful.WebService) {
 	ws.Route(ws.GET("/v1/nodes/{name}").
 		To(svc.GetNode).
 		Param(ws.PathParameter("name", "name of the node").DataType("string")))
+
+	ws.Route(ws.PATCH("/v1/nodes/{name}").
+		To(svc.PatchNode).
+		Param(ws.PathParameter("name", "name of the node").DataType("string")))
 }
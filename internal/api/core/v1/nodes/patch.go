@@ -0,0 +1,78 @@
+package nodes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+func (svc NodeService) PatchNode(r *restful.Request, w *restful.Response) {
+	name := r.PathParameter("name")
+
+	patch, err := io.ReadAll(r.Request.Body)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	node, err := svc.adapter.GetNode(r.Request.Context(), name)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "nodes"}, name)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get node: %w", err))
+		return
+	}
+
+	data, err := json.Marshal(node)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal node: %w", err))
+		return
+	}
+
+	mergedData, err := strategicpatch.StrategicMergePatch(data, patch, corev1.Node{})
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
+		return
+	}
+
+	updatedNode := &corev1.Node{}
+
+	err = json.Unmarshal(mergedData, updatedNode)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to unmarshal node: %w", err))
+		return
+	}
+
+	dryRun := r.QueryParameter("dryRun") != ""
+	if dryRun {
+		w.WriteAsJson(updatedNode)
+		return
+	}
+
+	// Only the scheduling state of the node can be updated, other changes are ignored.
+	err = svc.adapter.SetNodeUnschedulable(r.Request.Context(), name, updatedNode.Spec.Unschedulable)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to update node scheduling state: %w", err))
+		return
+	}
+
+	node, err = svc.adapter.GetNode(r.Request.Context(), name)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get node: %w", err))
+		return
+	}
+
+	w.WriteAsJson(node)
+}
@@ -30,7 +30,14 @@ func (svc PersistentVolumeClaimService) CreatePersistentVolumeClaim(r *restful.R
 		return
 	}
 
-	svc.operations <- controller.NewOperation(persistentVolumeClaim, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	operation := controller.NewOperation(persistentVolumeClaim, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	svc.operations <- operation
+
+	err = operation.Wait()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create persistent volume claim: %w", err))
+		return
+	}
 
 	w.WriteAsJson(persistentVolumeClaim)
 }
@@ -2,16 +2,18 @@ package persistentvolumeclaims
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
 	"github.com/portainer/k2d/internal/controller"
 	"github.com/portainer/k2d/internal/types"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc PersistentVolumeClaimService) PatchPersistentVolumeClaim(r *restful.Request, w *restful.Response) {
@@ -25,18 +27,26 @@ func (svc PersistentVolumeClaimService) PatchPersistentVolumeClaim(r *restful.Re
 	}
 
 	persistentVolumeClaim, err := svc.adapter.GetPersistentVolumeClaim(r.Request.Context(), persistentVolumeClaimName, namespace)
-	if err != nil {
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get persistent volume claim: %w", err))
 		return
 	}
 
-	data, err := json.Marshal(persistentVolumeClaim)
-	if err != nil {
-		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal persistent volume claim: %w", err))
+	if persistentVolumeClaim == nil && !utils.IsServerSideApply(r) {
+		utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "persistentvolumeclaims"}, persistentVolumeClaimName)
 		return
 	}
 
-	mergedData, err := strategicpatch.StrategicMergePatch(data, patch, corev1.PersistentVolumeClaim{})
+	var data []byte
+	if persistentVolumeClaim != nil {
+		data, err = json.Marshal(persistentVolumeClaim)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal persistent volume claim: %w", err))
+			return
+		}
+	}
+
+	mergedData, err := utils.PatchResource(r, data, patch, corev1.PersistentVolumeClaim{})
 	if err != nil {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
 		return
@@ -56,7 +66,14 @@ func (svc PersistentVolumeClaimService) PatchPersistentVolumeClaim(r *restful.Re
 		return
 	}
 
-	svc.operations <- controller.NewOperation(updatedPersistentVolumeClaim, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	operation := controller.NewOperation(updatedPersistentVolumeClaim, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	svc.operations <- operation
+
+	err = operation.Wait()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to patch persistent volume claim: %w", err))
+		return
+	}
 
 	w.WriteAsJson(updatedPersistentVolumeClaim)
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/emicklei/go-restful/v3"
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc PersistentVolumeService) GetPersistentVolume(r *restful.Request, w *restful.Response) {
@@ -16,7 +17,7 @@ func (svc PersistentVolumeService) GetPersistentVolume(r *restful.Request, w *re
 	persistentVolume, err := svc.adapter.GetPersistentVolume(r.Request.Context(), persistentVolumeName)
 	if err != nil {
 		if errors.Is(err, adaptererr.ErrResourceNotFound) {
-			w.WriteHeader(http.StatusNotFound)
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "persistentvolumes"}, persistentVolumeName)
 			return
 		}
 
@@ -0,0 +1,117 @@
+package pods
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/types"
+	remotecommandconsts "k8s.io/apimachinery/pkg/util/remotecommand"
+	"k8s.io/client-go/tools/remotecommand"
+	remotecommandserver "k8s.io/kubernetes/pkg/kubelet/cri/streaming/remotecommand"
+)
+
+const (
+	// attachIdleTimeout is the maximum duration an attach session can stay idle before being closed.
+	attachIdleTimeout = 4 * time.Hour
+
+	// attachStreamCreationTimeout is the maximum duration to wait for the client to create the streams.
+	attachStreamCreationTimeout = 30 * time.Second
+)
+
+// AttachToPod handles the HTTP request for the pods/attach subresource.
+// It upgrades the connection using the Kubernetes remote command protocol (SPDY/WebSocket)
+// and streams the stdio of the main process of the container associated with the pod.
+func (svc PodService) AttachToPod(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	podName := r.PathParameter("name")
+
+	streamOpts, err := remotecommandserver.NewOptions(r.Request)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse attach options: %w", err))
+		return
+	}
+
+	attacher := &podAttacher{
+		adapter:   svc.adapter,
+		namespace: namespace,
+	}
+
+	remotecommandserver.ServeAttach(
+		w.ResponseWriter,
+		r.Request,
+		attacher,
+		podName,
+		"",
+		r.QueryParameter("container"),
+		streamOpts,
+		attachIdleTimeout,
+		attachStreamCreationTimeout,
+		remotecommandconsts.SupportedStreamingProtocols,
+	)
+}
+
+// podAttacher implements the remotecommand Attacher interface on top of the Docker container attach API.
+type podAttacher struct {
+	adapter   *adapter.KubeDockerAdapter
+	namespace string
+}
+
+// AttachContainer attaches to the container associated with the pod and copies the data between
+// the Docker hijacked connection and the client streams until the container process exits or the client disconnects.
+// When a TTY is used, the Docker output is not multiplexed and is copied as-is to the stdout stream.
+func (a *podAttacher) AttachContainer(ctx context.Context, podName string, uid types.UID, container string, in io.Reader, out, stderr io.WriteCloser, tty bool, resize <-chan remotecommand.TerminalSize) error {
+	resp, err := a.adapter.AttachToPod(ctx, a.namespace, podName, adapter.PodAttachOptions{
+		Stdin:  in != nil,
+		Stdout: out != nil,
+		Stderr: stderr != nil,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to attach to pod: %w", err)
+	}
+	defer resp.Close()
+
+	if tty && resize != nil {
+		go func() {
+			for size := range resize {
+				_ = a.adapter.ResizePodTerminal(ctx, a.namespace, podName, uint(size.Height), uint(size.Width))
+			}
+		}()
+	}
+
+	if in != nil {
+		go func() {
+			_, _ = io.Copy(resp.Conn, in)
+			_ = resp.CloseWrite()
+		}()
+	}
+
+	if out == nil && stderr == nil {
+		<-ctx.Done()
+		return nil
+	}
+
+	if tty {
+		_, err = io.Copy(out, resp.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(writerOrDiscard(out), writerOrDiscard(stderr), resp.Reader)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to stream container output: %w", err)
+	}
+
+	return nil
+}
+
+func writerOrDiscard(w io.Writer) io.Writer {
+	if w == nil {
+		return io.Discard
+	}
+	return w
+}
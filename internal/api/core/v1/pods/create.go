@@ -26,11 +26,24 @@ func (svc PodService) CreatePod(r *restful.Request, w *restful.Response) {
 
 	dryRun := r.QueryParameter("dryRun") != ""
 	if dryRun {
+		err = svc.adapter.DryRunPod(pod)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid pod: %w", err))
+			return
+		}
+
 		w.WriteAsJson(pod)
 		return
 	}
 
-	svc.operations <- controller.NewOperation(pod, controller.MediumPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	operation := controller.NewOperation(pod, controller.MediumPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	svc.operations <- operation
+
+	err = operation.Wait()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create pod: %w", err))
+		return
+	}
 
 	w.WriteAsJson(pod)
 }
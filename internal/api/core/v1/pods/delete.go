@@ -14,6 +14,10 @@ func (svc PodService) DeletePod(r *restful.Request, w *restful.Response) {
 	podName := r.PathParameter("name")
 	svc.adapter.DeletePod(r.Request.Context(), podName, namespace)
 
+	if utils.GetDeletionPropagationPolicy(r) != metav1.DeletePropagationOrphan {
+		svc.adapter.DeleteDependents(r.Request.Context(), "Pod", podName, namespace)
+	}
+
 	w.WriteAsJson(metav1.Status{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Status",
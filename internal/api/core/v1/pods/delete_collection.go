@@ -0,0 +1,54 @@
+package pods
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func (svc PodService) DeletePodCollection(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	propagationPolicy := utils.GetDeletionPropagationPolicy(r)
+
+	selector, err := labels.Parse(r.QueryParameter("labelSelector"))
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("invalid selector parameter: %w", err))
+		return
+	}
+
+	podList, err := svc.adapter.ListPods(r.Request.Context(), namespace)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to list pods: %w", err))
+		return
+	}
+
+	deletedPods := corev1.PodList{
+		TypeMeta: podList.TypeMeta,
+		Items:    []corev1.Pod{},
+	}
+
+	for _, pod := range podList.Items {
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+
+		err = svc.adapter.DeletePod(r.Request.Context(), pod.Name, namespace)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to delete pod %s: %w", pod.Name, err))
+			return
+		}
+
+		if propagationPolicy != metav1.DeletePropagationOrphan {
+			svc.adapter.DeleteDependents(r.Request.Context(), "Pod", pod.Name, namespace)
+		}
+
+		deletedPods.Items = append(deletedPods.Items, pod)
+	}
+
+	w.WriteAsJson(deletedPods)
+}
@@ -0,0 +1,82 @@
+package pods
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/api/utils"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EvictPod handles the HTTP request for the pods/eviction subresource.
+// The pod is evicted synchronously: its preStop hook is executed and its container is stopped
+// within the grace period (either the one specified in the eviction delete options or the one defined in the pod spec)
+// before being removed.
+func (svc PodService) EvictPod(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	podName := r.PathParameter("name")
+
+	eviction := &policyv1.Eviction{}
+	err := r.ReadEntity(eviction)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	if eviction.Name != "" && eviction.Name != podName {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("name in URL does not match name in Eviction object: %w", adaptererr.ErrInvalidResource))
+		return
+	}
+
+	var gracePeriodSeconds *int64
+	dryRun := r.QueryParameter("dryRun") != ""
+	if eviction.DeleteOptions != nil {
+		gracePeriodSeconds = eviction.DeleteOptions.GracePeriodSeconds
+		dryRun = dryRun || len(eviction.DeleteOptions.DryRun) > 0
+	}
+
+	if dryRun {
+		_, err := svc.adapter.GetPod(r.Request.Context(), podName, namespace)
+		if err != nil {
+			if errors.Is(err, adaptererr.ErrResourceNotFound) {
+				utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "pods"}, podName)
+				return
+			}
+
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get pod: %w", err))
+			return
+		}
+
+		writeEvictionStatus(w)
+		return
+	}
+
+	err = svc.adapter.EvictPod(r.Request.Context(), podName, namespace, gracePeriodSeconds)
+	if err != nil {
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "pods"}, podName)
+			return
+		}
+
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to evict pod: %w", err))
+		return
+	}
+
+	writeEvictionStatus(w)
+}
+
+func writeEvictionStatus(w *restful.Response) {
+	w.WriteHeaderAndJson(http.StatusCreated, metav1.Status{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Status",
+			APIVersion: "v1",
+		},
+		Status: metav1.StatusSuccess,
+		Code:   http.StatusCreated,
+	}, restful.MIME_JSON)
+}
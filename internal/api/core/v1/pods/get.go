@@ -6,18 +6,26 @@ import (
 	"net/http"
 
 	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc PodService) GetPod(r *restful.Request, w *restful.Response) {
 	namespace := utils.GetNamespaceFromRequest(r)
 	podName := r.PathParameter("name")
 
+	export, exact, err := utils.GetExportOptions(r)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, err)
+		return
+	}
+
 	pod, err := svc.adapter.GetPod(r.Request.Context(), podName, namespace)
 	if err != nil {
 		if errors.Is(err, adaptererr.ErrResourceNotFound) {
-			w.WriteHeader(http.StatusNotFound)
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "pods"}, podName)
 			return
 		}
 
@@ -25,5 +33,9 @@ func (svc PodService) GetPod(r *restful.Request, w *restful.Response) {
 		return
 	}
 
+	if export {
+		adapter.ExportPod(pod, exact)
+	}
+
 	w.WriteAsJson(pod)
 }
@@ -2,19 +2,27 @@ package pods
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/emicklei/go-restful/v3"
 	"github.com/portainer/k2d/internal/adapter"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // GetPodLogs handles the HTTP request for retrieving logs from a pod.
 // It fetches the logs using the provided adapter and writes them to the HTTP response.
 // If the "follow" query parameter is set to true, it streams the logs to the response.
+// The "sinceSeconds", "sinceTime", "limitBytes" and "previous" query parameters are supported
+// and behave as documented in the Kubernetes PodLogOptions.
 // This function sets the necessary headers for streaming and uses a custom writer
 // (flushWriter) that invokes the http.Flusher interface on every Write call to ensure
 // the data is immediately sent to the client.
@@ -26,18 +34,69 @@ func (svc PodService) GetPodLogs(r *restful.Request, w *restful.Response) {
 		Follow:     r.QueryParameter("follow") == "true",
 		Timestamps: r.QueryParameter("timestamps") == "true",
 		Tail:       r.QueryParameter("tailLines"),
+		Previous:   r.QueryParameter("previous") == "true",
+	}
+
+	since, err := parseSinceParameters(r.QueryParameter("sinceSeconds"), r.QueryParameter("sinceTime"))
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, err)
+		return
+	}
+	podLogOptions.Since = since
+
+	var limitBytes int64
+	if r.QueryParameter("limitBytes") != "" {
+		limitBytes, err = strconv.ParseInt(r.QueryParameter("limitBytes"), 10, 64)
+		if err != nil || limitBytes <= 0 {
+			utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("invalid limitBytes value: %s", r.QueryParameter("limitBytes")))
+			return
+		}
+	}
+
+	containerName := r.QueryParameter("container")
+	if containerName != "" {
+		pod, err := svc.adapter.GetPod(r.Request.Context(), podName, namespace)
+		if err != nil {
+			if errors.Is(err, adaptererr.ErrResourceNotFound) {
+				utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "pods"}, podName)
+				return
+			}
+
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get pod: %w", err))
+			return
+		}
+
+		if !podHasContainer(pod, containerName) {
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "containers"}, containerName)
+			return
+		}
 	}
 
 	logs, err := svc.adapter.GetPodLogs(context.Background(), namespace, podName, podLogOptions)
 	if err != nil {
+		if podLogOptions.Previous && errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("previous terminated container not found for pod %s", podName))
+			return
+		}
+
+		if errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "pods"}, podName)
+			return
+		}
+
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get pod logs: %w", err))
 		return
 	}
 	defer logs.Close()
 
+	var output io.Writer = w
+	if limitBytes > 0 {
+		output = &limitWriter{w: w, remaining: limitBytes}
+	}
+
 	if !podLogOptions.Follow {
-		_, err := stdcopy.StdCopy(w, w, logs)
-		if err != nil {
+		_, err := stdcopy.StdCopy(output, output, logs)
+		if err != nil && !errors.Is(err, errLimitReached) {
 			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to copy logs: %w", err))
 			return
 		}
@@ -51,15 +110,19 @@ func (svc PodService) GetPodLogs(r *restful.Request, w *restful.Response) {
 
 	// Use a flusher to allow streaming data in the HTTP response
 	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
-		fw := &flushWriter{w: w, flusher: flusher}
-		_, err = stdcopy.StdCopy(fw, w, logs)
-		if err != nil {
+		var fw io.Writer = &flushWriter{w: w, flusher: flusher}
+		if limitBytes > 0 {
+			fw = &limitWriter{w: fw, remaining: limitBytes}
+		}
+
+		_, err = stdcopy.StdCopy(fw, fw, logs)
+		if err != nil && !errors.Is(err, errLimitReached) {
 			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to stream logs: %w", err))
 			return
 		}
 	} else {
 		// Fallback to normal HTTP response
-		_, _ = stdcopy.StdCopy(w, w, logs)
+		_, _ = stdcopy.StdCopy(output, output, logs)
 	}
 }
 
@@ -82,3 +145,80 @@ func (fw *flushWriter) Write(p []byte) (n int, err error) {
 	fw.flusher.Flush()
 	return n, nil
 }
+
+// podHasContainer returns true if the pod defines a container with the specified name.
+// The name of the container in the pod status is also accepted as it is based on the
+// name of the Docker container backing the pod.
+func podHasContainer(pod *corev1.Pod, containerName string) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == containerName {
+			return true
+		}
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == containerName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseSinceParameters converts the sinceSeconds and sinceTime query parameters into
+// a value that can be used with the Docker logs API.
+// Only one of the two parameters can be specified.
+func parseSinceParameters(sinceSeconds, sinceTime string) (string, error) {
+	if sinceSeconds != "" && sinceTime != "" {
+		return "", errors.New("at most one of sinceSeconds or sinceTime may be specified")
+	}
+
+	if sinceSeconds != "" {
+		seconds, err := strconv.ParseInt(sinceSeconds, 10, 64)
+		if err != nil || seconds <= 0 {
+			return "", fmt.Errorf("invalid sinceSeconds value: %s", sinceSeconds)
+		}
+		return fmt.Sprintf("%ds", seconds), nil
+	}
+
+	if sinceTime != "" {
+		t, err := time.Parse(time.RFC3339, sinceTime)
+		if err != nil {
+			return "", fmt.Errorf("invalid sinceTime value: %w", err)
+		}
+		return strconv.FormatInt(t.Unix(), 10), nil
+	}
+
+	return "", nil
+}
+
+// errLimitReached is returned by a limitWriter once the maximum number of bytes has been written.
+var errLimitReached = errors.New("log output limit reached")
+
+// limitWriter is a custom io.Writer that stops writing to the underlying writer
+// once a maximum number of bytes has been written.
+type limitWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+// Write writes at most the remaining number of bytes to the underlying writer.
+// It returns errLimitReached once the limit has been reached.
+func (lw *limitWriter) Write(p []byte) (n int, err error) {
+	if lw.remaining <= 0 {
+		return 0, errLimitReached
+	}
+
+	if int64(len(p)) > lw.remaining {
+		n, err = lw.w.Write(p[:lw.remaining])
+		lw.remaining -= int64(n)
+		if err != nil {
+			return n, err
+		}
+		return n, errLimitReached
+	}
+
+	n, err = lw.w.Write(p)
+	lw.remaining -= int64(n)
+	return n, err
+}
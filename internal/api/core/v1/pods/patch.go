@@ -2,16 +2,18 @@ package pods
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
 	"github.com/portainer/k2d/internal/controller"
 	"github.com/portainer/k2d/internal/types"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc PodService) PatchPod(r *restful.Request, w *restful.Response) {
@@ -25,23 +27,26 @@ func (svc PodService) PatchPod(r *restful.Request, w *restful.Response) {
 	}
 
 	pod, err := svc.adapter.GetPod(r.Request.Context(), podName, namespace)
-	if err != nil {
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get pod: %w", err))
 		return
 	}
 
-	if pod == nil {
-		w.WriteHeader(http.StatusNotFound)
+	if pod == nil && !utils.IsServerSideApply(r) {
+		utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "pods"}, podName)
 		return
 	}
 
-	data, err := json.Marshal(pod)
-	if err != nil {
-		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal pod: %w", err))
-		return
+	var data []byte
+	if pod != nil {
+		data, err = json.Marshal(pod)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal pod: %w", err))
+			return
+		}
 	}
 
-	mergedData, err := strategicpatch.StrategicMergePatch(data, patch, corev1.Pod{})
+	mergedData, err := utils.PatchResource(r, data, patch, corev1.Pod{})
 	if err != nil {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
 		return
@@ -57,11 +62,24 @@ func (svc PodService) PatchPod(r *restful.Request, w *restful.Response) {
 
 	dryRun := r.QueryParameter("dryRun") != ""
 	if dryRun {
+		err = svc.adapter.DryRunPod(updatedPod)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid pod: %w", err))
+			return
+		}
+
 		w.WriteAsJson(updatedPod)
 		return
 	}
 
-	svc.operations <- controller.NewOperation(updatedPod, controller.MediumPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	operation := controller.NewOperation(updatedPod, controller.MediumPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	svc.operations <- operation
+
+	err = operation.Wait()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to patch pod: %w", err))
+		return
+	}
 
 	w.WriteAsJson(updatedPod)
 }
@@ -37,12 +37,22 @@ func (svc PodService) RegisterPodAPI(ws *restful.WebService) {
 		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
 
 	ws.Route(ws.GET("/v1/pods").
-		To(svc.ListPods))
+		To(svc.ListPods).
+		Param(ws.QueryParameter("fieldSelector", "a selector to restrict the list of returned objects by their name and namespace").DataType("string")).
+		Param(ws.QueryParameter("watch", "when true, the changes of the pods are streamed as watch events").DataType("boolean")))
 
 	ws.Route(ws.GET("/v1/namespaces/{namespace}/pods").
 		Filter(utils.NamespaceValidation(svc.adapter)).
 		To(svc.ListPods).
-		Param(ws.PathParameter("namespace", "namespace name").DataType("string")))
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.QueryParameter("fieldSelector", "a selector to restrict the list of returned objects by their name and namespace").DataType("string")).
+		Param(ws.QueryParameter("watch", "when true, the changes of the pods are streamed as watch events").DataType("boolean")))
+
+	ws.Route(ws.DELETE("/v1/namespaces/{namespace}/pods").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.DeletePodCollection).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.QueryParameter("labelSelector", "a selector to restrict the list of deleted objects by their labels").DataType("string")))
 
 	ws.Route(ws.DELETE("/v1/pods/{name}").
 		To(svc.DeletePod).
@@ -55,13 +65,17 @@ func (svc PodService) RegisterPodAPI(ws *restful.WebService) {
 
 	ws.Route(ws.GET("/v1/pods/{name}").
 		To(svc.GetPod).
-		Param(ws.PathParameter("name", "name of the pod").DataType("string")))
+		Param(ws.PathParameter("name", "name of the pod").DataType("string")).
+		Param(ws.QueryParameter("export", "when true, the pod is stripped of the fields populated by the server").DataType("boolean")).
+		Param(ws.QueryParameter("exact", "when true, the namespace is kept in the exported pod").DataType("boolean")))
 
 	ws.Route(ws.GET("/v1/namespaces/{namespace}/pods/{name}").
 		Filter(utils.NamespaceValidation(svc.adapter)).
 		To(svc.GetPod).
 		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
-		Param(ws.PathParameter("name", "name of the pod").DataType("string")))
+		Param(ws.PathParameter("name", "name of the pod").DataType("string")).
+		Param(ws.QueryParameter("export", "when true, the pod is stripped of the fields populated by the server").DataType("boolean")).
+		Param(ws.QueryParameter("exact", "when true, the namespace is kept in the exported pod").DataType("boolean")))
 
 	ws.Route(ws.PATCH("/v1/pods/{name}").
 		To(svc.PatchPod).
@@ -77,12 +91,46 @@ func (svc PodService) RegisterPodAPI(ws *restful.WebService) {
 		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")).
 		AddExtension("x-kubernetes-group-version-kind", podGVKExtension))
 
+	ws.Route(ws.POST("/v1/namespaces/{namespace}/pods/{name}/attach").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.AttachToPod).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the pod").DataType("string")).
+		Param(ws.QueryParameter("container", "the container to attach to").DataType("string")).
+		Param(ws.QueryParameter("stdin", "redirect the standard input stream of the pod for this call").DataType("boolean")).
+		Param(ws.QueryParameter("stdout", "redirect the standard output stream of the pod for this call").DataType("boolean")).
+		Param(ws.QueryParameter("stderr", "redirect the standard error stream of the pod for this call").DataType("boolean")).
+		Param(ws.QueryParameter("tty", "allocate a terminal for this attach call").DataType("boolean")))
+
+	ws.Route(ws.GET("/v1/namespaces/{namespace}/pods/{name}/attach").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.AttachToPod).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the pod").DataType("string")).
+		Param(ws.QueryParameter("container", "the container to attach to").DataType("string")).
+		Param(ws.QueryParameter("stdin", "redirect the standard input stream of the pod for this call").DataType("boolean")).
+		Param(ws.QueryParameter("stdout", "redirect the standard output stream of the pod for this call").DataType("boolean")).
+		Param(ws.QueryParameter("stderr", "redirect the standard error stream of the pod for this call").DataType("boolean")).
+		Param(ws.QueryParameter("tty", "allocate a terminal for this attach call").DataType("boolean")))
+
+	ws.Route(ws.POST("/v1/namespaces/{namespace}/pods/{name}/eviction").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.EvictPod).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.PathParameter("name", "name of the pod").DataType("string")).
+		Param(ws.QueryParameter("dryRun", "when present, indicates that modifications should not be persisted").DataType("string")))
+
 	ws.Route(ws.GET("/v1/namespaces/{namespace}/pods/{name}/log").
 		Filter(utils.NamespaceValidation(svc.adapter)).
-		To(svc.GetPodLogs)).
+		To(svc.GetPodLogs).
 		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
 		Param(ws.PathParameter("name", "name of the pod").DataType("string")).
+		Param(ws.QueryParameter("container", "the container for which to stream logs").DataType("string")).
 		Param(ws.QueryParameter("follow", "follow the log stream of the pod").DataType("boolean")).
 		Param(ws.QueryParameter("tailLines", "the number of lines from the end of the logs to show").DataType("integer")).
-		Param(ws.QueryParameter("timestamps", "add an RFC3339 or RFC3339Nano timestamp at the beginning of every line of log output").DataType("boolean"))
+		Param(ws.QueryParameter("timestamps", "add an RFC3339 or RFC3339Nano timestamp at the beginning of every line of log output").DataType("boolean")).
+		Param(ws.QueryParameter("sinceSeconds", "a relative time in seconds before the current time from which to show logs").DataType("integer")).
+		Param(ws.QueryParameter("sinceTime", "an RFC3339 timestamp from which to show logs").DataType("string")).
+		Param(ws.QueryParameter("limitBytes", "the number of bytes to read from the server before terminating the log output").DataType("integer")).
+		Param(ws.QueryParameter("previous", "return previous terminated container logs").DataType("boolean")))
 }
@@ -1,36 +1,82 @@
 package secrets
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
 	"github.com/portainer/k2d/internal/controller"
 	"github.com/portainer/k2d/internal/types"
-	httputils "github.com/portainer/k2d/pkg/http"
-	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc SecretService) CreateSecret(r *restful.Request, w *restful.Response) {
 	namespace := utils.GetNamespaceFromRequest(r)
 
-	secret := &corev1.Secret{}
-	err := httputils.ParseJSONBody(r.Request, &secret)
+	body, err := io.ReadAll(r.Request.Body)
 	if err != nil {
 		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
 		return
 	}
 
+	secret, err := decodeSecret(body)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, err)
+		return
+	}
+
 	secret.Namespace = namespace
 
 	dryRun := r.QueryParameter("dryRun") != ""
 	if dryRun {
+		err = svc.adapter.DryRunSecret(secret)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid secret: %w", err))
+			return
+		}
+
 		w.WriteAsJson(secret)
 		return
 	}
 
-	svc.operations <- controller.NewOperation(secret, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	// Helm release secrets are created synchronously as Helm reads them back right after their creation
+	// and relies on an AlreadyExists error to detect conflicting releases.
+	if secret.Type == adapter.HelmReleaseSecretType {
+		_, err := svc.adapter.GetSecret(secret.Name, namespace)
+		if err == nil {
+			utils.HttpError(r, w, http.StatusConflict, apierr.NewAlreadyExists(schema.GroupResource{Group: "", Resource: "secrets"}, secret.Name))
+			return
+		}
+
+		if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get secret: %w", err))
+			return
+		}
+
+		err = svc.adapter.CreateSecret(secret)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create secret: %w", err))
+			return
+		}
+
+		w.WriteHeaderAndJson(http.StatusCreated, secret, restful.MIME_JSON)
+		return
+	}
+
+	operation := controller.NewOperation(secret, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	svc.operations <- operation
+
+	err = operation.Wait()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create secret: %w", err))
+		return
+	}
 
 	w.WriteAsJson(secret)
 }
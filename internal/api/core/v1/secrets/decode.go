@@ -0,0 +1,31 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/portainer/k2d/internal/adapter"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// decodeSecret decodes a JSON encoded secret received through the API and prepares it as done by the Kubernetes API server
+// (see adapter.PrepareSecret). An invalid base64 value or an invalid key is reported with an ErrInvalidResource error.
+func decodeSecret(data []byte) (*corev1.Secret, error) {
+	err := adapter.ValidateSecretDataEncoding(data)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &corev1.Secret{}
+	err = json.Unmarshal(data, secret)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse request body: %w", err)
+	}
+
+	err = adapter.PrepareSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
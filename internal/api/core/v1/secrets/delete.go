@@ -19,6 +19,10 @@ func (svc SecretService) DeleteSecret(r *restful.Request, w *restful.Response) {
 		return
 	}
 
+	if utils.GetDeletionPropagationPolicy(r) != metav1.DeletePropagationOrphan {
+		svc.adapter.DeleteDependents(r.Request.Context(), "Secret", secretName, namespace)
+	}
+
 	w.WriteAsJson(metav1.Status{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Status",
@@ -0,0 +1,50 @@
+package secrets
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func (svc SecretService) DeleteSecretCollection(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	propagationPolicy := utils.GetDeletionPropagationPolicy(r)
+
+	selector, err := labels.Parse(r.QueryParameter("labelSelector"))
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("invalid selector parameter: %w", err))
+		return
+	}
+
+	secretList, err := svc.adapter.ListSecrets(namespace, selector)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to list secrets: %w", err))
+		return
+	}
+
+	deletedSecrets := corev1.SecretList{
+		TypeMeta: secretList.TypeMeta,
+		Items:    []corev1.Secret{},
+	}
+
+	for _, secret := range secretList.Items {
+		err = svc.adapter.DeleteSecret(secret.Name, namespace)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to delete secret %s: %w", secret.Name, err))
+			return
+		}
+
+		if propagationPolicy != metav1.DeletePropagationOrphan {
+			svc.adapter.DeleteDependents(r.Request.Context(), "Secret", secret.Name, namespace)
+		}
+
+		deletedSecrets.Items = append(deletedSecrets.Items, secret)
+	}
+
+	w.WriteAsJson(deletedSecrets)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/emicklei/go-restful/v3"
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc SecretService) GetSecret(r *restful.Request, w *restful.Response) {
@@ -17,7 +18,7 @@ func (svc SecretService) GetSecret(r *restful.Request, w *restful.Response) {
 	secret, err := svc.adapter.GetSecret(secretName, namespace)
 	if err != nil {
 		if errors.Is(err, adaptererr.ErrResourceNotFound) {
-			w.WriteHeader(http.StatusNotFound)
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "secrets"}, secretName)
 			return
 		}
 
@@ -13,7 +13,7 @@ import (
 	"github.com/portainer/k2d/internal/controller"
 	"github.com/portainer/k2d/internal/types"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc SecretService) PatchSecret(r *restful.Request, w *restful.Response) {
@@ -27,41 +27,57 @@ func (svc SecretService) PatchSecret(r *restful.Request, w *restful.Response) {
 	}
 
 	secret, err := svc.adapter.GetSecret(secretName, namespace)
-	if err != nil && errors.Is(err, adaptererr.ErrResourceNotFound) {
-		w.WriteHeader(http.StatusNotFound)
-		return
-	} else if err != nil {
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get secret: %w", err))
 		return
 	}
 
-	data, err := json.Marshal(secret)
-	if err != nil {
-		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal secret: %w", err))
+	if secret == nil && !utils.IsServerSideApply(r) {
+		utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "secrets"}, secretName)
 		return
 	}
 
-	mergedData, err := strategicpatch.StrategicMergePatch(data, patch, corev1.Secret{})
+	var data []byte
+	if secret != nil {
+		data, err = json.Marshal(secret)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal secret: %w", err))
+			return
+		}
+	}
+
+	mergedData, err := utils.PatchResource(r, data, patch, corev1.Secret{})
 	if err != nil {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
 		return
 	}
 
-	updatedSecret := &corev1.Secret{}
-
-	err = json.Unmarshal(mergedData, updatedSecret)
+	updatedSecret, err := decodeSecret(mergedData)
 	if err != nil {
-		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to unmarshal secret: %w", err))
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("invalid patched secret: %w", err))
 		return
 	}
 
 	dryRun := r.QueryParameter("dryRun") != ""
 	if dryRun {
+		err = svc.adapter.DryRunSecret(updatedSecret)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid secret: %w", err))
+			return
+		}
+
 		w.WriteAsJson(updatedSecret)
 		return
 	}
 
-	svc.operations <- controller.NewOperation(updatedSecret, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	operation := controller.NewOperation(updatedSecret, controller.HighPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	svc.operations <- operation
+
+	err = operation.Wait()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to patch secret: %w", err))
+		return
+	}
 
 	w.WriteAsJson(updatedSecret)
 }
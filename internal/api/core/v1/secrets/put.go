@@ -3,29 +3,40 @@ package secrets
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/emicklei/go-restful/v3"
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
-	httputils "github.com/portainer/k2d/pkg/http"
-	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc SecretService) PutSecret(r *restful.Request, w *restful.Response) {
 	namespace := utils.GetNamespaceFromRequest(r)
 
 	secretName := r.PathParameter("name")
-	secret := &corev1.Secret{}
-	err := httputils.ParseJSONBody(r.Request, &secret)
+	body, err := io.ReadAll(r.Request.Body)
 	if err != nil {
 		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
 		return
 	}
 
+	secret, err := decodeSecret(body)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, err)
+		return
+	}
+
 	dryRun := r.QueryParameter("dryRun") != ""
 	if dryRun {
+		err = svc.adapter.DryRunSecret(secret)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid secret: %w", err))
+			return
+		}
+
 		w.WriteAsJson(secret)
 		return
 	}
@@ -60,7 +71,7 @@ func (svc SecretService) PutSecret(r *restful.Request, w *restful.Response) {
 		case <-time.After(1 * time.Second):
 			continue
 		case <-timeoutCh:
-			w.WriteHeader(http.StatusNotFound)
+			utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "secrets"}, secretName)
 			return
 		}
 	}
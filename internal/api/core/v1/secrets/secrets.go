@@ -46,6 +46,12 @@ func (svc SecretService) RegisterSecretAPI(ws *restful.WebService) {
 		Param(ws.QueryParameter("labelSelector", "a selector to restrict the list of returned objects by their labels").DataType("string")).
 		To(svc.ListSecrets))
 
+	ws.Route(ws.DELETE("/v1/namespaces/{namespace}/secrets").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.DeleteSecretCollection).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.QueryParameter("labelSelector", "a selector to restrict the list of deleted objects by their labels").DataType("string")))
+
 	ws.Route(ws.DELETE("/v1/secrets/{name}").
 		To(svc.DeleteSecret).
 		Param(ws.PathParameter("name", "name of the secret").DataType("string")))
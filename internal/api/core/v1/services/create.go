@@ -26,11 +26,24 @@ func (svc ServiceService) CreateService(r *restful.Request, w *restful.Response)
 
 	dryRun := r.QueryParameter("dryRun") != ""
 	if dryRun {
+		err = svc.adapter.DryRunService(service)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid service: %w", err))
+			return
+		}
+
 		w.WriteAsJson(service)
 		return
 	}
 
-	svc.operations <- controller.NewOperation(service, controller.LowPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	operation := controller.NewOperation(service, controller.LowPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	svc.operations <- operation
+
+	err = operation.Wait()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create service: %w", err))
+		return
+	}
 
 	w.WriteAsJson(service)
 }
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func (svc ServiceService) DeleteServiceCollection(r *restful.Request, w *restful.Response) {
+	namespace := utils.GetNamespaceFromRequest(r)
+	propagationPolicy := utils.GetDeletionPropagationPolicy(r)
+
+	selector, err := labels.Parse(r.QueryParameter("labelSelector"))
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("invalid selector parameter: %w", err))
+		return
+	}
+
+	serviceList, err := svc.adapter.ListServices(r.Request.Context(), namespace)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to list services: %w", err))
+		return
+	}
+
+	deletedServices := corev1.ServiceList{
+		TypeMeta: serviceList.TypeMeta,
+		Items:    []corev1.Service{},
+	}
+
+	for _, service := range serviceList.Items {
+		if !selector.Matches(labels.Set(service.Labels)) {
+			continue
+		}
+
+		err = svc.adapter.DeleteService(r.Request.Context(), service.Name, namespace)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to delete service %s: %w", service.Name, err))
+			return
+		}
+
+		if propagationPolicy != metav1.DeletePropagationOrphan {
+			svc.adapter.DeleteDependents(r.Request.Context(), "Service", service.Name, namespace)
+		}
+
+		deletedServices.Items = append(deletedServices.Items, service)
+	}
+
+	w.WriteAsJson(deletedServices)
+}
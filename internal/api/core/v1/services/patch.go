@@ -2,16 +2,18 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/api/utils"
 	"github.com/portainer/k2d/internal/controller"
 	"github.com/portainer/k2d/internal/types"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func (svc ServiceService) PatchService(r *restful.Request, w *restful.Response) {
@@ -25,23 +27,26 @@ func (svc ServiceService) PatchService(r *restful.Request, w *restful.Response)
 	}
 
 	service, err := svc.adapter.GetService(r.Request.Context(), serviceName, namespace)
-	if err != nil {
+	if err != nil && !errors.Is(err, adaptererr.ErrResourceNotFound) {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get service: %w", err))
 		return
 	}
 
-	if service == nil {
-		w.WriteHeader(http.StatusNotFound)
+	if service == nil && !utils.IsServerSideApply(r) {
+		utils.ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "services"}, serviceName)
 		return
 	}
 
-	data, err := json.Marshal(service)
-	if err != nil {
-		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal service: %w", err))
-		return
+	var data []byte
+	if service != nil {
+		data, err = json.Marshal(service)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to marshal service: %w", err))
+			return
+		}
 	}
 
-	mergedData, err := strategicpatch.StrategicMergePatch(data, patch, corev1.Service{})
+	mergedData, err := utils.PatchResource(r, data, patch, corev1.Service{})
 	if err != nil {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to apply patch: %w", err))
 		return
@@ -57,11 +62,24 @@ func (svc ServiceService) PatchService(r *restful.Request, w *restful.Response)
 
 	dryRun := r.QueryParameter("dryRun") != ""
 	if dryRun {
+		err = svc.adapter.DryRunService(updatedService)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusUnprocessableEntity, fmt.Errorf("invalid service: %w", err))
+			return
+		}
+
 		w.WriteAsJson(updatedService)
 		return
 	}
 
-	svc.operations <- controller.NewOperation(updatedService, controller.LowPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	operation := controller.NewOperation(updatedService, controller.LowPriorityOperation, r.HeaderParameter(types.RequestIDHeader))
+	svc.operations <- operation
+
+	err = operation.Wait()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to patch service: %w", err))
+		return
+	}
 
 	w.WriteAsJson(updatedService)
 }
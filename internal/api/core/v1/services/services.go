@@ -44,6 +44,12 @@ func (svc ServiceService) RegisterServiceAPI(ws *restful.WebService) {
 		To(svc.ListServices).
 		Param(ws.PathParameter("namespace", "namespace name").DataType("string")))
 
+	ws.Route(ws.DELETE("/v1/namespaces/{namespace}/services").
+		Filter(utils.NamespaceValidation(svc.adapter)).
+		To(svc.DeleteServiceCollection).
+		Param(ws.PathParameter("namespace", "namespace name").DataType("string")).
+		Param(ws.QueryParameter("labelSelector", "a selector to restrict the list of deleted objects by their labels").DataType("string")))
+
 	ws.Route(ws.DELETE("/v1/services/{name}").
 		To(svc.DeleteService).
 		Param(ws.PathParameter("name", "name of the service").DataType("string")))
@@ -32,7 +32,7 @@ func NewV1Service(adapter *adapter.KubeDockerAdapter, operations chan controller
 	return V1Service{
 		configMaps:             configmaps.NewConfigMapService(adapter, operations),
 		events:                 events.NewEventService(adapter),
-		namespaces:             namespaces.NewNamespaceService(adapter, operations),
+		namespaces:             namespaces.NewNamespaceService(adapter),
 		nodes:                  nodes.NewNodeService(adapter),
 		persistentvolumes:      persistentvolumes.NewPersistentVolumeService(adapter),
 		persistentvolumeclaims: persistentvolumeclaims.NewPersistentVolumeClaimService(adapter, operations),
@@ -65,7 +65,7 @@ func (svc V1Service) ListAPIResources(r *restful.Request, w *restful.Response) {
 				Kind:         "ConfigMap",
 				SingularName: "",
 				Name:         "configmaps",
-				Verbs:        []string{"create", "list", "delete", "get", "patch"},
+				Verbs:        []string{"create", "list", "delete", "deletecollection", "get", "patch"},
 				Namespaced:   true,
 				ShortNames:   []string{"cm"},
 			},
@@ -88,7 +88,7 @@ func (svc V1Service) ListAPIResources(r *restful.Request, w *restful.Response) {
 				Kind:         "Node",
 				SingularName: "",
 				Name:         "nodes",
-				Verbs:        []string{"list", "get"},
+				Verbs:        []string{"list", "get", "patch"},
 				Namespaced:   false,
 			},
 			{
@@ -111,21 +111,37 @@ func (svc V1Service) ListAPIResources(r *restful.Request, w *restful.Response) {
 				Kind:         "Pod",
 				SingularName: "",
 				Name:         "pods",
-				Verbs:        []string{"create", "list", "delete", "get", "patch"},
+				Verbs:        []string{"create", "list", "delete", "deletecollection", "get", "patch"},
+				Namespaced:   true,
+			},
+			{
+				Kind:         "PodAttachOptions",
+				SingularName: "",
+				Name:         "pods/attach",
+				Verbs:        []string{"create", "get"},
+				Namespaced:   true,
+			},
+			{
+				Kind:         "Eviction",
+				SingularName: "",
+				Name:         "pods/eviction",
+				Group:        "policy",
+				Version:      "v1",
+				Verbs:        []string{"create"},
 				Namespaced:   true,
 			},
 			{
 				Kind:         "Secret",
 				SingularName: "",
 				Name:         "secrets",
-				Verbs:        []string{"create", "list", "delete", "get", "patch"},
+				Verbs:        []string{"create", "list", "delete", "deletecollection", "get", "patch"},
 				Namespaced:   true,
 			},
 			{
 				Kind:         "Service",
 				SingularName: "",
 				Name:         "services",
-				Verbs:        []string{"create", "list", "delete", "get", "patch"},
+				Verbs:        []string{"create", "list", "delete", "deletecollection", "get", "patch"},
 				Namespaced:   true,
 				ShortNames:   []string{"svc"},
 			},
@@ -0,0 +1,46 @@
+package adopt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/utils"
+	httputils "github.com/portainer/k2d/pkg/http"
+)
+
+type AdoptService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+func NewAdoptService(adapter *adapter.KubeDockerAdapter) AdoptService {
+	return AdoptService{
+		adapter: adapter,
+	}
+}
+
+// AdoptContainer turns a Docker container created outside of k2d into a pod managed through the Kubernetes API.
+// It returns the pod managing the container.
+func (svc AdoptService) AdoptContainer(r *restful.Request, w *restful.Response) {
+	opts := adapter.AdoptContainerOptions{}
+	err := httputils.ParseJSONBody(r.Request, &opts)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	if opts.Container == "" {
+		utils.HttpError(r, w, http.StatusBadRequest, errors.New("the container field is required"))
+		return
+	}
+
+	pod, err := svc.adapter.AdoptContainer(r.Request.Context(), opts)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to adopt container: %w", err))
+		return
+	}
+
+	w.WriteHeaderAndJson(http.StatusCreated, pod, restful.MIME_JSON)
+}
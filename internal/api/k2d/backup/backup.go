@@ -0,0 +1,64 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/utils"
+	"github.com/portainer/k2d/internal/middleware"
+	"github.com/portainer/k2d/internal/token"
+)
+
+type BackupService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+func NewBackupService(adapter *adapter.KubeDockerAdapter) BackupService {
+	return BackupService{
+		adapter: adapter,
+	}
+}
+
+// CreateBackup returns a gzip compressed tar archive containing the state of k2d.
+// The archive contains the secrets and the TLS certificates of k2d, only admin tokens can be used to create it.
+func (svc BackupService) CreateBackup(r *restful.Request, w *restful.Response) {
+	role, _ := r.Attribute(middleware.RoleAttribute).(token.Role)
+	if role != token.AdminRole {
+		utils.HttpError(r, w, http.StatusForbidden, errors.New("only admin tokens can be used to create a backup"))
+		return
+	}
+
+	// The archive is built in memory so that a failure can still be reported with an error status
+	archive := &bytes.Buffer{}
+	err := svc.adapter.CreateBackup(r.Request.Context(), archive)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create backup: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("k2d-backup-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))))
+	w.Write(archive.Bytes())
+}
+
+// RestoreBackup restores a backup archive created through CreateBackup, provided as the request body.
+func (svc BackupService) RestoreBackup(r *restful.Request, w *restful.Response) {
+	role, _ := r.Attribute(middleware.RoleAttribute).(token.Role)
+	if role != token.AdminRole {
+		utils.HttpError(r, w, http.StatusForbidden, errors.New("only admin tokens can be used to restore a backup"))
+		return
+	}
+
+	report, err := svc.adapter.RestoreBackup(r.Request.Context(), r.Request.Body)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to restore backup: %w", err))
+		return
+	}
+
+	w.WriteAsJson(report)
+}
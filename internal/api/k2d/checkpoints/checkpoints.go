@@ -0,0 +1,173 @@
+package checkpoints
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/utils"
+	"github.com/portainer/k2d/internal/middleware"
+	"github.com/portainer/k2d/internal/token"
+	httputils "github.com/portainer/k2d/pkg/http"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CheckpointService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+// CreateCheckpointRequest is the body of a checkpoint creation request
+type CreateCheckpointRequest struct {
+	// Name is the name of the checkpoint, optional
+	Name string `json:"name"`
+	// Namespace is the namespace of the pod to checkpoint
+	Namespace string `json:"namespace"`
+	// PodName is the name of the pod to checkpoint
+	PodName string `json:"podName"`
+	// Exit defines whether the container of the pod is stopped once the checkpoint is taken, optional
+	Exit bool `json:"exit"`
+}
+
+// RestoreCheckpointRequest is the body of a checkpoint restore request
+type RestoreCheckpointRequest struct {
+	// Namespace is the namespace of the pod to restore the checkpoint into, optional.
+	// The namespace of the checkpoint is used when not specified.
+	Namespace string `json:"namespace"`
+	// PodName is the name of the pod to restore the checkpoint into, optional.
+	// The checkpoint is restored into the pod it was taken from when not specified.
+	PodName string `json:"podName"`
+}
+
+func NewCheckpointService(adapter *adapter.KubeDockerAdapter) CheckpointService {
+	return CheckpointService{
+		adapter: adapter,
+	}
+}
+
+// CreateCheckpoint checkpoints the container of a pod through the Docker checkpoint API (experimental).
+func (svc CheckpointService) CreateCheckpoint(r *restful.Request, w *restful.Response) {
+	request := CreateCheckpointRequest{}
+	err := httputils.ParseJSONBody(r.Request, &request)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	if request.Namespace == "" || request.PodName == "" {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("the namespace and podName fields are required"))
+		return
+	}
+
+	checkpoint, err := svc.adapter.CreateContainerCheckpoint(r.Request.Context(), request.Namespace, request.PodName, request.Name, request.Exit)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create checkpoint: %w", err))
+		return
+	}
+
+	w.WriteHeaderAndJson(http.StatusCreated, checkpoint, restful.MIME_JSON)
+}
+
+func (svc CheckpointService) ListCheckpoints(r *restful.Request, w *restful.Response) {
+	checkpoints, err := svc.adapter.ListContainerCheckpoints()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to list checkpoints: %w", err))
+		return
+	}
+
+	w.WriteAsJson(checkpoints)
+}
+
+func (svc CheckpointService) GetCheckpoint(r *restful.Request, w *restful.Response) {
+	checkpoint, err := svc.adapter.GetContainerCheckpoint(r.PathParameter("name"))
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get checkpoint: %w", err))
+		return
+	}
+
+	w.WriteAsJson(checkpoint)
+}
+
+// ExportCheckpoint returns a checkpoint as a tar archive, to import it on another k2d host.
+// The archive contains the memory of the processes of the container, only admin tokens can be used to export it.
+func (svc CheckpointService) ExportCheckpoint(r *restful.Request, w *restful.Response) {
+	role, _ := r.Attribute(middleware.RoleAttribute).(token.Role)
+	if role != token.AdminRole {
+		utils.HttpError(r, w, http.StatusForbidden, errors.New("only admin tokens can be used to export a checkpoint"))
+		return
+	}
+
+	checkpointName := r.PathParameter("name")
+
+	// The archive is built in memory so that a failure can still be reported with an error status
+	archive := &bytes.Buffer{}
+	err := svc.adapter.ExportContainerCheckpoint(checkpointName, archive)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to export checkpoint: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", checkpointName+".tar"))
+	w.Write(archive.Bytes())
+}
+
+// ImportCheckpoint imports a checkpoint archive exported through ExportCheckpoint, provided as the request body.
+func (svc CheckpointService) ImportCheckpoint(r *restful.Request, w *restful.Response) {
+	role, _ := r.Attribute(middleware.RoleAttribute).(token.Role)
+	if role != token.AdminRole {
+		utils.HttpError(r, w, http.StatusForbidden, errors.New("only admin tokens can be used to import a checkpoint"))
+		return
+	}
+
+	checkpoint, err := svc.adapter.ImportContainerCheckpoint(r.Request.Body)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to import checkpoint: %w", err))
+		return
+	}
+
+	w.WriteHeaderAndJson(http.StatusCreated, checkpoint, restful.MIME_JSON)
+}
+
+// RestoreCheckpoint restores a checkpoint in the container of a pod.
+func (svc CheckpointService) RestoreCheckpoint(r *restful.Request, w *restful.Response) {
+	request := RestoreCheckpointRequest{}
+	if r.Request.ContentLength != 0 {
+		err := httputils.ParseJSONBody(r.Request, &request)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+			return
+		}
+	}
+
+	err := svc.adapter.RestoreContainerCheckpoint(r.Request.Context(), r.PathParameter("name"), request.Namespace, request.PodName)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to restore checkpoint: %w", err))
+		return
+	}
+
+	w.WriteAsJson(successStatus())
+}
+
+func (svc CheckpointService) DeleteCheckpoint(r *restful.Request, w *restful.Response) {
+	err := svc.adapter.DeleteContainerCheckpoint(r.PathParameter("name"))
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to delete checkpoint: %w", err))
+		return
+	}
+
+	w.WriteAsJson(successStatus())
+}
+
+func successStatus() metav1.Status {
+	return metav1.Status{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Status",
+			APIVersion: "v1",
+		},
+		Status: "Success",
+		Code:   http.StatusOK,
+	}
+}
@@ -0,0 +1,52 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/utils"
+	httputils "github.com/portainer/k2d/pkg/http"
+)
+
+type ComposeService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+// ImportComposeProjectRequest is the body of a compose project import request
+type ImportComposeProjectRequest struct {
+	// Project is the name of the Docker Compose project to import
+	Project string `json:"project"`
+}
+
+func NewComposeService(adapter *adapter.KubeDockerAdapter) ComposeService {
+	return ComposeService{
+		adapter: adapter,
+	}
+}
+
+// ImportComposeProject exposes the services of a Docker Compose project running on the host
+// as deployments and services of a namespace named after the project.
+func (svc ComposeService) ImportComposeProject(r *restful.Request, w *restful.Response) {
+	request := ImportComposeProjectRequest{}
+	err := httputils.ParseJSONBody(r.Request, &request)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	if request.Project == "" {
+		utils.HttpError(r, w, http.StatusBadRequest, errors.New("the project field is required"))
+		return
+	}
+
+	report, err := svc.adapter.ImportComposeProject(r.Request.Context(), request.Project)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to import compose project: %w", err))
+		return
+	}
+
+	w.WriteAsJson(report)
+}
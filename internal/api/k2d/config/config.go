@@ -1,40 +1,83 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
 	"github.com/portainer/k2d/internal/api/utils"
 	"github.com/portainer/k2d/internal/k8s"
+	"github.com/portainer/k2d/internal/middleware"
+	"github.com/portainer/k2d/internal/ssl"
+	"github.com/portainer/k2d/internal/token"
+	"github.com/portainer/k2d/internal/types"
 )
 
+// certificateAuthMode is the value of the auth query parameter used to request a kubeconfig
+// authenticating with a client certificate instead of a bearer token.
+const certificateAuthMode = "certificate"
+
 type ConfigService struct {
-	caPath     string
-	serverAddr string
-	secret     string
+	adapter             *adapter.KubeDockerAdapter
+	serverAddr          string
+	serverConfiguration *types.K2DServerConfiguration
+	// rotationMutex ensures that a single secret rotation is performed at a time
+	rotationMutex *sync.Mutex
 }
 
-func NewConfigService(caPath, serverAddr, secret string) ConfigService {
+func NewConfigService(cfg *types.K2DServerConfiguration, serverAddr string, adapter *adapter.KubeDockerAdapter) ConfigService {
 	return ConfigService{
-		caPath:     caPath,
-		serverAddr: serverAddr,
-		secret:     secret,
+		adapter:             adapter,
+		serverAddr:          serverAddr,
+		serverConfiguration: cfg,
+		rotationMutex:       &sync.Mutex{},
 	}
 }
 
+// GetKubeconfig returns a kubeconfig embedding the token used to authenticate the request.
+// This allows a read-only token to be used to retrieve a view-only kubeconfig.
+// When the auth query parameter is set to certificate, the kubeconfig embeds a client certificate
+// signed by the k2d CA instead, issued with the role of the authenticated client and valid for 30 days (see ssl.ClientCertificateValidity).
 func (svc ConfigService) GetKubeconfig(r *restful.Request, w *restful.Response) {
-	authorizationHeader := r.HeaderParameter("Authorization")
-	secret := strings.TrimPrefix(authorizationHeader, "Bearer ")
-
-	if secret != svc.secret {
+	role, ok := r.Attribute(middleware.RoleAttribute).(token.Role)
+	if !ok {
 		w.WriteHeader(http.StatusUnauthorized)
 		w.Write([]byte("invalid secret\n"))
 		return
 	}
 
-	kubeconfig, err := k8s.GenerateKubeconfig(svc.caPath, svc.serverAddr, svc.secret)
+	var kubeconfig []byte
+	var err error
+
+	if r.QueryParameter("auth") == certificateAuthMode {
+		if svc.serverConfiguration.HTTPOnly {
+			utils.HttpError(r, w, http.StatusBadRequest, errors.New("client certificate authentication is not available when k2d is served over HTTP"))
+			return
+		}
+
+		certData, keyData, certErr := ssl.GenerateClientCertificate(svc.serverConfiguration.DataPath, "k2d-"+string(role), string(role))
+		if certErr != nil {
+			utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to generate client certificate: %w", certErr))
+			return
+		}
+
+		kubeconfig, err = k8s.GenerateCertificateKubeconfig(svc.serverConfiguration.KubeconfigCAPath, svc.serverAddr, certData, keyData)
+	} else {
+		authorizationHeader := r.HeaderParameter("Authorization")
+		secret := strings.TrimPrefix(authorizationHeader, "Bearer ")
+
+		if _, found := svc.serverConfiguration.Tokens.Role(secret); !found {
+			utils.HttpError(r, w, http.StatusBadRequest, errors.New("a bearer token is required to generate a token based kubeconfig, use auth=certificate instead"))
+			return
+		}
+
+		kubeconfig, err = k8s.GenerateKubeconfig(svc.serverConfiguration.KubeconfigCAPath, svc.serverAddr, secret)
+	}
+
 	if err != nil {
 		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to generate kubeconfig: %w", err))
 		return
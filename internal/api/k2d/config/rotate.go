@@ -0,0 +1,64 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	"github.com/portainer/k2d/internal/k8s"
+	"github.com/portainer/k2d/internal/middleware"
+	"github.com/portainer/k2d/internal/ssl"
+	"github.com/portainer/k2d/internal/token"
+)
+
+// RotateSecret generates a new secret and revokes the previous one, along with all the client certificates issued so far.
+// The token file and the service account secret mounted inside the containers are updated
+// so that workloads using the service account token are provided with the new secret without being redeployed.
+// It returns a kubeconfig embedding the new secret.
+func (svc ConfigService) RotateSecret(r *restful.Request, w *restful.Response) {
+	role, _ := r.Attribute(middleware.RoleAttribute).(token.Role)
+	if role != token.AdminRole {
+		utils.HttpError(r, w, http.StatusForbidden, errors.New("only admin tokens can be used to rotate the secret"))
+		return
+	}
+
+	svc.rotationMutex.Lock()
+	defer svc.rotationMutex.Unlock()
+
+	encodedSecret, err := token.RotateEncodedSecret(svc.serverConfiguration.TokenPath)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to rotate secret: %w", err))
+		return
+	}
+
+	previousSecret := svc.serverConfiguration.Secret()
+	svc.serverConfiguration.Tokens.Replace(previousSecret, encodedSecret, token.AdminRole)
+	svc.serverConfiguration.SetSecret(encodedSecret)
+
+	// The client certificates could have been issued using the previous secret, they are revoked as well
+	revokedBefore := time.Now()
+	err = ssl.RevokeClientCertificates(svc.serverConfiguration.DataPath, revokedBefore)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to revoke client certificates: %w", err))
+		return
+	}
+	svc.serverConfiguration.Tokens.RevokeCertificates(revokedBefore)
+
+	err = svc.adapter.RefreshServiceAccountSecret(svc.serverConfiguration.TokenPath, svc.serverConfiguration.CaPath)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to update service account secret: %w", err))
+		return
+	}
+
+	kubeconfig, err := k8s.GenerateKubeconfig(svc.serverConfiguration.KubeconfigCAPath, svc.serverAddr, encodedSecret)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to generate kubeconfig: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.Write(kubeconfig)
+}
@@ -0,0 +1,48 @@
+package export
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/utils"
+)
+
+type ExportService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+func NewExportService(adapter *adapter.KubeDockerAdapter) ExportService {
+	return ExportService{
+		adapter: adapter,
+	}
+}
+
+// ExportResources returns the resources managed by k2d as a multi-document YAML manifest.
+// The values of the secrets are redacted unless the redactSecrets query parameter is set to false.
+func (svc ExportService) ExportResources(r *restful.Request, w *restful.Response) {
+	opts := adapter.ExportOptions{
+		Namespace:     r.QueryParameter("namespace"),
+		RedactSecrets: true,
+	}
+
+	if redactSecrets := r.QueryParameter("redactSecrets"); redactSecrets != "" {
+		value, err := strconv.ParseBool(redactSecrets)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("invalid redactSecrets query parameter: %w", err))
+			return
+		}
+		opts.RedactSecrets = value
+	}
+
+	manifest, err := svc.adapter.ExportResources(r.Request.Context(), opts)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to export resources: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.Write(manifest)
+}
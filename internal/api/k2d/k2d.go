@@ -1,28 +1,53 @@
 package k2d
 
 import (
-	"fmt"
-
 	"github.com/emicklei/go-restful/v3"
 	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/k2d/adopt"
+	"github.com/portainer/k2d/internal/api/k2d/backup"
+	"github.com/portainer/k2d/internal/api/k2d/checkpoints"
+	"github.com/portainer/k2d/internal/api/k2d/compose"
 	"github.com/portainer/k2d/internal/api/k2d/config"
+	"github.com/portainer/k2d/internal/api/k2d/export"
+	"github.com/portainer/k2d/internal/api/k2d/operations"
+	"github.com/portainer/k2d/internal/api/k2d/snapshots"
+	"github.com/portainer/k2d/internal/api/k2d/stream"
 	"github.com/portainer/k2d/internal/api/k2d/system"
+	"github.com/portainer/k2d/internal/api/k2d/ui"
+	"github.com/portainer/k2d/internal/controller"
+	"github.com/portainer/k2d/internal/notification"
 	"github.com/portainer/k2d/internal/types"
 )
 
 type (
 	K2DAPI struct {
-		configService config.ConfigService
-		systemService system.SystemService
+		adoptService      adopt.AdoptService
+		backupService     backup.BackupService
+		checkpointService checkpoints.CheckpointService
+		composeService    compose.ComposeService
+		configService     config.ConfigService
+		exportService     export.ExportService
+		operationService  operations.OperationService
+		snapshotService   snapshots.SnapshotService
+		streamService     stream.StreamService
+		systemService     system.SystemService
+		uiService         ui.UIService
 	}
 )
 
-func NewK2DAPI(cfg *types.K2DServerConfiguration, adapter *adapter.KubeDockerAdapter) *K2DAPI {
-	serverAddress := fmt.Sprintf("https://%s:%d", cfg.ServerIpAddr, cfg.ServerPort)
-
+func NewK2DAPI(cfg *types.K2DServerConfiguration, adapter *adapter.KubeDockerAdapter, operationRegistry *controller.OperationStatusRegistry, broadcaster *notification.Broadcaster) *K2DAPI {
 	return &K2DAPI{
-		configService: config.NewConfigService(cfg.CaPath, serverAddress, cfg.Secret),
-		systemService: system.NewSystemService(cfg, adapter),
+		adoptService:      adopt.NewAdoptService(adapter),
+		backupService:     backup.NewBackupService(adapter),
+		checkpointService: checkpoints.NewCheckpointService(adapter),
+		composeService:    compose.NewComposeService(adapter),
+		configService:     config.NewConfigService(cfg, cfg.ServerURL, adapter),
+		exportService:     export.NewExportService(adapter),
+		operationService:  operations.NewOperationService(operationRegistry),
+		snapshotService:   snapshots.NewSnapshotService(adapter),
+		streamService:     stream.NewStreamService(broadcaster),
+		systemService:     system.NewSystemService(cfg, adapter),
+		uiService:         ui.NewUIService(),
 	}
 }
 
@@ -33,7 +58,185 @@ func (api K2DAPI) Kubeconfig() *restful.WebService {
 		Produces("application/yml")
 
 	routes.Route(routes.GET("").
-		To(api.configService.GetKubeconfig))
+		To(api.configService.GetKubeconfig).
+		Param(routes.QueryParameter("auth", "set to certificate to authenticate using a client certificate instead of a bearer token").DataType("string")))
+
+	return routes
+}
+
+// /k2d/rotate-secret
+func (api K2DAPI) RotateSecret() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/k2d/rotate-secret").
+		Produces("application/yml")
+
+	routes.Route(routes.POST("").
+		To(api.configService.RotateSecret))
+
+	return routes
+}
+
+// /k2d/adopt
+func (api K2DAPI) Adopt() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/k2d/adopt").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+
+	routes.Route(routes.POST("").
+		To(api.adoptService.AdoptContainer))
+
+	return routes
+}
+
+// /k2d/backup
+func (api K2DAPI) Backup() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/k2d/backup").
+		Produces("application/gzip")
+
+	routes.Route(routes.GET("").
+		To(api.backupService.CreateBackup))
+
+	return routes
+}
+
+// /k2d/restore
+func (api K2DAPI) Restore() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/k2d/restore").
+		Consumes("application/gzip", "application/octet-stream").
+		Produces(restful.MIME_JSON)
+
+	routes.Route(routes.POST("").
+		To(api.backupService.RestoreBackup))
+
+	return routes
+}
+
+// /k2d/checkpoints
+func (api K2DAPI) Checkpoints() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/k2d/checkpoints").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+
+	routes.Route(routes.POST("").
+		To(api.checkpointService.CreateCheckpoint))
+
+	routes.Route(routes.GET("").
+		To(api.checkpointService.ListCheckpoints))
+
+	routes.Route(routes.POST("/import").
+		To(api.checkpointService.ImportCheckpoint).
+		Consumes("application/x-tar", "application/octet-stream"))
+
+	routes.Route(routes.GET("/{name}").
+		To(api.checkpointService.GetCheckpoint).
+		Param(routes.PathParameter("name", "name of the checkpoint").DataType("string")))
+
+	routes.Route(routes.GET("/{name}/archive").
+		To(api.checkpointService.ExportCheckpoint).
+		Produces("application/x-tar").
+		Param(routes.PathParameter("name", "name of the checkpoint").DataType("string")))
+
+	routes.Route(routes.POST("/{name}/restore").
+		To(api.checkpointService.RestoreCheckpoint).
+		Param(routes.PathParameter("name", "name of the checkpoint").DataType("string")))
+
+	routes.Route(routes.DELETE("/{name}").
+		To(api.checkpointService.DeleteCheckpoint).
+		Param(routes.PathParameter("name", "name of the checkpoint").DataType("string")))
+
+	return routes
+}
+
+// /k2d/export
+func (api K2DAPI) Export() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/k2d/export").
+		Produces("application/x-yaml")
+
+	routes.Route(routes.GET("").
+		To(api.exportService.ExportResources).
+		Param(routes.QueryParameter("namespace", "only export the resources of this namespace").DataType("string")).
+		Param(routes.QueryParameter("redactSecrets", "set to false to export the values of the secrets").DataType("boolean").DefaultValue("true")))
+
+	return routes
+}
+
+// /k2d/import-compose
+func (api K2DAPI) ImportCompose() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/k2d/import-compose").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+
+	routes.Route(routes.POST("").
+		To(api.composeService.ImportComposeProject))
+
+	return routes
+}
+
+// /k2d/operations
+func (api K2DAPI) Operations() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/k2d/operations").
+		Produces(restful.MIME_JSON)
+
+	routes.Route(routes.GET("").
+		To(api.operationService.ListOperations))
+
+	routes.Route(routes.GET("/{requestID}").
+		To(api.operationService.GetOperation).
+		Param(routes.PathParameter("requestID", "ID of the request that submitted the operation").DataType("string")))
+
+	return routes
+}
+
+// /k2d/snapshots
+func (api K2DAPI) Snapshots() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/k2d/snapshots").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+
+	routes.Route(routes.POST("").
+		To(api.snapshotService.CreateSnapshot))
+
+	routes.Route(routes.GET("").
+		To(api.snapshotService.ListSnapshots))
+
+	routes.Route(routes.GET("/{name}").
+		To(api.snapshotService.GetSnapshot).
+		Param(routes.PathParameter("name", "name of the snapshot").DataType("string")))
+
+	routes.Route(routes.GET("/{name}/archive").
+		To(api.snapshotService.DownloadSnapshot).
+		Produces("application/x-tar").
+		Param(routes.PathParameter("name", "name of the snapshot").DataType("string")))
+
+	routes.Route(routes.POST("/{name}/restore").
+		To(api.snapshotService.RestoreSnapshot).
+		Param(routes.PathParameter("name", "name of the snapshot").DataType("string")))
+
+	routes.Route(routes.DELETE("/{name}").
+		To(api.snapshotService.DeleteSnapshot).
+		Param(routes.PathParameter("name", "name of the snapshot").DataType("string")))
+
+	return routes
+}
+
+// /k2d/stream
+func (api K2DAPI) Stream() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/k2d/stream").
+		Produces("text/event-stream")
+
+	routes.Route(routes.GET("").
+		To(api.streamService.StreamNotifications).
+		Param(routes.QueryParameter("namespace", "only stream the notifications of this namespace").DataType("string")).
+		Param(routes.QueryParameter("kind", "only stream the notifications of this kind of resource").DataType("string")))
 
 	return routes
 }
@@ -48,3 +251,18 @@ func (api K2DAPI) System() *restful.WebService {
 
 	return routes
 }
+
+// /k2d/ui
+func (api K2DAPI) UI() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/k2d/ui")
+
+	routes.Route(routes.GET("").
+		To(api.uiService.ServeFile))
+
+	routes.Route(routes.GET("/{subpath:*}").
+		To(api.uiService.ServeFile).
+		Param(routes.PathParameter("subpath", "path of the file of the dashboard").DataType("string")))
+
+	return routes
+}
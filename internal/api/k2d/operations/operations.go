@@ -0,0 +1,39 @@
+package operations
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	"github.com/portainer/k2d/internal/controller"
+)
+
+type OperationService struct {
+	registry *controller.OperationStatusRegistry
+}
+
+func NewOperationService(registry *controller.OperationStatusRegistry) OperationService {
+	return OperationService{
+		registry: registry,
+	}
+}
+
+// ListOperations returns the status of the latest operations processed by the operation controller.
+func (svc OperationService) ListOperations(r *restful.Request, w *restful.Response) {
+	w.WriteAsJson(svc.registry.List())
+}
+
+// GetOperation returns the status of the operation submitted by a request.
+// The request ID is returned in the X-K2d-Request-Id header of the responses.
+func (svc OperationService) GetOperation(r *restful.Request, w *restful.Response) {
+	requestID := r.PathParameter("requestID")
+
+	status, found := svc.registry.Get(requestID)
+	if !found {
+		utils.HttpError(r, w, http.StatusNotFound, fmt.Errorf("operation %s not found", requestID))
+		return
+	}
+
+	w.WriteAsJson(status)
+}
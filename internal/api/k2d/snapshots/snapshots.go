@@ -0,0 +1,138 @@
+package snapshots
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/api/utils"
+	httputils "github.com/portainer/k2d/pkg/http"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type SnapshotService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+// CreateSnapshotRequest is the body of a snapshot creation request
+type CreateSnapshotRequest struct {
+	// Name is the name of the snapshot, optional
+	Name string `json:"name"`
+	// VolumeName is the name of the Docker volume to snapshot
+	VolumeName string `json:"volumeName"`
+}
+
+// RestoreSnapshotRequest is the body of a snapshot restore request
+type RestoreSnapshotRequest struct {
+	// VolumeName is the name of the Docker volume to restore the snapshot into, optional.
+	// The snapshot is restored into the volume it was taken from when not specified.
+	VolumeName string `json:"volumeName"`
+}
+
+func NewSnapshotService(adapter *adapter.KubeDockerAdapter) SnapshotService {
+	return SnapshotService{
+		adapter: adapter,
+	}
+}
+
+// CreateSnapshot exports the content of a Docker volume as a tar archive stored in the k2d data path.
+func (svc SnapshotService) CreateSnapshot(r *restful.Request, w *restful.Response) {
+	request := CreateSnapshotRequest{}
+	err := httputils.ParseJSONBody(r.Request, &request)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+		return
+	}
+
+	if request.VolumeName == "" {
+		utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("the volumeName field is required"))
+		return
+	}
+
+	snapshot, err := svc.adapter.CreateVolumeSnapshot(r.Request.Context(), request.VolumeName, request.Name)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to create snapshot: %w", err))
+		return
+	}
+
+	w.WriteHeaderAndJson(http.StatusCreated, snapshot, restful.MIME_JSON)
+}
+
+func (svc SnapshotService) ListSnapshots(r *restful.Request, w *restful.Response) {
+	snapshots, err := svc.adapter.ListVolumeSnapshots()
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to list snapshots: %w", err))
+		return
+	}
+
+	w.WriteAsJson(snapshots)
+}
+
+func (svc SnapshotService) GetSnapshot(r *restful.Request, w *restful.Response) {
+	snapshot, err := svc.adapter.GetVolumeSnapshot(r.PathParameter("name"))
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get snapshot: %w", err))
+		return
+	}
+
+	w.WriteAsJson(snapshot)
+}
+
+// DownloadSnapshot streams the tar archive of a snapshot, e.g. to store it outside of the node.
+func (svc SnapshotService) DownloadSnapshot(r *restful.Request, w *restful.Response) {
+	snapshotName := r.PathParameter("name")
+
+	archive, err := svc.adapter.GetVolumeSnapshotArchive(snapshotName)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to get snapshot archive: %w", err))
+		return
+	}
+	defer archive.Close()
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", snapshotName+".tar"))
+	io.Copy(w, archive)
+}
+
+// RestoreSnapshot restores the content of a snapshot inside a Docker volume.
+func (svc SnapshotService) RestoreSnapshot(r *restful.Request, w *restful.Response) {
+	request := RestoreSnapshotRequest{}
+	if r.Request.ContentLength != 0 {
+		err := httputils.ParseJSONBody(r.Request, &request)
+		if err != nil {
+			utils.HttpError(r, w, http.StatusBadRequest, fmt.Errorf("unable to parse request body: %w", err))
+			return
+		}
+	}
+
+	err := svc.adapter.RestoreVolumeSnapshot(r.Request.Context(), r.PathParameter("name"), request.VolumeName)
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to restore snapshot: %w", err))
+		return
+	}
+
+	w.WriteAsJson(successStatus())
+}
+
+func (svc SnapshotService) DeleteSnapshot(r *restful.Request, w *restful.Response) {
+	err := svc.adapter.DeleteVolumeSnapshot(r.PathParameter("name"))
+	if err != nil {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to delete snapshot: %w", err))
+		return
+	}
+
+	w.WriteAsJson(successStatus())
+}
+
+func successStatus() metav1.Status {
+	return metav1.Status{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Status",
+			APIVersion: "v1",
+		},
+		Status: "Success",
+		Code:   http.StatusOK,
+	}
+}
@@ -0,0 +1,87 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	"github.com/portainer/k2d/internal/notification"
+)
+
+// heartbeatInterval is the interval at which a comment is sent to the clients of the stream
+// so that idle connections are not closed by proxies.
+const heartbeatInterval = 15 * time.Second
+
+type StreamService struct {
+	broadcaster *notification.Broadcaster
+}
+
+func NewStreamService(broadcaster *notification.Broadcaster) StreamService {
+	return StreamService{
+		broadcaster: broadcaster,
+	}
+}
+
+// StreamNotifications pushes the resource change notifications (operation results and pod lifecycle events)
+// to the client using Server-Sent Events until the client disconnects.
+// Each notification is sent as a JSON encoded event whose type is the reason of the notification (e.g. Applied, Crashed).
+// The notifications can be filtered using the namespace and kind query parameters.
+// A notification is dropped for a client that does not keep up with the stream.
+func (svc StreamService) StreamNotifications(r *restful.Request, w *restful.Response) {
+	flusher, ok := w.ResponseWriter.(http.Flusher)
+	if !ok {
+		utils.HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported by the response writer"))
+		return
+	}
+
+	namespace := r.QueryParameter("namespace")
+	kind := r.QueryParameter("kind")
+
+	notifications, unsubscribe := svc.broadcaster.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			_, err := fmt.Fprint(w, ": heartbeat\n\n")
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		case n, open := <-notifications:
+			if !open {
+				return
+			}
+
+			if (namespace != "" && n.Namespace != namespace) || (kind != "" && !strings.EqualFold(n.Kind, kind)) {
+				continue
+			}
+
+			data, err := json.Marshal(n)
+			if err != nil {
+				continue
+			}
+
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", n.Reason, data)
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -1,15 +1,18 @@
 package system
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"runtime"
-	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/emicklei/go-restful/v3"
 	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/adapter/store/volume"
 	"github.com/portainer/k2d/internal/api/utils"
+	"github.com/portainer/k2d/internal/middleware"
+	"github.com/portainer/k2d/internal/token"
 	k2dtypes "github.com/portainer/k2d/internal/types"
 )
 
@@ -19,12 +22,13 @@ type SystemService struct {
 }
 
 type Diagnostics struct {
-	Version             string                           `json:"version"`
-	ServerConfiguration *k2dtypes.K2DServerConfiguration `json:"serverConfiguration"`
-	OS                  string                           `json:"os"`
-	Arch                string                           `json:"arch"`
-	DockerInfo          types.Info                       `json:"dockerInfo"`
-	DockerVersion       types.Version                    `json:"dockerVersion"`
+	Version              string                           `json:"version"`
+	ServerConfiguration  *k2dtypes.K2DServerConfiguration `json:"serverConfiguration"`
+	OS                   string                           `json:"os"`
+	Arch                 string                           `json:"arch"`
+	DockerInfo           types.Info                       `json:"dockerInfo"`
+	DockerVersion        types.Version                    `json:"dockerVersion"`
+	VolumeCopyContainers volume.CopyContainerStatistics   `json:"volumeCopyContainers"`
 }
 
 func NewSystemService(cfg *k2dtypes.K2DServerConfiguration, adapter *adapter.KubeDockerAdapter) SystemService {
@@ -34,13 +38,13 @@ func NewSystemService(cfg *k2dtypes.K2DServerConfiguration, adapter *adapter.Kub
 	}
 }
 
+// Diagnostics returns information about k2d and the Docker host, e.g. to be attached to an issue.
+// The client is authenticated by the authentication middleware (bearer token or client certificate),
+// only admin clients can retrieve the diagnostics.
 func (svc SystemService) Diagnostics(r *restful.Request, w *restful.Response) {
-	authorizationHeader := r.HeaderParameter("Authorization")
-	secret := strings.TrimPrefix(authorizationHeader, "Bearer ")
-
-	if secret != svc.serverConfiguration.Secret {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("invalid secret\n"))
+	role, _ := r.Attribute(middleware.RoleAttribute).(token.Role)
+	if role != token.AdminRole {
+		utils.HttpError(r, w, http.StatusForbidden, errors.New("only admin tokens can be used to retrieve the diagnostics"))
 		return
 	}
 
@@ -51,15 +55,15 @@ func (svc SystemService) Diagnostics(r *restful.Request, w *restful.Response) {
 	}
 
 	info.DefaultAddressPools = nil
-	svc.serverConfiguration.Secret = "[redacted]"
 
 	diagnostics := Diagnostics{
-		Version:             k2dtypes.Version,
-		ServerConfiguration: svc.serverConfiguration,
-		OS:                  runtime.GOOS,
-		Arch:                runtime.GOARCH,
-		DockerInfo:          info,
-		DockerVersion:       version,
+		Version:              k2dtypes.Version,
+		ServerConfiguration:  svc.serverConfiguration,
+		OS:                   runtime.GOOS,
+		Arch:                 runtime.GOARCH,
+		DockerInfo:           info,
+		DockerVersion:        version,
+		VolumeCopyContainers: volume.GetCopyContainerStatistics(),
 	}
 
 	w.WriteAsJson(diagnostics)
@@ -0,0 +1,279 @@
+// The k2d dashboard only sends read requests to the k2d API, using the token provided by the user.
+// The token is kept in the session storage of the browser and is never sent to another origin.
+(function () {
+  "use strict";
+
+  const tokenKey = "k2d-token";
+  const refreshInterval = 30000;
+  const logTailLines = 100;
+
+  const elements = {
+    login: document.getElementById("login"),
+    token: document.getElementById("token"),
+    logout: document.getElementById("logout"),
+    dashboard: document.getElementById("dashboard"),
+    namespace: document.getElementById("namespace"),
+    status: document.getElementById("status"),
+    logs: document.getElementById("logs"),
+    logsPod: document.getElementById("logs-pod"),
+    logsContent: document.getElementById("logs-content"),
+  };
+
+  let stream = null;
+  let refreshTimer = null;
+
+  function token() {
+    return sessionStorage.getItem(tokenKey);
+  }
+
+  async function request(path) {
+    const response = await fetch(path, {
+      headers: { Authorization: "Bearer " + token(), Accept: "application/json" },
+    });
+
+    if (response.status === 401) {
+      sessionStorage.removeItem(tokenKey);
+      showLogin();
+      throw new Error("invalid token");
+    }
+
+    if (!response.ok) {
+      throw new Error(path + " returned " + response.status);
+    }
+
+    return response;
+  }
+
+  async function list(path) {
+    const response = await request(path);
+    const body = await response.json();
+    return (body.items || []).sort((a, b) => a.metadata.name.localeCompare(b.metadata.name));
+  }
+
+  function age(timestamp) {
+    if (!timestamp) {
+      return "";
+    }
+
+    const seconds = Math.max(0, Math.floor((Date.now() - new Date(timestamp).getTime()) / 1000));
+    if (seconds < 120) {
+      return seconds + "s";
+    }
+    if (seconds < 7200) {
+      return Math.floor(seconds / 60) + "m";
+    }
+    if (seconds < 172800) {
+      return Math.floor(seconds / 3600) + "h";
+    }
+    return Math.floor(seconds / 86400) + "d";
+  }
+
+  function renderRows(tableId, rows) {
+    const body = document.querySelector("#" + tableId + " tbody");
+    body.replaceChildren();
+
+    for (const cells of rows) {
+      const row = document.createElement("tr");
+      for (const cell of cells) {
+        const column = document.createElement("td");
+        if (cell instanceof Node) {
+          column.appendChild(cell);
+        } else {
+          column.textContent = cell.text !== undefined ? cell.text : cell;
+          if (cell.className) {
+            column.className = cell.className;
+          }
+        }
+        row.appendChild(column);
+      }
+      body.appendChild(row);
+    }
+  }
+
+  function podStatus(pod) {
+    const statuses = (pod.status && pod.status.containerStatuses) || [];
+    for (const status of statuses) {
+      if (status.state && status.state.waiting && status.state.waiting.reason) {
+        return status.state.waiting.reason;
+      }
+      if (status.state && status.state.terminated && status.state.terminated.reason) {
+        return status.state.terminated.reason;
+      }
+    }
+    return (pod.status && pod.status.phase) || "Unknown";
+  }
+
+  function podRestarts(pod) {
+    const statuses = (pod.status && pod.status.containerStatuses) || [];
+    return statuses.reduce((total, status) => total + (status.restartCount || 0), 0);
+  }
+
+  async function showLogs(namespace, name) {
+    elements.logs.hidden = false;
+    elements.logsPod.textContent = name;
+    elements.logsContent.textContent = "Loading...";
+
+    try {
+      const response = await request("/api/v1/namespaces/" + encodeURIComponent(namespace) + "/pods/" + encodeURIComponent(name) + "/log?tailLines=" + logTailLines);
+      elements.logsContent.textContent = await response.text();
+    } catch (err) {
+      elements.logsContent.textContent = err.message;
+    }
+  }
+
+  async function refresh() {
+    const namespace = elements.namespace.value;
+    if (!namespace) {
+      return;
+    }
+
+    const base = "/namespaces/" + encodeURIComponent(namespace);
+
+    try {
+      const [deployments, pods, services, events] = await Promise.all([
+        list("/apis/apps/v1" + base + "/deployments"),
+        list("/api/v1" + base + "/pods"),
+        list("/api/v1" + base + "/services"),
+        list("/api/v1" + base + "/events"),
+      ]);
+
+      renderRows("deployments", deployments.map((deployment) => [
+        deployment.metadata.name,
+        ((deployment.status && deployment.status.readyReplicas) || 0) + "/" + ((deployment.spec && deployment.spec.replicas) || 0),
+        deployment.spec.template.spec.containers.map((container) => container.image).join(", "),
+        age(deployment.metadata.creationTimestamp),
+      ]));
+
+      renderRows("pods", pods.map((pod) => {
+        const logsButton = document.createElement("button");
+        logsButton.type = "button";
+        logsButton.textContent = "Logs";
+        logsButton.addEventListener("click", () => showLogs(namespace, pod.metadata.name));
+
+        const status = podStatus(pod);
+        return [pod.metadata.name, { text: status, className: status }, podRestarts(pod), age(pod.metadata.creationTimestamp), logsButton];
+      }));
+
+      renderRows("services", services.map((service) => [
+        service.metadata.name,
+        service.spec.type,
+        (service.spec.ports || []).map((port) => port.port + (port.nodePort ? ":" + port.nodePort : "") + "/" + (port.protocol || "TCP")).join(", "),
+        age(service.metadata.creationTimestamp),
+      ]));
+
+      events.sort((a, b) => new Date(b.lastTimestamp || b.eventTime || 0) - new Date(a.lastTimestamp || a.eventTime || 0));
+      renderRows("events", events.map((event) => [
+        age(event.lastTimestamp || event.eventTime),
+        { text: event.type, className: event.type },
+        event.reason,
+        event.involvedObject.kind + "/" + event.involvedObject.name,
+        event.message,
+      ]));
+
+      elements.status.textContent = "Updated at " + new Date().toLocaleTimeString();
+    } catch (err) {
+      elements.status.textContent = err.message;
+    }
+  }
+
+  // scheduleRefresh coalesces the notifications received in a short period of time into a single refresh.
+  function scheduleRefresh() {
+    clearTimeout(refreshTimer);
+    refreshTimer = setTimeout(refresh, 500);
+  }
+
+  // watch refreshes the dashboard when a notification is received from the /k2d/stream endpoint.
+  // EventSource cannot send the Authorization header, the stream is read using fetch instead.
+  async function watch() {
+    if (stream) {
+      stream.abort();
+    }
+    stream = new AbortController();
+
+    try {
+      const response = await fetch("/k2d/stream?namespace=" + encodeURIComponent(elements.namespace.value), {
+        headers: { Authorization: "Bearer " + token() },
+        signal: stream.signal,
+      });
+      const reader = response.body.getReader();
+      for (;;) {
+        const { done, value } = await reader.read();
+        if (done) {
+          break;
+        }
+        if (new TextDecoder().decode(value).includes("data:")) {
+          scheduleRefresh();
+        }
+      }
+    } catch (err) {
+      // The stream is optional, the dashboard is also refreshed periodically.
+    }
+  }
+
+  async function loadNamespaces() {
+    const namespaces = await list("/api/v1/namespaces");
+    const selected = elements.namespace.value || "default";
+
+    elements.namespace.replaceChildren();
+    for (const namespace of namespaces) {
+      const option = document.createElement("option");
+      option.value = namespace.metadata.name;
+      option.textContent = namespace.metadata.name;
+      option.selected = namespace.metadata.name === selected;
+      elements.namespace.appendChild(option);
+    }
+  }
+
+  function showLogin() {
+    elements.dashboard.hidden = true;
+    elements.login.hidden = false;
+    if (stream) {
+      stream.abort();
+    }
+  }
+
+  async function start() {
+    elements.login.hidden = true;
+    elements.dashboard.hidden = false;
+
+    try {
+      await loadNamespaces();
+    } catch (err) {
+      elements.status.textContent = err.message;
+      return;
+    }
+
+    await refresh();
+    watch();
+  }
+
+  elements.login.addEventListener("submit", (event) => {
+    event.preventDefault();
+    sessionStorage.setItem(tokenKey, elements.token.value);
+    elements.token.value = "";
+    start();
+  });
+
+  elements.logout.addEventListener("click", () => {
+    sessionStorage.removeItem(tokenKey);
+    showLogin();
+  });
+
+  elements.namespace.addEventListener("change", () => {
+    elements.logs.hidden = true;
+    refresh();
+    watch();
+  });
+
+  setInterval(() => {
+    if (token() && !elements.dashboard.hidden) {
+      refresh();
+    }
+  }, refreshInterval);
+
+  if (token()) {
+    start();
+  } else {
+    showLogin();
+  }
+})();
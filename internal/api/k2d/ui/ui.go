@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"path"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+//go:embed static
+var static embed.FS
+
+type UIService struct {
+	files http.FileSystem
+}
+
+func NewUIService() UIService {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		// The static directory is embedded at build time, it always exists.
+		panic(err)
+	}
+
+	return UIService{
+		files: http.FS(files),
+	}
+}
+
+// ServeFile serves the files of the embedded web dashboard. The dashboard is a static page that retrieves
+// the namespaces, workloads, pods, logs and events using the k2d API, it only sends read requests.
+// The index page is served when no file is requested.
+func (svc UIService) ServeFile(r *restful.Request, w *restful.Response) {
+	name := path.Clean("/" + r.PathParameter("subpath"))
+	if name == "/" {
+		name = "/index.html"
+	}
+
+	file, err := svc.files.Open(name)
+	if err != nil {
+		http.NotFound(w, r.Request)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r.Request)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	http.ServeContent(w, r.Request, info.Name(), info.ModTime(), file)
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+func serveFile(t *testing.T, subpath string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	request := restful.NewRequest(httptest.NewRequest(http.MethodGet, "/k2d/ui/"+subpath, nil))
+	request.PathParameters()["subpath"] = subpath
+
+	recorder := httptest.NewRecorder()
+	NewUIService().ServeFile(request, restful.NewResponse(recorder))
+
+	return recorder
+}
+
+func TestServeFile(t *testing.T) {
+	recorder := serveFile(t, "")
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), "<title>k2d</title>") {
+		t.Errorf("expected the index page to be served, got status %d", recorder.Code)
+	}
+
+	recorder = serveFile(t, "app.js")
+	if recorder.Code != http.StatusOK {
+		t.Errorf("expected app.js to be served, got status %d", recorder.Code)
+	}
+
+	for _, subpath := range []string{"missing.js", "../ui.go", "../../k2d.go"} {
+		recorder = serveFile(t, subpath)
+		if recorder.Code != http.StatusNotFound {
+			t.Errorf("expected %s to be not found, got status %d", subpath, recorder.Code)
+		}
+	}
+}
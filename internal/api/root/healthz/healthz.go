@@ -1,17 +1,96 @@
 package healthz
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
 )
 
-type HealthzService struct{}
+// checkTimeout is the maximum duration of a single readiness check
+const checkTimeout = 5 * time.Second
 
-func NewHealthzService() HealthzService {
-	return HealthzService{}
+const (
+	statusOK     = "ok"
+	statusFailed = "failed"
+)
+
+// HealthStatus is the response of the /livez and /readyz endpoints.
+type HealthStatus struct {
+	Status string        `json:"status"`
+	Checks []CheckStatus `json:"checks"`
+}
+
+// CheckStatus is the status of a single component.
+type CheckStatus struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type HealthzService struct {
+	adapter *adapter.KubeDockerAdapter
+}
+
+func NewHealthzService(adapter *adapter.KubeDockerAdapter) HealthzService {
+	return HealthzService{
+		adapter: adapter,
+	}
 }
 
 func (svc HealthzService) Healthz(r *restful.Request, w *restful.Response) {
 	w.WriteHeader(http.StatusOK)
 }
+
+// Livez reports whether the k2d process is alive and able to serve requests.
+func (svc HealthzService) Livez(r *restful.Request, w *restful.Response) {
+	writeHealthStatus(w, []CheckStatus{
+		{Name: "ping", Status: statusOK},
+	})
+}
+
+// Readyz reports whether k2d is able to process requests: the Docker socket must be reachable
+// and the store backend used for secrets and configmaps must be usable.
+// It responds with a 503 status code when any of the checks fails.
+func (svc HealthzService) Readyz(r *restful.Request, w *restful.Response) {
+	ctx, cancel := context.WithTimeout(r.Request.Context(), checkTimeout)
+	defer cancel()
+
+	checks := []CheckStatus{
+		newCheckStatus("docker", func() error {
+			_, err := svc.adapter.Ping(ctx)
+			return err
+		}),
+		newCheckStatus("store", svc.adapter.CheckStoreBackend),
+	}
+
+	writeHealthStatus(w, checks)
+}
+
+func newCheckStatus(name string, check func() error) CheckStatus {
+	err := check()
+	if err != nil {
+		return CheckStatus{Name: name, Status: statusFailed, Message: err.Error()}
+	}
+
+	return CheckStatus{Name: name, Status: statusOK}
+}
+
+func writeHealthStatus(w *restful.Response, checks []CheckStatus) {
+	health := HealthStatus{
+		Status: statusOK,
+		Checks: checks,
+	}
+
+	statusCode := http.StatusOK
+	for _, check := range checks {
+		if check.Status != statusOK {
+			health.Status = statusFailed
+			statusCode = http.StatusServiceUnavailable
+		}
+	}
+
+	w.WriteHeaderAndJson(statusCode, health, restful.MIME_JSON)
+}
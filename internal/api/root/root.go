@@ -2,6 +2,7 @@ package root
 
 import (
 	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/adapter"
 	"github.com/portainer/k2d/internal/api/root/healthz"
 	"github.com/portainer/k2d/internal/api/root/version"
 )
@@ -13,10 +14,10 @@ type (
 	}
 )
 
-func NewRootAPI() *Root {
+func NewRootAPI(adapter *adapter.KubeDockerAdapter) *Root {
 	return &Root{
 		version: version.NewVersionService(),
-		health:  healthz.NewHealthzService(),
+		health:  healthz.NewHealthzService(adapter),
 	}
 }
 
@@ -31,6 +32,30 @@ func (api Root) Healthz() *restful.WebService {
 	return routes
 }
 
+// /livez
+func (api Root) Livez() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/livez").
+		Produces(restful.MIME_JSON)
+
+	routes.Route(routes.GET("").
+		To(api.health.Livez))
+
+	return routes
+}
+
+// /readyz
+func (api Root) Readyz() *restful.WebService {
+	routes := new(restful.WebService).
+		Path("/readyz").
+		Produces(restful.MIME_JSON)
+
+	routes.Route(routes.GET("").
+		To(api.health.Readyz))
+
+	return routes
+}
+
 // /version
 func (api Root) Version() *restful.WebService {
 	routes := new(restful.WebService).
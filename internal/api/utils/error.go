@@ -7,9 +7,10 @@ import (
 	"go.uber.org/zap"
 )
 
-// HttpError logs an error and sends an HTTP response with the given status code and error message.
+// HttpError logs an error and sends an HTTP response containing a Kubernetes Status object describing the error.
 // The function retrieves the logger from the context of the HTTP request, logs the error,
-// and then writes the error message to the HTTP response with the specified status code.
+// and then converts the error into a Status object using NewStatusFromError before writing it to the HTTP response.
+// Known adapter errors (e.g. ErrResourceNotFound) take precedence over the specified status code.
 //
 // Parameters:
 // - r: A pointer to the incoming restful.Request from which the logger is retrieved.
@@ -22,5 +23,6 @@ func HttpError(r *restful.Request, w *restful.Response, statusCode int, err erro
 		With(zap.String("request_id", r.Request.Header.Get(types.RequestIDHeader))).
 		Error(err)
 
-	w.WriteError(statusCode, err)
+	status := NewStatusFromError(statusCode, err)
+	w.WriteHeaderAndEntity(int(status.Code), status)
 }
@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+// JSONPatchMIME is the content type used by clients sending a JSON patch (RFC 6902), e.g. kubectl rollout undo
+// or kubectl patch --type=json.
+const JSONPatchMIME = "application/json-patch+json"
+
+// IsJSONPatch returns true if the request body is a JSON patch.
+func IsJSONPatch(r *restful.Request) bool {
+	return strings.HasPrefix(r.HeaderParameter("Content-Type"), JSONPatchMIME)
+}
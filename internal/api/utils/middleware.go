@@ -9,8 +9,6 @@ import (
 	"github.com/portainer/k2d/internal/adapter"
 	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
 	"github.com/portainer/k2d/internal/logging"
-	apierr "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -36,20 +34,10 @@ func NamespaceValidation(adapter *adapter.KubeDockerAdapter) restful.FilterFunct
 		_, err := adapter.GetNamespace(r.Request.Context(), namespace)
 		if err != nil {
 			if errors.Is(err, adaptererr.ErrResourceNotFound) {
-				notFoundErr := apierr.NewNotFound(
-					schema.GroupResource{Group: "", Resource: "namespaces"},
-					namespace,
-				)
-
-				notFoundErr.ErrStatus.TypeMeta = metav1.TypeMeta{
-					Kind:       "Status",
-					APIVersion: "v1",
-				}
-
 				logger := logging.LoggerFromContext(r.Request.Context())
 				logger.Errorw("namespace not found", "namespace", namespace)
 
-				w.WriteHeaderAndEntity(http.StatusNotFound, notFoundErr.ErrStatus)
+				ResourceNotFound(w, schema.GroupResource{Group: "", Resource: "namespaces"}, namespace)
 				return
 			}
 
@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/pkg/jsonpatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+)
+
+// ApplyPatchMIME is the content type used by clients performing a server-side apply
+// (e.g. kubectl apply --server-side, Flux).
+const ApplyPatchMIME = "application/apply-patch+yaml"
+
+const lastAppliedConfigAnnotationKey = "kubectl.kubernetes.io/last-applied-configuration"
+
+// IsServerSideApply returns true if the request is a server-side apply request.
+func IsServerSideApply(r *restful.Request) bool {
+	return strings.HasPrefix(r.HeaderParameter("Content-Type"), ApplyPatchMIME)
+}
+
+// PatchResource applies the patch sent in a PATCH request to the JSON representation of a resource.
+//
+// Server-side apply requests are handled using a naive merge: the applied configuration (YAML or JSON)
+// is merged on top of the original resource using a strategic merge patch. Managed fields are not tracked
+// and any managedFields sent by the client are discarded. The merged resource is then used as the new
+// last-applied configuration of the resource so that it is persisted as-is.
+// When the resource does not exist yet (original is nil), the applied configuration is returned so that the
+// resource can be created.
+//
+// JSON patch requests (e.g. kubectl rollout undo) are applied to the original resource. As the clients sending
+// JSON patches do not maintain the last-applied configuration, the patched resource is used as the new
+// last-applied configuration of the resource, so that the changes are not ignored.
+//
+// Any other request is handled as a strategic merge patch against the original resource.
+//
+// Parameters:
+// - r: The PATCH request, used to detect the patch type.
+// - original: The JSON representation of the existing resource, nil if the resource does not exist.
+// - patch: The body of the PATCH request.
+// - dataStruct: The versioned type of the resource (e.g. corev1.ConfigMap{}) used to compute the strategic merge.
+//
+// Returns:
+// - The JSON representation of the patched resource.
+// - An error if the patch cannot be applied.
+func PatchResource(r *restful.Request, original, patch []byte, dataStruct interface{}) ([]byte, error) {
+	if IsJSONPatch(r) {
+		if original == nil {
+			return nil, fmt.Errorf("unable to apply a JSON patch to a resource that does not exist")
+		}
+
+		patchedData, err := jsonpatch.Apply(original, patch)
+		if err != nil {
+			return nil, err
+		}
+
+		return setLastAppliedConfiguration(patchedData)
+	}
+
+	if !IsServerSideApply(r) {
+		return strategicpatch.StrategicMergePatch(original, patch, dataStruct)
+	}
+
+	appliedConfiguration, err := yaml.YAMLToJSON(patch)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert applied configuration to JSON: %w", err)
+	}
+
+	mergedData := appliedConfiguration
+	if original != nil {
+		mergedData, err = strategicpatch.StrategicMergePatch(original, appliedConfiguration, dataStruct)
+		if err != nil {
+			return nil, fmt.Errorf("unable to merge applied configuration: %w", err)
+		}
+	}
+
+	return setLastAppliedConfiguration(mergedData)
+}
+
+// PatchUnstructuredResource applies the patch sent in a PATCH request to the JSON representation of a resource
+// that does not have a Go type, such as a custom resource. As the patch strategies of the fields of these resources
+// are unknown, strategic merge patches are handled as JSON merge patches (RFC 7386).
+//
+// JSON patch and server-side apply requests are handled as in PatchResource, except that the applied configuration
+// is merged on top of the original resource using a JSON merge patch.
+//
+// Parameters:
+// - r: The PATCH request, used to detect the patch type.
+// - original: The JSON representation of the existing resource, nil if the resource does not exist.
+// - patch: The body of the PATCH request.
+//
+// Returns:
+// - The JSON representation of the patched resource.
+// - An error if the patch cannot be applied.
+func PatchUnstructuredResource(r *restful.Request, original, patch []byte) ([]byte, error) {
+	if IsJSONPatch(r) {
+		return PatchResource(r, original, patch, nil)
+	}
+
+	if IsServerSideApply(r) {
+		appliedConfiguration, err := yaml.YAMLToJSON(patch)
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert applied configuration to JSON: %w", err)
+		}
+
+		mergedData := appliedConfiguration
+		if original != nil {
+			mergedData, err = applyJSONMergePatch(original, appliedConfiguration)
+			if err != nil {
+				return nil, fmt.Errorf("unable to merge applied configuration: %w", err)
+			}
+		}
+
+		return setLastAppliedConfiguration(mergedData)
+	}
+
+	if original == nil {
+		return nil, fmt.Errorf("unable to apply a merge patch to a resource that does not exist")
+	}
+
+	return applyJSONMergePatch(original, patch)
+}
+
+// applyJSONMergePatch applies a JSON merge patch (RFC 7386) to a JSON document.
+func applyJSONMergePatch(original, patch []byte) ([]byte, error) {
+	var document, patchDocument interface{}
+
+	err := json.Unmarshal(original, &document)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal resource: %w", err)
+	}
+
+	err = json.Unmarshal(patch, &patchDocument)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal merge patch: %w", err)
+	}
+
+	return json.Marshal(mergeJSONValue(document, patchDocument))
+}
+
+// mergeJSONValue merges a patch value into a value of a JSON document: the objects are merged recursively,
+// the null values of the patch remove the corresponding fields and any other value replaces the original value.
+func mergeJSONValue(value, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		object = map[string]interface{}{}
+	}
+
+	for key, patchValue := range patchObject {
+		if patchValue == nil {
+			delete(object, key)
+			continue
+		}
+
+		object[key] = mergeJSONValue(object[key], patchValue)
+	}
+
+	return object
+}
+
+// setLastAppliedConfiguration removes the managed fields of a resource and sets its
+// last-applied-configuration annotation to the JSON representation of the resource itself.
+func setLastAppliedConfiguration(data []byte) ([]byte, error) {
+	resource := map[string]interface{}{}
+
+	err := json.Unmarshal(data, &resource)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal resource: %w", err)
+	}
+
+	metadata, ok := resource["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		resource["metadata"] = metadata
+	}
+	delete(metadata, "managedFields")
+
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		annotations = map[string]interface{}{}
+	}
+	delete(annotations, lastAppliedConfigAnnotationKey)
+
+	lastAppliedConfiguration, err := json.Marshal(resource)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal last applied configuration: %w", err)
+	}
+
+	annotations[lastAppliedConfigAnnotationKey] = string(lastAppliedConfiguration)
+	metadata["annotations"] = annotations
+
+	return json.Marshal(resource)
+}
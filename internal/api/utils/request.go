@@ -1,7 +1,12 @@
 package utils
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
 	"github.com/emicklei/go-restful/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // GetNamespaceFromRequest attempts to obtain the namespace from a restful.Request object.
@@ -22,3 +27,78 @@ func GetNamespaceFromRequest(r *restful.Request) string {
 
 	return namespace
 }
+
+// GetDeletionPropagationPolicy returns the propagation policy of a delete request, used to decide whether the dependents
+// of the deleted resource must be garbage collected.
+// The policy is read from the propagationPolicy query parameter or from the DeleteOptions sent in the request body
+// (e.g. kubectl delete --cascade=orphan). The deprecated orphanDependents option is also supported.
+// When no policy is specified, the Background policy is returned as the dependents are deleted by default.
+//
+// Parameters:
+//   - r: A pointer to a restful.Request object representing a delete request.
+//
+// Returns:
+//   - metav1.DeletionPropagation: The propagation policy of the request.
+func GetDeletionPropagationPolicy(r *restful.Request) metav1.DeletionPropagation {
+	if policy := r.QueryParameter("propagationPolicy"); policy != "" {
+		return metav1.DeletionPropagation(policy)
+	}
+
+	deleteOptions := metav1.DeleteOptions{}
+	if r.Request.Body != nil && r.Request.ContentLength != 0 {
+		if err := json.NewDecoder(r.Request.Body).Decode(&deleteOptions); err != nil {
+			return metav1.DeletePropagationBackground
+		}
+	}
+
+	if deleteOptions.PropagationPolicy != nil {
+		return *deleteOptions.PropagationPolicy
+	}
+
+	if deleteOptions.OrphanDependents != nil && *deleteOptions.OrphanDependents {
+		return metav1.DeletePropagationOrphan
+	}
+
+	return metav1.DeletePropagationBackground
+}
+
+// GetExportOptions returns the export options of a get request: when the export query parameter is set to true,
+// the resource must be stripped of the fields populated by the server so that it can be re-applied on another cluster
+// (e.g. kubectl get deploy foo -o yaml). The exact query parameter keeps the namespace of the resource, it defaults to false
+// as with the deprecated export option of the Kubernetes API.
+//
+// Parameters:
+//   - r: A pointer to a restful.Request object representing a get request.
+//
+// Returns:
+//   - bool: Whether the resource must be exported.
+//   - bool: Whether the export is exact.
+//   - error: An error if one of the query parameters is not a boolean.
+func GetExportOptions(r *restful.Request) (bool, bool, error) {
+	export, err := parseBoolQueryParameter(r, "export")
+	if err != nil {
+		return false, false, err
+	}
+
+	exact, err := parseBoolQueryParameter(r, "exact")
+	if err != nil {
+		return false, false, err
+	}
+
+	return export, exact, nil
+}
+
+// parseBoolQueryParameter returns the value of a boolean query parameter, false when it is not provided.
+func parseBoolQueryParameter(r *restful.Request, name string) (bool, error) {
+	value := r.QueryParameter(name)
+	if value == "" {
+		return false, nil
+	}
+
+	parsedValue, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s query parameter: %w", name, err)
+	}
+
+	return parsedValue, nil
+}
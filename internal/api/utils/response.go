@@ -2,17 +2,28 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/k8s"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 )
 
-// UnsupportedOperation is a helper function that writes a 404 Not Found response to the HTTP response.
+// UnsupportedOperation is a helper function that writes a 404 Not Found Status to the HTTP response.
 func UnsupportedOperation(r *restful.Request, w *restful.Response) {
-	w.WriteHeader(http.StatusNotFound)
+	status := NewStatusFromError(http.StatusNotFound, fmt.Errorf("the operation %s %s is not supported", r.Request.Method, r.Request.URL.Path))
+	w.WriteHeaderAndEntity(http.StatusNotFound, status)
+}
+
+// RouteNotFound is a helper function that writes a 404 Not Found Status to the HTTP response, used when the API group,
+// version or resource targeted by a request is not served (e.g. a custom resource whose definition does not exist).
+func RouteNotFound(r *restful.Request, w *restful.Response) {
+	status := NewStatusFromError(http.StatusNotFound, fmt.Errorf("the server could not find the requested resource"))
+	w.WriteHeaderAndEntity(http.StatusNotFound, status)
 }
 
 // listFunc defines a function type that receives a context and returns a list of objects.
@@ -24,10 +35,15 @@ type getTableFunc func(ctx context.Context) (*metav1.Table, error)
 // ListResources handles the HTTP request for listing resources. It supports two response modes,
 // standard list response, and table response. The mode is determined by the "Accept" HTTP header.
 // If the header value is "application/json;as=Table;v=v1;g=meta.k8s.io", a table response is returned.
+// The includeObject query parameter defines the object embedded in each row of the table (see k8s.SetTableRowObjects),
+// the list of resources is fetched as well when the complete objects are requested.
 // Otherwise, a standard list response is returned. It uses provided listFunc and getTableFunc
 // to fetch the data. It will handle any errors that occur during data retrieval and write them
 // to the HTTP response as necessary. Successful data retrieval results in the data being written
 // to the HTTP response in JSON format.
+// A standard list response is restricted to the resources matching the fieldSelector query parameter when it only
+// selects the resources by name and namespace (see metadataFieldSelector).
+// When the watch query parameter is set, the changes of the resources are streamed instead (see WatchResources).
 //
 // Parameters:
 // r: The incoming RESTful request containing information such as the context and HTTP headers.
@@ -35,6 +51,11 @@ type getTableFunc func(ctx context.Context) (*metav1.Table, error)
 // listFunc: A function that fetches a list of resources.
 // getTableFunc: A function that fetches a table of resources.
 func ListResources(r *restful.Request, w *restful.Response, listFunc listFunc, getTableFunc getTableFunc) {
+	if IsWatchRequest(r) {
+		WatchResources(r, w, listFunc)
+		return
+	}
+
 	acceptHeader := r.Request.Header.Get("Accept")
 
 	if strings.Contains(acceptHeader, "application/json;as=Table;v=v1;g=meta.k8s.io") {
@@ -44,15 +65,77 @@ func ListResources(r *restful.Request, w *restful.Response, listFunc listFunc, g
 			return
 		}
 
+		includeObject := metav1.IncludeObjectPolicy(r.QueryParameter("includeObject"))
+
+		var list interface{}
+		if includeObject == metav1.IncludeObject {
+			list, err = listFunc(r.Request.Context())
+			if err != nil {
+				HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to list resources: %w", err))
+				return
+			}
+		}
+
+		err = k8s.SetTableRowObjects(table, includeObject, list)
+		if err != nil {
+			HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to set table objects: %w", err))
+			return
+		}
+
 		w.WriteAsJson(table)
 		return
 	}
 
+	selector, err := metadataFieldSelector(r)
+	if err != nil {
+		HttpError(r, w, http.StatusBadRequest, err)
+		return
+	}
+
 	list, err := listFunc(r.Request.Context())
 	if err != nil {
 		HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to list resources: %w", err))
 		return
 	}
 
+	if selector != nil {
+		list, err = filterListItems(list, selector)
+		if err != nil {
+			HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("unable to filter resources: %w", err))
+			return
+		}
+	}
+
 	w.WriteAsJson(list)
 }
+
+// filterListItems returns a copy of a list of resources restricted to the items matching a metadata field selector.
+func filterListItems(list interface{}, selector fields.Selector) (interface{}, error) {
+	data, err := json.Marshal(list)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal list: %w", err)
+	}
+
+	decodedList := map[string]interface{}{}
+	err = json.Unmarshal(data, &decodedList)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal list: %w", err)
+	}
+
+	items, _ := decodedList["items"].([]interface{})
+
+	filteredItems := []interface{}{}
+	for _, item := range items {
+		object, _ := item.(map[string]interface{})
+		metadata, _ := object["metadata"].(map[string]interface{})
+		namespace, _ := metadata["namespace"].(string)
+		name, _ := metadata["name"].(string)
+
+		if selector.Matches(fields.Set{"metadata.name": name, "metadata.namespace": namespace}) {
+			filteredItems = append(filteredItems, item)
+		}
+	}
+	decodedList["items"] = filteredItems
+
+	return decodedList, nil
+}
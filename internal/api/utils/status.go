@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NewStatusFromError converts an error into a Kubernetes Status object that can be returned to Kubernetes clients.
+// Known adapter errors take precedence over the provided status code:
+//   - ErrResourceNotFound is mapped to a 404 NotFound status.
+//   - ErrResourceAlreadyExists is mapped to a 409 AlreadyExists status.
+//   - ErrResourceConflict is mapped to a 409 Conflict status.
+//   - ErrInvalidResource is mapped to a 422 Invalid status.
+//
+// Any other error is mapped to a Status whose reason is derived from the provided status code.
+//
+// Parameters:
+// - statusCode: The HTTP status code to use when the error is not a known adapter error.
+// - err: The error to convert.
+//
+// Returns:
+// - *metav1.Status: The Status object describing the error.
+func NewStatusFromError(statusCode int, err error) *metav1.Status {
+	var apiStatus apierr.APIStatus
+	if errors.As(err, &apiStatus) {
+		status := apiStatus.Status()
+		return withStatusTypeMeta(&status)
+	}
+
+	switch {
+	case errors.Is(err, adaptererr.ErrResourceNotFound):
+		statusCode = http.StatusNotFound
+	case errors.Is(err, adaptererr.ErrResourceAlreadyExists), errors.Is(err, adaptererr.ErrResourceConflict):
+		statusCode = http.StatusConflict
+	case errors.Is(err, adaptererr.ErrInvalidResource):
+		statusCode = http.StatusUnprocessableEntity
+	}
+
+	reason := reasonForStatusCode(statusCode)
+	if statusCode == http.StatusConflict && errors.Is(err, adaptererr.ErrResourceAlreadyExists) {
+		reason = metav1.StatusReasonAlreadyExists
+	}
+
+	return withStatusTypeMeta(&metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: err.Error(),
+		Reason:  reason,
+		Code:    int32(statusCode),
+	})
+}
+
+// ResourceNotFound writes a 404 NotFound Status object to the HTTP response for the specified resource.
+//
+// Parameters:
+// - w: The restful.Response where the Status will be written.
+// - resource: The group and resource of the object that was not found (e.g. {Group: "apps", Resource: "deployments"}).
+// - name: The name of the object that was not found.
+func ResourceNotFound(w *restful.Response, resource schema.GroupResource, name string) {
+	notFoundErr := apierr.NewNotFound(resource, name)
+	w.WriteHeaderAndEntity(http.StatusNotFound, withStatusTypeMeta(&notFoundErr.ErrStatus))
+}
+
+func withStatusTypeMeta(status *metav1.Status) *metav1.Status {
+	status.TypeMeta = metav1.TypeMeta{
+		Kind:       "Status",
+		APIVersion: "v1",
+	}
+	return status
+}
+
+func reasonForStatusCode(statusCode int) metav1.StatusReason {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return metav1.StatusReasonBadRequest
+	case http.StatusUnauthorized:
+		return metav1.StatusReasonUnauthorized
+	case http.StatusForbidden:
+		return metav1.StatusReasonForbidden
+	case http.StatusNotFound:
+		return metav1.StatusReasonNotFound
+	case http.StatusMethodNotAllowed:
+		return metav1.StatusReasonMethodNotAllowed
+	case http.StatusNotAcceptable:
+		return metav1.StatusReasonNotAcceptable
+	case http.StatusConflict:
+		return metav1.StatusReasonConflict
+	case http.StatusGone:
+		return metav1.StatusReasonGone
+	case http.StatusRequestEntityTooLarge:
+		return metav1.StatusReasonRequestEntityTooLarge
+	case http.StatusUnsupportedMediaType:
+		return metav1.StatusReasonUnsupportedMediaType
+	case http.StatusUnprocessableEntity:
+		return metav1.StatusReasonInvalid
+	case http.StatusTooManyRequests:
+		return metav1.StatusReasonTooManyRequests
+	case http.StatusInternalServerError:
+		return metav1.StatusReasonInternalError
+	case http.StatusServiceUnavailable:
+		return metav1.StatusReasonServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return metav1.StatusReasonTimeout
+	default:
+		return metav1.StatusReasonUnknown
+	}
+}
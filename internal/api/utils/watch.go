@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/k8s"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// watchPollInterval is the interval at which the resources are listed again to detect the changes reported to a watch.
+const watchPollInterval = time.Second
+
+// watchEvent is a change of a resource sent to a watch, framed as done by the Kubernetes API server.
+type watchEvent struct {
+	Type   watch.EventType `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// IsWatchRequest returns true when the request asks to watch the resources instead of listing them.
+func IsWatchRequest(r *restful.Request) bool {
+	watchParam := r.QueryParameter("watch")
+	return watchParam == "true" || watchParam == "1"
+}
+
+// WatchResources streams the changes of a list of resources to the client as watch events (ADDED, MODIFIED and DELETED),
+// one JSON encoded event per line, until the client disconnects or the timeoutSeconds query parameter expires.
+// As k2d does not keep an history of the resources, the resources are listed again every watchPollInterval and
+// compared to the previous list. The resource versions are not tracked either: the current state of the resources
+// is always sent first as ADDED events, whatever the resourceVersion query parameter.
+// The resources can be restricted by name and namespace using the fieldSelector query parameter (see metadataFieldSelector),
+// which is how kubectl wait watches a single resource.
+//
+// Parameters:
+// r: The incoming RESTful request.
+// w: The RESTful response writer to stream the events to.
+// listFunc: A function that fetches the list of resources to watch.
+func WatchResources(r *restful.Request, w *restful.Response, listFunc listFunc) {
+	flusher, ok := w.ResponseWriter.(http.Flusher)
+	if !ok {
+		HttpError(r, w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported by the response writer"))
+		return
+	}
+
+	selector, err := metadataFieldSelector(r)
+	if err != nil {
+		HttpError(r, w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Request.Context()
+	if timeoutParam := r.QueryParameter("timeoutSeconds"); timeoutParam != "" {
+		timeoutSeconds, err := strconv.Atoi(timeoutParam)
+		if err != nil || timeoutSeconds < 0 {
+			HttpError(r, w, http.StatusBadRequest, fmt.Errorf("invalid timeoutSeconds query parameter: %s", timeoutParam))
+			return
+		}
+
+		if timeoutSeconds > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+			defer cancel()
+		}
+	}
+
+	w.Header().Set("Content-Type", restful.MIME_JSON)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	encoder := json.NewEncoder(w)
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	previous := map[string][]byte{}
+	for {
+		list, err := listFunc(ctx)
+		if err == nil {
+			var current map[string][]byte
+			current, err = k8s.EncodeListItems(list)
+			if err == nil {
+				current = filterEncodedItems(current, selector)
+
+				for _, event := range diffWatchEvents(previous, current) {
+					if encoder.Encode(event) != nil {
+						return
+					}
+				}
+				flusher.Flush()
+
+				previous = current
+			}
+		}
+
+		if err != nil && ctx.Err() == nil {
+			status, _ := json.Marshal(NewStatusFromError(http.StatusInternalServerError, fmt.Errorf("unable to list resources: %w", err)))
+			encoder.Encode(watchEvent{Type: watch.Error, Object: status})
+			flusher.Flush()
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// diffWatchEvents returns the events describing the changes between two states of a list of resources,
+// indexed by namespace/name (see k8s.EncodeListItems). The events are sorted by resource for a deterministic output.
+func diffWatchEvents(previous, current map[string][]byte) []watchEvent {
+	keys := make([]string, 0, len(previous)+len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	for key := range previous {
+		if _, found := current[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	events := []watchEvent{}
+	for _, key := range keys {
+		previousObject, existed := previous[key]
+		currentObject, exists := current[key]
+
+		switch {
+		case !existed:
+			events = append(events, watchEvent{Type: watch.Added, Object: currentObject})
+		case !exists:
+			events = append(events, watchEvent{Type: watch.Deleted, Object: previousObject})
+		case string(previousObject) != string(currentObject):
+			events = append(events, watchEvent{Type: watch.Modified, Object: currentObject})
+		}
+	}
+
+	return events
+}
+
+// metadataFieldSelector parses the fieldSelector query parameter of a request when it only selects the resources
+// by name and namespace (e.g. metadata.name=web, as sent by kubectl wait).
+// It returns nil when the parameter is empty or uses other fields, so that the handlers supporting other fields
+// (e.g. events) can apply the selector themselves.
+func metadataFieldSelector(r *restful.Request) (fields.Selector, error) {
+	selectorParam := r.QueryParameter("fieldSelector")
+	if selectorParam == "" {
+		return nil, nil
+	}
+
+	selector, err := fields.ParseSelector(selectorParam)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fieldSelector query parameter: %w", err)
+	}
+
+	for _, requirement := range selector.Requirements() {
+		if requirement.Field != "metadata.name" && requirement.Field != "metadata.namespace" {
+			return nil, nil
+		}
+	}
+
+	return selector, nil
+}
+
+// filterEncodedItems returns the encoded resources, indexed by namespace/name, matching a metadata field selector.
+// All the resources are returned when the selector is nil.
+func filterEncodedItems(items map[string][]byte, selector fields.Selector) map[string][]byte {
+	if selector == nil {
+		return items
+	}
+
+	filteredItems := map[string][]byte{}
+	for key, item := range items {
+		namespace, name, _ := strings.Cut(key, "/")
+		if selector.Matches(fields.Set{"metadata.name": name, "metadata.namespace": namespace}) {
+			filteredItems[key] = item
+		}
+	}
+
+	return filteredItems
+}
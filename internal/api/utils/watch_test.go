@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestDiffWatchEvents(t *testing.T) {
+	previous := map[string][]byte{
+		"default/unchanged": []byte(`{"phase":"Running"}`),
+		"default/modified":  []byte(`{"phase":"Pending"}`),
+		"default/deleted":   []byte(`{"phase":"Running"}`),
+	}
+	current := map[string][]byte{
+		"default/unchanged": []byte(`{"phase":"Running"}`),
+		"default/modified":  []byte(`{"phase":"Running"}`),
+		"default/added":     []byte(`{"phase":"Pending"}`),
+	}
+
+	events := diffWatchEvents(previous, current)
+
+	expected := []struct {
+		eventType watch.EventType
+		object    string
+	}{
+		{eventType: watch.Added, object: `{"phase":"Pending"}`},
+		{eventType: watch.Deleted, object: `{"phase":"Running"}`},
+		{eventType: watch.Modified, object: `{"phase":"Running"}`},
+	}
+
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(events))
+	}
+
+	for i, event := range events {
+		if event.Type != expected[i].eventType || string(event.Object) != expected[i].object {
+			t.Errorf("expected event %d to be %s %s, got %s %s", i, expected[i].eventType, expected[i].object, event.Type, event.Object)
+		}
+	}
+}
+
+func TestFilterEncodedItems(t *testing.T) {
+	items := map[string][]byte{
+		"default/web":  []byte(`{}`),
+		"default/db":   []byte(`{}`),
+		"staging/web":  []byte(`{}`),
+		"/cluster-web": []byte(`{}`),
+	}
+
+	filteredItems := filterEncodedItems(items, fields.ParseSelectorOrDie("metadata.name=web,metadata.namespace=default"))
+	if len(filteredItems) != 1 || filteredItems["default/web"] == nil {
+		t.Errorf("expected only default/web to be selected, got %v", filteredItems)
+	}
+
+	if len(filterEncodedItems(items, nil)) != len(items) {
+		t.Error("expected all the items to be returned without a selector")
+	}
+}
+
+func TestFilterListItems(t *testing.T) {
+	list := map[string]interface{}{
+		"kind": "PodList",
+		"items": []interface{}{
+			map[string]interface{}{"metadata": map[string]interface{}{"name": "web", "namespace": "default"}},
+			map[string]interface{}{"metadata": map[string]interface{}{"name": "db", "namespace": "default"}},
+		},
+	}
+
+	filteredList, err := filterListItems(list, fields.ParseSelectorOrDie("metadata.name=web"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	items := filteredList.(map[string]interface{})["items"].([]interface{})
+	if len(items) != 1 {
+		t.Errorf("expected a single item, got %d", len(items))
+	}
+}
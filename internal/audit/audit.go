@@ -0,0 +1,221 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// eventQueueSize is the maximum number of audit events waiting to be written to the sinks.
+	// Events are dropped (and a warning is logged) when the queue is full so that the API is never blocked by a slow sink.
+	eventQueueSize = 256
+
+	// dropWarningInterval is the minimum interval between two warnings about the events dropped when the queue is full.
+	dropWarningInterval = time.Minute
+
+	// webhookTimeout is the timeout applied to the requests sent to a webhook sink.
+	webhookTimeout = 5 * time.Second
+)
+
+const (
+	// OutcomeSuccess is the outcome of a request that was successfully processed
+	OutcomeSuccess = "success"
+	// OutcomeFailure is the outcome of a request that returned an error
+	OutcomeFailure = "failure"
+)
+
+// Event is an audit record describing a mutating API request.
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"requestID"`
+	// User identifies the client: the SHA-256 hash prefix of the bearer token or the common name of the client certificate.
+	User        string `json:"user"`
+	Role        string `json:"role,omitempty"`
+	Verb        string `json:"verb"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	APIGroup    string `json:"apiGroup,omitempty"`
+	APIVersion  string `json:"apiVersion,omitempty"`
+	Resource    string `json:"resource,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+	StatusCode  int    `json:"statusCode"`
+	Outcome     string `json:"outcome"`
+	SourceIP    string `json:"sourceIP"`
+}
+
+// Sink is the destination of audit events.
+type Sink interface {
+	Write(event Event) error
+}
+
+// Logger records audit events asynchronously into a sink.
+type Logger struct {
+	logger *zap.SugaredLogger
+	sink   Sink
+	events chan Event
+	// dropped is the number of events dropped because the queue was full
+	dropped atomic.Uint64
+
+	mu sync.Mutex
+	// lastDropWarning is the time of the last warning about the dropped events,
+	// droppedAtLastWarning is the number of events dropped at that time
+	lastDropWarning      time.Time
+	droppedAtLastWarning uint64
+}
+
+// NewLogger returns a Logger writing audit events to the given sink.
+// It starts a background goroutine responsible for writing the events.
+func NewLogger(logger *zap.SugaredLogger, sink Sink) *Logger {
+	auditLogger := &Logger{
+		logger: logger,
+		sink:   sink,
+		events: make(chan Event, eventQueueSize),
+	}
+
+	go auditLogger.run()
+
+	return auditLogger
+}
+
+// NewSink returns the sink associated with the given destination.
+// Destinations starting with http:// or https:// are considered as webhook URLs, any other destination
+// is considered as the path of a file where the events are appended as JSON lines.
+func NewSink(destination string) (Sink, error) {
+	if strings.HasPrefix(destination, "http://") || strings.HasPrefix(destination, "https://") {
+		return &WebhookSink{
+			url:    destination,
+			client: &http.Client{Timeout: webhookTimeout},
+		}, nil
+	}
+
+	return NewFileSink(destination)
+}
+
+// Log queues an audit event. It never blocks: the event is dropped when the queue is full.
+func (l *Logger) Log(event Event) {
+	select {
+	case l.events <- event:
+	default:
+		l.dropped.Add(1)
+		l.warnDroppedEvents(event)
+	}
+}
+
+// Dropped returns the number of events dropped since the Logger was created because the queue was full.
+func (l *Logger) Dropped() uint64 {
+	return l.dropped.Load()
+}
+
+// warnDroppedEvents logs a warning about the dropped events. The warning is logged at most once per dropWarningInterval
+// so that the logs are not flooded when the sink cannot keep up, it reports the number of events dropped since the last warning.
+func (l *Logger) warnDroppedEvents(event Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if !l.lastDropWarning.IsZero() && now.Sub(l.lastDropWarning) < dropWarningInterval {
+		return
+	}
+
+	dropped := l.dropped.Load()
+	l.logger.Warnw("audit event queue is full, dropping events",
+		"dropped_events", dropped-l.droppedAtLastWarning,
+		"total_dropped_events", dropped,
+		"request_id", event.RequestID,
+		"verb", event.Verb,
+		"path", event.Path,
+	)
+
+	l.lastDropWarning = now
+	l.droppedAtLastWarning = dropped
+}
+
+func (l *Logger) run() {
+	for event := range l.events {
+		err := l.sink.Write(event)
+		if err != nil {
+			l.logger.Errorw("unable to write audit event",
+				"request_id", event.RequestID,
+				"error", err,
+			)
+		}
+	}
+}
+
+// FileSink appends audit events as JSON lines to a file.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink opens (or creates) the audit log file, creating the parent directories if needed.
+func NewFileSink(filePath string) (*FileSink, error) {
+	err := os.MkdirAll(filepath.Dir(filePath), 0755)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create audit log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open audit log file: %w", err)
+	}
+
+	return &FileSink{file: file}, nil
+}
+
+// Write appends the event to the audit log file.
+func (s *FileSink) Write(event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("unable to marshal audit event: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.file.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("unable to write audit event: %w", err)
+	}
+
+	return nil
+}
+
+// WebhookSink sends audit events as JSON to a webhook using POST requests.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// Write sends the event to the webhook.
+// It returns an error if the webhook cannot be reached or does not respond with a 2xx status code.
+func (s *WebhookSink) Write(event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("unable to marshal audit event: %w", err)
+	}
+
+	response, err := s.client.Post(s.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("unable to send audit event to webhook: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("audit webhook returned status code %d", response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// blockingSink blocks the writes until it is released, notifying each write on started.
+type blockingSink struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSink) Write(event Event) error {
+	s.started <- struct{}{}
+	<-s.release
+	return nil
+}
+
+func TestLoggerDropsEventsWhenQueueIsFull(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	sink := &blockingSink{started: make(chan struct{}, eventQueueSize+1), release: make(chan struct{})}
+	defer close(sink.release)
+
+	auditLogger := NewLogger(zap.New(core).Sugar(), sink)
+
+	// The first event is held by the sink, the next ones fill the queue
+	auditLogger.Log(Event{RequestID: "first"})
+	select {
+	case <-sink.started:
+	case <-time.After(time.Second):
+		t.Fatal("expected the first event to be written")
+	}
+
+	for i := 0; i < eventQueueSize; i++ {
+		auditLogger.Log(Event{RequestID: "queued"})
+	}
+
+	for i := 0; i < 10; i++ {
+		auditLogger.Log(Event{RequestID: "dropped"})
+	}
+
+	if auditLogger.Dropped() != 10 {
+		t.Errorf("expected 10 dropped events, got %d", auditLogger.Dropped())
+	}
+
+	warnings := logs.FilterMessage("audit event queue is full, dropping events").All()
+	if len(warnings) != 1 {
+		t.Fatalf("expected a single warning for the dropped events, got %d", len(warnings))
+	}
+
+	if dropped := warnings[0].ContextMap()["dropped_events"]; dropped != uint64(1) {
+		t.Errorf("expected the first warning to report 1 dropped event, got %v", dropped)
+	}
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+
+	"k8s.io/kubernetes/pkg/apis/core"
+)
+
+// reviewOperation submits the resource of an operation to the admission webhooks before it is queued.
+// The webhooks are called sequentially, in the order the operations are received, so that the operations applied
+// to the same resource are not reordered. The mutations requested by the webhooks are applied to the resource in place.
+// When the resource is rejected, the operation is completed with the admission error, which is returned to the API caller
+// in both the synchronous and asynchronous modes, and a FailedAdmission Warning event is recorded against the resource.
+//
+// Parameters:
+// op - The operation to review.
+//
+// Returns:
+// An error if the resource of the operation was rejected.
+func (controller *OperationController) reviewOperation(op Operation) error {
+	if controller.admission == nil {
+		return nil
+	}
+
+	err := controller.admission.Review(context.Background(), op.Operation)
+	if err == nil {
+		return nil
+	}
+
+	kind, namespace, name := describeOperation(op)
+
+	controller.logger.Warnw("operation rejected by admission webhooks",
+		"kind", kind,
+		"namespace", namespace,
+		"name", name,
+		"error", err,
+		"request_id", op.RequestID,
+	)
+
+	controller.adapter.RecordEvent(core.ObjectReference{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+	}, core.EventTypeWarning, "FailedAdmission", err.Error())
+
+	controller.notifyOperationResult(op, err)
+	controller.registry.setCompleted(op, err)
+	op.complete(err)
+
+	return err
+}
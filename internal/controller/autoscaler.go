@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"context"
+	"time"
+)
+
+// StartAutoscalerLoop periodically evaluates the horizontal pod autoscalers against the resource usage
+// of the containers of their target deployments (see adapter.ReconcileHorizontalPodAutoscalers).
+// The loop runs until the context is cancelled.
+//
+// Parameters:
+// ctx - The context used to stop the loop.
+// interval - The duration between two evaluations.
+func (controller *OperationController) StartAutoscalerLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			controller.logger.Debug("evaluating horizontal pod autoscalers")
+
+			err := controller.adapter.ReconcileHorizontalPodAutoscalers(ctx)
+			if err != nil {
+				controller.logger.Errorw("unable to evaluate horizontal pod autoscalers",
+					"error", err,
+				)
+			}
+		}
+	}
+}
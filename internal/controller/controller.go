@@ -2,27 +2,86 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/admission"
+	"github.com/portainer/k2d/internal/notification"
 	"go.uber.org/zap"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/apis/core"
 )
 
 type (
 	OperationController struct {
-		adapter      *adapter.KubeDockerAdapter
-		logger       *zap.SugaredLogger
-		maxBatchSize int
+		adapter             *adapter.KubeDockerAdapter
+		admission           *admission.Reviewer
+		broadcaster         *notification.Broadcaster
+		logger              *zap.SugaredLogger
+		maxBatchSize        int
+		notifier            *notification.Notifier
+		pendingQueue        *pendingQueue
+		registry            *OperationStatusRegistry
+		retries             chan Operation
+		synchronous         bool
+		workers             int
+		retryQueue          *retryQueue
+		retryMaxAttempts    int
+		retryInitialBackoff time.Duration
+	}
+
+	// OperationControllerOptions represents options that can be used to configure a new OperationController
+	OperationControllerOptions struct {
+		// Adapter is the adapter used to process the operations
+		Adapter *adapter.KubeDockerAdapter
+		// Admission is used to submit the resources to the admission webhooks before they are queued, optional
+		Admission *admission.Reviewer
+		// Broadcaster is used to stream the result of the operations and the lifecycle of the pods to the clients
+		// of the /k2d/stream endpoint, optional
+		Broadcaster *notification.Broadcaster
+		// Logger is the logger that will be used by the controller
+		Logger *zap.SugaredLogger
+		// MaxBatchSize is the maximum number of operations to process in a single batch
+		MaxBatchSize int
+		// Notifier is used to notify the result of the operations and the lifecycle of the pods, optional
+		Notifier *notification.Notifier
+		// PendingQueuePath is the path of the file where the operations accepted but not processed yet are persisted.
+		// The queued operations are lost on restart when it is empty.
+		PendingQueuePath string
+		// QueueEncryptionKey is the key used to encrypt the secrets persisted in the pending and retry queues.
+		// The operations on secrets are not persisted when it is empty.
+		QueueEncryptionKey []byte
+		// Registry is the registry where the status of each operation is reported
+		Registry *OperationStatusRegistry
+		// Synchronous defines whether the API callers wait for their operations to be processed (see Operation.Wait)
+		Synchronous bool
+		// Workers is the number of operations of the same priority that can be processed concurrently
+		Workers int
+		// RetryMaxAttempts is the maximum number of times a failing operation is processed before it is considered as failed
+		RetryMaxAttempts int
+		// RetryInitialBackoff is the delay before the first retry of a failing operation, it is doubled after each attempt
+		RetryInitialBackoff time.Duration
+		// RetryQueuePath is the path of the file where the operations waiting to be retried are persisted.
+		// The operations waiting to be retried are lost on restart when it is empty.
+		RetryQueuePath string
 	}
 
 	Operation struct {
 		Priority  OperationPriority
 		Operation interface{}
 		RequestID string
+		// done receives the result of the operation, see Wait
+		done chan error
+		// attempt is the number of times the operation was already processed and failed
+		attempt int
+		// sequence identifies the operation in the pending queue until it is processed, 0 if it is not persisted
+		sequence uint64
 	}
 
 	OperationBatch struct {
@@ -32,11 +91,17 @@ type (
 	}
 )
 
+// OperationPriority defines the order in which the operations of a batch are processed: all the operations of a priority
+// are processed before the operations of the next priority, so that the dependencies of a resource are created first.
+// The namespaces are not processed by the controller, they are created synchronously by the API.
 type OperationPriority int
 
 const (
+	// HighPriorityOperation is used for the ConfigMaps, Secrets and PersistentVolumeClaims, which can be referenced by workloads
 	HighPriorityOperation OperationPriority = iota
+	// MediumPriorityOperation is used for the workloads (Pods and Deployments)
 	MediumPriorityOperation
+	// LowPriorityOperation is used for the Services, which select the containers of the workloads
 	LowPriorityOperation
 )
 
@@ -58,22 +123,83 @@ func NewOperation(operation interface{}, priority OperationPriority, requestID s
 		Priority:  priority,
 		Operation: operation,
 		RequestID: requestID,
+		done:      make(chan error, 1),
+	}
+}
+
+// Wait must be called after the operation has been sent to the operation controller.
+// When the controller is synchronous, it blocks until the operation has been processed and returns its error.
+// When the first attempt fails, the error is returned right away while the operation is retried in the background.
+// Otherwise, it returns as soon as the operation has been queued by the controller.
+func (op Operation) Wait() error {
+	if op.done == nil {
+		return nil
+	}
+
+	return <-op.done
+}
+
+// complete sends the result of the operation to the caller waiting for it, if any.
+func (op Operation) complete(err error) {
+	if op.done == nil {
+		return
+	}
+
+	select {
+	case op.done <- err:
+	default:
 	}
 }
 
-func NewOperationController(logger *zap.SugaredLogger, adapter *adapter.KubeDockerAdapter, maxBatchSize int) *OperationController {
+func NewOperationController(options *OperationControllerOptions) *OperationController {
+	registry := options.Registry
+	if registry == nil {
+		registry = NewOperationStatusRegistry()
+	}
+
+	workers := options.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	retryMaxAttempts := options.RetryMaxAttempts
+	if retryMaxAttempts <= 0 {
+		retryMaxAttempts = defaultRetryMaxAttempts
+	}
+
+	retryInitialBackoff := options.RetryInitialBackoff
+	if retryInitialBackoff <= 0 {
+		retryInitialBackoff = defaultRetryInitialBackoff
+	}
+
 	return &OperationController{
-		adapter:      adapter,
-		logger:       logger,
-		maxBatchSize: maxBatchSize,
+		adapter:             options.Adapter,
+		admission:           options.Admission,
+		logger:              options.Logger,
+		maxBatchSize:        options.MaxBatchSize,
+		notifier:            options.Notifier,
+		broadcaster:         options.Broadcaster,
+		pendingQueue:        newPendingQueue(options.PendingQueuePath, options.QueueEncryptionKey),
+		registry:            registry,
+		retries:             make(chan Operation),
+		synchronous:         options.Synchronous,
+		workers:             workers,
+		retryQueue:          newRetryQueue(options.RetryQueuePath, options.QueueEncryptionKey),
+		retryMaxAttempts:    retryMaxAttempts,
+		retryInitialBackoff: retryInitialBackoff,
 	}
 }
 
-// StartControlLoop initializes and controls a loop to handle incoming operations. This function creates and
+// StartControlLoop initializes and controls a loop to handle incoming operations. Each operation is first submitted to the
+// admission webhooks, if any (see reviewOperation). This function creates and
 // processes batches of operations, with each batch either being a collection of operations up to the maximum batch size
 // or all operations received within a 3 second period, whichever condition is met first. It processes these batches
 // in parallel, ensuring the handling of incoming operations is non-blocking. This function continues running until
 // the input channel is closed and all operations have been processed.
+// The accepted operations are persisted in the pending queue before the callers are released, so that they are
+// replayed after a restart of k2d if they were not processed (see ReplayPendingOperations).
+// The operations to retry (see scheduleRetry) are added to the batches as well, so that they are ordered and serialized
+// with the other operations on the same resource.
 //
 // Parameters:
 // ops - A channel from which operations are received.
@@ -98,9 +224,11 @@ func (controller *OperationController) StartControlLoop(ops chan Operation) {
 		}
 	}
 
-	// Continually read from ops channel until it's closed
-	for op := range ops {
+	// enqueue adds an operation to the current batch
+	enqueue := func(op Operation) {
 		mu.Lock()
+		defer mu.Unlock()
+
 		queue = append(queue, op)
 
 		// If the queue is full, process the queue
@@ -114,15 +242,42 @@ func (controller *OperationController) StartControlLoop(ops chan Operation) {
 				mu.Unlock()
 			})
 		}
-		mu.Unlock()
 	}
 
-	// Process any remaining operations in the queue after ops channel is closed
-	mu.Lock()
-	processQueue()
-	mu.Unlock()
+	// Continually read from ops channel until it's closed
+	for {
+		select {
+		case op, ok := <-ops:
+			if !ok {
+				// Process any remaining operations in the queue after ops channel is closed
+				mu.Lock()
+				processQueue()
+				mu.Unlock()
+				return
+			}
+
+			controller.registry.setPending(op)
+
+			// The operations rejected by the admission webhooks are never queued
+			if controller.reviewOperation(op) != nil {
+				continue
+			}
+
+			op = controller.addPending(op)
+
+			// When the controller is asynchronous, the caller is released as soon as the operation is queued
+			if !controller.synchronous {
+				op.complete(nil)
+			}
+
+			enqueue(op)
+		case op := <-controller.retries:
+			enqueue(op)
+		}
+	}
 }
 
+// newOperationBatch groups the operations by priority, preserving the order in which they were received.
 func newOperationBatch(operations []Operation) OperationBatch {
 	return OperationBatch{
 		HighPriorityOperations:   filterOperationsByPriority(operations, HighPriorityOperation),
@@ -161,60 +316,353 @@ func (controller *OperationController) processPriorityOperations(ops []Operation
 		"priority", priority.String(),
 	)
 
-	for _, op := range ops {
-		controller.processOperation(op)
+	controller.processOperationsConcurrently(ops)
+}
+
+// processOperationsConcurrently processes a set of operations using the worker pool of the controller.
+// The operations associated with the same resource name in the same namespace are processed sequentially,
+// in the order they were received, as they are applied to the same container.
+func (controller *OperationController) processOperationsConcurrently(ops []Operation) {
+	groups := groupOperationsByResourceName(ops)
+
+	workers := controller.workers
+	if workers > len(groups) {
+		workers = len(groups)
 	}
+
+	if workers <= 1 {
+		for _, group := range groups {
+			for _, op := range group {
+				controller.processOperation(op)
+			}
+		}
+		return
+	}
+
+	queue := make(chan []Operation, len(groups))
+	for _, group := range groups {
+		queue <- group
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for group := range queue {
+				for _, op := range group {
+					controller.processOperation(op)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
 }
 
+// processOperation processes an operation and reports its result.
+// When the operation fails, it is retried with an exponential backoff until the maximum number of attempts
+// is reached: the failure can be transient (e.g. registry outage) or caused by a missing dependency that
+// is created by another batch of operations. A Warning event is recorded against the resource on each failed attempt.
+// When the last attempt fails, the error is logged and the operation is marked as failed in the operation status registry.
 func (controller *OperationController) processOperation(op Operation) {
+	// A retry is skipped when the operation has been superseded by another operation on the same resource in the meantime
+	if op.attempt > 0 && !controller.retryQueue.isCurrent(op) {
+		controller.logger.Debugw("operation retry superseded, skipping",
+			"request_id", op.RequestID,
+		)
+		controller.registry.setCompleted(op, nil)
+		op.complete(nil)
+		return
+	}
+
+	// An operation submitted through the API supersedes any operation waiting to be retried for the same resource
+	if op.attempt == 0 {
+		controller.removeRetry(op)
+		controller.setPendingPod(op)
+	}
+
+	err := controller.executeOperation(op)
+	retrying := err != nil && op.attempt+1 < controller.retryMaxAttempts
+	controller.updatePendingPod(op, err, retrying)
+
+	// From now on, the operation is either completed or persisted in the retry queue
+	controller.removePending(op)
+
+	if retrying {
+		// A synchronous caller receives the error of the first attempt rather than waiting for all the retries,
+		// the operation keeps being retried in the background
+		if controller.synchronous {
+			op.complete(err)
+		}
+
+		controller.retryOperation(op, err)
+		return
+	}
+
+	if op.attempt > 0 {
+		controller.removeRetry(op)
+	}
+
+	if err != nil {
+		kind, namespace, name := describeOperation(op)
+
+		controller.logger.Errorw("unable to process operation",
+			"kind", kind,
+			"namespace", namespace,
+			"name", name,
+			"error", err,
+			"attempts", op.attempt+1,
+			"request_id", op.RequestID,
+		)
+
+		controller.adapter.RecordEvent(core.ObjectReference{
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      name,
+		}, core.EventTypeWarning, "FailedCreate", err.Error())
+	}
+
+	controller.notifyOperationResult(op, err)
+	controller.registry.setCompleted(op, err)
+
+	if controller.synchronous {
+		op.complete(err)
+	}
+}
+
+// retryOperation records the failure of an operation and schedules its next attempt.
+// The operation is persisted in the retry queue so that it is retried after a restart of k2d.
+func (controller *OperationController) retryOperation(op Operation, err error) {
+	kind, namespace, name := describeOperation(op)
+	backoff := retryBackoff(controller.retryInitialBackoff, op.attempt)
+
+	controller.logger.Warnw("unable to process operation, retrying",
+		"kind", kind,
+		"namespace", namespace,
+		"name", name,
+		"error", err,
+		"attempt", op.attempt+1,
+		"backoff", backoff.String(),
+		"request_id", op.RequestID,
+	)
+
+	reason := "BackOff"
+	if isMissingDependencyError(err) {
+		reason = "MissingDependency"
+	}
+
+	controller.adapter.RecordEvent(core.ObjectReference{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+	}, core.EventTypeWarning, reason, fmt.Sprintf("attempt %d/%d failed: %s, retrying in %s", op.attempt+1, controller.retryMaxAttempts, err, backoff))
+
+	op.attempt++
+	controller.scheduleRetry(op, time.Now().Add(backoff))
+}
+
+// scheduleRetry stores an operation in the retry queue and sends it back to the control loop at the specified time,
+// where it is batched with the other operations (see StartControlLoop). The retry is skipped when it has been superseded
+// by another operation on the same resource in the meantime (see processOperation).
+func (controller *OperationController) scheduleRetry(op Operation, nextAttempt time.Time) {
+	err := controller.retryQueue.add(op, nextAttempt)
+	if err != nil {
+		controller.logger.Warnw("unable to persist operation retry",
+			"error", err,
+			"request_id", op.RequestID,
+		)
+	}
+
+	time.AfterFunc(time.Until(nextAttempt), func() {
+		controller.retries <- op
+	})
+}
+
+// addPending persists an accepted operation in the pending queue and returns the operation with its sequence number.
+func (controller *OperationController) addPending(op Operation) Operation {
+	op, err := controller.pendingQueue.add(op)
+	if err != nil {
+		controller.logger.Warnw("unable to persist queued operation, it will be lost if k2d is restarted before it is processed",
+			"error", err,
+			"request_id", op.RequestID,
+		)
+	}
+
+	return op
+}
+
+func (controller *OperationController) removePending(op Operation) {
+	err := controller.pendingQueue.remove(op)
+	if err != nil {
+		controller.logger.Warnw("unable to remove operation from the pending queue",
+			"error", err,
+			"request_id", op.RequestID,
+		)
+	}
+}
+
+// ReplayPendingOperations processes the operations that were queued but not processed when k2d was stopped,
+// in the order they were received. It must be called after ResumeRetries: an operation is received after the failed
+// operations of the same resource, a replayed operation therefore supersedes the retry persisted for its resource.
+func (controller *OperationController) ReplayPendingOperations() error {
+	operations, err := controller.pendingQueue.load()
+	if err != nil {
+		return fmt.Errorf("unable to load pending queue: %w", err)
+	}
+
+	if len(operations) == 0 {
+		return nil
+	}
+
+	for _, op := range operations {
+		controller.registry.setPending(op)
+	}
+
+	controller.logger.Infow("replaying queued operations",
+		"operation_count", len(operations),
+	)
+
+	go controller.processOperationQueue(operations)
+
+	return nil
+}
+
+func (controller *OperationController) removeRetry(op Operation) {
+	err := controller.retryQueue.remove(op)
+	if err != nil {
+		controller.logger.Warnw("unable to remove operation from the retry queue",
+			"error", err,
+			"request_id", op.RequestID,
+		)
+	}
+}
+
+// ResumeRetries schedules the operations that were waiting to be retried when k2d was stopped.
+// The operations whose next attempt is overdue are retried immediately.
+func (controller *OperationController) ResumeRetries() error {
+	operations, nextAttempts, err := controller.retryQueue.load()
+	if err != nil {
+		return fmt.Errorf("unable to load retry queue: %w", err)
+	}
+
+	for i, op := range operations {
+		controller.registry.setPending(op)
+		controller.setPendingPod(op)
+		controller.scheduleRetry(op, nextAttempts[i])
+	}
+
+	if len(operations) > 0 {
+		controller.logger.Infow("resumed operation retries",
+			"operation_count", len(operations),
+		)
+	}
+
+	return nil
+}
+
+func (controller *OperationController) executeOperation(op Operation) error {
 	switch op.Operation.(type) {
 	case *corev1.Pod:
-		err := controller.createPod(op)
-		if err != nil {
-			controller.logger.Errorw("unable to create pod",
-				"error", err,
-				"request_id", op.RequestID,
-			)
-		}
+		return controller.createPod(op)
 	case *appsv1.Deployment:
-		err := controller.createDeployment(op)
-		if err != nil {
-			controller.logger.Errorw("unable to create deployment",
-				"error", err,
-				"request_id", op.RequestID,
-			)
-		}
+		return controller.createDeployment(op)
 	case *corev1.ConfigMap:
-		err := controller.createConfigMap(op)
-		if err != nil {
-			controller.logger.Errorw("unable to create configmap",
-				"error", err,
-			)
-		}
+		return controller.createConfigMap(op)
 	case *corev1.Secret:
-		err := controller.createSecret(op)
-		if err != nil {
-			controller.logger.Errorw("unable to create secret",
-				"error", err,
-			)
-		}
+		return controller.createSecret(op)
 	case *corev1.Service:
-		err := controller.createService(op)
-		if err != nil {
-			controller.logger.Errorw("unable to update container",
-				"error", err,
-				"request_id", op.RequestID,
-			)
-		}
+		return controller.createService(op)
 	case *corev1.PersistentVolumeClaim:
-		err := controller.createPersistentVolumeClaim(op)
-		if err != nil {
-			controller.logger.Errorw("unable to update persistent volume claim",
-				"error", err,
-				"request_id", op.RequestID,
-			)
+		return controller.createPersistentVolumeClaim(op)
+	}
+
+	return nil
+}
+
+// pendingPodFromOperation returns the pod created by a workload operation (pod or deployment),
+// nil if the operation is not associated with a workload.
+func pendingPodFromOperation(op Operation) *corev1.Pod {
+	switch resource := op.Operation.(type) {
+	case *corev1.Pod:
+		return resource
+	case *appsv1.Deployment:
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        resource.Name,
+				Namespace:   resource.Namespace,
+				Labels:      resource.Spec.Template.Labels,
+				Annotations: resource.Spec.Template.Annotations,
+			},
+			Spec: resource.Spec.Template.Spec,
 		}
 	}
+
+	return nil
+}
+
+// setPendingPod reports the pod created by a workload operation as pending until its container is created,
+// so that it can be retrieved through the API even if the creation fails (see adapter.SetPendingPod).
+func (controller *OperationController) setPendingPod(op Operation) {
+	pod := pendingPodFromOperation(op)
+	if pod == nil {
+		return
+	}
+
+	err := controller.adapter.SetPendingPod(pod)
+	if err != nil {
+		controller.logger.Warnw("unable to set pending pod",
+			"name", pod.Name,
+			"namespace", pod.Namespace,
+			"error", err,
+		)
+	}
+}
+
+// updatePendingPod reports the result of a workload operation in the associated pending pod.
+// The pending pod is removed once the container is created.
+func (controller *OperationController) updatePendingPod(op Operation, err error, retrying bool) {
+	pod := pendingPodFromOperation(op)
+	if pod == nil {
+		return
+	}
+
+	if err == nil {
+		controller.adapter.DeletePendingPod(pod.Namespace, pod.Name)
+		return
+	}
+
+	controller.adapter.SetPendingPodFailure(pod.Namespace, pod.Name, err, retrying)
+}
+
+// describeOperation returns the kind, namespace and name of the resource associated with an operation.
+func describeOperation(op Operation) (string, string, string) {
+	kind := fmt.Sprintf("%T", op.Operation)
+	switch op.Operation.(type) {
+	case *corev1.Pod:
+		kind = "Pod"
+	case *appsv1.Deployment:
+		kind = "Deployment"
+	case *corev1.ConfigMap:
+		kind = "ConfigMap"
+	case *corev1.Secret:
+		kind = "Secret"
+	case *corev1.Service:
+		kind = "Service"
+	case *corev1.PersistentVolumeClaim:
+		kind = "PersistentVolumeClaim"
+	case *corev1.Namespace:
+		kind = "Namespace"
+	}
+
+	accessor, err := meta.Accessor(op.Operation)
+	if err != nil {
+		return kind, "", ""
+	}
+
+	return kind, accessor.GetNamespace(), accessor.GetName()
 }
 
 func (controller *OperationController) createPod(op Operation) error {
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/portainer/k2d/internal/adapter"
+)
+
+// StartCrashLoopDetectionLoop tracks the exit and restart history of the containers of the pods managed by k2d
+// (see adapter.TrackContainerLifecycleEvent) so that the restart count and the CrashLoopBackOff state are reported
+// in the pod status. The Docker events are watched again after a delay when the event stream fails.
+// The loop runs until the context is cancelled.
+//
+// Parameters:
+// ctx - The context used to stop the loop.
+func (controller *OperationController) StartCrashLoopDetectionLoop(ctx context.Context) {
+	for {
+		err := controller.adapter.WatchContainerLifecycleEvents(ctx, func(event adapter.ContainerLifecycleEvent) {
+			controller.adapter.TrackContainerLifecycleEvent(ctx, event)
+		})
+		if err != nil {
+			controller.logger.Warnw("unable to watch container lifecycle events for crash loop detection, retrying",
+				"error", err,
+				"retry_delay", lifecycleWatchRetryDelay.String(),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(lifecycleWatchRetryDelay):
+		}
+	}
+}
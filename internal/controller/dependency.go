@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"errors"
+
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+)
+
+// groupOperationsByResourceName groups the operations by namespace and resource name, preserving the order in which
+// they were received. The resource kind is ignored as resources sharing the same name (e.g. a pod and a deployment)
+// are associated with the same container.
+// The Services are all grouped together: Services with different names can select the same containers, and they are
+// applied one at a time by the adapter anyway.
+func groupOperationsByResourceName(operations []Operation) [][]Operation {
+	groups := [][]Operation{}
+	indexes := map[string]int{}
+
+	for _, op := range operations {
+		kind, namespace, name := describeOperation(op)
+		key := namespace + "/" + name
+		if kind == "Service" {
+			key = kind
+		}
+
+		index, found := indexes[key]
+		if !found {
+			index = len(groups)
+			indexes[key] = index
+			groups = append(groups, []Operation{})
+		}
+
+		groups[index] = append(groups[index], op)
+	}
+
+	return groups
+}
+
+// isMissingDependencyError returns true if an operation failed because a resource it references does not exist (yet).
+func isMissingDependencyError(err error) bool {
+	return errors.Is(err, adaptererr.ErrResourceNotFound)
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGroupOperationsByResourceName(t *testing.T) {
+	objectMeta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "default"}
+	}
+
+	operations := []Operation{
+		NewOperation(&corev1.Pod{ObjectMeta: objectMeta("web")}, MediumPriorityOperation, "1"),
+		NewOperation(&corev1.Service{ObjectMeta: objectMeta("web")}, LowPriorityOperation, "2"),
+		NewOperation(&appsv1.Deployment{ObjectMeta: objectMeta("web")}, MediumPriorityOperation, "3"),
+		NewOperation(&corev1.Service{ObjectMeta: objectMeta("web-nodeport")}, LowPriorityOperation, "4"),
+		NewOperation(&corev1.Pod{ObjectMeta: objectMeta("db")}, MediumPriorityOperation, "5"),
+	}
+
+	groups := groupOperationsByResourceName(operations)
+
+	expected := [][]string{{"1", "3"}, {"2", "4"}, {"5"}}
+	if len(groups) != len(expected) {
+		t.Fatalf("expected %d groups, got %d", len(expected), len(groups))
+	}
+
+	for i, group := range groups {
+		if len(group) != len(expected[i]) {
+			t.Fatalf("expected group %d to contain %d operations, got %d", i, len(expected[i]), len(group))
+		}
+
+		for j, op := range group {
+			if op.RequestID != expected[i][j] {
+				t.Errorf("expected operation %d of group %d to be %s, got %s", j, i, expected[i][j], op.RequestID)
+			}
+		}
+	}
+}
+
+func TestNewOperationBatch(t *testing.T) {
+	objectMeta := metav1.ObjectMeta{Name: "web", Namespace: "default"}
+
+	operations := []Operation{
+		NewOperation(&corev1.Service{ObjectMeta: objectMeta}, LowPriorityOperation, "1"),
+		NewOperation(&corev1.Pod{ObjectMeta: objectMeta}, MediumPriorityOperation, "2"),
+		NewOperation(&corev1.Secret{ObjectMeta: objectMeta}, HighPriorityOperation, "3"),
+		NewOperation(&corev1.ConfigMap{ObjectMeta: objectMeta}, HighPriorityOperation, "4"),
+	}
+
+	batch := newOperationBatch(operations)
+
+	expected := [][]string{{"3", "4"}, {"2"}, {"1"}}
+	for i, ops := range [][]Operation{batch.HighPriorityOperations, batch.MediumPriorityOperations, batch.LowPriorityOperations} {
+		if len(ops) != len(expected[i]) {
+			t.Fatalf("expected %d operations with priority %d, got %d", len(expected[i]), i, len(ops))
+		}
+
+		for j, op := range ops {
+			if op.RequestID != expected[i][j] {
+				t.Errorf("expected operation %d with priority %d to be %s, got %s", j, i, expected[i][j], op.RequestID)
+			}
+		}
+	}
+}
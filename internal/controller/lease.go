@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"context"
+	"time"
+)
+
+// StartNodeLeaseLoop renews the heartbeat lease of the node immediately and then periodically
+// (see adapter.RenewNodeLease), so that the clients checking the liveness of the node through its lease consider it alive.
+// The loop runs until the context is cancelled.
+//
+// Parameters:
+// ctx - The context used to stop the loop.
+// interval - The duration between two renewals.
+func (controller *OperationController) StartNodeLeaseLoop(ctx context.Context, interval time.Duration) {
+	controller.renewNodeLease(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			controller.renewNodeLease(ctx)
+		}
+	}
+}
+
+func (controller *OperationController) renewNodeLease(ctx context.Context) {
+	controller.logger.Debug("renewing node lease")
+
+	err := controller.adapter.RenewNodeLease(ctx)
+	if err != nil {
+		controller.logger.Errorw("unable to renew node lease",
+			"error", err,
+		)
+	}
+}
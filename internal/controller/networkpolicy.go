@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"context"
+	"time"
+)
+
+// StartNetworkPolicyLoop applies the network policies immediately and then periodically (see adapter.ApplyNetworkPolicies),
+// so that the containers created or re-created since the last evaluation are isolated as well.
+// The loop runs until the context is cancelled.
+//
+// Parameters:
+// ctx - The context used to stop the loop.
+// interval - The duration between two evaluations.
+func (controller *OperationController) StartNetworkPolicyLoop(ctx context.Context, interval time.Duration) {
+	controller.applyNetworkPolicies(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			controller.applyNetworkPolicies(ctx)
+		}
+	}
+}
+
+func (controller *OperationController) applyNetworkPolicies(ctx context.Context) {
+	controller.logger.Debug("applying network policies")
+
+	err := controller.adapter.ApplyNetworkPolicies(ctx)
+	if err != nil {
+		controller.logger.Errorw("unable to apply network policies",
+			"error", err,
+		)
+	}
+}
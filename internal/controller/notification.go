@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/portainer/k2d/internal/adapter"
+	"github.com/portainer/k2d/internal/notification"
+)
+
+// lifecycleWatchRetryDelay is the delay before watching the container lifecycle events again after a failure
+// of the event stream (e.g. restart of the Docker daemon).
+const lifecycleWatchRetryDelay = 5 * time.Second
+
+// notify sends a notification to the webhook notifier and publishes it to the subscribers of the stream endpoint.
+func (controller *OperationController) notify(n notification.Notification) {
+	controller.notifier.Notify(n)
+	controller.broadcaster.Publish(n)
+}
+
+// notifyOperationResult notifies the result of an operation once it has been processed, including all its retries.
+// As the operations are the only way to write to the store, this also notifies the changes of the stored resources
+// (e.g. configmaps and secrets).
+func (controller *OperationController) notifyOperationResult(op Operation, err error) {
+	if controller.notifier == nil && controller.broadcaster == nil {
+		return
+	}
+
+	kind, namespace, name := describeOperation(op)
+
+	result := notification.Notification{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Reason:    notification.ReasonApplied,
+		RequestID: op.RequestID,
+	}
+
+	if err != nil {
+		result.Reason = notification.ReasonFailed
+		result.Message = err.Error()
+	}
+
+	controller.notify(result)
+}
+
+// StartLifecycleNotificationLoop notifies the lifecycle events of the pods managed by k2d (started, completed, crashed,
+// out of memory and deleted) using the notifier and the broadcaster of the controller. The Docker events are watched
+// again after a delay when the event stream fails. The loop runs until the context is cancelled.
+//
+// Parameters:
+// ctx - The context used to stop the loop.
+func (controller *OperationController) StartLifecycleNotificationLoop(ctx context.Context) {
+	if controller.notifier == nil && controller.broadcaster == nil {
+		return
+	}
+
+	for {
+		err := controller.adapter.WatchContainerLifecycleEvents(ctx, controller.notifyContainerLifecycleEvent)
+		if err != nil {
+			controller.logger.Warnw("unable to watch container lifecycle events, retrying",
+				"error", err,
+				"retry_delay", lifecycleWatchRetryDelay.String(),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(lifecycleWatchRetryDelay):
+		}
+	}
+}
+
+func (controller *OperationController) notifyContainerLifecycleEvent(event adapter.ContainerLifecycleEvent) {
+	lifecycleNotification := notification.Notification{
+		Timestamp: event.Time,
+		Kind:      "Pod",
+		Namespace: event.Namespace,
+		Name:      event.PodName,
+	}
+
+	switch event.Action {
+	case adapter.ContainerStartedAction:
+		lifecycleNotification.Reason = notification.ReasonStarted
+	case adapter.ContainerDiedAction:
+		exitCode := event.ExitCode
+		lifecycleNotification.ExitCode = &exitCode
+
+		lifecycleNotification.Reason = notification.ReasonCompleted
+		if exitCode != 0 {
+			lifecycleNotification.Reason = notification.ReasonCrashed
+		}
+	case adapter.ContainerOOMAction:
+		lifecycleNotification.Reason = notification.ReasonOOMKilled
+	case adapter.ContainerDestroyedAction:
+		lifecycleNotification.Reason = notification.ReasonDeleted
+	default:
+		return
+	}
+
+	controller.notify(lifecycleNotification)
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// pendingEntry is the persisted representation of an operation waiting to be processed.
+type pendingEntry struct {
+	Sequence  uint64            `json:"sequence"`
+	Kind      string            `json:"kind"`
+	RequestID string            `json:"requestID"`
+	Priority  OperationPriority `json:"priority"`
+	Object    json.RawMessage   `json:"object"`
+}
+
+// pendingQueue keeps track of the operations accepted by the controller that have not been processed yet,
+// so that the operations queued when k2d is stopped are not lost (see ReplayPendingOperations).
+// Each operation is identified by a sequence number preserving the order in which the operations were received.
+// When a path is specified, the queue is persisted on disk after each change.
+type pendingQueue struct {
+	mu            sync.Mutex
+	path          string
+	encryptionKey []byte
+	nextSequence  uint64
+	entries       map[uint64]pendingEntry
+}
+
+// newPendingQueue returns a pending queue persisted in the specified file, the secrets are encrypted with
+// the specified key (see encodeOperationObject). The queue is not persisted when the path is empty.
+func newPendingQueue(path string, encryptionKey []byte) *pendingQueue {
+	return &pendingQueue{
+		path:          path,
+		encryptionKey: encryptionKey,
+		nextSequence:  1,
+		entries:       map[uint64]pendingEntry{},
+	}
+}
+
+// add stores an operation in the queue and returns the operation with its sequence number.
+// The operation is returned even when it cannot be persisted, it is then lost on restart.
+func (queue *pendingQueue) add(op Operation) (Operation, error) {
+	kind, _, _ := describeOperation(op)
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	op.sequence = queue.nextSequence
+	queue.nextSequence++
+
+	entry := pendingEntry{
+		Sequence:  op.sequence,
+		Kind:      kind,
+		RequestID: op.RequestID,
+		Priority:  op.Priority,
+	}
+
+	var err error
+	if queue.path != "" {
+		entry.Object, err = encodeOperationObject(kind, op.Operation, queue.encryptionKey)
+	}
+
+	// The entry is kept in memory even when its resource cannot be encoded, it is then not persisted
+	queue.entries[op.sequence] = entry
+	if err != nil {
+		return op, err
+	}
+
+	return op, queue.persist()
+}
+
+// remove removes an operation from the queue once it has been processed.
+func (queue *pendingQueue) remove(op Operation) error {
+	if op.sequence == 0 {
+		return nil
+	}
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	if _, found := queue.entries[op.sequence]; !found {
+		return nil
+	}
+
+	delete(queue.entries, op.sequence)
+	return queue.persist()
+}
+
+// load reads the persisted queue and returns the operations waiting to be processed, in the order they were received.
+// The operations are kept in the queue until they are processed. Entries that cannot be decoded are discarded.
+func (queue *pendingQueue) load() ([]Operation, error) {
+	if queue.path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(queue.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read pending queue file: %w", err)
+	}
+
+	entries := []pendingEntry{}
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal pending queue: %w", err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Sequence < entries[j].Sequence
+	})
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	operations := []Operation{}
+	for _, entry := range entries {
+		if entry.Sequence >= queue.nextSequence {
+			queue.nextSequence = entry.Sequence + 1
+		}
+
+		object, err := decodeOperationObject(entry.Kind, entry.Object, queue.encryptionKey)
+		if err != nil {
+			continue
+		}
+
+		queue.entries[entry.Sequence] = entry
+		operations = append(operations, Operation{
+			Priority:  entry.Priority,
+			Operation: object,
+			RequestID: entry.RequestID,
+			sequence:  entry.Sequence,
+		})
+	}
+
+	return operations, queue.persist()
+}
+
+// persist writes the queue on disk. It must be called with the queue lock held.
+func (queue *pendingQueue) persist() error {
+	if queue.path == "" {
+		return nil
+	}
+
+	entries := make([]pendingEntry, 0, len(queue.entries))
+	for _, entry := range queue.entries {
+		if entry.Object == nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("unable to marshal pending queue: %w", err)
+	}
+
+	return writeQueueFile(queue.path, data)
+}
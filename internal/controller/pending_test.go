@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/portainer/k2d/internal/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPendingQueueReplay(t *testing.T) {
+	queuePath := path.Join(t.TempDir(), types.PendingQueueFilename)
+	queue := newPendingQueue(queuePath, nil)
+
+	newConfigMapOperation := func(name string) Operation {
+		return NewOperation(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}, HighPriorityOperation, name)
+	}
+
+	first, err := queue.add(newConfigMapOperation("first"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := queue.add(newConfigMapOperation("second")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := queue.add(newConfigMapOperation("third")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := queue.remove(first); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	restarted := newPendingQueue(queuePath, nil)
+	operations, err := restarted.load()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(operations) != 2 || operations[0].RequestID != "second" || operations[1].RequestID != "third" {
+		t.Fatalf("expected the unprocessed operations in order, got %+v", operations)
+	}
+
+	configMap, ok := operations[0].Operation.(*corev1.ConfigMap)
+	if !ok || configMap.Name != "second" {
+		t.Errorf("unexpected replayed operation: %+v", operations[0].Operation)
+	}
+
+	next, err := restarted.add(newConfigMapOperation("fourth"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if next.sequence <= operations[1].sequence {
+		t.Errorf("expected the sequence to continue after the replayed operations, got %d", next.sequence)
+	}
+}
+
+func TestPendingQueueEncryptsSecrets(t *testing.T) {
+	queuePath := path.Join(t.TempDir(), types.PendingQueueFilename)
+	encryptionKey := bytes.Repeat([]byte("k"), 32)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("sensitive-value")},
+	}
+
+	queue := newPendingQueue(queuePath, encryptionKey)
+	if _, err := queue.add(NewOperation(secret, HighPriorityOperation, "secret")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	content, err := os.ReadFile(queuePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if bytes.Contains(content, []byte("credentials")) || bytes.Contains(content, secret.Data["password"]) {
+		t.Errorf("expected the secret to be encrypted in the queue file, got %s", content)
+	}
+
+	operations, err := newPendingQueue(queuePath, encryptionKey).load()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(operations) != 1 {
+		t.Fatalf("expected the secret operation to be replayed, got %d operations", len(operations))
+	}
+
+	replayed, ok := operations[0].Operation.(*corev1.Secret)
+	if !ok || string(replayed.Data["password"]) != "sensitive-value" {
+		t.Errorf("unexpected replayed operation: %+v", operations[0].Operation)
+	}
+
+	unencrypted := newPendingQueue(path.Join(t.TempDir(), types.PendingQueueFilename), nil)
+	if _, err := unencrypted.add(NewOperation(secret, HighPriorityOperation, "secret")); err == nil {
+		t.Errorf("expected an error when persisting a secret without an encryption key")
+	}
+}
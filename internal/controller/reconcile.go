@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"context"
+	"time"
+)
+
+// StartReconcileLoop periodically reconciles the workloads managed by k2d with the actual state of Docker,
+// re-creating the containers that have been removed or that have drifted from their definition (see adapter.ReconcileWorkloads).
+// The loop runs until the context is cancelled.
+//
+// Parameters:
+// ctx - The context used to stop the loop.
+// interval - The duration between two reconciliations.
+func (controller *OperationController) StartReconcileLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			controller.logger.Debug("reconciling workloads")
+
+			err := controller.adapter.ReconcileWorkloads(ctx)
+			if err != nil {
+				controller.logger.Errorw("unable to reconcile workloads",
+					"error", err,
+				)
+			}
+		}
+	}
+}
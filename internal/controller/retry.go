@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/portainer/k2d/internal/types"
+	"github.com/portainer/k2d/pkg/crypto"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// defaultRetryMaxAttempts is the default maximum number of times an operation is processed before it is
+	// considered as failed
+	defaultRetryMaxAttempts = 5
+
+	// defaultRetryInitialBackoff is the default delay before the first retry of a failed operation.
+	// The delay is doubled after each attempt.
+	defaultRetryInitialBackoff = 2 * time.Second
+
+	// maxRetryBackoff is the maximum delay between two attempts of an operation
+	maxRetryBackoff = 5 * time.Minute
+)
+
+// retryEntry is the persisted representation of an operation waiting to be retried.
+type retryEntry struct {
+	Kind        string            `json:"kind"`
+	RequestID   string            `json:"requestID"`
+	Priority    OperationPriority `json:"priority"`
+	Attempt     int               `json:"attempt"`
+	NextAttempt time.Time         `json:"nextAttempt"`
+	Object      json.RawMessage   `json:"object"`
+}
+
+// retryQueue keeps track of the operations waiting to be retried. There is at most one entry per resource:
+// an operation submitted for a resource supersedes any operation waiting to be retried for the same resource.
+// When a path is specified, the queue is persisted on disk after each change so that the retries
+// survive a restart of k2d.
+type retryQueue struct {
+	mu            sync.Mutex
+	path          string
+	encryptionKey []byte
+	entries       map[string]retryEntry
+}
+
+// newRetryQueue returns a retry queue persisted in the specified file, the secrets are encrypted with
+// the specified key (see encodeOperationObject). The queue is not persisted when the path is empty.
+func newRetryQueue(path string, encryptionKey []byte) *retryQueue {
+	return &retryQueue{
+		path:          path,
+		encryptionKey: encryptionKey,
+		entries:       map[string]retryEntry{},
+	}
+}
+
+// retryQueueKey returns the key identifying the resource associated with an operation in the retry queue.
+func retryQueueKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// add stores an operation in the queue, replacing any operation waiting to be retried for the same resource.
+func (queue *retryQueue) add(op Operation, nextAttempt time.Time) error {
+	kind, namespace, name := describeOperation(op)
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	entry := retryEntry{
+		Kind:        kind,
+		RequestID:   op.RequestID,
+		Priority:    op.Priority,
+		Attempt:     op.attempt,
+		NextAttempt: nextAttempt,
+	}
+
+	var err error
+	if queue.path != "" {
+		entry.Object, err = encodeOperationObject(kind, op.Operation, queue.encryptionKey)
+	}
+
+	// The entry is kept in memory even when its resource cannot be encoded so that the retry is not
+	// considered as superseded (see isCurrent), it is then not persisted
+	queue.entries[retryQueueKey(kind, namespace, name)] = entry
+	if err != nil {
+		return err
+	}
+
+	return queue.persist()
+}
+
+// remove removes the operation waiting to be retried for the resource associated with the specified operation.
+func (queue *retryQueue) remove(op Operation) error {
+	kind, namespace, name := describeOperation(op)
+	key := retryQueueKey(kind, namespace, name)
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	if _, found := queue.entries[key]; !found {
+		return nil
+	}
+
+	delete(queue.entries, key)
+	return queue.persist()
+}
+
+// isCurrent returns true if the specified operation is still the operation waiting to be retried for its resource.
+// It returns false when the operation has been superseded by another operation on the same resource.
+func (queue *retryQueue) isCurrent(op Operation) bool {
+	kind, namespace, name := describeOperation(op)
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	entry, found := queue.entries[retryQueueKey(kind, namespace, name)]
+	return found && entry.RequestID == op.RequestID && entry.Attempt == op.attempt
+}
+
+// load reads the persisted queue and returns the operations waiting to be retried along with the time
+// of their next attempt. Entries that cannot be decoded are discarded.
+func (queue *retryQueue) load() ([]Operation, []time.Time, error) {
+	if queue.path == "" {
+		return nil, nil, nil
+	}
+
+	data, err := os.ReadFile(queue.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("unable to read retry queue file: %w", err)
+	}
+
+	entries := []retryEntry{}
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to unmarshal retry queue: %w", err)
+	}
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	operations := []Operation{}
+	nextAttempts := []time.Time{}
+	for _, entry := range entries {
+		object, err := decodeOperationObject(entry.Kind, entry.Object, queue.encryptionKey)
+		if err != nil {
+			continue
+		}
+
+		op := Operation{
+			Priority:  entry.Priority,
+			Operation: object,
+			RequestID: entry.RequestID,
+			attempt:   entry.Attempt,
+		}
+
+		kind, namespace, name := describeOperation(op)
+		queue.entries[retryQueueKey(kind, namespace, name)] = entry
+
+		operations = append(operations, op)
+		nextAttempts = append(nextAttempts, entry.NextAttempt)
+	}
+
+	return operations, nextAttempts, nil
+}
+
+// persist writes the queue on disk. It must be called with the queue lock held.
+func (queue *retryQueue) persist() error {
+	if queue.path == "" {
+		return nil
+	}
+
+	entries := make([]retryEntry, 0, len(queue.entries))
+	for _, entry := range queue.entries {
+		if entry.Object == nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("unable to marshal retry queue: %w", err)
+	}
+
+	return writeQueueFile(queue.path, data)
+}
+
+// writeQueueFile replaces the content of a queue file. The content is written to a temporary file that is
+// renamed afterwards so that the queue file is never left partially written if k2d is stopped.
+func writeQueueFile(filePath string, data []byte) error {
+	tmpPath := path.Join(path.Dir(filePath), types.QueueTemporaryFilename(path.Base(filePath)))
+	err := os.WriteFile(tmpPath, data, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write queue file %s: %w", path.Base(filePath), err)
+	}
+
+	err = os.Rename(tmpPath, filePath)
+	if err != nil {
+		return fmt.Errorf("unable to replace queue file %s: %w", path.Base(filePath), err)
+	}
+
+	return nil
+}
+
+// encodeOperationObject encodes the resource associated with an operation so that it can be persisted in a queue file.
+// The secrets are encrypted with the specified key so that their data is never written in clear, they cannot be
+// persisted without a key.
+func encodeOperationObject(kind string, object interface{}, encryptionKey []byte) (json.RawMessage, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal operation: %w", err)
+	}
+
+	if kind != "Secret" {
+		return data, nil
+	}
+
+	if len(encryptionKey) == 0 {
+		return nil, errors.New("unable to persist a secret operation without an encryption key")
+	}
+
+	encryptedData, err := crypto.Encrypt(data, encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encrypt operation: %w", err)
+	}
+
+	// The encrypted secret is persisted as a base64 encoded JSON string
+	return json.Marshal(encryptedData)
+}
+
+// decodeOperationObject decodes the resource associated with a persisted operation, see encodeOperationObject.
+func decodeOperationObject(kind string, data []byte, encryptionKey []byte) (interface{}, error) {
+	if kind == "Secret" {
+		encryptedData := []byte{}
+		err := json.Unmarshal(data, &encryptedData)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal encrypted %s: %w", kind, err)
+		}
+
+		if len(encryptionKey) == 0 {
+			return nil, errors.New("unable to decrypt a secret operation without an encryption key")
+		}
+
+		data, err = crypto.Decrypt(encryptedData, encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt %s: %w", kind, err)
+		}
+	}
+
+	var object interface{}
+
+	switch kind {
+	case "Pod":
+		object = &corev1.Pod{}
+	case "Deployment":
+		object = &appsv1.Deployment{}
+	case "ConfigMap":
+		object = &corev1.ConfigMap{}
+	case "Secret":
+		object = &corev1.Secret{}
+	case "Service":
+		object = &corev1.Service{}
+	case "PersistentVolumeClaim":
+		object = &corev1.PersistentVolumeClaim{}
+	default:
+		return nil, fmt.Errorf("unsupported operation kind: %s", kind)
+	}
+
+	err := json.Unmarshal(data, object)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal %s: %w", kind, err)
+	}
+
+	return object, nil
+}
+
+// retryBackoff returns the delay before the next attempt of an operation that was already processed
+// the specified number of times.
+func retryBackoff(initialBackoff time.Duration, attempt int) time.Duration {
+	backoff := initialBackoff
+	for i := 0; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+
+	return backoff
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestScheduleRetrySendsOperationToControlLoop(t *testing.T) {
+	controller := NewOperationController(&OperationControllerOptions{Logger: zap.NewNop().Sugar()})
+
+	op := NewOperation(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}, HighPriorityOperation, "1")
+	op.attempt = 1
+
+	controller.scheduleRetry(op, time.Now())
+
+	select {
+	case retry := <-controller.retries:
+		if retry.RequestID != op.RequestID || retry.attempt != 1 {
+			t.Errorf("unexpected operation retried: %+v", retry)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the operation to be sent back to the control loop")
+	}
+}
+
+func TestProcessOperationSkipsSupersededRetry(t *testing.T) {
+	controller := NewOperationController(&OperationControllerOptions{Logger: zap.NewNop().Sugar()})
+
+	retry := NewOperation(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}, HighPriorityOperation, "1")
+	retry.attempt = 1
+	controller.registry.setPending(retry)
+
+	err := controller.retryQueue.add(retry, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// A newer operation on the same resource replaces the retry
+	err = controller.retryQueue.add(NewOperation(retry.Operation, HighPriorityOperation, "2"), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The controller has no adapter: processing the operation would panic if the retry was not skipped
+	controller.processOperation(retry)
+
+	status, found := controller.registry.Get("1")
+	if !found || status.State != OperationSucceeded {
+		t.Errorf("expected the superseded retry to be completed without error, got %+v", status)
+	}
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxOperationStatuses is the maximum number of operation statuses retained by the registry.
+// The statuses of the oldest operations are discarded first.
+const maxOperationStatuses = 500
+
+// OperationState represents the state of an operation processed by the operation controller.
+type OperationState string
+
+const (
+	// OperationPending is the state of an operation waiting to be processed
+	OperationPending OperationState = "Pending"
+	// OperationSucceeded is the state of an operation that was successfully processed
+	OperationSucceeded OperationState = "Succeeded"
+	// OperationFailed is the state of an operation that could not be processed
+	OperationFailed OperationState = "Failed"
+)
+
+// OperationStatus is the status of an operation, identified by the ID of the API request that submitted it.
+type OperationStatus struct {
+	RequestID   string         `json:"requestID"`
+	Kind        string         `json:"kind"`
+	Namespace   string         `json:"namespace,omitempty"`
+	Name        string         `json:"name"`
+	State       OperationState `json:"state"`
+	Error       string         `json:"error,omitempty"`
+	SubmittedAt time.Time      `json:"submittedAt"`
+	CompletedAt *time.Time     `json:"completedAt,omitempty"`
+}
+
+// OperationStatusRegistry keeps track of the status of the operations processed by the operation controller
+// so that failures can be reported back to the API callers.
+type OperationStatusRegistry struct {
+	mu       sync.RWMutex
+	statuses map[string]*OperationStatus
+	order    []string
+}
+
+// NewOperationStatusRegistry returns an empty OperationStatusRegistry.
+func NewOperationStatusRegistry() *OperationStatusRegistry {
+	return &OperationStatusRegistry{
+		statuses: map[string]*OperationStatus{},
+		order:    []string{},
+	}
+}
+
+// Get returns the status of the operation submitted by the request with the given ID.
+func (registry *OperationStatusRegistry) Get(requestID string) (OperationStatus, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	status, found := registry.statuses[requestID]
+	if !found {
+		return OperationStatus{}, false
+	}
+
+	return *status, true
+}
+
+// List returns the status of all the operations retained by the registry, most recent first.
+func (registry *OperationStatusRegistry) List() []OperationStatus {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	statuses := make([]OperationStatus, 0, len(registry.statuses))
+	for _, status := range registry.statuses {
+		statuses = append(statuses, *status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].SubmittedAt.After(statuses[j].SubmittedAt)
+	})
+
+	return statuses
+}
+
+// setPending registers an operation as waiting to be processed.
+func (registry *OperationStatusRegistry) setPending(op Operation) {
+	if op.RequestID == "" {
+		return
+	}
+
+	kind, namespace, name := describeOperation(op)
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, found := registry.statuses[op.RequestID]; !found {
+		registry.order = append(registry.order, op.RequestID)
+	}
+
+	registry.statuses[op.RequestID] = &OperationStatus{
+		RequestID:   op.RequestID,
+		Kind:        kind,
+		Namespace:   namespace,
+		Name:        name,
+		State:       OperationPending,
+		SubmittedAt: time.Now(),
+	}
+
+	for len(registry.order) > maxOperationStatuses {
+		delete(registry.statuses, registry.order[0])
+		registry.order = registry.order[1:]
+	}
+}
+
+// setCompleted updates the status of an operation once it has been processed.
+func (registry *OperationStatusRegistry) setCompleted(op Operation, err error) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	status, found := registry.statuses[op.RequestID]
+	if !found {
+		return
+	}
+
+	now := time.Now()
+	status.CompletedAt = &now
+	status.State = OperationSucceeded
+	status.Error = ""
+
+	if err != nil {
+		status.State = OperationFailed
+		status.Error = err.Error()
+	}
+}
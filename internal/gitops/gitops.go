@@ -0,0 +1,244 @@
+package gitops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/portainer/k2d/internal/adapter"
+	adaptererr "github.com/portainer/k2d/internal/adapter/errors"
+	"github.com/portainer/k2d/internal/config"
+	"github.com/portainer/k2d/internal/controller"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type (
+	// Agent is a built-in GitOps agent. It periodically pulls a git repository and applies the manifests
+	// of the repository through the operation controller, the same way the resources created through the API are applied.
+	Agent struct {
+		adapter    *adapter.KubeDockerAdapter
+		logger     *zap.SugaredLogger
+		operations chan controller.Operation
+		repository adapter.GitRepositoryOptions
+		// repositoryURL is the URL of the git repository with its credentials redacted, used in the logs
+		repositoryURL string
+		path          string
+		interval      time.Duration
+		// revision is the last revision of the repository applied successfully
+		revision string
+	}
+
+	// AgentOptions represents options that can be used to configure a new Agent
+	AgentOptions struct {
+		// Adapter is the adapter used to synchronize the git repository and to create the namespaces
+		Adapter *adapter.KubeDockerAdapter
+		// Logger is the logger that will be used by the agent
+		Logger *zap.SugaredLogger
+		// Operations is the channel used to send the resources of the manifests to the operation controller
+		Operations chan controller.Operation
+		// RepositoryURL is the URL of the git repository
+		RepositoryURL string
+		// Branch is the branch of the git repository
+		Branch string
+		// Path is the directory of the git repository containing the manifests
+		Path string
+		// Interval is the interval at which the git repository is synchronized
+		Interval time.Duration
+		// AuthSecret is the secret used to authenticate against the git repository (namespace/name), optional
+		AuthSecret string
+		// ImageName is the name of the container image used to run the git commands
+		ImageName string
+	}
+)
+
+// NewAgent creates a new GitOps agent.
+// It returns an error if the manifests path escapes the repository or if the interval is not positive.
+func NewAgent(options *AgentOptions) (*Agent, error) {
+	if options.Interval <= 0 {
+		return nil, fmt.Errorf("invalid gitops interval %s: the interval must be positive", options.Interval)
+	}
+
+	manifestsPath := filepath.Clean(options.Path)
+	if filepath.IsAbs(manifestsPath) || manifestsPath == ".." || strings.HasPrefix(manifestsPath, "../") {
+		return nil, fmt.Errorf("invalid gitops path %s: the path must be relative to the root of the repository", options.Path)
+	}
+
+	repository := adapter.GitRepositoryOptions{
+		URL:       options.RepositoryURL,
+		Branch:    options.Branch,
+		ImageName: options.ImageName,
+	}
+
+	if options.AuthSecret != "" {
+		repository.AuthSecretNamespace = "default"
+		repository.AuthSecretName = options.AuthSecret
+
+		if namespace, name, found := strings.Cut(options.AuthSecret, "/"); found {
+			repository.AuthSecretNamespace = namespace
+			repository.AuthSecretName = name
+		}
+	}
+
+	return &Agent{
+		adapter:       options.Adapter,
+		logger:        options.Logger,
+		operations:    options.Operations,
+		repository:    repository,
+		repositoryURL: config.RedactURLCredentials(options.RepositoryURL),
+		path:          manifestsPath,
+		interval:      options.Interval,
+	}, nil
+}
+
+// Start synchronizes the git repository immediately and then periodically. The loop runs until the context is cancelled.
+//
+// Parameters:
+// ctx - The context used to stop the loop.
+func (agent *Agent) Start(ctx context.Context) {
+	agent.logger.Infow("starting gitops agent",
+		"repository", agent.repositoryURL,
+		"branch", agent.repository.Branch,
+		"path", agent.path,
+		"interval", agent.interval.String(),
+	)
+
+	agent.synchronize(ctx)
+
+	ticker := time.NewTicker(agent.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			agent.synchronize(ctx)
+		}
+	}
+}
+
+// synchronize pulls the git repository and applies its manifests when a new revision is available.
+// A revision is applied again on the next synchronization if it could not be applied entirely.
+func (agent *Agent) synchronize(ctx context.Context) {
+	agent.logger.Debugw("synchronizing gitops repository",
+		"repository", agent.repositoryURL,
+		"branch", agent.repository.Branch,
+	)
+
+	repositoryPath, revision, err := agent.adapter.SyncGitRepository(ctx, agent.repository)
+	if err != nil {
+		agent.logger.Errorw("unable to synchronize gitops repository",
+			"repository", agent.repositoryURL,
+			"error", err,
+		)
+		return
+	}
+
+	if revision == agent.revision {
+		return
+	}
+
+	err = agent.apply(ctx, filepath.Join(repositoryPath, agent.path), revision)
+	if err != nil {
+		agent.logger.Errorw("unable to apply gitops repository manifests",
+			"repository", agent.repositoryURL,
+			"revision", revision,
+			"error", err,
+		)
+		return
+	}
+
+	agent.revision = revision
+}
+
+// apply applies the manifests of a directory. The namespaces are created first, then the other resources are sent
+// to the operation controller using the same priorities as the API, which orders them according to their dependencies.
+// The resources removed from the repository are not deleted.
+func (agent *Agent) apply(ctx context.Context, manifestsPath, revision string) error {
+	manifests, unsupported, err := loadManifests(manifestsPath)
+	if err != nil {
+		return err
+	}
+
+	for _, description := range unsupported {
+		agent.logger.Warnw("skipping unsupported gitops manifest",
+			"manifest", description,
+		)
+	}
+
+	requestID := "gitops-" + revision
+	if len(revision) > 12 {
+		requestID = "gitops-" + revision[:12]
+	}
+
+	failures := 0
+	operations := []controller.Operation{}
+	for _, manifest := range manifests {
+		var priority controller.OperationPriority
+
+		switch resource := manifest.object.(type) {
+		case *corev1.Namespace:
+			err = agent.ensureNamespace(ctx, resource)
+			if err != nil {
+				agent.logger.Errorw("unable to apply gitops manifest",
+					"manifest", manifest.source,
+					"error", err,
+				)
+				failures++
+			}
+			continue
+		case *corev1.ConfigMap, *corev1.Secret, *corev1.PersistentVolumeClaim:
+			priority = controller.HighPriorityOperation
+		case *corev1.Pod, *appsv1.Deployment:
+			priority = controller.MediumPriorityOperation
+		case *corev1.Service:
+			priority = controller.LowPriorityOperation
+		}
+
+		operations = append(operations, controller.NewOperation(manifest.object, priority, requestID))
+	}
+
+	for _, operation := range operations {
+		agent.operations <- operation
+	}
+
+	for _, operation := range operations {
+		err = operation.Wait()
+		if err != nil {
+			agent.logger.Errorw("unable to apply gitops manifest",
+				"request_id", requestID,
+				"error", err,
+			)
+			failures++
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d manifests could not be applied", failures)
+	}
+
+	agent.logger.Infow("gitops repository manifests applied",
+		"repository", agent.repositoryURL,
+		"revision", revision,
+		"manifest_count", len(manifests),
+	)
+
+	return nil
+}
+
+// ensureNamespace creates a namespace declared in the manifests if it does not exist yet.
+func (agent *Agent) ensureNamespace(ctx context.Context, namespace *corev1.Namespace) error {
+	_, err := agent.adapter.GetNamespace(ctx, namespace.Name)
+	if err == nil {
+		return nil
+	} else if !errors.Is(err, adaptererr.ErrResourceNotFound) {
+		return fmt.Errorf("unable to get namespace %s: %w", namespace.Name, err)
+	}
+
+	return agent.adapter.CreateNetworkFromNamespace(ctx, namespace)
+}
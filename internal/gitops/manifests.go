@@ -0,0 +1,196 @@
+package gitops
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// manifest represents a resource decoded from a manifest file of the git repository
+type manifest struct {
+	// source is the path of the manifest file, relative to the manifests directory
+	source string
+	// object is the typed resource (e.g. *corev1.ConfigMap)
+	object runtime.Object
+}
+
+// loadManifests decodes the resources of the YAML and JSON files of a directory and of its sub directories,
+// in lexical order. The hidden files and directories (e.g. .git) are ignored.
+// A file can contain multiple YAML documents as well as List resources.
+// It returns the decoded resources as well as the descriptions of the documents describing an unsupported kind of resource.
+func loadManifests(directory string) ([]manifest, []string, error) {
+	manifests := []manifest{}
+	unsupported := []string{}
+
+	err := filepath.WalkDir(directory, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(entry.Name(), ".") && filePath != directory {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		extension := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (extension != ".yaml" && extension != ".yml" && extension != ".json") {
+			return nil
+		}
+
+		source, err := filepath.Rel(directory, filePath)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return fmt.Errorf("unable to open manifest %s: %w", source, err)
+		}
+		defer file.Close()
+
+		fileManifests, fileUnsupported, err := decodeManifests(file, source)
+		if err != nil {
+			return err
+		}
+
+		manifests = append(manifests, fileManifests...)
+		unsupported = append(unsupported, fileUnsupported...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return manifests, unsupported, nil
+}
+
+// decodeManifests decodes the resources of the YAML documents (or JSON objects) of a manifest file.
+func decodeManifests(r io.Reader, source string) ([]manifest, []string, error) {
+	manifests := []manifest{}
+	unsupported := []string{}
+
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		document := runtime.RawExtension{}
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("unable to decode manifest %s: %w", source, err)
+		}
+
+		if len(document.Raw) == 0 || bytes.Equal(document.Raw, []byte("null")) {
+			continue
+		}
+
+		documentManifests, documentUnsupported, err := decodeManifest(document.Raw, source)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		manifests = append(manifests, documentManifests...)
+		unsupported = append(unsupported, documentUnsupported...)
+	}
+
+	return manifests, unsupported, nil
+}
+
+// decodeManifest decodes a single resource, or the items of a List resource.
+// The last-applied-configuration annotation of the resource is set to its definition, as kubectl apply does.
+// It allows k2d to skip the re-creation of the containers of the workloads that did not change between two synchronizations.
+func decodeManifest(data []byte, source string) ([]manifest, []string, error) {
+	typeMeta := metav1.TypeMeta{}
+	err := json.Unmarshal(data, &typeMeta)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to decode manifest %s: %w", source, err)
+	}
+
+	var object runtime.Object
+	switch typeMeta.GroupVersionKind().String() {
+	case corev1.SchemeGroupVersion.WithKind("List").String():
+		list := corev1.List{}
+		err = json.Unmarshal(data, &list)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to decode manifest %s: %w", source, err)
+		}
+
+		manifests := []manifest{}
+		unsupported := []string{}
+		for _, item := range list.Items {
+			itemManifests, itemUnsupported, err := decodeManifest(item.Raw, source)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			manifests = append(manifests, itemManifests...)
+			unsupported = append(unsupported, itemUnsupported...)
+		}
+
+		return manifests, unsupported, nil
+	case corev1.SchemeGroupVersion.WithKind("Namespace").String():
+		object = &corev1.Namespace{}
+	case corev1.SchemeGroupVersion.WithKind("ConfigMap").String():
+		object = &corev1.ConfigMap{}
+	case corev1.SchemeGroupVersion.WithKind("Secret").String():
+		object = &corev1.Secret{}
+	case corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim").String():
+		object = &corev1.PersistentVolumeClaim{}
+	case corev1.SchemeGroupVersion.WithKind("Pod").String():
+		object = &corev1.Pod{}
+	case corev1.SchemeGroupVersion.WithKind("Service").String():
+		object = &corev1.Service{}
+	case appsv1.SchemeGroupVersion.WithKind("Deployment").String():
+		object = &appsv1.Deployment{}
+	default:
+		return nil, []string{fmt.Sprintf("%s (%s %s)", source, typeMeta.APIVersion, typeMeta.Kind)}, nil
+	}
+
+	err = json.Unmarshal(data, object)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to decode %s manifest %s: %w", typeMeta.Kind, source, err)
+	}
+
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to access the metadata of manifest %s: %w", source, err)
+	}
+
+	if accessor.GetName() == "" {
+		return nil, nil, fmt.Errorf("invalid %s manifest %s: the metadata.name field is required", typeMeta.Kind, source)
+	}
+
+	if _, isNamespace := object.(*corev1.Namespace); !isNamespace && accessor.GetNamespace() == "" {
+		accessor.SetNamespace("default")
+	}
+
+	lastAppliedConfiguration := bytes.Buffer{}
+	err = json.Compact(&lastAppliedConfiguration, data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to encode manifest %s: %w", source, err)
+	}
+
+	annotations := accessor.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations["kubectl.kubernetes.io/last-applied-configuration"] = lastAppliedConfiguration.String()
+	accessor.SetAnnotations(annotations)
+
+	return []manifest{{source: source, object: object}}, nil, nil
+}
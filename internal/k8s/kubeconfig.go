@@ -11,9 +11,31 @@ import (
 // GenerateKubeconfig generates a Kubernetes configuration file (kubeconfig) with the provided CA path, server address, and authentication token.
 // The function returns the generated kubeconfig as a byte slice and an error if any.
 func GenerateKubeconfig(caPath, serverAddr, token string) ([]byte, error) {
-	caData, err := os.ReadFile(caPath)
-	if err != nil {
-		return []byte{}, fmt.Errorf("unable to read TLS CA file: %w", err)
+	return generateKubeconfig(caPath, serverAddr, &api.AuthInfo{
+		Token: token,
+	})
+}
+
+// GenerateCertificateKubeconfig generates a Kubernetes configuration file (kubeconfig) with the provided CA path, server address,
+// and PEM encoded client certificate and key used for authentication.
+// The function returns the generated kubeconfig as a byte slice and an error if any.
+func GenerateCertificateKubeconfig(caPath, serverAddr string, certData, keyData []byte) ([]byte, error) {
+	return generateKubeconfig(caPath, serverAddr, &api.AuthInfo{
+		ClientCertificateData: certData,
+		ClientKeyData:         keyData,
+	})
+}
+
+// generateKubeconfig generates a kubeconfig for the provided server address and authentication information.
+// The CA certificate is not included when the CA path is empty, the clients then rely on the CA certificates of their system.
+func generateKubeconfig(caPath, serverAddr string, authInfo *api.AuthInfo) ([]byte, error) {
+	var caData []byte
+	if caPath != "" {
+		data, err := os.ReadFile(caPath)
+		if err != nil {
+			return []byte{}, fmt.Errorf("unable to read TLS CA file: %w", err)
+		}
+		caData = data
 	}
 
 	kubeconfig := api.Config{
@@ -33,9 +55,7 @@ func GenerateKubeconfig(caPath, serverAddr, token string) ([]byte, error) {
 		},
 		CurrentContext: "k2d",
 		AuthInfos: map[string]*api.AuthInfo{
-			"k2d-root": {
-				Token: token,
-			},
+			"k2d-root": authInfo,
 		},
 	}
 
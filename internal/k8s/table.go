@@ -1,9 +1,16 @@
 package k8s
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/kubernetes/pkg/printers"
 	printersinternal "k8s.io/kubernetes/pkg/printers/internalversion"
 )
@@ -13,8 +20,9 @@ import (
 // is compatible with the tabular output format used by kubectl for human-readable views.
 //
 // Detailed Steps:
-//  1. Utilize the Kubernetes internal print handlers to generate a basic table from the runtime.Object input.
-//     The print handlers populate the table's column definitions and rows based on the resource type.
+//  1. Utilize the Kubernetes internal print handlers to generate a table from the runtime.Object input.
+//     The print handlers populate the table's column definitions and rows based on the resource type,
+//     using the same columns as a real cluster (e.g. READY, STATUS, RESTARTS and AGE for pods).
 //
 // 2. Use meta.ExtractList to convert the runtime.Object into a slice of individual resources.
 //
@@ -23,11 +31,15 @@ import (
 //   - This is essential to iterate over the list and manipulate each resource individually.
 //
 //     3. Iterate over each element in the slice and create a PartialObjectMetadata object from it.
-//     PartialObjectMetadata contains only the resource's metadata, significantly reducing the data size.
+//     PartialObjectMetadata contains only the resource's metadata (name, namespace, creation timestamp, labels,
+//     annotations and owner references), significantly reducing the data size while still allowing kubectl
+//     to display the labels (--show-labels, -L) of the resources.
 //
 // 4. Replace the original Object field in each row of the table with this PartialObjectMetadata.
 //
-//  5. Finally, assign the appropriate TypeMeta to the table to specify it as a metav1.Table of API version
+//  5. Sort the rows by namespace and name, as the resources are listed by the API server.
+//
+//  6. Finally, assign the appropriate TypeMeta to the table to specify it as a metav1.Table of API version
 //     "meta.k8s.io/v1".
 //
 // The function returns:
@@ -46,7 +58,8 @@ func GenerateTable(obj runtime.Object) (*metav1.Table, error) {
 	tableGenerator := printers.NewTableGenerator()
 	printersinternal.AddHandlers(tableGenerator)
 
-	options := printers.GenerateOptions{}
+	// Generate the wide columns as well, kubectl only displays them when using the wide output format
+	options := printers.GenerateOptions{Wide: true}
 	table, err := tableGenerator.GenerateTable(obj, options)
 	if err != nil {
 		return nil, err
@@ -62,21 +75,13 @@ func GenerateTable(obj runtime.Object) (*metav1.Table, error) {
 			gvk := runtimeObj.GetObjectKind().GroupVersionKind()
 
 			if metaObj, ok := runtimeObj.(metav1.Object); ok {
-				partialMetadata := &metav1.PartialObjectMetadata{
-					TypeMeta: metav1.TypeMeta{
-						Kind:       gvk.Kind,
-						APIVersion: gvk.GroupVersion().String(),
-					},
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      metaObj.GetName(),
-						Namespace: metaObj.GetNamespace(),
-					},
-				}
-				table.Rows[i].Object.Object = partialMetadata
+				table.Rows[i].Object.Object = newPartialObjectMetadata(gvk.Kind, gvk.GroupVersion().String(), metaObj)
 			}
 		}
 	}
 
+	sortTableRows(table)
+
 	table.TypeMeta = metav1.TypeMeta{
 		Kind:       "Table",
 		APIVersion: "meta.k8s.io/v1",
@@ -84,3 +89,202 @@ func GenerateTable(obj runtime.Object) (*metav1.Table, error) {
 
 	return table, nil
 }
+
+// MetadataTableColumn is the column computed from the creation timestamp of the resources in a table generated
+// by GenerateMetadataTable
+type MetadataTableColumn string
+
+const (
+	// AgeColumn displays the age of the resources, as done by the API server for the custom resources
+	AgeColumn MetadataTableColumn = "Age"
+	// CreatedAtColumn displays the creation date of the resources, as done by the API server for the resources
+	// that do not define any print handler (e.g. the custom resource definitions)
+	CreatedAtColumn MetadataTableColumn = "Created At"
+)
+
+// GenerateMetadataTable generates a metav1.Table for resources that are not supported by the Kubernetes internal
+// print handlers, such as the custom resources. The table only contains the name of each resource and a column
+// computed from its creation timestamp, as the default table of the API server.
+//
+// Parameters:
+// - kind: The kind of the resources, used in the metadata of each row.
+// - apiVersion: The API version of the resources, used in the metadata of each row.
+// - objects: The resources to display in the table.
+// - column: The column computed from the creation timestamp of the resources.
+//
+// Returns:
+// - A pointer to a metav1.Table.
+func GenerateMetadataTable(kind, apiVersion string, objects []metav1.Object, column MetadataTableColumn) *metav1.Table {
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Table",
+			APIVersion: "meta.k8s.io/v1",
+		},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name", Description: metav1.ObjectMeta{}.SwaggerDoc()["name"]},
+			{Name: string(column), Type: "date", Description: metav1.ObjectMeta{}.SwaggerDoc()["creationTimestamp"]},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	for _, object := range objects {
+		var creationTimestamp interface{}
+		switch column {
+		case AgeColumn:
+			creationTimestamp = translateTimestampSince(object.GetCreationTimestamp())
+		default:
+			creationTimestamp = object.GetCreationTimestamp().Time.UTC().Format(time.RFC3339)
+		}
+
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells:  []interface{}{object.GetName(), creationTimestamp},
+			Object: runtime.RawExtension{Object: newPartialObjectMetadata(kind, apiVersion, object)},
+		})
+	}
+
+	sortTableRows(table)
+
+	return table
+}
+
+// translateTimestampSince returns the elapsed time since the timestamp in a human-readable format,
+// as done by the print handlers of the API server.
+func translateTimestampSince(timestamp metav1.Time) string {
+	if timestamp.IsZero() {
+		return "<unknown>"
+	}
+
+	return duration.HumanDuration(time.Since(timestamp.Time))
+}
+
+// sortTableRows sorts the rows of a table by namespace and name. The order of the rows associated with the same
+// resource is preserved.
+func sortTableRows(table *metav1.Table) {
+	sort.SliceStable(table.Rows, func(i, j int) bool {
+		namespaceI, nameI := tableRowResource(table.Rows[i])
+		namespaceJ, nameJ := tableRowResource(table.Rows[j])
+
+		if namespaceI != namespaceJ {
+			return namespaceI < namespaceJ
+		}
+		return nameI < nameJ
+	})
+}
+
+// tableRowResource returns the namespace and name of the resource associated with a table row.
+func tableRowResource(row metav1.TableRow) (string, string) {
+	if row.Object.Object == nil {
+		return "", ""
+	}
+
+	accessor, err := meta.Accessor(row.Object.Object)
+	if err != nil {
+		return "", ""
+	}
+
+	return accessor.GetNamespace(), accessor.GetName()
+}
+
+// SetTableRowObjects sets the object embedded in each row of a table according to the includeObject option
+// of the request (see metav1.IncludeObjectPolicy):
+//   - None: no object is embedded.
+//   - Metadata: the metadata of the resource is embedded, as generated by GenerateTable. This is the default.
+//   - Object: the complete resource is embedded, taken from the list of resources as returned by the API
+//     (e.g. a corev1.PodList). The rows whose resource is not part of the list keep their metadata.
+//
+// Parameters:
+// - table: The table generated by GenerateTable or GenerateMetadataTable.
+// - policy: The value of the includeObject option.
+// - list: The list of resources, only used with the Object policy.
+//
+// Returns:
+// - An error if the list of resources cannot be encoded.
+func SetTableRowObjects(table *metav1.Table, policy metav1.IncludeObjectPolicy, list interface{}) error {
+	if policy == metav1.IncludeNone {
+		for i := range table.Rows {
+			table.Rows[i].Object = runtime.RawExtension{}
+		}
+		return nil
+	}
+
+	if policy != metav1.IncludeObject {
+		return nil
+	}
+
+	objects, err := EncodeListItems(list)
+	if err != nil {
+		return err
+	}
+
+	for i := range table.Rows {
+		namespace, name := tableRowResource(table.Rows[i])
+		if object, found := objects[namespace+"/"+name]; found {
+			table.Rows[i].Object = runtime.RawExtension{Raw: object}
+		}
+	}
+
+	return nil
+}
+
+// EncodeListItems encodes each item of a list of resources and returns them indexed by namespace/name.
+// The kind and API version of the items are set from the list, as they are omitted from the items of a list.
+func EncodeListItems(list interface{}) (map[string][]byte, error) {
+	data, err := json.Marshal(list)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal list: %w", err)
+	}
+
+	decodedList := struct {
+		Kind       string                   `json:"kind"`
+		APIVersion string                   `json:"apiVersion"`
+		Items      []map[string]interface{} `json:"items"`
+	}{}
+
+	err = json.Unmarshal(data, &decodedList)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal list: %w", err)
+	}
+
+	objects := map[string][]byte{}
+	for _, item := range decodedList.Items {
+		if _, found := item["kind"]; !found && decodedList.Kind != "" {
+			item["kind"] = strings.TrimSuffix(decodedList.Kind, "List")
+		}
+		if _, found := item["apiVersion"]; !found && decodedList.APIVersion != "" {
+			item["apiVersion"] = decodedList.APIVersion
+		}
+
+		metadata, _ := item["metadata"].(map[string]interface{})
+		namespace, _ := metadata["namespace"].(string)
+		name, _ := metadata["name"].(string)
+
+		object, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal %s: %w", name, err)
+		}
+
+		objects[namespace+"/"+name] = object
+	}
+
+	return objects, nil
+}
+
+// newPartialObjectMetadata returns the metadata of a resource, used as the object of the rows of a table.
+func newPartialObjectMetadata(kind, apiVersion string, metaObj metav1.Object) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       kind,
+			APIVersion: apiVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              metaObj.GetName(),
+			Namespace:         metaObj.GetNamespace(),
+			UID:               metaObj.GetUID(),
+			ResourceVersion:   metaObj.GetResourceVersion(),
+			CreationTimestamp: metaObj.GetCreationTimestamp(),
+			Labels:            metaObj.GetLabels(),
+			Annotations:       metaObj.GetAnnotations(),
+			OwnerReferences:   metaObj.GetOwnerReferences(),
+		},
+	}
+}
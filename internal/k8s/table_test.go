@@ -0,0 +1,78 @@
+package k8s
+
+import (
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGenerateMetadataTableSortsRows(t *testing.T) {
+	objects := []metav1.Object{
+		&metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		&metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		&metav1.ObjectMeta{Name: "db", Namespace: "a-b"},
+		&metav1.ObjectMeta{Name: "cache", Namespace: "a"},
+	}
+
+	table := GenerateMetadataTable("Widget", "example.com/v1", objects, AgeColumn)
+
+	expected := []string{"cache", "db", "api", "web"}
+	for i, row := range table.Rows {
+		if row.Cells[0] != expected[i] {
+			t.Errorf("row %d: expected %s, got %v", i, expected[i], row.Cells[0])
+		}
+	}
+
+	if table.Rows[0].Cells[1] != "<unknown>" {
+		t.Errorf("expected the age of a resource without creation timestamp to be unknown, got %v", table.Rows[0].Cells[1])
+	}
+}
+
+func TestSetTableRowObjects(t *testing.T) {
+	newTable := func() *metav1.Table {
+		return GenerateMetadataTable("Pod", "v1", []metav1.Object{
+			&metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		}, AgeColumn)
+	}
+
+	list := corev1.PodList{
+		TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"},
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec:       corev1.PodSpec{NodeName: "k2d"},
+			},
+		},
+	}
+
+	table := newTable()
+	if err := SetTableRowObjects(table, metav1.IncludeObject, list); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	pod := corev1.Pod{}
+	if err := json.Unmarshal(table.Rows[0].Object.Raw, &pod); err != nil {
+		t.Fatalf("unable to decode embedded object: %s", err)
+	}
+	if pod.Kind != "Pod" || pod.APIVersion != "v1" || pod.Spec.NodeName != "k2d" {
+		t.Errorf("expected the complete pod to be embedded, got %+v", pod)
+	}
+
+	table = newTable()
+	if err := SetTableRowObjects(table, metav1.IncludeNone, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if table.Rows[0].Object.Object != nil || table.Rows[0].Object.Raw != nil {
+		t.Error("expected no object to be embedded")
+	}
+
+	table = newTable()
+	if err := SetTableRowObjects(table, metav1.IncludeMetadata, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := table.Rows[0].Object.Object.(*metav1.PartialObjectMetadata); !ok {
+		t.Error("expected the metadata to be embedded")
+	}
+}
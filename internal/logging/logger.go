@@ -26,9 +26,10 @@ func LoggerFromContext(ctx context.Context) *zap.SugaredLogger {
 }
 
 // NewLogger creates and configures a new logger.
-// It takes the desired log level and a flag that specifies if the logs should be in JSON format.
+// It takes the desired log level, a flag that specifies if the logs should be in JSON format and the additional
+// sinks where the logs are written (see SinkOptions).
 // The function returns a SugaredLogger and an error if the configuration fails.
-func NewLogger(logLevel string, json bool) (*zap.SugaredLogger, error) {
+func NewLogger(logLevel string, json bool, sinks SinkOptions) (*zap.SugaredLogger, error) {
 	level, err := parseLogLevel(logLevel)
 	if err != nil {
 		return nil, err
@@ -41,6 +42,17 @@ func NewLogger(logLevel string, json bool) (*zap.SugaredLogger, error) {
 		return nil, err
 	}
 
+	sinkCores, err := buildSinkCores(config, sinks)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sinkCores) != 0 {
+		logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(append([]zapcore.Core{core}, sinkCores...)...)
+		}))
+	}
+
 	return setGlobalLogger(logger), nil
 }
 
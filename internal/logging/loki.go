@@ -0,0 +1,217 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// lokiQueueSize is the maximum number of log entries waiting to be pushed to Loki.
+	// Entries are dropped when the queue is full so that k2d is never blocked by a slow Loki endpoint.
+	lokiQueueSize = 1024
+	// lokiBatchSize is the maximum number of log entries pushed in a single request
+	lokiBatchSize = 100
+	// lokiFlushInterval is the maximum delay before a log entry is pushed
+	lokiFlushInterval = time.Second
+	// lokiPushTimeout is the timeout applied to the push requests
+	lokiPushTimeout = 5 * time.Second
+)
+
+type (
+	// lokiEntry is a log entry waiting to be pushed to Loki
+	lokiEntry struct {
+		timestamp time.Time
+		level     zapcore.Level
+		line      string
+	}
+
+	// lokiPusher pushes log entries to the push API of Loki (/loki/api/v1/push) in batches, from a background goroutine.
+	lokiPusher struct {
+		url     string
+		labels  map[string]string
+		client  *http.Client
+		entries chan lokiEntry
+		flush   chan chan struct{}
+	}
+
+	// lokiStream is a stream of the push API of Loki
+	lokiStream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+
+	// lokiCore is a zapcore.Core queuing the log entries to be pushed to Loki.
+	lokiCore struct {
+		zapcore.LevelEnabler
+		encoder zapcore.Encoder
+		pusher  *lokiPusher
+	}
+)
+
+// newLokiCore returns a core pushing the log entries to the specified Loki push URL
+// (e.g. http://loki:3100/loki/api/v1/push). The level of each entry is added to the specified labels.
+func newLokiCore(url string, labels map[string]string, encoder zapcore.Encoder, enabler zapcore.LevelEnabler) *lokiCore {
+	pusher := &lokiPusher{
+		url:     url,
+		labels:  labels,
+		client:  &http.Client{Timeout: lokiPushTimeout},
+		entries: make(chan lokiEntry, lokiQueueSize),
+		flush:   make(chan chan struct{}),
+	}
+
+	go pusher.run()
+
+	return &lokiCore{
+		LevelEnabler: enabler,
+		encoder:      encoder,
+		pusher:       pusher,
+	}
+}
+
+func (c *lokiCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+
+	return &lokiCore{
+		LevelEnabler: c.LevelEnabler,
+		encoder:      encoder,
+		pusher:       c.pusher,
+	}
+}
+
+func (c *lokiCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *lokiCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buffer, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	defer buffer.Free()
+
+	select {
+	case c.pusher.entries <- lokiEntry{timestamp: entry.Time, level: entry.Level, line: strings.TrimSuffix(buffer.String(), "\n")}:
+	default:
+	}
+
+	return nil
+}
+
+// Sync pushes the queued log entries.
+func (c *lokiCore) Sync() error {
+	done := make(chan struct{})
+
+	select {
+	case c.pusher.flush <- done:
+	case <-time.After(lokiPushTimeout):
+		return nil
+	}
+
+	<-done
+	return nil
+}
+
+func (p *lokiPusher) run() {
+	ticker := time.NewTicker(lokiFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]lokiEntry, 0, lokiBatchSize)
+
+	for {
+		select {
+		case entry := <-p.entries:
+			batch = append(batch, entry)
+			if len(batch) < lokiBatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case done := <-p.flush:
+			for len(p.entries) > 0 {
+				batch = append(batch, <-p.entries)
+			}
+			p.pushBatch(batch)
+			batch = batch[:0]
+			close(done)
+			continue
+		}
+
+		p.pushBatch(batch)
+		batch = batch[:0]
+	}
+}
+
+// pushBatch pushes a batch of log entries. The errors are reported on the standard error output,
+// they cannot be logged as they would be pushed to Loki as well.
+func (p *lokiPusher) pushBatch(batch []lokiEntry) {
+	if len(batch) == 0 {
+		return
+	}
+
+	err := p.push(batch)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to push %d log entries to loki: %s\n", len(batch), err)
+	}
+}
+
+func (p *lokiPusher) push(batch []lokiEntry) error {
+	data, err := json.Marshal(map[string][]lokiStream{"streams": p.buildStreams(batch)})
+	if err != nil {
+		return fmt.Errorf("unable to marshal log entries: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, p.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("unable to create push request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("unable to send push request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("loki returned status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// buildStreams groups the log entries of a batch into one stream per level.
+func (p *lokiPusher) buildStreams(batch []lokiEntry) []lokiStream {
+	streams := []lokiStream{}
+	streamIndexes := map[zapcore.Level]int{}
+
+	for _, entry := range batch {
+		index, exists := streamIndexes[entry.level]
+		if !exists {
+			labels := map[string]string{"level": entry.level.String()}
+			for key, value := range p.labels {
+				labels[key] = value
+			}
+
+			streams = append(streams, lokiStream{Stream: labels})
+			index = len(streams) - 1
+			streamIndexes[entry.level] = index
+		}
+
+		streams[index].Values = append(streams[index].Values, [2]string{strconv.FormatInt(entry.timestamp.UnixNano(), 10), entry.line})
+	}
+
+	return streams
+}
@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// rotatingFile is a zapcore.WriteSyncer writing to a file that is rotated when it reaches a maximum size.
+// The rotated files are renamed using a numeric suffix (k2d.log.1 being the most recent one) and only
+// the specified number of rotated files is retained.
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// newRotatingFile opens (or creates) the file at the specified path, creating its directory if needed.
+func newRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create log directory: %w", err)
+	}
+
+	rf := &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	err = rf.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+func (rf *rotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("unable to stat log file: %w", err)
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+
+	return nil
+}
+
+// Write writes an entry to the file, rotating the file beforehand when the entry would exceed the maximum size.
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		err := rf.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+
+	return n, err
+}
+
+// rotate closes the current file, shifts the rotated files and opens a new file.
+// The oldest rotated file is removed when the maximum number of rotated files is reached.
+func (rf *rotatingFile) rotate() error {
+	err := rf.file.Close()
+	if err != nil {
+		return fmt.Errorf("unable to close log file: %w", err)
+	}
+
+	if rf.maxBackups <= 0 {
+		err = os.Remove(rf.path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove log file: %w", err)
+		}
+		return rf.open()
+	}
+
+	os.Remove(rf.backupPath(rf.maxBackups))
+	for i := rf.maxBackups - 1; i >= 1; i-- {
+		err = os.Rename(rf.backupPath(i), rf.backupPath(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to rotate log file: %w", err)
+		}
+	}
+
+	err = os.Rename(rf.path, rf.backupPath(1))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to rotate log file: %w", err)
+	}
+
+	return rf.open()
+}
+
+func (rf *rotatingFile) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", rf.path, index)
+}
+
+// Sync commits the content of the file to the disk.
+func (rf *rotatingFile) Sync() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	return rf.file.Sync()
+}
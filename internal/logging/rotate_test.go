@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "k2d.log")
+
+	file, err := newRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("unable to create rotating file: %s", err)
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(line))
+		if err != nil {
+			t.Fatalf("unable to write to rotating file: %s", err)
+		}
+	}
+
+	expected := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+
+	for filePath, content := range expected {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("unable to read %s: %s", filePath, err)
+		}
+
+		if string(data) != content {
+			t.Errorf("expected %s to contain %q, got %q", filePath, content, data)
+		}
+	}
+
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("expected only 2 rotated files to be retained")
+	}
+}
+
+func TestLokiPusherBuildStreams(t *testing.T) {
+	pusher := &lokiPusher{labels: map[string]string{"job": "k2d"}}
+
+	streams := pusher.buildStreams([]lokiEntry{
+		{level: zapcore.DebugLevel, line: "debug"},
+		{level: zapcore.InfoLevel, line: "info"},
+		{level: zapcore.InfoLevel, line: "info again"},
+	})
+
+	if len(streams) != 2 {
+		t.Fatalf("expected one stream per level, got %d", len(streams))
+	}
+
+	if streams[1].Stream["level"] != "info" || streams[1].Stream["job"] != "k2d" || len(streams[1].Values) != 2 {
+		t.Errorf("unexpected info stream: %+v", streams[1])
+	}
+
+	if !strings.Contains(streams[0].Values[0][1], "debug") {
+		t.Errorf("unexpected debug stream: %+v", streams[0])
+	}
+}
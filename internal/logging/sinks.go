@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SinkOptions defines the additional destinations of the logs of k2d. The logs are always written to the standard
+// error output, each sink being optional.
+type SinkOptions struct {
+	// FilePath is the path of the file where the logs are written
+	FilePath string
+	// FileMaxSize is the maximum size of the file in bytes before it is rotated, the file is never rotated when it is 0
+	FileMaxSize int64
+	// FileMaxBackups is the number of rotated files retained
+	FileMaxBackups int
+	// SyslogAddress is the address of the syslog daemon: local, or a URL using the udp or tcp scheme
+	SyslogAddress string
+	// LokiURL is the URL of the push API of Loki
+	LokiURL string
+	// LokiLabels are the labels of the log streams pushed to Loki
+	LokiLabels map[string]string
+}
+
+// buildSinkCores returns the cores writing the logs to the configured sinks, using the encoder of the logger configuration.
+func buildSinkCores(config zap.Config, sinks SinkOptions) ([]zapcore.Core, error) {
+	newEncoder := func() zapcore.Encoder {
+		if config.Encoding == "json" {
+			return zapcore.NewJSONEncoder(config.EncoderConfig)
+		}
+		return zapcore.NewConsoleEncoder(config.EncoderConfig)
+	}
+
+	cores := []zapcore.Core{}
+
+	if sinks.FilePath != "" {
+		file, err := newRotatingFile(sinks.FilePath, sinks.FileMaxSize, sinks.FileMaxBackups)
+		if err != nil {
+			return nil, err
+		}
+
+		cores = append(cores, zapcore.NewCore(newEncoder(), file, config.Level))
+	}
+
+	if sinks.SyslogAddress != "" {
+		core, err := newSyslogCore(sinks.SyslogAddress, newEncoder(), config.Level)
+		if err != nil {
+			return nil, err
+		}
+
+		cores = append(cores, core)
+	}
+
+	if sinks.LokiURL != "" {
+		cores = append(cores, newLokiCore(sinks.LokiURL, sinks.LokiLabels, newEncoder(), config.Level))
+	}
+
+	return cores, nil
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"fmt"
+	"log/syslog"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syslogTag is the tag of the messages sent to syslog
+const syslogTag = "k2d"
+
+// syslogCore is a zapcore.Core sending the log entries to syslog, using the syslog severity matching the level of each entry.
+// journald collects the messages sent to the local syslog socket.
+type syslogCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	writer  *syslog.Writer
+}
+
+// newSyslogCore connects to the syslog daemon at the specified address: local to use the local syslog socket (/dev/log),
+// or a URL using the udp or tcp scheme (e.g. udp://192.168.1.10:514) to use a remote syslog daemon.
+func newSyslogCore(address string, encoder zapcore.Encoder, enabler zapcore.LevelEnabler) (*syslogCore, error) {
+	network, raddr := "", ""
+
+	if address != "local" {
+		syslogURL, err := url.Parse(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid syslog address %s: %w", address, err)
+		}
+
+		if syslogURL.Scheme != "udp" && syslogURL.Scheme != "tcp" {
+			return nil, fmt.Errorf("invalid syslog address %s: the scheme must be udp or tcp", address)
+		}
+
+		network, raddr = syslogURL.Scheme, syslogURL.Host
+	}
+
+	writer, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_DAEMON, syslogTag)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to syslog: %w", err)
+	}
+
+	return &syslogCore{
+		LevelEnabler: enabler,
+		encoder:      encoder,
+		writer:       writer,
+	}, nil
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+
+	return &syslogCore{
+		LevelEnabler: c.LevelEnabler,
+		encoder:      encoder,
+		writer:       c.writer,
+	}
+}
+
+func (c *syslogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *syslogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buffer, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	defer buffer.Free()
+
+	message := strings.TrimSuffix(buffer.String(), "\n")
+
+	switch entry.Level {
+	case zapcore.DebugLevel:
+		return c.writer.Debug(message)
+	case zapcore.InfoLevel:
+		return c.writer.Info(message)
+	case zapcore.WarnLevel:
+		return c.writer.Warning(message)
+	case zapcore.ErrorLevel:
+		return c.writer.Err(message)
+	default:
+		return c.writer.Crit(message)
+	}
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/audit"
+	"github.com/portainer/k2d/internal/token"
+	"github.com/portainer/k2d/internal/types"
+)
+
+// auditedReadPaths are the paths of the read requests that are audited although they are not sensitive requests
+// (see isSensitiveRequest), as they return credentials or the whole state of k2d.
+var auditedReadPaths = map[string]struct{}{
+	"/k2d/backup":     {},
+	"/k2d/kubeconfig": {},
+}
+
+// AuditRequests returns a restful.FilterFunction that records the requests in the audit log once they have been processed.
+// The mutating requests (any request that is not a GET, HEAD or OPTIONS request) are always audited. The read requests are
+// audited when they are sensitive (exec, attach, portforward, secrets, unredacted export, archives, see isSensitiveRequest)
+// or when they return credentials (backup, kubeconfig).
+// It must be registered before the authentication filter so that rejected requests are audited as well.
+func AuditRequests(auditLogger *audit.Logger) restful.FilterFunction {
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		if !isAuditedRequest(req) {
+			chain.ProcessFilter(req, resp)
+			return
+		}
+
+		chain.ProcessFilter(req, resp)
+
+		event := audit.Event{
+			Timestamp:  time.Now().UTC(),
+			RequestID:  req.Request.Header.Get(types.RequestIDHeader),
+			User:       auditUser(req),
+			Method:     req.Request.Method,
+			Path:       req.Request.URL.Path,
+			StatusCode: resp.StatusCode(),
+			Outcome:    audit.OutcomeSuccess,
+			SourceIP:   req.Request.RemoteAddr,
+		}
+
+		if role, ok := req.Attribute(RoleAttribute).(token.Role); ok {
+			event.Role = string(role)
+		}
+
+		if event.StatusCode >= http.StatusBadRequest {
+			event.Outcome = audit.OutcomeFailure
+		}
+
+		setAuditResourceInfo(&event)
+		auditLogger.Log(event)
+	}
+}
+
+// isAuditedRequest returns true if a request must be recorded in the audit log.
+func isAuditedRequest(req *restful.Request) bool {
+	switch req.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return true
+	}
+
+	if _, audited := auditedReadPaths[strings.TrimSuffix(req.Request.URL.Path, "/")]; audited {
+		return true
+	}
+
+	return isSensitiveRequest(req)
+}
+
+// auditUser identifies the client of a request without exposing its credentials.
+// It returns the common name of the client certificate when the client was authenticated using a certificate,
+// or a prefix of the SHA-256 hash of the bearer token.
+func auditUser(req *restful.Request) string {
+	if req.Request.TLS != nil && len(req.Request.TLS.VerifiedChains) > 0 && len(req.Request.TLS.VerifiedChains[0]) > 0 {
+		return "cert:" + req.Request.TLS.VerifiedChains[0][0].Subject.CommonName
+	}
+
+	secret := strings.TrimPrefix(req.HeaderParameter("Authorization"), "Bearer ")
+	if secret == "" {
+		return "anonymous"
+	}
+
+	hash := sha256.Sum256([]byte(secret))
+	return "token:" + hex.EncodeToString(hash[:])[:16]
+}
+
+// setAuditResourceInfo parses the path of a Kubernetes API request and sets the group, version, resource,
+// subresource, namespace, name and verb of the audit event.
+// e.g. /apis/apps/v1/namespaces/default/deployments/nginx/scale or /api/v1/namespaces/default
+func setAuditResourceInfo(event *audit.Event) {
+	event.Verb = strings.ToLower(event.Method)
+
+	segments := strings.Split(strings.Trim(event.Path, "/"), "/")
+
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		event.APIVersion = segments[1]
+		segments = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		event.APIGroup = segments[1]
+		event.APIVersion = segments[2]
+		segments = segments[3:]
+	default:
+		return
+	}
+
+	if len(segments) >= 2 && segments[0] == "namespaces" {
+		event.Namespace = segments[1]
+		if len(segments) == 2 {
+			event.Resource = "namespaces"
+			event.Name = segments[1]
+			segments = nil
+		} else {
+			segments = segments[2:]
+		}
+	}
+
+	if len(segments) >= 1 {
+		event.Resource = segments[0]
+	}
+	if len(segments) >= 2 {
+		event.Name = segments[1]
+	}
+	if len(segments) >= 3 {
+		event.Subresource = segments[2]
+	}
+
+	switch event.Method {
+	case http.MethodPost:
+		event.Verb = "create"
+	case http.MethodPut:
+		event.Verb = "update"
+	case http.MethodPatch:
+		event.Verb = "patch"
+	case http.MethodDelete:
+		event.Verb = "delete"
+		if event.Name == "" {
+			event.Verb = "deletecollection"
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+)
+
+func TestIsAuditedRequest(t *testing.T) {
+	tests := []struct {
+		method  string
+		target  string
+		audited bool
+	}{
+		{method: http.MethodPost, target: "/api/v1/namespaces/default/pods", audited: true},
+		{method: http.MethodDelete, target: "/api/v1/namespaces/default/pods/web", audited: true},
+		{method: http.MethodGet, target: "/api/v1/namespaces/default/pods", audited: false},
+		{method: http.MethodGet, target: "/k2d/export", audited: false},
+		{method: http.MethodGet, target: "/api/v1/namespaces/default/pods/web/exec?command=sh", audited: true},
+		{method: http.MethodGet, target: "/api/v1/namespaces/default/pods/web/attach", audited: true},
+		{method: http.MethodGet, target: "/api/v1/namespaces/k2d/secrets/k2d-serviceaccount", audited: true},
+		{method: http.MethodGet, target: "/k2d/export?redactSecrets=false", audited: true},
+		{method: http.MethodGet, target: "/k2d/backup", audited: true},
+		{method: http.MethodGet, target: "/k2d/kubeconfig", audited: true},
+	}
+
+	for _, test := range tests {
+		req := restful.NewRequest(httptest.NewRequest(test.method, test.target, nil))
+
+		if audited := isAuditedRequest(req); audited != test.audited {
+			t.Errorf("%s %s: expected audited to be %t, got %t", test.method, test.target, test.audited, audited)
+		}
+	}
+}
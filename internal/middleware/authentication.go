@@ -1,27 +1,147 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	restful "github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	"github.com/portainer/k2d/internal/token"
 )
 
-// CheckAuthenticationHeader returns a restful.FilterFunction that checks the Authorization header of a request.
-// The header should contain a "Bearer" token, which is compared with the given encodedSecret parameter.
-// If the token does not match the encodedSecret, the filter responds with an HTTP 401 Unauthorized status code and stops processing the request.
-// If the token matches, the filter calls the next filter in the chain.
-func CheckAuthenticationHeader(encodedSecret string) restful.FilterFunction {
+// unauthenticatedPaths are the paths that can be requested without authentication so that
+// health checks can be performed by process supervisors (systemd, watchdogs...).
+var unauthenticatedPaths = map[string]struct{}{
+	"/healthz": {},
+	"/livez":   {},
+	"/readyz":  {},
+}
+
+// unauthenticatedPathPrefix is the prefix of the static files of the web dashboard. They do not contain any data,
+// the dashboard retrieves the resources from the API using a token provided by the user.
+const unauthenticatedPathPrefix = "/k2d/ui"
+
+// RoleAttribute is the name of the request attribute containing the token.Role of the authenticated client.
+const RoleAttribute = "k2d-role"
+
+// CheckAuthenticationHeader returns a restful.FilterFunction that authenticates a request.
+// When the client presented a certificate signed by the k2d CA (verified during the TLS handshake), the role
+// is retrieved from the organization of the certificate.
+// Otherwise, the Authorization header should contain a "Bearer" token, which is looked up in the given tokens registry.
+// If the client cannot be authenticated, the filter responds with an HTTP 401 Unauthorized Status object and stops processing the request.
+// If the token is associated with the read-only role and the request is not a read request or is a sensitive request, the filter responds with
+// an HTTP 403 Forbidden Status object and stops processing the request.
+// The health check endpoints (/healthz, /livez and /readyz) and the web dashboard (/k2d/ui) do not require authentication.
+// Otherwise, the role is stored in the RoleAttribute attribute of the request and the filter calls the next filter in the chain.
+func CheckAuthenticationHeader(tokens *token.Registry) restful.FilterFunction {
 	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
-		authorizationHeader := req.HeaderParameter("Authorization")
-		secret := strings.TrimPrefix(authorizationHeader, "Bearer ")
+		if isUnauthenticatedPath(req.Request.URL.Path) && req.Request.Method == http.MethodGet {
+			chain.ProcessFilter(req, resp)
+			return
+		}
+
+		role, found := roleFromClientCertificate(req, tokens)
+		if !found {
+			authorizationHeader := req.HeaderParameter("Authorization")
+			secret := strings.TrimPrefix(authorizationHeader, "Bearer ")
+
+			role, found = tokens.Role(secret)
+		}
+
+		if !found {
+			resp.WriteHeaderAndEntity(http.StatusUnauthorized, utils.NewStatusFromError(http.StatusUnauthorized, errors.New("invalid secret")))
+			return
+		}
 
-		if secret != encodedSecret {
-			resp.WriteHeader(http.StatusUnauthorized)
-			resp.Write([]byte("invalid secret\n"))
+		if role == token.ReadOnlyRole && !isReadOnlyRequest(req) {
+			resp.WriteHeaderAndEntity(http.StatusForbidden, utils.NewStatusFromError(http.StatusForbidden, errors.New("the token used is read-only and cannot be used to perform this operation")))
 			return
 		}
 
+		req.SetAttribute(RoleAttribute, role)
 		chain.ProcessFilter(req, resp)
 	}
 }
+
+// isUnauthenticatedPath returns true if the path can be requested without authentication.
+func isUnauthenticatedPath(path string) bool {
+	if _, unauthenticated := unauthenticatedPaths[path]; unauthenticated {
+		return true
+	}
+
+	return path == unauthenticatedPathPrefix || strings.HasPrefix(path, unauthenticatedPathPrefix+"/")
+}
+
+// roleFromClientCertificate returns the role of a client authenticated using a certificate.
+// The certificate chain is verified by the TLS server, only the revocation of the certificate (see token.Registry.RevokeCertificates)
+// and the role stored in the organization of the certificate are checked.
+func roleFromClientCertificate(req *restful.Request, tokens *token.Registry) (token.Role, bool) {
+	if req.Request.TLS == nil || len(req.Request.TLS.VerifiedChains) == 0 || len(req.Request.TLS.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+
+	if tokens.CertificateRevoked(req.Request.TLS.VerifiedChains[0][0].NotBefore) {
+		return "", false
+	}
+
+	for _, organization := range req.Request.TLS.VerifiedChains[0][0].Subject.Organization {
+		role, err := token.ParseRole(organization)
+		if err == nil {
+			return role, true
+		}
+	}
+
+	return "", false
+}
+
+// isReadOnlyRequest returns true if the request does not modify any resource and can be performed with the read-only role.
+// Only GET, HEAD and OPTIONS requests are considered read-only, with the exception of the sensitive requests (see isSensitiveRequest)
+// which give access to the containers or to the secrets, and could be used to obtain an admin token.
+func isReadOnlyRequest(req *restful.Request) bool {
+	switch req.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return false
+	}
+
+	return !isSensitiveRequest(req)
+}
+
+// isSensitiveRequest returns true if the request gives access to the containers or to secret values:
+//   - The interactive subresources (exec, attach and portforward), even when they are requested using GET (websockets).
+//   - The secrets endpoints, the k2d namespace contains the service account secret which stores the admin token.
+//   - The export of the resources when the secrets are not redacted.
+//   - The snapshot and checkpoint archives, which contain the data of the volumes and the memory of the containers.
+func isSensitiveRequest(req *restful.Request) bool {
+	path := strings.TrimSuffix(req.Request.URL.Path, "/")
+
+	for _, subresource := range []string{"/exec", "/attach", "/portforward"} {
+		if strings.HasSuffix(path, subresource) {
+			return true
+		}
+	}
+
+	if strings.HasPrefix(path, "/api/") {
+		segments := strings.Split(path, "/")
+		for i, segment := range segments {
+			if segment == "secrets" && segments[i-1] != "namespaces" {
+				return true
+			}
+		}
+	}
+
+	if path == "/k2d/export" {
+		redactSecrets, err := strconv.ParseBool(req.Request.URL.Query().Get("redactSecrets"))
+		if err == nil && !redactSecrets {
+			return true
+		}
+	}
+
+	if strings.HasSuffix(path, "/archive") && (strings.HasPrefix(path, "/k2d/snapshots/") || strings.HasPrefix(path, "/k2d/checkpoints/")) {
+		return true
+	}
+
+	return false
+}
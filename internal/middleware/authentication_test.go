@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/token"
+)
+
+func TestCheckAuthenticationHeaderReadOnlyRole(t *testing.T) {
+	tokens := token.NewRegistry(map[string]token.Role{
+		"admin-token":     token.AdminRole,
+		"read-only-token": token.ReadOnlyRole,
+	})
+
+	tests := []struct {
+		method   string
+		target   string
+		expected int
+	}{
+		{method: http.MethodGet, target: "/api/v1/namespaces/default/pods", expected: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/namespaces/secrets/pods", expected: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/namespaces/default/configmaps/web", expected: http.StatusOK},
+		{method: http.MethodGet, target: "/k2d/export", expected: http.StatusOK},
+		{method: http.MethodGet, target: "/k2d/export?redactSecrets=true", expected: http.StatusOK},
+		{method: http.MethodGet, target: "/k2d/snapshots/data", expected: http.StatusOK},
+		{method: http.MethodDelete, target: "/api/v1/namespaces/default/pods/web", expected: http.StatusForbidden},
+		{method: http.MethodGet, target: "/api/v1/namespaces/k2d/secrets/k2d-serviceaccount", expected: http.StatusForbidden},
+		{method: http.MethodGet, target: "/api/v1/namespaces/default/secrets", expected: http.StatusForbidden},
+		{method: http.MethodGet, target: "/api/v1/secrets", expected: http.StatusForbidden},
+		{method: http.MethodGet, target: "/api/v1/watch/namespaces/k2d/secrets", expected: http.StatusForbidden},
+		{method: http.MethodGet, target: "/api/v1/namespaces/default/pods/web/exec", expected: http.StatusForbidden},
+		{method: http.MethodGet, target: "/k2d/export?redactSecrets=false", expected: http.StatusForbidden},
+		{method: http.MethodGet, target: "/k2d/snapshots/data/archive", expected: http.StatusForbidden},
+		{method: http.MethodGet, target: "/k2d/checkpoints/web/archive", expected: http.StatusForbidden},
+	}
+
+	filter := CheckAuthenticationHeader(tokens)
+
+	for _, test := range tests {
+		t.Run(test.method+" "+test.target, func(t *testing.T) {
+			for _, secret := range []string{"read-only-token", "admin-token"} {
+				httpRequest := httptest.NewRequest(test.method, test.target, nil)
+				httpRequest.Header.Set("Authorization", "Bearer "+secret)
+
+				recorder := httptest.NewRecorder()
+				response := restful.NewResponse(recorder)
+				response.SetRequestAccepts(restful.MIME_JSON)
+
+				chain := &restful.FilterChain{Target: func(req *restful.Request, resp *restful.Response) {
+					resp.WriteHeader(http.StatusOK)
+				}}
+
+				filter(restful.NewRequest(httpRequest), response, chain)
+
+				expected := test.expected
+				if secret == "admin-token" {
+					expected = http.StatusOK
+				}
+
+				if recorder.Code != expected {
+					t.Errorf("expected status %d using the %s, got %d", expected, secret, recorder.Code)
+				}
+			}
+		})
+	}
+}
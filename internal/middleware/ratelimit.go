@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/portainer/k2d/internal/api/utils"
+	"golang.org/x/time/rate"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// inflightRetryAfterSeconds is the delay suggested to the clients when the max-inflight limit is reached
+	inflightRetryAfterSeconds = 1
+
+	// rateLimiterIdleTTL is the duration after which the rate limiter of an idle client is discarded
+	rateLimiterIdleTTL = 10 * time.Minute
+)
+
+// RateLimitOptions defines the limits applied by the LimitRequests filter.
+// Read-only requests (GET, HEAD and OPTIONS) and mutating requests are limited separately.
+// A value of 0 disables the associated limit.
+type RateLimitOptions struct {
+	// MaxReadOnlyInflight is the maximum number of read-only requests processed at the same time
+	MaxReadOnlyInflight int
+	// MaxMutatingInflight is the maximum number of mutating requests processed at the same time
+	MaxMutatingInflight int
+	// ReadOnlyRate is the number of read-only requests per second allowed for a single client
+	ReadOnlyRate float64
+	// MutatingRate is the number of mutating requests per second allowed for a single client
+	MutatingRate float64
+	// Burst is the maximum number of requests a single client can send in a burst, for each type of request
+	Burst int
+}
+
+// LimitRequests returns a restful.FilterFunction protecting the Docker daemon from bursts of requests.
+// Requests are classified as read-only or mutating and each class is subject to:
+//  1. A per-client rate limit, clients being identified by their IP address.
+//  2. A maximum number of requests processed at the same time (max-inflight). Long-running requests
+//     (watch, follow logs, exec, attach, portforward and the k2d stream) are not counted as they can stay open indefinitely.
+//
+// When a limit is reached, the filter responds with an HTTP 429 TooManyRequests Status object and a Retry-After header.
+func LimitRequests(opts RateLimitOptions) restful.FilterFunction {
+	readOnly := newRequestLimiter(opts.MaxReadOnlyInflight, opts.ReadOnlyRate, opts.Burst)
+	mutating := newRequestLimiter(opts.MaxMutatingInflight, opts.MutatingRate, opts.Burst)
+
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		limiter := mutating
+		if isReadRequest(req) {
+			limiter = readOnly
+		}
+
+		delay := limiter.reserve(clientIP(req.Request))
+		if delay > 0 {
+			writeTooManyRequests(req, resp, "client rate limit exceeded, please try again later", int(math.Ceil(delay.Seconds())))
+			return
+		}
+
+		if !isLongRunningRequest(req) {
+			if !limiter.acquire() {
+				writeTooManyRequests(req, resp, "too many requests are being processed, please try again later", inflightRetryAfterSeconds)
+				return
+			}
+			defer limiter.release()
+		}
+
+		chain.ProcessFilter(req, resp)
+	}
+}
+
+// isReadRequest returns true if the method of the request does not modify any resource.
+// Unlike isReadOnlyRequest, which is used for authorization, the sensitive requests are not excluded
+// as they do not put more load on the Docker daemon than the other read requests.
+func isReadRequest(req *restful.Request) bool {
+	switch req.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func writeTooManyRequests(req *restful.Request, resp *restful.Response, message string, retryAfterSeconds int) {
+	resp.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+	utils.HttpError(req, resp, http.StatusTooManyRequests, apierr.NewTooManyRequests(message, retryAfterSeconds))
+}
+
+// isLongRunningRequest returns true for requests that can stay open indefinitely.
+func isLongRunningRequest(req *restful.Request) bool {
+	if req.QueryParameter("watch") == "true" || req.QueryParameter("watch") == "1" || req.QueryParameter("follow") == "true" {
+		return true
+	}
+
+	path := strings.TrimSuffix(req.Request.URL.Path, "/")
+	if path == "/k2d/stream" {
+		return true
+	}
+
+	for _, subresource := range []string{"/exec", "/attach", "/portforward"} {
+		if strings.HasSuffix(path, subresource) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// requestLimiter applies the max-inflight and per-client rate limits of a class of requests.
+type requestLimiter struct {
+	inflight chan struct{}
+	rate     rate.Limit
+	burst    int
+
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	lastPrune time.Time
+}
+
+func newRequestLimiter(maxInflight int, requestsPerSecond float64, burst int) *requestLimiter {
+	limiter := &requestLimiter{
+		rate:      rate.Limit(requestsPerSecond),
+		burst:     burst,
+		clients:   map[string]*clientLimiter{},
+		lastPrune: time.Now(),
+	}
+
+	if maxInflight > 0 {
+		limiter.inflight = make(chan struct{}, maxInflight)
+	}
+
+	if limiter.burst < 1 {
+		limiter.burst = 1
+	}
+
+	return limiter
+}
+
+// acquire reserves an inflight slot. It returns false if all the slots are in use.
+func (l *requestLimiter) acquire() bool {
+	if l.inflight == nil {
+		return true
+	}
+
+	select {
+	case l.inflight <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *requestLimiter) release() {
+	if l.inflight != nil {
+		<-l.inflight
+	}
+}
+
+// reserve consumes a token from the bucket of the client.
+// It returns 0 if the request is allowed, or the delay after which the client can retry.
+func (l *requestLimiter) reserve(client string) time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastPrune) > rateLimiterIdleTTL {
+		for key, entry := range l.clients {
+			if now.Sub(entry.lastSeen) > rateLimiterIdleTTL {
+				delete(l.clients, key)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	entry, found := l.clients[client]
+	if !found {
+		entry = &clientLimiter{limiter: rate.NewLimiter(l.rate, l.burst)}
+		l.clients[client] = entry
+	}
+	entry.lastSeen = now
+
+	reservation := entry.limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	if delay > 0 {
+		reservation.CancelAt(now)
+		return delay
+	}
+
+	return 0
+}
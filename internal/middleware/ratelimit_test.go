@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+)
+
+func processLimitedRequest(filter restful.FilterFunction, method, target string, handler func()) *httptest.ResponseRecorder {
+	httpRequest := httptest.NewRequest(method, target, nil)
+	httpRequest.RemoteAddr = "192.168.1.10:52000"
+
+	recorder := httptest.NewRecorder()
+	response := restful.NewResponse(recorder)
+	response.SetRequestAccepts(restful.MIME_JSON)
+
+	chain := &restful.FilterChain{Target: func(req *restful.Request, resp *restful.Response) {
+		if handler != nil {
+			handler()
+		}
+		resp.WriteHeader(http.StatusOK)
+	}}
+
+	filter(restful.NewRequest(httpRequest), response, chain)
+
+	return recorder
+}
+
+func TestLimitRequestsRate(t *testing.T) {
+	filter := LimitRequests(RateLimitOptions{MutatingRate: 0.1, Burst: 1})
+
+	recorder := processLimitedRequest(filter, http.MethodPost, "/api/v1/namespaces/default/pods", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected the first request to be allowed, got %d", recorder.Code)
+	}
+
+	recorder = processLimitedRequest(filter, http.MethodPost, "/api/v1/namespaces/default/pods", nil)
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the second request to be rate limited, got %d", recorder.Code)
+	}
+
+	if recorder.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+
+	// Read-only requests are limited separately, including the sensitive ones
+	for _, target := range []string{"/api/v1/namespaces/default/pods", "/api/v1/namespaces/default/secrets"} {
+		recorder = processLimitedRequest(filter, http.MethodGet, target, nil)
+		if recorder.Code != http.StatusOK {
+			t.Errorf("expected the read-only request to %s to be allowed, got %d", target, recorder.Code)
+		}
+	}
+}
+
+func TestLimitRequestsInflight(t *testing.T) {
+	filter := LimitRequests(RateLimitOptions{MaxReadOnlyInflight: 1})
+
+	var nested *httptest.ResponseRecorder
+	var longRunning *httptest.ResponseRecorder
+
+	recorder := processLimitedRequest(filter, http.MethodGet, "/api/v1/namespaces/default/pods", func() {
+		// The inflight slot is held while the first request is processed
+		nested = processLimitedRequest(filter, http.MethodGet, "/api/v1/namespaces/default/configmaps", nil)
+		longRunning = processLimitedRequest(filter, http.MethodGet, "/api/v1/namespaces/default/pods?watch=true", nil)
+	})
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected the first request to be allowed, got %d", recorder.Code)
+	}
+
+	if nested.Code != http.StatusTooManyRequests {
+		t.Errorf("expected the concurrent request to be rejected, got %d", nested.Code)
+	}
+
+	if longRunning.Code != http.StatusOK {
+		t.Errorf("expected the long-running request not to be counted, got %d", longRunning.Code)
+	}
+
+	recorder = processLimitedRequest(filter, http.MethodGet, "/api/v1/namespaces/default/configmaps", nil)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("expected the inflight slot to be released, got %d", recorder.Code)
+	}
+}
@@ -8,8 +8,12 @@ import (
 
 // AddTracingHeaders is a filter function that adds a unique tracing header to each incoming HTTP request.
 // This tracing header ("X-K2d-Request-Id") is populated with a new UUID for each request.
+// The header is also added to the response so that callers can retrieve the status of the operation
+// associated with their request (see /k2d/operations).
 // The function then proceeds with the rest of the filter chain by calling the ProcessFilter method.
 func AddTracingHeaders(r *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
-	r.Request.Header.Set(types.RequestIDHeader, string(uuid.NewUUID()))
+	requestID := string(uuid.NewUUID())
+	r.Request.Header.Set(types.RequestIDHeader, requestID)
+	resp.Header().Set(types.RequestIDHeader, requestID)
 	chain.ProcessFilter(r, resp)
 }
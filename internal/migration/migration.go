@@ -0,0 +1,149 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/portainer/k2d/pkg/filesystem"
+	"go.uber.org/zap"
+)
+
+// SchemaVersionFilename is the name of the file of the data path recording the version of the schema of the data
+// managed by k2d (container labels, store layout...).
+const SchemaVersionFilename = "schema-version.json"
+
+// ErrUnsupportedSchemaVersion is returned when the data path was migrated by a more recent version of k2d
+var ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+
+type (
+	// Migration migrates the data managed by k2d to a version of the schema.
+	// A migration must be idempotent: it is run again on the next start when it fails, and it is run
+	// when k2d starts for the first time as the version of a data path created by a previous version of k2d is unknown.
+	Migration struct {
+		// Version is the version of the schema after the migration, the migrations are run in the order of their versions
+		Version int
+		// Description describes the migration, it is logged when the migration is run
+		Description string
+		// Migrate runs the migration
+		Migrate func(ctx context.Context) error
+	}
+
+	// schemaVersion is the content of the schema version file
+	schemaVersion struct {
+		Version    int       `json:"version"`
+		MigratedAt time.Time `json:"migratedAt"`
+	}
+
+	// Runner runs the migrations that were not applied to a data path yet.
+	Runner struct {
+		logger     *zap.SugaredLogger
+		dataPath   string
+		migrations []Migration
+	}
+)
+
+// NewRunner returns a Runner applying the specified migrations to the data path.
+func NewRunner(logger *zap.SugaredLogger, dataPath string, migrations []Migration) *Runner {
+	return &Runner{
+		logger:     logger,
+		dataPath:   dataPath,
+		migrations: migrations,
+	}
+}
+
+// LatestVersion returns the version of the schema once all the migrations are applied.
+func (r *Runner) LatestVersion() int {
+	latest := 0
+	for _, migration := range r.migrations {
+		if migration.Version > latest {
+			latest = migration.Version
+		}
+	}
+	return latest
+}
+
+// Run applies the migrations whose version is greater than the version recorded in the data path, in order.
+// The version is recorded after each successful migration so that an interrupted migration is resumed on the next start.
+// It returns an ErrUnsupportedSchemaVersion error when the data path was migrated by a more recent version of k2d,
+// as its data may not be understood by this version.
+func (r *Runner) Run(ctx context.Context) error {
+	current, err := ReadSchemaVersion(r.dataPath)
+	if err != nil {
+		return err
+	}
+
+	latest := r.LatestVersion()
+	if current > latest {
+		return fmt.Errorf("%w: the data path uses schema version %d while this version of k2d supports up to version %d",
+			ErrUnsupportedSchemaVersion, current, latest)
+	}
+
+	migrations := make([]Migration, len(r.migrations))
+	copy(migrations, r.migrations)
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	for _, migration := range migrations {
+		if migration.Version <= current {
+			continue
+		}
+
+		r.logger.Infow("migrating data",
+			"schema_version", migration.Version,
+			"description", migration.Description,
+		)
+
+		err := migration.Migrate(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to migrate data to schema version %d (%s): %w", migration.Version, migration.Description, err)
+		}
+
+		err = writeSchemaVersion(r.dataPath, migration.Version)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadSchemaVersion returns the version of the schema recorded in the data path.
+// It returns 0 when no version is recorded (new data path or data path created by a previous version of k2d).
+func ReadSchemaVersion(dataPath string) (int, error) {
+	data, err := os.ReadFile(path.Join(dataPath, SchemaVersionFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("unable to read schema version file: %w", err)
+	}
+
+	version := schemaVersion{}
+	err = json.Unmarshal(data, &version)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse schema version file: %w", err)
+	}
+
+	return version.Version, nil
+}
+
+func writeSchemaVersion(dataPath string, version int) error {
+	data, err := json.Marshal(schemaVersion{Version: version, MigratedAt: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("unable to marshal schema version: %w", err)
+	}
+
+	err = filesystem.CreateFileWithDirectories(path.Join(dataPath, SchemaVersionFilename), data)
+	if err != nil {
+		return fmt.Errorf("unable to write schema version file: %w", err)
+	}
+
+	return nil
+}
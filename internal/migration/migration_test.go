@@ -0,0 +1,86 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestRunnerRun(t *testing.T) {
+	dataPath := t.TempDir()
+	applied := []int{}
+
+	newMigration := func(version int) Migration {
+		return Migration{
+			Version:     version,
+			Description: "test",
+			Migrate: func(ctx context.Context) error {
+				applied = append(applied, version)
+				return nil
+			},
+		}
+	}
+
+	runner := NewRunner(zap.NewNop().Sugar(), dataPath, []Migration{newMigration(2), newMigration(1)})
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(applied) != 2 || applied[0] != 1 || applied[1] != 2 {
+		t.Errorf("expected the migrations to be applied in order, got %v", applied)
+	}
+
+	version, err := ReadSchemaVersion(dataPath)
+	if err != nil || version != 2 {
+		t.Errorf("expected schema version 2, got %d (%v)", version, err)
+	}
+
+	applied = []int{}
+	runner = NewRunner(zap.NewNop().Sugar(), dataPath, []Migration{newMigration(1), newMigration(2), newMigration(3)})
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(applied) != 1 || applied[0] != 3 {
+		t.Errorf("expected only the pending migration to be applied, got %v", applied)
+	}
+}
+
+func TestRunnerRunFailure(t *testing.T) {
+	dataPath := t.TempDir()
+
+	runner := NewRunner(zap.NewNop().Sugar(), dataPath, []Migration{
+		{Version: 1, Migrate: func(ctx context.Context) error { return nil }},
+		{Version: 2, Migrate: func(ctx context.Context) error { return errors.New("failure") }},
+	})
+	if err := runner.Run(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	version, err := ReadSchemaVersion(dataPath)
+	if err != nil || version != 1 {
+		t.Errorf("expected the version of the last successful migration to be recorded, got %d (%v)", version, err)
+	}
+}
+
+func TestRunnerRunUnsupportedSchemaVersion(t *testing.T) {
+	dataPath := t.TempDir()
+
+	err := os.WriteFile(path.Join(dataPath, SchemaVersionFilename), []byte(`{"version":5}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewRunner(zap.NewNop().Sugar(), dataPath, []Migration{
+		{Version: 1, Migrate: func(ctx context.Context) error { return nil }},
+	})
+
+	err = runner.Run(context.Background())
+	if !errors.Is(err, ErrUnsupportedSchemaVersion) {
+		t.Errorf("expected ErrUnsupportedSchemaVersion, got %v", err)
+	}
+}
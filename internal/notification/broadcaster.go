@@ -0,0 +1,69 @@
+package notification
+
+import (
+	"sync"
+	"time"
+)
+
+// subscriberQueueSize is the maximum number of notifications waiting to be received by a subscriber.
+// Notifications are dropped for a subscriber when its queue is full so that a slow client never blocks k2d.
+const subscriberQueueSize = 64
+
+// Broadcaster fans out notifications to the subscribers of the /k2d/stream endpoint.
+// A nil Broadcaster discards the notifications, which allows the callers to publish unconditionally.
+type Broadcaster struct {
+	mu          sync.RWMutex
+	subscribers map[chan Notification]struct{}
+}
+
+// NewBroadcaster returns a Broadcaster without any subscriber.
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{
+		subscribers: map[chan Notification]struct{}{},
+	}
+}
+
+// Subscribe registers a new subscriber and returns the channel where the notifications are received,
+// as well as a function that must be called to unsubscribe. The channel is closed when unsubscribing.
+func (b *Broadcaster) Subscribe() (<-chan Notification, func()) {
+	notifications := make(chan Notification, subscriberQueueSize)
+
+	b.mu.Lock()
+	b.subscribers[notifications] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, notifications)
+			b.mu.Unlock()
+
+			close(notifications)
+		})
+	}
+
+	return notifications, unsubscribe
+}
+
+// Publish sends a notification to all the subscribers. It never blocks.
+// The timestamp of the notification is set to the current time when it is not provided.
+func (b *Broadcaster) Publish(notification Notification) {
+	if b == nil {
+		return
+	}
+
+	if notification.Timestamp.IsZero() {
+		notification.Timestamp = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- notification:
+		default:
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package notification
+
+import (
+	"testing"
+)
+
+func TestBroadcasterPublish(t *testing.T) {
+	broadcaster := NewBroadcaster()
+
+	first, unsubscribeFirst := broadcaster.Subscribe()
+	second, unsubscribeSecond := broadcaster.Subscribe()
+	defer unsubscribeSecond()
+
+	broadcaster.Publish(Notification{Kind: "Pod", Name: "web", Reason: ReasonStarted})
+
+	for _, subscriber := range []<-chan Notification{first, second} {
+		notification := <-subscriber
+		if notification.Name != "web" || notification.Timestamp.IsZero() {
+			t.Errorf("unexpected notification: %+v", notification)
+		}
+	}
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+
+	if _, open := <-first; open {
+		t.Error("expected the channel to be closed after unsubscribing")
+	}
+
+	broadcaster.Publish(Notification{Kind: "Pod", Name: "db", Reason: ReasonStarted})
+	if notification := <-second; notification.Name != "db" {
+		t.Errorf("expected the remaining subscriber to receive the notification, got %+v", notification)
+	}
+}
+
+func TestBroadcasterPublishDoesNotBlock(t *testing.T) {
+	broadcaster := NewBroadcaster()
+
+	subscriber, unsubscribe := broadcaster.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < subscriberQueueSize+10; i++ {
+		broadcaster.Publish(Notification{Kind: "Pod", Name: "web", Reason: ReasonStarted})
+	}
+
+	if len(subscriber) != subscriberQueueSize {
+		t.Errorf("expected %d queued notifications, got %d", subscriberQueueSize, len(subscriber))
+	}
+
+	var nilBroadcaster *Broadcaster
+	nilBroadcaster.Publish(Notification{Kind: "Pod", Name: "web"})
+}
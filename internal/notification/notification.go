@@ -0,0 +1,147 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// notificationQueueSize is the maximum number of notifications waiting to be sent to the webhook.
+	// Notifications are dropped (and a warning is logged) when the queue is full so that k2d is never blocked by a slow webhook.
+	notificationQueueSize = 256
+
+	// webhookTimeout is the timeout applied to the requests sent to the webhook.
+	webhookTimeout = 5 * time.Second
+)
+
+const (
+	// ReasonApplied is the reason of a notification sent when a resource was created or updated
+	ReasonApplied = "Applied"
+	// ReasonFailed is the reason of a notification sent when a resource could not be created or updated
+	ReasonFailed = "Failed"
+	// ReasonStarted is the reason of a notification sent when the container of a workload has started
+	ReasonStarted = "Started"
+	// ReasonCompleted is the reason of a notification sent when the container of a workload has exited successfully
+	ReasonCompleted = "Completed"
+	// ReasonCrashed is the reason of a notification sent when the container of a workload has exited with an error
+	ReasonCrashed = "Crashed"
+	// ReasonOOMKilled is the reason of a notification sent when the container of a workload ran out of memory
+	ReasonOOMKilled = "OOMKilled"
+	// ReasonDeleted is the reason of a notification sent when the container of a workload was removed
+	ReasonDeleted = "Deleted"
+)
+
+// Notification describes a change of state of a resource managed by k2d.
+type Notification struct {
+	Timestamp time.Time `json:"timestamp"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message,omitempty"`
+	// ExitCode is the exit code of the container, only set for the Completed and Crashed notifications
+	ExitCode *int `json:"exitCode,omitempty"`
+	// RequestID is the identifier of the request that triggered the change, if any
+	RequestID string `json:"requestID,omitempty"`
+}
+
+// Notifier sends notifications asynchronously to a webhook as JSON using POST requests.
+// A nil Notifier discards the notifications, which allows the callers to notify unconditionally.
+type Notifier struct {
+	logger        *zap.SugaredLogger
+	url           string
+	token         string
+	client        *http.Client
+	notifications chan Notification
+}
+
+// NewNotifier returns a Notifier sending notifications to the given webhook URL.
+// The token is sent as a bearer token in the Authorization header when it is not empty.
+// It starts a background goroutine responsible for sending the notifications.
+func NewNotifier(logger *zap.SugaredLogger, url, token string) *Notifier {
+	notifier := &Notifier{
+		logger:        logger,
+		url:           url,
+		token:         token,
+		client:        &http.Client{Timeout: webhookTimeout},
+		notifications: make(chan Notification, notificationQueueSize),
+	}
+
+	go notifier.run()
+
+	return notifier
+}
+
+// Notify queues a notification. It never blocks.
+// The timestamp of the notification is set to the current time when it is not provided.
+func (n *Notifier) Notify(notification Notification) {
+	if n == nil {
+		return
+	}
+
+	if notification.Timestamp.IsZero() {
+		notification.Timestamp = time.Now()
+	}
+
+	select {
+	case n.notifications <- notification:
+	default:
+		n.logger.Warnw("notification queue is full, dropping notification",
+			"kind", notification.Kind,
+			"namespace", notification.Namespace,
+			"name", notification.Name,
+			"reason", notification.Reason,
+		)
+	}
+}
+
+func (n *Notifier) run() {
+	for notification := range n.notifications {
+		err := n.send(notification)
+		if err != nil {
+			n.logger.Errorw("unable to send notification",
+				"kind", notification.Kind,
+				"namespace", notification.Namespace,
+				"name", notification.Name,
+				"reason", notification.Reason,
+				"error", err,
+			)
+		}
+	}
+}
+
+// send sends a notification to the webhook.
+// It returns an error if the webhook cannot be reached or does not respond with a 2xx status code.
+func (n *Notifier) send(notification Notification) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("unable to marshal notification: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("unable to create notification request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if n.token != "" {
+		request.Header.Set("Authorization", "Bearer "+n.token)
+	}
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("unable to send notification to webhook: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("notification webhook returned status code %d", response.StatusCode)
+	}
+
+	return nil
+}
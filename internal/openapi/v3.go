@@ -0,0 +1,301 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/go-openapi/spec"
+	openapi_v3 "github.com/google/gnostic-models/openapiv3"
+	"github.com/munnerz/goautoneg"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/kube-openapi/pkg/openapiconv"
+	kubespec "k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	// openAPIV3ProtobufSubType is the media subtype used to serve OpenAPI v3 documents in the protobuf format
+	openAPIV3ProtobufSubType = "com.github.proto-openapi.spec.v3@v1.0+protobuf"
+
+	// groupVersionPathParameter is the name of the path parameter holding the group/version of a document (e.g. apis/apps/v1)
+	groupVersionPathParameter = "groupversion"
+)
+
+// OpenAPIV3Discovery is the document served at the root of the OpenAPI v3 service.
+// It lists the group/version documents available and the URL where each of them can be retrieved.
+type OpenAPIV3Discovery struct {
+	Paths map[string]OpenAPIV3DiscoveryGroupVersion `json:"paths"`
+}
+
+// OpenAPIV3DiscoveryGroupVersion contains the URL of an OpenAPI v3 group/version document.
+// The URL contains a hash of the document so that clients can cache it.
+type OpenAPIV3DiscoveryGroupVersion struct {
+	ServerRelativeURL string `json:"serverRelativeURL"`
+}
+
+// OpenAPIV3Service is the service responsible for serving the OpenAPI v3 specs.
+// Unlike OpenAPI v2, the spec is split into one document per group/version (api/v1, apis/apps/v1...).
+// The documents are generated from the OpenAPI v2 spec of the registered web services.
+type OpenAPIV3Service struct {
+	// rwMutex protects All members of this service.
+	rwMutex       sync.RWMutex
+	apiPath       string
+	lastModified  time.Time
+	groupVersions map[string]*openAPIV3GroupVersionCache
+}
+
+type openAPIV3GroupVersionCache struct {
+	jsonCache  HandlerCache
+	protoCache HandlerCache
+	etagCache  HandlerCache
+}
+
+// NewOpenAPIV3Service returns a new WebService that provides the OpenAPI v3 documentation of all services.
+// The discovery document is served at the root of config.APIPath and each group/version document
+// is served under config.APIPath/<group-version> (e.g. /openapi/v3/apis/apps/v1).
+func NewOpenAPIV3Service(config restfulspec.Config) (*restful.WebService, error) {
+	ws := new(restful.WebService)
+	ws.Path(config.APIPath)
+	ws.Produces("application/"+openAPIV3ProtobufSubType, restful.MIME_JSON)
+
+	resource := &OpenAPIV3Service{
+		apiPath: config.APIPath,
+	}
+
+	swagger := restfulspec.BuildSwagger(config)
+	err := resource.UpdateSpec(swagger)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Route(ws.GET("/").To(resource.getDiscovery))
+	ws.Route(ws.GET("/{" + groupVersionPathParameter + ":*}").Filter(EncodingFilter).To(resource.getGroupVersion))
+	return ws, nil
+}
+
+// UpdateSpec splits the OpenAPI v2 spec into group/version specs and resets the associated caches.
+// The OpenAPI v3 documents are lazily generated when they are first requested.
+func (o *OpenAPIV3Service) UpdateSpec(openapiSpec *spec.Swagger) error {
+	o.rwMutex.Lock()
+	defer o.rwMutex.Unlock()
+
+	groupVersions := map[string]*openAPIV3GroupVersionCache{}
+	for groupVersion, groupVersionSpec := range splitSpecByGroupVersion(openapiSpec) {
+		groupVersionSpec := groupVersionSpec
+
+		cache := &openAPIV3GroupVersionCache{}
+		cache.jsonCache = cache.jsonCache.New(func() ([]byte, error) {
+			return toOpenAPIV3(groupVersionSpec)
+		})
+		cache.protoCache = cache.protoCache.New(func() ([]byte, error) {
+			json, err := cache.jsonCache.Get()
+			if err != nil {
+				return nil, err
+			}
+			return toProtoBinaryV3(json)
+		})
+		cache.etagCache = cache.etagCache.New(func() ([]byte, error) {
+			json, err := cache.jsonCache.Get()
+			if err != nil {
+				return nil, err
+			}
+			return []byte(computeETag(json)), nil
+		})
+
+		groupVersions[groupVersion] = cache
+	}
+
+	o.groupVersions = groupVersions
+	o.lastModified = time.Now()
+
+	return nil
+}
+
+func (o *OpenAPIV3Service) getDiscovery(req *restful.Request, resp *restful.Response) {
+	o.rwMutex.RLock()
+	defer o.rwMutex.RUnlock()
+
+	discovery := OpenAPIV3Discovery{
+		Paths: map[string]OpenAPIV3DiscoveryGroupVersion{},
+	}
+
+	for groupVersion, cache := range o.groupVersions {
+		etag, err := cache.etagCache.Get()
+		if err != nil {
+			log.Printf("Error in OpenAPI v3 handler: %s", err)
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		discovery.Paths[groupVersion] = OpenAPIV3DiscoveryGroupVersion{
+			ServerRelativeURL: fmt.Sprintf("%s/%s?hash=%s", o.apiPath, groupVersion, etag),
+		}
+	}
+
+	resp.Header().Set("Last-Modified", o.lastModified.UTC().Format(http.TimeFormat))
+	resp.WriteAsJson(discovery)
+}
+
+func (o *OpenAPIV3Service) getGroupVersion(req *restful.Request, resp *restful.Response) {
+	groupVersion := strings.Trim(req.PathParameter(groupVersionPathParameter), "/")
+
+	o.rwMutex.RLock()
+	cache, found := o.groupVersions[groupVersion]
+	lastModified := o.lastModified
+	o.rwMutex.RUnlock()
+
+	if !found {
+		resp.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	etag, err := cache.etagCache.Get()
+	if err != nil {
+		log.Printf("Error in OpenAPI v3 handler: %s", err)
+		resp.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	// The hash query parameter is used by clients to cache documents.
+	// When the hash is outdated, the client is redirected to the URL of the current document.
+	hash := req.QueryParameter("hash")
+	if hash != "" && hash != string(etag) {
+		http.Redirect(resp.ResponseWriter, req.Request, fmt.Sprintf("%s/%s?hash=%s", o.apiPath, groupVersion, etag), http.StatusMovedPermanently)
+		return
+	}
+
+	accepted := []struct {
+		Type    string
+		SubType string
+		GetData func() ([]byte, error)
+	}{
+		{"application", "json", cache.jsonCache.Get},
+		{"application", openAPIV3ProtobufSubType, cache.protoCache.Get},
+	}
+
+	decipherableFormats := req.Request.Header.Get("Accept")
+	if decipherableFormats == "" {
+		decipherableFormats = "*/*"
+	}
+
+	clauses := goautoneg.ParseAccept(decipherableFormats)
+	resp.Header().Add("Vary", "Accept")
+	for _, clause := range clauses {
+		for _, accepts := range accepted {
+			if clause.Type != accepts.Type && clause.Type != "*" {
+				continue
+			}
+			if clause.SubType != accepts.SubType && clause.SubType != "*" {
+				continue
+			}
+
+			data, err := accepts.GetData()
+			if err != nil {
+				log.Printf("Error in OpenAPI v3 handler: %s", err)
+				// only return a 503 if we have no older cache data to serve
+				if data == nil {
+					resp.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+			}
+
+			if hash != "" {
+				resp.Header().Set("Cache-Control", "public, immutable")
+				resp.Header().Set("Expires", time.Now().AddDate(1, 0, 0).UTC().Format(http.TimeFormat))
+			}
+
+			resp.Header().Set("Content-Type", accepts.Type+"/"+accepts.SubType)
+			resp.Header().Set("Etag", strconv.Quote(string(etag)))
+			resp.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+			resp.Write(data)
+			return
+		}
+	}
+
+	resp.WriteHeader(http.StatusNotAcceptable)
+}
+
+// splitSpecByGroupVersion splits an OpenAPI v2 spec into one spec per group/version.
+// Only the paths served under /api/<version> and /apis/<group>/<version> are kept.
+// The definitions are shared across all the group/version specs.
+func splitSpecByGroupVersion(swagger *spec.Swagger) map[string]*spec.Swagger {
+	groupVersionSpecs := map[string]*spec.Swagger{}
+
+	if swagger.Paths == nil {
+		return groupVersionSpecs
+	}
+
+	paths := make([]string, 0, len(swagger.Paths.Paths))
+	for path := range swagger.Paths.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		groupVersion := groupVersionFromPath(path)
+		if groupVersion == "" {
+			continue
+		}
+
+		groupVersionSpec, found := groupVersionSpecs[groupVersion]
+		if !found {
+			specCopy := *swagger
+			specCopy.Paths = &spec.Paths{Paths: map[string]spec.PathItem{}}
+			groupVersionSpec = &specCopy
+			groupVersionSpecs[groupVersion] = groupVersionSpec
+		}
+
+		groupVersionSpec.Paths.Paths[path] = swagger.Paths.Paths[path]
+	}
+
+	return groupVersionSpecs
+}
+
+// groupVersionFromPath returns the group/version associated with an API path.
+// e.g. /api/v1/namespaces returns api/v1 and /apis/apps/v1/deployments returns apis/apps/v1.
+// It returns an empty string for paths that are not associated with a group/version.
+func groupVersionFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(segments) >= 2 && segments[0] == "api" {
+		return strings.Join(segments[:2], "/")
+	}
+
+	if len(segments) >= 3 && segments[0] == "apis" {
+		return strings.Join(segments[:3], "/")
+	}
+
+	return ""
+}
+
+// toOpenAPIV3 converts an OpenAPI v2 spec into an OpenAPI v3 document serialized as JSON.
+func toOpenAPIV3(swagger *spec.Swagger) ([]byte, error) {
+	data, err := json.Marshal(swagger)
+	if err != nil {
+		return nil, err
+	}
+
+	v2 := &kubespec.Swagger{}
+	err = json.Unmarshal(data, v2)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(openapiconv.ConvertV2ToV3(v2))
+}
+
+func toProtoBinaryV3(json []byte) ([]byte, error) {
+	document, err := openapi_v3.ParseDocument(json)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(document)
+}
@@ -0,0 +1,137 @@
+package ssl
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/portainer/k2d/internal/logging"
+)
+
+// certificateCheckInterval is the interval at which the certificate files of the k2d API server are checked
+// for changes and renewal.
+const certificateCheckInterval = time.Minute
+
+// CertificateReloader serves the TLS certificate of the k2d API server (see tls.Config.GetCertificate) and reloads it
+// when its files are modified, so that a renewed or replaced certificate is used without restarting k2d.
+type CertificateReloader struct {
+	certFile    string
+	keyFile     string
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	modTime     time.Time
+}
+
+// NewCertificateReloader returns a CertificateReloader serving the certificate stored in the specified files.
+func NewCertificateReloader(certFile, keyFile string) (*CertificateReloader, error) {
+	reloader := &CertificateReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	_, err := reloader.reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// GetCertificate returns the current certificate, it is meant to be used as tls.Config.GetCertificate.
+func (reloader *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	reloader.mu.RLock()
+	defer reloader.mu.RUnlock()
+
+	return reloader.certificate, nil
+}
+
+// Start periodically renews the certificate using the specified function, if any, and reloads the certificate
+// when its files are modified. It runs until the context is cancelled.
+// A failure is logged and the current certificate keeps being served.
+func (reloader *CertificateReloader) Start(ctx context.Context, renew func(ctx context.Context) error) {
+	logger := logging.LoggerFromContext(ctx)
+
+	ticker := time.NewTicker(certificateCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if renew != nil {
+				err := renew(ctx)
+				if err != nil {
+					logger.Errorw("unable to renew TLS certificate",
+						"error", err,
+					)
+				}
+			}
+
+			reloaded, err := reloader.reload()
+			if err != nil {
+				logger.Errorw("unable to reload TLS certificate, the current certificate is kept",
+					"cert_file", reloader.certFile,
+					"error", err,
+				)
+				continue
+			}
+
+			if reloaded {
+				logger.Infow("TLS certificate reloaded",
+					"cert_file", reloader.certFile,
+				)
+			}
+		}
+	}
+}
+
+// reload loads the certificate when its files were modified since the last load.
+// It returns true if the certificate was loaded.
+func (reloader *CertificateReloader) reload() (bool, error) {
+	modTime, err := latestModTime(reloader.certFile, reloader.keyFile)
+	if err != nil {
+		return false, err
+	}
+
+	reloader.mu.RLock()
+	unchanged := reloader.certificate != nil && modTime.Equal(reloader.modTime)
+	reloader.mu.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(reloader.certFile, reloader.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("unable to load TLS certificate: %w", err)
+	}
+
+	reloader.mu.Lock()
+	reloader.certificate = &certificate
+	reloader.modTime = modTime
+	reloader.mu.Unlock()
+
+	return true, nil
+}
+
+// latestModTime returns the most recent modification time of the specified files.
+func latestModTime(files ...string) (time.Time, error) {
+	latest := time.Time{}
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unable to stat %s: %w", file, err)
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
@@ -2,8 +2,12 @@ package ssl
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"net"
+	"os"
 	"path"
 	"time"
 
@@ -13,12 +17,21 @@ import (
 )
 
 const (
-	SSL_FOLDER    = "ssl"
-	CA_FILENAME   = "ca.pem"
-	CERT_FILENAME = "cert.pem"
-	KEY_FILENAME  = "key.pem"
+	SSL_FOLDER      = "ssl"
+	CA_FILENAME     = "ca.pem"
+	CA_KEY_FILENAME = "ca-key.pem"
+	CERT_FILENAME   = "cert.pem"
+	KEY_FILENAME    = "key.pem"
+
+	// CLIENT_REVOCATION_FILENAME is the name of the file storing the time before which the client certificates are revoked
+	CLIENT_REVOCATION_FILENAME = "client-revoked-before"
 )
 
+// ClientCertificateValidity is the validity period of the client certificates issued by k2d.
+// It is kept short as a leaked certificate cannot be revoked individually (see RevokeClientCertificates),
+// a new kubeconfig must be retrieved once it expires.
+const ClientCertificateValidity = 30 * 24 * time.Hour
+
 // SSLCAPath constructs and returns the file path of the CA certificate.
 // The path is formed by joining the provided data path, the predefined SSL folder,
 // and the CA filename.
@@ -26,6 +39,13 @@ func SSLCAPath(dataPath string) string {
 	return path.Join(dataPath, SSL_FOLDER, CA_FILENAME)
 }
 
+// SSLCAKeyPath constructs and returns the file path of the CA private key.
+// The path is formed by joining the provided data path, the predefined SSL folder,
+// and the CA key filename.
+func SSLCAKeyPath(dataPath string) string {
+	return path.Join(dataPath, SSL_FOLDER, CA_KEY_FILENAME)
+}
+
 // SSLCertPath constructs and returns the file path of the SSL certificate.
 // The path is formed by joining the provided data path, the predefined SSL folder,
 // and the SSL certificate filename.
@@ -40,17 +60,104 @@ func SSLKeyPath(dataPath string) string {
 	return path.Join(dataPath, SSL_FOLDER, KEY_FILENAME)
 }
 
+// ClientRevocationPath constructs and returns the file path of the client certificates revocation time.
+func ClientRevocationPath(dataPath string) string {
+	return path.Join(dataPath, SSL_FOLDER, CLIENT_REVOCATION_FILENAME)
+}
+
+// RevokeClientCertificates persists the time before which the client certificates are revoked: the certificates
+// issued before this time are rejected, even if they are not expired (see LoadClientCertificatesRevocation).
+// It is used when the secret is rotated, as the previous secret could have been used to issue certificates.
+func RevokeClientCertificates(dataPath string, revokedBefore time.Time) error {
+	err := filesystem.CreateDir(path.Join(dataPath, SSL_FOLDER))
+	if err != nil {
+		return fmt.Errorf("unable to create SSL directory: %w", err)
+	}
+
+	err = os.WriteFile(ClientRevocationPath(dataPath), []byte(revokedBefore.UTC().Format(time.RFC3339)), 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write client certificates revocation file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadClientCertificatesRevocation returns the time before which the client certificates are revoked,
+// the zero time when no certificate was ever revoked.
+func LoadClientCertificatesRevocation(dataPath string) (time.Time, error) {
+	data, err := os.ReadFile(ClientRevocationPath(dataPath))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("unable to read client certificates revocation file: %w", err)
+	}
+
+	revokedBefore, err := time.Parse(time.RFC3339, string(data))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse client certificates revocation file: %w", err)
+	}
+
+	return revokedBefore, nil
+}
+
+// TLSOptions represents the TLS configuration of the k2d API server.
+type TLSOptions struct {
+	// CertFile and KeyFile are the paths to a certificate and private key provided by the operator.
+	// A certificate signed by the k2d CA is generated and renewed automatically when they are empty.
+	CertFile string
+	KeyFile  string
+	// CAFile is the path to the CA bundle used by the clients to verify the certificate provided by the operator.
+	// The certificate file itself (e.g. a full chain) is used when it is empty.
+	CAFile string
+	// SANs are the additional host names and IP addresses included in the generated certificate.
+	SANs []string
+	// RenewBefore is the duration before the expiry of the generated certificate from which it is renewed.
+	RenewBefore time.Duration
+}
+
+// CustomCertificate returns true if the certificate of the k2d API server is provided by the operator.
+func (opts TLSOptions) CustomCertificate() bool {
+	return opts.CertFile != ""
+}
+
+// ServerCertPaths returns the paths to the certificate and private key of the k2d API server.
+func ServerCertPaths(dataPath string, opts TLSOptions) (string, string) {
+	if opts.CustomCertificate() {
+		return opts.CertFile, opts.KeyFile
+	}
+
+	return SSLCertPath(dataPath), SSLKeyPath(dataPath)
+}
+
+// ServerCAPath returns the path to the CA bundle that the clients of the k2d API server must trust.
+// It is included in the generated kubeconfig files and mounted in the containers.
+func ServerCAPath(dataPath string, opts TLSOptions) string {
+	switch {
+	case opts.CAFile != "":
+		return opts.CAFile
+	case opts.CustomCertificate():
+		return opts.CertFile
+	default:
+		return SSLCAPath(dataPath)
+	}
+}
+
 // EnsureTLSCertificatesExist generates TLS certificates for the provided IP address
 // and stores them in a specified directory. If the certificates already exist,
-// the function simply verifies their existence and returns.
+// the function ensures that the TLS certificate is still valid for the k2d API server and renews it otherwise.
 //
 // The function first creates a directory at the provided `dataPath`, if it does not exist.
 // It then checks for the existence of the TLS certificates in this directory. If the certificates
-// do not exist, the function generates new ones.
+// do not exist, the function generates new ones. The CA is always generated, as it is used to issue client certificates.
+// When the certificate is provided by the operator (see TLSOptions), the function only verifies that it can be loaded.
+// Otherwise, the TLS certificate is renewed, using the existing CA, when it does not include the IP address or one
+// of the SANs (e.g. after a change of the advertise address) or when it expires within the renewal period.
 //
 // Parameters:
 // - `dataPath`: The path where the SSL folder and the certificates are (or will be) located.
 // - `ipAddr`: The IP address for which the certificates are generated.
+// - `opts`: The TLS configuration of the k2d API server.
 //
 // It returns an error if any occurs during the directory creation, certificate existence check,
 // or certificate generation processes.
@@ -58,7 +165,7 @@ func SSLKeyPath(dataPath string) string {
 // The generated certificates have a validity period of 25 years.
 //
 // This function depends on the ssl.GenerateTLSCertificatesForIPAddr and filesystem.CreateDir functions.
-func EnsureTLSCertificatesExist(ctx context.Context, dataPath string, ipAddr net.IP) error {
+func EnsureTLSCertificatesExist(ctx context.Context, dataPath string, ipAddr net.IP, opts TLSOptions) error {
 	certPath := path.Join(dataPath, SSL_FOLDER)
 
 	err := filesystem.CreateDir(certPath)
@@ -71,21 +178,31 @@ func EnsureTLSCertificatesExist(ctx context.Context, dataPath string, ipAddr net
 		Country:      "NZ",
 		Locality:     "Auckland",
 		// 25 years validity
-		Validity:     25 * 365 * 24 * time.Hour,
-		IpAddr:       ipAddr,
-		CertPath:     path.Join(dataPath, SSL_FOLDER),
-		CAFilename:   CA_FILENAME,
-		CertFilename: CERT_FILENAME,
-		KeyFilename:  KEY_FILENAME,
+		Validity:      25 * 365 * 24 * time.Hour,
+		IpAddr:        ipAddr,
+		CertPath:      path.Join(dataPath, SSL_FOLDER),
+		CAFilename:    CA_FILENAME,
+		CAKeyFilename: CA_KEY_FILENAME,
+		CertFilename:  CERT_FILENAME,
+		KeyFilename:   KEY_FILENAME,
 	}
 
+	for _, san := range opts.SANs {
+		if ip := net.ParseIP(san); ip != nil {
+			cfg.IPAddresses = append(cfg.IPAddresses, ip)
+		} else {
+			cfg.DNSNames = append(cfg.DNSNames, san)
+		}
+	}
+
+	logger := logging.LoggerFromContext(ctx)
+
 	tlsFilesExist, err := areTLSCertificatesPresent(cfg)
 	if err != nil {
 		return fmt.Errorf("unable to check if TLS files exist: %w", err)
 	}
 
 	if !tlsFilesExist {
-		logger := logging.LoggerFromContext(ctx)
 		logger.Infow("TLS certificates not found. Generating new ones",
 			"ip_address", ipAddr,
 		)
@@ -96,9 +213,136 @@ func EnsureTLSCertificatesExist(ctx context.Context, dataPath string, ipAddr net
 		}
 	}
 
+	if opts.CustomCertificate() {
+		_, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return fmt.Errorf("unable to load TLS certificate %s: %w", opts.CertFile, err)
+		}
+		return nil
+	}
+
+	reason, err := certificateRenewalReason(cfg, opts.RenewBefore)
+	if err != nil {
+		return err
+	}
+
+	if reason == "" {
+		return nil
+	}
+
+	caKeyExists, err := filesystem.FileExists(SSLCAKeyPath(dataPath))
+	if err != nil {
+		return fmt.Errorf("unable to check if CA private key exists: %w", err)
+	}
+
+	if !caKeyExists {
+		logger.Warnw("renewing TLS certificates, the CA private key is not available and a new CA is generated: the kubeconfig files must be retrieved again",
+			"reason", reason,
+		)
+
+		err = ssl.GenerateTLSCertificatesForIPAddr(cfg)
+		if err != nil {
+			return fmt.Errorf("unable to generate TLS certificates: %w", err)
+		}
+		return nil
+	}
+
+	logger.Infow("renewing TLS certificate",
+		"reason", reason,
+	)
+
+	err = ssl.GenerateServerCertificate(cfg)
+	if err != nil {
+		return fmt.Errorf("unable to renew TLS certificate: %w", err)
+	}
+
 	return nil
 }
 
+// certificateRenewalReason returns the reason why the generated TLS certificate must be renewed,
+// an empty string if it is still valid for the k2d API server.
+func certificateRenewalReason(cfg ssl.CertConfig, renewBefore time.Duration) (string, error) {
+	cert, err := ssl.LoadCertificate(path.Join(cfg.CertPath, cfg.CertFilename))
+	if err != nil {
+		return "", fmt.Errorf("unable to load TLS certificate: %w", err)
+	}
+
+	if time.Now().Add(renewBefore).After(cert.NotAfter) {
+		return fmt.Sprintf("the certificate expires on %s", cert.NotAfter.Format(time.RFC3339)), nil
+	}
+
+	for _, ip := range ssl.ServerCertificateIPAddresses(cfg) {
+		if !containsIP(cert.IPAddresses, ip) {
+			return fmt.Sprintf("the certificate does not include the IP address %s", ip), nil
+		}
+	}
+
+	for _, dnsName := range ssl.ServerCertificateDNSNames(cfg) {
+		if !containsString(cert.DNSNames, dnsName) {
+			return fmt.Sprintf("the certificate does not include the host name %s", dnsName), nil
+		}
+	}
+
+	return "", nil
+}
+
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, candidate := range ips {
+		if candidate.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// NewServerTLSConfig returns the TLS configuration of the k2d API server.
+// Clients can optionally present a client certificate signed by the k2d CA to authenticate,
+// as an alternative to a bearer token. The certificate is verified during the TLS handshake when it is provided.
+func NewServerTLSConfig(dataPath string) (*tls.Config, error) {
+	caData, err := os.ReadFile(SSLCAPath(dataPath))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read TLS CA file: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caData) {
+		return nil, errors.New("unable to parse TLS CA file")
+	}
+
+	return &tls.Config{
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientCAs:  clientCAs,
+	}, nil
+}
+
+// GenerateClientCertificate issues a client certificate signed by the k2d CA.
+// The role is stored as the organization of the certificate and is used to authorize the requests
+// authenticated with this certificate.
+// It returns the PEM encoded certificate and private key.
+// An error is returned if the CA private key is not available, which is the case when the certificates
+// were generated by a version of k2d that did not persist it. Removing the SSL folder regenerates all the certificates.
+func GenerateClientCertificate(dataPath, commonName, role string) ([]byte, []byte, error) {
+	caKeyExists, err := filesystem.FileExists(SSLCAKeyPath(dataPath))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to check if CA private key exists: %w", err)
+	}
+
+	if !caKeyExists {
+		return nil, nil, fmt.Errorf("CA private key not found in %s, remove the %s folder to regenerate the TLS certificates", path.Join(dataPath, SSL_FOLDER), SSL_FOLDER)
+	}
+
+	return ssl.GenerateClientCertificate(SSLCAPath(dataPath), SSLCAKeyPath(dataPath), commonName, []string{role}, ClientCertificateValidity)
+}
+
 func areTLSCertificatesPresent(cfg ssl.CertConfig) (bool, error) {
 	files := []string{cfg.CAFilename, cfg.CertFilename, cfg.KeyFilename}
 
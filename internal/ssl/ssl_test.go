@@ -0,0 +1,61 @@
+package ssl
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/portainer/k2d/pkg/ssl"
+)
+
+func TestEnsureTLSCertificatesExistRenewal(t *testing.T) {
+	dataPath := t.TempDir()
+	ctx := context.Background()
+	ip := net.ParseIP("192.168.1.10")
+
+	err := EnsureTLSCertificatesExist(ctx, dataPath, ip, TLSOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	caData, err := os.ReadFile(SSLCAPath(dataPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = EnsureTLSCertificatesExist(ctx, dataPath, net.ParseIP("192.168.1.20"), TLSOptions{SANs: []string{"k2d.local", "10.0.0.1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cert, err := ssl.LoadCertificate(SSLCertPath(dataPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !containsIP(cert.IPAddresses, net.ParseIP("192.168.1.20")) || !containsIP(cert.IPAddresses, net.ParseIP("10.0.0.1")) {
+		t.Errorf("expected the renewed certificate to include the new IP addresses, got %v", cert.IPAddresses)
+	}
+	if !containsString(cert.DNSNames, "k2d.local") {
+		t.Errorf("expected the renewed certificate to include the SAN host name, got %v", cert.DNSNames)
+	}
+
+	renewedCAData, err := os.ReadFile(SSLCAPath(dataPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(caData) != string(renewedCAData) {
+		t.Error("expected the CA to be kept when renewing the certificate")
+	}
+
+	reason, err := certificateRenewalReason(ssl.CertConfig{
+		IpAddr:       net.ParseIP("192.168.1.20"),
+		CertPath:     dataPath + "/" + SSL_FOLDER,
+		CertFilename: CERT_FILENAME,
+	}, 30*365*24*time.Hour)
+	if err != nil || reason == "" {
+		t.Errorf("expected the certificate to be renewed before its expiry, got %q (%v)", reason, err)
+	}
+}
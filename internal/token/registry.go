@@ -0,0 +1,64 @@
+package token
+
+import (
+	"sync"
+	"time"
+)
+
+// Registry holds the bearer tokens accepted by the k2d API and their associated role.
+// It is safe for concurrent use and allows tokens to be rotated at runtime.
+type Registry struct {
+	mu    sync.RWMutex
+	roles map[string]Role
+	// certificatesRevokedBefore is the time before which the client certificates issued by k2d are revoked
+	certificatesRevokedBefore time.Time
+}
+
+// NewRegistry returns a new Registry initialized with the given tokens.
+func NewRegistry(roles map[string]Role) *Registry {
+	registry := &Registry{
+		roles: map[string]Role{},
+	}
+
+	for token, role := range roles {
+		registry.roles[token] = role
+	}
+
+	return registry
+}
+
+// Role returns the role associated with a token and whether the token is known.
+func (registry *Registry) Role(token string) (Role, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	role, found := registry.roles[token]
+	return role, found
+}
+
+// Replace revokes a token and registers a new token with the given role.
+func (registry *Registry) Replace(oldToken, newToken string, role Role) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	delete(registry.roles, oldToken)
+	registry.roles[newToken] = role
+}
+
+// RevokeCertificates revokes the client certificates issued before the specified time.
+func (registry *Registry) RevokeCertificates(revokedBefore time.Time) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.certificatesRevokedBefore = revokedBefore
+}
+
+// CertificateRevoked returns true if a client certificate valid from the specified time (its NotBefore field) is revoked.
+// The validity of the certificates is stored with a precision of one second: the certificates issued during
+// the second of the revocation are not revoked.
+func (registry *Registry) CertificateRevoked(notBefore time.Time) bool {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	return notBefore.Before(registry.certificatesRevokedBefore.Truncate(time.Second))
+}
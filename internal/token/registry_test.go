@@ -0,0 +1,29 @@
+package token
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegistryCertificateRevoked(t *testing.T) {
+	registry := NewRegistry(map[string]Role{})
+
+	issuedAt := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	if registry.CertificateRevoked(issuedAt) {
+		t.Error("expected no certificate to be revoked by default")
+	}
+
+	registry.RevokeCertificates(issuedAt.Add(90 * time.Minute).Add(500 * time.Millisecond))
+
+	if !registry.CertificateRevoked(issuedAt) {
+		t.Error("expected a certificate issued before the revocation to be revoked")
+	}
+
+	if registry.CertificateRevoked(issuedAt.Add(90 * time.Minute)) {
+		t.Error("expected a certificate issued during the second of the revocation not to be revoked")
+	}
+
+	if registry.CertificateRevoked(issuedAt.Add(2 * time.Hour)) {
+		t.Error("expected a certificate issued after the revocation not to be revoked")
+	}
+}
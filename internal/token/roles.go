@@ -0,0 +1,104 @@
+package token
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Role represents the permissions associated with a bearer token.
+type Role string
+
+const (
+	// AdminRole grants full access to the k2d API
+	AdminRole Role = "admin"
+	// ReadOnlyRole only grants access to the read operations of the k2d API (GET, HEAD and OPTIONS requests),
+	// excluding the requests giving access to the containers or to the secrets
+	ReadOnlyRole Role = "read-only"
+)
+
+// ParseRole returns the Role associated with the given value.
+// It returns an error if the value is not a supported role.
+func ParseRole(value string) (Role, error) {
+	switch Role(value) {
+	case AdminRole, ReadOnlyRole:
+		return Role(value), nil
+	default:
+		return "", fmt.Errorf("unsupported role %q, valid roles are: %s, %s", value, AdminRole, ReadOnlyRole)
+	}
+}
+
+// LoadTokens builds the list of bearer tokens accepted by the k2d API and their associated role.
+// The encoded secret is always associated with the admin role.
+// Additional tokens can be defined using the tokens map (token:role pairs) and/or a tokens file.
+// The tokens file contains one token per line, using the format token,role. Empty lines and lines
+// starting with # are ignored.
+// The function returns an error if a role is not supported or if the tokens file cannot be read.
+func LoadTokens(encodedSecret string, tokens map[string]string, tokensFilePath string) (map[string]Role, error) {
+	roles := map[string]Role{}
+
+	for token, value := range tokens {
+		role, err := ParseRole(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid role for token: %w", err)
+		}
+
+		roles[token] = role
+	}
+
+	if tokensFilePath != "" {
+		fileTokens, err := loadTokensFile(tokensFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load tokens file: %w", err)
+		}
+
+		for token, role := range fileTokens {
+			roles[token] = role
+		}
+	}
+
+	roles[encodedSecret] = AdminRole
+
+	return roles, nil
+}
+
+// loadTokensFile reads the token,role pairs defined in a tokens file.
+func loadTokensFile(filePath string) (map[string]Role, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %w", err)
+	}
+	defer file.Close()
+
+	roles := map[string]Role{}
+	lineNumber := 0
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		token, value, found := strings.Cut(line, ",")
+		if !found || strings.TrimSpace(token) == "" {
+			return nil, fmt.Errorf("invalid format on line %d, expected token,role", lineNumber)
+		}
+
+		role, err := ParseRole(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid role on line %d: %w", lineNumber, err)
+		}
+
+		roles[strings.TrimSpace(token)] = role
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read file: %w", err)
+	}
+
+	return roles, nil
+}
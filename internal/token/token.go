@@ -50,3 +50,17 @@ func RetrieveOrCreateEncodedSecret(logger *zap.SugaredLogger, secret, tokenPath
 
 	return encodedSecret, nil
 }
+
+// RotateEncodedSecret generates a new secret using a UUID, encodes it in base64 and overwrites the token file with it.
+// The token file is updated in place so that the containers where it is mounted can read the new secret.
+// The function returns the new encoded secret as a string, or an error if the token file cannot be written.
+func RotateEncodedSecret(tokenPath string) (string, error) {
+	encodedSecret := base64.StdEncoding.EncodeToString([]byte(uuid.NewUUID()))
+
+	err := filesystem.CreateFileWithDirectories(tokenPath, []byte(encodedSecret))
+	if err != nil {
+		return "", fmt.Errorf("unable to update token file: %w", err)
+	}
+
+	return encodedSecret, nil
+}
@@ -0,0 +1,15 @@
+package types
+
+const (
+	// PendingQueueFilename is the name of the file, relative to the k2d data path, where the operations
+	// accepted by the API but not processed yet are persisted
+	PendingQueueFilename = "operations-pending.json"
+	// RetryQueueFilename is the name of the file, relative to the k2d data path, where the operations
+	// waiting to be retried are persisted
+	RetryQueueFilename = "operations-retry.json"
+)
+
+// QueueTemporaryFilename returns the name of the temporary file used to replace the content of a queue file.
+func QueueTemporaryFilename(queueFilename string) string {
+	return "." + queueFilename + ".tmp"
+}
@@ -1,5 +1,11 @@
 package types
 
+import (
+	"sync/atomic"
+
+	"github.com/portainer/k2d/internal/token"
+)
+
 // K2DServerConfiguration represents the configuration of the k2d server
 type K2DServerConfiguration struct {
 	// ServerIpAddr is the IP address on which the k2d server listens. It will be shared with all created containers through
@@ -11,10 +17,36 @@ type K2DServerConfiguration struct {
 	// CaPath is the path to the CA certificate that is used to sign the server certificate. It will be mounted into all
 	// containers
 	CaPath string
+	// ServerURL is the URL of the k2d API server included in the generated kubeconfig files
+	ServerURL string
+	// HTTPOnly defines whether the k2d API server is served over plain HTTP, behind a TLS-terminating reverse proxy.
+	// Client certificates cannot be used to authenticate in this mode.
+	HTTPOnly bool
+	// KubeconfigCAPath is the path to the CA certificate included in the generated kubeconfig files.
+	// It is empty when the clients rely on the CA certificates of their system (e.g. behind a reverse proxy).
+	KubeconfigCAPath string
+	// DataPath is the path where k2d stores its data, including the TLS certificates
+	DataPath string
 	// TokenPath is the path to the token file that will be mounted into all containers
 	TokenPath string
-	// Secret is the secret used to protect some API operations such as getting the kubeconfig.
-	Secret string
+	// secret is the secret used to protect some API operations such as getting the kubeconfig.
+	// It can be rotated while the API is serving requests and must be accessed through Secret and SetSecret.
+	// It is never serialized (e.g. in the diagnostics).
+	secret atomic.Value
+	// Tokens are the bearer tokens accepted by the k2d API, associated with their role. It always includes the secret.
+	// They are never serialized (e.g. in the diagnostics).
+	Tokens *token.Registry `json:"-"`
+}
+
+// Secret returns the secret used to protect some API operations such as getting the kubeconfig.
+func (cfg *K2DServerConfiguration) Secret() string {
+	secret, _ := cfg.secret.Load().(string)
+	return secret
+}
+
+// SetSecret replaces the secret, e.g. when it is rotated.
+func (cfg *K2DServerConfiguration) SetSecret(secret string) {
+	cfg.secret.Store(secret)
 }
 
 const (
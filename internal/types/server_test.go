@@ -0,0 +1,42 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestServerConfigurationSecret(t *testing.T) {
+	cfg := &K2DServerConfiguration{}
+	cfg.SetSecret("initial")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			cfg.SetSecret("rotated")
+		}()
+		go func() {
+			defer wg.Done()
+			if secret := cfg.Secret(); secret != "initial" && secret != "rotated" {
+				t.Errorf("unexpected secret %q", secret)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if cfg.Secret() != "rotated" {
+		t.Errorf("expected the secret to be rotated, got %q", cfg.Secret())
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if strings.Contains(string(data), "rotated") {
+		t.Errorf("expected the secret not to be serialized, got %s", data)
+	}
+}
@@ -0,0 +1,152 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// patchOperation represents an operation of a JSON patch.
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Apply applies a JSON patch (RFC 6902) to a JSON document.
+// Only the add, remove and replace operations are supported.
+func Apply(original, patch []byte) ([]byte, error) {
+	operations := []patchOperation{}
+	err := json.Unmarshal(patch, &operations)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal JSON patch: %w", err)
+	}
+
+	var document interface{}
+	err = json.Unmarshal(original, &document)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal resource: %w", err)
+	}
+
+	for _, operation := range operations {
+		var value interface{}
+		if operation.Op != "remove" {
+			err = json.Unmarshal(operation.Value, &value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for operation %s %s: %w", operation.Op, operation.Path, err)
+			}
+		}
+
+		document, err = applyOperation(document, parseJSONPointer(operation.Path), operation.Op, value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to apply operation %s %s: %w", operation.Op, operation.Path, err)
+		}
+	}
+
+	return json.Marshal(document)
+}
+
+// parseJSONPointer splits a JSON pointer (RFC 6901) into its unescaped reference tokens.
+func parseJSONPointer(pointer string) []string {
+	if pointer == "" {
+		return []string{}
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens
+}
+
+// applyOperation applies an operation to the node of a document referenced by the specified path
+// and returns the updated node.
+func applyOperation(node interface{}, path []string, op string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		switch op {
+		case "add", "replace":
+			return value, nil
+		default:
+			return nil, fmt.Errorf("unsupported operation on the document root")
+		}
+	}
+
+	token := path[0]
+	last := len(path) == 1
+
+	switch typedNode := node.(type) {
+	case map[string]interface{}:
+		child, exists := typedNode[token]
+
+		if last {
+			switch op {
+			case "add":
+				typedNode[token] = value
+			case "replace":
+				if !exists {
+					return nil, fmt.Errorf("path not found: %s", token)
+				}
+				typedNode[token] = value
+			case "remove":
+				if !exists {
+					return nil, fmt.Errorf("path not found: %s", token)
+				}
+				delete(typedNode, token)
+			default:
+				return nil, fmt.Errorf("unsupported operation")
+			}
+
+			return typedNode, nil
+		}
+
+		if !exists {
+			return nil, fmt.Errorf("path not found: %s", token)
+		}
+
+		updatedChild, err := applyOperation(child, path[1:], op, value)
+		if err != nil {
+			return nil, err
+		}
+		typedNode[token] = updatedChild
+
+		return typedNode, nil
+	case []interface{}:
+		if last && op == "add" && token == "-" {
+			return append(typedNode, value), nil
+		}
+
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index > len(typedNode) || (index == len(typedNode) && !(last && op == "add")) {
+			return nil, fmt.Errorf("invalid array index: %s", token)
+		}
+
+		if last {
+			switch op {
+			case "add":
+				typedNode = append(typedNode, nil)
+				copy(typedNode[index+1:], typedNode[index:])
+				typedNode[index] = value
+			case "replace":
+				typedNode[index] = value
+			case "remove":
+				typedNode = append(typedNode[:index], typedNode[index+1:]...)
+			default:
+				return nil, fmt.Errorf("unsupported operation")
+			}
+
+			return typedNode, nil
+		}
+
+		updatedChild, err := applyOperation(typedNode[index], path[1:], op, value)
+		if err != nil {
+			return nil, err
+		}
+		typedNode[index] = updatedChild
+
+		return typedNode, nil
+	default:
+		return nil, fmt.Errorf("path not found: %s", token)
+	}
+}
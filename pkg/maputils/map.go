@@ -8,6 +8,16 @@ func ContainsKeyValuePairInMap(key string, value string, m map[string]string) bo
 	return false
 }
 
+// ContainsAllKeyValuePairsInMap returns true if all the key/value pairs of the subset exist in the map
+func ContainsAllKeyValuePairsInMap(subset map[string]string, m map[string]string) bool {
+	for key, value := range subset {
+		if !ContainsKeyValuePairInMap(key, value, m) {
+			return false
+		}
+	}
+	return true
+}
+
 // mergeMapsInPlace takes two maps of type map[string]string.
 // It copies the key-value pairs from the second map (map2) into the first map (map1),
 // overwriting any existing values with the same keys.
@@ -35,3 +45,18 @@ func ConvertMapStringToStringSliceByte(input map[string]string) map[string][]byt
 	}
 	return output
 }
+
+// EqualMaps returns true if both maps contain the same key/value pairs.
+func EqualMaps(map1, map2 map[string]string) bool {
+	if len(map1) != len(map2) {
+		return false
+	}
+
+	for key, value := range map1 {
+		if !ContainsKeyValuePairInMap(key, value, map2) {
+			return false
+		}
+	}
+
+	return true
+}
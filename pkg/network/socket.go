@@ -0,0 +1,46 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// ListenUnixSocket creates a listener on a unix domain socket at the specified path.
+// The parent directory is created if it does not exist and a stale socket left behind by a previous process is removed.
+// The socket is only accessible to the owner and the group of the process (0660).
+func ListenUnixSocket(socketPath string) (net.Listener, error) {
+	err := os.MkdirAll(filepath.Dir(socketPath), 0755)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create socket directory: %w", err)
+	}
+
+	info, err := os.Lstat(socketPath)
+	if err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s already exists and is not a socket", socketPath)
+		}
+
+		err = os.Remove(socketPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to remove stale socket: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("unable to stat %s: %w", socketPath, err)
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen on socket: %w", err)
+	}
+
+	err = os.Chmod(socketPath, 0660)
+	if err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("unable to set socket permissions: %w", err)
+	}
+
+	return listener, nil
+}
@@ -7,6 +7,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"math/big"
 	"net"
@@ -23,20 +24,26 @@ import (
 // - Locality: The locality where the organization is located.
 // - Validity: The duration that the certificate will be valid for.
 // - IpAddr: The IP address that the certificate will be issued for.
+// - IPAddresses: Additional IP addresses that the certificate will be issued for.
+// - DNSNames: Additional host names that the certificate will be issued for.
 // - CertPath: The path where the generated certificate and key files will be saved.
 // - CAFilename: The filename of the certificate authority's certificate file.
+// - CAKeyFilename: The filename of the certificate authority's private key file, used to issue client certificates.
 // - CertFilename: The filename of the generated certificate file.
 // - KeyFilename: The filename of the generated private key file.
 type CertConfig struct {
-	Organization string
-	Country      string
-	Locality     string
-	Validity     time.Duration
-	IpAddr       net.IP
-	CertPath     string
-	CAFilename   string
-	CertFilename string
-	KeyFilename  string
+	Organization  string
+	Country       string
+	Locality      string
+	Validity      time.Duration
+	IpAddr        net.IP
+	IPAddresses   []net.IP
+	DNSNames      []string
+	CertPath      string
+	CAFilename    string
+	CAKeyFilename string
+	CertFilename  string
+	KeyFilename   string
 }
 
 // GenerateTLSCertificatesForIPAddr generates a CA certificate, a TLS certificate, and a private key
@@ -69,12 +76,6 @@ func GenerateTLSCertificatesForIPAddr(cfg CertConfig) error {
 		return fmt.Errorf("unable to generate CA TLS certificate: %w", err)
 	}
 
-	caPEM := new(bytes.Buffer)
-	pem.Encode(caPEM, &pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: caBytes,
-	})
-
 	caPath := path.Join(cfg.CertPath, cfg.CAFilename)
 
 	caOut, err := os.Create(caPath)
@@ -92,21 +93,63 @@ func GenerateTLSCertificatesForIPAddr(cfg CertConfig) error {
 		return fmt.Errorf("an error occured while closing %s: %w", caPath, err)
 	}
 
-	caPrivKeyPEM := new(bytes.Buffer)
-	pem.Encode(caPrivKeyPEM, &pem.Block{
+	caKeyPath := path.Join(cfg.CertPath, cfg.CAKeyFilename)
+
+	caKeyOut, err := os.OpenFile(caKeyPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to open %s for writing: %w", caKeyPath, err)
+	}
+
+	pem.Encode(caKeyOut, &pem.Block{
 		Type:  "RSA PRIVATE KEY",
 		Bytes: x509.MarshalPKCS1PrivateKey(caPrivKey),
 	})
 
+	err = caKeyOut.Close()
+	if err != nil {
+		return fmt.Errorf("an error occured while closing %s: %w", caKeyPath, err)
+	}
+
+	return generateServerCertificate(cfg, ca, caPrivKey)
+}
+
+// GenerateServerCertificate generates a new TLS certificate and private key for the IP addresses and host names
+// specified in the CertConfig, signed by the existing CA stored in the CertConfig directory. It is used to renew
+// the TLS certificate without replacing the CA, so that the clients trusting the CA keep working.
+func GenerateServerCertificate(cfg CertConfig) error {
+	ca, caPrivKey, err := loadCA(path.Join(cfg.CertPath, cfg.CAFilename), path.Join(cfg.CertPath, cfg.CAKeyFilename))
+	if err != nil {
+		return fmt.Errorf("unable to load CA: %w", err)
+	}
+
+	return generateServerCertificate(cfg, ca, caPrivKey)
+}
+
+// ServerCertificateIPAddresses returns the IP addresses included in the TLS certificate generated for a CertConfig.
+func ServerCertificateIPAddresses(cfg CertConfig) []net.IP {
+	return append([]net.IP{cfg.IpAddr, net.IPv6loopback}, cfg.IPAddresses...)
+}
+
+// ServerCertificateDNSNames returns the host names included in the TLS certificate generated for a CertConfig.
+func ServerCertificateDNSNames(cfg CertConfig) []string {
+	return append([]string{"kubernetes.default.svc"}, cfg.DNSNames...)
+}
+
+func generateServerCertificate(cfg CertConfig, ca *x509.Certificate, caPrivKey *rsa.PrivateKey) error {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return fmt.Errorf("unable to generate serial number: %w", err)
+	}
+
 	cert := &x509.Certificate{
-		SerialNumber: big.NewInt(1658),
+		SerialNumber: serialNumber,
 		Subject: pkix.Name{
 			Organization: []string{cfg.Organization},
 			Country:      []string{cfg.Country},
 			Locality:     []string{cfg.Locality},
 		},
-		IPAddresses:  []net.IP{cfg.IpAddr, net.IPv6loopback},
-		DNSNames:     []string{"kubernetes.default.svc"},
+		IPAddresses:  ServerCertificateIPAddresses(cfg),
+		DNSNames:     ServerCertificateDNSNames(cfg),
 		NotBefore:    time.Now(),
 		NotAfter:     time.Now().Add(cfg.Validity),
 		SubjectKeyId: []byte{1, 2, 3, 4, 6},
@@ -165,3 +208,120 @@ func GenerateTLSCertificatesForIPAddr(cfg CertConfig) error {
 
 	return nil
 }
+
+// LoadCertificate reads the first PEM encoded certificate of the specified file.
+func LoadCertificate(certPath string) (*x509.Certificate, error) {
+	certData, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", certPath, err)
+	}
+
+	certBlock, _ := pem.Decode(certData)
+	if certBlock == nil {
+		return nil, fmt.Errorf("unable to decode certificate %s", certPath)
+	}
+
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse certificate %s: %w", certPath, err)
+	}
+
+	return cert, nil
+}
+
+// GenerateClientCertificate generates a client certificate and its associated private key, signed by the CA
+// stored in caCertPath and caKeyPath. The certificate can be used to authenticate against a server that trusts the CA.
+//
+// Parameters:
+// - caCertPath: The path to the PEM encoded CA certificate.
+// - caKeyPath: The path to the PEM encoded CA private key (PKCS1).
+// - commonName: The common name of the certificate, used to identify the client.
+// - organizations: The organizations of the certificate, used to identify the groups of the client.
+// - validity: The duration that the certificate will be valid for.
+//
+// Returns:
+// - The PEM encoded client certificate.
+// - The PEM encoded client private key.
+// - An error if the CA cannot be loaded or if the certificate cannot be generated.
+func GenerateClientCertificate(caCertPath, caKeyPath, commonName string, organizations []string, validity time.Duration) ([]byte, []byte, error) {
+	ca, caPrivKey, err := loadCA(caCertPath, caKeyPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to load CA: %w", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to generate serial number: %w", err)
+	}
+
+	cert := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: organizations,
+		},
+		NotBefore:   time.Now(),
+		NotAfter:    time.Now().Add(validity),
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+	}
+
+	certPrivKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to generate client certificate private key: %w", err)
+	}
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, cert, ca, &certPrivKey.PublicKey, caPrivKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to generate client certificate: %w", err)
+	}
+
+	certPEM := new(bytes.Buffer)
+	pem.Encode(certPEM, &pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: certBytes,
+	})
+
+	keyPEM := new(bytes.Buffer)
+	pem.Encode(keyPEM, &pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(certPrivKey),
+	})
+
+	return certPEM.Bytes(), keyPEM.Bytes(), nil
+}
+
+// loadCA reads the PEM encoded CA certificate and private key from disk.
+func loadCA(caCertPath, caKeyPath string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	caData, err := os.ReadFile(caCertPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read %s: %w", caCertPath, err)
+	}
+
+	caBlock, _ := pem.Decode(caData)
+	if caBlock == nil {
+		return nil, nil, errors.New("unable to decode CA certificate")
+	}
+
+	ca, err := x509.ParseCertificate(caBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse CA certificate: %w", err)
+	}
+
+	caKeyData, err := os.ReadFile(caKeyPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read %s: %w", caKeyPath, err)
+	}
+
+	caKeyBlock, _ := pem.Decode(caKeyData)
+	if caKeyBlock == nil {
+		return nil, nil, errors.New("unable to decode CA private key")
+	}
+
+	caPrivKey, err := x509.ParsePKCS1PrivateKey(caKeyBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse CA private key: %w", err)
+	}
+
+	return ca, caPrivKey, nil
+}
@@ -0,0 +1,30 @@
+package sysinfo
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// DiskUsage represents the usage of the filesystem backing a specific path.
+type DiskUsage struct {
+	// Total is the total size of the filesystem in bytes
+	Total uint64
+	// Available is the number of bytes available to unprivileged users
+	Available uint64
+}
+
+// GetDiskUsage returns the usage of the filesystem that contains the specified path.
+// It returns an error if the filesystem statistics cannot be retrieved.
+func GetDiskUsage(path string) (DiskUsage, error) {
+	var stat syscall.Statfs_t
+
+	err := syscall.Statfs(path, &stat)
+	if err != nil {
+		return DiskUsage{}, fmt.Errorf("unable to retrieve filesystem statistics for %s: %w", path, err)
+	}
+
+	return DiskUsage{
+		Total:     uint64(stat.Blocks) * uint64(stat.Bsize),
+		Available: uint64(stat.Bavail) * uint64(stat.Bsize),
+	}, nil
+}
@@ -0,0 +1,69 @@
+package sysinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MemInfoPath is the path to the file exposing the memory statistics of the host
+const MemInfoPath = "/proc/meminfo"
+
+// MemoryUsage represents the memory usage of the host.
+type MemoryUsage struct {
+	// Total is the total amount of usable memory in bytes
+	Total uint64
+	// Available is the amount of memory available for starting new applications in bytes
+	Available uint64
+}
+
+// GetMemoryUsage returns the memory usage of the host by parsing the MemTotal and MemAvailable
+// entries of /proc/meminfo.
+// It returns an error if the file cannot be read or if one of the entries is missing.
+func GetMemoryUsage() (MemoryUsage, error) {
+	file, err := os.Open(MemInfoPath)
+	if err != nil {
+		return MemoryUsage{}, fmt.Errorf("unable to open %s: %w", MemInfoPath, err)
+	}
+	defer file.Close()
+
+	values := map[string]uint64{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		key := strings.TrimSuffix(fields[0], ":")
+		if key != "MemTotal" && key != "MemAvailable" {
+			continue
+		}
+
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return MemoryUsage{}, fmt.Errorf("unable to parse %s value: %w", key, err)
+		}
+
+		// values are expressed in kB in /proc/meminfo
+		values[key] = value * 1024
+	}
+
+	if err := scanner.Err(); err != nil {
+		return MemoryUsage{}, fmt.Errorf("unable to read %s: %w", MemInfoPath, err)
+	}
+
+	total, totalFound := values["MemTotal"]
+	available, availableFound := values["MemAvailable"]
+	if !totalFound || !availableFound {
+		return MemoryUsage{}, fmt.Errorf("unable to find memory statistics in %s", MemInfoPath)
+	}
+
+	return MemoryUsage{
+		Total:     total,
+		Available: available,
+	}, nil
+}